@@ -0,0 +1,148 @@
+// Package apierror defines the stable, machine-readable codes sent with every
+// error response. Messages are for people and may be reworded; codes are for
+// clients to branch on and don't change once published.
+package apierror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Code identifies the kind of error in an error response
+type Code string
+
+// Generic codes, used when no more specific code applies
+const (
+	BadRequest       Code = "BAD_REQUEST"
+	ValidationFailed Code = "VALIDATION_FAILED"
+	InvalidID        Code = "INVALID_ID"
+	Unauthorized     Code = "UNAUTHORIZED"
+	Forbidden        Code = "FORBIDDEN"
+	NotFound         Code = "NOT_FOUND"
+	Conflict         Code = "CONFLICT"
+	RateLimited      Code = "RATE_LIMITED"
+	Internal         Code = "INTERNAL_ERROR"
+)
+
+// Authentication and accounts
+const (
+	TokenInvalid              Code = "TOKEN_INVALID"
+	InvalidCredentials        Code = "INVALID_CREDENTIALS"
+	AccountDeactivated        Code = "ACCOUNT_DEACTIVATED"
+	EmailTaken                Code = "EMAIL_TAKEN"
+	EmailAlreadyVerified      Code = "EMAIL_ALREADY_VERIFIED"
+	EmailVerificationRequired Code = "EMAIL_VERIFICATION_REQUIRED"
+	PasswordIncorrect         Code = "PASSWORD_INCORRECT"
+	UserNotFound              Code = "USER_NOT_FOUND"
+	SellerNotFound            Code = "SELLER_NOT_FOUND"
+	NotASeller                Code = "NOT_A_SELLER"
+	StaffNotFound             Code = "STAFF_NOT_FOUND"
+)
+
+// Products and inventory
+const (
+	ProductNotFound        Code = "PRODUCT_NOT_FOUND"
+	ProductForbidden       Code = "PRODUCT_FORBIDDEN"
+	ProductUnavailable     Code = "PRODUCT_UNAVAILABLE"
+	SKUTaken               Code = "SKU_TAKEN"
+	InsufficientStock      Code = "INSUFFICIENT_STOCK"
+	PurchaseLimitReached   Code = "PURCHASE_LIMIT_REACHED"
+	ProductPendingApproval Code = "PRODUCT_PENDING_APPROVAL"
+	CategoryNotFound       Code = "CATEGORY_NOT_FOUND"
+	CategoryNameTaken      Code = "CATEGORY_NAME_TAKEN"
+	ImageNotFound          Code = "IMAGE_NOT_FOUND"
+	ImageLimitReached      Code = "IMAGE_LIMIT_REACHED"
+)
+
+// Carts, orders and payments
+const (
+	CartEmpty               Code = "CART_EMPTY"
+	CartItemNotFound        Code = "CART_ITEM_NOT_FOUND"
+	CartItemLimitReached    Code = "CART_ITEM_LIMIT_REACHED"
+	CartValueLimitReached   Code = "CART_VALUE_LIMIT_REACHED"
+	OrderNotFound           Code = "ORDER_NOT_FOUND"
+	OrderForbidden          Code = "ORDER_FORBIDDEN"
+	OrderNotModifiable      Code = "ORDER_NOT_MODIFIABLE"
+	OrderNotCancellable     Code = "ORDER_NOT_CANCELLABLE"
+	InvalidStatusTransition Code = "INVALID_STATUS_TRANSITION"
+	MinimumOrderNotMet      Code = "MINIMUM_ORDER_NOT_MET"
+	StockNotCommitted       Code = "STOCK_NOT_COMMITTED"
+	ResendLimitReached      Code = "RESEND_LIMIT_REACHED"
+	ShippingCostIncrease    Code = "SHIPPING_COST_INCREASE"
+	PaymentDisputed         Code = "PAYMENT_DISPUTED"
+)
+
+// Returns
+const (
+	ReturnNotFound         Code = "RETURN_NOT_FOUND"
+	ReturnNotEligible      Code = "RETURN_NOT_ELIGIBLE"
+	ReturnQuantityExceeded Code = "RETURN_QUANTITY_EXCEEDED"
+	ReturnClosed           Code = "RETURN_CLOSED"
+)
+
+// Coupons
+const (
+	CouponInvalid           Code = "COUPON_INVALID"
+	CouponExpired           Code = "COUPON_EXPIRED"
+	CouponMinSpendNotMet    Code = "COUPON_MIN_SPEND_NOT_MET"
+	CouponNotApplicable     Code = "COUPON_NOT_APPLICABLE"
+	CouponUsageLimitReached Code = "COUPON_USAGE_LIMIT_REACHED"
+	CouponCodeTaken         Code = "COUPON_CODE_TAKEN"
+)
+
+// Reviews
+const (
+	ReviewNotFound   Code = "REVIEW_NOT_FOUND"
+	ReviewForbidden  Code = "REVIEW_FORBIDDEN"
+	ReviewNotAllowed Code = "REVIEW_NOT_ALLOWED"
+	AlreadyReviewed  Code = "ALREADY_REVIEWED"
+)
+
+// ForStatus returns the generic code for an HTTP status
+func ForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return BadRequest
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusForbidden:
+		return Forbidden
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusTooManyRequests:
+		return RateLimited
+	}
+	if status >= 500 {
+		return Internal
+	}
+	return BadRequest
+}
+
+// Coder is implemented by errors that carry their own code, such as the
+// service layer's errors
+type Coder interface {
+	Code() Code
+}
+
+// FromError returns the code carried by err or an error it wraps, if any
+func FromError(err error) (Code, bool) {
+	var coder Coder
+	if errors.As(err, &coder) && coder.Code() != "" {
+		return coder.Code(), true
+	}
+	return "", false
+}
+
+// Resolve picks the code for an error response: the error's own code for
+// client errors, falling back to the status's generic code. Server errors are
+// always Internal, whatever the error carries.
+func Resolve(status int, err error) Code {
+	if status >= 400 && status < 500 {
+		if code, ok := FromError(err); ok {
+			return code
+		}
+	}
+	return ForStatus(status)
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/joho/godotenv"
 )
 
@@ -19,6 +21,9 @@ type Config struct {
 	// JWT
 	JWT JWTConfig
 
+	// Auth tokens
+	Auth AuthConfig
+
 	// Server
 	Server ServerConfig
 
@@ -28,11 +33,35 @@ type Config struct {
 	// Stripe
 	Stripe StripeConfig
 
+	// Payment mode
+	Payment PaymentConfig
+
 	// Application
 	App AppConfig
 
 	// File Upload
 	Upload UploadConfig
+
+	// Currency
+	Currency CurrencyConfig
+
+	// Orders
+	Order OrderConfig
+
+	// Cart limits
+	Cart CartConfig
+
+	// Shipping
+	Shipping ShippingConfig
+
+	// Storefront curation
+	Storefront StorefrontConfig
+
+	// List page sizes
+	Pagination PaginationConfig
+
+	// Third-party integrations
+	Integration IntegrationConfig
 }
 
 type DatabaseConfig struct {
@@ -42,6 +71,14 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+
+	// Connection pool; raise MaxOpenConns with the database's max_connections
+	// in mind, shared by every instance
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration // 0 keeps connections open indefinitely
+
+	SlowQueryThreshold time.Duration // Queries taking at least this long are logged; 0 disables
 }
 
 type RedisConfig struct {
@@ -56,6 +93,11 @@ type JWTConfig struct {
 	Expiry time.Duration
 }
 
+type AuthConfig struct {
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+}
+
 type ServerConfig struct {
 	Host string
 	Port int
@@ -67,6 +109,15 @@ type EmailConfig struct {
 	SMTPUsername string
 	SMTPPassword string
 	FromEmail    string
+
+	// Bulk sends (broadcasts) are throttled to stay under the provider's limits;
+	// a rate of 0 leaves that window unlimited
+	BulkRatePerSecond    int
+	BulkRatePerMinute    int
+	BulkBatchSize        int
+	BulkMaxAttempts      int
+	BulkRetryDelay       time.Duration // Doubled after each failed attempt
+	BulkDispatchInterval time.Duration // 0 disables the dispatcher
 }
 
 type StripeConfig struct {
@@ -75,6 +126,16 @@ type StripeConfig struct {
 	WebhookSecret  string
 }
 
+// Payment modes
+const (
+	PaymentModeLive = "live" // Charges go through Stripe
+	PaymentModeTest = "test" // Charges are simulated, see payment.MockService
+)
+
+type PaymentConfig struct {
+	Mode string
+}
+
 type AppConfig struct {
 	Environment string
 	URL         string
@@ -82,8 +143,131 @@ type AppConfig struct {
 }
 
 type UploadConfig struct {
-	MaxFileSize int64
-	UploadDir   string
+	MaxFileSize          int64
+	UploadDir            string
+	VerifyImageURLs      bool          // HEAD-check external image URLs before saving them
+	ImageURLCheckTimeout time.Duration
+	MaxImagesPerProduct  int
+}
+
+type CurrencyConfig struct {
+	MinorUnits int    // Decimal places of the store currency, 0 to 2
+	Rounding   string // "half_up", "half_even" or "down", see money.RoundingMode
+}
+
+type OrderConfig struct {
+	AutoConfirm          bool // When false, paid orders wait for a merchant to confirm them
+	FraudReviewThreshold int
+	StockReservationTTL  time.Duration // How long an unpaid order holds its stock
+	StockReservationMax  time.Duration // Longest an unpaid order can hold its stock, counting extensions
+	ReturnWindowDays     int           // Default return window for products without their own
+	CouponHoldTTL        time.Duration // How long a checkout holds a coupon use before paying
+	CouponStacking       string        // "stack" or "best", see models.CouponStackingStack
+
+	// Least an order's subtotal after discounts may be; 0 disables the minimum.
+	// Orders paid with an exempt method skip the store and seller minimums.
+	MinimumAmount               float64
+	MinimumAmountExemptPayments []string
+
+	// How long an order may sit in each status before it is flagged as stuck; 0 disables the check
+	SLAPendingReview time.Duration
+	SLAConfirmed     time.Duration
+	SLAProcessing    time.Duration
+	SLAShipped       time.Duration
+	SLACheckInterval time.Duration
+
+	// Days after delivery to ask customers to review what they bought; 0 disables reminders
+	ReviewReminderDays          int
+	ReviewReminderCheckInterval time.Duration
+
+	// Days after which finished orders are archived out of order lists; 0 keeps them live
+	RetentionDays        int
+	ArchiveCheckInterval time.Duration
+
+	// Checkout with just an email and shipping address, no account needed
+	GuestCheckout bool
+
+	// Single-use coupon issued to customers as they register
+	WelcomeCoupon         bool
+	WelcomeCouponType     models.CouponType
+	WelcomeCouponValue    float64
+	WelcomeCouponMinSpend float64
+	WelcomeCouponValidity time.Duration // 0 never expires
+}
+
+// CartConfig holds the cart limits of each role; sellers and admins default
+// to the customer limits unless given their own
+type CartConfig struct {
+	Customer models.CartLimits // Customers, guests and seller staff
+	Seller   models.CartLimits
+	Admin    models.CartLimits
+}
+
+// For returns the cart limits of a role
+func (c CartConfig) For(role models.UserRole) models.CartLimits {
+	switch role {
+	case models.RoleSeller:
+		return c.Seller
+	case models.RoleAdmin:
+		return c.Admin
+	default:
+		return c.Customer
+	}
+}
+
+type ShippingConfig struct {
+	FlatRate              float64
+	FreeShippingThreshold float64 // 0 disables free shipping
+
+	// Delivery estimates, in business days
+	DefaultProcessingDays int // Processing time for products without their own
+	TransitDays           int
+}
+
+type StorefrontConfig struct {
+	MaxFeaturedSellers int
+
+	// Rating gate for featured, top-rated and related product lists, see models.RatingGate
+	MinListingRating         float64 // 0 with MinListingReviews 0 disables the gate
+	MinListingReviews        int
+	ListingIncludeFewReviews bool
+
+	// New arrivals list
+	NewArrivalsDays              int // Default window when the request doesn't set one
+	NewArrivalsIncludeOutOfStock bool
+
+	// Sellers' new products, and material edits to listed ones, wait for an
+	// admin's approval before they're listed
+	ProductApproval bool
+
+	// How often queued product rating recomputes run; 0 recomputes on every review change
+	RatingRecomputeInterval time.Duration
+
+	// Sort for product listings that don't ask for one, unless their category sets its own
+	DefaultProductSort models.ProductSort
+
+	// Days a product may stay a draft before it's on its seller's list of listings to fix
+	StaleDraftDays int
+}
+
+// PageSizeConfig holds the default and maximum page size of a list; a larger
+// requested limit is clamped to Max
+type PageSizeConfig struct {
+	Default int
+	Max     int
+}
+
+type PaginationConfig struct {
+	Default       PageSizeConfig // Lists without their own sizes
+	Products      PageSizeConfig
+	Orders        PageSizeConfig
+	Reviews       PageSizeConfig // Reviews and product questions
+	Notifications PageSizeConfig
+	Admin         PageSizeConfig // Admin-only lists
+}
+
+type IntegrationConfig struct {
+	APIKeys []string // Keys accepted in the X-API-Key header; none disables integration endpoints
 }
 
 func Load() (*Config, error) {
@@ -102,6 +286,12 @@ func Load() (*Config, error) {
 		Password: getEnv("DB_PASSWORD", "password"),
 		Name:     getEnv("DB_NAME", "ecommerce_db"),
 		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+
+		MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
+		ConnMaxLifetime: time.Duration(getEnvAsInt("DB_CONN_MAX_LIFETIME_MINUTES", 30)) * time.Minute,
+
+		SlowQueryThreshold: time.Duration(getEnvAsInt("DB_SLOW_QUERY_THRESHOLD_MS", 200)) * time.Millisecond,
 	}
 
 	// Redis configuration
@@ -123,6 +313,22 @@ func Load() (*Config, error) {
 		Expiry: jwtExpiry,
 	}
 
+	// Auth token configuration
+	passwordResetTTL, err := time.ParseDuration(getEnv("PASSWORD_RESET_TTL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_RESET_TTL format: %w", err)
+	}
+
+	emailVerificationTTL, err := time.ParseDuration(getEnv("EMAIL_VERIFICATION_TTL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EMAIL_VERIFICATION_TTL format: %w", err)
+	}
+
+	config.Auth = AuthConfig{
+		PasswordResetTTL:     passwordResetTTL,
+		EmailVerificationTTL: emailVerificationTTL,
+	}
+
 	// Server configuration
 	config.Server = ServerConfig{
 		Host: getEnv("SERVER_HOST", "localhost"),
@@ -136,6 +342,13 @@ func Load() (*Config, error) {
 		SMTPUsername: getEnv("SMTP_USERNAME", ""),
 		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
 		FromEmail:    getEnv("FROM_EMAIL", "noreply@ecommerce.com"),
+
+		BulkRatePerSecond:    getEnvAsInt("EMAIL_RATE_PER_SECOND", 10),
+		BulkRatePerMinute:    getEnvAsInt("EMAIL_RATE_PER_MINUTE", 300),
+		BulkBatchSize:        getEnvAsInt("EMAIL_BATCH_SIZE", 100),
+		BulkMaxAttempts:      getEnvAsInt("EMAIL_MAX_ATTEMPTS", 3),
+		BulkRetryDelay:       time.Duration(getEnvAsInt("EMAIL_RETRY_DELAY_MINUTES", 5)) * time.Minute,
+		BulkDispatchInterval: time.Duration(getEnvAsInt("EMAIL_DISPATCH_INTERVAL_SECONDS", 10)) * time.Second,
 	}
 
 	// Stripe configuration
@@ -145,17 +358,138 @@ func Load() (*Config, error) {
 		WebhookSecret:  getEnv("STRIPE_WEBHOOK_SECRET", ""),
 	}
 
+	// Payment configuration
+	config.Payment = PaymentConfig{
+		Mode: getEnv("PAYMENT_MODE", PaymentModeLive),
+	}
+
 	// Application configuration
 	config.App = AppConfig{
 		Environment: getEnv("APP_ENV", "development"),
 		URL:         getEnv("APP_URL", "http://localhost:8080"),
 		FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
 	}
+	switch {
+	case config.Payment.Mode != PaymentModeLive && config.Payment.Mode != PaymentModeTest:
+		return nil, fmt.Errorf("invalid PAYMENT_MODE %q: must be %q or %q", config.Payment.Mode, PaymentModeLive, PaymentModeTest)
+	case config.Payment.Mode == PaymentModeTest && config.App.Environment == "production":
+		return nil, fmt.Errorf("PAYMENT_MODE=%s can't be used with APP_ENV=production", PaymentModeTest)
+	}
 
 	// Upload configuration
 	config.Upload = UploadConfig{
-		MaxFileSize: getEnvAsInt64("MAX_FILE_SIZE", 10485760), // 10MB
-		UploadDir:   getEnv("UPLOAD_DIR", "./uploads"),
+		MaxFileSize:          getEnvAsInt64("MAX_FILE_SIZE", 10485760), // 10MB
+		UploadDir:            getEnv("UPLOAD_DIR", "./uploads"),
+		VerifyImageURLs:      getEnvAsBool("VERIFY_IMAGE_URLS", false),
+		ImageURLCheckTimeout: time.Duration(getEnvAsInt("IMAGE_URL_CHECK_TIMEOUT_SECONDS", 5)) * time.Second,
+		MaxImagesPerProduct:  getEnvAsInt("MAX_IMAGES_PER_PRODUCT", 10),
+	}
+
+	// Currency configuration
+	config.Currency = CurrencyConfig{
+		MinorUnits: getEnvAsInt("CURRENCY_MINOR_UNITS", 2),
+		Rounding:   getEnv("CURRENCY_ROUNDING", "half_up"),
+	}
+
+	// Order configuration
+	config.Order = OrderConfig{
+		AutoConfirm:          getEnvAsBool("ORDER_AUTO_CONFIRM", true),
+		FraudReviewThreshold: getEnvAsInt("FRAUD_REVIEW_THRESHOLD", 70),
+		StockReservationTTL:  time.Duration(getEnvAsInt("STOCK_RESERVATION_TTL_MINUTES", 30)) * time.Minute,
+		StockReservationMax:  time.Duration(getEnvAsInt("STOCK_RESERVATION_MAX_MINUTES", 60)) * time.Minute,
+		ReturnWindowDays:     getEnvAsInt("DEFAULT_RETURN_WINDOW_DAYS", 30),
+		CouponHoldTTL:        time.Duration(getEnvAsInt("COUPON_HOLD_TTL_MINUTES", 30)) * time.Minute,
+		CouponStacking:       getEnv("COUPON_PROMOTION_STACKING", "stack"),
+		SLAPendingReview:     time.Duration(getEnvAsInt("ORDER_SLA_PENDING_REVIEW_HOURS", 24)) * time.Hour,
+		SLAConfirmed:         time.Duration(getEnvAsInt("ORDER_SLA_CONFIRMED_HOURS", 48)) * time.Hour,
+		SLAProcessing:        time.Duration(getEnvAsInt("ORDER_SLA_PROCESSING_HOURS", 48)) * time.Hour,
+		SLAShipped:           time.Duration(getEnvAsInt("ORDER_SLA_SHIPPED_HOURS", 240)) * time.Hour,
+		SLACheckInterval:     time.Duration(getEnvAsInt("ORDER_SLA_CHECK_INTERVAL_MINUTES", 15)) * time.Minute,
+
+		ReviewReminderDays:          getEnvAsInt("REVIEW_REMINDER_DAYS", 7),
+		ReviewReminderCheckInterval: time.Duration(getEnvAsInt("REVIEW_REMINDER_CHECK_INTERVAL_MINUTES", 60)) * time.Minute,
+
+		RetentionDays:        getEnvAsInt("ORDER_RETENTION_DAYS", 0),
+		ArchiveCheckInterval: time.Duration(getEnvAsInt("ORDER_ARCHIVE_CHECK_INTERVAL_MINUTES", 1440)) * time.Minute,
+
+		GuestCheckout: getEnvAsBool("GUEST_CHECKOUT_ENABLED", true),
+
+		WelcomeCoupon:         getEnvAsBool("WELCOME_COUPON_ENABLED", false),
+		WelcomeCouponType:     models.CouponType(getEnv("WELCOME_COUPON_TYPE", string(models.CouponTypePercent))),
+		WelcomeCouponValue:    getEnvAsFloat("WELCOME_COUPON_VALUE", 10),
+		WelcomeCouponMinSpend: getEnvAsFloat("WELCOME_COUPON_MIN_SPEND", 0),
+		WelcomeCouponValidity: time.Duration(getEnvAsInt("WELCOME_COUPON_VALID_DAYS", 30)) * 24 * time.Hour,
+
+		MinimumAmount:               getEnvAsFloat("MINIMUM_ORDER_AMOUNT", 0),
+		MinimumAmountExemptPayments: getEnvAsList("MINIMUM_ORDER_EXEMPT_PAYMENT_METHODS"),
+	}
+	if welcome := config.Order; welcome.WelcomeCoupon {
+		switch {
+		case welcome.WelcomeCouponType != models.CouponTypePercent && welcome.WelcomeCouponType != models.CouponTypeFixed:
+			return nil, fmt.Errorf("invalid WELCOME_COUPON_TYPE %q", welcome.WelcomeCouponType)
+		case welcome.WelcomeCouponValue <= 0,
+			welcome.WelcomeCouponType == models.CouponTypePercent && welcome.WelcomeCouponValue > 100:
+			return nil, fmt.Errorf("invalid WELCOME_COUPON_VALUE %v", welcome.WelcomeCouponValue)
+		}
+	}
+
+	// Cart configuration
+	customerCart := getCartLimits("CART", models.CartLimits{MaxItems: 100, MaxValue: 10000})
+	config.Cart = CartConfig{
+		Customer: customerCart,
+		Seller:   getCartLimits("CART_SELLER", customerCart),
+		Admin:    getCartLimits("CART_ADMIN", customerCart),
+	}
+	for prefix, limits := range map[string]models.CartLimits{"CART": config.Cart.Customer, "CART_SELLER": config.Cart.Seller, "CART_ADMIN": config.Cart.Admin} {
+		if limits.MaxItems < 0 || limits.MaxValue < 0 {
+			return nil, fmt.Errorf("invalid %s_MAX_ITEMS or %s_MAX_VALUE: limits can't be negative", prefix, prefix)
+		}
+	}
+
+	// Shipping configuration
+	config.Shipping = ShippingConfig{
+		FlatRate:              getEnvAsFloat("SHIPPING_FLAT_RATE", 5.99),
+		FreeShippingThreshold: getEnvAsFloat("FREE_SHIPPING_THRESHOLD", 50),
+
+		DefaultProcessingDays: getEnvAsInt("DEFAULT_PROCESSING_DAYS", 2),
+		TransitDays:           getEnvAsInt("SHIPPING_TRANSIT_DAYS", 5),
+	}
+
+	// Storefront configuration
+	config.Storefront = StorefrontConfig{
+		MaxFeaturedSellers:       getEnvAsInt("MAX_FEATURED_SELLERS", 12),
+		MinListingRating:         getEnvAsFloat("MIN_LISTING_RATING", 3.5),
+		MinListingReviews:        getEnvAsInt("MIN_LISTING_REVIEWS", 3),
+		ListingIncludeFewReviews: getEnvAsBool("LISTING_INCLUDE_FEW_REVIEWS", false),
+
+		NewArrivalsDays:              getEnvAsInt("NEW_ARRIVALS_DAYS", 30),
+		NewArrivalsIncludeOutOfStock: getEnvAsBool("NEW_ARRIVALS_INCLUDE_OUT_OF_STOCK", false),
+
+		ProductApproval: getEnvAsBool("PRODUCT_APPROVAL_REQUIRED", false),
+
+		RatingRecomputeInterval: time.Duration(getEnvAsInt("RATING_RECOMPUTE_INTERVAL_SECONDS", 5)) * time.Second,
+
+		DefaultProductSort: models.ProductSort(getEnv("DEFAULT_PRODUCT_SORT", string(models.ProductSortNewest))),
+
+		StaleDraftDays: getEnvAsInt("STALE_DRAFT_DAYS", 14),
+	}
+	if !config.Storefront.DefaultProductSort.IsValid() {
+		return nil, fmt.Errorf("invalid DEFAULT_PRODUCT_SORT %q", config.Storefront.DefaultProductSort)
+	}
+
+	// Pagination configuration
+	config.Pagination = PaginationConfig{
+		Default:       getPageSize("DEFAULT", 20, 100),
+		Products:      getPageSize("PRODUCTS", 10, 100),
+		Orders:        getPageSize("ORDERS", 10, 100),
+		Reviews:       getPageSize("REVIEWS", 10, 100),
+		Notifications: getPageSize("NOTIFICATIONS", 10, 100),
+		Admin:         getPageSize("ADMIN", 20, 100),
+	}
+
+	// Integration configuration
+	config.Integration = IntegrationConfig{
+		APIKeys: getEnvAsList("INTEGRATION_API_KEYS"),
 	}
 
 	return config, nil
@@ -177,6 +511,22 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getCartLimits reads <prefix>_MAX_ITEMS and <prefix>_MAX_VALUE
+func getCartLimits(prefix string, defaults models.CartLimits) models.CartLimits {
+	return models.CartLimits{
+		MaxItems: getEnvAsInt(prefix+"_MAX_ITEMS", defaults.MaxItems),
+		MaxValue: getEnvAsFloat(prefix+"_MAX_VALUE", defaults.MaxValue),
+	}
+}
+
+// getPageSize reads PAGE_SIZE_<resource>_DEFAULT and PAGE_SIZE_<resource>_MAX
+func getPageSize(resource string, defaultSize, maxSize int) PageSizeConfig {
+	return PageSizeConfig{
+		Default: getEnvAsInt("PAGE_SIZE_"+resource+"_DEFAULT", defaultSize),
+		Max:     getEnvAsInt("PAGE_SIZE_"+resource+"_MAX", maxSize),
+	}
+}
+
 func getEnvAsInt64(key string, defaultValue int64) int64 {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
@@ -185,3 +535,32 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList reads a comma-separated list, skipping empty entries
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
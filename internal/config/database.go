@@ -20,11 +20,21 @@ func InitDatabase(cfg *Config) (*gorm.DB, error) {
 		cfg.Database.SSLMode,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: newSlowQueryLogger(cfg.Database.SlowQueryThreshold),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection pool: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+
 	// Note: Auto-migration is disabled since we use SQL migration files
 	// If you need to enable auto-migration for development, uncomment the following lines:
 	// if err := AutoMigrate(db); err != nil {
@@ -53,13 +63,40 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.Category{},
 		&models.Product{},
 		&models.ProductImage{},
+		&models.Tag{},
+		&models.ProductTag{},
 		&models.Order{},
 		&models.OrderItem{},
+		&models.OrderStatusHistory{},
+		&models.OrderFulfillment{},
+		&models.StockReservation{},
 		&models.Cart{},
 		&models.CartItem{},
 		&models.Review{},
 		&models.ReviewHelpful{},
 		&models.Wishlist{},
 		&models.Notification{},
+		&models.SearchLog{},
+		&models.ProductRecall{},
+		&models.FeaturedSeller{},
+		&models.PriceHistory{},
+		&models.Dispute{},
+		&models.ProductQuestion{},
+		&models.ProductAnswer{},
+		&models.Promotion{},
+		&models.Coupon{},
+		&models.Address{},
+		&models.PurchaseLimitExemption{},
+		&models.ProductTranslation{},
+		&models.SupportTicket{},
+		&models.SupportTicketMessage{},
+		&models.AuditLog{},
+		&models.ReviewEdit{},
+		&models.ShippingZone{},
+		&models.ShippingRate{},
+		&models.EmailBroadcast{},
+		&models.EmailDelivery{},
+		&models.Conversation{},
+		&models.ConversationMessage{},
 	)
 }
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryLogger logs queries that take at least threshold, with the ID of the
+// request that ran them. Errors are still logged by GORM's own logger, whose
+// slow-query output is turned off in favour of this one.
+type slowQueryLogger struct {
+	logger.Interface
+	threshold time.Duration
+	out       *log.Logger
+}
+
+func newSlowQueryLogger(threshold time.Duration) logger.Interface {
+	out := log.New(os.Stdout, "", log.LstdFlags)
+	return &slowQueryLogger{
+		Interface: logger.New(out, logger.Config{LogLevel: logger.Warn, Colorful: true}),
+		threshold: threshold,
+		out:       out,
+	}
+}
+
+func (l *slowQueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	clone := *l
+	clone.Interface = l.Interface.LogMode(level)
+	return &clone
+}
+
+func (l *slowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.Interface.Trace(ctx, begin, fc, err)
+
+	elapsed := time.Since(begin)
+	if l.threshold <= 0 || elapsed < l.threshold {
+		return
+	}
+
+	sql, rows := fc()
+	requestID := utils.RequestIDFromContext(ctx)
+	if requestID == "" {
+		requestID = "-"
+	}
+	l.out.Printf("Slow query: request_id=%s elapsed=%s rows=%d sql=%s", requestID, elapsed.Round(time.Millisecond), rows, sql)
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type AddressHandler struct {
+	addressService service.AddressService
+}
+
+func NewAddressHandler(addressService service.AddressService) *AddressHandler {
+	return &AddressHandler{addressService: addressService}
+}
+
+// GetAddresses lists the user's saved addresses
+// @Summary List saved addresses
+// @Description List the authenticated user's address book, defaults first
+// @Tags addresses
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.Address}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/addresses [get]
+func (h *AddressHandler) GetAddresses(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	addresses, err := h.addressService.GetAddresses(c.Request().Context(), userID)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Addresses retrieved successfully", addresses)
+}
+
+// GetAddress gets one saved address
+// @Summary Get a saved address
+// @Description Get one of the authenticated user's saved addresses
+// @Tags addresses
+// @Produce json
+// @Param id path int true "Address ID"
+// @Success 200 {object} utils.Response{data=models.Address}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/addresses/{id} [get]
+func (h *AddressHandler) GetAddress(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid address ID")
+	}
+
+	address, err := h.addressService.GetAddress(c.Request().Context(), userID, uint(id))
+	if err != nil {
+		return addressError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Address retrieved successfully", address)
+}
+
+// CreateAddress saves a new address
+// @Summary Save an address
+// @Description Add an address to the authenticated user's address book. Marking it as a default replaces the previous default of that kind; the first address becomes the default for both.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param address body models.AddressRequest true "Address"
+// @Success 201 {object} utils.Response{data=models.Address}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/addresses [post]
+func (h *AddressHandler) CreateAddress(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	var req models.AddressRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	address, err := h.addressService.CreateAddress(c.Request().Context(), userID, &req)
+	if err != nil {
+		return addressError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Address saved successfully", address)
+}
+
+// UpdateAddress replaces a saved address
+// @Summary Update a saved address
+// @Description Replace one of the authenticated user's saved addresses. Orders already placed keep the address they were placed with.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int true "Address ID"
+// @Param address body models.AddressRequest true "Address"
+// @Success 200 {object} utils.Response{data=models.Address}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/addresses/{id} [put]
+func (h *AddressHandler) UpdateAddress(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid address ID")
+	}
+
+	var req models.AddressRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	address, err := h.addressService.UpdateAddress(c.Request().Context(), userID, uint(id), &req)
+	if err != nil {
+		return addressError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Address updated successfully", address)
+}
+
+// DeleteAddress removes a saved address
+// @Summary Delete a saved address
+// @Description Remove one of the authenticated user's saved addresses
+// @Tags addresses
+// @Produce json
+// @Param id path int true "Address ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/addresses/{id} [delete]
+func (h *AddressHandler) DeleteAddress(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid address ID")
+	}
+
+	if err := h.addressService.DeleteAddress(c.Request().Context(), userID, uint(id)); err != nil {
+		return addressError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Address deleted successfully", nil)
+}
+
+// addressError maps address service errors to responses
+func addressError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAddressNotFound):
+		return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+	case errors.Is(err, service.ErrAddressBookFull):
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+	return serviceError(c, err)
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
@@ -16,6 +19,7 @@ type AdminHandler struct {
 	productService service.ProductService
 	orderService   service.OrderService
 	reviewService  service.ReviewService
+	searchService  service.SearchService
 }
 
 func NewAdminHandler(
@@ -23,12 +27,14 @@ func NewAdminHandler(
 	productService service.ProductService,
 	orderService service.OrderService,
 	reviewService service.ReviewService,
+	searchService service.SearchService,
 ) *AdminHandler {
 	return &AdminHandler{
 		userService:    userService,
 		productService: productService,
 		orderService:   orderService,
 		reviewService:  reviewService,
+		searchService:  searchService,
 	}
 }
 
@@ -90,6 +96,8 @@ func (h *AdminHandler) GetDashboardStats(c echo.Context) error {
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
 // @Param period query string false "Period (daily, weekly, monthly)" default(daily)
+// @Param format query string false "Response format (json, csv)" default(json)
+// @Param compare query bool false "Compare with the preceding period of the same length"
 // @Success 200 {object} utils.Response{data=models.SalesAnalytics}
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
@@ -103,6 +111,16 @@ func (h *AdminHandler) GetSalesAnalytics(c echo.Context) error {
 		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
 	}
 
+	format, err := utils.ExportFormat(c)
+	if err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+
+	compare, err := parseCompareParam(c)
+	if err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid compare value (use true or false)")
+	}
+
 	var startDate, endDate *time.Time
 	period := c.QueryParam("period")
 	if period == "" {
@@ -135,7 +153,7 @@ func (h *AdminHandler) GetSalesAnalytics(c echo.Context) error {
 
 	analytics, err := h.orderService.GetOrderAnalytics(c.Request().Context(), nil, startDate, endDate)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	salesAnalytics := &models.SalesAnalytics{
@@ -153,6 +171,18 @@ func (h *AdminHandler) GetSalesAnalytics(c echo.Context) error {
 		// You can add more detailed analytics here like daily/weekly/monthly breakdowns
 	}
 
+	if compare {
+		salesAnalytics.Comparison, err = h.orderService.ComparePeriods(c.Request().Context(), nil, *startDate, *endDate)
+		if err != nil {
+			return serviceError(c, err)
+		}
+	}
+
+	if format == utils.ExportFormatCSV {
+		filename := utils.ExportFilename("sales", *startDate, *endDate, format)
+		return utils.ExportCSV(c, filename, []*models.SalesAnalytics{salesAnalytics})
+	}
+
 	return utils.SuccessResponse(c, "Sales analytics retrieved successfully", salesAnalytics)
 }
 
@@ -176,7 +206,7 @@ func (h *AdminHandler) GetUserAnalytics(c echo.Context) error {
 	// You would implement GetUserStats in UserService
 	// userStats, err := h.userService.GetUserStats(c.Request().Context())
 	// if err != nil {
-	//     return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	//     return serviceError(c, err)
 	// }
 
 	// For now, return a placeholder response
@@ -270,6 +300,183 @@ func (h *AdminHandler) GetReviewAnalytics(c echo.Context) error {
 	return utils.SuccessResponse(c, "Review analytics retrieved successfully", reviewAnalytics)
 }
 
+// GetCancellationAnalytics retrieves cancellation analytics
+// @Summary Get cancellation analytics
+// @Description Break down cancelled orders by reason over a date range (admin only)
+// @Tags admin
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param format query string false "Response format (json, csv); CSV has one row per reason" default(json)
+// @Success 200 {object} utils.Response{data=models.CancellationAnalytics}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/analytics/cancellations [get]
+func (h *AdminHandler) GetCancellationAnalytics(c echo.Context) error {
+	userRole := c.Get("user_role").(models.UserRole)
+	if userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
+	}
+
+	format, err := utils.ExportFormat(c)
+	if err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+
+	analytics, err := h.orderService.GetCancellationAnalytics(c.Request().Context(), startDate, endDate)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	if format == utils.ExportFormatCSV {
+		filename := utils.ExportFilename("cancellations", startDate, endDate, format)
+		return utils.ExportCSV(c, filename, analytics.ByReason)
+	}
+
+	return utils.SuccessResponse(c, "Cancellation analytics retrieved successfully", analytics)
+}
+
+// GetBestSellers retrieves the best-selling products
+// @Summary Get best sellers
+// @Description Rank products by units sold on orders placed over a date range, excluding cancelled and refunded orders. The CSV export includes every product sold (admin only)
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param limit query int false "Products to return as JSON (max 100)" default(20)
+// @Param format query string false "Response format (json, csv)" default(json)
+// @Success 200 {object} utils.Response{data=models.BestSellersReport}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/analytics/best-sellers [get]
+func (h *AdminHandler) GetBestSellers(c echo.Context) error {
+	userRole := c.Get("user_role").(models.UserRole)
+	if userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
+	}
+
+	format, err := utils.ExportFormat(c)
+	if err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+
+	if format == utils.ExportFormatCSV {
+		filename := utils.ExportFilename("best_sellers", startDate, endDate, format)
+		return utils.StreamCSV(c, filename, utils.CSVHeader(models.BestSeller{}), func(write func(record []string) error) error {
+			return h.orderService.EachBestSeller(c.Request().Context(), startDate, endDate, func(bestSeller *models.BestSeller) error {
+				return write(utils.CSVRecord(bestSeller))
+			})
+		})
+	}
+
+	limit := 20
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 100 {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		}
+		limit = parsed
+	}
+
+	report, err := h.orderService.GetBestSellers(c.Request().Context(), startDate, endDate, limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Best sellers retrieved successfully", report)
+}
+
+// ExportOrders streams the accounting export of orders
+// @Summary Export orders for accounting
+// @Description Download one CSV row per order item for orders placed over a date range: order number, date, customer, product, SKU, quantity, unit price, the line's share of the order's discount, tax and shipping, order totals, and payment and refund status. Every order is included whatever its status (admin only)
+// @Tags admin
+// @Produce text/csv
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param format query string false "Export format (csv)" default(csv)
+// @Success 200 {file} file
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/orders/export [get]
+func (h *AdminHandler) ExportOrders(c echo.Context) error {
+	userRole := c.Get("user_role").(models.UserRole)
+	if userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
+	}
+
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = utils.ExportFormatCSV
+	}
+	if format != utils.ExportFormatCSV {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid format (use csv)")
+	}
+
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+
+	filename := utils.ExportFilename("orders", startDate, endDate, format)
+	return utils.StreamCSV(c, filename, utils.CSVHeader(models.OrderExportLine{}), func(write func(record []string) error) error {
+		return h.orderService.EachOrderExportLine(c.Request().Context(), startDate, endDate, func(line *models.OrderExportLine) error {
+			return write(utils.CSVRecord(line))
+		})
+	})
+}
+
+// GetSearchAnalytics retrieves search analytics
+// @Summary Get search analytics
+// @Description Get top search queries and top zero-result queries over a date range (admin only)
+// @Tags admin
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} utils.Response{data=models.SearchAnalytics}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/analytics/searches [get]
+func (h *AdminHandler) GetSearchAnalytics(c echo.Context) error {
+	userRole := c.Get("user_role").(models.UserRole)
+	if userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
+	}
+
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+
+	analytics, err := h.searchService.GetSearchAnalytics(c.Request().Context(), startDate, endDate)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Search analytics retrieved successfully", analytics)
+}
+
 // GetSystemHealth checks system health
 // @Summary Get system health
 // @Description Get system health status (admin only)
@@ -354,14 +561,342 @@ func (h *AdminHandler) GetOrderDetails(c echo.Context) error {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID")
+		return utils.InvalidIDError(c, "Invalid order ID")
 	}
 
 	// Admin can view any order, so we pass admin role
 	order, err := h.orderService.GetOrder(c.Request().Context(), uint(id), 0, models.RoleAdmin)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusNotFound, "Order not found")
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.OrderNotFound, "Order not found")
 	}
 
 	return utils.SuccessResponse(c, "Order details retrieved successfully", order)
 }
+
+// GetFraudReviewQueue retrieves orders held for fraud review
+// @Summary Get fraud review queue
+// @Description Get orders flagged by fraud scoring and awaiting manual review (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.FraudReviewItem}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/orders/review [get]
+func (h *AdminHandler) GetFraudReviewQueue(c echo.Context) error {
+	userRole := c.Get("user_role").(models.UserRole)
+	if userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
+	}
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
+
+	offset := (page - 1) * limit
+
+	items, err := h.orderService.GetFlaggedOrders(c.Request().Context(), limit, offset)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Fraud review queue retrieved successfully", items)
+}
+
+// GetStuckOrders retrieves orders that have breached their status SLA
+// @Summary Get stuck orders
+// @Description Get orders that have sat in their status longer than the configured SLA, longest waiting first (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.StuckOrder}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/orders/stuck [get]
+func (h *AdminHandler) GetStuckOrders(c echo.Context) error {
+	userRole := c.Get("user_role").(models.UserRole)
+	if userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
+	}
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
+
+	offset := (page - 1) * limit
+
+	orders, err := h.orderService.GetStuckOrders(c.Request().Context(), limit, offset)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Stuck orders retrieved successfully", orders)
+}
+
+// ReviewFlaggedOrder approves or rejects an order held for fraud review
+// @Summary Review flagged order
+// @Description Approve (release to payment) or reject (cancel as fraud) a flagged order (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param review body models.FraudReviewRequest true "Review decision"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/orders/{id}/review [put]
+func (h *AdminHandler) ReviewFlaggedOrder(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+	if userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	var req models.FraudReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	err = h.orderService.ReviewFlaggedOrder(c.Request().Context(), uint(id), &req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotPendingReview) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
+	}
+
+	if req.Approve {
+		return utils.SuccessResponse(c, "Order approved successfully", nil)
+	}
+	return utils.SuccessResponse(c, "Order rejected successfully", nil)
+}
+
+// RecalculateOrder recomputes an order's stored totals from its items
+// @Summary Recalculate order totals
+// @Description Repair an order's totals: line totals from unit price and quantity, shipping re-quoted, and the order total, keeping discounts and tax. Charged or shipped orders need force. The before and after totals are audited (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param recalculate body models.OrderRecalculateRequest false "Force and note"
+// @Success 200 {object} utils.Response{data=models.OrderRecalculation}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/orders/{id}/recalculate [post]
+func (h *AdminHandler) RecalculateOrder(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	var req models.OrderRecalculateRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	recalculation, err := h.orderService.RecalculateOrder(c.Request().Context(), uint(id), adminID, &req)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Order recalculated successfully", recalculation)
+}
+
+// BulkModerateReviews applies a moderation action to many reviews
+// @Summary Bulk moderate reviews
+// @Description Approve, reject or delete a list of reviews in one transaction with per-review results (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param moderation body models.BulkModerateRequest true "Review IDs and action"
+// @Success 200 {object} utils.Response{data=models.BulkModerateResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/reviews/bulk-moderate [post]
+func (h *AdminHandler) BulkModerateReviews(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+	if userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
+	}
+
+	var req models.BulkModerateRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	result, err := h.reviewService.BulkModerate(c.Request().Context(), &req, userID)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Reviews moderated successfully", result)
+}
+
+// GetReviewHistory lists a review's earlier versions
+// @Summary Get review edit history
+// @Description Get the rating, title and comment a review had before each edit, oldest first (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Review ID"
+// @Success 200 {object} utils.Response{data=[]models.ReviewEdit}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/reviews/{id}/history [get]
+func (h *AdminHandler) GetReviewHistory(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid review ID")
+	}
+
+	edits, err := h.reviewService.GetReviewHistory(c.Request().Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrReviewNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Review history retrieved successfully", edits)
+}
+
+// DeactivateSeller takes a seller off the marketplace
+// @Summary Deactivate seller
+// @Description Deactivate a seller account and hide its active products. Orders and reviews are kept (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Seller ID"
+// @Success 200 {object} utils.Response{data=models.SellerStatusChange}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/sellers/{id}/deactivate [post]
+func (h *AdminHandler) DeactivateSeller(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid seller ID")
+	}
+
+	result, err := h.userService.DeactivateSeller(c.Request().Context(), uint(id), adminID)
+	if err != nil {
+		return sellerStatusError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Seller deactivated successfully", result)
+}
+
+// ReactivateSeller puts a deactivated seller back on the marketplace
+// @Summary Reactivate seller
+// @Description Reactivate a seller account and restore the products its deactivation hid (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Seller ID"
+// @Success 200 {object} utils.Response{data=models.SellerStatusChange}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/sellers/{id}/reactivate [post]
+func (h *AdminHandler) ReactivateSeller(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid seller ID")
+	}
+
+	result, err := h.userService.ReactivateSeller(c.Request().Context(), uint(id), adminID)
+	if err != nil {
+		return sellerStatusError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Seller reactivated successfully", result)
+}
+
+func sellerStatusError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+	case errors.Is(err, service.ErrNotASeller):
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrSellerAlreadyDeactivated),
+		errors.Is(err, service.ErrSellerAlreadyActive):
+		return utils.ErrorResponseFromError(c, http.StatusConflict, err)
+	default:
+		return serviceError(c, err)
+	}
+}
+
+// parseDateRange reads start_date/end_date query params, defaulting to the last 30 days.
+// The end date is inclusive of the whole day.
+func parseDateRange(c echo.Context) (time.Time, time.Time, error) {
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -30)
+
+	if startDateStr := c.QueryParam("start_date"); startDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid start_date format (use YYYY-MM-DD)")
+		}
+		startDate = parsed
+	}
+
+	if endDateStr := c.QueryParam("end_date"); endDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid end_date format (use YYYY-MM-DD)")
+		}
+		endDate = parsed.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, errors.New("end_date must not be before start_date")
+	}
+
+	return startDate, endDate, nil
+}
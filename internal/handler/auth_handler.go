@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
@@ -44,8 +46,14 @@ func (h *authHandler) Register(c echo.Context) error {
 
 	response, err := h.authService.Register(c.Request().Context(), &req)
 	if err != nil {
-		if err.Error() == "user with this email already exists" {
-			return utils.ConflictError(c, err.Error())
+		if errors.Is(err, service.ErrEmailTaken) {
+			return utils.ErrorResponseFromError(c, http.StatusConflict, err)
+		}
+		if errors.Is(err, service.ErrGuestClaimUnverified) {
+			return utils.ErrorResponseFromError(c, http.StatusForbidden, err)
+		}
+		if errors.Is(err, service.ErrInvalidToken) {
+			return utils.ErrorResponseFromError(c, http.StatusUnauthorized, err)
 		}
 		return utils.InternalServerError(c, "Failed to register user")
 	}
@@ -73,7 +81,7 @@ func (h *authHandler) Login(c echo.Context) error {
 
 	response, err := h.authService.Login(c.Request().Context(), &req)
 	if err != nil {
-		return utils.UnauthorizedError(c, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusUnauthorized, err)
 	}
 
 	return utils.SuccessResponse(c, "Login successful", response)
@@ -100,7 +108,7 @@ func (h *authHandler) RefreshToken(c echo.Context) error {
 	
 	newToken, err := h.authService.RefreshToken(c.Request().Context(), token)
 	if err != nil {
-		return utils.UnauthorizedError(c, "Invalid or expired token")
+		return utils.ErrorResponseWithCode(c, http.StatusUnauthorized, apierror.TokenInvalid, "Invalid or expired token")
 	}
 
 	return utils.SuccessResponse(c, "Token refreshed successfully", map[string]string{
@@ -141,7 +149,7 @@ func (h *authHandler) GetProfile(c echo.Context) error {
 	
 	user, err := h.authService.GetCurrentUser(c.Request().Context(), userID)
 	if err != nil {
-		return utils.NotFoundError(c, "User not found")
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.UserNotFound, "User not found")
 	}
 
 	return utils.SuccessResponse(c, "Profile retrieved successfully", user)
@@ -169,8 +177,8 @@ func (h *authHandler) ChangePassword(c echo.Context) error {
 
 	err := h.authService.ChangePassword(c.Request().Context(), userID, &req)
 	if err != nil {
-		if err.Error() == "current password is incorrect" {
-			return utils.BadRequestError(c, err.Error())
+		if errors.Is(err, service.ErrIncorrectPassword) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 		}
 		return utils.InternalServerError(c, "Failed to change password")
 	}
@@ -198,12 +206,12 @@ func (h *authHandler) ForgotPassword(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
 	err := h.authService.ForgotPassword(c.Request().Context(), req.Email)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Password reset email sent successfully", nil)
@@ -230,17 +238,47 @@ func (h *authHandler) ResetPassword(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
 	err := h.authService.ResetPassword(c.Request().Context(), req.Token, req.NewPassword)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Password reset successfully", nil)
 }
 
+// CheckEmailAvailability reports whether an email can be used to register
+// @Summary Check email availability
+// @Description Check whether an email is free for registration. Rate limited with the other auth endpoints to prevent enumeration.
+// @Tags auth
+// @Produce json
+// @Param email query string true "Email address"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Router /auth/email-available [get]
+func (h *authHandler) CheckEmailAvailability(c echo.Context) error {
+	var req models.EmailAvailabilityRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	available, err := h.authService.IsEmailAvailable(c.Request().Context(), req.Email)
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to check email availability")
+	}
+
+	return utils.SuccessResponse(c, "Email availability checked", map[string]bool{
+		"available": available,
+	})
+}
+
 // VerifyEmail handles email verification
 // @Summary Verify email address
 // @Description Verify the user's email address using the token sent to their email
@@ -259,7 +297,7 @@ func (h *authHandler) VerifyEmail(c echo.Context) error {
 
 	err := h.authService.VerifyEmail(c.Request().Context(), token)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Email verified successfully", nil)
@@ -285,12 +323,12 @@ func (h *authHandler) ResendVerification(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
 	err := h.authService.ResendVerification(c.Request().Context(), req.Email)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Verification email sent successfully", nil)
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
@@ -21,6 +23,7 @@ func NewCartHandler(cartService service.CartService) *CartHandler {
 // AddToCart adds a product to user's cart
 func (h *CartHandler) AddToCart(c echo.Context) error {
 	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
 
 	var req models.CartAddRequest
 	if err := c.Bind(&req); err != nil {
@@ -28,24 +31,47 @@ func (h *CartHandler) AddToCart(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
-	cart, err := h.cartService.AddToCart(c.Request().Context(), userID, &req)
+	cart, err := h.cartService.AddToCart(c.Request().Context(), userID, userRole, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return cartError(c, err)
 	}
 
 	return utils.CreatedResponse(c, "Product added to cart successfully", cart)
 }
 
+// AddManyToCart adds a list of products to user's cart
+func (h *CartHandler) AddManyToCart(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	var req models.CartBulkAddRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+
+	result, err := h.cartService.AddMany(c.Request().Context(), userID, userRole, &req)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Products added to cart successfully", result)
+}
+
 // UpdateCartItem updates quantity of a product in user's cart
 func (h *CartHandler) UpdateCartItem(c echo.Context) error {
 	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
 
 	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	var req struct {
@@ -56,12 +82,12 @@ func (h *CartHandler) UpdateCartItem(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
-	cart, err := h.cartService.UpdateCartItem(c.Request().Context(), userID, uint(productID), req.Quantity)
+	cart, err := h.cartService.UpdateCartItem(c.Request().Context(), userID, userRole, uint(productID), req.Quantity)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return cartError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Cart item updated successfully", cart)
@@ -73,12 +99,12 @@ func (h *CartHandler) RemoveFromCart(c echo.Context) error {
 
 	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	err = h.cartService.RemoveFromCart(c.Request().Context(), userID, uint(productID))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product removed from cart successfully", nil)
@@ -90,10 +116,12 @@ func (h *CartHandler) GetUserCart(c echo.Context) error {
 
 	cart, err := h.cartService.GetUserCart(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
-	return utils.SuccessResponse(c, "Cart retrieved successfully", cart)
+	return utils.SuccessResponseWithMeta(c, "Cart retrieved successfully", cart, map[string]interface{}{
+		"locale": getLocale(c),
+	})
 }
 
 // GetCartTotal retrieves user's cart total
@@ -102,10 +130,24 @@ func (h *CartHandler) GetCartTotal(c echo.Context) error {
 
 	total, err := h.cartService.GetCartTotal(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Cart total retrieved successfully", total)
+}
+
+// GetCartSummary retrieves the cart's subtotal, tax, shipping, discount and grand total
+func (h *CartHandler) GetCartSummary(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+	destination := c.QueryParam("destination")
+
+	summary, err := h.cartService.GetCartSummary(c.Request().Context(), userID, userRole, destination)
+	if err != nil {
+		return serviceError(c, err)
 	}
 
-	return utils.SuccessResponse(c, "Cart total retrieved successfully", map[string]float64{"total": total})
+	return utils.SuccessResponse(c, "Cart summary retrieved successfully", summary)
 }
 
 // ClearCart clears user's entire cart
@@ -114,7 +156,7 @@ func (h *CartHandler) ClearCart(c echo.Context) error {
 
 	err := h.cartService.ClearCart(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Cart cleared successfully", nil)
@@ -126,8 +168,23 @@ func (h *CartHandler) GetCartItemCount(c echo.Context) error {
 
 	count, err := h.cartService.GetCartItemCount(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Cart item count retrieved successfully", map[string]int{"count": count})
 }
+
+// cartError maps cart service errors to responses with their error codes
+func cartError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ProductNotFound, err.Error())
+	case errors.Is(err, service.ErrCartItemNotFound):
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.CartItemNotFound, err.Error())
+	case errors.Is(err, service.ErrProductUnavailable):
+		return utils.ErrorResponseWithCode(c, http.StatusBadRequest, apierror.ProductUnavailable, err.Error())
+	case errors.Is(err, service.ErrInsufficientStock):
+		return utils.ErrorResponseWithCode(c, http.StatusBadRequest, apierror.InsufficientStock, err.Error())
+	}
+	return serviceError(c, err)
+}
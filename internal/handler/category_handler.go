@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
@@ -26,12 +27,12 @@ func (h *CategoryHandler) CreateCategory(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
 	category, err := h.categoryService.CreateCategory(c.Request().Context(), &req)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.CreatedResponse(c, "Category created successfully", category)
@@ -41,12 +42,12 @@ func (h *CategoryHandler) CreateCategory(c echo.Context) error {
 func (h *CategoryHandler) GetCategory(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return utils.InvalidIDError(c, "Invalid category ID")
 	}
 
 	category, err := h.categoryService.GetCategory(c.Request().Context(), uint(id))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusNotFound, "Category not found")
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.CategoryNotFound, "Category not found")
 	}
 
 	return utils.SuccessResponse(c, "Category retrieved successfully", category)
@@ -56,7 +57,7 @@ func (h *CategoryHandler) GetCategory(c echo.Context) error {
 func (h *CategoryHandler) GetAllCategories(c echo.Context) error {
 	categories, err := h.categoryService.GetAllCategories(c.Request().Context())
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Categories retrieved successfully", categories)
@@ -71,7 +72,7 @@ func (h *CategoryHandler) GetCategoryBySlug(c echo.Context) error {
 
 	category, err := h.categoryService.GetCategoryBySlug(c.Request().Context(), slug)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusNotFound, "Category not found")
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.CategoryNotFound, "Category not found")
 	}
 
 	return utils.SuccessResponse(c, "Category retrieved successfully", category)
@@ -81,7 +82,7 @@ func (h *CategoryHandler) GetCategoryBySlug(c echo.Context) error {
 func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return utils.InvalidIDError(c, "Invalid category ID")
 	}
 
 	var req models.CategoryUpdateRequest
@@ -90,12 +91,12 @@ func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
 	category, err := h.categoryService.UpdateCategory(c.Request().Context(), uint(id), &req)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Category updated successfully", category)
@@ -105,12 +106,12 @@ func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
 func (h *CategoryHandler) DeleteCategory(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return utils.InvalidIDError(c, "Invalid category ID")
 	}
 
 	err = h.categoryService.DeleteCategory(c.Request().Context(), uint(id))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Category deleted successfully", nil)
@@ -120,7 +121,7 @@ func (h *CategoryHandler) DeleteCategory(c echo.Context) error {
 func (h *CategoryHandler) GetCategoriesHierarchy(c echo.Context) error {
 	categories, err := h.categoryService.GetCategoriesHierarchy(c.Request().Context())
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Categories hierarchy retrieved successfully", categories)
@@ -130,12 +131,12 @@ func (h *CategoryHandler) GetCategoriesHierarchy(c echo.Context) error {
 func (h *CategoryHandler) GetCategoryChildren(c echo.Context) error {
 	parentID, err := strconv.ParseUint(c.Param("parentId"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid parent category ID")
+		return utils.InvalidIDError(c, "Invalid parent category ID")
 	}
 
 	categories, err := h.categoryService.GetCategoryChildren(c.Request().Context(), uint(parentID))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Child categories retrieved successfully", categories)
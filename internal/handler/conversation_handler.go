@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type ConversationHandler struct {
+	conversationService service.ConversationService
+}
+
+func NewConversationHandler(conversationService service.ConversationService) *ConversationHandler {
+	return &ConversationHandler{conversationService: conversationService}
+}
+
+// ContactSeller sends a private message to a seller
+// @Summary Contact a seller
+// @Description Send a private message to a seller, optionally about one of their products or one of the authenticated user's orders. Messages about the same product and order continue the same conversation. The seller is notified.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path int true "Seller ID"
+// @Param message body models.ContactSellerRequest true "Message"
+// @Success 201 {object} utils.Response{data=models.Conversation}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /sellers/{id}/messages [post]
+func (h *ConversationHandler) ContactSeller(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	sellerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid seller ID")
+	}
+
+	var req models.ContactSellerRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	conversation, err := h.conversationService.ContactSeller(c.Request().Context(), userID, uint(sellerID), &req)
+	if err != nil {
+		return conversationError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Message sent successfully", conversation)
+}
+
+// GetConversations lists the user's conversations
+// @Summary List my conversations
+// @Description List the authenticated user's conversations with sellers or customers, most recent activity first
+// @Tags messages
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=[]models.Conversation}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations [get]
+func (h *ConversationHandler) GetConversations(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceDefault)
+
+	conversations, total, err := h.conversationService.GetConversations(c.Request().Context(), userID, limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Conversations retrieved successfully", conversations, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetConversation retrieves a conversation with its messages
+// @Summary Get a conversation
+// @Description Get a conversation with its messages, oldest first (participants and admins only)
+// @Tags messages
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Success 200 {object} utils.Response{data=models.Conversation}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/{id} [get]
+func (h *ConversationHandler) GetConversation(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid conversation ID")
+	}
+
+	conversation, err := h.conversationService.GetConversation(c.Request().Context(), userID, userRole, uint(id))
+	if err != nil {
+		return conversationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Conversation retrieved successfully", conversation)
+}
+
+// SendMessage replies in a conversation
+// @Summary Reply in a conversation
+// @Description Add a message to a conversation the authenticated user takes part in. The other participant is notified.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Param message body models.ConversationMessageRequest true "Message"
+// @Success 200 {object} utils.Response{data=models.Conversation}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/{id}/messages [post]
+func (h *ConversationHandler) SendMessage(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid conversation ID")
+	}
+
+	var req models.ConversationMessageRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	conversation, err := h.conversationService.SendMessage(c.Request().Context(), userID, uint(id), &req)
+	if err != nil {
+		return conversationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Message sent successfully", conversation)
+}
+
+// conversationError maps conversation service errors to HTTP responses
+func conversationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrConversationNotFound),
+		errors.Is(err, service.ErrSellerNotFound),
+		errors.Is(err, service.ErrProductNotFound),
+		errors.Is(err, service.ErrOrderNotFound):
+		return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+	case errors.Is(err, service.ErrMessageSelf):
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+	return serviceError(c, err)
+}
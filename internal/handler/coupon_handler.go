@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type CouponHandler struct {
+	couponService service.CouponService
+	cartService   service.CartService
+}
+
+func NewCouponHandler(couponService service.CouponService, cartService service.CartService) *CouponHandler {
+	return &CouponHandler{
+		couponService: couponService,
+		cartService:   cartService,
+	}
+}
+
+// ValidateCoupon previews a coupon against the user's cart
+// @Summary Validate a coupon
+// @Description Check whether a coupon can be used on the authenticated user's cart and what it would take off, without holding one of its uses. A coupon that can't be used returns valid=false with the reason. Rate limited per client to stop code guessing.
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Param coupon body models.CouponValidateRequest true "Coupon code"
+// @Success 200 {object} utils.Response{data=models.CouponPreview}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /coupons/validate [post]
+func (h *CouponHandler) ValidateCoupon(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	var req models.CouponValidateRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	summary, err := h.cartService.GetCartSummary(c.Request().Context(), userID, userRole, "")
+	if err != nil || summary.ItemCount == 0 {
+		return utils.ErrorResponseWithCode(c, http.StatusBadRequest, apierror.CartEmpty, "Cart is empty")
+	}
+
+	// Checkout applies coupons to the subtotal net of line discounts, on top
+	// of any promotion
+	var promotionDiscount float64
+	if summary.Promotion != nil {
+		promotionDiscount = summary.Promotion.Discount
+	}
+	subtotal := summary.Subtotal - (summary.Discount - promotionDiscount)
+
+	preview, err := h.couponService.Preview(c.Request().Context(), req.Code, userID, subtotal, promotionDiscount, summary.Lines)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Coupon checked successfully", preview)
+}
+
+// GetCoupons lists coupons
+// @Summary List coupons
+// @Description List all coupons with their paid usage counts (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=[]models.Coupon}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/coupons [get]
+func (h *CouponHandler) GetCoupons(c echo.Context) error {
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
+
+	coupons, total, err := h.couponService.GetCoupons(c.Request().Context(), limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Coupons retrieved successfully", coupons, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// CreateCoupon creates a coupon
+// @Summary Create a coupon
+// @Description Create a percent or fixed-amount coupon with an optional usage limit and expiry (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param coupon body models.CreateCouponRequest true "Coupon"
+// @Success 201 {object} utils.Response{data=models.Coupon}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/coupons [post]
+func (h *CouponHandler) CreateCoupon(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	var req models.CreateCouponRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	coupon, err := h.couponService.CreateCoupon(c.Request().Context(), &req, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCouponCodeTaken):
+			return utils.ErrorResponseWithCode(c, http.StatusConflict, apierror.CouponCodeTaken, err.Error())
+		case errors.Is(err, service.ErrCouponPercentTooHigh),
+			errors.Is(err, service.ErrExpiryInPast):
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Coupon created successfully", coupon)
+}
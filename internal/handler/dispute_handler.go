@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// maxWebhookPayloadSize caps how much of a webhook body is read
+const maxWebhookPayloadSize = 65536
+
+type DisputeHandler struct {
+	disputeService service.DisputeService
+}
+
+func NewDisputeHandler(disputeService service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{disputeService: disputeService}
+}
+
+// StripeWebhook receives Stripe webhook events
+// @Summary Stripe webhook
+// @Description Receive Stripe events; dispute events create or update the order's dispute. Requires a valid Stripe-Signature header.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /webhooks/stripe [post]
+func (h *DisputeHandler) StripeWebhook(c echo.Context) error {
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookPayloadSize))
+	if err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read request body")
+	}
+
+	if err := h.disputeService.HandlePaymentWebhook(c.Request().Context(), payload, c.Request().Header.Get("Stripe-Signature")); err != nil {
+		if errors.Is(err, service.ErrInvalidWebhook) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Webhook processed successfully", nil)
+}
+
+// GetDisputes retrieves payment disputes
+// @Summary Get disputes
+// @Description Get payment disputes, soonest evidence deadline first (admin only)
+// @Tags admin
+// @Produce json
+// @Param status query string false "Dispute status"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.DisputeResponse}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/disputes [get]
+func (h *DisputeHandler) GetDisputes(c echo.Context) error {
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
+
+	var status *models.DisputeStatus
+	if s := c.QueryParam("status"); s != "" {
+		disputeStatus := models.DisputeStatus(s)
+		status = &disputeStatus
+	}
+
+	disputes, total, err := h.disputeService.GetDisputes(c.Request().Context(), status, limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Disputes retrieved successfully", disputes, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetDispute retrieves a payment dispute
+// @Summary Get dispute
+// @Description Get a payment dispute with its order and evidence (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Dispute ID"
+// @Success 200 {object} utils.Response{data=models.DisputeResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/disputes/{id} [get]
+func (h *DisputeHandler) GetDispute(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid dispute ID")
+	}
+
+	dispute, err := h.disputeService.GetDispute(c.Request().Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrDisputeNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Dispute retrieved successfully", dispute)
+}
+
+// SubmitDisputeEvidence records evidence for a payment dispute
+// @Summary Submit dispute evidence
+// @Description Record evidence notes and document URLs for an open dispute (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Dispute ID"
+// @Param evidence body models.DisputeEvidenceRequest true "Evidence metadata"
+// @Success 200 {object} utils.Response{data=models.DisputeResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/disputes/{id}/evidence [put]
+func (h *DisputeHandler) SubmitDisputeEvidence(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid dispute ID")
+	}
+
+	var req models.DisputeEvidenceRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	dispute, err := h.disputeService.SubmitEvidence(c.Request().Context(), uint(id), &req, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDisputeNotFound):
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		case errors.Is(err, service.ErrDisputeClosed):
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Dispute evidence saved successfully", dispute)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type EmailBroadcastHandler struct {
+	broadcastService service.EmailBroadcastService
+}
+
+func NewEmailBroadcastHandler(broadcastService service.EmailBroadcastService) *EmailBroadcastHandler {
+	return &EmailBroadcastHandler{broadcastService: broadcastService}
+}
+
+// CreateEmailBroadcast queues an email to every active user in an audience
+// @Summary Send an email broadcast
+// @Description Queue an email to every active user in the audience (all, customers or sellers). It is sent in the background at the configured send rate; poll the broadcast for progress (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param broadcast body models.EmailBroadcastRequest true "Email broadcast"
+// @Success 201 {object} utils.Response{data=models.EmailBroadcastProgress}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/email-broadcasts [post]
+func (h *EmailBroadcastHandler) CreateEmailBroadcast(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	var req models.EmailBroadcastRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	progress, err := h.broadcastService.CreateBroadcast(c.Request().Context(), &req, adminID)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Email broadcast queued successfully", progress)
+}
+
+// GetEmailBroadcasts lists email broadcasts
+// @Summary List email broadcasts
+// @Description List email broadcasts, newest first, with their delivery progress (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=[]models.EmailBroadcastProgress}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/email-broadcasts [get]
+func (h *EmailBroadcastHandler) GetEmailBroadcasts(c echo.Context) error {
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
+
+	broadcasts, total, err := h.broadcastService.GetBroadcasts(c.Request().Context(), limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Email broadcasts retrieved successfully", broadcasts, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetEmailBroadcast retrieves an email broadcast's progress
+// @Summary Get email broadcast status
+// @Description Get a broadcast with its pending, sent and failed delivery counts (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Email broadcast ID"
+// @Success 200 {object} utils.Response{data=models.EmailBroadcastProgress}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/email-broadcasts/{id} [get]
+func (h *EmailBroadcastHandler) GetEmailBroadcast(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid email broadcast ID")
+	}
+
+	progress, err := h.broadcastService.GetBroadcast(c.Request().Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrBroadcastNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Email broadcast retrieved successfully", progress)
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+// serviceError responds to an error from a service with the status for its
+// kind and the code it carries. Handlers only need their own branch for errors
+// that should get a different status than their kind's.
+func serviceError(c echo.Context, err error) error {
+	return utils.ErrorResponseFromError(c, serviceErrorStatus(err), err)
+}
+
+// serviceErrorStatus maps a service error kind to its HTTP status. Errors of
+// no known kind are internal errors.
+func serviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInvalid), errors.Is(err, service.ErrLimitReached):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
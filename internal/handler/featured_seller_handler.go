@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type FeaturedSellerHandler struct {
+	featuredSellerService service.FeaturedSellerService
+}
+
+func NewFeaturedSellerHandler(featuredSellerService service.FeaturedSellerService) *FeaturedSellerHandler {
+	return &FeaturedSellerHandler{featuredSellerService: featuredSellerService}
+}
+
+// GetFeaturedSellers returns the curated storefront sellers
+// @Summary Get featured sellers
+// @Description Get the public profiles of the currently featured sellers in display order
+// @Tags sellers
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.SellerPublicProfile}
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /sellers/featured [get]
+func (h *FeaturedSellerHandler) GetFeaturedSellers(c echo.Context) error {
+	profiles, err := h.featuredSellerService.GetFeaturedSellers(c.Request().Context())
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Featured sellers retrieved successfully", profiles)
+}
+
+// ListFeaturedSellers returns every featured entry, including expired ones
+// @Summary List featured seller entries
+// @Description Get all featured seller entries with position and expiry (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.FeaturedSeller}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/featured-sellers [get]
+func (h *FeaturedSellerHandler) ListFeaturedSellers(c echo.Context) error {
+	featured, err := h.featuredSellerService.ListFeaturedSellers(c.Request().Context())
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Featured sellers retrieved successfully", featured)
+}
+
+// FeatureSeller adds a seller to the storefront
+// @Summary Feature a seller
+// @Description Add a seller to the featured list with an optional position and expiry (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param featured body models.FeatureSellerRequest true "Seller to feature"
+// @Success 201 {object} utils.Response{data=models.FeaturedSeller}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/featured-sellers [post]
+func (h *FeaturedSellerHandler) FeatureSeller(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	var req models.FeatureSellerRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	featured, err := h.featuredSellerService.FeatureSeller(c.Request().Context(), &req, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSellerNotFound):
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		case errors.Is(err, service.ErrSellerAlreadyFeatured):
+			return utils.ErrorResponseFromError(c, http.StatusConflict, err)
+		case errors.Is(err, service.ErrFeaturedSellerLimit),
+			errors.Is(err, service.ErrExpiryInPast):
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Seller featured successfully", featured)
+}
+
+// UnfeatureSeller removes a seller from the storefront
+// @Summary Unfeature a seller
+// @Description Remove a seller from the featured list (admin only)
+// @Tags admin
+// @Produce json
+// @Param seller_id path int true "Seller ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/featured-sellers/{seller_id} [delete]
+func (h *FeaturedSellerHandler) UnfeatureSeller(c echo.Context) error {
+	sellerID, err := strconv.ParseUint(c.Param("seller_id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid seller ID")
+	}
+
+	if err := h.featuredSellerService.UnfeatureSeller(c.Request().Context(), uint(sellerID)); err != nil {
+		if errors.Is(err, service.ErrFeaturedSellerNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Seller unfeatured successfully", nil)
+}
+
+// ReorderFeaturedSellers sets the featured display order
+// @Summary Reorder featured sellers
+// @Description Set the display order of featured sellers; unlisted sellers follow in their current order (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param order body models.ReorderFeaturedSellersRequest true "Seller IDs in display order"
+// @Success 200 {object} utils.Response{data=[]models.FeaturedSeller}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/featured-sellers/order [put]
+func (h *FeaturedSellerHandler) ReorderFeaturedSellers(c echo.Context) error {
+	var req models.ReorderFeaturedSellersRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	featured, err := h.featuredSellerService.ReorderFeaturedSellers(c.Request().Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrFeaturedSellerNotFound):
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		case errors.Is(err, service.ErrDuplicateFeaturedSeller):
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Featured sellers reordered successfully", featured)
+}
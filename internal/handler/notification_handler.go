@@ -26,12 +26,12 @@ func (h *NotificationHandler) CreateNotification(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
 	notification, err := h.notificationService.CreateNotification(c.Request().Context(), &req)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.CreatedResponse(c, "Notification created successfully", notification)
@@ -41,21 +41,13 @@ func (h *NotificationHandler) CreateNotification(c echo.Context) error {
 func (h *NotificationHandler) GetUserNotifications(c echo.Context) error {
 	userID := c.Get("user_id").(uint)
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page < 1 {
-		page = 1
-	}
-
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit < 1 {
-		limit = 10
-	}
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceNotifications)
 
 	offset := (page - 1) * limit
 
 	notifications, err := h.notificationService.GetUserNotifications(c.Request().Context(), userID, limit, offset)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Notifications retrieved successfully", notifications)
@@ -67,7 +59,7 @@ func (h *NotificationHandler) GetUnreadNotifications(c echo.Context) error {
 
 	notifications, err := h.notificationService.GetUnreadNotifications(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Unread notifications retrieved successfully", notifications)
@@ -79,12 +71,12 @@ func (h *NotificationHandler) MarkAsRead(c echo.Context) error {
 
 	notificationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid notification ID")
+		return utils.InvalidIDError(c, "Invalid notification ID")
 	}
 
 	err = h.notificationService.MarkAsRead(c.Request().Context(), userID, uint(notificationID))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Notification marked as read", nil)
@@ -96,7 +88,7 @@ func (h *NotificationHandler) MarkAllAsRead(c echo.Context) error {
 
 	err := h.notificationService.MarkAllAsRead(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "All notifications marked as read", nil)
@@ -108,12 +100,12 @@ func (h *NotificationHandler) DeleteNotification(c echo.Context) error {
 
 	notificationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid notification ID")
+		return utils.InvalidIDError(c, "Invalid notification ID")
 	}
 
 	err = h.notificationService.DeleteNotification(c.Request().Context(), userID, uint(notificationID))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Notification deleted successfully", nil)
@@ -125,7 +117,7 @@ func (h *NotificationHandler) GetNotificationCount(c echo.Context) error {
 
 	count, err := h.notificationService.GetNotificationCount(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Notification count retrieved successfully", map[string]int{"count": count})
@@ -137,7 +129,7 @@ func (h *NotificationHandler) GetUnreadCount(c echo.Context) error {
 
 	count, err := h.notificationService.GetUnreadCount(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Unread notification count retrieved successfully", map[string]int{"count": count})
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
@@ -12,15 +15,20 @@ import (
 )
 
 type OrderHandler struct {
-	orderService service.OrderService
+	orderService   service.OrderService
+	productService service.ProductService
 }
 
-func NewOrderHandler(orderService service.OrderService) *OrderHandler {
+func NewOrderHandler(orderService service.OrderService, productService service.ProductService) *OrderHandler {
 	return &OrderHandler{
-		orderService: orderService,
+		orderService:   orderService,
+		productService: productService,
 	}
 }
 
+// confirmationRecommendationLimit caps the products recommended on the confirmation page
+const confirmationRecommendationLimit = 8
+
 // CreateOrder creates a new order
 // @Summary Create a new order
 // @Description Create a new order with items
@@ -48,7 +56,92 @@ func (h *OrderHandler) CreateOrder(c echo.Context) error {
 
 	order, err := h.orderService.CreateOrder(c.Request().Context(), &req, userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return orderRequestError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Order created successfully", order)
+}
+
+// PreviewOrder prices an order without placing it
+// @Summary Preview order totals
+// @Description Work out the subtotal, discounts, shipping, tax and total an order would have, with the same checks as placing it, without saving it or holding stock or the coupon
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param order body models.CreateOrderRequest true "Order data, as for creating the order"
+// @Success 200 {object} utils.Response{data=models.OrderPreview}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /orders/preview [post]
+func (h *OrderHandler) PreviewOrder(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	var req models.CreateOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	preview, err := h.orderService.PreviewOrder(c.Request().Context(), &req, userID)
+	if err != nil {
+		return orderRequestError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Order preview calculated successfully", preview)
+}
+
+// orderRequestError responds to an error creating or previewing an order
+func orderRequestError(c echo.Context, err error) error {
+	if isCouponError(err) {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+	// A saved shipping or billing address that can't be found is the
+	// request's fault, so it's a bad request here rather than a 404
+	if errors.Is(err, service.ErrAddressNotFound) ||
+		errors.Is(err, service.ErrPurchaseLimitReached) ||
+		errors.Is(err, service.ErrMinimumOrderNotMet) {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+	return serviceError(c, err)
+}
+
+// CreateGuestOrder creates an order without an account
+// @Summary Create a guest order
+// @Description Check out with an email and shipping address instead of an account. The response carries the guest_token needed to pay for the order, and registering with the email later claims it.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param order body models.GuestOrderRequest true "Order, contact and shipping data"
+// @Success 201 {object} utils.Response{data=models.Order}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /orders/guest [post]
+func (h *OrderHandler) CreateGuestOrder(c echo.Context) error {
+	var req models.GuestOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	order, err := h.orderService.CreateGuestOrder(c.Request().Context(), &req)
+	if err != nil {
+		if isCouponError(err) ||
+			errors.Is(err, service.ErrPurchaseLimitReached) ||
+			errors.Is(err, service.ErrMinimumOrderNotMet) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Order created successfully", order)
@@ -73,25 +166,121 @@ func (h *OrderHandler) GetOrder(c echo.Context) error {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID")
+		return utils.InvalidIDError(c, "Invalid order ID")
 	}
 
 	order, err := h.orderService.GetOrder(c.Request().Context(), uint(id), userID, userRole)
 	if err != nil {
-		if err.Error() == "unauthorized to view this order" {
-			return utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		if errors.Is(err, service.ErrOrderViewForbidden) {
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.OrderForbidden, err.Error())
 		}
-		return utils.ErrorResponse(c, http.StatusNotFound, "Order not found")
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.OrderNotFound, "Order not found")
 	}
 
 	return utils.SuccessResponse(c, "Order retrieved successfully", order)
 }
 
+// TrackOrder shows where an order is to whoever knows its number and email
+// @Summary Track an order
+// @Description Get an order's status, status timeline and shipment tracking by its order number and the email it was placed with, without signing in. Nothing about the customer, address or items is returned.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param track body models.OrderTrackRequest true "Order number and email"
+// @Success 200 {object} utils.Response{data=models.OrderTrackingStatus}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /orders/track [post]
+func (h *OrderHandler) TrackOrder(c echo.Context) error {
+	var req models.OrderTrackRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	tracking, err := h.orderService.TrackOrder(c.Request().Context(), &req)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Order tracking retrieved successfully", tracking)
+}
+
+// GetOrderConfirmation retrieves the data for the order confirmation page
+// @Summary Get order confirmation page
+// @Description Get the order with its itemized totals, delivery estimate, tracking (once shipped) and recommended products in one call, for the post-checkout thank-you page (owning customer only)
+// @Tags orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response{data=models.OrderConfirmationPage}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /orders/{id}/confirmation [get]
+func (h *OrderHandler) GetOrderConfirmation(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	// Checked as a customer so only the customer who placed the order gets
+	// its confirmation page, whatever their role
+	order, err := h.orderService.GetOrder(c.Request().Context(), uint(id), userID, models.RoleCustomer)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderViewForbidden) {
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.OrderForbidden, err.Error())
+		}
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.OrderNotFound, "Order not found")
+	}
+
+	return utils.SuccessResponse(c, "Order confirmation retrieved successfully",
+		models.NewOrderConfirmationPage(order, h.confirmationRecommendations(c, order)))
+}
+
+// confirmationRecommendations collects products related to the order's items,
+// leaving out what was just bought. Recommendations are best effort; a failed
+// lookup only shortens the list.
+func (h *OrderHandler) confirmationRecommendations(c echo.Context, order *models.Order) []*models.Product {
+	seen := make(map[uint]bool, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		seen[item.ProductID] = true
+	}
+
+	recommended := make([]*models.Product, 0, confirmationRecommendationLimit)
+	for _, item := range order.OrderItems {
+		if len(recommended) >= confirmationRecommendationLimit {
+			break
+		}
+		related, err := h.productService.GetRelatedProducts(c.Request().Context(), item.ProductID, confirmationRecommendationLimit)
+		if err != nil {
+			continue
+		}
+		for _, product := range related {
+			if seen[product.ID] || len(recommended) >= confirmationRecommendationLimit {
+				continue
+			}
+			seen[product.ID] = true
+			recommended = append(recommended, product)
+		}
+	}
+	return recommended
+}
+
 // GetUserOrders retrieves orders for the current user
 // @Summary Get user orders
 // @Description Get orders for the authenticated user
 // @Tags orders
 // @Produce json
+// @Param include_archived query bool false "Include orders archived after the retention period"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} utils.Response{data=[]models.Order}
@@ -103,21 +292,18 @@ func (h *OrderHandler) GetOrder(c echo.Context) error {
 func (h *OrderHandler) GetUserOrders(c echo.Context) error {
 	userID := c.Get("user_id").(uint)
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
+	includeArchived, err := parseIncludeArchivedParam(c)
+	if err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid include_archived value (use true or false)")
 	}
 
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceOrders)
 
 	offset := (page - 1) * limit
 
-	orders, err := h.orderService.GetUserOrders(c.Request().Context(), userID, limit, offset)
+	orders, err := h.orderService.GetUserOrders(c.Request().Context(), userID, includeArchived, limit, offset)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Orders retrieved successfully", orders)
@@ -125,11 +311,16 @@ func (h *OrderHandler) GetUserOrders(c echo.Context) error {
 
 // GetAllOrders retrieves all orders (admin only)
 // @Summary Get all orders
-// @Description Get all orders (admin only)
+// @Description Get all orders, optionally filtered by status, creation date and product category (admin only)
 // @Tags orders
 // @Produce json
+// @Param status query string false "Order status"
+// @Param category query string false "Only orders containing a product in this category"
+// @Param start_date query string false "Created on or after (YYYY-MM-DD)"
+// @Param end_date query string false "Created on or before (YYYY-MM-DD)"
+// @Param include_archived query bool false "Include orders archived after the retention period"
 // @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
+// @Param limit query int false "Items per page" default(20)
 // @Success 200 {object} utils.Response{data=[]models.Order}
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
@@ -144,24 +335,47 @@ func (h *OrderHandler) GetAllOrders(c echo.Context) error {
 		return utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
 	}
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
+	filter := &models.OrderFilter{Category: strings.TrimSpace(c.QueryParam("category"))}
+	if statusStr := c.QueryParam("status"); statusStr != "" {
+		status := models.OrderStatus(statusStr)
+		filter.Status = &status
 	}
-
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
+	if startDateStr := c.QueryParam("start_date"); startDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format (use YYYY-MM-DD)")
+		}
+		filter.DateFrom = &parsed
+	}
+	if endDateStr := c.QueryParam("end_date"); endDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format (use YYYY-MM-DD)")
+		}
+		// Include the whole end day
+		dateTo := parsed.AddDate(0, 0, 1)
+		filter.DateTo = &dateTo
 	}
+	includeArchived, err := parseIncludeArchivedParam(c)
+	if err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid include_archived value (use true or false)")
+	}
+	filter.IncludeArchived = includeArchived
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
 
 	offset := (page - 1) * limit
 
-	orders, err := h.orderService.GetAllOrders(c.Request().Context(), limit, offset)
+	orders, total, err := h.orderService.GetAllOrders(c.Request().Context(), filter, limit, offset)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
-	return utils.SuccessResponse(c, "Orders retrieved successfully", orders)
+	return utils.SuccessResponseWithMeta(c, "Orders retrieved successfully", orders, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
 }
 
 // GetOrdersByStatus retrieves orders by status
@@ -189,21 +403,13 @@ func (h *OrderHandler) GetOrdersByStatus(c echo.Context) error {
 	statusStr := c.Param("status")
 	status := models.OrderStatus(statusStr)
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
-	}
-
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceOrders)
 
 	offset := (page - 1) * limit
 
 	orders, err := h.orderService.GetOrdersByStatus(c.Request().Context(), status, limit, offset)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Orders retrieved successfully", orders)
@@ -214,6 +420,8 @@ func (h *OrderHandler) GetOrdersByStatus(c echo.Context) error {
 // @Description Get orders containing seller's products
 // @Tags orders
 // @Produce json
+// @Param include_archived query bool false "Include orders archived after the retention period"
+// @Param assigned_to query string false "Only orders assigned to this staff member's ID, or none for unassigned orders"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} utils.Response{data=[]models.Order}
@@ -231,26 +439,151 @@ func (h *OrderHandler) GetSellerOrders(c echo.Context) error {
 		return utils.ErrorResponse(c, http.StatusForbidden, "Seller access required")
 	}
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
+	includeArchived, err := parseIncludeArchivedParam(c)
+	if err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid include_archived value (use true or false)")
 	}
-
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
+	filter := &models.SellerOrderFilter{IncludeArchived: includeArchived}
+	if err := parseAssignedToParam(c, filter); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid assigned_to value (use a staff ID or none)")
 	}
 
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceOrders)
+
 	offset := (page - 1) * limit
 
-	orders, err := h.orderService.GetSellerOrders(c.Request().Context(), userID, limit, offset)
+	orders, err := h.orderService.GetSellerOrders(c.Request().Context(), userID, filter, limit, offset)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Seller orders retrieved successfully", orders)
 }
 
+// GetAssignedOrders retrieves the orders assigned to the current staff member
+// @Summary Get my assigned orders
+// @Description Get the live orders assigned to the authenticated staff member, each showing only the portion they fulfill (seller staff only)
+// @Tags orders
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Order}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /staff/orders [get]
+func (h *OrderHandler) GetAssignedOrders(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceOrders)
+
+	offset := (page - 1) * limit
+
+	orders, err := h.orderService.GetAssignedOrders(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Assigned orders retrieved successfully", orders)
+}
+
+// AssignOrder assigns the seller's portion of an order to a staff member
+// @Summary Assign order to staff
+// @Description Assign or reassign the seller's portion of an order to one of their staff, or unassign it with a null staff_id. The staff member is notified. Admins pass seller_id for split orders.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body models.OrderAssignRequest true "Assignment"
+// @Success 200 {object} utils.Response{data=models.Order}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /seller/orders/{id}/assignee [put]
+func (h *OrderHandler) AssignOrder(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	var req models.OrderAssignRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	order, err := h.orderService.AssignOrder(c.Request().Context(), uint(id), &req, userID, userRole)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound), errors.Is(err, service.ErrStaffNotFound):
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		case errors.Is(err, service.ErrOrderAssignForbidden):
+			return utils.ErrorResponseFromError(c, http.StatusForbidden, err)
+		case errors.Is(err, service.ErrOrderNotAssignable):
+			return utils.ErrorResponseFromError(c, http.StatusConflict, err)
+		case errors.Is(err, service.ErrAssignSellerRequired):
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Order assignment updated successfully", order)
+}
+
+// GetProductOrders retrieves orders containing one of the seller's products
+// @Summary Get orders for a product
+// @Description Get orders containing a specific product, with the product's line item highlighted (seller of the product/admin)
+// @Tags orders
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.ProductOrderItem}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /seller/products/{id}/orders [get]
+func (h *OrderHandler) GetProductOrders(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	if userRole != models.RoleSeller && userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Seller access required")
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceOrders)
+
+	offset := (page - 1) * limit
+
+	orders, err := h.orderService.GetProductOrders(c.Request().Context(), uint(productID), userID, userRole, limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ProductNotFound, err.Error())
+		case errors.Is(err, service.ErrProductOrdersForbidden):
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.ProductForbidden, err.Error())
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product orders retrieved successfully", orders)
+}
+
 // UpdateOrderStatus updates the status of an order
 // @Summary Update order status
 // @Description Update order status (admin/seller)
@@ -264,6 +597,7 @@ func (h *OrderHandler) GetSellerOrders(c echo.Context) error {
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Security BearerAuth
 // @Router /orders/{id}/status [put]
@@ -273,7 +607,7 @@ func (h *OrderHandler) UpdateOrderStatus(c echo.Context) error {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID")
+		return utils.InvalidIDError(c, "Invalid order ID")
 	}
 
 	var req models.UpdateOrderStatusRequest
@@ -283,10 +617,7 @@ func (h *OrderHandler) UpdateOrderStatus(c echo.Context) error {
 
 	err = h.orderService.UpdateOrderStatus(c.Request().Context(), uint(id), req.Status, userID, userRole)
 	if err != nil {
-		if err.Error() == "unauthorized to update this order" {
-			return utils.ErrorResponse(c, http.StatusForbidden, err.Error())
-		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Order status updated successfully", nil)
@@ -304,13 +635,14 @@ func (h *OrderHandler) UpdateOrderStatus(c echo.Context) error {
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Security BearerAuth
 // @Router /orders/{id}/payment [post]
 func (h *OrderHandler) ProcessPayment(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID")
+		return utils.InvalidIDError(c, "Invalid order ID")
 	}
 
 	var req models.PaymentRequest
@@ -324,7 +656,76 @@ func (h *OrderHandler) ProcessPayment(c echo.Context) error {
 
 	paymentResponse, err := h.orderService.ProcessPayment(c.Request().Context(), uint(id), &req)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Payment processed successfully", paymentResponse)
+}
+
+// ExtendReservation extends how long an unpaid order holds its stock
+// @Summary Extend stock reservation
+// @Description Hold an unpaid order's stock for another reservation period while checking out, up to the longest an order may hold it (Owner)
+// @Tags orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response{data=models.ReservationHold}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /orders/{id}/reservation/extend [post]
+func (h *OrderHandler) ExtendReservation(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	hold, err := h.orderService.ExtendReservation(c.Request().Context(), uint(id), userID)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Stock reservation extended successfully", hold)
+}
+
+// ProcessGuestPayment processes payment for a guest order
+// @Summary Pay for a guest order
+// @Description Process payment for an order placed with guest checkout, using the guest_token returned when it was placed. The order total is charged.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param payment body models.GuestPaymentRequest true "Payment data and guest token"
+// @Success 200 {object} utils.Response{data=models.PaymentResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /orders/guest/{id}/payment [post]
+func (h *OrderHandler) ProcessGuestPayment(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	var req models.GuestPaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	paymentResponse, err := h.orderService.ProcessGuestPayment(c.Request().Context(), uint(id), &req)
+	if err != nil {
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Payment processed successfully", paymentResponse)
@@ -332,15 +733,18 @@ func (h *OrderHandler) ProcessPayment(c echo.Context) error {
 
 // CancelOrder cancels an order
 // @Summary Cancel order
-// @Description Cancel an order
+// @Description Cancel an order with an optional reason
 // @Tags orders
+// @Accept json
 // @Produce json
 // @Param id path int true "Order ID"
+// @Param cancel body models.CancelOrderRequest false "Cancellation reason"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Security BearerAuth
 // @Router /orders/{id}/cancel [put]
@@ -350,15 +754,21 @@ func (h *OrderHandler) CancelOrder(c echo.Context) error {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID")
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	var req models.CancelOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
 	}
 
-	err = h.orderService.CancelOrder(c.Request().Context(), uint(id), userID, userRole)
+	err = h.orderService.CancelOrder(c.Request().Context(), uint(id), &req, userID, userRole)
 	if err != nil {
-		if err.Error() == "unauthorized to cancel this order" {
-			return utils.ErrorResponse(c, http.StatusForbidden, err.Error())
-		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Order cancelled successfully", nil)
@@ -371,6 +781,7 @@ func (h *OrderHandler) CancelOrder(c echo.Context) error {
 // @Produce json
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param compare query bool false "Compare with the preceding period of the same length (defaults the range to the last 30 days)"
 // @Success 200 {object} utils.Response{data=models.OrderAnalytics}
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
@@ -386,6 +797,11 @@ func (h *OrderHandler) GetOrderAnalytics(c echo.Context) error {
 		return utils.ErrorResponse(c, http.StatusForbidden, "Access denied")
 	}
 
+	compare, err := parseCompareParam(c)
+	if err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid compare value (use true or false)")
+	}
+
 	var startDate, endDate *time.Time
 
 	if startDateStr := c.QueryParam("start_date"); startDateStr != "" {
@@ -400,6 +816,14 @@ func (h *OrderHandler) GetOrderAnalytics(c echo.Context) error {
 		}
 	}
 
+	// A comparison needs a bounded range, so default to the last 30 days
+	if compare && (startDate == nil || endDate == nil) {
+		now := time.Now()
+		endDate = &now
+		start := now.AddDate(0, 0, -30)
+		startDate = &start
+	}
+
 	var sellerID *uint
 	if userRole == models.RoleSeller {
 		sellerID = &userID
@@ -407,8 +831,231 @@ func (h *OrderHandler) GetOrderAnalytics(c echo.Context) error {
 
 	analytics, err := h.orderService.GetOrderAnalytics(c.Request().Context(), sellerID, startDate, endDate)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
+	}
+
+	if compare {
+		analytics.Comparison, err = h.orderService.ComparePeriods(c.Request().Context(), sellerID, *startDate, *endDate)
+		if err != nil {
+			return serviceError(c, err)
+		}
 	}
 
 	return utils.SuccessResponse(c, "Order analytics retrieved successfully", analytics)
 }
+
+// parseCompareParam reads the optional compare query parameter
+func parseCompareParam(c echo.Context) (bool, error) {
+	compareStr := c.QueryParam("compare")
+	if compareStr == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(compareStr)
+}
+
+// parseIncludeArchivedParam reads the optional include_archived query parameter
+func parseIncludeArchivedParam(c echo.Context) (bool, error) {
+	includeStr := c.QueryParam("include_archived")
+	if includeStr == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(includeStr)
+}
+
+// parseAssignedToParam reads the optional assigned_to query parameter: a staff
+// member's ID, or none for orders nobody is assigned to
+func parseAssignedToParam(c echo.Context, filter *models.SellerOrderFilter) error {
+	assignedStr := c.QueryParam("assigned_to")
+	switch assignedStr {
+	case "":
+		return nil
+	case "none":
+		filter.Unassigned = true
+		return nil
+	}
+	staffID, err := strconv.ParseUint(assignedStr, 10, 32)
+	if err != nil {
+		return err
+	}
+	id := uint(staffID)
+	filter.AssignedTo = &id
+	return nil
+}
+
+// isCouponError reports whether err is a coupon the customer can't use
+func isCouponError(err error) bool {
+	return errors.Is(err, service.ErrInvalidCoupon) ||
+		errors.Is(err, service.ErrCouponExpired) ||
+		errors.Is(err, service.ErrCouponMinSpend) ||
+		errors.Is(err, service.ErrCouponNotApplicable) ||
+		errors.Is(err, service.ErrCouponUsageLimit)
+}
+
+// UpdateShippingAddress changes where an order ships
+// @Summary Update order shipping address
+// @Description Change the shipping address of the authenticated user's order, to a saved address or a new one, until the order is processed or shipped. The change is recorded in the order's history.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param address body models.UpdateShippingAddressRequest true "New shipping address"
+// @Success 200 {object} utils.Response{data=models.Order}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /orders/{id}/shipping-address [put]
+func (h *OrderHandler) UpdateShippingAddress(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	var req models.UpdateShippingAddressRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	order, err := h.orderService.UpdateShippingAddress(c.Request().Context(), uint(id), &req, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderModifyForbidden):
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.OrderForbidden, err.Error())
+		case errors.Is(err, service.ErrAddressNotFound):
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		case errors.Is(err, service.ErrOrderShipped):
+			return utils.ErrorResponseWithCode(c, http.StatusConflict, apierror.OrderNotModifiable, err.Error())
+		case errors.Is(err, service.ErrOrderNotModifiable):
+			return utils.ErrorResponseWithCode(c, http.StatusBadRequest, apierror.OrderNotModifiable, err.Error())
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Shipping address updated successfully", order)
+}
+
+// ResendConfirmationEmail re-sends the order confirmation email
+// @Summary Resend order confirmation email
+// @Description Send the order confirmation email to the customer again, up to 3 times an hour per order (order owner/admin)
+// @Tags orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /orders/{id}/resend-confirmation [post]
+func (h *OrderHandler) ResendConfirmationEmail(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	err = h.orderService.ResendConfirmationEmail(c.Request().Context(), uint(id), userID, userRole)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderViewForbidden):
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.OrderForbidden, err.Error())
+		case errors.Is(err, service.ErrOrderCancelled):
+			return utils.ErrorResponseWithCode(c, http.StatusBadRequest, apierror.OrderNotModifiable, err.Error())
+		case errors.Is(err, service.ErrResendLimitReached):
+			return utils.ErrorResponseWithCode(c, http.StatusTooManyRequests, apierror.ResendLimitReached, err.Error())
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Order confirmation email sent successfully", nil)
+}
+
+// GetConfirmationQueue retrieves paid orders awaiting merchant confirmation
+// @Summary Get order confirmation queue
+// @Description Get paid orders held for confirmation because auto-confirmation is off, oldest first. Sellers only see orders containing their products (seller/admin)
+// @Tags orders
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Order}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /orders/confirmation-queue [get]
+func (h *OrderHandler) GetConfirmationQueue(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceOrders)
+
+	orders, err := h.orderService.GetConfirmationQueue(c.Request().Context(), userID, userRole, limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Confirmation queue retrieved successfully", orders)
+}
+
+// ReviewOrderConfirmation approves or rejects an order awaiting confirmation
+// @Summary Approve or reject an order awaiting confirmation
+// @Description Approve a paid order so it can be fulfilled, or reject it, which cancels and refunds it (seller of an item in the order/admin)
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param decision body models.OrderConfirmationRequest true "Review decision"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /orders/{id}/confirmation [put]
+func (h *OrderHandler) ReviewOrderConfirmation(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	var req models.OrderConfirmationRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	err = h.orderService.ReviewOrderConfirmation(c.Request().Context(), uint(id), &req, userID, userRole)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotAwaitingConfirmation):
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		case errors.Is(err, service.ErrOrderUpdateForbidden),
+			errors.Is(err, service.ErrOrderStatusForbidden):
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.OrderForbidden, err.Error())
+		}
+		return serviceError(c, err)
+	}
+
+	if req.Approve {
+		return utils.SuccessResponse(c, "Order confirmed successfully", nil)
+	}
+	return utils.SuccessResponse(c, "Order rejected and refunded successfully", nil)
+}
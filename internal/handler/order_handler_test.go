@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// fakeOrderService returns err from the calls under test
+type fakeOrderService struct {
+	service.OrderService
+	err error
+}
+
+func (s *fakeOrderService) ProcessPayment(ctx context.Context, orderID uint, req *models.PaymentRequest) (*models.PaymentResponse, error) {
+	return nil, s.err
+}
+
+func (s *fakeOrderService) CancelOrder(ctx context.Context, id uint, req *models.CancelOrderRequest, userID uint, userRole models.UserRole) error {
+	return s.err
+}
+
+func (s *fakeOrderService) UpdateOrderStatus(ctx context.Context, id uint, status models.OrderStatus, userID uint, userRole models.UserRole) error {
+	return s.err
+}
+
+const paymentBody = `{"order_id": 1, "payment_method": "card", "currency": "usd",
+	"success_url": "https://shop.example.com/ok", "cancel_url": "https://shop.example.com/cancel"}`
+
+// serve runs the handler with err as the service's result and returns the response
+func serve(t *testing.T, err error, body string, call func(*OrderHandler, echo.Context) error) (int, models.ErrorResponse) {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user_id", uint(7))
+	c.Set("user_role", models.RoleCustomer)
+
+	h := NewOrderHandler(&fakeOrderService{err: err}, nil)
+	if err := call(h, c); err != nil {
+		t.Fatalf("handler returned %v", err)
+	}
+
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return rec.Code, resp
+}
+
+func TestOrderHandlersMapServiceErrorsByKind(t *testing.T) {
+	processPayment := func(h *OrderHandler, c echo.Context) error { return h.ProcessPayment(c) }
+	cancelOrder := func(h *OrderHandler, c echo.Context) error { return h.CancelOrder(c) }
+	updateStatus := func(h *OrderHandler, c echo.Context) error { return h.UpdateOrderStatus(c) }
+
+	tests := []struct {
+		name       string
+		call       func(*OrderHandler, echo.Context) error
+		body       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"payment on missing order", processPayment, paymentBody, service.ErrOrderNotFound, http.StatusNotFound, "ORDER_NOT_FOUND"},
+		{"payment on paid order", processPayment, paymentBody, service.ErrOrderNotPending, http.StatusConflict, "CONFLICT"},
+		{"payment with wrong amount", processPayment, paymentBody, service.ErrPaymentAmountMismatch, http.StatusBadRequest, "BAD_REQUEST"},
+		{"payment stock not committed", processPayment, paymentBody, service.ErrStockNotCommitted, http.StatusConflict, "STOCK_NOT_COMMITTED"},
+		{"cancel missing order", cancelOrder, `{}`, service.ErrOrderNotFound, http.StatusNotFound, "ORDER_NOT_FOUND"},
+		{"cancel shipped order", cancelOrder, `{}`, service.ErrOrderNotCancellable, http.StatusConflict, "ORDER_NOT_CANCELLABLE"},
+		{"cancel someone else's order", cancelOrder, `{}`, service.ErrOrderCancelForbidden, http.StatusForbidden, "ORDER_FORBIDDEN"},
+		{"invalid transition", updateStatus, `{"status": "delivered"}`,
+			fmt.Errorf("wrapped: %w", service.ErrInvalidStatusTransition), http.StatusBadRequest, "INVALID_STATUS_TRANSITION"},
+		{"status update forbidden", updateStatus, `{"status": "shipped"}`, service.ErrOrderStatusForbidden, http.StatusForbidden, "ORDER_FORBIDDEN"},
+		{"unexpected error", updateStatus, `{"status": "shipped"}`, errors.New("connection reset"), http.StatusInternalServerError, "INTERNAL_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := serve(t, tt.err, tt.body, tt.call)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", resp.Code, tt.wantCode)
+			}
+		})
+	}
+}
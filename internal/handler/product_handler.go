@@ -1,22 +1,30 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/JonathanVera18/ecommerce-api/pkg/geo"
 	"github.com/labstack/echo/v4"
 )
 
 type ProductHandler struct {
 	productService service.ProductService
+	searchService  service.SearchService
 }
 
-func NewProductHandler(productService service.ProductService) *ProductHandler {
+func NewProductHandler(productService service.ProductService, searchService service.SearchService) *ProductHandler {
 	return &ProductHandler{
 		productService: productService,
+		searchService:  searchService,
 	}
 }
 
@@ -30,6 +38,7 @@ func NewProductHandler(productService service.ProductService) *ProductHandler {
 // @Success 201 {object} utils.Response{data=models.Product}
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Security BearerAuth
 // @Router /products [post]
@@ -51,9 +60,15 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 		return utils.ValidationError(c, validationErrors)
 	}
 
-	product, err := h.productService.CreateProduct(c.Request().Context(), &req, userID)
+	product, err := h.productService.CreateProduct(c.Request().Context(), &req, userID, userRole)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		if isBackorderLimitError(err) || isReturnPolicyError(err) || errors.Is(err, service.ErrInvalidAvailabilityWindow) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		if errors.Is(err, service.ErrSKUTaken) {
+			return utils.ErrorResponseWithCode(c, http.StatusConflict, apierror.SKUTaken, err.Error())
+		}
+		return serviceError(c, err)
 	}
 
 	return utils.CreatedResponse(c, "Product created successfully", product)
@@ -61,7 +76,7 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 
 // GetProduct retrieves a product by ID
 // @Summary Get product by ID
-// @Description Get product details by ID
+// @Description Get product details by ID. Products awaiting approval are only shown to their seller and admins.
 // @Tags products
 // @Produce json
 // @Param id path int true "Product ID"
@@ -72,13 +87,147 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 func (h *ProductHandler) GetProduct(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	product, err := h.productService.GetProduct(c.Request().Context(), uint(id))
+	if err != nil || !visibleTo(c, product) {
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ProductNotFound, "Product not found")
+	}
+	h.localize(c, product)
+
+	return utils.SuccessResponse(c, "Product retrieved successfully", product)
+}
+
+// GetProductsBatch retrieves several products by ID
+// @Summary Get products by IDs
+// @Description Get up to 100 products in one call, in the order requested. Unknown and deleted IDs are left out.
+// @Tags products
+// @Produce json
+// @Param ids query string true "Comma-separated product IDs"
+// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/batch [get]
+func (h *ProductHandler) GetProductsBatch(c echo.Context) error {
+	var ids []uint
+	for _, part := range strings.Split(c.QueryParam("ids"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || id == 0 {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID: "+part)
+		}
+		ids = append(ids, uint(id))
+	}
+	if len(ids) == 0 {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "ids is required")
+	}
+
+	return h.respondWithProducts(c, ids)
+}
+
+// PostProductsBatch retrieves several products by ID from a request body
+// @Summary Get products by IDs (body)
+// @Description Same as GET /products/batch for ID lists too long for a query string
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param request body models.BatchProductsRequest true "Product IDs"
+// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/batch [post]
+func (h *ProductHandler) PostProductsBatch(c echo.Context) error {
+	var req models.BatchProductsRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	return h.respondWithProducts(c, req.ProductIDs)
+}
+
+func (h *ProductHandler) respondWithProducts(c echo.Context, ids []uint) error {
+	products, err := h.productService.GetProductsByIDs(c.Request().Context(), ids)
+	if err != nil {
+		if errors.Is(err, service.ErrTooManyProductIDs) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
+	}
+	visible := products[:0]
+	for _, product := range products {
+		if visibleTo(c, product) {
+			visible = append(visible, product)
+		}
+	}
+	products = visible
+	h.localize(c, products...)
+
+	return utils.SuccessResponse(c, "Products retrieved successfully", products)
+}
+
+// GetProductChanges lists products changed since a point in time
+// @Summary Get product changes
+// @Description Incremental catalog sync for integrations: products created, updated or deleted at or after since, oldest change first, with their current stock and status. Pass next_cursor as cursor to read the next page, or to poll for later changes.
+// @Tags integrations
+// @Produce json
+// @Param since query string false "RFC 3339 timestamp; required without cursor"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param limit query int false "Changes per page (max 500)" default(100)
+// @Success 200 {object} utils.Response{data=models.ProductChangesResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security APIKeyAuth
+// @Router /products/changes [get]
+func (h *ProductHandler) GetProductChanges(c echo.Context) error {
+	var cursor models.ProductChangeCursor
+	if raw := c.QueryParam("cursor"); raw != "" {
+		decoded, err := models.DecodeProductChangeCursor(raw)
+		if err != nil {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid cursor")
+		}
+		cursor = decoded
+	} else {
+		since, err := time.Parse(time.RFC3339, c.QueryParam("since"))
+		if err != nil {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "since must be an RFC 3339 timestamp when no cursor is given")
+		}
+		cursor = models.ProductChangeCursor{Since: since, UpdatedAt: since}
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	changes, err := h.productService.GetProductChanges(c.Request().Context(), cursor, limit)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusNotFound, "Product not found")
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product changes retrieved successfully", changes)
+}
+
+// GetProductBySlug retrieves a product by slug
+// @Summary Get product by slug
+// @Description Get product details by its SEO-friendly slug
+// @Tags products
+// @Produce json
+// @Param slug path string true "Product slug"
+// @Success 200 {object} utils.Response{data=models.Product}
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /products/slug/{slug} [get]
+func (h *ProductHandler) GetProductBySlug(c echo.Context) error {
+	product, err := h.productService.GetProductBySlug(c.Request().Context(), c.Param("slug"))
+	if err != nil || !visibleTo(c, product) {
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ProductNotFound, "Product not found")
 	}
+	h.localize(c, product)
 
 	return utils.SuccessResponse(c, "Product retrieved successfully", product)
 }
@@ -93,20 +242,22 @@ func (h *ProductHandler) GetProduct(c echo.Context) error {
 // @Param category query string false "Filter by category"
 // @Param seller_id query int false "Filter by seller ID"
 // @Param search query string false "Search in product name and description"
+// @Param min_price query number false "Minimum price (inclusive)"
+// @Param max_price query number false "Maximum price (inclusive)"
+// @Param price_tier query string false "Budget tier (under_25, 25_50, 50_100, 100_plus)"
+// @Param tags query string false "Comma-separated tags to filter by"
+// @Param tag_match query string false "Match any or all of the tags (any, all)" default(any)
+// @Param include_unavailable query bool false "Also list products outside their available from/until window" default(false)
+// @Param stock_status query string false "Stock status (in_stock, low_stock, out_of_stock, backorderable)"
+// @Param sort_by query string false "Sort (newest, best_selling, price_asc, price_desc, rating, popular, name); defaults to the category's sort, then the store's"
+// @Param country query string false "Override detected country (ISO 3166-1 alpha-2)"
+// @Param currency query string false "Override suggested currency (ISO 4217)"
 // @Success 200 {object} utils.Response{data=models.ProductListResponse}
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /products [get]
 func (h *ProductHandler) GetProducts(c echo.Context) error {
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
-	}
-
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceProducts)
 
 	offset := (page - 1) * limit
 
@@ -125,12 +276,139 @@ func (h *ProductHandler) GetProducts(c echo.Context) error {
 		}
 	}
 
+	if minPriceStr := c.QueryParam("min_price"); minPriceStr != "" {
+		minPrice, err := strconv.ParseFloat(minPriceStr, 64)
+		if err != nil || minPrice < 0 {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid min_price")
+		}
+		req.MinPrice = &minPrice
+	}
+
+	if maxPriceStr := c.QueryParam("max_price"); maxPriceStr != "" {
+		maxPrice, err := strconv.ParseFloat(maxPriceStr, 64)
+		if err != nil || maxPrice < 0 {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid max_price")
+		}
+		req.MaxPrice = &maxPrice
+	}
+
+	if priceTier := c.QueryParam("price_tier"); priceTier != "" {
+		if _, ok := models.GetPriceTierRange(models.PriceTier(priceTier)); !ok {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid price_tier (use under_25, 25_50, 50_100 or 100_plus)")
+		}
+		req.PriceTier = models.PriceTier(priceTier)
+	}
+
+	if tags := c.QueryParam("tags"); tags != "" {
+		req.Tags = models.NormalizeTags(strings.Split(tags, ","))
+	}
+
+	switch tagMatch := models.TagMatchMode(c.QueryParam("tag_match")); tagMatch {
+	case "", models.TagMatchAny:
+		req.TagMatch = models.TagMatchAny
+	case models.TagMatchAll:
+		req.TagMatch = models.TagMatchAll
+	default:
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tag_match (use any or all)")
+	}
+
+	if includeUnavailable := c.QueryParam("include_unavailable"); includeUnavailable != "" {
+		include, err := strconv.ParseBool(includeUnavailable)
+		if err != nil {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid include_unavailable")
+		}
+		req.IncludeUnavailable = include
+	}
+
+	if stockStatus := models.StockStatus(c.QueryParam("stock_status")); stockStatus != "" {
+		if !stockStatus.IsValid() {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid stock_status (use in_stock, low_stock, out_of_stock or backorderable)")
+		}
+		req.StockStatus = stockStatus
+	}
+
+	sortBy, err := productSortParam(c)
+	if err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+	req.SortBy = sortBy
+
 	products, err := h.productService.GetProducts(c.Request().Context(), req)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, service.ErrInvalidPriceRange) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
 	}
 
-	return utils.SuccessResponse(c, "Products retrieved successfully", products)
+	h.localize(c, products.Products...)
+
+	return utils.SuccessResponseWithMeta(c, "Products retrieved successfully", products, map[string]interface{}{
+		"locale": getLocale(c),
+	})
+}
+
+// GetTags retrieves the most used tags for a tag cloud
+// @Summary Get popular tags
+// @Description Get the tags used by the most active products, with product counts
+// @Tags products
+// @Produce json
+// @Param limit query int false "Number of tags" default(50)
+// @Success 200 {object} utils.Response{data=[]models.TagCount}
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /tags [get]
+func (h *ProductHandler) GetTags(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	tags, err := h.productService.GetPopularTags(c.Request().Context(), limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Tags retrieved successfully", tags)
+}
+
+// GetProductsByTag retrieves products for a tag landing page
+// @Summary Get products by tag
+// @Description Get products carrying a tag, with pagination
+// @Tags products
+// @Produce json
+// @Param tag path string true "Tag"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=models.ProductListResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /tags/{tag}/products [get]
+func (h *ProductHandler) GetProductsByTag(c echo.Context) error {
+	tags := models.NormalizeTags([]string{c.Param("tag")})
+	if len(tags) == 0 {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tag")
+	}
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceProducts)
+
+	req := &models.GetProductsRequest{
+		Page:     page,
+		Limit:    limit,
+		Offset:   (page - 1) * limit,
+		Tags:     tags,
+		TagMatch: models.TagMatchAny,
+	}
+
+	products, err := h.productService.GetProducts(c.Request().Context(), req)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	h.localize(c, products.Products...)
+
+	return utils.SuccessResponseWithMeta(c, "Products retrieved successfully", products, map[string]interface{}{
+		"locale": getLocale(c),
+	})
 }
 
 // UpdateProduct updates an existing product
@@ -159,7 +437,7 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	var req models.UpdateProductRequest
@@ -167,12 +445,20 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
 	}
 
-	product, err := h.productService.UpdateProduct(c.Request().Context(), uint(id), &req, userID)
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	product, err := h.productService.UpdateProduct(c.Request().Context(), uint(id), &req, userID, userRole)
 	if err != nil {
-		if err.Error() == "unauthorized to update this product" {
-			return utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		if errors.Is(err, service.ErrProductUpdateForbidden) {
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.ProductForbidden, err.Error())
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, service.ErrComparePriceTooLow) || errors.Is(err, service.ErrInvalidPrice) || isBackorderLimitError(err) || isReturnPolicyError(err) ||
+			errors.Is(err, service.ErrInvalidAvailabilityWindow) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product updated successfully", product)
@@ -202,15 +488,15 @@ func (h *ProductHandler) DeleteProduct(c echo.Context) error {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	err = h.productService.DeleteProduct(c.Request().Context(), uint(id), userID)
 	if err != nil {
-		if err.Error() == "unauthorized to delete this product" {
-			return utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		if errors.Is(err, service.ErrProductDeleteForbidden) {
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.ProductForbidden, err.Error())
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product deleted successfully", nil)
@@ -242,7 +528,7 @@ func (h *ProductHandler) UpdateStock(c echo.Context) error {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	var req models.UpdateStockRequest
@@ -252,10 +538,10 @@ func (h *ProductHandler) UpdateStock(c echo.Context) error {
 
 	err = h.productService.UpdateStock(c.Request().Context(), uint(id), req.Stock, userID)
 	if err != nil {
-		if err.Error() == "unauthorized to update this product's stock" {
-			return utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		if errors.Is(err, service.ErrStockUpdateForbidden) {
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.ProductForbidden, err.Error())
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Stock updated successfully", nil)
@@ -294,108 +580,835 @@ func (h *ProductHandler) GetLowStockProducts(c echo.Context) error {
 
 	products, err := h.productService.GetLowStockProducts(c.Request().Context(), threshold, sellerID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Low stock products retrieved successfully", products)
 }
 
-// GetTopRatedProducts gets top rated products
-// @Summary Get top rated products
-// @Description Get products with highest ratings
+// GetPriceHistory retrieves a product's price changes
+// @Summary Get product price history
+// @Description Get every price change of a product, newest first (seller of the product/admin only)
 // @Tags products
 // @Produce json
-// @Param limit query int false "Number of products to return" default(10)
-// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=[]models.PriceHistory}
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
-// @Router /products/top-rated [get]
-func (h *ProductHandler) GetTopRatedProducts(c echo.Context) error {
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
+// @Security BearerAuth
+// @Router /products/{id}/price-history [get]
+func (h *ProductHandler) GetPriceHistory(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
-	products, err := h.productService.GetTopRatedProducts(c.Request().Context(), limit)
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceDefault)
+
+	history, total, err := h.productService.GetPriceHistory(c.Request().Context(), uint(id), userID, userRole, limit, (page-1)*limit)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ProductNotFound, err.Error())
+		case errors.Is(err, service.ErrPriceHistoryForbidden):
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.ProductForbidden, err.Error())
+		}
+		return serviceError(c, err)
 	}
 
-	return utils.SuccessResponse(c, "Top rated products retrieved successfully", products)
+	return utils.SuccessResponseWithMeta(c, "Price history retrieved successfully", history, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
 }
 
-// SearchProducts searches for products
-// @Summary Search products
-// @Description Search products by name and description
-// @Tags products
+// BulkSetVisibility shows or hides many products at once
+// @Summary Bulk set product visibility
+// @Description Show or hide a list of products, optionally changing their status, in one transaction with per-product results (seller/admin only). Sellers can only change their own products.
+// @Tags seller
+// @Accept json
 // @Produce json
-// @Param q query string true "Search query"
-// @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
-// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Param visibility body models.BulkVisibilityRequest true "Product IDs and target visibility or status"
+// @Success 200 {object} utils.Response{data=models.BulkVisibilityResponse}
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
-// @Router /products/search [get]
-func (h *ProductHandler) SearchProducts(c echo.Context) error {
-	query := c.QueryParam("q")
-	if query == "" {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Search query is required")
+// @Security BearerAuth
+// @Router /seller/products/visibility/bulk [put]
+func (h *ProductHandler) BulkSetVisibility(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	if userRole != models.RoleSeller && userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Access denied")
 	}
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
+	var req models.BulkVisibilityRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
 	}
 
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
 	}
 
-	offset := (page - 1) * limit
+	result, err := h.productService.BulkSetVisibility(c.Request().Context(), &req, userID, userRole)
+	if err != nil {
+		return serviceError(c, err)
+	}
 
-	products, err := h.productService.SearchProducts(c.Request().Context(), query, limit, offset)
+	return utils.SuccessResponse(c, "Product visibility updated successfully", result)
+}
+
+// GetTranslations lists a product's translations
+// @Summary Get product translations
+// @Description Get every locale a product has been translated into (seller of the product/admin only)
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response{data=[]models.ProductTranslation}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /products/{id}/translations [get]
+func (h *ProductHandler) GetTranslations(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
-	return utils.SuccessResponse(c, "Search results retrieved successfully", products)
+	translations, err := h.productService.GetTranslations(c.Request().Context(), uint(id), userID, userRole)
+	if err != nil {
+		return translationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product translations retrieved successfully", translations)
 }
 
-// GetProductsByCategory gets products by category
-// @Summary Get products by category
-// @Description Get products filtered by category
+// SetTranslation creates or replaces a product translation
+// @Summary Set product translation
+// @Description Create or replace a product's name, descriptions and meta fields in a locale such as "fr" or "pt-BR" (seller of the product/admin only)
 // @Tags products
+// @Accept json
 // @Produce json
-// @Param category path string true "Product category"
-// @Param page query int false "Page number" default(1)
-// @Param limit query int false "Items per page" default(10)
-// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Param id path int true "Product ID"
+// @Param locale path string true "Locale code"
+// @Param translation body models.ProductTranslationRequest true "Translated content"
+// @Success 200 {object} utils.Response{data=models.ProductTranslation}
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
-// @Router /products/category/{category} [get]
-func (h *ProductHandler) GetProductsByCategory(c echo.Context) error {
-	category := c.Param("category")
-	if category == "" {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Category is required")
+// @Security BearerAuth
+// @Router /products/{id}/translations/{locale} [put]
+func (h *ProductHandler) SetTranslation(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
+	var req models.ProductTranslationRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
 	}
 
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
 	}
 
-	offset := (page - 1) * limit
+	translation, err := h.productService.SetTranslation(c.Request().Context(), uint(id), c.Param("locale"), &req, userID, userRole)
+	if err != nil {
+		return translationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product translation saved successfully", translation)
+}
+
+// DeleteTranslation removes a product translation
+// @Summary Delete product translation
+// @Description Remove a product's translation in a locale; requests for it fall back to the base content (seller of the product/admin only)
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param locale path string true "Locale code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /products/{id}/translations/{locale} [delete]
+func (h *ProductHandler) DeleteTranslation(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
 
-	products, err := h.productService.GetProductsByCategory(c.Request().Context(), category, limit, offset)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
-	return utils.SuccessResponse(c, "Products by category retrieved successfully", products)
+	if err := h.productService.DeleteTranslation(c.Request().Context(), uint(id), c.Param("locale"), userID, userRole); err != nil {
+		return translationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product translation deleted successfully", nil)
+}
+
+// translationError maps product translation errors to responses
+func translationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ProductNotFound, err.Error())
+	case errors.Is(err, service.ErrTranslationsForbidden):
+		return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.ProductForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidLocale):
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid locale (use a language code such as fr or pt-BR)")
+	}
+	return serviceError(c, err)
+}
+
+// visibleTo checks if the caller, signed in or not, may see the product
+func visibleTo(c echo.Context, product *models.Product) bool {
+	userID, _ := c.Get("user_id").(uint)
+	userRole, _ := c.Get("user_role").(models.UserRole)
+	return product.IsVisibleTo(userID, userRole)
+}
+
+// GetPendingProducts lists products awaiting approval
+// @Summary Get products awaiting approval
+// @Description Get sellers' products waiting to be approved before they're listed, longest waiting first (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/products/pending [get]
+func (h *ProductHandler) GetPendingProducts(c echo.Context) error {
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
+
+	products, total, err := h.productService.GetPendingProducts(c.Request().Context(), limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Products awaiting approval retrieved successfully", products, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// ApproveProduct lists a product awaiting approval
+// @Summary Approve product
+// @Description Approve a product awaiting approval so it's listed; the seller is notified (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response{data=models.Product}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/products/{id}/approve [put]
+func (h *ProductHandler) ApproveProduct(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	product, err := h.productService.ApproveProduct(c.Request().Context(), uint(id), adminID)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product approved successfully", product)
+}
+
+// RejectProduct rejects a product awaiting approval
+// @Summary Reject product
+// @Description Return a product awaiting approval to its seller as a draft; the seller is notified with the reason (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param rejection body models.ProductRejectRequest true "Reason for the seller"
+// @Success 200 {object} utils.Response{data=models.Product}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/products/{id}/reject [put]
+func (h *ProductHandler) RejectProduct(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	var req models.ProductRejectRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	product, err := h.productService.RejectProduct(c.Request().Context(), uint(id), &req, adminID)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product rejected successfully", product)
+}
+
+// GrantPurchaseLimitExemption exempts a customer from a product's purchase limit
+// @Summary Grant a purchase limit exemption
+// @Description Let a customer buy a product past its per-customer purchase limit (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param exemption body models.PurchaseLimitExemptionRequest true "Customer to exempt"
+// @Success 201 {object} utils.Response{data=models.PurchaseLimitExemption}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/products/{id}/purchase-limit-exemptions [post]
+func (h *ProductHandler) GrantPurchaseLimitExemption(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	var req models.PurchaseLimitExemptionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	exemption, err := h.productService.GrantPurchaseLimitExemption(c.Request().Context(), uint(id), &req, adminID)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ProductNotFound, err.Error())
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Purchase limit exemption granted successfully", exemption)
+}
+
+// RevokePurchaseLimitExemption removes a customer's exemption from a product's purchase limit
+// @Summary Revoke a purchase limit exemption
+// @Description Apply the product's per-customer purchase limit to the customer again (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param user_id path int true "Customer ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/products/{id}/purchase-limit-exemptions/{user_id} [delete]
+func (h *ProductHandler) RevokePurchaseLimitExemption(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid user ID")
+	}
+
+	if err := h.productService.RevokePurchaseLimitExemption(c.Request().Context(), uint(id), uint(userID)); err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Purchase limit exemption revoked successfully", nil)
+}
+
+// GetCacheStats reports the product cache's hit and miss counts
+// @Summary Get product cache stats
+// @Description Hit and miss counts of each product cache since this instance started (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.CacheStats}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/cache/stats [get]
+func (h *ProductHandler) GetCacheStats(c echo.Context) error {
+	return utils.SuccessResponse(c, "Cache stats retrieved successfully", h.productService.GetCacheStats())
+}
+
+// GetInventoryAlerts gets a seller's low stock overview
+// @Summary Get inventory alerts
+// @Description Get products at or below their low stock level with recent sales velocity, days of stock remaining and a suggested reorder quantity, most urgent first (seller/admin only)
+// @Tags seller
+// @Produce json
+// @Param seller_id query int false "Seller ID (admin only)"
+// @Success 200 {object} utils.Response{data=models.InventoryAlerts}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /seller/inventory/alerts [get]
+func (h *ProductHandler) GetInventoryAlerts(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	if userRole != models.RoleSeller && userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Access denied")
+	}
+
+	sellerID := userID
+	if userRole == models.RoleAdmin {
+		id, err := strconv.ParseUint(c.QueryParam("seller_id"), 10, 32)
+		if err != nil {
+			return utils.InvalidIDError(c, "Invalid seller ID")
+		}
+		sellerID = uint(id)
+	}
+
+	alerts, err := h.productService.GetInventoryAlerts(c.Request().Context(), sellerID)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Inventory alerts retrieved successfully", alerts)
+}
+
+// GetProductsNeedingAttention lists a seller's listings to fix
+// @Summary Get products needing attention
+// @Description List the seller's products flagged as out of stock, low rated, or missing images, a category, a description or a short description, or left as a draft longer than STALE_DRAFT_DAYS. Each lists its reason codes; products with more pressing issues come first (seller/admin only)
+// @Tags seller
+// @Produce json
+// @Param seller_id query int false "Seller ID (admin only)"
+// @Param reason query string false "Only products flagged for this reason"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.ProductAttention}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /seller/products/attention [get]
+func (h *ProductHandler) GetProductsNeedingAttention(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	if userRole != models.RoleSeller && userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Access denied")
+	}
+
+	sellerID := userID
+	if userRole == models.RoleAdmin {
+		id, err := strconv.ParseUint(c.QueryParam("seller_id"), 10, 32)
+		if err != nil {
+			return utils.InvalidIDError(c, "Invalid seller ID")
+		}
+		sellerID = uint(id)
+	}
+
+	var reason *models.ProductAttentionReason
+	if r := c.QueryParam("reason"); r != "" {
+		attentionReason := models.ProductAttentionReason(r)
+		reason = &attentionReason
+	}
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceProducts)
+
+	offset := (page - 1) * limit
+
+	products, total, err := h.productService.GetProductsNeedingAttention(c.Request().Context(), sellerID, reason, limit, offset)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Products needing attention retrieved successfully", products, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetInventoryValuation gets the cost and retail value of a seller's stock
+// @Summary Get inventory valuation
+// @Description Get total cost and retail value of stock by category (seller/admin only). Products without a cost price are excluded from cost value and counted separately.
+// @Tags seller
+// @Produce json
+// @Param seller_id query int false "Seller ID (admin only)"
+// @Success 200 {object} utils.Response{data=models.InventoryValuation}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /seller/analytics/inventory-valuation [get]
+func (h *ProductHandler) GetInventoryValuation(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	if userRole != models.RoleSeller && userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Access denied")
+	}
+
+	sellerID := userID
+	if userRole == models.RoleAdmin {
+		id, err := strconv.ParseUint(c.QueryParam("seller_id"), 10, 32)
+		if err != nil {
+			return utils.InvalidIDError(c, "Invalid seller ID")
+		}
+		sellerID = uint(id)
+	}
+
+	valuation, err := h.productService.GetInventoryValuation(c.Request().Context(), sellerID)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Inventory valuation retrieved successfully", valuation)
+}
+
+// GetTopRatedProducts gets top rated products
+// @Summary Get top rated products
+// @Description Get products with highest ratings. Products below the configured rating gate are excluded
+// @Tags products
+// @Produce json
+// @Param limit query int false "Number of products to return" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/top-rated [get]
+func (h *ProductHandler) GetTopRatedProducts(c echo.Context) error {
+	limit := utils.LimitParamFor(c, utils.PageResourceProducts)
+
+	products, err := h.productService.GetTopRatedProducts(c.Request().Context(), limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	h.localize(c, products...)
+
+	return utils.SuccessResponse(c, "Top rated products retrieved successfully", products)
+}
+
+// GetFeaturedProducts gets featured products
+// @Summary Get featured products
+// @Description Get active featured products, best rated first. Products below the configured rating gate are excluded
+// @Tags products
+// @Produce json
+// @Param limit query int false "Number of products to return" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/featured [get]
+func (h *ProductHandler) GetFeaturedProducts(c echo.Context) error {
+	limit := utils.LimitParamFor(c, utils.PageResourceProducts)
+
+	products, err := h.productService.GetFeaturedProducts(c.Request().Context(), limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	h.localize(c, products...)
+
+	return utils.SuccessResponse(c, "Featured products retrieved successfully", products)
+}
+
+// GetNewArrivals gets recently added products
+// @Summary Get new arrivals
+// @Description Get active, published products added within the last days days, newest first. Out-of-stock products are excluded unless configured otherwise
+// @Tags products
+// @Produce json
+// @Param days query int false "Window in days (defaults to NEW_ARRIVALS_DAYS, max 365)"
+// @Param category query string false "Category"
+// @Param limit query int false "Number of products to return" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/new-arrivals [get]
+func (h *ProductHandler) GetNewArrivals(c echo.Context) error {
+	days := 0
+	if daysStr := c.QueryParam("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed <= 0 {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid days")
+		}
+		days = parsed
+	}
+
+	limit := utils.LimitParamFor(c, utils.PageResourceProducts)
+
+	products, err := h.productService.GetNewArrivals(c.Request().Context(), days, c.QueryParam("category"), limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	h.localize(c, products...)
+
+	return utils.SuccessResponse(c, "New arrivals retrieved successfully", products)
+}
+
+// GetRelatedProducts gets products related to a product
+// @Summary Get related products
+// @Description Get other active products from the same category, best rated first. Products below the configured rating gate are excluded
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param limit query int false "Number of products to return" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/related [get]
+func (h *ProductHandler) GetRelatedProducts(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	limit := utils.LimitParamFor(c, utils.PageResourceProducts)
+
+	products, err := h.productService.GetRelatedProducts(c.Request().Context(), uint(id), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ProductNotFound, err.Error())
+		}
+		return serviceError(c, err)
+	}
+
+	h.localize(c, products...)
+
+	return utils.SuccessResponse(c, "Related products retrieved successfully", products)
+}
+
+// SearchProducts searches for products
+// @Summary Search products
+// @Description Search products by name and description
+// @Tags products
+// @Produce json
+// @Param q query string true "Search query"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/search [get]
+func (h *ProductHandler) SearchProducts(c echo.Context) error {
+	query := c.QueryParam("q")
+	if query == "" {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Search query is required")
+	}
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceProducts)
+
+	offset := (page - 1) * limit
+
+	products, err := h.productService.SearchProducts(c.Request().Context(), query, limit, offset)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	// Log the query once per search, not for every page
+	if page == 1 {
+		var userID *uint
+		if id, ok := c.Get("user_id").(uint); ok {
+			userID = &id
+		}
+		h.searchService.RecordSearch(c.Request().Context(), query, len(products), userID)
+	}
+
+	h.localize(c, products...)
+
+	return utils.SuccessResponse(c, "Search results retrieved successfully", products)
+}
+
+// GetSearchSuggestions returns type-ahead suggestions for a search prefix
+// @Summary Get search suggestions
+// @Description Get product name completions and popular search terms matching a prefix
+// @Tags products
+// @Produce json
+// @Param q query string true "Search prefix"
+// @Param limit query int false "Maximum suggestions per list" default(10)
+// @Success 200 {object} utils.Response{data=models.SearchSuggestionsResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/search/suggestions [get]
+func (h *ProductHandler) GetSearchSuggestions(c echo.Context) error {
+	query := c.QueryParam("q")
+	if query == "" {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Search query is required")
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	suggestions, err := h.searchService.GetSuggestions(c.Request().Context(), query, limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Search suggestions retrieved successfully", suggestions)
+}
+
+// GetProductsByCategory gets products by category
+// @Summary Get products by category
+// @Description Get products filtered by category
+// @Tags products
+// @Produce json
+// @Param category path string true "Product category"
+// @Param sort_by query string false "Sort (newest, best_selling, price_asc, price_desc, rating, popular, name); defaults to the category's sort, then the store's"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Product}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/category/{category} [get]
+func (h *ProductHandler) GetProductsByCategory(c echo.Context) error {
+	category := c.Param("category")
+	if category == "" {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Category is required")
+	}
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceProducts)
+
+	offset := (page - 1) * limit
+
+	sortBy, err := productSortParam(c)
+	if err != nil {
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+
+	products, err := h.productService.GetProductsByCategory(c.Request().Context(), category, sortBy, limit, offset)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	h.localize(c, products...)
+
+	return utils.SuccessResponse(c, "Products by category retrieved successfully", products)
+}
+
+// productSortParam reads the sort_by query param; empty leaves the choice to
+// the category's or the store's default
+func productSortParam(c echo.Context) (models.ProductSort, error) {
+	sortBy := models.ProductSort(c.QueryParam("sort_by"))
+	if sortBy != "" && !sortBy.IsValid() {
+		return "", service.ErrInvalidProductSort
+	}
+	return sortBy, nil
+}
+
+// localize translates the products' content into the request's preferred language
+func (h *ProductHandler) localize(c echo.Context, products ...*models.Product) {
+	h.productService.LocalizeProducts(c.Request().Context(), contentLocales(c), products...)
+}
+
+// contentLocales returns the languages the client wants content in, most
+// preferred first: the lang query param, then the Accept-Language header by
+// quality. Unrecognized codes are skipped.
+func contentLocales(c echo.Context) []string {
+	var locales []string
+	if lang := c.QueryParam("lang"); lang != "" {
+		if locale, ok := models.NormalizeLocale(lang); ok {
+			locales = append(locales, locale)
+		}
+	}
+
+	type weightedLocale struct {
+		locale  string
+		quality float64
+	}
+	var accepted []weightedLocale
+	for _, part := range strings.Split(c.Request().Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale, ok := models.NormalizeLocale(tag)
+		if !ok {
+			continue
+		}
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality > 0 {
+			accepted = append(accepted, weightedLocale{locale: locale, quality: quality})
+		}
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+	for _, a := range accepted {
+		locales = append(locales, a.locale)
+	}
+
+	return locales
+}
+
+// getLocale returns the locale suggestions set by the geo middleware
+func getLocale(c echo.Context) *models.LocaleInfo {
+	if info, ok := c.Get("locale").(*models.LocaleInfo); ok {
+		return info
+	}
+	return &models.LocaleInfo{
+		Country:   geo.DefaultCountry,
+		Currency:  geo.DefaultCurrency,
+		Locale:    geo.DefaultLocale,
+		TaxRegion: geo.DefaultCountry,
+	}
+}
+
+// isBackorderLimitError reports whether err is a backorder limit validation error
+func isBackorderLimitError(err error) bool {
+	return errors.Is(err, service.ErrNegativeBackorderLimit) ||
+		errors.Is(err, service.ErrBackordersDisabled)
+}
+
+// isReturnPolicyError reports whether err is a return window validation error
+func isReturnPolicyError(err error) bool {
+	return errors.Is(err, service.ErrNegativeReturnWindow) ||
+		errors.Is(err, service.ErrReturnWindowNotReturnable)
 }
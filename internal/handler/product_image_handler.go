@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
@@ -23,7 +25,7 @@ func NewProductImageHandler(productImageService service.ProductImageService) *Pr
 
 // AddProductImage adds a new image to a product
 // @Summary Add product image
-// @Description Add a new image to a product
+// @Description Add a new image to a product. Without alt_text, one is generated from the product name and image position; meta.alt_text_warnings lists images without meaningful alt text
 // @Tags product-images
 // @Accept json
 // @Produce json
@@ -39,7 +41,7 @@ func NewProductImageHandler(productImageService service.ProductImageService) *Pr
 func (h *ProductImageHandler) AddProductImage(c echo.Context) error {
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	var req models.ProductImageRequest
@@ -48,18 +50,21 @@ func (h *ProductImageHandler) AddProductImage(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
 	image, err := h.productImageService.AddProductImage(c.Request().Context(), uint(productID), &req)
 	if err != nil {
-		if err.Error() == "product not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrProductNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, service.ErrInvalidImageURL) || errors.Is(err, service.ErrImageLimitReached) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
 	}
 
-	return utils.CreatedResponse(c, "Product image added successfully", image)
+	return imagesResponse(c, http.StatusCreated, "Product image added successfully", image, []models.ProductImage{*image})
 }
 
 // GetProductImages gets all images for a product
@@ -76,15 +81,15 @@ func (h *ProductImageHandler) AddProductImage(c echo.Context) error {
 func (h *ProductImageHandler) GetProductImages(c echo.Context) error {
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	images, err := h.productImageService.GetProductImages(c.Request().Context(), uint(productID))
 	if err != nil {
-		if err.Error() == "product not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrProductNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product images retrieved successfully", images)
@@ -105,15 +110,15 @@ func (h *ProductImageHandler) GetProductImages(c echo.Context) error {
 func (h *ProductImageHandler) GetProductImage(c echo.Context) error {
 	imageID, err := strconv.ParseUint(c.Param("image_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid image ID")
+		return utils.InvalidIDError(c, "Invalid image ID")
 	}
 
 	image, err := h.productImageService.GetProductImage(c.Request().Context(), uint(imageID))
 	if err != nil {
-		if err.Error() == "product image not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrProductImageNotFound) {
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ImageNotFound, err.Error())
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product image retrieved successfully", image)
@@ -138,7 +143,7 @@ func (h *ProductImageHandler) GetProductImage(c echo.Context) error {
 func (h *ProductImageHandler) UpdateProductImage(c echo.Context) error {
 	imageID, err := strconv.ParseUint(c.Param("image_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid image ID")
+		return utils.InvalidIDError(c, "Invalid image ID")
 	}
 
 	var req models.ProductImageRequest
@@ -147,23 +152,23 @@ func (h *ProductImageHandler) UpdateProductImage(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
 	image, err := h.productImageService.UpdateProductImage(c.Request().Context(), uint(imageID), &req)
 	if err != nil {
-		if err.Error() == "product image not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrProductImageNotFound) {
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ImageNotFound, err.Error())
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
-	return utils.SuccessResponse(c, "Product image updated successfully", image)
+	return imagesResponse(c, http.StatusOK, "Product image updated successfully", image, []models.ProductImage{*image})
 }
 
 // DeleteProductImage deletes a product image
 // @Summary Delete product image
-// @Description Delete a product image
+// @Description Delete a product image. If it was the primary, another image is promoted; the resulting primary image is returned
 // @Tags product-images
 // @Produce json
 // @Param product_id path int true "Product ID"
@@ -178,18 +183,20 @@ func (h *ProductImageHandler) UpdateProductImage(c echo.Context) error {
 func (h *ProductImageHandler) DeleteProductImage(c echo.Context) error {
 	imageID, err := strconv.ParseUint(c.Param("image_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid image ID")
+		return utils.InvalidIDError(c, "Invalid image ID")
 	}
 
-	err = h.productImageService.DeleteProductImage(c.Request().Context(), uint(imageID))
+	primary, err := h.productImageService.DeleteProductImage(c.Request().Context(), uint(imageID))
 	if err != nil {
-		if err.Error() == "product image not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrProductImageNotFound) {
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ImageNotFound, err.Error())
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
-	return utils.SuccessResponse(c, "Product image deleted successfully", nil)
+	return utils.SuccessResponse(c, "Product image deleted successfully", map[string]interface{}{
+		"primary_image": primary,
+	})
 }
 
 // SetPrimaryImage sets an image as the primary image for a product
@@ -209,23 +216,23 @@ func (h *ProductImageHandler) DeleteProductImage(c echo.Context) error {
 func (h *ProductImageHandler) SetPrimaryImage(c echo.Context) error {
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	imageID, err := strconv.ParseUint(c.Param("image_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid image ID")
+		return utils.InvalidIDError(c, "Invalid image ID")
 	}
 
 	err = h.productImageService.SetPrimaryImage(c.Request().Context(), uint(productID), uint(imageID))
 	if err != nil {
-		if err.Error() == "product not found" || err.Error() == "image not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrProductNotFound) || errors.Is(err, service.ErrImageNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
 		}
-		if err.Error() == "image does not belong to the specified product" {
-			return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		if errors.Is(err, service.ErrImageNotOnProduct) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Primary image set successfully", nil)
@@ -245,15 +252,18 @@ func (h *ProductImageHandler) SetPrimaryImage(c echo.Context) error {
 func (h *ProductImageHandler) GetPrimaryImage(c echo.Context) error {
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	image, err := h.productImageService.GetPrimaryImage(c.Request().Context(), uint(productID))
 	if err != nil {
-		if err.Error() == "product not found" || err.Error() == "primary image not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrPrimaryImageNotFound) {
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ImageNotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrProductNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Primary image retrieved successfully", image)
@@ -278,12 +288,12 @@ func (h *ProductImageHandler) GetPrimaryImage(c echo.Context) error {
 func (h *ProductImageHandler) UpdateImageOrder(c echo.Context) error {
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	imageID, err := strconv.ParseUint(c.Param("image_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid image ID")
+		return utils.InvalidIDError(c, "Invalid image ID")
 	}
 
 	var req map[string]int
@@ -298,13 +308,13 @@ func (h *ProductImageHandler) UpdateImageOrder(c echo.Context) error {
 
 	err = h.productImageService.UpdateImageOrder(c.Request().Context(), uint(productID), uint(imageID), sortOrder)
 	if err != nil {
-		if err.Error() == "product not found" || err.Error() == "image not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrProductNotFound) || errors.Is(err, service.ErrImageNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
 		}
-		if err.Error() == "image does not belong to the specified product" {
-			return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		if errors.Is(err, service.ErrImageNotOnProduct) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Image order updated successfully", nil)
@@ -312,7 +322,7 @@ func (h *ProductImageHandler) UpdateImageOrder(c echo.Context) error {
 
 // BulkAddImages adds multiple images to a product
 // @Summary Bulk add images
-// @Description Add multiple images to a product
+// @Description Add multiple images to a product. Images without alt_text get one generated from the product name and image position; meta.alt_text_warnings lists images without meaningful alt text
 // @Tags product-images
 // @Accept json
 // @Produce json
@@ -328,7 +338,7 @@ func (h *ProductImageHandler) UpdateImageOrder(c echo.Context) error {
 func (h *ProductImageHandler) BulkAddImages(c echo.Context) error {
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	var req []models.ProductImageRequest
@@ -349,13 +359,16 @@ func (h *ProductImageHandler) BulkAddImages(c echo.Context) error {
 
 	images, err := h.productImageService.BulkAddImages(c.Request().Context(), uint(productID), req)
 	if err != nil {
-		if err.Error() == "product not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrProductNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, service.ErrInvalidImageURL) || errors.Is(err, service.ErrImageLimitReached) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
 	}
 
-	return utils.CreatedResponse(c, "Images added successfully", images)
+	return imagesResponse(c, http.StatusCreated, "Images added successfully", images, images)
 }
 
 // ReplaceProductImages replaces all images for a product
@@ -376,7 +389,7 @@ func (h *ProductImageHandler) BulkAddImages(c echo.Context) error {
 func (h *ProductImageHandler) ReplaceProductImages(c echo.Context) error {
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	var req []models.ProductImageRequest
@@ -393,11 +406,80 @@ func (h *ProductImageHandler) ReplaceProductImages(c echo.Context) error {
 
 	images, err := h.productImageService.ReplaceProductImages(c.Request().Context(), uint(productID), req)
 	if err != nil {
-		if err.Error() == "product not found" {
-			return utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrProductNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		if errors.Is(err, service.ErrInvalidImageURL) || errors.Is(err, service.ErrImageLimitReached) {
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return imagesResponse(c, http.StatusOK, "Product images replaced successfully", images, images)
+}
+
+// GetAccessibilityReport lists the seller's products that need alt text work
+// @Summary Get product accessibility report
+// @Description List the seller's products that have no images, or images whose alt text is missing, was filled in automatically, or is a placeholder such as "image" or a file name (seller/admin only)
+// @Tags seller
+// @Produce json
+// @Param seller_id query int false "Seller ID (admin only)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=[]models.ProductAccessibility}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /seller/products/accessibility [get]
+func (h *ProductImageHandler) GetAccessibilityReport(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	if userRole != models.RoleSeller && userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Access denied")
+	}
+
+	sellerID := userID
+	if userRole == models.RoleAdmin {
+		id, err := strconv.ParseUint(c.QueryParam("seller_id"), 10, 32)
+		if err != nil {
+			return utils.InvalidIDError(c, "Invalid seller ID")
+		}
+		sellerID = uint(id)
+	}
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceProducts)
+
+	offset := (page - 1) * limit
+
+	products, total, err := h.productImageService.GetAccessibilityReport(c.Request().Context(), sellerID, limit, offset)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Accessibility report retrieved successfully", products, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// imagesResponse sends data, adding an alt_text_warnings entry to the
+// metadata for any of the written images without meaningful alt text
+func imagesResponse(c echo.Context, status int, message string, data interface{}, images []models.ProductImage) error {
+	warnings := models.AltTextWarnings(images)
+	if len(warnings) == 0 {
+		if status == http.StatusCreated {
+			return utils.CreatedResponse(c, message, data)
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return utils.SuccessResponse(c, message, data)
 	}
 
-	return utils.SuccessResponse(c, "Product images replaced successfully", images)
+	meta := map[string]interface{}{"alt_text_warnings": warnings}
+	if status == http.StatusCreated {
+		return utils.CreatedResponseWithMeta(c, message, data, meta)
+	}
+	return utils.SuccessResponseWithMeta(c, message, data, meta)
 }
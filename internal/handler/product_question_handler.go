@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type ProductQuestionHandler struct {
+	questionService service.ProductQuestionService
+}
+
+func NewProductQuestionHandler(questionService service.ProductQuestionService) *ProductQuestionHandler {
+	return &ProductQuestionHandler{questionService: questionService}
+}
+
+// GetProductQuestions retrieves the questions asked about a product
+// @Summary Get product questions
+// @Description Get a product's questions, newest first, with answers. Seller and staff answers are badged and listed first.
+// @Tags questions
+// @Produce json
+// @Param product_id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.ProductQuestionResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{product_id}/questions [get]
+func (h *ProductQuestionHandler) GetProductQuestions(c echo.Context) error {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceReviews)
+
+	questions, total, err := h.questionService.GetProductQuestions(c.Request().Context(), uint(productID), limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Questions retrieved successfully", questions, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// AskQuestion asks a question about a product
+// @Summary Ask a product question
+// @Description Ask a question about a product
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param product_id path int true "Product ID"
+// @Param question body models.AskQuestionRequest true "Question"
+// @Success 201 {object} utils.Response{data=models.ProductQuestionResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /products/{product_id}/questions [post]
+func (h *ProductQuestionHandler) AskQuestion(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	var req models.AskQuestionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	question, err := h.questionService.AskQuestion(c.Request().Context(), uint(productID), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Question posted successfully", question)
+}
+
+// AnswerQuestion answers a product question
+// @Summary Answer a product question
+// @Description Answer a product question. Answers from the product's seller get a "Seller" badge and admin answers a "Staff" badge; the asker is notified.
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param id path int true "Question ID"
+// @Param answer body models.AnswerQuestionRequest true "Answer"
+// @Success 201 {object} utils.Response{data=models.ProductAnswerResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /questions/{id}/answers [post]
+func (h *ProductQuestionHandler) AnswerQuestion(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid question ID")
+	}
+
+	var req models.AnswerQuestionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	answer, err := h.questionService.AnswerQuestion(c.Request().Context(), uint(questionID), userID, userRole, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrQuestionNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Answer posted successfully", answer)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type PromotionHandler struct {
+	promotionService service.PromotionService
+}
+
+func NewPromotionHandler(promotionService service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{promotionService: promotionService}
+}
+
+// GetPromotions lists promotions
+// @Summary List promotions
+// @Description List all order-level promotions, including inactive and expired ones (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=[]models.Promotion}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions [get]
+func (h *PromotionHandler) GetPromotions(c echo.Context) error {
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
+
+	promotions, total, err := h.promotionService.GetPromotions(c.Request().Context(), limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Promotions retrieved successfully", promotions, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetPromotion retrieves a promotion
+// @Summary Get a promotion
+// @Description Get a promotion by ID (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} utils.Response{data=models.Promotion}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions/{id} [get]
+func (h *PromotionHandler) GetPromotion(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid promotion ID")
+	}
+
+	promotion, err := h.promotionService.GetPromotion(c.Request().Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrPromotionNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Promotion retrieved successfully", promotion)
+}
+
+// CreatePromotion creates a promotion
+// @Summary Create a promotion
+// @Description Create an order-level promotion: category_percent, bogo or spend_and_save. The running promotion giving the largest discount is applied at checkout (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param promotion body models.PromotionRequest true "Promotion"
+// @Success 201 {object} utils.Response{data=models.Promotion}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions [post]
+func (h *PromotionHandler) CreatePromotion(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	var req models.PromotionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	promotion, err := h.promotionService.CreatePromotion(c.Request().Context(), &req, adminID)
+	if err != nil {
+		return promotionError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Promotion created successfully", promotion)
+}
+
+// UpdatePromotion replaces a promotion's rule and window
+// @Summary Update a promotion
+// @Description Replace a promotion's rule and active window (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param promotion body models.PromotionRequest true "Promotion"
+// @Success 200 {object} utils.Response{data=models.Promotion}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid promotion ID")
+	}
+
+	var req models.PromotionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	promotion, err := h.promotionService.UpdatePromotion(c.Request().Context(), uint(id), &req)
+	if err != nil {
+		return promotionError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Promotion updated successfully", promotion)
+}
+
+// DeletePromotion deletes a promotion
+// @Summary Delete a promotion
+// @Description Delete a promotion; orders it was applied to keep its name (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions/{id} [delete]
+func (h *PromotionHandler) DeletePromotion(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid promotion ID")
+	}
+
+	if err := h.promotionService.DeletePromotion(c.Request().Context(), uint(id)); err != nil {
+		if errors.Is(err, service.ErrPromotionNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Promotion deleted successfully", nil)
+}
+
+// promotionError maps promotion create/update errors to responses
+func promotionError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrPromotionNotFound),
+		errors.Is(err, service.ErrCategoryNotFound),
+		errors.Is(err, service.ErrProductNotFound):
+		return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+	case errors.Is(err, service.ErrPromotionCategoryRequired),
+		errors.Is(err, service.ErrDiscountPercentRequired),
+		errors.Is(err, service.ErrBuyGetQuantitiesRequired),
+		errors.Is(err, service.ErrMinSpendDiscountRequired),
+		errors.Is(err, service.ErrDiscountExceedsMinSpend),
+		errors.Is(err, service.ErrPromotionEndsBeforeStart):
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+	return serviceError(c, err)
+}
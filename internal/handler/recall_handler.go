@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type RecallHandler struct {
+	recallService service.RecallService
+}
+
+func NewRecallHandler(recallService service.RecallService) *RecallHandler {
+	return &RecallHandler{recallService: recallService}
+}
+
+// RecallProduct notifies every customer who bought a product
+// @Summary Recall a product
+// @Description Send a notification and email to every customer with the product in a paid order (admin/seller of the product)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param recall body models.ProductRecallRequest true "Recall message"
+// @Success 200 {object} utils.Response{data=models.ProductRecall}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/products/{id}/recall [post]
+func (h *RecallHandler) RecallProduct(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	userRole := c.Get("user_role").(models.UserRole)
+
+	if userRole != models.RoleSeller && userRole != models.RoleAdmin {
+		return utils.ErrorResponse(c, http.StatusForbidden, "Access denied")
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	var req models.ProductRecallRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	recall, err := h.recallService.RecallProduct(c.Request().Context(), uint(productID), &req, userID, userRole)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		case errors.Is(err, service.ErrRecallForbidden):
+			return utils.ErrorResponseFromError(c, http.StatusForbidden, err)
+		}
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product recall sent successfully", recall)
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type ReturnHandler struct {
+	returnService service.ReturnService
+}
+
+func NewReturnHandler(returnService service.ReturnService) *ReturnHandler {
+	return &ReturnHandler{returnService: returnService}
+}
+
+// RequestReturn opens a return for an order item
+// @Summary Request a return
+// @Description Ask to return units of a delivered item while its product's return window is open (Owner)
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param return body models.ReturnCreateRequest true "Item, quantity and reason"
+// @Success 201 {object} utils.Response{data=models.ReturnRequest}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /orders/{id}/returns [post]
+func (h *ReturnHandler) RequestReturn(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid order ID")
+	}
+
+	var req models.ReturnCreateRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	returnRequest, err := h.returnService.RequestReturn(c.Request().Context(), uint(orderID), userID, &req)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Return requested successfully", returnRequest)
+}
+
+// GetMyReturns retrieves the current user's return requests
+// @Summary Get my returns
+// @Description Get your return requests, newest first
+// @Tags orders
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.ReturnRequest}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /returns/my [get]
+func (h *ReturnHandler) GetMyReturns(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceOrders)
+
+	returnRequests, total, err := h.returnService.GetMyReturns(c.Request().Context(), userID, limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Return requests retrieved successfully", returnRequests, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetReturns retrieves return requests for review
+// @Summary Get returns
+// @Description Get return requests, oldest first (admin only)
+// @Tags admin
+// @Produce json
+// @Param status query string false "Return status"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.ReturnRequest}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/returns [get]
+func (h *ReturnHandler) GetReturns(c echo.Context) error {
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
+
+	var status *models.ReturnStatus
+	if s := c.QueryParam("status"); s != "" {
+		returnStatus := models.ReturnStatus(s)
+		status = &returnStatus
+	}
+
+	returnRequests, total, err := h.returnService.GetReturns(c.Request().Context(), status, limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Return requests retrieved successfully", returnRequests, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// ApproveReturn approves a return request and refunds it
+// @Summary Approve return
+// @Description Approve a return and refund the returned units' share of the order (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Return request ID"
+// @Param resolution body models.ReturnResolveRequest false "Note for the customer"
+// @Success 200 {object} utils.Response{data=models.ReturnRequest}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/returns/{id}/approve [put]
+func (h *ReturnHandler) ApproveReturn(c echo.Context) error {
+	return h.resolveReturn(c, h.returnService.ApproveReturn, "Return approved and refunded successfully")
+}
+
+// RejectReturn rejects a return request
+// @Summary Reject return
+// @Description Reject a return request without a refund (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Return request ID"
+// @Param resolution body models.ReturnResolveRequest false "Reason for the customer"
+// @Success 200 {object} utils.Response{data=models.ReturnRequest}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/returns/{id}/reject [put]
+func (h *ReturnHandler) RejectReturn(c echo.Context) error {
+	return h.resolveReturn(c, h.returnService.RejectReturn, "Return rejected successfully")
+}
+
+type resolveReturnFunc func(ctx context.Context, id, adminID uint, req *models.ReturnResolveRequest) (*models.ReturnRequest, error)
+
+func (h *ReturnHandler) resolveReturn(c echo.Context, resolve resolveReturnFunc, message string) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid return request ID")
+	}
+
+	var req models.ReturnResolveRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	returnRequest, err := resolve(c.Request().Context(), uint(id), adminID, &req)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, message, returnRequest)
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
@@ -49,11 +52,11 @@ func (h *ReviewHandler) CreateReview(c echo.Context) error {
 
 	review, err := h.reviewService.CreateReview(c.Request().Context(), &req, userID)
 	if err != nil {
-		if err.Error() == "you can only review products you have purchased and received" ||
-			err.Error() == "you have already reviewed this product" {
-			return utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		if errors.Is(err, service.ErrReviewNotPurchased) ||
+			errors.Is(err, service.ErrAlreadyReviewed) {
+			return utils.ErrorResponseFromError(c, http.StatusForbidden, err)
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Review created successfully", review)
@@ -72,12 +75,12 @@ func (h *ReviewHandler) CreateReview(c echo.Context) error {
 func (h *ReviewHandler) GetReview(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid review ID")
+		return utils.InvalidIDError(c, "Invalid review ID")
 	}
 
 	review, err := h.reviewService.GetReview(c.Request().Context(), uint(id))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusNotFound, "Review not found")
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.ReviewNotFound, "Review not found")
 	}
 
 	return utils.SuccessResponse(c, "Review retrieved successfully", review)
@@ -85,10 +88,11 @@ func (h *ReviewHandler) GetReview(c echo.Context) error {
 
 // GetProductReviews retrieves reviews for a product
 // @Summary Get product reviews
-// @Description Get reviews for a specific product
+// @Description Get reviews for a specific product. most_helpful ranks reviews with a few votes by their helpful votes, ahead of reviews few have voted on yet
 // @Tags reviews
 // @Produce json
 // @Param product_id path int true "Product ID"
+// @Param sort_by query string false "Sort order (newest, oldest, rating_high, rating_low, most_helpful)" default(newest)
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} utils.Response{data=[]models.Review}
@@ -98,24 +102,24 @@ func (h *ReviewHandler) GetReview(c echo.Context) error {
 func (h *ReviewHandler) GetProductReviews(c echo.Context) error {
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
+	sortBy := models.ReviewSort(c.QueryParam("sort_by"))
+	if sortBy == "" {
+		sortBy = models.ReviewSortNewest
 	}
-
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
+	if !sortBy.IsValid() {
+		return serviceError(c, service.ErrInvalidReviewSort)
 	}
 
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceReviews)
+
 	offset := (page - 1) * limit
 
-	reviews, err := h.reviewService.GetProductReviews(c.Request().Context(), uint(productID), limit, offset)
+	reviews, err := h.reviewService.GetProductReviews(c.Request().Context(), uint(productID), sortBy, limit, offset)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product reviews retrieved successfully", reviews)
@@ -137,24 +141,85 @@ func (h *ReviewHandler) GetProductReviews(c echo.Context) error {
 func (h *ReviewHandler) GetUserReviews(c echo.Context) error {
 	userID := c.Get("user_id").(uint)
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceReviews)
+
+	offset := (page - 1) * limit
+
+	reviews, err := h.reviewService.GetUserReviews(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return serviceError(c, err)
 	}
 
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
+	return utils.SuccessResponse(c, "User reviews retrieved successfully", reviews)
+}
+
+// GetSellerReviews retrieves the reviews of the seller's products
+// @Summary Get seller reviews
+// @Description Get the approved reviews of all your products, newest first, each marked when it still awaits your response (seller only)
+// @Tags reviews
+// @Produce json
+// @Param rating query int false "Star rating (1-5)"
+// @Param has_response query bool false "Only reviews you have (true) or haven't (false) responded to"
+// @Param start_date query string false "Written on or after (YYYY-MM-DD)"
+// @Param end_date query string false "Written on or before (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]models.SellerReview}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /seller/reviews [get]
+func (h *ReviewHandler) GetSellerReviews(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	filter := &models.SellerReviewFilter{}
+	if ratingStr := c.QueryParam("rating"); ratingStr != "" {
+		rating, err := strconv.Atoi(ratingStr)
+		if err != nil || rating < 1 || rating > 5 {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid rating (use 1 to 5)")
+		}
+		filter.Rating = &rating
+	}
+	if hasResponseStr := c.QueryParam("has_response"); hasResponseStr != "" {
+		hasResponse, err := strconv.ParseBool(hasResponseStr)
+		if err != nil {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid has_response value (use true or false)")
+		}
+		filter.HasResponse = &hasResponse
+	}
+	if startDateStr := c.QueryParam("start_date"); startDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date format (use YYYY-MM-DD)")
+		}
+		filter.DateFrom = &parsed
+	}
+	if endDateStr := c.QueryParam("end_date"); endDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date format (use YYYY-MM-DD)")
+		}
+		// Include the whole end day
+		dateTo := parsed.AddDate(0, 0, 1)
+		filter.DateTo = &dateTo
 	}
 
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceReviews)
+
 	offset := (page - 1) * limit
 
-	reviews, err := h.reviewService.GetUserReviews(c.Request().Context(), userID, limit, offset)
+	reviews, total, err := h.reviewService.GetSellerReviews(c.Request().Context(), userID, filter, limit, offset)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
-	return utils.SuccessResponse(c, "User reviews retrieved successfully", reviews)
+	return utils.SuccessResponseWithMeta(c, "Seller reviews retrieved successfully", reviews, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
 }
 
 // UpdateReview updates an existing review
@@ -178,7 +243,7 @@ func (h *ReviewHandler) UpdateReview(c echo.Context) error {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid review ID")
+		return utils.InvalidIDError(c, "Invalid review ID")
 	}
 
 	var req models.UpdateReviewRequest
@@ -188,10 +253,10 @@ func (h *ReviewHandler) UpdateReview(c echo.Context) error {
 
 	review, err := h.reviewService.UpdateReview(c.Request().Context(), uint(id), &req, userID)
 	if err != nil {
-		if err.Error() == "unauthorized to update this review" {
-			return utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		if errors.Is(err, service.ErrReviewUpdateForbidden) {
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.ReviewForbidden, err.Error())
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Review updated successfully", review)
@@ -217,15 +282,15 @@ func (h *ReviewHandler) DeleteReview(c echo.Context) error {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid review ID")
+		return utils.InvalidIDError(c, "Invalid review ID")
 	}
 
 	err = h.reviewService.DeleteReview(c.Request().Context(), uint(id), userID, userRole)
 	if err != nil {
-		if err.Error() == "unauthorized to delete this review" {
-			return utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		if errors.Is(err, service.ErrReviewDeleteForbidden) {
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.ReviewForbidden, err.Error())
 		}
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Review deleted successfully", nil)
@@ -249,21 +314,13 @@ func (h *ReviewHandler) GetReviewsByRating(c echo.Context) error {
 		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid rating (must be 1-5)")
 	}
 
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	if page <= 0 {
-		page = 1
-	}
-
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceReviews)
 
 	offset := (page - 1) * limit
 
 	reviews, err := h.reviewService.GetReviewsByRating(c.Request().Context(), rating, limit, offset)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Reviews by rating retrieved successfully", reviews)
@@ -280,14 +337,11 @@ func (h *ReviewHandler) GetReviewsByRating(c echo.Context) error {
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /reviews/top [get]
 func (h *ReviewHandler) GetTopReviews(c echo.Context) error {
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
+	limit := utils.LimitParamFor(c, utils.PageResourceReviews)
 
 	reviews, err := h.reviewService.GetTopReviews(c.Request().Context(), limit)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Top reviews retrieved successfully", reviews)
@@ -304,14 +358,11 @@ func (h *ReviewHandler) GetTopReviews(c echo.Context) error {
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /reviews/recent [get]
 func (h *ReviewHandler) GetRecentReviews(c echo.Context) error {
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
+	limit := utils.LimitParamFor(c, utils.PageResourceReviews)
 
 	reviews, err := h.reviewService.GetRecentReviews(c.Request().Context(), limit)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Recent reviews retrieved successfully", reviews)
@@ -330,17 +381,41 @@ func (h *ReviewHandler) GetRecentReviews(c echo.Context) error {
 func (h *ReviewHandler) GetProductReviewStats(c echo.Context) error {
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	stats, err := h.reviewService.GetProductReviewStats(c.Request().Context(), uint(productID))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product review stats retrieved successfully", stats)
 }
 
+// GetReviewSummary retrieves the review summary for a product
+// @Summary Get product review summary
+// @Description Get most-mentioned keywords, pros/cons and the positive/negative ratio for a product's reviews
+// @Tags reviews
+// @Produce json
+// @Param product_id path int true "Product ID"
+// @Success 200 {object} utils.Response{data=models.ReviewSummary}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{product_id}/reviews/summary [get]
+func (h *ReviewHandler) GetReviewSummary(c echo.Context) error {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid product ID")
+	}
+
+	summary, err := h.reviewService.GetReviewSummary(c.Request().Context(), uint(productID))
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product review summary retrieved successfully", summary)
+}
+
 // CanUserReview checks if user can review a product
 // @Summary Check if user can review
 // @Description Check if authenticated user can review a specific product
@@ -358,12 +433,12 @@ func (h *ReviewHandler) CanUserReview(c echo.Context) error {
 
 	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	canReview, err := h.reviewService.CanUserReview(c.Request().Context(), userID, uint(productID))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Review eligibility checked successfully", map[string]bool{
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 	"github.com/JonathanVera18/ecommerce-api/internal/middleware"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	
@@ -9,22 +10,34 @@ import (
 
 // Handlers contains all the handlers
 type Handlers struct {
-	Auth         *AuthHandler
-	User         *UserHandler
-	Product      *ProductHandler
-	Order        *OrderHandler
-	Review       *ReviewHandler
-	Admin        *AdminHandler
-	Category     *CategoryHandler
-	Wishlist     *WishlistHandler
-	Cart         *CartHandler
-	Notification *NotificationHandler
-	FileUpload   *FileUploadHandler
-	ProductImage *ProductImageHandler
+	Auth           *AuthHandler
+	User           *UserHandler
+	Product        *ProductHandler
+	Order          *OrderHandler
+	Review         *ReviewHandler
+	Admin          *AdminHandler
+	Category       *CategoryHandler
+	Wishlist       *WishlistHandler
+	Cart           *CartHandler
+	Notification   *NotificationHandler
+	FileUpload     *FileUploadHandler
+	ProductImage   *ProductImageHandler
+	Recall         *RecallHandler
+	FeaturedSeller *FeaturedSellerHandler
+	Dispute        *DisputeHandler
+	Return         *ReturnHandler
+	Question       *ProductQuestionHandler
+	Promotion      *PromotionHandler
+	Coupon         *CouponHandler
+	Address        *AddressHandler
+	Support        *SupportHandler
+	Conversation   *ConversationHandler
+	Shipping       *ShippingHandler
+	EmailBroadcast *EmailBroadcastHandler
 }
 
 // SetupRoutes configures all the application routes
-func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthService) {
+func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthService, redisClient *redis.Client, integrationAPIKeys []string) {
 	// Get JWT service from auth service
 	jwtService := authService.GetJWTService()
 
@@ -33,9 +46,10 @@ func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthServi
 
 	// Auth routes (with stricter rate limiting)
 	auth := api.Group("/auth")
-	auth.Use(middleware.AuthRateLimit()) // Stricter rate limiting for auth endpoints
+	auth.Use(middleware.AuthRateLimit(redisClient)) // Stricter rate limiting for auth endpoints
 	auth.POST("/register", handlers.Auth.Register)
 	auth.POST("/login", handlers.Auth.Login)
+	auth.GET("/email-available", handlers.Auth.CheckEmailAvailability)
 	auth.POST("/refresh", handlers.Auth.RefreshToken)
 	auth.POST("/logout", handlers.Auth.Logout, middleware.JWTAuth(jwtService))
 	auth.GET("/profile", handlers.Auth.GetProfile, middleware.JWTAuth(jwtService))
@@ -48,6 +62,12 @@ func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthServi
 	// User routes
 	users := api.Group("/users")
 	users.GET("/me", handlers.User.GetProfile, middleware.JWTAuth(jwtService))
+	users.GET("/me/stats", handlers.User.GetMyStats, middleware.JWTAuth(jwtService))
+	users.GET("/me/addresses", handlers.Address.GetAddresses, middleware.JWTAuth(jwtService))
+	users.POST("/me/addresses", handlers.Address.CreateAddress, middleware.JWTAuth(jwtService))
+	users.GET("/me/addresses/:id", handlers.Address.GetAddress, middleware.JWTAuth(jwtService))
+	users.PUT("/me/addresses/:id", handlers.Address.UpdateAddress, middleware.JWTAuth(jwtService))
+	users.DELETE("/me/addresses/:id", handlers.Address.DeleteAddress, middleware.JWTAuth(jwtService))
 	users.GET("/profile", handlers.User.GetProfile, middleware.JWTAuth(jwtService))
 	users.PUT("/profile", handlers.User.UpdateProfile, middleware.JWTAuth(jwtService))
 	users.GET("", handlers.User.GetUsers, middleware.JWTAuth(jwtService), middleware.RequireRole("admin"))
@@ -59,21 +79,43 @@ func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthServi
 	// Product routes
 	products := api.Group("/products")
 	products.GET("", handlers.Product.GetProducts)
-	products.GET("/:id", handlers.Product.GetProduct)
+	products.GET("/batch", handlers.Product.GetProductsBatch)
+	products.GET("/changes", handlers.Product.GetProductChanges, middleware.APIKeyAuth(integrationAPIKeys))
+	products.POST("/batch", handlers.Product.PostProductsBatch)
+	products.GET("/:id", handlers.Product.GetProduct, middleware.OptionalAuthMiddleware(jwtService))
+	products.GET("/slug/:slug", handlers.Product.GetProductBySlug, middleware.OptionalAuthMiddleware(jwtService))
 	products.POST("", handlers.Product.CreateProduct, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	products.PUT("/:id", handlers.Product.UpdateProduct, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	products.DELETE("/:id", handlers.Product.DeleteProduct, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	products.GET("/:id/price-history", handlers.Product.GetPriceHistory, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	products.PUT("/:id/stock", handlers.Product.UpdateStock, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	products.GET("/:id/translations", handlers.Product.GetTranslations, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	products.PUT("/:id/translations/:locale", handlers.Product.SetTranslation, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	products.DELETE("/:id/translations/:locale", handlers.Product.DeleteTranslation, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	products.GET("/low-stock", handlers.Product.GetLowStockProducts, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	products.GET("/top-rated", handlers.Product.GetTopRatedProducts)
-	products.GET("/search", handlers.Product.SearchProducts)
+	products.GET("/featured", handlers.Product.GetFeaturedProducts)
+	products.GET("/new-arrivals", handlers.Product.GetNewArrivals)
+	products.GET("/:id/related", handlers.Product.GetRelatedProducts)
+	products.GET("/search", handlers.Product.SearchProducts, middleware.OptionalAuthMiddleware(jwtService))
+	products.GET("/search/suggestions", handlers.Product.GetSearchSuggestions)
 	products.GET("/category/:category", handlers.Product.GetProductsByCategory)
 
+	// Tags
+	api.GET("/tags", handlers.Product.GetTags)
+	api.GET("/tags/:tag/products", handlers.Product.GetProductsByTag)
+
 	// Product reviews
 	products.GET("/:product_id/reviews", handlers.Review.GetProductReviews)
 	products.GET("/:product_id/reviews/stats", handlers.Review.GetProductReviewStats)
+	products.GET("/:product_id/reviews/summary", handlers.Review.GetReviewSummary)
 	products.GET("/:product_id/can-review", handlers.Review.CanUserReview, middleware.JWTAuth(jwtService))
 
+	// Product Q&A
+	products.GET("/:product_id/questions", handlers.Question.GetProductQuestions)
+	products.POST("/:product_id/questions", handlers.Question.AskQuestion, middleware.JWTAuth(jwtService))
+	api.POST("/questions/:id/answers", handlers.Question.AnswerQuestion, middleware.JWTAuth(jwtService))
+
 	// Product images
 	products.GET("/:product_id/images", handlers.ProductImage.GetProductImages)
 	products.POST("/:product_id/images", handlers.ProductImage.AddProductImage, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
@@ -89,17 +131,54 @@ func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthServi
 	// Order routes
 	orders := api.Group("/orders")
 	orders.POST("", handlers.Order.CreateOrder, middleware.JWTAuth(jwtService))
+	orders.POST("/preview", handlers.Order.PreviewOrder, middleware.JWTAuth(jwtService))
+	orders.POST("/guest", handlers.Order.CreateGuestOrder, middleware.AuthRateLimit(redisClient))
+	orders.POST("/guest/:id/payment", handlers.Order.ProcessGuestPayment, middleware.AuthRateLimit(redisClient))
+	orders.POST("/track", handlers.Order.TrackOrder, middleware.AuthRateLimit(redisClient))
 	orders.GET("/my", handlers.Order.GetUserOrders, middleware.JWTAuth(jwtService))
+	orders.GET("/confirmation-queue", handlers.Order.GetConfirmationQueue, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	orders.GET("/:id", handlers.Order.GetOrder, middleware.JWTAuth(jwtService))
 	orders.PUT("/:id/status", handlers.Order.UpdateOrderStatus, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	orders.POST("/:id/payment", handlers.Order.ProcessPayment, middleware.JWTAuth(jwtService))
+	orders.POST("/:id/reservation/extend", handlers.Order.ExtendReservation, middleware.JWTAuth(jwtService))
 	orders.PUT("/:id/cancel", handlers.Order.CancelOrder, middleware.JWTAuth(jwtService))
+	orders.PUT("/:id/shipping-address", handlers.Order.UpdateShippingAddress, middleware.JWTAuth(jwtService))
+	orders.POST("/:id/resend-confirmation", handlers.Order.ResendConfirmationEmail, middleware.JWTAuth(jwtService))
+	orders.GET("/:id/confirmation", handlers.Order.GetOrderConfirmation, middleware.JWTAuth(jwtService))
+	orders.POST("/:id/returns", handlers.Return.RequestReturn, middleware.JWTAuth(jwtService))
+	api.GET("/returns/my", handlers.Return.GetMyReturns, middleware.JWTAuth(jwtService))
+	orders.PUT("/:id/confirmation", handlers.Order.ReviewOrderConfirmation, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	orders.GET("/status/:status", handlers.Order.GetOrdersByStatus, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	orders.GET("/analytics", handlers.Order.GetOrderAnalytics, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 
 	// Seller routes
 	seller := api.Group("/seller")
+	seller.GET("/onboarding", handlers.User.GetSellerOnboarding, middleware.JWTAuth(jwtService), middleware.RequireRole("seller"))
 	seller.GET("/orders", handlers.Order.GetSellerOrders, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	seller.PUT("/orders/:id/assignee", handlers.Order.AssignOrder, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	seller.POST("/staff", handlers.User.CreateStaff, middleware.JWTAuth(jwtService), middleware.RequireRole("seller"))
+	seller.GET("/staff", handlers.User.GetStaff, middleware.JWTAuth(jwtService), middleware.RequireRole("seller"))
+	seller.DELETE("/staff/:id", handlers.User.RemoveStaff, middleware.JWTAuth(jwtService), middleware.RequireRole("seller"))
+	seller.GET("/reviews", handlers.Review.GetSellerReviews, middleware.JWTAuth(jwtService), middleware.RequireRole("seller"))
+	seller.GET("/products/:id/orders", handlers.Order.GetProductOrders, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	seller.GET("/analytics/inventory-valuation", handlers.Product.GetInventoryValuation, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	seller.GET("/inventory/alerts", handlers.Product.GetInventoryAlerts, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	seller.GET("/products/accessibility", handlers.ProductImage.GetAccessibilityReport, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	seller.GET("/products/attention", handlers.Product.GetProductsNeedingAttention, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+	seller.PUT("/products/visibility/bulk", handlers.Product.BulkSetVisibility, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
+
+	// Seller staff routes
+	staff := api.Group("/staff")
+	staff.GET("/orders", handlers.Order.GetAssignedOrders, middleware.JWTAuth(jwtService), middleware.RequireRole("seller_staff"))
+
+	// Coupon routes
+	api.POST("/coupons/validate", handlers.Coupon.ValidateCoupon, middleware.JWTAuth(jwtService), middleware.CouponRateLimit(redisClient))
+
+	// Payment provider webhooks (authenticated by signature, not JWT)
+	api.POST("/webhooks/stripe", handlers.Dispute.StripeWebhook)
+
+	// Public storefront routes
+	api.GET("/sellers/featured", handlers.FeaturedSeller.GetFeaturedSellers)
 
 	// Review routes
 	reviews := api.Group("/reviews")
@@ -117,9 +196,55 @@ func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthServi
 	admin.Use(middleware.JWTAuth(jwtService), middleware.RequireRole("admin"))
 	admin.GET("/dashboard", handlers.Admin.GetDashboardStats)
 	admin.GET("/orders", handlers.Order.GetAllOrders)
+	admin.GET("/orders/export", handlers.Admin.ExportOrders)
+	admin.GET("/orders/review", handlers.Admin.GetFraudReviewQueue)
+	admin.GET("/orders/stuck", handlers.Admin.GetStuckOrders)
 	admin.GET("/orders/:id", handlers.Admin.GetOrderDetails)
+	admin.PUT("/orders/:id/review", handlers.Admin.ReviewFlaggedOrder)
+	admin.POST("/orders/:id/recalculate", handlers.Admin.RecalculateOrder)
 	admin.PUT("/users/:id", handlers.Admin.ManageUser)
+	admin.POST("/sellers/:id/deactivate", handlers.Admin.DeactivateSeller)
+	admin.POST("/sellers/:id/reactivate", handlers.Admin.ReactivateSeller)
+	admin.POST("/reviews/bulk-moderate", handlers.Admin.BulkModerateReviews)
+	admin.GET("/reviews/:id/history", handlers.Admin.GetReviewHistory)
+	admin.GET("/products/pending", handlers.Product.GetPendingProducts)
+	admin.PUT("/products/:id/approve", handlers.Product.ApproveProduct)
+	admin.PUT("/products/:id/reject", handlers.Product.RejectProduct)
+	admin.POST("/products/:id/purchase-limit-exemptions", handlers.Product.GrantPurchaseLimitExemption)
+	admin.DELETE("/products/:id/purchase-limit-exemptions/:user_id", handlers.Product.RevokePurchaseLimitExemption)
 	admin.GET("/health", handlers.Admin.GetSystemHealth)
+	admin.GET("/cache/stats", handlers.Product.GetCacheStats)
+	admin.GET("/featured-sellers", handlers.FeaturedSeller.ListFeaturedSellers)
+	admin.POST("/featured-sellers", handlers.FeaturedSeller.FeatureSeller)
+	admin.PUT("/featured-sellers/order", handlers.FeaturedSeller.ReorderFeaturedSellers)
+	admin.DELETE("/featured-sellers/:seller_id", handlers.FeaturedSeller.UnfeatureSeller)
+	admin.GET("/support/tickets", handlers.Support.GetTickets)
+	admin.GET("/support/tickets/:id", handlers.Support.GetTicket)
+	admin.POST("/support/tickets/:id/messages", handlers.Support.RespondToTicket)
+	admin.GET("/disputes", handlers.Dispute.GetDisputes)
+	admin.GET("/disputes/:id", handlers.Dispute.GetDispute)
+	admin.PUT("/disputes/:id/evidence", handlers.Dispute.SubmitDisputeEvidence)
+	admin.GET("/returns", handlers.Return.GetReturns)
+	admin.PUT("/returns/:id/approve", handlers.Return.ApproveReturn)
+	admin.PUT("/returns/:id/reject", handlers.Return.RejectReturn)
+	admin.GET("/promotions", handlers.Promotion.GetPromotions)
+	admin.POST("/promotions", handlers.Promotion.CreatePromotion)
+	admin.GET("/promotions/:id", handlers.Promotion.GetPromotion)
+	admin.PUT("/promotions/:id", handlers.Promotion.UpdatePromotion)
+	admin.DELETE("/promotions/:id", handlers.Promotion.DeletePromotion)
+	admin.GET("/coupons", handlers.Coupon.GetCoupons)
+	admin.POST("/coupons", handlers.Coupon.CreateCoupon)
+	admin.GET("/email-broadcasts", handlers.EmailBroadcast.GetEmailBroadcasts)
+	admin.POST("/email-broadcasts", handlers.EmailBroadcast.CreateEmailBroadcast)
+	admin.GET("/email-broadcasts/:id", handlers.EmailBroadcast.GetEmailBroadcast)
+	admin.GET("/shipping-zones", handlers.Shipping.GetShippingZones)
+	admin.POST("/shipping-zones", handlers.Shipping.CreateShippingZone)
+	admin.GET("/shipping-zones/:id", handlers.Shipping.GetShippingZone)
+	admin.PUT("/shipping-zones/:id", handlers.Shipping.UpdateShippingZone)
+	admin.DELETE("/shipping-zones/:id", handlers.Shipping.DeleteShippingZone)
+
+	// Recalls are registered outside the admin group so sellers can recall their own products
+	api.POST("/admin/products/:id/recall", handlers.Recall.RecallProduct, middleware.JWTAuth(jwtService), middleware.RequireRole("seller", "admin"))
 	
 	// Admin analytics
 	adminAnalytics := admin.Group("/analytics")
@@ -127,6 +252,9 @@ func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthServi
 	adminAnalytics.GET("/users", handlers.Admin.GetUserAnalytics)
 	adminAnalytics.GET("/products", handlers.Admin.GetProductAnalytics)
 	adminAnalytics.GET("/reviews", handlers.Admin.GetReviewAnalytics)
+	adminAnalytics.GET("/cancellations", handlers.Admin.GetCancellationAnalytics)
+	adminAnalytics.GET("/best-sellers", handlers.Admin.GetBestSellers)
+	adminAnalytics.GET("/searches", handlers.Admin.GetSearchAnalytics)
 
 	// Category routes
 	categories := api.Group("/categories")
@@ -144,6 +272,8 @@ func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthServi
 	wishlist.Use(middleware.JWTAuth(jwtService))
 	wishlist.POST("", handlers.Wishlist.AddToWishlist)
 	wishlist.GET("", handlers.Wishlist.GetUserWishlist)
+	wishlist.GET("/count", handlers.Wishlist.GetWishlistCount)
+	wishlist.POST("/check-batch", handlers.Wishlist.CheckWishlistBatch)
 	wishlist.DELETE("/:productId", handlers.Wishlist.RemoveFromWishlist)
 	wishlist.GET("/:productId/check", handlers.Wishlist.IsProductInWishlist)
 	wishlist.DELETE("", handlers.Wishlist.ClearWishlist)
@@ -152,10 +282,12 @@ func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthServi
 	cart := api.Group("/cart")
 	cart.Use(middleware.JWTAuth(jwtService))
 	cart.POST("", handlers.Cart.AddToCart)
+	cart.POST("/items/bulk", handlers.Cart.AddManyToCart)
 	cart.GET("", handlers.Cart.GetUserCart)
 	cart.PUT("/:productId", handlers.Cart.UpdateCartItem)
 	cart.DELETE("/:productId", handlers.Cart.RemoveFromCart)
 	cart.GET("/total", handlers.Cart.GetCartTotal)
+	cart.GET("/summary", handlers.Cart.GetCartSummary)
 	cart.GET("/count", handlers.Cart.GetCartItemCount)
 	cart.DELETE("", handlers.Cart.ClearCart)
 
@@ -171,6 +303,22 @@ func SetupRoutes(e *echo.Echo, handlers *Handlers, authService service.AuthServi
 	notifications.GET("/unread-count", handlers.Notification.GetUnreadCount)
 	notifications.POST("", handlers.Notification.CreateNotification, middleware.RequireRole("admin"))
 
+	// Support ticket routes
+	support := api.Group("/support")
+	support.Use(middleware.JWTAuth(jwtService))
+	support.POST("/tickets", handlers.Support.CreateTicket)
+	support.GET("/tickets", handlers.Support.GetMyTickets)
+	support.GET("/tickets/:id", handlers.Support.GetMyTicket)
+	support.POST("/tickets/:id/messages", handlers.Support.ReplyToTicket)
+
+	// Customer-seller messaging routes
+	api.POST("/sellers/:id/messages", handlers.Conversation.ContactSeller, middleware.JWTAuth(jwtService))
+	conversations := api.Group("/conversations")
+	conversations.Use(middleware.JWTAuth(jwtService))
+	conversations.GET("", handlers.Conversation.GetConversations)
+	conversations.GET("/:id", handlers.Conversation.GetConversation)
+	conversations.POST("/:id/messages", handlers.Conversation.SendMessage)
+
 	// File upload routes
 	uploads := api.Group("/uploads")
 	uploads.POST("", handlers.FileUpload.UploadFile, middleware.JWTAuth(jwtService))
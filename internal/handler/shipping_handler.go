@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type ShippingHandler struct {
+	shippingService service.ShippingService
+}
+
+func NewShippingHandler(shippingService service.ShippingService) *ShippingHandler {
+	return &ShippingHandler{shippingService: shippingService}
+}
+
+// GetShippingZones lists shipping zones
+// @Summary List shipping zones
+// @Description List every shipping zone with its weight-bracket rate table (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.ShippingZone}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/shipping-zones [get]
+func (h *ShippingHandler) GetShippingZones(c echo.Context) error {
+	zones, err := h.shippingService.GetZones(c.Request().Context())
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Shipping zones retrieved successfully", zones)
+}
+
+// GetShippingZone retrieves a shipping zone
+// @Summary Get a shipping zone
+// @Description Get a shipping zone and its rate table by ID (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Shipping zone ID"
+// @Success 200 {object} utils.Response{data=models.ShippingZone}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/shipping-zones/{id} [get]
+func (h *ShippingHandler) GetShippingZone(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid shipping zone ID")
+	}
+
+	zone, err := h.shippingService.GetZone(c.Request().Context(), uint(id))
+	if err != nil {
+		return shippingZoneError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Shipping zone retrieved successfully", zone)
+}
+
+// CreateShippingZone creates a shipping zone
+// @Summary Create a shipping zone
+// @Description Create a shipping zone covering countries and/or regions (e.g. "US-AK") with per-weight-bracket rates. A default zone prices destinations no other zone covers (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param zone body models.ShippingZoneRequest true "Shipping zone"
+// @Success 201 {object} utils.Response{data=models.ShippingZone}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/shipping-zones [post]
+func (h *ShippingHandler) CreateShippingZone(c echo.Context) error {
+	var req models.ShippingZoneRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	zone, err := h.shippingService.CreateZone(c.Request().Context(), &req)
+	if err != nil {
+		return shippingZoneError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Shipping zone created successfully", zone)
+}
+
+// UpdateShippingZone replaces a shipping zone
+// @Summary Update a shipping zone
+// @Description Replace a shipping zone's destinations and rate table (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping zone ID"
+// @Param zone body models.ShippingZoneRequest true "Shipping zone"
+// @Success 200 {object} utils.Response{data=models.ShippingZone}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/shipping-zones/{id} [put]
+func (h *ShippingHandler) UpdateShippingZone(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid shipping zone ID")
+	}
+
+	var req models.ShippingZoneRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	zone, err := h.shippingService.UpdateZone(c.Request().Context(), uint(id), &req)
+	if err != nil {
+		return shippingZoneError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Shipping zone updated successfully", zone)
+}
+
+// DeleteShippingZone deletes a shipping zone
+// @Summary Delete a shipping zone
+// @Description Delete a shipping zone; its destinations fall back to the default zone (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Shipping zone ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/shipping-zones/{id} [delete]
+func (h *ShippingHandler) DeleteShippingZone(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid shipping zone ID")
+	}
+
+	if err := h.shippingService.DeleteZone(c.Request().Context(), uint(id)); err != nil {
+		return shippingZoneError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Shipping zone deleted successfully", nil)
+}
+
+// shippingZoneError maps shipping zone errors to responses
+func shippingZoneError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrShippingZoneNotFound):
+		return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+	case errors.Is(err, service.ErrZoneWithoutDestinations),
+		errors.Is(err, service.ErrInvalidRateBracket),
+		errors.Is(err, service.ErrOverlappingRates):
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+	return serviceError(c, err)
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type SupportHandler struct {
+	supportService service.SupportService
+}
+
+func NewSupportHandler(supportService service.SupportService) *SupportHandler {
+	return &SupportHandler{supportService: supportService}
+}
+
+// CreateTicket opens a support ticket
+// @Summary Open a support ticket
+// @Description Report a problem, optionally about one of the authenticated user's orders. Admins are notified.
+// @Tags support
+// @Accept json
+// @Produce json
+// @Param ticket body models.CreateSupportTicketRequest true "Ticket details"
+// @Success 201 {object} utils.Response{data=models.SupportTicket}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /support/tickets [post]
+func (h *SupportHandler) CreateTicket(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	var req models.CreateSupportTicketRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	ticket, err := h.supportService.CreateTicket(c.Request().Context(), userID, &req)
+	if err != nil {
+		return supportError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Support ticket created successfully", ticket)
+}
+
+// GetMyTickets lists the user's support tickets
+// @Summary List my support tickets
+// @Description List the authenticated user's support tickets, most recently updated first
+// @Tags support
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=[]models.SupportTicket}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /support/tickets [get]
+func (h *SupportHandler) GetMyTickets(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceDefault)
+
+	tickets, total, err := h.supportService.GetMyTickets(c.Request().Context(), userID, limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Support tickets retrieved successfully", tickets, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetMyTicket retrieves one of the user's support tickets
+// @Summary Get my support ticket
+// @Description Get one of the authenticated user's support tickets with its linked order and messages
+// @Tags support
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} utils.Response{data=models.SupportTicket}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /support/tickets/{id} [get]
+func (h *SupportHandler) GetMyTicket(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid ticket ID")
+	}
+
+	ticket, err := h.supportService.GetMyTicket(c.Request().Context(), userID, uint(id))
+	if err != nil {
+		return supportError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Support ticket retrieved successfully", ticket)
+}
+
+// ReplyToTicket adds the user's reply to their support ticket
+// @Summary Reply to my support ticket
+// @Description Add a message to one of the authenticated user's open support tickets
+// @Tags support
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param reply body models.SupportTicketReplyRequest true "Reply"
+// @Success 200 {object} utils.Response{data=models.SupportTicket}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /support/tickets/{id}/messages [post]
+func (h *SupportHandler) ReplyToTicket(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid ticket ID")
+	}
+
+	var req models.SupportTicketReplyRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+	// Customers can't set the status themselves
+	req.Status = nil
+
+	ticket, err := h.supportService.ReplyToTicket(c.Request().Context(), userID, uint(id), &req)
+	if err != nil {
+		return supportError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Reply added successfully", ticket)
+}
+
+// GetTickets lists all support tickets
+// @Summary Get support tickets
+// @Description Get support tickets, longest waiting first (admin only)
+// @Tags admin
+// @Produce json
+// @Param status query string false "Ticket status (open, answered, closed)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=[]models.SupportTicket}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/support/tickets [get]
+func (h *SupportHandler) GetTickets(c echo.Context) error {
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
+
+	var status *models.SupportTicketStatus
+	if s := c.QueryParam("status"); s != "" {
+		ticketStatus := models.SupportTicketStatus(s)
+		status = &ticketStatus
+	}
+
+	tickets, total, err := h.supportService.GetTickets(c.Request().Context(), status, limit, (page-1)*limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponseWithMeta(c, "Support tickets retrieved successfully", tickets, map[string]interface{}{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetTicket retrieves a support ticket
+// @Summary Get support ticket
+// @Description Get a support ticket with its linked order and messages (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} utils.Response{data=models.SupportTicket}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/support/tickets/{id} [get]
+func (h *SupportHandler) GetTicket(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid ticket ID")
+	}
+
+	ticket, err := h.supportService.GetTicket(c.Request().Context(), uint(id))
+	if err != nil {
+		return supportError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Support ticket retrieved successfully", ticket)
+}
+
+// RespondToTicket adds a staff reply to a support ticket
+// @Summary Respond to support ticket
+// @Description Reply to a support ticket and optionally set its status; defaults to answered. The customer is notified. (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param reply body models.SupportTicketReplyRequest true "Reply"
+// @Success 200 {object} utils.Response{data=models.SupportTicket}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/support/tickets/{id}/messages [post]
+func (h *SupportHandler) RespondToTicket(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid ticket ID")
+	}
+
+	var req models.SupportTicketReplyRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	ticket, err := h.supportService.RespondToTicket(c.Request().Context(), adminID, uint(id), &req)
+	if err != nil {
+		return supportError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Response added successfully", ticket)
+}
+
+// supportError maps support service errors to responses
+func supportError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrTicketNotFound),
+		errors.Is(err, service.ErrOrderNotFound):
+		return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+	case errors.Is(err, service.ErrTicketClosed):
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+	}
+	return serviceError(c, err)
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
@@ -43,12 +46,163 @@ func (h *userHandler) GetProfile(c echo.Context) error {
 
 	user, err := h.userService.GetProfile(c.Request().Context(), userID)
 	if err != nil {
-		return utils.NotFoundError(c, "User not found")
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.UserNotFound, "User not found")
 	}
 
 	return utils.SuccessResponse(c, "Profile retrieved successfully", user)
 }
 
+// GetSellerOnboarding handles getting the seller onboarding checklist
+// @Summary Get seller onboarding status
+// @Description Get the steps left before the seller can start selling and whether onboarding is complete
+// @Tags sellers
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.SellerOnboardingStatus
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /seller/onboarding [get]
+func (h *userHandler) GetSellerOnboarding(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return err
+	}
+
+	status, err := h.userService.GetSellerOnboarding(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.UserNotFound, "User not found")
+		}
+		return utils.InternalServerError(c, "Failed to get onboarding status")
+	}
+
+	return utils.SuccessResponse(c, "Onboarding status retrieved successfully", status)
+}
+
+// CreateStaff handles a seller creating a staff account
+// @Summary Create staff account
+// @Description Create a staff account for the seller's team. Staff sign in with their own credentials and fulfill the orders the seller assigns them.
+// @Tags sellers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.StaffCreateRequest true "Staff account"
+// @Success 201 {object} models.UserResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /seller/staff [post]
+func (h *userHandler) CreateStaff(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.StaffCreateRequest
+	if err := utils.BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	staff, err := h.userService.CreateStaff(c.Request().Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmailTaken):
+			return utils.ErrorResponseFromError(c, http.StatusConflict, err)
+		case errors.Is(err, service.ErrWeakPassword):
+			return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
+		}
+		return utils.InternalServerError(c, "Failed to create staff account")
+	}
+
+	return utils.CreatedResponse(c, "Staff account created successfully", staff)
+}
+
+// GetStaff handles listing the seller's staff accounts
+// @Summary List staff accounts
+// @Description Get the seller's staff accounts, including removed ones (inactive)
+// @Tags sellers
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.Response{data=[]models.UserResponse}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /seller/staff [get]
+func (h *userHandler) GetStaff(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return err
+	}
+
+	staff, err := h.userService.GetStaff(c.Request().Context(), userID)
+	if err != nil {
+		return utils.InternalServerError(c, "Failed to get staff")
+	}
+
+	return utils.SuccessResponse(c, "Staff retrieved successfully", staff)
+}
+
+// RemoveStaff handles a seller removing a staff account
+// @Summary Remove staff account
+// @Description Deactivate a staff account and unassign the orders assigned to it
+// @Tags sellers
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Staff user ID"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /seller/staff/{id} [delete]
+func (h *userHandler) RemoveStaff(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return err
+	}
+
+	staffID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return utils.InvalidIDError(c, "Invalid staff ID")
+	}
+
+	if err := h.userService.RemoveStaff(c.Request().Context(), userID, uint(staffID)); err != nil {
+		if errors.Is(err, service.ErrStaffNotFound) {
+			return utils.ErrorResponseFromError(c, http.StatusNotFound, err)
+		}
+		return utils.InternalServerError(c, "Failed to remove staff member")
+	}
+
+	return utils.SuccessResponse(c, "Staff member removed successfully", nil)
+}
+
+// GetMyStats handles getting the current user's order history summary
+// @Summary Get own order stats
+// @Description Get total spent, order count, favorite category and member-since date for the authenticated user. Cancelled and refunded orders are excluded.
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.CustomerStats
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /users/me/stats [get]
+func (h *userHandler) GetMyStats(c echo.Context) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return err
+	}
+
+	stats, err := h.userService.GetCustomerStats(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.UserNotFound, "User not found")
+		}
+		return utils.InternalServerError(c, "Failed to get order stats")
+	}
+
+	return utils.SuccessResponse(c, "Order stats retrieved successfully", stats)
+}
+
 // UpdateProfile handles updating user profile
 // @Summary Update user profile
 // @Description Update the profile of the currently authenticated user
@@ -88,13 +242,13 @@ func (h *userHandler) UpdateProfile(c echo.Context) error {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
-// @Param role query string false "Filter by role" Enums(customer, seller, admin)
+// @Param role query string false "Filter by role" Enums(customer, seller, admin, seller_staff)
 // @Success 200 {object} models.Response{data=[]models.UserResponse,meta=models.PaginationMeta}
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
 // @Router /users [get]
 func (h *userHandler) GetUsers(c echo.Context) error {
-	page, limit := utils.PaginationParams(c)
+	page, limit := utils.PaginationParamsFor(c, utils.PageResourceAdmin)
 	
 	var role *models.UserRole
 	if roleStr := c.QueryParam("role"); roleStr != "" {
@@ -126,12 +280,12 @@ func (h *userHandler) GetUsers(c echo.Context) error {
 func (h *userHandler) GetUser(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.BadRequestError(c, "Invalid user ID")
+		return utils.InvalidIDError(c, "Invalid user ID")
 	}
 
 	user, err := h.userService.GetUserByID(c.Request().Context(), uint(id))
 	if err != nil {
-		return utils.NotFoundError(c, "User not found")
+		return utils.ErrorResponseWithCode(c, http.StatusNotFound, apierror.UserNotFound, "User not found")
 	}
 
 	return utils.SuccessResponse(c, "User retrieved successfully", user)
@@ -159,8 +313,8 @@ func (h *userHandler) CreateUser(c echo.Context) error {
 
 	user, err := h.userService.CreateUser(c.Request().Context(), &req)
 	if err != nil {
-		if err.Error() == "user with this email already exists" {
-			return utils.ConflictError(c, err.Error())
+		if errors.Is(err, service.ErrEmailTaken) {
+			return utils.ErrorResponseFromError(c, http.StatusConflict, err)
 		}
 		return utils.InternalServerError(c, "Failed to create user")
 	}
@@ -186,7 +340,7 @@ func (h *userHandler) CreateUser(c echo.Context) error {
 func (h *userHandler) UpdateUser(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.BadRequestError(c, "Invalid user ID")
+		return utils.InvalidIDError(c, "Invalid user ID")
 	}
 
 	var req models.UserUpdateRequest
@@ -217,10 +371,12 @@ func (h *userHandler) UpdateUser(c echo.Context) error {
 func (h *userHandler) DeleteUser(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return utils.BadRequestError(c, "Invalid user ID")
+		return utils.InvalidIDError(c, "Invalid user ID")
 	}
 
-	if err := h.userService.DeleteUser(c.Request().Context(), uint(id)); err != nil {
+	adminID := c.Get("user_id").(uint)
+
+	if err := h.userService.DeleteUser(c.Request().Context(), uint(id), adminID); err != nil {
 		return utils.InternalServerError(c, "Failed to delete user")
 	}
 
@@ -28,12 +28,12 @@ func (h *WishlistHandler) AddToWishlist(c echo.Context) error {
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return utils.ErrorResponseFromError(c, http.StatusBadRequest, err)
 	}
 
 	wishlist, err := h.wishlistService.AddToWishlist(c.Request().Context(), userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.CreatedResponse(c, "Product added to wishlist successfully", wishlist)
@@ -45,12 +45,12 @@ func (h *WishlistHandler) RemoveFromWishlist(c echo.Context) error {
 
 	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	err = h.wishlistService.RemoveFromWishlist(c.Request().Context(), userID, uint(productID))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product removed from wishlist successfully", nil)
@@ -62,7 +62,7 @@ func (h *WishlistHandler) GetUserWishlist(c echo.Context) error {
 
 	wishlist, err := h.wishlistService.GetUserWishlist(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Wishlist retrieved successfully", wishlist)
@@ -74,12 +74,12 @@ func (h *WishlistHandler) IsProductInWishlist(c echo.Context) error {
 
 	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return utils.InvalidIDError(c, "Invalid product ID")
 	}
 
 	isInWishlist, err := h.wishlistService.IsProductInWishlist(c.Request().Context(), userID, uint(productID))
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product wishlist status retrieved successfully", map[string]bool{"is_in_wishlist": isInWishlist})
@@ -91,8 +91,41 @@ func (h *WishlistHandler) ClearWishlist(c echo.Context) error {
 
 	err := h.wishlistService.ClearWishlist(c.Request().Context(), userID)
 	if err != nil {
-		return utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return serviceError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Wishlist cleared successfully", nil)
 }
+
+// CheckWishlistBatch checks which of a list of products are in user's wishlist
+func (h *WishlistHandler) CheckWishlistBatch(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	var req models.WishlistCheckBatchRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ValidationError(c, utils.GetValidationErrors(err))
+	}
+
+	statuses, err := h.wishlistService.CheckMany(c.Request().Context(), userID, req.ProductIDs)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product wishlist statuses retrieved successfully", statuses)
+}
+
+// GetWishlistCount gets the number of items in user's wishlist
+func (h *WishlistHandler) GetWishlistCount(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	count, err := h.wishlistService.GetWishlistCount(c.Request().Context(), userID)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Wishlist count retrieved successfully", map[string]int64{"count": count})
+}
@@ -1,11 +1,12 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
-	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/utils"
 )
 
@@ -16,36 +17,24 @@ func AuthMiddleware(jwtService *utils.JWTService) echo.MiddlewareFunc {
 			// Get Authorization header
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
-				return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-					Success: false,
-					Error:   "Authorization header required",
-				})
+				return utils.ErrorResponseWithCode(c, http.StatusUnauthorized, apierror.Unauthorized, "Authorization header required")
 			}
 
 			// Check if it starts with "Bearer "
 			if !strings.HasPrefix(authHeader, "Bearer ") {
-				return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-					Success: false,
-					Error:   "Invalid authorization header format",
-				})
+				return utils.ErrorResponseWithCode(c, http.StatusUnauthorized, apierror.Unauthorized, "Invalid authorization header format")
 			}
 
 			// Extract token
 			token := strings.TrimPrefix(authHeader, "Bearer ")
 			if token == "" {
-				return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-					Success: false,
-					Error:   "Token required",
-				})
+				return utils.ErrorResponseWithCode(c, http.StatusUnauthorized, apierror.Unauthorized, "Token required")
 			}
 
 			// Validate token
 			claims, err := jwtService.ValidateToken(token)
 			if err != nil {
-				return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-					Success: false,
-					Error:   "Invalid or expired token",
-				})
+				return utils.ErrorResponseWithCode(c, http.StatusUnauthorized, apierror.TokenInvalid, "Invalid or expired token")
 			}
 
 			// Set user information in context
@@ -99,3 +88,24 @@ func OptionalAuthMiddleware(jwtService *utils.JWTService) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// APIKeyAuth lets integrations in with one of the given keys in the X-API-Key
+// header. With no keys configured every request is rejected.
+func APIKeyAuth(keys []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get("X-API-Key")
+			if key == "" {
+				return utils.ErrorResponseWithCode(c, http.StatusUnauthorized, apierror.Unauthorized, "API key required")
+			}
+
+			for _, valid := range keys {
+				if subtle.ConstantTimeCompare([]byte(key), []byte(valid)) == 1 {
+					return next(c)
+				}
+			}
+
+			return utils.ErrorResponseWithCode(c, http.StatusUnauthorized, apierror.Unauthorized, "Invalid API key")
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/pkg/geo"
+	"github.com/labstack/echo/v4"
+)
+
+// geoLookupTimeout bounds how long a request waits on the geo provider
+const geoLookupTimeout = 300 * time.Millisecond
+
+// GeoLocation resolves the client's country from its IP and stores locale
+// defaults in the context under "locale". Clients can override the result with
+// the country, currency and locale query params. Lookup failures never block
+// the request.
+func GeoLocation(geoService geo.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			info := &models.LocaleInfo{Country: geo.DefaultCountry}
+
+			if geoService != nil {
+				ctx, cancel := context.WithTimeout(c.Request().Context(), geoLookupTimeout)
+				location, err := geoService.Lookup(ctx, c.RealIP())
+				cancel()
+				if err == nil && location != nil && location.CountryCode != "" {
+					info.Country = strings.ToUpper(location.CountryCode)
+					info.Detected = true
+					if location.Region != "" {
+						info.TaxRegion = info.Country + "-" + strings.ToUpper(location.Region)
+					}
+				}
+			}
+
+			// Explicit params always win over geolocation
+			if country := c.QueryParam("country"); len(country) == 2 {
+				info.Country = strings.ToUpper(country)
+				info.TaxRegion = ""
+				info.Detected = false
+			}
+
+			defaults := geo.DefaultsForCountry(info.Country)
+			info.Currency = defaults.Currency
+			info.Locale = defaults.Locale
+			if info.TaxRegion == "" {
+				info.TaxRegion = info.Country
+			}
+
+			if currency := c.QueryParam("currency"); len(currency) == 3 {
+				info.Currency = strings.ToUpper(currency)
+			}
+			if locale := c.QueryParam("locale"); locale != "" {
+				info.Locale = locale
+			}
+
+			c.Set("locale", info)
+			return next(c)
+		}
+	}
+}
@@ -1,43 +1,48 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
 	"github.com/labstack/echo/v4"
-	"golang.org/x/time/rate"
+	"github.com/redis/go-redis/v9"
 )
 
+const rateLimitWindow = time.Minute
+
 // RateLimitConfig holds the configuration for rate limiting
 type RateLimitConfig struct {
+	Name              string // Keeps each endpoint group's counters apart
 	RequestsPerMinute int
-	BurstSize         int
 	SkipSuccessful    bool
 }
 
 // DefaultRateLimitConfig returns a default rate limit configuration
 func DefaultRateLimitConfig() RateLimitConfig {
 	return RateLimitConfig{
+		Name:              "default",
 		RequestsPerMinute: 100,
-		BurstSize:         10,
 		SkipSuccessful:    false,
 	}
 }
 
 // RateLimit returns a rate limiting middleware
-func RateLimit() echo.MiddlewareFunc {
-	return RateLimitWithConfig(DefaultRateLimitConfig())
+func RateLimit(client *redis.Client) echo.MiddlewareFunc {
+	return RateLimitWithConfig(client, DefaultRateLimitConfig())
 }
 
-// RateLimitWithConfig returns a rate limiting middleware with custom configuration
-func RateLimitWithConfig(config RateLimitConfig) echo.MiddlewareFunc {
-	// Create a rate limiter per IP address
-	limiters := make(map[string]*rate.Limiter)
-
-	// Convert requests per minute to requests per second
-	requestsPerSecond := float64(config.RequestsPerMinute) / 60.0
-
+// RateLimitWithConfig returns a rate limiting middleware with custom
+// configuration. Requests are counted per IP in one-minute windows in Redis,
+// so every API instance shares the same budget. If Redis can't be reached the
+// request is counted in this instance's memory instead, so the limit still
+// holds per instance rather than lifting.
+func RateLimitWithConfig(client *redis.Client, config RateLimitConfig) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// Get client IP
@@ -46,45 +51,112 @@ func RateLimitWithConfig(config RateLimitConfig) echo.MiddlewareFunc {
 				clientIP = c.Request().RemoteAddr
 			}
 
-			// Get or create rate limiter for this IP
-			limiter, exists := limiters[clientIP]
-			if !exists {
-				limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), config.BurstSize)
-				limiters[clientIP] = limiter
+			now := time.Now()
+			count, reset, err := countRequest(c.Request().Context(), client, config.Name, clientIP, now)
+			if err != nil {
+				fmt.Printf("Warning: rate limit check failed for %s, counting in memory: %v\n", clientIP, err)
+				count, reset = localCounts.count(config.Name, clientIP, now)
 			}
+			setRateLimitHeaders(c, config, count, reset)
 
-			// Check if request is allowed
-			if !limiter.Allow() {
-				// Add rate limit headers
-				c.Response().Header().Set("X-RateLimit-Limit", strconv.Itoa(config.RequestsPerMinute))
-				c.Response().Header().Set("X-RateLimit-Remaining", "0")
-				c.Response().Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
-
-				return echo.NewHTTPError(http.StatusTooManyRequests, "Rate limit exceeded")
+			if count > int64(config.RequestsPerMinute) {
+				return utils.ErrorResponseWithCode(c, http.StatusTooManyRequests, apierror.RateLimited, "Rate limit exceeded")
 			}
 
-			// Add rate limit headers for successful requests
-			c.Response().Header().Set("X-RateLimit-Limit", strconv.Itoa(config.RequestsPerMinute))
-
 			return next(c)
 		}
 	}
 }
 
+// rateLimitKey names the counter for the client's window starting at windowStart
+func rateLimitKey(name, clientIP string, windowStart time.Time) string {
+	return fmt.Sprintf("ratelimit:%s:%s:%d", name, clientIP, windowStart.Unix())
+}
+
+// countRequest counts a request against the client's current window and
+// returns the window's count so far and when the window ends
+func countRequest(ctx context.Context, client *redis.Client, name, clientIP string, now time.Time) (int64, time.Time, error) {
+	windowStart := now.Truncate(rateLimitWindow)
+	reset := windowStart.Add(rateLimitWindow)
+	key := rateLimitKey(name, clientIP, windowStart)
+
+	pipe := client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireAt(ctx, key, reset)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return incr.Val(), reset, nil
+}
+
+// localCounts counts requests while Redis is unavailable
+var localCounts = &localCounter{counts: make(map[string]int64)}
+
+// localCounter keeps one-minute request counts in memory, the same windows
+// countRequest keeps in Redis. Counts from earlier windows are dropped as the
+// window moves on.
+type localCounter struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int64
+}
+
+// count counts a request against the client's current window and returns the
+// window's count so far and when the window ends
+func (l *localCounter) count(name, clientIP string, now time.Time) (int64, time.Time) {
+	windowStart := now.Truncate(rateLimitWindow)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !windowStart.Equal(l.windowStart) {
+		l.windowStart = windowStart
+		l.counts = make(map[string]int64)
+	}
+	key := rateLimitKey(name, clientIP, windowStart)
+	l.counts[key]++
+	return l.counts[key], windowStart.Add(rateLimitWindow)
+}
+
+// setRateLimitHeaders adds X-RateLimit-* headers describing the client's
+// budget in the current window. Limit, Remaining and Reset all come from the
+// same counter; Reset is the Unix time the window ends.
+func setRateLimitHeaders(c echo.Context, config RateLimitConfig, count int64, reset time.Time) {
+	remaining := int64(config.RequestsPerMinute) - count
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	header := c.Response().Header()
+	header.Set("X-RateLimit-Limit", strconv.Itoa(config.RequestsPerMinute))
+	header.Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+}
+
 // AuthRateLimit returns a stricter rate limit for authentication endpoints
-func AuthRateLimit() echo.MiddlewareFunc {
-	return RateLimitWithConfig(RateLimitConfig{
+func AuthRateLimit(client *redis.Client) echo.MiddlewareFunc {
+	return RateLimitWithConfig(client, RateLimitConfig{
+		Name:              "auth",
 		RequestsPerMinute: 30, // More restrictive for auth endpoints
-		BurstSize:         5,
+		SkipSuccessful:    false,
+	})
+}
+
+// CouponRateLimit returns a strict rate limit for coupon checks so codes
+// can't be guessed by trying them in bulk
+func CouponRateLimit(client *redis.Client) echo.MiddlewareFunc {
+	return RateLimitWithConfig(client, RateLimitConfig{
+		Name:              "coupon",
+		RequestsPerMinute: 10,
 		SkipSuccessful:    false,
 	})
 }
 
 // APIRateLimit returns a general rate limit for API endpoints
-func APIRateLimit() echo.MiddlewareFunc {
-	return RateLimitWithConfig(RateLimitConfig{
+func APIRateLimit(client *redis.Client) echo.MiddlewareFunc {
+	return RateLimitWithConfig(client, RateLimitConfig{
+		Name:              "api",
 		RequestsPerMinute: 100,
-		BurstSize:         20,
 		SkipSuccessful:    false,
 	})
 }
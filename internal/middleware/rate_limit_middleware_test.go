@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// With Redis down the limit must still hold, counted in memory
+func TestRateLimitCountsInMemoryWhenRedisIsDown(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0", MaxRetries: -1})
+	defer client.Close()
+
+	e := echo.New()
+	limited := RateLimitWithConfig(client, RateLimitConfig{Name: "test-offline", RequestsPerMinute: 3})(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for i := 1; i <= 4; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		rec := httptest.NewRecorder()
+		if err := limited(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("request %d returned %v", i, err)
+		}
+
+		want := http.StatusOK
+		if i > 3 {
+			want = http.StatusTooManyRequests
+		}
+		if rec.Code != want {
+			t.Errorf("request %d status = %d, want %d", i, rec.Code, want)
+		}
+	}
+}
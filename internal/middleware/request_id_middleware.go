@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// RequestID assigns each request an ID, reusing the client's X-Request-ID when
+// sent. It's echoed in the response header, picked up by the access log and
+// stored in the request context for the slow-query log.
+func RequestID() echo.MiddlewareFunc {
+	return middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, requestID string) {
+			req := c.Request()
+			c.SetRequest(req.WithContext(utils.WithRequestID(req.Context(), requestID)))
+		},
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
 )
 
 // RequireRole creates middleware that requires specific user roles
@@ -14,10 +16,7 @@ func RequireRole(allowedRoles ...models.UserRole) echo.MiddlewareFunc {
 			// Get user role from context (set by auth middleware)
 			userRole, ok := c.Get("user_role").(models.UserRole)
 			if !ok {
-				return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-					Success: false,
-					Error:   "Authentication required",
-				})
+				return utils.ErrorResponseWithCode(c, http.StatusUnauthorized, apierror.Unauthorized, "Authentication required")
 			}
 
 			// Check if user role is allowed
@@ -27,10 +26,7 @@ func RequireRole(allowedRoles ...models.UserRole) echo.MiddlewareFunc {
 				}
 			}
 
-			return c.JSON(http.StatusForbidden, models.ErrorResponse{
-				Success: false,
-				Error:   "Insufficient permissions",
-			})
+			return utils.ErrorResponseWithCode(c, http.StatusForbidden, apierror.Forbidden, "Insufficient permissions")
 		}
 	}
 }
@@ -0,0 +1,82 @@
+package models
+
+// Address is a saved address in a user's address book. A user has at most one
+// default shipping and one default billing address.
+type Address struct {
+	BaseModel
+	UserID            uint    `json:"user_id" gorm:"not null;index"`
+	Label             string  `json:"label" gorm:"type:varchar(50);not null"` // e.g. "Home", "Work"
+	FirstName         string  `json:"first_name" gorm:"type:varchar(100);not null"`
+	LastName          string  `json:"last_name" gorm:"type:varchar(100);not null"`
+	Phone             *string `json:"phone,omitempty" gorm:"type:varchar(20)"`
+	Street            string  `json:"street" gorm:"type:varchar(255);not null"`
+	City              string  `json:"city" gorm:"type:varchar(100);not null"`
+	State             string  `json:"state" gorm:"type:varchar(100);not null"`
+	Country           string  `json:"country" gorm:"type:varchar(100);not null"`
+	PostalCode        string  `json:"postal_code" gorm:"type:varchar(20);not null"`
+	IsDefaultShipping bool    `json:"is_default_shipping" gorm:"default:false"`
+	IsDefaultBilling  bool    `json:"is_default_billing" gorm:"default:false"`
+}
+
+// AddressRequest represents the request to create or replace a saved address
+type AddressRequest struct {
+	Label             string  `json:"label" validate:"required,max=50"`
+	FirstName         string  `json:"first_name" validate:"required,max=100"`
+	LastName          string  `json:"last_name" validate:"required,max=100"`
+	Phone             *string `json:"phone,omitempty" validate:"omitempty,max=20"`
+	Street            string  `json:"street" validate:"required,max=255"`
+	City              string  `json:"city" validate:"required,max=100"`
+	State             string  `json:"state" validate:"required,max=100"`
+	Country           string  `json:"country" validate:"required,max=100"`
+	PostalCode        string  `json:"postal_code" validate:"required,max=20"`
+	IsDefaultShipping bool    `json:"is_default_shipping"`
+	IsDefaultBilling  bool    `json:"is_default_billing"`
+}
+
+// Apply copies the request onto the address
+func (r *AddressRequest) Apply(address *Address) {
+	address.Label = r.Label
+	address.FirstName = r.FirstName
+	address.LastName = r.LastName
+	address.Phone = r.Phone
+	address.Street = r.Street
+	address.City = r.City
+	address.State = r.State
+	address.Country = r.Country
+	address.PostalCode = r.PostalCode
+	address.IsDefaultShipping = r.IsDefaultShipping
+	address.IsDefaultBilling = r.IsDefaultBilling
+}
+
+// CopyToShipping snapshots the address into the order's shipping fields, so
+// later edits to the address don't change the order
+func (a *Address) CopyToShipping(order *Order) {
+	order.ShippingFirstName = a.FirstName
+	order.ShippingLastName = a.LastName
+	order.ShippingPhone = copyString(a.Phone)
+	order.ShippingStreet = a.Street
+	order.ShippingCity = a.City
+	order.ShippingState = a.State
+	order.ShippingCountry = a.Country
+	order.ShippingPostalCode = a.PostalCode
+}
+
+// CopyToBilling snapshots the address into the order's billing fields
+func (a *Address) CopyToBilling(order *Order) {
+	order.BillingFirstName = copyString(&a.FirstName)
+	order.BillingLastName = copyString(&a.LastName)
+	order.BillingPhone = copyString(a.Phone)
+	order.BillingStreet = copyString(&a.Street)
+	order.BillingCity = copyString(&a.City)
+	order.BillingState = copyString(&a.State)
+	order.BillingCountry = copyString(&a.Country)
+	order.BillingPostalCode = copyString(&a.PostalCode)
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := *s
+	return &v
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 )
 
@@ -31,6 +32,49 @@ type SalesAnalytics struct {
 	DailyBreakdown   []DailySales   `json:"daily_breakdown,omitempty"`
 	WeeklyBreakdown  []WeeklySales  `json:"weekly_breakdown,omitempty"`
 	MonthlyBreakdown []MonthlySales `json:"monthly_breakdown,omitempty"`
+	Comparison       *PeriodComparison `json:"comparison,omitempty"` // Set with compare=true
+}
+
+// Period comparison
+// Delivered-order sales in a period against the immediately preceding period
+// of the same length. A change is nil when the previous period had nothing to
+// compare against.
+type PeriodSales struct {
+	StartDate         time.Time `json:"start_date"`
+	EndDate           time.Time `json:"end_date"`
+	Revenue           float64   `json:"revenue"`
+	Orders            int64     `json:"orders"`
+	AverageOrderValue float64   `json:"average_order_value"`
+}
+
+type PeriodComparison struct {
+	Current                        PeriodSales `json:"current"`
+	Previous                       PeriodSales `json:"previous"`
+	RevenueChangePercent           *float64    `json:"revenue_change_percent"`
+	OrdersChangePercent            *float64    `json:"orders_change_percent"`
+	AverageOrderValueChangePercent *float64    `json:"average_order_value_change_percent"`
+}
+
+// NewPeriodComparison works out the percentage changes from previous to current
+func NewPeriodComparison(current, previous PeriodSales) *PeriodComparison {
+	return &PeriodComparison{
+		Current:                        current,
+		Previous:                       previous,
+		RevenueChangePercent:           percentChange(current.Revenue, previous.Revenue),
+		OrdersChangePercent:            percentChange(float64(current.Orders), float64(previous.Orders)),
+		AverageOrderValueChangePercent: percentChange(current.AverageOrderValue, previous.AverageOrderValue),
+	}
+}
+
+// percentChange returns the change from previous to current as a percentage,
+// or nil when previous is zero
+func percentChange(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	// A percentage, not money, so it keeps two decimals whatever the currency
+	change := math.Round((current-previous)/previous*10000) / 100
+	return &change
 }
 
 type DailySales struct {
@@ -71,6 +115,37 @@ type ProductAnalytics struct {
 	TopRatedProducts   []*Product  `json:"top_rated_products"`
 }
 
+// Cancellation analytics
+type CancellationAnalytics struct {
+	StartDate      time.Time                 `json:"start_date"`
+	EndDate        time.Time                 `json:"end_date"`
+	TotalCancelled int64                     `json:"total_cancelled"`
+	ByReason       []CancellationReasonCount `json:"by_reason"`
+}
+
+type CancellationReasonCount struct {
+	Reason CancellationReason `json:"reason"`
+	Count  int64              `json:"count"`
+}
+
+// Best-selling products
+type BestSellersReport struct {
+	StartDate time.Time    `json:"start_date"`
+	EndDate   time.Time    `json:"end_date"`
+	Products  []BestSeller `json:"products"`
+}
+
+// BestSeller is a product's sales over a report's date range. Name and SKU
+// are as recorded on its most recent order item.
+type BestSeller struct {
+	ProductID   uint    `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	ProductSKU  string  `json:"product_sku"`
+	UnitsSold   int64   `json:"units_sold"`
+	OrderCount  int64   `json:"order_count"`
+	Revenue     float64 `json:"revenue"`
+}
+
 // Review analytics
 type ReviewAnalytics struct {
 	TotalReviews   int64     `json:"total_reviews"`
@@ -94,3 +169,59 @@ type AdminUserUpdateRequest struct {
 	IsActive  *bool     `json:"is_active,omitempty"`
 	IsVerified *bool    `json:"is_verified,omitempty"`
 }
+
+// Inventory valuation
+// Products without a cost price are excluded from cost totals and counted in
+// ProductsWithoutCost; they still count towards retail value.
+type InventoryValuation struct {
+	SellerID            uint                         `json:"seller_id"`
+	TotalUnits          int64                        `json:"total_units"`
+	TotalCostValue      float64                      `json:"total_cost_value"`
+	TotalRetailValue    float64                      `json:"total_retail_value"`
+	ProductsWithoutCost int64                        `json:"products_without_cost"`
+	OversoldProducts    int64                        `json:"oversold_products"` // Products whose stock is below zero
+	OversoldUnits       int64                        `json:"oversold_units"`    // Units sold beyond stock, owed to customers
+	ByCategory          []CategoryInventoryValuation `json:"by_category"`
+}
+
+type CategoryInventoryValuation struct {
+	Category            string  `json:"category"`
+	ProductCount        int64   `json:"product_count"`
+	TotalUnits          int64   `json:"total_units"`
+	CostValue           float64 `json:"cost_value"`
+	RetailValue         float64 `json:"retail_value"`
+	ProductsWithoutCost int64   `json:"products_without_cost"`
+	OversoldProducts    int64   `json:"oversold_products"`
+	OversoldUnits       int64   `json:"oversold_units"`
+}
+
+// Inventory alerts
+// Products at or below their low stock level, most urgent first. Sales velocity
+// counts units ordered over the last VelocityWindowDays, excluding cancelled and
+// refunded orders.
+type InventoryAlerts struct {
+	SellerID           uint             `json:"seller_id"`
+	VelocityWindowDays int              `json:"velocity_window_days"`
+	Alerts             []InventoryAlert `json:"alerts"`
+}
+
+type InventoryAlert struct {
+	ProductID                uint     `json:"product_id"`
+	Name                     string   `json:"name"`
+	SKU                      string   `json:"sku"`
+	Stock                    int      `json:"stock"`
+	LowStockLevel            int      `json:"low_stock_level"`
+	UnitsSold                int      `json:"units_sold"`
+	DailySalesRate           float64  `json:"daily_sales_rate"`
+	DaysOfStockRemaining     *float64 `json:"days_of_stock_remaining"` // Nil when the product had no recent sales
+	SuggestedReorderQuantity int      `json:"suggested_reorder_quantity"`
+}
+
+// Cache stats
+// Hit and miss counts of one cache since the instance started
+type CacheStats struct {
+	Name    string  `json:"name"`
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditLog records an administrative action taken on an entity
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ActorID    uint      `json:"actor_id" gorm:"not null;index"`
+	Action     string    `json:"action" gorm:"type:varchar(50);not null"`
+	EntityType string    `json:"entity_type" gorm:"type:varchar(50);not null;index:idx_audit_logs_entity"`
+	EntityID   uint      `json:"entity_id" gorm:"not null;index:idx_audit_logs_entity"`
+	Details    *string   `json:"details,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// Conversation is a private thread between a customer and a seller, optionally
+// about one of the seller's products or one of the customer's orders. Only
+// the two participants and admins can read it; platform issues go through
+// support tickets instead.
+type Conversation struct {
+	BaseModel
+	CustomerID    uint                  `json:"customer_id" gorm:"not null;index"`
+	SellerID      uint                  `json:"seller_id" gorm:"not null;index"`
+	ProductID     *uint                 `json:"product_id,omitempty" gorm:"index"`
+	OrderID       *uint                 `json:"order_id,omitempty" gorm:"index"`
+	Subject       string                `json:"subject" gorm:"type:varchar(255)"` // Snapshot of the product name or order number
+	LastMessageAt time.Time             `json:"last_message_at" gorm:"not null;index"`
+	Messages      []ConversationMessage `json:"messages,omitempty" gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE"`
+}
+
+// ConversationMessage is one message in a conversation
+type ConversationMessage struct {
+	BaseModel
+	ConversationID uint   `json:"conversation_id" gorm:"not null;index"`
+	SenderID       uint   `json:"sender_id" gorm:"not null"`
+	Body           string `json:"body" gorm:"type:text;not null"`
+}
+
+// ContactSellerRequest represents a customer's first message to a seller
+type ContactSellerRequest struct {
+	ProductID *uint  `json:"product_id,omitempty"`
+	OrderID   *uint  `json:"order_id,omitempty"`
+	Body      string `json:"body" validate:"required,min=2,max=5000"`
+}
+
+// ConversationMessageRequest represents a reply in a conversation
+type ConversationMessageRequest struct {
+	Body string `json:"body" validate:"required,min=2,max=5000"`
+}
+
+// HasParticipant checks whether the user is the conversation's customer or seller
+func (c *Conversation) HasParticipant(userID uint) bool {
+	return c.CustomerID == userID || c.SellerID == userID
+}
+
+// OtherParticipant returns the participant the sender is writing to
+func (c *Conversation) OtherParticipant(senderID uint) uint {
+	if senderID == c.CustomerID {
+		return c.SellerID
+	}
+	return c.CustomerID
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
+)
+
+// CouponType is how a coupon's value is applied
+type CouponType string
+
+const (
+	CouponTypePercent CouponType = "percent" // Value is a percentage of the subtotal
+	CouponTypeFixed   CouponType = "fixed"   // Value is an amount off the subtotal
+)
+
+// Coupon stacking policies for orders that also qualify for a promotion
+const (
+	CouponStackingStack = "stack" // The coupon applies on top of the promotion
+	CouponStackingBest  = "best"  // Only the larger of the two discounts applies
+)
+
+// Coupon is a code customers enter at checkout for a discount. Untargeted
+// coupons discount the whole order; a coupon with a ProductID or CategoryID
+// only discounts the matching items, as line discounts. UsedCount only counts
+// paid orders; checkouts in progress hold a use in Redis until they're paid or
+// released.
+type Coupon struct {
+	BaseModel
+	Code       string     `json:"code" gorm:"type:varchar(50);not null;uniqueIndex:idx_coupons_code_unique,where:deleted_at IS NULL"`
+	Type       CouponType `json:"type" gorm:"type:varchar(20);not null"`
+	Value      float64    `json:"value" gorm:"type:decimal(10,2);not null"`
+	MinSpend   float64    `json:"min_spend" gorm:"type:decimal(10,2);default:0"`
+	UsageLimit *int       `json:"usage_limit,omitempty"` // Nil allows unlimited uses
+	UsedCount  int        `json:"used_count" gorm:"not null;default:0"`
+	IsActive   bool       `json:"is_active" gorm:"not null"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedBy  uint       `json:"created_by" gorm:"not null"`
+
+	// Targeting; with both set an item must match both
+	ProductID  *uint `json:"product_id,omitempty" gorm:"index"`
+	CategoryID *uint `json:"category_id,omitempty" gorm:"index"`
+
+	// Only this customer can use the coupon, e.g. their welcome coupon
+	UserID *uint `json:"user_id,omitempty" gorm:"index"`
+}
+
+// CreateCouponRequest represents the request to create a coupon
+type CreateCouponRequest struct {
+	Code       string     `json:"code" validate:"required,min=3,max=50,alphanum"`
+	Type       CouponType `json:"type" validate:"required,oneof=percent fixed"`
+	Value      float64    `json:"value" validate:"required,gt=0"`
+	MinSpend   float64    `json:"min_spend" validate:"min=0"`
+	UsageLimit *int       `json:"usage_limit,omitempty" validate:"omitempty,min=1"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	ProductID  *uint      `json:"product_id,omitempty"`  // Only discount this product
+	CategoryID *uint      `json:"category_id,omitempty"` // Only discount products in this category
+}
+
+// CouponValidateRequest represents the request to check a coupon against the cart
+type CouponValidateRequest struct {
+	Code string `json:"code" validate:"required,max=50"`
+}
+
+// CouponPreview is what a coupon would do to the cart if applied at checkout.
+// Previewing doesn't hold one of the coupon's uses, so a valid preview can
+// still be refused at checkout if the last use is taken in between.
+type CouponPreview struct {
+	Code              string  `json:"code"`
+	Valid             bool    `json:"valid"`
+	Reason            string  `json:"reason,omitempty"` // Why the coupon can't be used, when not valid
+	Subtotal          float64 `json:"subtotal"`
+	PromotionDiscount float64 `json:"promotion_discount"` // Dropped when only the better discount applies and the coupon wins
+	Discount          float64 `json:"discount"`           // The coupon's own discount
+	Total             float64 `json:"total"`              // Subtotal after every discount, before shipping and tax
+}
+
+// Discount returns the coupon's discount on a subtotal, never more than the subtotal
+func (c *Coupon) Discount(subtotal float64) float64 {
+	amount := money.FromFloat(subtotal)
+	discount := money.FromFloat(c.Value)
+	if c.Type == CouponTypePercent {
+		discount = amount.Percent(c.Value)
+	}
+	return discount.Clamp(amount).Float()
+}
+
+// Describe describes the coupon's discount for customers, e.g. "10% off"
+func (c *Coupon) Describe() string {
+	if c.Type == CouponTypePercent {
+		return strconv.FormatFloat(c.Value, 'f', -1, 64) + "% off"
+	}
+	return money.Format(c.Value) + " off"
+}
+
+// UsableBy reports whether the customer may use the coupon
+func (c *Coupon) UsableBy(userID uint) bool {
+	return c.UserID == nil || *c.UserID == userID
+}
+
+// Targeted reports whether the coupon only discounts some items
+func (c *Coupon) Targeted() bool {
+	return c.ProductID != nil || c.CategoryID != nil
+}
+
+// Matches reports whether the coupon discounts a product in categoryID
+func (c *Coupon) Matches(productID uint, categoryID *uint) bool {
+	if c.ProductID != nil && *c.ProductID != productID {
+		return false
+	}
+	if c.CategoryID != nil && (categoryID == nil || *c.CategoryID != *categoryID) {
+		return false
+	}
+	return true
+}
+
+// EligibleSubtotal returns the total of the lines the coupon discounts: all of
+// them for an untargeted coupon
+func (c *Coupon) EligibleSubtotal(lines []PromotionLine) float64 {
+	var subtotal money.Amount
+	for _, line := range lines {
+		if c.Matches(line.ProductID, line.CategoryID) {
+			subtotal += money.FromFloat(line.LineTotal)
+		}
+	}
+	return subtotal.Float()
+}
+
+// IsExpired reports whether the coupon has expired at t
+func (c *Coupon) IsExpired(t time.Time) bool {
+	return c.ExpiresAt != nil && !t.Before(*c.ExpiresAt)
+}
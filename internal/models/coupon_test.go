@@ -0,0 +1,133 @@
+package models
+
+import "testing"
+
+func uintPtr(v uint) *uint { return &v }
+
+// mixedCart has two products in category 1 and one in category 2
+func mixedCart() (*Order, map[uint]*uint) {
+	order := &Order{
+		OrderItems: []OrderItem{
+			{ProductID: 1, Quantity: 2, UnitPrice: 10, TotalPrice: 20},
+			{ProductID: 2, Quantity: 1, UnitPrice: 30, TotalPrice: 30},
+			{ProductID: 3, Quantity: 1, UnitPrice: 50, TotalPrice: 50},
+		},
+	}
+	order.CalculateTotals()
+	categories := map[uint]*uint{1: uintPtr(1), 2: uintPtr(1), 3: uintPtr(2)}
+	return order, categories
+}
+
+func applyCoupon(order *Order, coupon *Coupon, categories map[uint]*uint) float64 {
+	var lines []PromotionLine
+	for _, item := range order.OrderItems {
+		lines = append(lines, PromotionLine{ProductID: item.ProductID, CategoryID: categories[item.ProductID], LineTotal: item.LineTotal()})
+	}
+	discount := coupon.Discount(coupon.EligibleSubtotal(lines))
+	return order.ApplyItemDiscount(func(item *OrderItem) bool {
+		return coupon.Matches(item.ProductID, categories[item.ProductID])
+	}, discount)
+}
+
+func TestTargetedPercentCouponDiscountsOnlyMatchingItems(t *testing.T) {
+	order, categories := mixedCart()
+	coupon := &Coupon{Type: CouponTypePercent, Value: 10, CategoryID: uintPtr(1)}
+
+	applied := applyCoupon(order, coupon, categories)
+
+	if applied != 5 {
+		t.Fatalf("applied discount = %v, want 5", applied)
+	}
+	wantLines := []float64{2, 3, 0}
+	for i, want := range wantLines {
+		if got := order.OrderItems[i].DiscountAmount; got != want {
+			t.Errorf("item %d discount = %v, want %v", i, got, want)
+		}
+	}
+	if order.SubtotalAmount != 95 || order.TotalAmount != 95 {
+		t.Errorf("subtotal/total = %v/%v, want 95/95", order.SubtotalAmount, order.TotalAmount)
+	}
+	if order.DiscountAmount != 0 {
+		t.Errorf("order-level discount = %v, want 0", order.DiscountAmount)
+	}
+}
+
+func TestTargetedFixedCouponIsSplitAcrossMatchingItems(t *testing.T) {
+	order, categories := mixedCart()
+	coupon := &Coupon{Type: CouponTypeFixed, Value: 10, CategoryID: uintPtr(1)}
+
+	applied := applyCoupon(order, coupon, categories)
+
+	if applied != 10 {
+		t.Fatalf("applied discount = %v, want 10", applied)
+	}
+	// 20 and 30 of the 50 eligible
+	if order.OrderItems[0].DiscountAmount != 4 || order.OrderItems[1].DiscountAmount != 6 {
+		t.Errorf("line discounts = %v, %v, want 4, 6", order.OrderItems[0].DiscountAmount, order.OrderItems[1].DiscountAmount)
+	}
+	if order.OrderItems[2].DiscountAmount != 0 {
+		t.Errorf("unmatched item discounted by %v", order.OrderItems[2].DiscountAmount)
+	}
+	if order.SubtotalAmount != 90 {
+		t.Errorf("subtotal = %v, want 90", order.SubtotalAmount)
+	}
+}
+
+func TestTargetedFixedCouponNeverExceedsMatchingItems(t *testing.T) {
+	order, categories := mixedCart()
+	coupon := &Coupon{Type: CouponTypeFixed, Value: 100, ProductID: uintPtr(1)}
+
+	applied := applyCoupon(order, coupon, categories)
+
+	if applied != 20 {
+		t.Fatalf("applied discount = %v, want 20", applied)
+	}
+	if order.SubtotalAmount != 80 {
+		t.Errorf("subtotal = %v, want 80", order.SubtotalAmount)
+	}
+}
+
+func TestItemDiscountRoundingAddsUpToCouponDiscount(t *testing.T) {
+	order := &Order{
+		OrderItems: []OrderItem{
+			{ProductID: 1, Quantity: 1, UnitPrice: 10, TotalPrice: 10},
+			{ProductID: 2, Quantity: 1, UnitPrice: 10, TotalPrice: 10},
+			{ProductID: 3, Quantity: 1, UnitPrice: 10, TotalPrice: 10},
+		},
+	}
+	order.CalculateTotals()
+
+	applied := order.ApplyItemDiscount(func(*OrderItem) bool { return true }, 10)
+
+	var sum float64
+	for _, item := range order.OrderItems {
+		sum += item.DiscountAmount
+	}
+	if applied != 10 || order.SubtotalAmount != 20 {
+		t.Errorf("applied %v, subtotal %v, want 10 and 20 (line discounts sum to %v)", applied, order.SubtotalAmount, sum)
+	}
+}
+
+func TestCouponMatches(t *testing.T) {
+	both := &Coupon{ProductID: uintPtr(1), CategoryID: uintPtr(2)}
+	tests := []struct {
+		name      string
+		coupon    *Coupon
+		productID uint
+		category  *uint
+		want      bool
+	}{
+		{"untargeted", &Coupon{}, 9, nil, true},
+		{"product match", &Coupon{ProductID: uintPtr(1)}, 1, nil, true},
+		{"product mismatch", &Coupon{ProductID: uintPtr(1)}, 2, nil, false},
+		{"category match", &Coupon{CategoryID: uintPtr(2)}, 5, uintPtr(2), true},
+		{"uncategorized product", &Coupon{CategoryID: uintPtr(2)}, 5, nil, false},
+		{"both must match", both, 1, uintPtr(3), false},
+		{"both match", both, 1, uintPtr(2), true},
+	}
+	for _, tt := range tests {
+		if got := tt.coupon.Matches(tt.productID, tt.category); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
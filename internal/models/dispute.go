@@ -0,0 +1,83 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// DisputeStatus mirrors the payment provider's dispute status
+type DisputeStatus string
+
+const (
+	DisputeStatusWarningNeedsResponse DisputeStatus = "warning_needs_response"
+	DisputeStatusWarningUnderReview   DisputeStatus = "warning_under_review"
+	DisputeStatusWarningClosed        DisputeStatus = "warning_closed"
+	DisputeStatusNeedsResponse        DisputeStatus = "needs_response"
+	DisputeStatusUnderReview          DisputeStatus = "under_review"
+	DisputeStatusWon                  DisputeStatus = "won"
+	DisputeStatusLost                 DisputeStatus = "lost"
+)
+
+// Dispute tracks a chargeback raised against an order's payment
+type Dispute struct {
+	BaseModel
+	OrderID         uint          `json:"order_id" gorm:"not null;index"`
+	StripeDisputeID string        `json:"stripe_dispute_id" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Amount          float64       `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Currency        string        `json:"currency" gorm:"type:varchar(3)"`
+	Reason          string        `json:"reason" gorm:"type:varchar(50)"`
+	Status          DisputeStatus `json:"status" gorm:"type:varchar(30);not null;index"`
+	EvidenceDueBy   *time.Time    `json:"evidence_due_by,omitempty"`
+
+	// Evidence metadata recorded by admins
+	EvidenceNotes       *string    `json:"evidence_notes,omitempty" gorm:"type:text"`
+	EvidenceDocuments   *string    `json:"-" gorm:"type:text"` // Newline-separated document URLs, see GetEvidenceDocuments
+	EvidenceSubmittedAt *time.Time `json:"evidence_submitted_at,omitempty"`
+	EvidenceSubmittedBy *uint      `json:"evidence_submitted_by,omitempty"`
+
+	// Relationships
+	Order Order `json:"order,omitempty" gorm:"foreignKey:OrderID"`
+}
+
+// DisputeEvidenceRequest represents the evidence metadata an admin submits for a dispute
+type DisputeEvidenceRequest struct {
+	Notes        string   `json:"notes" validate:"required,min=10,max=5000"`
+	DocumentURLs []string `json:"document_urls,omitempty" validate:"omitempty,max=20,dive,url,max=500"`
+}
+
+// DisputeResponse represents a dispute with its evidence documents
+type DisputeResponse struct {
+	*Dispute
+	EvidenceDocuments []string `json:"evidence_documents"`
+}
+
+// IsOpen checks if the dispute is still unresolved
+func (d *Dispute) IsOpen() bool {
+	switch d.Status {
+	case DisputeStatusWon, DisputeStatusLost, DisputeStatusWarningClosed:
+		return false
+	}
+	return true
+}
+
+// GetEvidenceDocuments returns the evidence document URLs as a slice
+func (d *Dispute) GetEvidenceDocuments() []string {
+	if d.EvidenceDocuments == nil || *d.EvidenceDocuments == "" {
+		return []string{}
+	}
+	return strings.Split(*d.EvidenceDocuments, "\n")
+}
+
+// SetEvidenceDocuments sets the evidence document URLs from a slice
+func (d *Dispute) SetEvidenceDocuments(urls []string) {
+	joined := strings.Join(urls, "\n")
+	d.EvidenceDocuments = &joined
+}
+
+// ToResponse converts Dispute to DisputeResponse
+func (d *Dispute) ToResponse() DisputeResponse {
+	return DisputeResponse{
+		Dispute:           d,
+		EvidenceDocuments: d.GetEvidenceDocuments(),
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+)
+
+// EmailBroadcastAudience is who an email broadcast goes to
+type EmailBroadcastAudience string
+
+const (
+	EmailAudienceAll       EmailBroadcastAudience = "all"
+	EmailAudienceCustomers EmailBroadcastAudience = "customers"
+	EmailAudienceSellers   EmailBroadcastAudience = "sellers"
+)
+
+// Roles returns the user roles the audience covers
+func (a EmailBroadcastAudience) Roles() []UserRole {
+	switch a {
+	case EmailAudienceCustomers:
+		return []UserRole{RoleCustomer}
+	case EmailAudienceSellers:
+		return []UserRole{RoleSeller}
+	default:
+		return []UserRole{RoleCustomer, RoleSeller, RoleAdmin}
+	}
+}
+
+// EmailDeliveryStatus is where a single recipient's email stands
+type EmailDeliveryStatus string
+
+const (
+	EmailDeliveryPending EmailDeliveryStatus = "pending"
+	EmailDeliverySent    EmailDeliveryStatus = "sent"
+	EmailDeliveryFailed  EmailDeliveryStatus = "failed" // Gave up after the maximum attempts
+)
+
+// EmailBroadcast is an admin email to every active user in an audience. Each
+// recipient gets an EmailDelivery, which the email dispatcher works through
+// at the configured send rate.
+type EmailBroadcast struct {
+	BaseModel
+	Subject         string                 `json:"subject" gorm:"type:varchar(255);not null"`
+	Body            string                 `json:"body" gorm:"type:text;not null"`
+	Audience        EmailBroadcastAudience `json:"audience" gorm:"type:varchar(20);not null"`
+	TotalRecipients int                    `json:"total_recipients" gorm:"default:0"`
+	CreatedBy       uint                   `json:"created_by" gorm:"not null"`
+}
+
+// EmailDelivery is one recipient's copy of a broadcast or recall notice and
+// its delivery result. Pending deliveries are picked up once NextAttemptAt
+// passes.
+type EmailDelivery struct {
+	ID            uint                `json:"id" gorm:"primaryKey"`
+	BroadcastID   *uint               `json:"broadcast_id,omitempty" gorm:"index"`
+	RecallID      *uint               `json:"recall_id,omitempty" gorm:"index"`
+	UserID        uint                `json:"user_id" gorm:"not null"`
+	Email         string              `json:"email" gorm:"type:varchar(255);not null"`
+	Name          string              `json:"name" gorm:"type:varchar(100)"`
+	Status        EmailDeliveryStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Attempts      int                 `json:"attempts" gorm:"default:0"`
+	LastError     *string             `json:"last_error,omitempty" gorm:"type:text"`
+	NextAttemptAt time.Time           `json:"next_attempt_at" gorm:"not null"`
+	SentAt        *time.Time          `json:"sent_at,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+
+	Broadcast *EmailBroadcast `json:"-" gorm:"foreignKey:BroadcastID"`
+	Recall    *ProductRecall  `json:"-" gorm:"foreignKey:RecallID"`
+}
+
+// EmailBroadcastRequest represents the request to send an email broadcast
+type EmailBroadcastRequest struct {
+	Subject  string                 `json:"subject" validate:"required,min=2,max=255"`
+	Body     string                 `json:"body" validate:"required,min=2,max=20000"`
+	Audience EmailBroadcastAudience `json:"audience" validate:"required,oneof=all customers sellers"`
+}
+
+// EmailBroadcastStatus summarizes where a broadcast's sending stands
+type EmailBroadcastStatus string
+
+const (
+	EmailBroadcastQueued    EmailBroadcastStatus = "queued"
+	EmailBroadcastSending   EmailBroadcastStatus = "sending"
+	EmailBroadcastCompleted EmailBroadcastStatus = "completed"
+)
+
+// EmailBroadcastProgress is a broadcast with its delivery counts so far
+type EmailBroadcastProgress struct {
+	EmailBroadcast
+	Status          EmailBroadcastStatus `json:"status"`
+	Pending         int64                `json:"pending"`
+	Sent            int64                `json:"sent"`
+	Failed          int64                `json:"failed"`
+	PercentComplete float64              `json:"percent_complete"`
+}
+
+// EmailDeliveryCounts holds a broadcast's delivery counts by status
+type EmailDeliveryCounts struct {
+	Pending int64
+	Sent    int64
+	Failed  int64
+}
+
+// NewEmailBroadcastProgress works out the broadcast's status from its counts
+func NewEmailBroadcastProgress(broadcast *EmailBroadcast, counts EmailDeliveryCounts) *EmailBroadcastProgress {
+	progress := &EmailBroadcastProgress{
+		EmailBroadcast:  *broadcast,
+		Status:          EmailBroadcastSending,
+		Pending:         counts.Pending,
+		Sent:            counts.Sent,
+		Failed:          counts.Failed,
+		PercentComplete: 100,
+	}
+
+	done := counts.Sent + counts.Failed
+	if total := done + counts.Pending; total > 0 {
+		progress.PercentComplete = float64(int(float64(done)/float64(total)*10000)) / 100
+	}
+
+	switch {
+	case counts.Pending == 0:
+		progress.Status = EmailBroadcastCompleted
+	case done == 0:
+		progress.Status = EmailBroadcastQueued
+	}
+	return progress
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// FeaturedSeller is an admin-curated seller promoted on the homepage
+type FeaturedSeller struct {
+	BaseModel
+	SellerID  uint       `json:"seller_id" gorm:"not null;uniqueIndex"`
+	Seller    User       `json:"seller,omitempty" gorm:"foreignKey:SellerID"`
+	Position  int        `json:"position" gorm:"not null;default:0"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index"` // Nil keeps the seller featured until removed
+	CreatedBy uint       `json:"created_by" gorm:"not null"`
+}
+
+// FeatureSellerRequest represents the request to feature a seller
+type FeatureSellerRequest struct {
+	SellerID  uint       `json:"seller_id" validate:"required"`
+	Position  *int       `json:"position,omitempty" validate:"omitempty,min=0"` // Defaults to the end of the list
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ReorderFeaturedSellersRequest represents the new featured seller order
+type ReorderFeaturedSellersRequest struct {
+	SellerIDs []uint `json:"seller_ids" validate:"required,min=1,dive,required"`
+}
+
+// SellerPublicProfile is the public view of a seller's storefront
+type SellerPublicProfile struct {
+	ID               uint      `json:"id"`
+	StoreName        string    `json:"store_name"`
+	StoreDescription *string   `json:"store_description,omitempty"`
+	Avatar           *string   `json:"avatar,omitempty"`
+	MemberSince      time.Time `json:"member_since"`
+}
+
+// ToSellerPublicProfile converts a seller to their public profile, falling back
+// to the seller's name when no store name is set
+func (u *User) ToSellerPublicProfile() SellerPublicProfile {
+	storeName := u.FirstName + " " + u.LastName
+	if u.StoreName != nil && *u.StoreName != "" {
+		storeName = *u.StoreName
+	}
+
+	return SellerPublicProfile{
+		ID:               u.ID,
+		StoreName:        storeName,
+		StoreDescription: u.StoreDescription,
+		Avatar:           u.Avatar,
+		MemberSince:      u.CreatedAt,
+	}
+}
+
+// IsActive checks if the featured slot hasn't expired
+func (f *FeaturedSeller) IsActive() bool {
+	return f.ExpiresAt == nil || f.ExpiresAt.After(time.Now())
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AltTextIssue is why an image's alt text doesn't describe the image
+type AltTextIssue string
+
+const (
+	AltTextMissing       AltTextIssue = "missing"
+	AltTextAutoGenerated AltTextIssue = "auto_generated" // Filled in from the product name, see DefaultAltText
+	AltTextGeneric       AltTextIssue = "generic"        // A placeholder such as "image" or "photo"
+	AltTextFileName      AltTextIssue = "file_name"
+)
+
+// GenericAltTexts are placeholders that say nothing about the image. Compared
+// lower case after trimming.
+var GenericAltTexts = []string{
+	"image", "img", "photo", "picture", "pic", "product", "product image",
+	"thumbnail", "untitled", "alt", "alt text", "placeholder", "-", ".",
+}
+
+// AltTextFileNamePattern matches alt text that is just a file name, like
+// "IMG_1234" or "front.jpg". It is used case-insensitively in Go and SQL.
+const AltTextFileNamePattern = `(\.(jpe?g|png|gif|webp|avif|bmp|svg)$)|(^(img|dsc|image|photo)[_ -]?[0-9]+$)`
+
+var altTextFileName = regexp.MustCompile("(?i)" + AltTextFileNamePattern)
+
+// DefaultAltText is the alt text given to the position'th (from 1) image of a
+// product added without any
+func DefaultAltText(productName string, position int) string {
+	suffix := fmt.Sprintf(" - image %d", position)
+	name := []rune(strings.TrimSpace(productName))
+	if max := 255 - len(suffix); len(name) > max {
+		name = name[:max]
+	}
+	return string(name) + suffix
+}
+
+// AltTextIssue returns what's wrong with the image's alt text, or "" if it
+// looks meaningful
+func (i *ProductImage) AltTextIssue() AltTextIssue {
+	alt := strings.ToLower(strings.TrimSpace(i.AltText))
+	switch {
+	case alt == "":
+		return AltTextMissing
+	case i.AltTextGenerated:
+		return AltTextAutoGenerated
+	case altTextFileName.MatchString(alt):
+		return AltTextFileName
+	}
+	for _, generic := range GenericAltTexts {
+		if alt == generic {
+			return AltTextGeneric
+		}
+	}
+	return ""
+}
+
+// AltTextWarning flags an image in a response whose alt text isn't meaningful
+type AltTextWarning struct {
+	Index   int          `json:"index"` // Position of the image in the response
+	ImageID uint         `json:"image_id"`
+	AltText string       `json:"alt_text"`
+	Issue   AltTextIssue `json:"issue"`
+}
+
+// AltTextWarnings checks each image's alt text
+func AltTextWarnings(images []ProductImage) []AltTextWarning {
+	var warnings []AltTextWarning
+	for i := range images {
+		if issue := images[i].AltTextIssue(); issue != "" {
+			warnings = append(warnings, AltTextWarning{
+				Index:   i,
+				ImageID: images[i].ID,
+				AltText: images[i].AltText,
+				Issue:   issue,
+			})
+		}
+	}
+	return warnings
+}
+
+// ProductAccessibility is a product in a seller's accessibility report: it
+// has no images, or images without meaningful alt text
+type ProductAccessibility struct {
+	ProductID uint   `json:"product_id"`
+	Name      string `json:"name"`
+	SKU       string `json:"sku"`
+
+	ImageCount              int `json:"image_count"`
+	MissingAltTextCount     int `json:"missing_alt_text_count"`     // Blank alt text
+	GeneratedAltTextCount   int `json:"generated_alt_text_count"`   // Filled in with DefaultAltText
+	PlaceholderAltTextCount int `json:"placeholder_alt_text_count"` // Generic words or file names
+
+	NoImages bool `json:"no_images"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// LocaleInfo represents the currency and tax region suggestions for a request
+type LocaleInfo struct {
+	Country   string `json:"country"`
+	Currency  string `json:"currency"`
+	Locale    string `json:"locale"`
+	TaxRegion string `json:"tax_region"`
+	Detected  bool   `json:"detected"` // true when the country came from IP geolocation
+}
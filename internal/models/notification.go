@@ -12,10 +12,21 @@ const (
 	NotificationTypeOrderUpdated   NotificationType = "order_updated"
 	NotificationTypeOrderShipped   NotificationType = "order_shipped"
 	NotificationTypeOrderDelivered NotificationType = "order_delivered"
+	NotificationTypeOrderSLABreach NotificationType = "order_sla_breach"
+	NotificationTypeOrderAssigned  NotificationType = "order_assigned"
 	NotificationTypeProductLowStock NotificationType = "product_low_stock"
+	NotificationTypeProductOversold NotificationType = "product_oversold" // High priority: an order took stock below zero
 	NotificationTypeReviewReceived NotificationType = "review_received"
+	NotificationTypeQuestionAnswered NotificationType = "question_answered"
+	NotificationTypeProductRecall  NotificationType = "product_recall"
+	NotificationTypeProductApproval NotificationType = "product_approval"
+	NotificationTypePaymentDisputed NotificationType = "payment_disputed"
+	NotificationTypeSupportTicket  NotificationType = "support_ticket"
+	NotificationTypeSupportReply   NotificationType = "support_reply"
+	NotificationTypeNewMessage     NotificationType = "new_message"
 	NotificationTypePasswordReset  NotificationType = "password_reset"
 	NotificationTypeEmailVerified  NotificationType = "email_verified"
+	NotificationTypeAccountStatus  NotificationType = "account_status"
 	NotificationTypeGeneral        NotificationType = "general"
 )
 
@@ -2,20 +2,27 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
 )
 
+const fraudReasonSeparator = "; "
+
 // OrderStatus represents order status
 type OrderStatus string
 
 const (
-	OrderStatusPending    OrderStatus = "pending"
-	OrderStatusConfirmed  OrderStatus = "confirmed"
-	OrderStatusProcessing OrderStatus = "processing"
-	OrderStatusShipped    OrderStatus = "shipped"
-	OrderStatusDelivered  OrderStatus = "delivered"
-	OrderStatusCancelled  OrderStatus = "cancelled"
-	OrderStatusRefunded   OrderStatus = "refunded"
+	OrderStatusPending              OrderStatus = "pending"
+	OrderStatusPendingReview        OrderStatus = "pending_review"
+	OrderStatusAwaitingConfirmation OrderStatus = "awaiting_confirmation" // Paid, waiting for a merchant to confirm it when auto-confirmation is off
+	OrderStatusConfirmed            OrderStatus = "confirmed"
+	OrderStatusProcessing           OrderStatus = "processing"
+	OrderStatusShipped              OrderStatus = "shipped"
+	OrderStatusDelivered            OrderStatus = "delivered"
+	OrderStatusCancelled            OrderStatus = "cancelled"
+	OrderStatusRefunded             OrderStatus = "refunded"
 )
 
 // PaymentStatus represents payment status
@@ -27,6 +34,7 @@ const (
 	PaymentStatusFailed    PaymentStatus = "failed"
 	PaymentStatusRefunded  PaymentStatus = "refunded"
 	PaymentStatusCancelled PaymentStatus = "cancelled"
+	PaymentStatusDisputed  PaymentStatus = "disputed" // A chargeback is open; refunds are blocked until it closes
 )
 
 // PaymentMethod represents payment methods
@@ -39,6 +47,19 @@ const (
 	PaymentMethodCash   PaymentMethod = "cash_on_delivery"
 )
 
+// CancellationReason represents why an order was cancelled
+type CancellationReason string
+
+const (
+	CancellationReasonOutOfStock      CancellationReason = "out_of_stock"
+	CancellationReasonCustomerRequest CancellationReason = "customer_request"
+	CancellationReasonFraud           CancellationReason = "fraud"
+	CancellationReasonPaymentFailed   CancellationReason = "payment_failed"
+	CancellationReasonExpired         CancellationReason = "expired" // Set by the system when unpaid orders expire
+	CancellationReasonRejected        CancellationReason = "rejected" // Rejected by the merchant on confirmation review
+	CancellationReasonOther           CancellationReason = "other"
+)
+
 // Order represents an order in the system
 type Order struct {
 	BaseModel
@@ -47,19 +68,33 @@ type Order struct {
 	Customer    User          `json:"customer,omitempty" gorm:"foreignKey:CustomerID"`
 	
 	// Order details
-	Status        OrderStatus   `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	Status        OrderStatus   `json:"status" gorm:"type:varchar(30);not null;default:'pending'"`
 	TotalAmount   float64       `json:"total_amount" gorm:"type:decimal(10,2);not null"`
 	SubtotalAmount float64      `json:"subtotal_amount" gorm:"type:decimal(10,2);not null"`
 	TaxAmount     float64       `json:"tax_amount" gorm:"type:decimal(10,2);default:0"`
 	ShippingAmount float64      `json:"shipping_amount" gorm:"type:decimal(10,2);default:0"`
 	DiscountAmount float64      `json:"discount_amount" gorm:"type:decimal(10,2);default:0"`
 	
+	// Order-level promotion applied at checkout, name kept as a snapshot
+	PromotionID   *uint   `json:"promotion_id,omitempty" gorm:"index"`
+	PromotionName *string `json:"promotion_name,omitempty" gorm:"type:varchar(255)"`
+	
+	// Coupon entered at checkout; its use is held until payment and counted once paid
+	CouponID       *uint   `json:"coupon_id,omitempty" gorm:"index"`
+	CouponCode     *string `json:"coupon_code,omitempty" gorm:"type:varchar(50)"`
+	CouponDiscount float64 `json:"coupon_discount" gorm:"type:decimal(10,2);default:0"` // Included in DiscountAmount, or in the items' line discounts for a targeted coupon
+	
 	// Payment information
 	PaymentStatus PaymentStatus `json:"payment_status" gorm:"type:varchar(20);not null;default:'pending'"`
 	PaymentMethod PaymentMethod `json:"payment_method" gorm:"type:varchar(20)"`
 	PaymentID     *string       `json:"payment_id,omitempty" gorm:"type:varchar(255)"` // External payment ID
 	PaidAt        *time.Time    `json:"paid_at,omitempty"`
 	
+	// Guest checkouts pay with the token returned when the order was placed;
+	// only its hash is kept
+	GuestTokenHash *string `json:"-" gorm:"type:varchar(64)"`
+	GuestToken     string  `json:"guest_token,omitempty" gorm:"-"` // Set only on the guest checkout response
+	
 	// Shipping information
 	ShippingFirstName string  `json:"shipping_first_name" gorm:"type:varchar(100);not null"`
 	ShippingLastName  string  `json:"shipping_last_name" gorm:"type:varchar(100);not null"`
@@ -82,6 +117,11 @@ type Order struct {
 	BillingCountry     *string `json:"billing_country,omitempty" gorm:"type:varchar(100)"`
 	BillingPostalCode  *string `json:"billing_postal_code,omitempty" gorm:"type:varchar(20)"`
 	
+	// Delivery estimate made at checkout from the slowest item's processing time
+	ProcessingTimeDays    int        `json:"processing_time_days" gorm:"default:0"`
+	EstimatedShipDate     *time.Time `json:"estimated_ship_date,omitempty"`
+	EstimatedDeliveryDate *time.Time `json:"estimated_delivery_date,omitempty"`
+	
 	// Tracking information
 	TrackingNumber *string    `json:"tracking_number,omitempty" gorm:"type:varchar(100)"`
 	ShippedAt      *time.Time `json:"shipped_at,omitempty"`
@@ -91,8 +131,34 @@ type Order struct {
 	Notes        *string `json:"notes,omitempty" gorm:"type:text"`
 	InternalNotes *string `json:"internal_notes,omitempty" gorm:"type:text"` // Admin/staff notes
 	
+	// Cancellation information
+	CancellationReason *CancellationReason `json:"cancellation_reason,omitempty" gorm:"type:varchar(30);index"`
+	CancellationNote   *string             `json:"cancellation_note,omitempty" gorm:"type:text"`
+	CancelledAt        *time.Time          `json:"cancelled_at,omitempty"`
+	
+	// Fraud review information (admin only, see FraudReviewItem)
+	FraudScore   int     `json:"-" gorm:"default:0"`
+	FraudReasons *string `json:"-" gorm:"type:text"`
+	
+	// Set when sellers and admins were alerted that the order is stuck in its current status
+	SLAAlertedAt *time.Time `json:"-"`
+	
+	// Set once the customer has been asked to review the order's products
+	ReviewReminderSentAt *time.Time `json:"-"`
+	
+	// Set when the order passed the retention period; archived orders are left
+	// out of order lists unless they're asked for
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	
+	// Staff member fulfilling the order. Split orders are assigned per seller
+	// on their fulfillment groups instead; ScopeToSeller shows a seller's here.
+	AssignedTo *uint      `json:"assigned_to,omitempty" gorm:"index"`
+	AssignedAt *time.Time `json:"assigned_at,omitempty"`
+	
 	// Relationships
-	OrderItems []OrderItem `json:"order_items,omitempty" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	OrderItems    []OrderItem          `json:"order_items,omitempty" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	StatusHistory []OrderStatusHistory `json:"status_history,omitempty" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	Fulfillments  []OrderFulfillment   `json:"fulfillments,omitempty" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"` // Only set when the order spans several sellers
 	
 	// Computed fields
 	ItemCount int `json:"item_count" gorm:"-"`
@@ -104,10 +170,12 @@ type OrderItem struct {
 	OrderID   uint    `json:"order_id" gorm:"not null"`
 	ProductID uint    `json:"product_id" gorm:"not null"`
 	Product   Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	SellerID  uint    `json:"seller_id" gorm:"index"` // Snapshot of the product's seller, used to group fulfillment
 	
 	Quantity  int     `json:"quantity" gorm:"not null" validate:"min=1"`
 	UnitPrice float64 `json:"unit_price" gorm:"type:decimal(10,2);not null"`
-	TotalPrice float64 `json:"total_price" gorm:"type:decimal(10,2);not null"`
+	TotalPrice float64 `json:"total_price" gorm:"type:decimal(10,2);not null"` // Before line discount
+	DiscountAmount float64 `json:"discount_amount" gorm:"type:decimal(10,2);default:0"` // Line-level discount
 	
 	// Product snapshot (to preserve product details at time of order)
 	ProductName        string  `json:"product_name" gorm:"type:varchar(255);not null"`
@@ -116,6 +184,42 @@ type OrderItem struct {
 	ProductImage       *string `json:"product_image,omitempty" gorm:"type:varchar(500)"`
 }
 
+// OrderFulfillment is one seller's portion of an order that spans several sellers.
+// Payment is captured once on the parent order; AllocatedAmount is the seller's
+// share of it, used for payouts.
+type OrderFulfillment struct {
+	BaseModel
+	OrderID         uint        `json:"order_id" gorm:"not null;uniqueIndex:idx_order_fulfillments_order_seller"`
+	SellerID        uint        `json:"seller_id" gorm:"not null;uniqueIndex:idx_order_fulfillments_order_seller;index"`
+	Status          OrderStatus `json:"status" gorm:"type:varchar(30);not null;default:'pending'"`
+	ItemCount       int         `json:"item_count" gorm:"not null;default:0"`
+	SubtotalAmount  float64     `json:"subtotal_amount" gorm:"type:decimal(10,2);not null"`
+	TaxAmount       float64     `json:"tax_amount" gorm:"type:decimal(10,2);default:0"`
+	ShippingAmount  float64     `json:"shipping_amount" gorm:"type:decimal(10,2);default:0"`
+	DiscountAmount  float64     `json:"discount_amount" gorm:"type:decimal(10,2);default:0"`
+	AllocatedAmount float64     `json:"allocated_amount" gorm:"type:decimal(10,2);not null"`
+	TrackingNumber  *string     `json:"tracking_number,omitempty" gorm:"type:varchar(100)"`
+	ShippedAt       *time.Time  `json:"shipped_at,omitempty"`
+	DeliveredAt     *time.Time  `json:"delivered_at,omitempty"`
+	AssignedTo      *uint       `json:"assigned_to,omitempty" gorm:"index"` // Seller's staff member fulfilling this portion
+	AssignedAt      *time.Time  `json:"assigned_at,omitempty"`
+}
+
+// OrderStatusHistory records every status change of an order
+type OrderStatusHistory struct {
+	BaseModel
+	OrderID    uint                `json:"order_id" gorm:"not null;index"`
+	FromStatus OrderStatus         `json:"from_status" gorm:"type:varchar(30)"`
+	ToStatus   OrderStatus         `json:"to_status" gorm:"type:varchar(30);not null"`
+	Reason     *CancellationReason `json:"reason,omitempty" gorm:"type:varchar(30)"`
+	Note       *string             `json:"note,omitempty" gorm:"type:text"`
+	ChangedBy  uint                `json:"changed_by"`
+}
+
+// OrderNoteConfirmationResent is the status history note recorded when the
+// order confirmation email is resent; the status itself doesn't change
+const OrderNoteConfirmationResent = "Order confirmation email resent"
+
 // Cart represents a shopping cart (temporary before order)
 type Cart struct {
 	BaseModel
@@ -131,18 +235,66 @@ type Cart struct {
 // CartItem represents items in a cart
 type CartItem struct {
 	BaseModel
-	CartID    uint    `json:"cart_id" gorm:"not null"`
-	ProductID uint    `json:"product_id" gorm:"not null"`
+	CartID    uint    `json:"cart_id" gorm:"not null;uniqueIndex:idx_cart_items_cart_product"`
+	ProductID uint    `json:"product_id" gorm:"not null;uniqueIndex:idx_cart_items_cart_product"`
 	Product   Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
 	Quantity  int     `json:"quantity" gorm:"not null" validate:"min=1"`
 }
 
+// CartLimits bounds a cart: how many different products it may hold and what
+// they may be worth at current prices. 0 lifts a limit.
+type CartLimits struct {
+	MaxItems int     `json:"max_items"`
+	MaxValue float64 `json:"max_value"`
+}
+
+// ExceedsItems checks if a cart of lines different products is over the limit
+func (l CartLimits) ExceedsItems(lines int) bool {
+	return l.MaxItems > 0 && lines > l.MaxItems
+}
+
+// ExceedsValue checks if a cart worth value is over the limit
+func (l CartLimits) ExceedsValue(value float64) bool {
+	return l.MaxValue > 0 && money.FromFloat(value) > money.FromFloat(l.MaxValue)
+}
+
 // CartAddRequest represents the request to add item to cart
 type CartAddRequest struct {
 	ProductID uint `json:"product_id" validate:"required"`
 	Quantity  int  `json:"quantity" validate:"required,min=1"`
 }
 
+// CartBulkAddRequest represents the request to add many items to the cart at once
+type CartBulkAddRequest struct {
+	Items []CartAddRequest `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// CartAddStatus is what happened to one item of a bulk add
+type CartAddStatus string
+
+const (
+	CartAddAdded              CartAddStatus = "added"
+	CartAddClamped            CartAddStatus = "clamped"             // Added, but only up to the stock available
+	CartAddSkippedUnavailable CartAddStatus = "skipped_unavailable" // Not found, not for sale or out of stock
+	CartAddSkippedItemLimit   CartAddStatus = "skipped_item_limit"  // The cart holds as many different products as it may
+	CartAddSkippedValueLimit  CartAddStatus = "skipped_value_limit" // Adding it would take the cart over its value limit
+)
+
+// CartAddResult reports how one item of a bulk add went
+type CartAddResult struct {
+	ProductID    uint          `json:"product_id"`
+	Requested    int           `json:"requested"`
+	Added        int           `json:"added"`
+	CartQuantity int           `json:"cart_quantity"` // The product's quantity in the cart afterwards
+	Status       CartAddStatus `json:"status"`
+}
+
+// CartBulkAddResponse represents the cart after a bulk add with each item's result
+type CartBulkAddResponse struct {
+	Cart    *CartResponse   `json:"cart"`
+	Results []CartAddResult `json:"results"`
+}
+
 // CartUpdateRequest represents the request to update cart item
 type CartUpdateRequest struct {
 	Quantity int `json:"quantity" validate:"required,min=1"`
@@ -223,6 +375,29 @@ type OrderListRequest struct {
 	SortOrder     string         `query:"sort_order" validate:"omitempty,oneof=asc desc"`
 }
 
+// OrderFilter narrows the admin order list. Unset fields don't filter.
+type OrderFilter struct {
+	Status          *OrderStatus
+	Category        string     // Orders with at least one product in this category
+	DateFrom        *time.Time // Inclusive
+	DateTo          *time.Time // Exclusive
+	IncludeArchived bool
+}
+
+// SellerOrderFilter narrows a seller's order list
+type SellerOrderFilter struct {
+	IncludeArchived bool
+	AssignedTo      *uint // Orders whose seller portion is assigned to this staff member
+	Unassigned      bool  // Orders whose seller portion isn't assigned to anyone
+}
+
+// OrderAssignRequest represents a request to assign a seller's portion of an
+// order to one of their staff, or to unassign it when StaffID is nil
+type OrderAssignRequest struct {
+	StaffID  *uint `json:"staff_id"`
+	SellerID *uint `json:"seller_id,omitempty"` // Admins only: whose portion of a split order to assign
+}
+
 // CartItemRequest represents the request to add/update cart items
 type CartItemRequest struct {
 	ProductID uint `json:"product_id" validate:"required"`
@@ -243,7 +418,7 @@ type OrderStatsResponse struct {
 type PaymentRequest struct {
 	OrderID       uint          `json:"order_id" validate:"required"`
 	PaymentMethod PaymentMethod `json:"payment_method" validate:"required"`
-	Amount        float64       `json:"amount" validate:"required,min=0.01"`
+	Amount        float64       `json:"amount" validate:"omitempty,min=0.01"` // Optional; the order total is charged and a different amount is rejected
 	Currency      string        `json:"currency" validate:"required,len=3"`
 	
 	// Stripe specific fields
@@ -256,9 +431,63 @@ type PaymentRequest struct {
 
 // CreateOrderRequest represents the request to create an order
 type CreateOrderRequest struct {
-	Items           []OrderItemRequest `json:"items" validate:"required,min=1,dive"`
-	ShippingAddress string             `json:"shipping_address" validate:"required"`
-	PaymentMethod   PaymentMethod      `json:"payment_method" validate:"required"`
+	Items             []OrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	ShippingAddress   string             `json:"shipping_address" validate:"required_without=ShippingAddressID"`
+	ShippingAddressID *uint              `json:"shipping_address_id,omitempty"` // Saved address, used instead of shipping_address
+	BillingAddressID  *uint              `json:"billing_address_id,omitempty"`  // Saved address for billing
+	PaymentMethod     PaymentMethod      `json:"payment_method" validate:"required"`
+	CouponCode        *string            `json:"coupon_code,omitempty" validate:"omitempty,max=50"`
+}
+
+// GuestOrderRequest represents the request to create an order without an
+// account. The email gets the order confirmation, and registering with it
+// later claims the order.
+type GuestOrderRequest struct {
+	Items         []OrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	PaymentMethod PaymentMethod      `json:"payment_method" validate:"required"`
+	CouponCode    *string            `json:"coupon_code,omitempty" validate:"omitempty,max=50"`
+
+	// Contact and shipping information
+	Email      string  `json:"email" validate:"required,email,max=255"`
+	FirstName  string  `json:"first_name" validate:"required,max=100"`
+	LastName   string  `json:"last_name" validate:"required,max=100"`
+	Phone      *string `json:"phone,omitempty" validate:"omitempty,max=20"`
+	Street     string  `json:"street" validate:"required,max=255"`
+	City       string  `json:"city" validate:"required,max=100"`
+	State      string  `json:"state" validate:"required,max=100"`
+	Country    string  `json:"country" validate:"required,max=100"`
+	PostalCode string  `json:"postal_code" validate:"required,max=20"`
+}
+
+// CreateOrderRequest returns the order part of the request
+func (r *GuestOrderRequest) CreateOrderRequest() *CreateOrderRequest {
+	return &CreateOrderRequest{
+		Items:           r.Items,
+		ShippingAddress: strings.TrimSpace(r.Street),
+		PaymentMethod:   r.PaymentMethod,
+		CouponCode:      r.CouponCode,
+	}
+}
+
+// Address returns the shipping address given in the request
+func (r *GuestOrderRequest) Address() *Address {
+	return &Address{
+		FirstName:  strings.TrimSpace(r.FirstName),
+		LastName:   strings.TrimSpace(r.LastName),
+		Phone:      r.Phone,
+		Street:     strings.TrimSpace(r.Street),
+		City:       strings.TrimSpace(r.City),
+		State:      strings.TrimSpace(r.State),
+		Country:    strings.TrimSpace(r.Country),
+		PostalCode: strings.TrimSpace(r.PostalCode),
+	}
+}
+
+// GuestPaymentRequest represents a guest paying for their order with the
+// token returned when it was placed
+type GuestPaymentRequest struct {
+	GuestToken string `json:"guest_token" validate:"required"`
+	PaymentRequest
 }
 
 // OrderItemRequest represents an order item in a request
@@ -272,6 +501,387 @@ type UpdateOrderStatusRequest struct {
 	Status OrderStatus `json:"status" validate:"required"`
 }
 
+// CancelOrderRequest represents the request to cancel an order
+type CancelOrderRequest struct {
+	Reason CancellationReason `json:"reason" validate:"omitempty,oneof=out_of_stock customer_request fraud payment_failed other"`
+	Note   *string            `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// UpdateShippingAddressRequest represents the request to change where an
+// order ships. Either a saved address or the full address must be given.
+type UpdateShippingAddressRequest struct {
+	AddressID  *uint   `json:"address_id,omitempty"` // Saved address, used instead of the fields below
+	FirstName  string  `json:"first_name" validate:"required_without=AddressID,max=100"`
+	LastName   string  `json:"last_name" validate:"required_without=AddressID,max=100"`
+	Phone      *string `json:"phone,omitempty" validate:"omitempty,max=20"`
+	Street     string  `json:"street" validate:"required_without=AddressID,max=255"`
+	City       string  `json:"city" validate:"required_without=AddressID,max=100"`
+	State      string  `json:"state" validate:"required_without=AddressID,max=100"`
+	Country    string  `json:"country" validate:"required_without=AddressID,max=100"`
+	PostalCode string  `json:"postal_code" validate:"required_without=AddressID,max=20"`
+}
+
+// Address returns the address given in the request's fields
+func (r *UpdateShippingAddressRequest) Address() *Address {
+	return &Address{
+		FirstName:  strings.TrimSpace(r.FirstName),
+		LastName:   strings.TrimSpace(r.LastName),
+		Phone:      r.Phone,
+		Street:     strings.TrimSpace(r.Street),
+		City:       strings.TrimSpace(r.City),
+		State:      strings.TrimSpace(r.State),
+		Country:    strings.TrimSpace(r.Country),
+		PostalCode: strings.TrimSpace(r.PostalCode),
+	}
+}
+
+// OrderMinimum is a minimum order amount an order was checked against: the
+// store's, or a seller's for their portion of the order
+type OrderMinimum struct {
+	SellerID  *uint   `json:"seller_id,omitempty"` // Nil for the store-wide minimum
+	StoreName string  `json:"store_name,omitempty"`
+	Minimum   float64 `json:"minimum"`
+	Amount    float64 `json:"amount"`    // Subtotal after discounts counted toward the minimum
+	Shortfall float64 `json:"shortfall"` // What's left to add; 0 once met
+}
+
+// Met checks if the order reaches the minimum
+func (m *OrderMinimum) Met() bool {
+	return m.Shortfall <= 0
+}
+
+// FraudReviewRequest represents an admin decision on a flagged order
+type FraudReviewRequest struct {
+	Approve bool    `json:"approve"`
+	Note    *string `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// OrderRecalculateRequest represents an admin's request to recompute an order's totals
+type OrderRecalculateRequest struct {
+	Force bool    `json:"force"` // Required once the order was charged or has shipped
+	Note  *string `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// OrderRecalculation is an order with its totals before and after they were recomputed
+type OrderRecalculation struct {
+	Order   *Order      `json:"order"`
+	Before  OrderTotals `json:"before"`
+	After   OrderTotals `json:"after"`
+	Changed bool        `json:"changed"`
+}
+
+// OrderConfirmationRequest represents a merchant decision on a paid order
+// awaiting confirmation. Rejected orders are cancelled and refunded.
+type OrderConfirmationRequest struct {
+	Approve bool    `json:"approve"`
+	Note    *string `json:"note,omitempty" validate:"omitempty,max=1000"` // Kept as the cancellation note on rejection
+}
+
+// OrderConfirmationPage is everything the post-checkout thank-you page shows,
+// in one response
+type OrderConfirmationPage struct {
+	Order             *Order                `json:"order"`
+	Lines             []OrderLineSummary    `json:"lines"`
+	Totals            OrderTotals           `json:"totals"`
+	EstimatedDelivery OrderDeliveryEstimate `json:"estimated_delivery"`
+	Tracking          *OrderTracking        `json:"tracking,omitempty"` // Set once the order has shipped with a tracking number
+	Recommended       []ProductResponse     `json:"recommended"`
+}
+
+// OrderLineSummary is one item of an order with its line totals
+type OrderLineSummary struct {
+	ProductID      uint    `json:"product_id"`
+	ProductName    string  `json:"product_name"`
+	ProductImage   *string `json:"product_image,omitempty"`
+	Quantity       int     `json:"quantity"`
+	UnitPrice      float64 `json:"unit_price"`
+	Subtotal       float64 `json:"subtotal"`        // Before the line discount
+	DiscountAmount float64 `json:"discount_amount"` // Line-level discount
+	Total          float64 `json:"total"`
+}
+
+// OrderTotals breaks down how an order's total was reached
+type OrderTotals struct {
+	ItemCount      int     `json:"item_count"`
+	Subtotal       float64 `json:"subtotal"`
+	DiscountAmount float64 `json:"discount_amount"`
+	CouponDiscount float64 `json:"coupon_discount"` // Included in DiscountAmount, or in the line discounts for a targeted coupon
+	ShippingAmount float64 `json:"shipping_amount"`
+	TaxAmount      float64 `json:"tax_amount"`
+	Total          float64 `json:"total"`
+}
+
+// OrderDeliveryEstimate is the delivery estimate made at checkout
+type OrderDeliveryEstimate struct {
+	ProcessingTimeDays    int        `json:"processing_time_days"`
+	EstimatedShipDate     *time.Time `json:"estimated_ship_date,omitempty"`
+	EstimatedDeliveryDate *time.Time `json:"estimated_delivery_date,omitempty"`
+}
+
+// OrderTracking is where a shipped order is
+type OrderTracking struct {
+	TrackingNumber string     `json:"tracking_number"`
+	ShippedAt      *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// OrderTrackRequest looks an order up by its number and the email it was
+// placed with, for shoppers who aren't signed in
+type OrderTrackRequest struct {
+	OrderNumber string `json:"order_number" validate:"required,max=50"`
+	Email       string `json:"email" validate:"required,email"`
+}
+
+// OrderTrackingStatus is what order tracking shows: where the order is, never
+// who placed it, where it's going or what's in it
+type OrderTrackingStatus struct {
+	OrderNumber       string                `json:"order_number"`
+	Status            OrderStatus           `json:"status"`
+	PlacedAt          time.Time             `json:"placed_at"`
+	EstimatedDelivery OrderDeliveryEstimate `json:"estimated_delivery"`
+	Timeline          []OrderTimelineEntry  `json:"timeline"`
+	Shipments         []OrderTracking       `json:"shipments"` // One per tracking number; split orders ship per seller
+}
+
+// OrderTimelineEntry is one status an order moved to, and when
+type OrderTimelineEntry struct {
+	Status OrderStatus `json:"status"`
+	At     time.Time   `json:"at"`
+}
+
+// OrderPreview is what an order would come to if it were placed now. Nothing
+// is saved and no stock or coupon use is held, so placing it can still fail.
+type OrderPreview struct {
+	Lines             []OrderLineSummary    `json:"lines"`
+	Totals            OrderTotals           `json:"totals"`
+	PromotionName     *string               `json:"promotion_name,omitempty"`
+	CouponCode        *string               `json:"coupon_code,omitempty"` // Unset when a better promotion was used instead
+	EstimatedDelivery OrderDeliveryEstimate `json:"estimated_delivery"`
+}
+
+// NewOrderPreview builds the preview of an order that hasn't been saved
+func NewOrderPreview(order *Order) *OrderPreview {
+	return &OrderPreview{
+		Lines:             order.LineSummaries(),
+		Totals:            order.Totals(),
+		PromotionName:     order.PromotionName,
+		CouponCode:        order.CouponCode,
+		EstimatedDelivery: order.DeliveryEstimate(),
+	}
+}
+
+// LineSummaries returns the order's items with their line totals
+func (o *Order) LineSummaries() []OrderLineSummary {
+	lines := make([]OrderLineSummary, len(o.OrderItems))
+	for i := range o.OrderItems {
+		item := &o.OrderItems[i]
+		lines[i] = OrderLineSummary{
+			ProductID:      item.ProductID,
+			ProductName:    item.ProductName,
+			ProductImage:   item.ProductImage,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.UnitPrice,
+			Subtotal:       item.TotalPrice,
+			DiscountAmount: item.DiscountAmount,
+			Total:          money.Round(item.LineTotal()),
+		}
+	}
+	return lines
+}
+
+// Totals breaks down the order's total
+func (o *Order) Totals() OrderTotals {
+	totals := OrderTotals{
+		Subtotal:       o.SubtotalAmount,
+		DiscountAmount: o.DiscountAmount,
+		CouponDiscount: o.CouponDiscount,
+		ShippingAmount: o.ShippingAmount,
+		TaxAmount:      o.TaxAmount,
+		Total:          o.TotalAmount,
+	}
+	for i := range o.OrderItems {
+		totals.ItemCount += o.OrderItems[i].Quantity
+	}
+	return totals
+}
+
+// DeliveryEstimate returns the delivery estimate made at checkout
+func (o *Order) DeliveryEstimate() OrderDeliveryEstimate {
+	return OrderDeliveryEstimate{
+		ProcessingTimeDays:    o.ProcessingTimeDays,
+		EstimatedShipDate:     o.EstimatedShipDate,
+		EstimatedDeliveryDate: o.EstimatedDeliveryDate,
+	}
+}
+
+// NewOrderConfirmationPage builds the confirmation page for an order and the
+// products recommended alongside it
+func NewOrderConfirmationPage(order *Order, recommended []*Product) *OrderConfirmationPage {
+	page := &OrderConfirmationPage{
+		Order:             order,
+		Lines:             order.LineSummaries(),
+		Totals:            order.Totals(),
+		EstimatedDelivery: order.DeliveryEstimate(),
+		Recommended:       make([]ProductResponse, len(recommended)),
+	}
+
+	if order.TrackingNumber != nil && *order.TrackingNumber != "" {
+		page.Tracking = &OrderTracking{
+			TrackingNumber: *order.TrackingNumber,
+			ShippedAt:      order.ShippedAt,
+			DeliveredAt:    order.DeliveredAt,
+		}
+	}
+
+	for i, product := range recommended {
+		page.Recommended[i] = product.ToResponse()
+	}
+	return page
+}
+
+// NewOrderTrackingStatus builds the tracking view of an order. The timeline
+// leaves out history entries that didn't change the status, and every note.
+func NewOrderTrackingStatus(order *Order) *OrderTrackingStatus {
+	status := &OrderTrackingStatus{
+		OrderNumber:       order.OrderNumber,
+		Status:            order.Status,
+		PlacedAt:          order.CreatedAt,
+		EstimatedDelivery: order.DeliveryEstimate(),
+		Timeline:          []OrderTimelineEntry{},
+		Shipments:         []OrderTracking{},
+	}
+
+	for _, history := range order.StatusHistory {
+		if history.FromStatus == history.ToStatus {
+			continue
+		}
+		status.Timeline = append(status.Timeline, OrderTimelineEntry{Status: history.ToStatus, At: history.CreatedAt})
+	}
+
+	if order.TrackingNumber != nil && *order.TrackingNumber != "" {
+		status.Shipments = append(status.Shipments, OrderTracking{
+			TrackingNumber: *order.TrackingNumber,
+			ShippedAt:      order.ShippedAt,
+			DeliveredAt:    order.DeliveredAt,
+		})
+	}
+	for _, fulfillment := range order.Fulfillments {
+		if fulfillment.TrackingNumber == nil || *fulfillment.TrackingNumber == "" {
+			continue
+		}
+		status.Shipments = append(status.Shipments, OrderTracking{
+			TrackingNumber: *fulfillment.TrackingNumber,
+			ShippedAt:      fulfillment.ShippedAt,
+			DeliveredAt:    fulfillment.DeliveredAt,
+		})
+	}
+	return status
+}
+
+// FraudReviewItem represents a flagged order in the admin review queue
+type FraudReviewItem struct {
+	Order        *Order   `json:"order"`
+	FraudScore   int      `json:"fraud_score"`
+	FraudReasons []string `json:"fraud_reasons"`
+}
+
+// StuckOrder represents an order that has been in its status longer than the SLA allows
+type StuckOrder struct {
+	Order        *Order      `json:"order"`
+	Status       OrderStatus `json:"status"`
+	StatusSince  time.Time   `json:"status_since"`
+	DueBy        time.Time   `json:"due_by"` // When the order should have moved on
+	OverdueHours float64     `json:"overdue_hours"`
+}
+
+// ProductOrderItem represents an order containing a seller's product, with that product's line highlighted
+type ProductOrderItem struct {
+	Order *Order     `json:"order"`
+	Item  *OrderItem `json:"item"`
+}
+
+// Refund states of a line in the accounting export
+const (
+	ExportRefundNone     = "none"
+	ExportRefundPartial  = "partial"
+	ExportRefundRefunded = "refunded"
+)
+
+// OrderExportLine is one order item in the accounting export, with its share
+// of the order's discount, tax and shipping. The shares of an order's lines
+// add up to the order's amounts; see AllocateOrderExportLines.
+type OrderExportLine struct {
+	OrderID            uint          `json:"-"`
+	OrderNumber        string        `json:"order_number"`
+	OrderDate          time.Time     `json:"order_date"`
+	OrderStatus        OrderStatus   `json:"order_status"`
+	CustomerID         uint          `json:"customer_id"`
+	CustomerName       string        `json:"customer_name"`
+	CustomerEmail      string        `json:"customer_email"`
+	ProductID          uint          `json:"product_id"`
+	ProductName        string        `json:"product_name"`
+	SKU                string        `json:"sku"`
+	Quantity           int           `json:"quantity"`
+	UnitPrice          float64       `json:"unit_price"`
+	LineSubtotal       float64       `json:"line_subtotal"` // Before any discount
+	LineDiscount       float64       `json:"line_discount"`
+	OrderDiscountShare float64       `json:"order_discount_share"`
+	TaxShare           float64       `json:"tax_share"`
+	ShippingShare      float64       `json:"shipping_share"`
+	LineTotal          float64       `json:"line_total"` // After discounts, with the tax and shipping shares
+	OrderSubtotal      float64       `json:"order_subtotal"`
+	OrderDiscount      float64       `json:"order_discount"`
+	OrderTax           float64       `json:"order_tax"`
+	OrderShipping      float64       `json:"order_shipping"`
+	OrderTotal         float64       `json:"order_total"`
+	PaymentStatus      PaymentStatus `json:"payment_status"`
+	PaymentMethod      PaymentMethod `json:"payment_method"`
+	PaidAt             *time.Time    `json:"paid_at"`
+	RefundedQuantity   int           `json:"refunded_quantity"` // Units refunded through returns
+	RefundedAmount     float64       `json:"refunded_amount"`
+	RefundStatus       string        `json:"refund_status"`
+}
+
+// AllocateOrderExportLines fills in the shares, totals and refund status of
+// one order's lines, splitting the order's discount, tax and shipping by each
+// line's total after its own discount. The last line takes what's left so the
+// shares add up to the order's amounts.
+func AllocateOrderExportLines(lines []*OrderExportLine) {
+	if len(lines) == 0 {
+		return
+	}
+
+	orderSubtotal := money.FromFloat(lines[0].OrderSubtotal)
+	discountLeft := money.FromFloat(lines[0].OrderDiscount)
+	taxLeft := money.FromFloat(lines[0].OrderTax)
+	shippingLeft := money.FromFloat(lines[0].OrderShipping)
+	for i, line := range lines {
+		base := money.FromFloat(line.LineSubtotal) - money.FromFloat(line.LineDiscount)
+		discount, tax, shipping := discountLeft, taxLeft, shippingLeft
+		if i < len(lines)-1 {
+			discount = money.FromFloat(line.OrderDiscount).Share(base, orderSubtotal)
+			tax = money.FromFloat(line.OrderTax).Share(base, orderSubtotal)
+			shipping = money.FromFloat(line.OrderShipping).Share(base, orderSubtotal)
+			discountLeft -= discount
+			taxLeft -= tax
+			shippingLeft -= shipping
+		}
+		line.OrderDiscountShare = discount.Float()
+		line.TaxShare = tax.Float()
+		line.ShippingShare = shipping.Float()
+		line.LineTotal = (base - discount + tax + shipping).Float()
+
+		switch {
+		case line.PaymentStatus == PaymentStatusRefunded || (line.RefundedQuantity > 0 && line.RefundedQuantity >= line.Quantity):
+			line.RefundStatus = ExportRefundRefunded
+		case line.RefundedAmount > 0:
+			line.RefundStatus = ExportRefundPartial
+		default:
+			line.RefundStatus = ExportRefundNone
+		}
+	}
+}
+
 // PaymentProcessRequest represents a payment processing request
 type PaymentProcessRequest struct {
 	Token string `json:"token" validate:"required"`
@@ -293,6 +903,7 @@ type OrderAnalytics struct {
 	ShippedOrders    int64   `json:"shipped_orders"`
 	DeliveredOrders  int64   `json:"delivered_orders"`
 	CancelledOrders  int64   `json:"cancelled_orders"`
+	Comparison       *PeriodComparison `json:"comparison,omitempty"` // Set with compare=true
 }
 
 // GenerateOrderNumber generates a unique order number
@@ -306,27 +917,402 @@ func (o *Order) GenerateOrderNumber() {
 
 // CalculateTotals calculates order totals based on order items
 func (o *Order) CalculateTotals() {
-	o.SubtotalAmount = 0
+	var subtotal money.Amount
 	o.ItemCount = 0
 	
-	for _, item := range o.OrderItems {
-		o.SubtotalAmount += item.TotalPrice
-		o.ItemCount += item.Quantity
+	for i := range o.OrderItems {
+		subtotal += money.FromFloat(o.OrderItems[i].LineTotal())
+		o.ItemCount += o.OrderItems[i].Quantity
 	}
 	
-	// Calculate total (subtotal + tax + shipping - discount)
-	o.TotalAmount = o.SubtotalAmount + o.TaxAmount + o.ShippingAmount - o.DiscountAmount
+	// Calculate total (subtotal + tax + shipping - discount) in minor units so
+	// it adds up to the cent
+	total := subtotal + money.FromFloat(o.TaxAmount) + money.FromFloat(o.ShippingAmount) - money.FromFloat(o.DiscountAmount)
+	o.SubtotalAmount = subtotal.Float()
+	o.TotalAmount = total.Float()
+}
+
+// DiscountedSubtotal returns the subtotal after item and order discounts
+func (o *Order) DiscountedSubtotal() float64 {
+	return (money.FromFloat(o.SubtotalAmount) - money.FromFloat(o.DiscountAmount)).Float()
+}
+
+// DiscountedSellerSubtotal returns the seller's items' subtotal after their
+// item discounts and their share of the order discount, split the same way
+// BuildFulfillments allocates it
+func (o *Order) DiscountedSellerSubtotal(sellerID uint) float64 {
+	var subtotal money.Amount
+	for i := range o.OrderItems {
+		if o.OrderItems[i].SellerID == sellerID {
+			subtotal += money.FromFloat(o.OrderItems[i].LineTotal())
+		}
+	}
+	subtotal -= money.FromFloat(o.DiscountAmount).Share(subtotal, money.FromFloat(o.SubtotalAmount))
+	return subtotal.Float()
+}
+
+// ApplyItemDiscount splits a discount across the items matched by match, in
+// proportion to their line totals, adds it to their line discounts and
+// recalculates the order totals. It returns the discount applied, which is
+// less than amount only when the matching items are worth less. Used for
+// coupons that target specific products.
+func (o *Order) ApplyItemDiscount(match func(item *OrderItem) bool, amount float64) float64 {
+	var matched []*OrderItem
+	var eligible money.Amount
+	for i := range o.OrderItems {
+		if item := &o.OrderItems[i]; match(item) {
+			matched = append(matched, item)
+			eligible += money.FromFloat(item.LineTotal())
+		}
+	}
+
+	discount := money.FromFloat(amount).Clamp(eligible)
+	left := discount
+	for i, item := range matched {
+		share := discount.Share(money.FromFloat(item.LineTotal()), eligible)
+		if i == len(matched)-1 || share > left {
+			// The last item takes what rounding left over
+			share = left
+		}
+		item.ApplyDiscount((money.FromFloat(item.DiscountAmount) + share).Float())
+		left -= share
+	}
+
+	o.CalculateTotals()
+	return discount.Float()
 }
 
 // CanCancel checks if the order can be cancelled
 func (o *Order) CanCancel() bool {
-	return o.Status == OrderStatusPending || o.Status == OrderStatusConfirmed
+	return o.Status == OrderStatusPending || o.Status == OrderStatusPendingReview ||
+		o.Status == OrderStatusAwaitingConfirmation || o.Status == OrderStatusConfirmed
+}
+
+// IsPendingReview checks if the order is held for fraud review
+func (o *Order) IsPendingReview() bool {
+	return o.Status == OrderStatusPendingReview
 }
 
-// CanRefund checks if the order can be refunded
+// GetFraudReasons returns fraud reasons as a slice
+func (o *Order) GetFraudReasons() []string {
+	if o.FraudReasons == nil || *o.FraudReasons == "" {
+		return []string{}
+	}
+	return strings.Split(*o.FraudReasons, fraudReasonSeparator)
+}
+
+// SetFraudReasons sets fraud reasons from a slice
+func (o *Order) SetFraudReasons(reasons []string) {
+	joined := strings.Join(reasons, fraudReasonSeparator)
+	o.FraudReasons = &joined
+}
+
+// ToFraudReviewItem converts Order to FraudReviewItem
+func (o *Order) ToFraudReviewItem() FraudReviewItem {
+	return FraudReviewItem{
+		Order:        o,
+		FraudScore:   o.FraudScore,
+		FraudReasons: o.GetFraudReasons(),
+	}
+}
+
+// ToProductOrderItem converts Order to ProductOrderItem, highlighting the line for productID
+func (o *Order) ToProductOrderItem(productID uint) ProductOrderItem {
+	item := ProductOrderItem{Order: o}
+	for i := range o.OrderItems {
+		if o.OrderItems[i].ProductID == productID {
+			item.Item = &o.OrderItems[i]
+			break
+		}
+	}
+	return item
+}
+
+// BuildFulfillments groups the items by seller and allocates the order's tax,
+// shipping and discount to each group in proportion to its subtotal. Orders
+// from a single seller get no groups. Call after the totals are final.
+func (o *Order) BuildFulfillments() {
+	o.Fulfillments = nil
+
+	groups := make(map[uint]*OrderFulfillment)
+	subtotals := make(map[uint]money.Amount)
+	var sellerIDs []uint
+	for i := range o.OrderItems {
+		item := &o.OrderItems[i]
+		group, ok := groups[item.SellerID]
+		if !ok {
+			group = &OrderFulfillment{SellerID: item.SellerID, Status: o.Status}
+			groups[item.SellerID] = group
+			sellerIDs = append(sellerIDs, item.SellerID)
+		}
+		group.ItemCount += item.Quantity
+		subtotals[item.SellerID] += money.FromFloat(item.LineTotal())
+	}
+
+	if len(sellerIDs) < 2 {
+		return
+	}
+
+	// The last group takes what's left so allocations add up to the total
+	orderSubtotal := money.FromFloat(o.SubtotalAmount)
+	taxLeft := money.FromFloat(o.TaxAmount)
+	shippingLeft := money.FromFloat(o.ShippingAmount)
+	discountLeft := money.FromFloat(o.DiscountAmount)
+	totalLeft := money.FromFloat(o.TotalAmount)
+	for i, sellerID := range sellerIDs {
+		group := groups[sellerID]
+		subtotal := subtotals[sellerID]
+		tax, shipping, discount, total := taxLeft, shippingLeft, discountLeft, totalLeft
+		if i < len(sellerIDs)-1 {
+			tax = money.FromFloat(o.TaxAmount).Share(subtotal, orderSubtotal)
+			shipping = money.FromFloat(o.ShippingAmount).Share(subtotal, orderSubtotal)
+			discount = money.FromFloat(o.DiscountAmount).Share(subtotal, orderSubtotal)
+			total = subtotal + tax + shipping - discount
+			taxLeft -= tax
+			shippingLeft -= shipping
+			discountLeft -= discount
+			totalLeft -= total
+		}
+		group.SubtotalAmount = subtotal.Float()
+		group.TaxAmount = tax.Float()
+		group.ShippingAmount = shipping.Float()
+		group.DiscountAmount = discount.Float()
+		group.AllocatedAmount = total.Float()
+		o.Fulfillments = append(o.Fulfillments, *group)
+	}
+}
+
+// RepriceItems recomputes each line's total from its unit price and quantity
+func (o *Order) RepriceItems() {
+	for i := range o.OrderItems {
+		item := &o.OrderItems[i]
+		item.TotalPrice = money.FromFloat(item.UnitPrice).Times(item.Quantity).Float()
+	}
+}
+
+// ReallocateFulfillments re-splits the order's totals across its existing
+// seller groups as BuildFulfillments does, keeping each group's status,
+// tracking and assignment
+func (o *Order) ReallocateFulfillments() {
+	if !o.IsSplit() {
+		return
+	}
+
+	existing := o.Fulfillments
+	o.BuildFulfillments()
+	for i := range existing {
+		group := &existing[i]
+		if allocated := o.FulfillmentForSeller(group.SellerID); allocated != nil {
+			group.ItemCount = allocated.ItemCount
+			group.SubtotalAmount = allocated.SubtotalAmount
+			group.TaxAmount = allocated.TaxAmount
+			group.ShippingAmount = allocated.ShippingAmount
+			group.DiscountAmount = allocated.DiscountAmount
+			group.AllocatedAmount = allocated.AllocatedAmount
+		}
+	}
+	o.Fulfillments = existing
+}
+
+// IsSplit checks if the order is fulfilled by more than one seller
+func (o *Order) IsSplit() bool {
+	return len(o.Fulfillments) > 0
+}
+
+// FulfillmentForSeller returns the seller's fulfillment group, or nil if the order isn't split
+func (o *Order) FulfillmentForSeller(sellerID uint) *OrderFulfillment {
+	for i := range o.Fulfillments {
+		if o.Fulfillments[i].SellerID == sellerID {
+			return &o.Fulfillments[i]
+		}
+	}
+	return nil
+}
+
+// HasSellerItems checks whether any of the order's items is the seller's
+func (o *Order) HasSellerItems(sellerID uint) bool {
+	for _, item := range o.OrderItems {
+		if item.SellerID == sellerID {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeToSeller trims a split order down to the seller's items and fulfillment
+// group so a seller only sees their portion. Single-seller orders are left as is.
+func (o *Order) ScopeToSeller(sellerID uint) {
+	if !o.IsSplit() {
+		return
+	}
+
+	items := make([]OrderItem, 0, len(o.OrderItems))
+	for _, item := range o.OrderItems {
+		if item.SellerID == sellerID {
+			items = append(items, item)
+		}
+	}
+	o.OrderItems = items
+
+	group := o.FulfillmentForSeller(sellerID)
+	if group == nil {
+		o.Fulfillments = []OrderFulfillment{}
+		return
+	}
+
+	// Show the seller's share of the totals rather than the whole order's
+	scoped := *group
+	o.Fulfillments = []OrderFulfillment{scoped}
+	o.SubtotalAmount = scoped.SubtotalAmount
+	o.TaxAmount = scoped.TaxAmount
+	o.ShippingAmount = scoped.ShippingAmount
+	o.DiscountAmount = scoped.DiscountAmount
+	o.TotalAmount = scoped.AllocatedAmount
+	o.ItemCount = scoped.ItemCount
+	o.AssignedTo = scoped.AssignedTo
+	o.AssignedAt = scoped.AssignedAt
+}
+
+// AssigneeFor returns the staff member the seller's portion is assigned to, or nil
+func (o *Order) AssigneeFor(sellerID uint) *uint {
+	if !o.IsSplit() {
+		return o.AssignedTo
+	}
+	if group := o.FulfillmentForSeller(sellerID); group != nil {
+		return group.AssignedTo
+	}
+	return nil
+}
+
+// AssignTo assigns the seller's portion to a staff member, or unassigns it
+// when staffID is nil. Split orders are assigned on the seller's fulfillment
+// group, which is returned so it can be saved; single-seller orders return nil.
+func (o *Order) AssignTo(sellerID uint, staffID *uint, at time.Time) *OrderFulfillment {
+	var assignedAt *time.Time
+	if staffID != nil {
+		assignedAt = &at
+	}
+
+	if !o.IsSplit() {
+		o.AssignedTo = staffID
+		o.AssignedAt = assignedAt
+		return nil
+	}
+	group := o.FulfillmentForSeller(sellerID)
+	if group != nil {
+		group.AssignedTo = staffID
+		group.AssignedAt = assignedAt
+	}
+	return group
+}
+
+// SellerAssignedTo returns the seller whose portion of the order is assigned
+// to the staff member
+func (o *Order) SellerAssignedTo(staffID uint) (uint, bool) {
+	if !o.IsSplit() {
+		if o.AssignedTo != nil && *o.AssignedTo == staffID && len(o.OrderItems) > 0 {
+			return o.OrderItems[0].SellerID, true
+		}
+		return 0, false
+	}
+	for _, group := range o.Fulfillments {
+		if group.AssignedTo != nil && *group.AssignedTo == staffID {
+			return group.SellerID, true
+		}
+	}
+	return 0, false
+}
+
+// WasCharged checks if the customer's payment for the order was collected,
+// even if it was since refunded or disputed
+func (o *Order) WasCharged() bool {
+	return o.PaymentStatus == PaymentStatusPaid || o.PaymentStatus == PaymentStatusRefunded || o.PaymentStatus == PaymentStatusDisputed
+}
+
+// IsFinished checks if the order is delivered, cancelled or refunded
+func (o *Order) IsFinished() bool {
+	return o.Status == OrderStatusDelivered || o.Status == OrderStatusCancelled || o.Status == OrderStatusRefunded
+}
+
+// CanRefund checks if the order's payment can still be refunded. Disputed
+// orders can't be refunded until the dispute closes, since the chargeback is
+// already returning the money, and a refunded payment has nothing left.
 func (o *Order) CanRefund() bool {
-	return o.PaymentStatus == PaymentStatusPaid && 
-		(o.Status == OrderStatusDelivered || o.Status == OrderStatusShipped)
+	return o.PaymentStatus != PaymentStatusDisputed && o.PaymentStatus != PaymentStatusRefunded
+}
+
+// IsItemReturnEligible checks whether an item can still be returned under its
+// product's return policy. The window runs from when the item's seller group was
+// delivered, or from the order's delivery when it has no fulfillment groups.
+// The item's Product must be loaded.
+func (o *Order) IsItemReturnEligible(item *OrderItem, now time.Time) bool {
+	return item.Product.IsReturnEligible(o.ItemDeliveredAt(item), now)
+}
+
+// ItemDeliveredAt returns when the item's seller group was delivered, or the
+// order's delivery when it has no fulfillment groups; nil if not yet delivered
+func (o *Order) ItemDeliveredAt(item *OrderItem) *time.Time {
+	if fulfillment := o.FulfillmentForSeller(item.SellerID); fulfillment != nil {
+		return fulfillment.DeliveredAt
+	}
+	return o.DeliveredAt
+}
+
+// ItemForProduct returns the order's first item for the product, or nil
+func (o *Order) ItemForProduct(productID uint) *OrderItem {
+	for i := range o.OrderItems {
+		if o.OrderItems[i].ProductID == productID {
+			return &o.OrderItems[i]
+		}
+	}
+	return nil
+}
+
+// ItemRefundAmount returns what returning quantity units of the item refunds:
+// their share of the line total after its discount and its share of the order
+// discount. Tax and shipping aren't refunded.
+func (o *Order) ItemRefundAmount(item *OrderItem, quantity int) float64 {
+	line := money.FromFloat(item.LineTotal())
+	line -= money.FromFloat(o.DiscountAmount).Share(line, money.FromFloat(o.SubtotalAmount))
+	return line.Share(money.FromMinor(int64(quantity)), money.FromMinor(int64(item.Quantity))).Float()
+}
+
+// FindItem returns the order's item with the given ID, or nil
+func (o *Order) FindItem(itemID uint) *OrderItem {
+	for i := range o.OrderItems {
+		if o.OrderItems[i].ID == itemID {
+			return &o.OrderItems[i]
+		}
+	}
+	return nil
+}
+
+// CanChangeShippingAddress checks if the order hasn't started fulfillment, so
+// it can still be sent somewhere else. A split order is locked once any of its
+// seller groups has shipped.
+func (o *Order) CanChangeShippingAddress() bool {
+	if !o.CanCancel() {
+		return false
+	}
+	for _, fulfillment := range o.Fulfillments {
+		if fulfillment.ShippedAt != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// HasShipped checks if the order, or any part of it, is on its way
+func (o *Order) HasShipped() bool {
+	if o.Status == OrderStatusShipped || o.Status == OrderStatusDelivered || o.ShippedAt != nil {
+		return true
+	}
+	for _, fulfillment := range o.Fulfillments {
+		if fulfillment.ShippedAt != nil {
+			return true
+		}
+	}
+	return false
 }
 
 // CanShip checks if the order can be shipped
@@ -355,7 +1341,17 @@ func (o *Order) GetShippingAddress() string {
 
 // CalculateTotal calculates total price for order item
 func (oi *OrderItem) CalculateTotal() {
-	oi.TotalPrice = oi.UnitPrice * float64(oi.Quantity)
+	oi.TotalPrice = money.FromFloat(oi.UnitPrice).Times(oi.Quantity).Float()
+}
+
+// ApplyDiscount sets the line discount, clamped to the line total
+func (oi *OrderItem) ApplyDiscount(amount float64) {
+	oi.DiscountAmount = money.FromFloat(amount).Clamp(money.FromFloat(oi.TotalPrice)).Float()
+}
+
+// LineTotal returns the line total after the line discount
+func (oi *OrderItem) LineTotal() float64 {
+	return (money.FromFloat(oi.TotalPrice) - money.FromFloat(oi.DiscountAmount)).Float()
 }
 
 // UpdateFromProduct updates order item fields from product
@@ -372,15 +1368,16 @@ func (oi *OrderItem) UpdateFromProduct(product *Product) {
 
 // CalculateTotals calculates cart totals
 func (c *Cart) CalculateTotals() {
-	c.TotalAmount = 0
+	var total money.Amount
 	c.ItemCount = 0
 	
 	for _, item := range c.CartItems {
 		if item.Product.ID != 0 {
-			c.TotalAmount += item.Product.Price * float64(item.Quantity)
+			total += money.FromFloat(item.Product.Price).Times(item.Quantity)
 		}
 		c.ItemCount += item.Quantity
 	}
+	c.TotalAmount = total.Float()
 }
 
 // ToResponse converts Cart to CartResponse
@@ -392,7 +1389,7 @@ func (c *Cart) ToResponse() CartResponse {
 		UpdatedAt:  c.UpdatedAt,
 	}
 	
-	totalAmount := 0.0
+	var totalAmount money.Amount
 	itemCount := 0
 	
 	for _, item := range c.CartItems {
@@ -402,17 +1399,50 @@ func (c *Cart) ToResponse() CartResponse {
 			ProductID: item.ProductID,
 			Product:   item.Product.ToResponse(),
 			Quantity:  item.Quantity,
-			Subtotal:  item.Product.Price * float64(item.Quantity),
+			Subtotal:  money.FromFloat(item.Product.Price).Times(item.Quantity).Float(),
 			CreatedAt: item.CreatedAt,
 			UpdatedAt: item.UpdatedAt,
 		}
 		resp.Items = append(resp.Items, itemResp)
-		totalAmount += itemResp.Subtotal
+		totalAmount += money.FromFloat(itemResp.Subtotal)
 		itemCount += item.Quantity
 	}
 	
-	resp.TotalAmount = totalAmount
+	resp.TotalAmount = totalAmount.Float()
 	resp.ItemCount = itemCount
 	
 	return resp
-}
\ No newline at end of file
+}
+// EstimateDelivery sets the estimated ship and delivery dates, counting
+// processing and transit time in business days from now
+func (o *Order) EstimateDelivery(processingDays, transitDays int, now time.Time) {
+	shipDate := addBusinessDays(now, processingDays)
+	deliveryDate := addBusinessDays(shipDate, transitDays)
+
+	o.ProcessingTimeDays = processingDays
+	o.EstimatedShipDate = &shipDate
+	o.EstimatedDeliveryDate = &deliveryDate
+}
+
+// ShippingEstimate describes when the order ships, e.g. "Ships in 2 business days"
+func (o *Order) ShippingEstimate() string {
+	switch o.ProcessingTimeDays {
+	case 0:
+		return "Ships today"
+	case 1:
+		return "Ships in 1 business day"
+	default:
+		return fmt.Sprintf("Ships in %d business days", o.ProcessingTimeDays)
+	}
+}
+
+// addBusinessDays moves t forward by days, skipping Saturdays and Sundays
+func addBusinessDays(t time.Time, days int) time.Time {
+	for days > 0 {
+		t = t.AddDate(0, 0, 1)
+		if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+			days--
+		}
+	}
+	return t
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
+)
+
+func TestCalculateTotalsAddsUpToTheCent(t *testing.T) {
+	// Summed as float64 these lines come to 0.30000000000000004 and the
+	// total to 5.970000000000001
+	order := &Order{
+		OrderItems: []OrderItem{
+			{Quantity: 1, UnitPrice: 0.1, TotalPrice: 0.1},
+			{Quantity: 2, UnitPrice: 0.1, TotalPrice: 0.2},
+		},
+		TaxAmount:      0.07,
+		ShippingAmount: 5.6,
+	}
+	order.CalculateTotals()
+
+	if order.SubtotalAmount != 0.3 {
+		t.Errorf("SubtotalAmount = %v, want 0.3", order.SubtotalAmount)
+	}
+	if order.TotalAmount != 5.97 {
+		t.Errorf("TotalAmount = %v, want 5.97", order.TotalAmount)
+	}
+	if order.ItemCount != 3 {
+		t.Errorf("ItemCount = %d, want 3", order.ItemCount)
+	}
+}
+
+func TestCalculateTotalsSubtractsLineAndOrderDiscounts(t *testing.T) {
+	order := &Order{
+		OrderItems: []OrderItem{
+			{Quantity: 3, UnitPrice: 19.99, TotalPrice: 59.97, DiscountAmount: 5.99},
+		},
+		DiscountAmount: 0.1,
+	}
+	order.CalculateTotals()
+
+	if order.SubtotalAmount != 53.98 {
+		t.Errorf("SubtotalAmount = %v, want 53.98", order.SubtotalAmount)
+	}
+	if order.TotalAmount != 53.88 {
+		t.Errorf("TotalAmount = %v, want 53.88", order.TotalAmount)
+	}
+}
+
+func TestIsItemReturnEligibleCountsFromTheSellersDelivery(t *testing.T) {
+	window := 7
+	product := Product{Returnable: true, ReturnWindowDays: &window}
+	now := time.Now()
+	delivered := now.AddDate(0, 0, -3)
+	order := &Order{
+		Fulfillments: []OrderFulfillment{
+			{SellerID: 1, DeliveredAt: &delivered},
+			{SellerID: 2},
+		},
+		OrderItems: []OrderItem{
+			{SellerID: 1, Product: product},
+			{SellerID: 2, Product: product},
+		},
+	}
+
+	if !order.IsItemReturnEligible(&order.OrderItems[0], now) {
+		t.Error("item delivered 3 days ago should be returnable in a 7 day window")
+	}
+	if order.IsItemReturnEligible(&order.OrderItems[0], now.AddDate(0, 0, 5)) {
+		t.Error("item delivered 8 days ago should be past a 7 day window")
+	}
+	if order.ItemDeliveredAt(&order.OrderItems[1]) != nil || order.IsItemReturnEligible(&order.OrderItems[1], now) {
+		t.Error("item whose seller hasn't delivered should not be returnable")
+	}
+}
+
+func TestReallocateFulfillmentsKeepsGroupProgress(t *testing.T) {
+	tracking := "1Z999"
+	order := &Order{
+		OrderItems: []OrderItem{
+			{SellerID: 1, Quantity: 1, UnitPrice: 30, TotalPrice: 30},
+			{SellerID: 2, Quantity: 1, UnitPrice: 10, TotalPrice: 10},
+		},
+		ShippingAmount: 8,
+		Fulfillments: []OrderFulfillment{
+			{BaseModel: BaseModel{ID: 4}, SellerID: 1, Status: OrderStatusShipped, TrackingNumber: &tracking, SubtotalAmount: 1},
+			{BaseModel: BaseModel{ID: 5}, SellerID: 2, Status: OrderStatusConfirmed, SubtotalAmount: 1},
+		},
+	}
+	order.CalculateTotals()
+	order.ReallocateFulfillments()
+
+	first, second := order.Fulfillments[0], order.Fulfillments[1]
+	if first.ID != 4 || first.Status != OrderStatusShipped || first.TrackingNumber == nil {
+		t.Errorf("first group = %+v, want its ID, status and tracking kept", first)
+	}
+	if first.SubtotalAmount != 30 || first.ShippingAmount != 6 || first.AllocatedAmount != 36 {
+		t.Errorf("first group amounts = %v/%v/%v, want 30/6/36", first.SubtotalAmount, first.ShippingAmount, first.AllocatedAmount)
+	}
+	if second.ID != 5 || second.AllocatedAmount != 12 {
+		t.Errorf("second group = %+v, want ID 5 allocated 12", second)
+	}
+}
+
+func TestAllocateOrderExportLinesAddsUpToOrderAmounts(t *testing.T) {
+	// Three equal lines can't split 1.00 evenly; the last takes the odd cent
+	order := OrderExportLine{OrderSubtotal: 30, OrderDiscount: 1, OrderTax: 1, OrderShipping: 5, OrderTotal: 35, PaymentStatus: PaymentStatusPaid}
+	lines := make([]*OrderExportLine, 3)
+	for i := range lines {
+		line := order
+		line.Quantity = 2
+		line.LineSubtotal = 10
+		lines[i] = &line
+	}
+	lines[1].RefundedQuantity, lines[1].RefundedAmount = 1, 4.83
+	lines[2].RefundedQuantity, lines[2].RefundedAmount = 2, 9.67
+
+	AllocateOrderExportLines(lines)
+
+	var discount, tax, shipping, total float64
+	for _, line := range lines {
+		discount += line.OrderDiscountShare
+		tax += line.TaxShare
+		shipping += line.ShippingShare
+		total += line.LineTotal
+	}
+	if money.Round(discount) != 1 || money.Round(tax) != 1 || money.Round(shipping) != 5 || money.Round(total) != 35 {
+		t.Errorf("shares add up to discount %.2f, tax %.2f, shipping %.2f, total %.2f; want 1, 1, 5, 35", discount, tax, shipping, total)
+	}
+	if lines[0].TaxShare != 0.33 || lines[2].TaxShare != 0.34 {
+		t.Errorf("tax shares = %.2f, %.2f, want 0.33 then the remaining 0.34", lines[0].TaxShare, lines[2].TaxShare)
+	}
+
+	want := []string{ExportRefundNone, ExportRefundPartial, ExportRefundRefunded}
+	for i, line := range lines {
+		if line.RefundStatus != want[i] {
+			t.Errorf("line %d refund status = %s, want %s", i, line.RefundStatus, want[i])
+		}
+	}
+}
@@ -4,16 +4,19 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // ProductStatus represents product status
 type ProductStatus string
 
 const (
-	ProductStatusDraft     ProductStatus = "draft"
-	ProductStatusActive    ProductStatus = "active"
-	ProductStatusInactive  ProductStatus = "inactive"
-	ProductStatusDeleted   ProductStatus = "deleted"
+	ProductStatusDraft           ProductStatus = "draft"
+	ProductStatusActive          ProductStatus = "active"
+	ProductStatusInactive        ProductStatus = "inactive"
+	ProductStatusDeleted         ProductStatus = "deleted"
+	ProductStatusPendingApproval ProductStatus = "pending_approval" // Hidden until an admin approves it
 )
 
 // ProductCategory represents product categories
@@ -36,8 +39,8 @@ type Product struct {
 	BaseModel
 	Name         string          `json:"name" gorm:"type:varchar(255);not null" validate:"required,min=3,max=255"`
 	Description  string          `json:"description" gorm:"type:text" validate:"required,min=10"`
-	ShortDesc    *string         `json:"short_description,omitempty" gorm:"type:varchar(500)"`
-	SKU          string          `json:"sku" gorm:"type:varchar(100);unique;not null" validate:"required"`
+	ShortDesc    *string         `json:"short_description,omitempty" gorm:"column:short_description;type:varchar(500)"`
+	SKU          string          `json:"sku" gorm:"type:varchar(100);not null;uniqueIndex:idx_products_sku_unique,where:deleted_at IS NULL" validate:"required"`
 	Price        float64         `json:"price" gorm:"type:decimal(10,2);not null" validate:"required,min=0"`
 	ComparePrice *float64        `json:"compare_price,omitempty" gorm:"type:decimal(10,2)" validate:"omitempty,gtfield=Price"`
 	CostPrice    *float64        `json:"cost_price,omitempty" gorm:"type:decimal(10,2)" validate:"omitempty,min=0"`
@@ -48,6 +51,24 @@ type Product struct {
 	LowStockLevel    int  `json:"low_stock_level" gorm:"default:10" validate:"min=0"`
 	TrackInventory   bool `json:"track_inventory" gorm:"default:true"`
 	AllowBackorders  bool `json:"allow_backorders" gorm:"default:false"`
+	MaxBackorderQuantity int `json:"max_backorder_quantity" gorm:"default:0" validate:"min=0"` // Units that may be sold beyond stock
+	OversellTolerance int `json:"oversell_tolerance" gorm:"default:0" validate:"min=0"` // Units checkout may take stock below zero; 0 is strict
+	
+	// Per-customer purchase limit for high-demand products
+	PurchaseLimitPerCustomer int `json:"purchase_limit_per_customer" gorm:"default:0" validate:"min=0"` // 0 means no limit
+	PurchaseLimitWindowDays  int `json:"purchase_limit_window_days" gorm:"default:0" validate:"min=0"`  // 0 counts all past orders
+	
+	// Returns
+	Returnable       bool `json:"returnable" gorm:"not null"`                                       // False for digital or perishable goods; no gorm default so false is written
+	ReturnWindowDays *int `json:"return_window_days,omitempty" validate:"omitempty,min=1,max=365"` // Nil uses DefaultReturnWindowDays
+	RestockingFeePercent *float64 `json:"restocking_fee_percent,omitempty" gorm:"type:decimal(5,2)"` // Kept from returns that aren't for a defect; nil uses the seller's
+	
+	// Business days the seller needs before the item ships
+	ProcessingTimeDays *int `json:"processing_time_days,omitempty" validate:"omitempty,min=0,max=60"` // Nil uses DefaultProcessingTimeDays
+	
+	// Seasonal availability; nil leaves that side of the window open
+	AvailableFrom  *time.Time `json:"available_from,omitempty" gorm:"index"`
+	AvailableUntil *time.Time `json:"available_until,omitempty" gorm:"index"`
 	
 	// Organization
 	Category   string `json:"category" gorm:"type:varchar(50);not null" validate:"required"`
@@ -64,13 +85,18 @@ type Product struct {
 	// SEO
 	MetaTitle       *string `json:"meta_title,omitempty" gorm:"type:varchar(255)"`
 	MetaDescription *string `json:"meta_description,omitempty" gorm:"type:varchar(500)"`
-	Slug            string  `json:"slug" gorm:"type:varchar(255);unique;not null"`
+	Slug            string  `json:"slug" gorm:"type:varchar(255);not null;uniqueIndex:idx_products_slug_unique,where:deleted_at IS NULL"`
 	
 	// Status and visibility - simplified for compatibility
 	IsActive  bool          `json:"is_active" gorm:"default:true"`
 	Status    ProductStatus `json:"status" gorm:"type:varchar(20);not null;default:'draft'" validate:"required"`
 	Featured  bool          `json:"featured" gorm:"default:false"`
 	Visible   bool          `json:"visible" gorm:"default:true"`
+	// Set on products hidden because their seller was deactivated, so that
+	// reactivating the seller restores exactly those
+	HiddenWithSeller bool `json:"-" gorm:"default:false"`
+	// Why an admin last rejected the product; cleared once it's approved
+	RejectionReason *string `json:"rejection_reason,omitempty" gorm:"type:text"`
 	
 	// Images - simplified for compatibility
 	Images []string `json:"images,omitempty" gorm:"-"`
@@ -92,16 +118,25 @@ type Product struct {
 	ReviewCount   int     `json:"review_count" gorm:"column:review_count;default:0"`
 	IsLowStock    bool    `json:"is_low_stock" gorm:"-"`
 	IsInStock     bool    `json:"is_in_stock" gorm:"-"`
+	IsBackorderable bool  `json:"is_backorderable" gorm:"-"`
+	IsAvailable   bool    `json:"is_available" gorm:"-"` // Within the available from/until window
+	LowestRecentPrice *float64 `json:"lowest_recent_price,omitempty" gorm:"-"` // Lowest price in the last LowestPriceWindowDays, set on single-product reads
+	
+	// Set when the content was translated for the request, see ApplyTranslation
+	ContentLocale string              `json:"content_locale,omitempty" gorm:"-"`
+	BaseContent   *ProductBaseContent `json:"base_content,omitempty" gorm:"-"`
 }
 
 // ProductImage represents product images
 type ProductImage struct {
 	BaseModel
-	ProductID uint   `json:"product_id" gorm:"not null"`
+	ProductID uint   `json:"product_id" gorm:"not null;uniqueIndex:idx_product_images_primary_unique,where:is_primary = true AND deleted_at IS NULL"`
 	URL       string `json:"url" gorm:"type:varchar(500);not null" validate:"required,url"`
 	AltText   string `json:"alt_text" gorm:"type:varchar(255)" validate:"max=255"`
 	SortOrder int    `json:"sort_order" gorm:"default:0"`
-	IsPrimary bool   `json:"is_primary" gorm:"default:false"`
+	IsPrimary bool   `json:"is_primary" gorm:"default:false"` // Exactly one per product with images, kept by the repository
+
+	AltTextGenerated bool `json:"alt_text_generated" gorm:"default:false"` // AltText was filled in because none was given
 }
 
 // ProductCreateRequest represents the request to create a product
@@ -118,6 +153,7 @@ type ProductCreateRequest struct {
 	LowStockLevel   int             `json:"low_stock_level" validate:"min=0"`
 	TrackInventory  bool            `json:"track_inventory"`
 	AllowBackorders bool            `json:"allow_backorders"`
+	MaxBackorderQuantity int        `json:"max_backorder_quantity" validate:"min=0"`
 	
 	Category ProductCategory `json:"category" validate:"required"`
 	Tags     []string        `json:"tags,omitempty"`
@@ -144,13 +180,14 @@ type ProductUpdateRequest struct {
 	Description  *string         `json:"description,omitempty" validate:"omitempty,min=10"`
 	ShortDesc    *string         `json:"short_description,omitempty" validate:"omitempty,max=500"`
 	Price        *float64        `json:"price,omitempty" validate:"omitempty,min=0"`
-	ComparePrice *float64        `json:"compare_price,omitempty"`
+	ComparePrice *float64        `json:"compare_price,omitempty" validate:"omitempty,min=0"` // Checked against the resulting price in UpdateProduct
 	CostPrice    *float64        `json:"cost_price,omitempty" validate:"omitempty,min=0"`
 	
 	StockQuantity   *int  `json:"stock_quantity,omitempty" validate:"omitempty,min=0"`
 	LowStockLevel   *int  `json:"low_stock_level,omitempty" validate:"omitempty,min=0"`
 	TrackInventory  *bool `json:"track_inventory,omitempty"`
 	AllowBackorders *bool `json:"allow_backorders,omitempty"`
+	MaxBackorderQuantity *int `json:"max_backorder_quantity,omitempty" validate:"omitempty,min=0"`
 	
 	Category *ProductCategory `json:"category,omitempty"`
 	Tags     []string         `json:"tags,omitempty"`
@@ -187,6 +224,7 @@ type ProductListRequest struct {
 	MinPrice     *float64          `query:"min_price" validate:"omitempty,min=0"`
 	MaxPrice     *float64          `query:"max_price" validate:"omitempty,min=0"`
 	InStock      *bool             `query:"in_stock"`
+	StockStatus  *StockStatus      `query:"stock_status" validate:"omitempty,oneof=in_stock low_stock out_of_stock backorderable"`
 	Featured     *bool             `query:"featured"`
 	Search       string            `query:"search"`
 	SortBy       string            `query:"sort_by" validate:"omitempty,oneof=name price created_at updated_at view_count rating"`
@@ -207,41 +245,283 @@ type ProductStatsResponse struct {
 type CreateProductRequest struct {
 	Name        string   `json:"name" validate:"required,min=3,max=255"`
 	Description string   `json:"description" validate:"required,min=10"`
+	SKU         string   `json:"sku" validate:"required,max=100"` // Unique among live products
 	Price       float64  `json:"price" validate:"required,min=0"`
 	Stock       int      `json:"stock" validate:"min=0"`
 	Category    string   `json:"category" validate:"required"`
 	Images      []string `json:"images,omitempty"`
+	Tags        []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=100"`
+	
+	AllowBackorders      bool `json:"allow_backorders"`
+	MaxBackorderQuantity int  `json:"max_backorder_quantity" validate:"min=0"`
+	OversellTolerance    int  `json:"oversell_tolerance" validate:"min=0,max=1000"` // 0 never oversells
+	
+	PurchaseLimitPerCustomer int `json:"purchase_limit_per_customer" validate:"min=0"`         // 0 means no limit
+	PurchaseLimitWindowDays  int `json:"purchase_limit_window_days" validate:"min=0,max=365"` // 0 counts all past orders
+	
+	Returnable       *bool `json:"returnable,omitempty"`                        // Defaults to true
+	ReturnWindowDays int   `json:"return_window_days" validate:"min=0,max=365"` // 0 uses the store default
+	RestockingFeePercent *float64 `json:"restocking_fee_percent,omitempty" validate:"omitempty,min=0,max=100"` // Omitted uses the seller's
+	ProcessingTimeDays *int `json:"processing_time_days,omitempty" validate:"omitempty,min=0,max=60"` // Omitted uses the store default
+	
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`
+	AvailableUntil *time.Time `json:"available_until,omitempty"` // Must not be before available_from
 }
 
 type UpdateProductRequest struct {
-	Name        *string  `json:"name,omitempty" validate:"omitempty,min=3,max=255"`
-	Description *string  `json:"description,omitempty" validate:"omitempty,min=10"`
-	Price       *float64 `json:"price,omitempty" validate:"omitempty,min=0"`
-	Stock       *int     `json:"stock,omitempty" validate:"omitempty,min=0"`
-	Category    *string  `json:"category,omitempty"`
-	Images      []string `json:"images,omitempty"`
-	IsActive    *bool    `json:"is_active,omitempty"`
+	Name         *string  `json:"name,omitempty" validate:"omitempty,min=3,max=255"`
+	Description  *string  `json:"description,omitempty" validate:"omitempty,min=10"`
+	Price        *float64 `json:"price,omitempty" validate:"omitempty,min=0"`
+	ComparePrice *float64 `json:"compare_price,omitempty" validate:"omitempty,min=0"` // 0 clears the compare price
+	Stock        *int     `json:"stock,omitempty" validate:"omitempty,min=0"`
+	Category     *string  `json:"category,omitempty"`
+	Images       []string `json:"images,omitempty"`
+	Tags         []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=100"`
+	IsActive     *bool    `json:"is_active,omitempty"`
+	
+	AllowBackorders      *bool `json:"allow_backorders,omitempty"`
+	MaxBackorderQuantity *int  `json:"max_backorder_quantity,omitempty" validate:"omitempty,min=0"`
+	OversellTolerance    *int  `json:"oversell_tolerance,omitempty" validate:"omitempty,min=0,max=1000"`
+	
+	PurchaseLimitPerCustomer *int `json:"purchase_limit_per_customer,omitempty" validate:"omitempty,min=0"`
+	PurchaseLimitWindowDays  *int `json:"purchase_limit_window_days,omitempty" validate:"omitempty,min=0,max=365"`
+	
+	Returnable       *bool `json:"returnable,omitempty"`
+	ReturnWindowDays *int  `json:"return_window_days,omitempty" validate:"omitempty,min=0,max=365"` // 0 reverts to the store default
+	RestockingFeePercent *float64 `json:"restocking_fee_percent,omitempty" validate:"omitempty,min=0,max=100"`
+	ProcessingTimeDays *int `json:"processing_time_days,omitempty" validate:"omitempty,min=0,max=60"`
+	
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`  // The zero time clears it
+	AvailableUntil *time.Time `json:"available_until,omitempty"` // The zero time clears it
+}
+
+// ChangesContent checks if the update changes what shoppers see of the
+// product: its name, description, category, images or tags
+func (r *UpdateProductRequest) ChangesContent(p *Product) bool {
+	return (r.Name != nil && *r.Name != p.Name) ||
+		(r.Description != nil && *r.Description != p.Description) ||
+		(r.Category != nil && *r.Category != p.Category) ||
+		r.Images != nil || r.Tags != nil
+}
+
+// ProductSort is the order a product listing is shown in
+type ProductSort string
+
+const (
+	ProductSortNewest      ProductSort = "newest"
+	ProductSortBestSelling ProductSort = "best_selling" // Units sold on orders that weren't cancelled or refunded
+	ProductSortPriceAsc    ProductSort = "price_asc"
+	ProductSortPriceDesc   ProductSort = "price_desc"
+	ProductSortRating      ProductSort = "rating"
+	ProductSortPopular     ProductSort = "popular" // Most viewed
+	ProductSortName        ProductSort = "name"
+)
+
+// IsValid checks if the sort is one listings support
+func (s ProductSort) IsValid() bool {
+	switch s {
+	case ProductSortNewest, ProductSortBestSelling, ProductSortPriceAsc, ProductSortPriceDesc,
+		ProductSortRating, ProductSortPopular, ProductSortName:
+		return true
+	}
+	return false
 }
 
 type GetProductsRequest struct {
-	Page     int    `json:"page"`
-	Limit    int    `json:"limit"`
-	Offset   int    `json:"offset"`
-	Category string `json:"category,omitempty"`
-	Search   string `json:"search,omitempty"`
-	SellerID *uint  `json:"seller_id,omitempty"`
+	Page      int          `json:"page"`
+	Limit     int          `json:"limit"`
+	Offset    int          `json:"offset"`
+	Category  string       `json:"category,omitempty"`
+	Search    string       `json:"search,omitempty"`
+	SortBy    ProductSort  `json:"sort_by,omitempty"` // Empty uses the category's default sort, then the store's
+	SellerID  *uint        `json:"seller_id,omitempty"`
+	MinPrice  *float64     `json:"min_price,omitempty"`
+	MaxPrice  *float64     `json:"max_price,omitempty"`
+	PriceTier PriceTier    `json:"price_tier,omitempty"`
+	Tags      []string     `json:"tags,omitempty"`
+	TagMatch  TagMatchMode `json:"tag_match,omitempty"`
+	StockStatus StockStatus `json:"stock_status,omitempty"`
+	
+	IncludeUnavailable bool `json:"include_unavailable,omitempty"` // Also list products outside their available window
+}
+
+// RatingGate keeps poorly rated products out of featured and recommended lists.
+// Products with at least MinReviews reviews must average MinRating or more;
+// products with fewer reviews have no reliable rating yet and are included
+// only when IncludeFewReviews is set.
+type RatingGate struct {
+	MinRating         float64
+	MinReviews        int
+	IncludeFewReviews bool
+}
+
+// Enabled reports whether the gate filters anything
+func (g RatingGate) Enabled() bool {
+	return g.MinRating > 0 || g.MinReviews > 0
+}
+
+// PriceTier represents a budget bucket used for browsing by price
+type PriceTier string
+
+const (
+	PriceTierUnder25 PriceTier = "under_25"
+	PriceTier25To50  PriceTier = "25_50"
+	PriceTier50To100 PriceTier = "50_100"
+	PriceTierOver100 PriceTier = "100_plus"
+)
+
+// PriceTierRange defines a tier's bounds. Min is inclusive, Max is exclusive; zero Max means no upper bound.
+type PriceTierRange struct {
+	Tier  PriceTier
+	Label string
+	Min   float64
+	Max   float64
+}
+
+// PriceTierRanges lists the budget tiers in display order
+var PriceTierRanges = []PriceTierRange{
+	{Tier: PriceTierUnder25, Label: "Under $25", Min: 0, Max: 25},
+	{Tier: PriceTier25To50, Label: "$25 - $50", Min: 25, Max: 50},
+	{Tier: PriceTier50To100, Label: "$50 - $100", Min: 50, Max: 100},
+	{Tier: PriceTierOver100, Label: "$100+", Min: 100, Max: 0},
+}
+
+// GetPriceTierRange returns the bounds for a price tier
+func GetPriceTierRange(tier PriceTier) (PriceTierRange, bool) {
+	for _, r := range PriceTierRanges {
+		if r.Tier == tier {
+			return r, true
+		}
+	}
+	return PriceTierRange{}, false
+}
+
+// Tag represents a normalized product tag shared across products
+type Tag struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_tags_name_unique,where:deleted_at IS NULL"`
+	CreatedAt time.Time      `json:"created_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// ProductTag links a product to a tag. The rows are kept in sync with
+// Product.Tags so listings can filter by tag without scanning the CSV column.
+type ProductTag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProductID uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_product_tags_product_tag"`
+	TagID     uint      `json:"tag_id" gorm:"not null;uniqueIndex:idx_product_tags_product_tag;index"`
+	Tag       Tag       `json:"tag,omitempty" gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LowestPriceWindowDays is the look-back period for Product.LowestRecentPrice
+const LowestPriceWindowDays = 30
+
+// PriceHistory records a change to a product's price
+type PriceHistory struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProductID uint      `json:"product_id" gorm:"not null;index:idx_price_histories_product_created"`
+	OldPrice  float64   `json:"old_price" gorm:"type:decimal(10,2);not null"`
+	NewPrice  float64   `json:"new_price" gorm:"type:decimal(10,2);not null"`
+	ChangedBy uint      `json:"changed_by" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_price_histories_product_created"`
+}
+
+// TagMatchMode controls how a multi-tag filter is applied
+type TagMatchMode string
+
+const (
+	TagMatchAny TagMatchMode = "any"
+	TagMatchAll TagMatchMode = "all"
+)
+
+// StockStatus filters listings by where a product's stock sits. The statuses
+// don't overlap: a low stock product is also in stock but is only matched by
+// StockStatusLowStock when asked for, and an out of stock product that can
+// still take backorders is backorderable rather than out of stock.
+type StockStatus string
+
+const (
+	StockStatusInStock       StockStatus = "in_stock"      // Untracked, or stock above zero
+	StockStatusLowStock      StockStatus = "low_stock"     // Tracked, above zero and at or below the low stock level
+	StockStatusOutOfStock    StockStatus = "out_of_stock"  // Tracked, none left and no backorders possible
+	StockStatusBackorderable StockStatus = "backorderable" // Tracked, none left but backorders still allowed
+)
+
+// IsValid reports whether s is a known stock status
+func (s StockStatus) IsValid() bool {
+	switch s {
+	case StockStatusInStock, StockStatusLowStock, StockStatusOutOfStock, StockStatusBackorderable:
+		return true
+	}
+	return false
+}
+
+// TagCount represents a tag facet with its product count
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// PriceTierCount represents a price tier facet with its product count
+type PriceTierCount struct {
+	Tier  PriceTier `json:"tier"`
+	Label string    `json:"label"`
+	Min   float64   `json:"min"`
+	Max   *float64  `json:"max,omitempty"`
+	Count int64     `json:"count"`
 }
 
 type UpdateStockRequest struct {
 	Stock int `json:"stock" validate:"min=0"`
 }
 
+// ProductRejectRequest represents an admin rejecting a product awaiting approval
+type ProductRejectRequest struct {
+	Reason string `json:"reason" validate:"required,min=5,max=1000"` // Sent to the seller
+}
+
+// MaxBatchProductIDs caps how many products one batch fetch may request
+const MaxBatchProductIDs = 100
+
+// BatchProductsRequest represents the request to fetch several products at once
+type BatchProductsRequest struct {
+	ProductIDs []uint `json:"product_ids" validate:"required,min=1,max=100,dive,required"`
+}
+
+// BulkVisibilityRequest represents the request to show or hide many products
+// at once. Visible defaults to whether Status is active when only the status
+// is given.
+type BulkVisibilityRequest struct {
+	ProductIDs []uint         `json:"product_ids" validate:"required,min=1,max=100,dive,required"`
+	Visible    *bool          `json:"visible,omitempty" validate:"required_without=Status"`
+	Status     *ProductStatus `json:"status,omitempty" validate:"omitempty,oneof=draft active inactive"`
+}
+
+// VisibilityItemResult represents the outcome for a single product in a bulk visibility change
+type VisibilityItemResult struct {
+	ProductID uint   `json:"product_id"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkVisibilityResponse represents the outcome of a bulk visibility change
+type BulkVisibilityResponse struct {
+	Visible   bool                   `json:"visible"`
+	Status    *ProductStatus         `json:"status,omitempty"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []VisibilityItemResult `json:"results"`
+}
+
 // Response models
 type ProductListResponse struct {
-	Products []*Product `json:"products"`
-	Total    int64      `json:"total"`
-	Page     int        `json:"page"`
-	Limit    int        `json:"limit"`
+	Products   []*Product       `json:"products"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	Limit      int              `json:"limit"`
+	PriceTiers []PriceTierCount `json:"price_tiers,omitempty"`
+	Tags       []TagCount       `json:"tags,omitempty"`
 }
 
 // ProductResponse represents the product response
@@ -259,6 +539,15 @@ type ProductResponse struct {
 	LowStockLevel   int                     `json:"low_stock_level"`
 	TrackInventory  bool                    `json:"track_inventory"`
 	AllowBackorders bool                    `json:"allow_backorders"`
+	MaxBackorderQuantity int                `json:"max_backorder_quantity"`
+	OversellTolerance    int                `json:"oversell_tolerance"`
+	PurchaseLimitPerCustomer int            `json:"purchase_limit_per_customer"`
+	PurchaseLimitWindowDays  int            `json:"purchase_limit_window_days"`
+	Returnable      bool                    `json:"returnable"`
+	ReturnWindowDays int                    `json:"return_window_days"` // Effective window; 0 when not returnable
+	ProcessingTimeDays int                  `json:"processing_time_days"` // Effective business days before shipping
+	AvailableFrom   *time.Time              `json:"available_from,omitempty"`
+	AvailableUntil  *time.Time              `json:"available_until,omitempty"`
 	Category        string                  `json:"category"`
 	CategoryID      *uint                   `json:"category_id,omitempty"`
 	Tags            []string                `json:"tags,omitempty"`
@@ -282,6 +571,11 @@ type ProductResponse struct {
 	ReviewCount     int                     `json:"review_count"`
 	IsLowStock      bool                    `json:"is_low_stock"`
 	IsInStock       bool                    `json:"is_in_stock"`
+	IsBackorderable bool                    `json:"is_backorderable"`
+	IsAvailable     bool                    `json:"is_available"`
+	LowestRecentPrice *float64              `json:"lowest_recent_price,omitempty"`
+	ContentLocale   string                  `json:"content_locale,omitempty"`
+	BaseContent     *ProductBaseContent     `json:"base_content,omitempty"`
 	CreatedAt       time.Time               `json:"created_at"`
 	UpdatedAt       time.Time               `json:"updated_at"`
 	
@@ -292,14 +586,15 @@ type ProductResponse struct {
 
 // ProductImageResponse represents product image response
 type ProductImageResponse struct {
-	ID        uint      `json:"id"`
-	ProductID uint      `json:"product_id"`
-	URL       string    `json:"url"`
-	AltText   string    `json:"alt_text"`
-	SortOrder int       `json:"sort_order"`
-	IsPrimary bool      `json:"is_primary"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID               uint      `json:"id"`
+	ProductID        uint      `json:"product_id"`
+	URL              string    `json:"url"`
+	AltText          string    `json:"alt_text"`
+	AltTextGenerated bool      `json:"alt_text_generated"`
+	SortOrder        int       `json:"sort_order"`
+	IsPrimary        bool      `json:"is_primary"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 // ToResponse converts Product to ProductResponse
@@ -320,6 +615,15 @@ func (p *Product) ToResponse() ProductResponse {
 		LowStockLevel:   p.LowStockLevel,
 		TrackInventory:  p.TrackInventory,
 		AllowBackorders: p.AllowBackorders,
+		MaxBackorderQuantity: p.MaxBackorderQuantity,
+		OversellTolerance:    p.OversellTolerance,
+		PurchaseLimitPerCustomer: p.PurchaseLimitPerCustomer,
+		PurchaseLimitWindowDays:  p.PurchaseLimitWindowDays,
+		Returnable:      p.Returnable,
+		ReturnWindowDays: p.EffectiveReturnWindowDays(),
+		ProcessingTimeDays: p.EffectiveProcessingTimeDays(),
+		AvailableFrom:   p.AvailableFrom,
+		AvailableUntil:  p.AvailableUntil,
 		Category:        p.Category,
 		CategoryID:      p.CategoryID,
 		Tags:            p.GetTagsList(),
@@ -342,6 +646,11 @@ func (p *Product) ToResponse() ProductResponse {
 		ReviewCount:     p.ReviewCount,
 		IsLowStock:      p.IsLowStock,
 		IsInStock:       p.IsInStock,
+		IsBackorderable: p.IsBackorderable,
+		IsAvailable:     p.IsAvailable,
+		LowestRecentPrice: p.LowestRecentPrice,
+		ContentLocale:   p.ContentLocale,
+		BaseContent:     p.BaseContent,
 		CreatedAt:       p.CreatedAt,
 		UpdatedAt:       p.UpdatedAt,
 		DiscountPercent: p.CalculateDiscount(),
@@ -352,14 +661,15 @@ func (p *Product) ToResponse() ProductResponse {
 	if p.ProductImages != nil {
 		for _, img := range p.ProductImages {
 			resp.ProductImages = append(resp.ProductImages, ProductImageResponse{
-				ID:        img.ID,
-				ProductID: img.ProductID,
-				URL:       img.URL,
-				AltText:   img.AltText,
-				SortOrder: img.SortOrder,
-				IsPrimary: img.IsPrimary,
-				CreatedAt: img.CreatedAt,
-				UpdatedAt: img.UpdatedAt,
+				ID:               img.ID,
+				ProductID:        img.ProductID,
+				URL:              img.URL,
+				AltText:          img.AltText,
+				AltTextGenerated: img.AltTextGenerated,
+				SortOrder:        img.SortOrder,
+				IsPrimary:        img.IsPrimary,
+				CreatedAt:        img.CreatedAt,
+				UpdatedAt:        img.UpdatedAt,
 			})
 		}
 	}
@@ -367,6 +677,15 @@ func (p *Product) ToResponse() ProductResponse {
 	return resp
 }
 
+// ShippingWeight returns the weight of quantity units for shipping quotes.
+// Products without a weight count as weightless.
+func (p *Product) ShippingWeight(quantity int) float64 {
+	if p.Weight == nil {
+		return 0
+	}
+	return *p.Weight * float64(quantity)
+}
+
 // GetTagsList returns tags as a slice
 func (p *Product) GetTagsList() []string {
 	if p.Tags == "" {
@@ -381,6 +700,7 @@ func (p *Product) GetTagsList() []string {
 
 // SetTagsList sets tags from a slice
 func (p *Product) SetTagsList(tags []string) {
+	tags = NormalizeTags(tags)
 	if len(tags) == 0 {
 		p.Tags = ""
 		return
@@ -388,10 +708,111 @@ func (p *Product) SetTagsList(tags []string) {
 	p.Tags = strings.Join(tags, ",")
 }
 
+// NormalizeTags lowercases and trims tags, dropping blanks and duplicates
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
 // UpdateComputedFields updates computed fields
 func (p *Product) UpdateComputedFields() {
-	p.IsLowStock = p.TrackInventory && p.StockQuantity <= p.LowStockLevel
-	p.IsInStock = !p.TrackInventory || p.StockQuantity > 0
+	p.IsLowStock = p.TrackInventory && p.Stock <= p.LowStockLevel
+	p.IsInStock = !p.TrackInventory || p.Stock > 0
+	p.IsBackorderable = !p.IsInStock && p.BackorderAvailable() > 0
+	p.IsAvailable = p.IsAvailableAt(time.Now())
+}
+
+// IsAvailableAt checks whether now falls within the product's available
+// from/until window. Products without a window are always available.
+func (p *Product) IsAvailableAt(now time.Time) bool {
+	if p.AvailableFrom != nil && now.Before(*p.AvailableFrom) {
+		return false
+	}
+	if p.AvailableUntil != nil && now.After(*p.AvailableUntil) {
+		return false
+	}
+	return true
+}
+
+// BackorderAvailable returns how many more units can be sold beyond current stock
+func (p *Product) BackorderAvailable() int {
+	if !p.TrackInventory || !p.AllowBackorders {
+		return 0
+	}
+	// Stock can already be negative from earlier backorders
+	floor := -p.MaxBackorderQuantity
+	if p.Stock > 0 {
+		return p.MaxBackorderQuantity
+	}
+	if p.Stock <= floor {
+		return 0
+	}
+	return p.Stock - floor
+}
+
+// BackorderLimit returns how many units checkout may sell beyond stock as
+// backorders; 0 when the product doesn't take backorders
+func (p *Product) BackorderLimit() int {
+	if !p.AllowBackorders {
+		return 0
+	}
+	return p.MaxBackorderQuantity
+}
+
+// DefaultReturnWindowDays is the return window for products without their own.
+// It is set from configuration at startup.
+var DefaultReturnWindowDays = 30
+
+// EffectiveReturnWindowDays returns the product's return window, falling back
+// to the store default. Non-returnable products have no window.
+func (p *Product) EffectiveReturnWindowDays() int {
+	if !p.Returnable {
+		return 0
+	}
+	if p.ReturnWindowDays != nil {
+		return *p.ReturnWindowDays
+	}
+	return DefaultReturnWindowDays
+}
+
+// DefaultProcessingTimeDays is the processing time for products without their own.
+// It is set from configuration at startup.
+var DefaultProcessingTimeDays = 2
+
+// EffectiveProcessingTimeDays returns the business days the product takes to
+// ship, falling back to the store default
+func (p *Product) EffectiveProcessingTimeDays() int {
+	if p.ProcessingTimeDays != nil {
+		return *p.ProcessingTimeDays
+	}
+	return DefaultProcessingTimeDays
+}
+
+// ReturnDeadline returns the last moment the product can be returned for an
+// order delivered at deliveredAt, or nil if it can't be returned at all
+func (p *Product) ReturnDeadline(deliveredAt *time.Time) *time.Time {
+	days := p.EffectiveReturnWindowDays()
+	if deliveredAt == nil || days == 0 {
+		return nil
+	}
+	deadline := deliveredAt.AddDate(0, 0, days)
+	return &deadline
+}
+
+// IsReturnEligible checks whether the product can still be returned for an
+// order delivered at deliveredAt. Undelivered orders are not yet eligible.
+func (p *Product) IsReturnEligible(deliveredAt *time.Time, now time.Time) bool {
+	deadline := p.ReturnDeadline(deliveredAt)
+	return deadline != nil && !now.After(*deadline)
 }
 
 // GenerateSlug generates a URL-friendly slug from the product name
@@ -408,12 +829,38 @@ func (p *Product) GenerateSlug() {
 	p.Slug = result.String()
 }
 
+// IsVisibleTo checks if the user may see the product. Products awaiting
+// approval are only shown to their seller and admins; anonymous viewers pass 0.
+func (p *Product) IsVisibleTo(userID uint, role UserRole) bool {
+	if p.Status != ProductStatusPendingApproval {
+		return true
+	}
+	return role == RoleAdmin || (userID != 0 && p.SellerID == userID)
+}
+
+// NeedsApproval checks if the product is awaiting approval or was rejected;
+// either way only an admin's approval lists it
+func (p *Product) NeedsApproval() bool {
+	return p.Status == ProductStatusPendingApproval || p.RejectionReason != nil
+}
+
+// HoldForApproval takes the product out of listings until an admin approves it
+func (p *Product) HoldForApproval() {
+	p.Status = ProductStatusPendingApproval
+	p.IsActive = false
+	p.Visible = false
+}
+
 // CanOrder checks if the product can be ordered
 func (p *Product) CanOrder(quantity int) bool {
 	if p.Status != ProductStatusActive || !p.Visible {
 		return false
 	}
 	
+	if !p.IsAvailableAt(time.Now()) {
+		return false
+	}
+	
 	if !p.TrackInventory {
 		return true
 	}
@@ -422,7 +869,23 @@ func (p *Product) CanOrder(quantity int) bool {
 		return true
 	}
 	
-	return p.AllowBackorders
+	return p.canBackorder(quantity)
+}
+
+// CanFulfill checks whether checkout can take quantity from stock without going
+// further below zero than the product's backorder limit or oversell tolerance,
+// whichever is larger. ProductRepository.DecrementStock applies the same floor.
+func (p *Product) CanFulfill(quantity int) bool {
+	floor := p.OversellTolerance
+	if limit := p.BackorderLimit(); limit > floor {
+		floor = limit
+	}
+	return p.Stock-quantity >= -floor
+}
+
+// canBackorder checks whether quantity fits within stock plus the backorder limit
+func (p *Product) canBackorder(quantity int) bool {
+	return p.AllowBackorders && p.StockQuantity-quantity >= -p.MaxBackorderQuantity
 }
 
 // CalculateDiscount calculates discount percentage if compare price is set
@@ -468,7 +931,7 @@ func (p *Product) ReserveStock(quantity int) error {
 		return nil
 	}
 	
-	if p.StockQuantity < quantity && !p.AllowBackorders {
+	if p.StockQuantity < quantity && !p.canBackorder(quantity) {
 		return fmt.Errorf("insufficient stock: available %d, requested %d", p.StockQuantity, quantity)
 	}
 	
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// ProductAttentionReason is why a product is on its seller's list of listings
+// to fix
+type ProductAttentionReason string
+
+const (
+	AttentionOutOfStock              ProductAttentionReason = "out_of_stock"
+	AttentionLowRating               ProductAttentionReason = "low_rating" // Rated below the listing gate, see RatingGate
+	AttentionMissingImages           ProductAttentionReason = "missing_images"
+	AttentionMissingCategory         ProductAttentionReason = "missing_category" // Not placed in the category tree
+	AttentionMissingDescription      ProductAttentionReason = "missing_description"
+	AttentionStaleDraft              ProductAttentionReason = "stale_draft" // Still a draft after StaleDraftDays
+	AttentionMissingShortDescription ProductAttentionReason = "missing_short_description"
+)
+
+// ProductAttentionReasons lists every reason, most pressing first
+var ProductAttentionReasons = []ProductAttentionReason{
+	AttentionOutOfStock,
+	AttentionLowRating,
+	AttentionMissingImages,
+	AttentionMissingCategory,
+	AttentionMissingDescription,
+	AttentionStaleDraft,
+	AttentionMissingShortDescription,
+}
+
+// IsValid checks if the reason is one of ProductAttentionReasons
+func (r ProductAttentionReason) IsValid() bool {
+	for _, reason := range ProductAttentionReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductAttentionCriteria sets the thresholds a seller's products are
+// checked against
+type ProductAttentionCriteria struct {
+	Rating      RatingGate
+	DraftBefore time.Time               // Drafts created before this are stale
+	Reason      *ProductAttentionReason // Only list products flagged for this
+}
+
+// ProductAttention is a product on its seller's list of listings to fix, with
+// every reason it was flagged for, most pressing first
+type ProductAttention struct {
+	ProductID     uint                     `json:"product_id"`
+	Name          string                   `json:"name"`
+	SKU           string                   `json:"sku"`
+	Status        ProductStatus            `json:"status"`
+	Stock         int                      `json:"stock"`
+	AverageRating float64                  `json:"average_rating"`
+	ReviewCount   int                      `json:"review_count"`
+	CreatedAt     time.Time                `json:"created_at"`
+	Reasons       []ProductAttentionReason `json:"reasons"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page sizes of the product change feed
+const (
+	DefaultProductChangesLimit = 100
+	MaxProductChangesLimit     = 500
+)
+
+// ProductChangeType says what happened to a product since the feed's start time
+type ProductChangeType string
+
+const (
+	ProductChangeCreated ProductChangeType = "created"
+	ProductChangeUpdated ProductChangeType = "updated"
+	ProductChangeDeleted ProductChangeType = "deleted"
+)
+
+// ProductChange is a product's current sync state in the change feed. Deleted
+// products are tombstones: they keep their last stock and status but are no
+// longer sold.
+type ProductChange struct {
+	ID        uint              `json:"id"`
+	SKU       string            `json:"sku"`
+	Change    ProductChangeType `json:"change"`
+	Stock     int               `json:"stock"`
+	Status    ProductStatus     `json:"status"`
+	IsActive  bool              `json:"is_active"`
+	Price     float64           `json:"price"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	DeletedAt *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// ProductChangesResponse is one page of the product change feed, oldest change
+// first. Pass NextCursor back to read the next page.
+type ProductChangesResponse struct {
+	Changes    []ProductChange `json:"changes"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
+// ProductChangeCursor marks a position in the change feed: the feed's start
+// time and the last product returned, ordered by (updated_at, id)
+type ProductChangeCursor struct {
+	Since     time.Time
+	UpdatedAt time.Time
+	ID        uint
+}
+
+// NewProductChange describes the product as seen by a feed that started at since
+func NewProductChange(p *Product, since time.Time) ProductChange {
+	change := ProductChange{
+		ID:        p.ID,
+		SKU:       p.SKU,
+		Change:    ProductChangeUpdated,
+		Stock:     p.Stock,
+		Status:    p.Status,
+		IsActive:  p.IsActive,
+		Price:     p.Price,
+		UpdatedAt: p.UpdatedAt,
+	}
+	switch {
+	case p.DeletedAt.Valid:
+		deletedAt := p.DeletedAt.Time
+		change.Change = ProductChangeDeleted
+		change.DeletedAt = &deletedAt
+	case p.CreatedAt.After(since):
+		change.Change = ProductChangeCreated
+	}
+	return change
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c ProductChangeCursor) Encode() string {
+	raw := strings.Join([]string{
+		c.Since.Format(time.RFC3339Nano),
+		c.UpdatedAt.Format(time.RFC3339Nano),
+		strconv.FormatUint(uint64(c.ID), 10),
+	}, "|")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeProductChangeCursor parses a cursor returned by Encode
+func DecodeProductChangeCursor(cursor string) (ProductChangeCursor, error) {
+	invalid := errors.New("invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ProductChangeCursor{}, invalid
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return ProductChangeCursor{}, invalid
+	}
+
+	since, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return ProductChangeCursor{}, invalid
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return ProductChangeCursor{}, invalid
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return ProductChangeCursor{}, invalid
+	}
+
+	return ProductChangeCursor{Since: since, UpdatedAt: updatedAt, ID: uint(id)}, nil
+}
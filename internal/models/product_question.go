@@ -0,0 +1,119 @@
+package models
+
+import "time"
+
+// AnswerAuthorType identifies who answered a product question
+type AnswerAuthorType string
+
+const (
+	AnswerAuthorSeller   AnswerAuthorType = "seller"   // The product's own seller
+	AnswerAuthorStaff    AnswerAuthorType = "staff"    // An admin
+	AnswerAuthorCustomer AnswerAuthorType = "customer" // Any other user
+)
+
+// ProductQuestion represents a question asked about a product
+type ProductQuestion struct {
+	BaseModel
+	ProductID uint            `json:"product_id" gorm:"not null;index"`
+	Product   Product         `json:"-" gorm:"foreignKey:ProductID"`
+	UserID    uint            `json:"user_id" gorm:"not null;index"`
+	User      User            `json:"-" gorm:"foreignKey:UserID"`
+	Question  string          `json:"question" gorm:"type:text;not null"`
+	Answers   []ProductAnswer `json:"answers,omitempty" gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+}
+
+// ProductAnswer represents an answer to a product question
+type ProductAnswer struct {
+	BaseModel
+	QuestionID uint             `json:"question_id" gorm:"not null;index"`
+	UserID     uint             `json:"user_id" gorm:"not null"`
+	User       User             `json:"-" gorm:"foreignKey:UserID"`
+	Answer     string           `json:"answer" gorm:"type:text;not null"`
+	AuthorType AnswerAuthorType `json:"author_type" gorm:"type:varchar(20);not null;default:'customer'"` // Snapshot taken when answering
+}
+
+// AskQuestionRequest represents the request to ask a question about a product
+type AskQuestionRequest struct {
+	Question string `json:"question" validate:"required,min=10,max=1000"`
+}
+
+// AnswerQuestionRequest represents the request to answer a product question
+type AnswerQuestionRequest struct {
+	Answer string `json:"answer" validate:"required,min=2,max=2000"`
+}
+
+// ProductQuestionResponse represents a question with its answers, official answers first
+type ProductQuestionResponse struct {
+	ID        uint                    `json:"id"`
+	ProductID uint                    `json:"product_id"`
+	Question  string                  `json:"question"`
+	AskedBy   string                  `json:"asked_by"`
+	Answers   []ProductAnswerResponse `json:"answers"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// ProductAnswerResponse represents an answer with its author badge
+type ProductAnswerResponse struct {
+	ID         uint             `json:"id"`
+	QuestionID uint             `json:"question_id"`
+	Answer     string           `json:"answer"`
+	AnsweredBy string           `json:"answered_by"`
+	AuthorType AnswerAuthorType `json:"author_type"`
+	Badge      *string          `json:"badge,omitempty"` // "Seller" or "Staff"; customers have none
+	IsOfficial bool             `json:"is_official"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// IsOfficial checks if the answer came from the seller or staff
+func (a *ProductAnswer) IsOfficial() bool {
+	return a.AuthorType == AnswerAuthorSeller || a.AuthorType == AnswerAuthorStaff
+}
+
+// ToResponse converts ProductAnswer to ProductAnswerResponse
+func (a *ProductAnswer) ToResponse() ProductAnswerResponse {
+	resp := ProductAnswerResponse{
+		ID:         a.ID,
+		QuestionID: a.QuestionID,
+		Answer:     a.Answer,
+		AnsweredBy: a.User.FirstName,
+		AuthorType: a.AuthorType,
+		IsOfficial: a.IsOfficial(),
+		CreatedAt:  a.CreatedAt,
+	}
+
+	var badge string
+	switch a.AuthorType {
+	case AnswerAuthorSeller:
+		badge = "Seller"
+	case AnswerAuthorStaff:
+		badge = "Staff"
+	}
+	if badge != "" {
+		resp.Badge = &badge
+	}
+
+	return resp
+}
+
+// ToResponse converts ProductQuestion to ProductQuestionResponse
+func (q *ProductQuestion) ToResponse() ProductQuestionResponse {
+	resp := ProductQuestionResponse{
+		ID:        q.ID,
+		ProductID: q.ProductID,
+		Question:  q.Question,
+		AskedBy:   q.User.FirstName,
+		Answers:   make([]ProductAnswerResponse, 0, len(q.Answers)),
+		CreatedAt: q.CreatedAt,
+	}
+
+	// Official answers first, each group in the order they were given
+	for _, official := range []bool{true, false} {
+		for i := range q.Answers {
+			if q.Answers[i].IsOfficial() == official {
+				resp.Answers = append(resp.Answers, q.Answers[i].ToResponse())
+			}
+		}
+	}
+
+	return resp
+}
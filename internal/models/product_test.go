@@ -0,0 +1,90 @@
+package models
+
+import "testing"
+
+func TestCanFulfillAppliesBackorderLimit(t *testing.T) {
+	product := &Product{Stock: 2, TrackInventory: true, AllowBackorders: true, MaxBackorderQuantity: 3}
+
+	if !product.CanFulfill(5) {
+		t.Error("expected 5 units to fit within stock plus 3 backorders")
+	}
+	if product.CanFulfill(6) {
+		t.Error("expected 6 units to exceed the backorder limit")
+	}
+
+	product.AllowBackorders = false
+	if product.CanFulfill(3) {
+		t.Error("expected the limit to be ignored when backorders are off")
+	}
+}
+
+func TestCanFulfillUsesLargerOfToleranceAndBackorderLimit(t *testing.T) {
+	product := &Product{Stock: 0, AllowBackorders: true, MaxBackorderQuantity: 2, OversellTolerance: 4}
+
+	if !product.CanFulfill(4) {
+		t.Error("expected the oversell tolerance to allow 4 units")
+	}
+	if product.CanFulfill(5) {
+		t.Error("expected 5 units to exceed the oversell tolerance")
+	}
+}
+
+func TestIsBackorderableFollowsStock(t *testing.T) {
+	// StockQuantity is stale; checkout only moves Stock
+	product := &Product{Stock: -1, StockQuantity: 10, TrackInventory: true, AllowBackorders: true, MaxBackorderQuantity: 3}
+	product.UpdateComputedFields()
+
+	if product.IsInStock {
+		t.Error("expected product with negative stock not to be in stock")
+	}
+	if !product.IsBackorderable {
+		t.Error("expected product to be backorderable")
+	}
+	if got := product.BackorderAvailable(); got != 2 {
+		t.Errorf("BackorderAvailable() = %d, want 2", got)
+	}
+
+	product.Stock = -3
+	product.UpdateComputedFields()
+	if product.IsBackorderable {
+		t.Error("expected product at its backorder limit not to be backorderable")
+	}
+}
+
+func TestPendingProductIsVisibleToSellerAndAdminsOnly(t *testing.T) {
+	product := &Product{SellerID: 7, Status: ProductStatusActive, IsActive: true, Visible: true}
+	product.HoldForApproval()
+
+	if product.IsActive || product.Visible {
+		t.Error("expected a product held for approval to be unlisted")
+	}
+	if product.IsVisibleTo(0, "") {
+		t.Error("expected an anonymous viewer not to see a pending product")
+	}
+	if product.IsVisibleTo(8, RoleSeller) {
+		t.Error("expected another seller not to see a pending product")
+	}
+	if !product.IsVisibleTo(7, RoleSeller) {
+		t.Error("expected the seller to see their pending product")
+	}
+	if !product.IsVisibleTo(1, RoleAdmin) {
+		t.Error("expected an admin to see a pending product")
+	}
+}
+
+func TestChangesContentIgnoresUnchangedFields(t *testing.T) {
+	product := &Product{Name: "Lamp", Description: "A lamp", Category: "home"}
+	name, price := "Lamp", 12.5
+
+	if (&UpdateProductRequest{Name: &name, Price: &price}).ChangesContent(product) {
+		t.Error("expected an unchanged name and a new price not to change content")
+	}
+
+	name = "Desk lamp"
+	if !(&UpdateProductRequest{Name: &name}).ChangesContent(product) {
+		t.Error("expected a new name to change content")
+	}
+	if !(&UpdateProductRequest{Tags: []string{"light"}}).ChangesContent(product) {
+		t.Error("expected new tags to change content")
+	}
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// localePattern matches a language code with an optional region, e.g. "fr" or "fr-CA"
+var localePattern = regexp.MustCompile(`^([a-zA-Z]{2,3})(?:[-_]([a-zA-Z]{2}))?$`)
+
+// ProductTranslation holds a product's content in one locale
+type ProductTranslation struct {
+	BaseModel
+	ProductID       uint    `json:"product_id" gorm:"not null;uniqueIndex:idx_product_translations_product_locale"`
+	Locale          string  `json:"locale" gorm:"type:varchar(10);not null;uniqueIndex:idx_product_translations_product_locale"`
+	Name            string  `json:"name" gorm:"type:varchar(255);not null"`
+	Description     string  `json:"description" gorm:"type:text;not null"`
+	ShortDesc       *string `json:"short_description,omitempty" gorm:"column:short_description;type:varchar(500)"`
+	MetaTitle       *string `json:"meta_title,omitempty" gorm:"type:varchar(255)"`
+	MetaDescription *string `json:"meta_description,omitempty" gorm:"type:varchar(500)"`
+}
+
+// ProductTranslationRequest represents the request to set a product's content in a locale
+type ProductTranslationRequest struct {
+	Name            string  `json:"name" validate:"required,min=3,max=255"`
+	Description     string  `json:"description" validate:"required,min=10"`
+	ShortDesc       *string `json:"short_description,omitempty" validate:"omitempty,max=500"`
+	MetaTitle       *string `json:"meta_title,omitempty" validate:"omitempty,max=255"`
+	MetaDescription *string `json:"meta_description,omitempty" validate:"omitempty,max=500"`
+}
+
+// ProductBaseContent is a product's untranslated content, returned alongside a translation
+type ProductBaseContent struct {
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	ShortDesc       *string `json:"short_description,omitempty"`
+	MetaTitle       *string `json:"meta_title,omitempty"`
+	MetaDescription *string `json:"meta_description,omitempty"`
+}
+
+// NormalizeLocale validates a locale code and returns it as language[-REGION],
+// e.g. "pt_br" becomes "pt-BR"
+func NormalizeLocale(code string) (string, bool) {
+	match := localePattern.FindStringSubmatch(strings.TrimSpace(code))
+	if match == nil {
+		return "", false
+	}
+	locale := strings.ToLower(match[1])
+	if match[2] != "" {
+		locale += "-" + strings.ToUpper(match[2])
+	}
+	return locale, true
+}
+
+// LocaleFallbacks returns the locales to try for a normalized locale, most
+// specific first, e.g. "fr-CA" then "fr"
+func LocaleFallbacks(locale string) []string {
+	if i := strings.Index(locale, "-"); i > 0 {
+		return []string{locale, locale[:i]}
+	}
+	return []string{locale}
+}
+
+// ApplyTranslation replaces the product's content with the translation and
+// keeps the original in BaseContent. Optional fields the translation leaves
+// empty keep their base value.
+func (p *Product) ApplyTranslation(t *ProductTranslation) {
+	p.BaseContent = &ProductBaseContent{
+		Name:            p.Name,
+		Description:     p.Description,
+		ShortDesc:       p.ShortDesc,
+		MetaTitle:       p.MetaTitle,
+		MetaDescription: p.MetaDescription,
+	}
+	p.ContentLocale = t.Locale
+
+	p.Name = t.Name
+	p.Description = t.Description
+	if t.ShortDesc != nil {
+		p.ShortDesc = t.ShortDesc
+	}
+	if t.MetaTitle != nil {
+		p.MetaTitle = t.MetaTitle
+	}
+	if t.MetaDescription != nil {
+		p.MetaDescription = t.MetaDescription
+	}
+}
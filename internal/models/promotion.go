@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
+)
+
+// PromotionType is the rule a promotion applies
+type PromotionType string
+
+const (
+	PromotionTypeCategoryPercent PromotionType = "category_percent" // Percentage off items in a category
+	PromotionTypeBOGO            PromotionType = "bogo"             // Buy X get Y of the same product free or discounted
+	PromotionTypeSpendAndSave    PromotionType = "spend_and_save"   // Fixed amount off once the subtotal reaches a minimum
+)
+
+// Promotion is an admin-managed order-level promotion. At most one promotion
+// applies to an order: the one giving the largest discount.
+type Promotion struct {
+	BaseModel
+	Name        string        `json:"name" gorm:"type:varchar(255);not null"`
+	Description *string       `json:"description,omitempty" gorm:"type:text"`
+	Type        PromotionType `json:"type" gorm:"type:varchar(30);not null"`
+	IsActive    bool          `json:"is_active" gorm:"not null;index"`
+	StartsAt    time.Time     `json:"starts_at" gorm:"not null;index"`
+	EndsAt      *time.Time    `json:"ends_at,omitempty" gorm:"index"` // Nil keeps the promotion running until deactivated
+
+	// Rule parameters, which ones are used depends on Type
+	CategoryID      *uint   `json:"category_id,omitempty" gorm:"index"`                  // category_percent; optional filter for bogo
+	ProductID       *uint   `json:"product_id,omitempty" gorm:"index"`                   // Optional filter for bogo
+	DiscountPercent float64 `json:"discount_percent" gorm:"type:decimal(5,2);default:0"` // category_percent; bogo discount on the "get" units
+	BuyQuantity     int     `json:"buy_quantity" gorm:"default:0"`
+	GetQuantity     int     `json:"get_quantity" gorm:"default:0"`
+	MinSpend        float64 `json:"min_spend" gorm:"type:decimal(10,2);default:0"`
+	DiscountAmount  float64 `json:"discount_amount" gorm:"type:decimal(10,2);default:0"` // spend_and_save
+
+	CreatedBy uint `json:"created_by" gorm:"not null"`
+}
+
+// PromotionRequest represents the request to create or replace a promotion
+type PromotionRequest struct {
+	Name            string        `json:"name" validate:"required,min=2,max=255"`
+	Description     *string       `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Type            PromotionType `json:"type" validate:"required,oneof=category_percent bogo spend_and_save"`
+	IsActive        *bool         `json:"is_active,omitempty"` // Defaults to true
+	StartsAt        *time.Time    `json:"starts_at,omitempty"` // Defaults to now
+	EndsAt          *time.Time    `json:"ends_at,omitempty"`
+	CategoryID      *uint         `json:"category_id,omitempty"`
+	ProductID       *uint         `json:"product_id,omitempty"`
+	DiscountPercent float64       `json:"discount_percent" validate:"min=0,max=100"`
+	BuyQuantity     int           `json:"buy_quantity" validate:"min=0"`
+	GetQuantity     int           `json:"get_quantity" validate:"min=0"`
+	MinSpend        float64       `json:"min_spend" validate:"min=0"`
+	DiscountAmount  float64       `json:"discount_amount" validate:"min=0"`
+}
+
+// AppliedPromotion is the promotion applied to a cart or order
+type AppliedPromotion struct {
+	ID       uint    `json:"id"`
+	Name     string  `json:"name"`
+	Discount float64 `json:"discount"`
+}
+
+// PromotionLine is the part of a cart or order line a promotion is evaluated against
+type PromotionLine struct {
+	ProductID  uint
+	CategoryID *uint
+	Quantity   int
+	UnitPrice  float64
+	LineTotal  float64 // After line discounts
+}
+
+// Discount returns the discount the promotion gives on lines with the given
+// subtotal, or zero when the order doesn't qualify. The discount never exceeds
+// the subtotal.
+func (p *Promotion) Discount(lines []PromotionLine, subtotal float64) float64 {
+	if subtotal < p.MinSpend {
+		return 0
+	}
+
+	// Each line's discount is rounded on its own so the total is the sum of what's shown per line
+	var discount money.Amount
+	switch p.Type {
+	case PromotionTypeCategoryPercent:
+		for _, line := range lines {
+			if p.matchesCategory(line) {
+				discount += money.FromFloat(line.LineTotal).Percent(p.DiscountPercent)
+			}
+		}
+	case PromotionTypeBOGO:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return 0
+		}
+		for _, line := range lines {
+			if (p.ProductID != nil && line.ProductID != *p.ProductID) || !p.matchesCategory(line) {
+				continue
+			}
+			// Each full set of buy+get units earns GetQuantity discounted units
+			discountedUnits := line.Quantity / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+			discount += money.FromFloat(line.UnitPrice).Times(discountedUnits).Percent(p.DiscountPercent)
+		}
+	case PromotionTypeSpendAndSave:
+		discount = money.FromFloat(p.DiscountAmount)
+	}
+
+	return discount.Clamp(money.FromFloat(subtotal)).Float()
+}
+
+// matchesCategory reports whether the line is in the promotion's category, or
+// true when the promotion isn't limited to one
+func (p *Promotion) matchesCategory(line PromotionLine) bool {
+	if p.CategoryID == nil {
+		return true
+	}
+	return line.CategoryID != nil && *line.CategoryID == *p.CategoryID
+}
@@ -0,0 +1,17 @@
+package models
+
+// PurchaseLimitExemption lets one customer buy a product past its
+// per-customer purchase limit, e.g. a verified business buyer
+type PurchaseLimitExemption struct {
+	BaseModel
+	ProductID uint    `json:"product_id" gorm:"not null;uniqueIndex:idx_purchase_limit_exemptions_product_user"`
+	UserID    uint    `json:"user_id" gorm:"not null;uniqueIndex:idx_purchase_limit_exemptions_product_user"`
+	Note      *string `json:"note,omitempty" gorm:"type:text"`
+	CreatedBy uint    `json:"created_by" gorm:"not null"`
+}
+
+// PurchaseLimitExemptionRequest represents the request to exempt a customer from a product's purchase limit
+type PurchaseLimitExemptionRequest struct {
+	UserID uint    `json:"user_id" validate:"required"`
+	Note   *string `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// ProductRecall records a recall notice sent to everyone who bought a product.
+// Its emails are queued for the email dispatcher; EmailsFailed counts those
+// that gave up after the last attempt.
+type ProductRecall struct {
+	BaseModel
+	ProductID         uint   `json:"product_id" gorm:"not null;index"`
+	InitiatedBy       uint   `json:"initiated_by" gorm:"not null"`
+	Message           string `json:"message" gorm:"type:text;not null"`
+	CustomersNotified int    `json:"customers_notified" gorm:"default:0"`
+	EmailsFailed      int    `json:"emails_failed" gorm:"default:0"`
+
+	// Relationships
+	Product Product `json:"-" gorm:"foreignKey:ProductID"`
+}
+
+// ProductRecallRequest represents the request to recall a product
+type ProductRecallRequest struct {
+	Message string `json:"message" validate:"required,min=10,max=2000"`
+}
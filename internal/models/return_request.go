@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
+)
+
+// ReturnStatus represents where a return request is in review
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested"
+	ReturnStatusApproved  ReturnStatus = "approved" // Claimed by an admin; the refund is being issued
+	ReturnStatusRefunded  ReturnStatus = "refunded"
+	ReturnStatusRejected  ReturnStatus = "rejected"
+)
+
+// ReturnRequest is a customer's request to return some units of one order item.
+// Eligibility follows the product's return window, measured from delivery.
+type ReturnRequest struct {
+	BaseModel
+	OrderID      uint         `json:"order_id" gorm:"not null;index"`
+	OrderItemID  uint         `json:"order_item_id" gorm:"not null;index"`
+	CustomerID   uint         `json:"customer_id" gorm:"not null;index"`
+	Quantity     int          `json:"quantity" gorm:"not null"`
+	Reason       string       `json:"reason" gorm:"type:text;not null"`
+	Defective    bool         `json:"defective" gorm:"not null;default:false"` // Returned for a defect, so no restocking fee
+	Status       ReturnStatus `json:"status" gorm:"type:varchar(20);not null;default:'requested';index"`
+	RefundAmount float64      `json:"refund_amount" gorm:"type:decimal(10,2);default:0"` // Set once refunded, after the restocking fee
+	// Restocking fee the seller keeps: the percent is fixed when the return is
+	// requested, the amount once it's refunded
+	RestockingFeePercent float64    `json:"restocking_fee_percent" gorm:"type:decimal(5,2);default:0"`
+	RestockingFee        float64    `json:"restocking_fee" gorm:"type:decimal(10,2);default:0"`
+	ResolutionNote       *string    `json:"resolution_note,omitempty" gorm:"type:text"`
+	ResolvedBy           *uint      `json:"resolved_by,omitempty"`
+	ResolvedAt           *time.Time `json:"resolved_at,omitempty"`
+
+	// Relationships
+	OrderItem OrderItem `json:"order_item,omitempty" gorm:"foreignKey:OrderItemID"`
+}
+
+// ReturnCreateRequest represents a customer's request to return an order item
+type ReturnCreateRequest struct {
+	OrderItemID uint   `json:"order_item_id" validate:"required"`
+	Quantity    int    `json:"quantity" validate:"required,min=1"`
+	Reason      string `json:"reason" validate:"required,min=5,max=1000"`
+	Defective   bool   `json:"defective"` // The item is faulty; no restocking fee is charged
+}
+
+// ReturnResolveRequest represents an admin's note when approving or rejecting a return
+type ReturnResolveRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// IsOpen checks if the return is still waiting for a decision
+func (r *ReturnRequest) IsOpen() bool {
+	return r.Status == ReturnStatusRequested
+}
+
+// SplitRefund splits what the returned units are worth into the refund and the
+// restocking fee kept from it
+func (r *ReturnRequest) SplitRefund(amount float64) (refund, fee float64) {
+	gross := money.FromFloat(amount)
+	kept := gross.Percent(r.RestockingFeePercent).Clamp(gross)
+	return (gross - kept).Float(), kept.Float()
+}
@@ -7,11 +7,14 @@ import (
 // Review represents a product review
 type Review struct {
 	BaseModel
-	ProductID uint    `json:"product_id" gorm:"not null"`
+	// A user reviews a product once per order it was bought in; a review
+	// without an order takes one of those slots (see migration 057 for the
+	// unique index)
+	ProductID uint    `json:"product_id" gorm:"not null;index"`
 	Product   Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
-	UserID    uint    `json:"user_id" gorm:"not null"`
+	UserID    uint    `json:"user_id" gorm:"not null;index"`
 	User      User    `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	OrderID   *uint   `json:"order_id,omitempty"` // Optional: link to order for verified purchases
+	OrderID   *uint   `json:"order_id,omitempty" gorm:"index"` // Optional: link to order for verified purchases
 	Order     *Order  `json:"order,omitempty" gorm:"foreignKey:OrderID"`
 	
 	Rating  int    `json:"rating" gorm:"not null" validate:"required,min=1,max=5"`
@@ -22,6 +25,9 @@ type Review struct {
 	IsVerified bool `json:"is_verified" gorm:"default:false"` // Verified purchase
 	IsApproved bool `json:"is_approved" gorm:"default:true"`  // Moderation
 	
+	// Set when the author last changed the review; see ReviewEdit for what changed
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+	
 	// Helpful votes
 	HelpfulCount    int `json:"helpful_count" gorm:"default:0"`
 	NotHelpfulCount int `json:"not_helpful_count" gorm:"default:0"`
@@ -47,6 +53,18 @@ type ReviewHelpful struct {
 	User   User `json:"-" gorm:"foreignKey:UserID"`
 }
 
+// ReviewEdit records a review's content as it was before an edit, so
+// moderators can see what a review said before it was changed. CreatedAt is
+// when the edit was made.
+type ReviewEdit struct {
+	BaseModel
+	ReviewID        uint   `json:"review_id" gorm:"not null;index"`
+	PreviousRating  int    `json:"previous_rating" gorm:"not null"`
+	PreviousTitle   string `json:"previous_title" gorm:"type:varchar(255)"`
+	PreviousComment string `json:"previous_comment" gorm:"type:text"`
+	EditedBy        uint   `json:"edited_by" gorm:"not null"`
+}
+
 // ReviewCreateRequest represents the request to create a review
 type ReviewCreateRequest struct {
 	ProductID uint   `json:"product_id" validate:"required"`
@@ -78,6 +96,50 @@ type ReviewListRequest struct {
 	SortOrder  string  `query:"sort_order" validate:"omitempty,oneof=asc desc"`
 }
 
+// ReviewSort is the order a product's reviews are listed in
+type ReviewSort string
+
+const (
+	ReviewSortNewest      ReviewSort = "newest"
+	ReviewSortOldest      ReviewSort = "oldest"
+	ReviewSortRatingHigh  ReviewSort = "rating_high"
+	ReviewSortRatingLow   ReviewSort = "rating_low"
+	ReviewSortMostHelpful ReviewSort = "most_helpful" // Reviews with at least ReviewHelpfulMinVotes votes first
+)
+
+// ReviewHelpfulMinVotes is how many helpful or not helpful votes a review needs
+// before the most helpful sort ranks it above reviews few have voted on yet
+const ReviewHelpfulMinVotes = 3
+
+// IsValid checks if the sort is one review listings support
+func (s ReviewSort) IsValid() bool {
+	switch s {
+	case ReviewSortNewest, ReviewSortOldest, ReviewSortRatingHigh, ReviewSortRatingLow, ReviewSortMostHelpful:
+		return true
+	}
+	return false
+}
+
+// SellerReviewFilter narrows the reviews of a seller's products
+type SellerReviewFilter struct {
+	Rating      *int
+	HasResponse *bool      // Only reviews the seller has (true) or hasn't (false) responded to
+	DateFrom    *time.Time // Inclusive
+	DateTo      *time.Time // Exclusive
+}
+
+// SellerReview is a review of one of the seller's products, flagged while it
+// still waits for their response
+type SellerReview struct {
+	Review           *Review `json:"review"`
+	AwaitingResponse bool    `json:"awaiting_response"`
+}
+
+// AwaitingSellerResponse checks if the seller hasn't responded to the review yet
+func (r *Review) AwaitingSellerResponse() bool {
+	return r.SellerResponse == nil || *r.SellerResponse == ""
+}
+
 // ReviewResponse represents a review response
 type ReviewResponse struct {
 	ID               uint                 `json:"id"`
@@ -145,6 +207,7 @@ type SellerResponseRequest struct {
 // Request models
 type CreateReviewRequest struct {
 	ProductID uint   `json:"product_id" validate:"required"`
+	OrderID   *uint  `json:"order_id,omitempty"` // Marks the review a verified purchase from this delivered order
 	Rating    int    `json:"rating" validate:"required,min=1,max=5"`
 	Comment   string `json:"comment" validate:"required,min=10,max=2000"`
 }
@@ -154,6 +217,58 @@ type UpdateReviewRequest struct {
 	Comment *string `json:"comment,omitempty" validate:"omitempty,min=10,max=2000"`
 }
 
+// ReviewSummary aggregates review comments into keywords and sentiment.
+// Sentiment is bucketed by rating: 4-5 positive, 3 neutral, 1-2 negative.
+type ReviewSummary struct {
+	ProductID     uint           `json:"product_id"`
+	TotalReviews  int            `json:"total_reviews"`
+	PositiveCount int            `json:"positive_count"`
+	NeutralCount  int            `json:"neutral_count"`
+	NegativeCount int            `json:"negative_count"`
+	PositiveRatio float64        `json:"positive_ratio"`
+	NegativeRatio float64        `json:"negative_ratio"`
+	TopKeywords   []KeywordCount `json:"top_keywords"`
+	Pros          []KeywordCount `json:"pros"` // Keywords most mentioned in positive reviews
+	Cons          []KeywordCount `json:"cons"` // Keywords most mentioned in negative reviews
+	GeneratedAt   time.Time      `json:"generated_at"`
+}
+
+// KeywordCount represents how many reviews mention a keyword
+type KeywordCount struct {
+	Keyword string `json:"keyword"`
+	Count   int    `json:"count"`
+}
+
+// ModerationAction represents a moderation action applied to reviews
+type ModerationAction string
+
+const (
+	ModerationActionApprove ModerationAction = "approve"
+	ModerationActionReject  ModerationAction = "reject"
+	ModerationActionDelete  ModerationAction = "delete"
+)
+
+// BulkModerateRequest represents the request to moderate many reviews at once
+type BulkModerateRequest struct {
+	ReviewIDs []uint           `json:"review_ids" validate:"required,min=1,max=100,dive,required"`
+	Action    ModerationAction `json:"action" validate:"required,oneof=approve reject delete"`
+}
+
+// ModerationItemResult represents the outcome for a single review in a bulk action
+type ModerationItemResult struct {
+	ReviewID uint   `json:"review_id"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
+
+// BulkModerateResponse represents the outcome of a bulk moderation action
+type BulkModerateResponse struct {
+	Action    ModerationAction       `json:"action"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []ModerationItemResult `json:"results"`
+}
+
 // Response models
 type ReviewStats struct {
 	AverageRating      float64        `json:"average_rating"`
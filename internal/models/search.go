@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// SearchSuggestionsResponse represents type-ahead suggestions for a search prefix
+type SearchSuggestionsResponse struct {
+	Query    string   `json:"query"`
+	Products []string `json:"products"`
+	Popular  []string `json:"popular"`
+}
+
+// SearchLog records a product search and how many results it returned
+type SearchLog struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Query       string    `json:"query" gorm:"type:varchar(100);not null;index"`
+	ResultCount int       `json:"result_count" gorm:"not null;default:0"`
+	UserID      *uint     `json:"user_id,omitempty" gorm:"index"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
+
+// SearchQueryStat represents how often a query was searched
+type SearchQueryStat struct {
+	Query string `json:"query"`
+	Count int64  `json:"count"`
+}
+
+// SearchAnalytics represents the search report for merchandisers
+type SearchAnalytics struct {
+	StartDate            time.Time         `json:"start_date"`
+	EndDate              time.Time         `json:"end_date"`
+	TotalSearches        int64             `json:"total_searches"`
+	ZeroResultSearches   int64             `json:"zero_result_searches"`
+	TopQueries           []SearchQueryStat `json:"top_queries"`
+	TopZeroResultQueries []SearchQueryStat `json:"top_zero_result_queries"`
+}
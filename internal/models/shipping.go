@@ -0,0 +1,146 @@
+package models
+
+import (
+	"strings"
+)
+
+// ShippingQuote represents the shipping cost for a subtotal and how far it is from free shipping.
+// The threshold is checked against the subtotal after line-item discounts and before
+// order-level discounts, so an order-wide promotion can't knock an order out of free shipping.
+type ShippingQuote struct {
+	Zone                  string  `json:"zone,omitempty"` // Shipping zone the destination resolved to, empty for the flat rate
+	Cost                  float64 `json:"shipping_cost"`
+	FreeShippingThreshold float64 `json:"free_shipping_threshold,omitempty"`
+	AmountToFreeShipping  float64 `json:"amount_to_free_shipping"`
+	QualifiesForFree      bool    `json:"qualifies_for_free_shipping"`
+}
+
+// CartTotalResponse represents the cart total with shipping
+type CartTotalResponse struct {
+	Subtotal float64       `json:"subtotal"`
+	Total    float64       `json:"total"`
+	Shipping ShippingQuote `json:"shipping"`
+}
+
+// CartSummary represents the full cart breakdown shown before checkout.
+// Amounts are computed the same way CreateOrder computes them so the cart
+// page and the placed order agree.
+type CartSummary struct {
+	Destination       string            `json:"destination,omitempty"`
+	ItemCount         int               `json:"item_count"`
+	Subtotal          float64           `json:"subtotal"`
+	EstimatedTax      float64           `json:"estimated_tax"`
+	EstimatedShipping float64           `json:"estimated_shipping"`
+	Discount          float64           `json:"discount"`
+	GrandTotal        float64           `json:"grand_total"`
+	Shipping          ShippingQuote     `json:"shipping"`
+	Promotion         *AppliedPromotion `json:"promotion,omitempty"`
+	MinimumOrder      []OrderMinimum    `json:"minimum_order,omitempty"` // Minimums the cart doesn't reach yet
+	Reservations      []CartReservation `json:"reservations,omitempty"`  // Stock the user's unpaid orders hold for cart items
+	LineCount         int               `json:"line_count"`              // Different products in the cart, counted against Limits.MaxItems
+	Limits            CartLimits        `json:"limits"`                  // Subtotal is counted against Limits.MaxValue
+	Lines             []PromotionLine   `json:"-"`                       // For checking targeted coupons against the cart
+}
+
+// ShippingZone is an admin-managed group of destinations sharing a rate table
+// keyed by weight. A destination matches the zone listing its region before
+// one listing only its country; unmatched destinations use the default zone.
+type ShippingZone struct {
+	BaseModel
+	Name      string         `json:"name" gorm:"type:varchar(100);not null"`
+	Countries string         `json:"countries,omitempty" gorm:"type:varchar(1000)"` // Comma-separated country codes, e.g. "US,CA"
+	Regions   string         `json:"regions,omitempty" gorm:"type:varchar(1000)"`   // Comma-separated country-region codes, e.g. "US-AK,US-HI"
+	IsDefault bool           `json:"is_default" gorm:"not null;default:false;index"`
+	Rates     []ShippingRate `json:"rates" gorm:"foreignKey:ZoneID;constraint:OnDelete:CASCADE"`
+}
+
+// ShippingRate is one weight bracket of a zone's rate table. The bracket
+// covers weights from MinWeight up to, but not including, MaxWeight.
+type ShippingRate struct {
+	ID        uint     `json:"id" gorm:"primaryKey"`
+	ZoneID    uint     `json:"zone_id" gorm:"not null;index"`
+	MinWeight float64  `json:"min_weight" gorm:"type:decimal(8,3);not null;default:0"`
+	MaxWeight *float64 `json:"max_weight,omitempty" gorm:"type:decimal(8,3)"` // Nil has no upper bound
+	Cost      float64  `json:"cost" gorm:"type:decimal(10,2);not null"`
+}
+
+// ShippingZoneRequest represents the request to create or replace a shipping zone
+type ShippingZoneRequest struct {
+	Name      string                `json:"name" validate:"required,min=2,max=100"`
+	Countries []string              `json:"countries,omitempty" validate:"omitempty,dive,min=2,max=3"`
+	Regions   []string              `json:"regions,omitempty" validate:"omitempty,dive,min=4,max=10"`
+	IsDefault bool                  `json:"is_default"`
+	Rates     []ShippingRateRequest `json:"rates" validate:"required,min=1,dive"`
+}
+
+// ShippingRateRequest represents one weight bracket of a shipping zone request
+type ShippingRateRequest struct {
+	MinWeight float64  `json:"min_weight" validate:"min=0"`
+	MaxWeight *float64 `json:"max_weight,omitempty" validate:"omitempty,gt=0"`
+	Cost      float64  `json:"cost" validate:"min=0"`
+}
+
+// ShippingDestination is where an order ships, as matched against zones
+type ShippingDestination struct {
+	Country string
+	Region  string
+}
+
+// ParseShippingDestination reads a "US" or "US-CA" style destination
+func ParseShippingDestination(destination string) ShippingDestination {
+	country, region, _ := strings.Cut(strings.TrimSpace(destination), "-")
+	return ShippingDestination{Country: country, Region: region}
+}
+
+// NormalizeShippingCodes upper-cases and trims zone codes, dropping blanks and
+// duplicates, and joins them for storage
+func NormalizeShippingCodes(codes []string) string {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+	return strings.Join(normalized, ",")
+}
+
+// Match reports how specifically the zone covers the destination: 2 for its
+// region, 1 for its country and 0 when it doesn't cover it at all
+func (z *ShippingZone) Match(dest ShippingDestination) int {
+	country := strings.ToUpper(strings.TrimSpace(dest.Country))
+	region := strings.ToUpper(strings.TrimSpace(dest.Region))
+	if country == "" {
+		return 0
+	}
+	if region != "" && containsCode(z.Regions, country+"-"+region) {
+		return 2
+	}
+	if containsCode(z.Countries, country) {
+		return 1
+	}
+	return 0
+}
+
+// RateFor returns the bracket covering the weight, if any
+func (z *ShippingZone) RateFor(weight float64) *ShippingRate {
+	for i := range z.Rates {
+		rate := &z.Rates[i]
+		if weight >= rate.MinWeight && (rate.MaxWeight == nil || weight < *rate.MaxWeight) {
+			return rate
+		}
+	}
+	return nil
+}
+
+func containsCode(codes, code string) bool {
+	for _, c := range strings.Split(codes, ",") {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
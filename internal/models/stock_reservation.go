@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// ReservationStatus represents the state of a stock reservation
+type ReservationStatus string
+
+const (
+	ReservationStatusReserved  ReservationStatus = "reserved"
+	ReservationStatusCommitted ReservationStatus = "committed"
+	ReservationStatusReleased  ReservationStatus = "released"
+)
+
+// StockReservation holds stock for an order's line until payment succeeds.
+// Stock is decremented when the reservation is made and added back on release.
+type StockReservation struct {
+	BaseModel
+	OrderID   uint              `json:"order_id" gorm:"not null;index"`
+	ProductID uint              `json:"product_id" gorm:"not null"`
+	Quantity  int               `json:"quantity" gorm:"not null"`
+	Status    ReservationStatus `json:"status" gorm:"type:varchar(20);not null;default:'reserved';index"`
+	ExpiresAt time.Time         `json:"expires_at" gorm:"not null;index"`
+}
+
+// ReservationHold is how long an unpaid order holds its stock
+type ReservationHold struct {
+	OrderID   uint      `json:"order_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CartReservation is stock an unpaid order of the user's holds for an item in
+// their cart, until ExpiresAt
+type CartReservation struct {
+	OrderID   uint      `json:"order_id"`
+	ProductID uint      `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+// SupportTicketStatus represents where a support ticket stands
+type SupportTicketStatus string
+
+const (
+	SupportTicketStatusOpen     SupportTicketStatus = "open"     // Waiting on staff
+	SupportTicketStatusAnswered SupportTicketStatus = "answered" // Waiting on the customer
+	SupportTicketStatusClosed   SupportTicketStatus = "closed"
+)
+
+// SupportTicket represents a customer's request for help, optionally about one of their orders
+type SupportTicket struct {
+	BaseModel
+	UserID   uint                   `json:"user_id" gorm:"not null;index"`
+	User     User                   `json:"-" gorm:"foreignKey:UserID"`
+	OrderID  *uint                  `json:"order_id,omitempty" gorm:"index"`
+	Order    *Order                 `json:"order,omitempty" gorm:"foreignKey:OrderID"`
+	Subject  string                 `json:"subject" gorm:"type:varchar(255);not null"`
+	Body     string                 `json:"body" gorm:"type:text;not null"`
+	Status   SupportTicketStatus    `json:"status" gorm:"type:varchar(20);not null;default:'open';index"`
+	Messages []SupportTicketMessage `json:"messages,omitempty" gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE"`
+}
+
+// SupportTicketMessage represents a reply in a support ticket's thread
+type SupportTicketMessage struct {
+	BaseModel
+	TicketID uint   `json:"ticket_id" gorm:"not null;index"`
+	UserID   uint   `json:"user_id" gorm:"not null"`
+	Body     string `json:"body" gorm:"type:text;not null"`
+	IsStaff  bool   `json:"is_staff" gorm:"default:false"` // Snapshot taken when replying
+}
+
+// CreateSupportTicketRequest represents the request to open a support ticket
+type CreateSupportTicketRequest struct {
+	OrderID *uint  `json:"order_id,omitempty"`
+	Subject string `json:"subject" validate:"required,min=3,max=255"`
+	Body    string `json:"body" validate:"required,min=10,max=5000"`
+}
+
+// SupportTicketReplyRequest represents a reply to a support ticket. Only staff
+// may set the status; a staff reply without one marks the ticket answered.
+type SupportTicketReplyRequest struct {
+	Body   string               `json:"body" validate:"required,min=2,max=5000"`
+	Status *SupportTicketStatus `json:"status,omitempty" validate:"omitempty,oneof=open answered closed"`
+}
+
+// IsClosed checks if the ticket no longer takes customer replies
+func (t *SupportTicket) IsClosed() bool {
+	return t.Status == SupportTicketStatusClosed
+}
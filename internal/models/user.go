@@ -13,6 +13,10 @@ const (
 	RoleCustomer UserRole = "customer"
 	RoleSeller   UserRole = "seller"
 	RoleAdmin    UserRole = "admin"
+
+	// RoleSellerStaff is a seller's team member. Staff accounts belong to the
+	// seller in EmployerID and fulfill the orders assigned to them.
+	RoleSellerStaff UserRole = "seller_staff"
 )
 
 // User represents a user in the system
@@ -20,14 +24,21 @@ type User struct {
 	BaseModel
 	FirstName    string    `json:"first_name" gorm:"type:varchar(100);not null" validate:"required,min=2,max=100"`
 	LastName     string    `json:"last_name" gorm:"type:varchar(100);not null" validate:"required,min=2,max=100"`
-	Email        string    `json:"email" gorm:"type:varchar(255);unique;not null" validate:"required,email"`
+	Email        string    `json:"email" gorm:"type:varchar(255);not null;uniqueIndex:idx_users_email_unique,where:deleted_at IS NULL" validate:"required,email"`
 	Password     string    `json:"-" gorm:"type:varchar(255);not null" validate:"required,min=12,containsany=!@#$%^&*,containsany=0123456789,containsany=ABCDEFGHIJKLMNOPQRSTUVWXYZ,containsany=abcdefghijklmnopqrstuvwxyz"`
 	Phone        *string   `json:"phone,omitempty" gorm:"type:varchar(20)" validate:"omitempty,e164"`
-	Role         UserRole  `json:"role" gorm:"type:varchar(20);not null;default:'customer'" validate:"required,oneof=customer seller admin"`
+	Role         UserRole  `json:"role" gorm:"type:varchar(20);not null;default:'customer'" validate:"required,oneof=customer seller admin seller_staff"`
 	IsActive     bool      `json:"is_active" gorm:"default:true"`
 	IsVerified   bool      `json:"is_verified" gorm:"default:false"`
 	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
 	
+	// Guests checked out without an account and can't sign in. Registering
+	// with the same email claims the record, along with its orders.
+	IsGuest bool `json:"is_guest" gorm:"default:false;index"`
+	
+	// Notification preferences
+	ReviewRemindersOptOut bool `json:"review_reminders_opt_out" gorm:"default:false"` // No emails asking to review delivered purchases
+	
 	// Profile information
 	DateOfBirth *time.Time `json:"date_of_birth,omitempty" gorm:"type:date"`
 	Gender      *string    `json:"gender,omitempty" gorm:"type:varchar(10)" validate:"omitempty,oneof=male female other"`
@@ -44,6 +55,13 @@ type User struct {
 	StoreName        *string `json:"store_name,omitempty" gorm:"type:varchar(255)"`
 	StoreDescription *string `json:"store_description,omitempty" gorm:"type:text"`
 	TaxID           *string `json:"tax_id,omitempty" gorm:"type:varchar(50)"`
+	MinimumOrderAmount *float64 `json:"minimum_order_amount,omitempty" gorm:"type:decimal(10,2)"` // Least a customer must spend with the seller per order; nil or 0 for none
+	RestockingFeePercent *float64 `json:"restocking_fee_percent,omitempty" gorm:"type:decimal(5,2)"` // Default for the seller's products; nil or 0 for none
+	
+	WelcomeCouponID *uint `json:"-"` // Set once the customer has been issued their welcome coupon
+	
+	// Staff specific fields
+	EmployerID *uint `json:"employer_id,omitempty" gorm:"index"` // The seller a staff member works for
 	
 	// Relationships
 	Products []Product `json:"products,omitempty" gorm:"foreignKey:SellerID"`
@@ -69,6 +87,9 @@ type UserUpdateRequest struct {
 	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
 	Gender      *string    `json:"gender,omitempty" validate:"omitempty,oneof=male female other"`
 	
+	// Notification preferences
+	ReviewRemindersOptOut *bool `json:"review_reminders_opt_out,omitempty"`
+	
 	// Address information
 	Street     *string `json:"street,omitempty"`
 	City       *string `json:"city,omitempty"`
@@ -80,6 +101,8 @@ type UserUpdateRequest struct {
 	StoreName        *string `json:"store_name,omitempty"`
 	StoreDescription *string `json:"store_description,omitempty"`
 	TaxID           *string `json:"tax_id,omitempty"`
+	MinimumOrderAmount *float64 `json:"minimum_order_amount,omitempty" validate:"omitempty,min=0"`
+	RestockingFeePercent *float64 `json:"restocking_fee_percent,omitempty" validate:"omitempty,min=0,max=100"`
 }
 
 // UserResponse represents the user response (without sensitive data)
@@ -96,6 +119,9 @@ type UserResponse struct {
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	
+	// Notification preferences
+	ReviewRemindersOptOut bool `json:"review_reminders_opt_out"`
+	
 	// Profile information
 	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
 	Gender      *string    `json:"gender,omitempty"`
@@ -109,8 +135,22 @@ type UserResponse struct {
 	PostalCode *string `json:"postal_code,omitempty"`
 	
 	// Seller information
-	StoreName        *string `json:"store_name,omitempty"`
-	StoreDescription *string `json:"store_description,omitempty"`
+	StoreName          *string  `json:"store_name,omitempty"`
+	StoreDescription   *string  `json:"store_description,omitempty"`
+	MinimumOrderAmount *float64 `json:"minimum_order_amount,omitempty"`
+	RestockingFeePercent *float64 `json:"restocking_fee_percent,omitempty"`
+	
+	// Staff information
+	EmployerID *uint `json:"employer_id,omitempty"`
+}
+
+// StaffCreateRequest represents a seller's request to create a staff account
+type StaffCreateRequest struct {
+	FirstName string  `json:"first_name" validate:"required,min=2,max=100"`
+	LastName  string  `json:"last_name" validate:"required,min=2,max=100"`
+	Email     string  `json:"email" validate:"required,email"`
+	Password  string  `json:"password" validate:"required,min=8"`
+	Phone     *string `json:"phone,omitempty" validate:"omitempty,e164"`
 }
 
 // LoginRequest represents the login request
@@ -127,6 +167,9 @@ type RegisterRequest struct {
 	Password  string   `json:"password" validate:"required,min=8"`
 	Phone     *string  `json:"phone,omitempty" validate:"omitempty,e164"`
 	Role      UserRole `json:"role" validate:"required,oneof=customer seller"`
+
+	// Sent to an email used for guest checkout; required to claim its orders
+	VerificationToken string `json:"verification_token,omitempty"`
 }
 
 // PasswordChangeRequest represents the password change request
@@ -171,6 +214,7 @@ func (u *User) ToResponse() UserResponse {
 		CreatedAt:        u.CreatedAt,
 		UpdatedAt:        u.UpdatedAt,
 		DateOfBirth:      u.DateOfBirth,
+		ReviewRemindersOptOut: u.ReviewRemindersOptOut,
 		Gender:           u.Gender,
 		Avatar:           u.Avatar,
 		Street:           u.Street,
@@ -180,6 +224,11 @@ func (u *User) ToResponse() UserResponse {
 		PostalCode:       u.PostalCode,
 		StoreName:        u.StoreName,
 		StoreDescription: u.StoreDescription,
+
+		MinimumOrderAmount:   u.MinimumOrderAmount,
+		RestockingFeePercent: u.RestockingFeePercent,
+
+		EmployerID: u.EmployerID,
 	}
 }
 
@@ -198,6 +247,11 @@ func (u *User) IsSeller() bool {
 	return u.Role == RoleSeller
 }
 
+// IsStaffOf checks if the user is an active staff member of the seller
+func (u *User) IsStaffOf(sellerID uint) bool {
+	return u.Role == RoleSellerStaff && u.IsActive && u.EmployerID != nil && *u.EmployerID == sellerID
+}
+
 // IsAdmin checks if the user is an admin
 func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
@@ -216,6 +270,42 @@ type UserStatsResponse struct {
 	NewUsersMonth  int64 `json:"new_users_month"`
 }
 
+// SellerOnboardingStep represents one item on the seller getting-started checklist
+type SellerOnboardingStep struct {
+	Key       string `json:"key"`
+	Title     string `json:"title"`
+	Required  bool   `json:"required"`
+	Completed bool   `json:"completed"`
+}
+
+// SellerOnboardingStatus represents a seller's progress towards being able to sell
+type SellerOnboardingStatus struct {
+	Steps          []SellerOnboardingStep `json:"steps"`
+	CompletedSteps int                    `json:"completed_steps"`
+	TotalSteps     int                    `json:"total_steps"`
+	IsComplete     bool                   `json:"is_complete"`
+}
+
+// SellerStatusChange reports a seller's deactivation or reactivation
+type SellerStatusChange struct {
+	SellerID         uint `json:"seller_id"`
+	IsActive         bool `json:"is_active"`
+	ProductsAffected int  `json:"products_affected"`
+}
+
+// CustomerStats summarizes a customer's own order history for their account dashboard
+type CustomerStats struct {
+	TotalSpent       float64   `json:"total_spent"`                 // Paid orders, excluding cancelled and refunded ones
+	OrderCount       int64     `json:"order_count"`                 // Orders counted in TotalSpent
+	FavoriteCategory *string   `json:"favorite_category,omitempty"` // Category the customer bought the most units from
+	MemberSince      time.Time `json:"member_since"`
+}
+
+// EmailAvailabilityRequest represents the registration email availability check
+type EmailAvailabilityRequest struct {
+	Email string `query:"email" validate:"required,email,max=255"`
+}
+
 // ForgotPasswordRequest represents the forgot password request
 type ForgotPasswordRequest struct {
 	Email string `json:"email" validate:"required,email"`
@@ -249,6 +339,16 @@ type PasswordResetToken struct {
 	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// IsExpired checks if the reset token is past its expiry
+func (t *PasswordResetToken) IsExpired() bool {
+	return !time.Now().Before(t.ExpiresAt)
+}
+
+// IsUsed checks if the reset token has already been redeemed
+func (t *PasswordResetToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
 // EmailVerificationToken represents an email verification token
 type EmailVerificationToken struct {
 	BaseModel
@@ -260,3 +360,13 @@ type EmailVerificationToken struct {
 	// Relationships
 	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
+
+// IsExpired checks if the verification token is past its expiry
+func (t *EmailVerificationToken) IsExpired() bool {
+	return !time.Now().Before(t.ExpiresAt)
+}
+
+// IsUsed checks if the verification token has already been redeemed
+func (t *EmailVerificationToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
@@ -20,6 +20,11 @@ type WishlistAddRequest struct {
 	ProductID uint `json:"product_id" validate:"required"`
 }
 
+// WishlistCheckBatchRequest represents the request to check many products at once
+type WishlistCheckBatchRequest struct {
+	ProductIDs []uint `json:"product_ids" validate:"required,min=1,max=100,dive,required"`
+}
+
 // WishlistResponse represents the wishlist response
 type WishlistResponse struct {
 	ID        uint      `json:"id"`
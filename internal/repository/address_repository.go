@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+type AddressRepository interface {
+	Create(ctx context.Context, address *models.Address) error
+	GetByID(ctx context.Context, id uint) (*models.Address, error)
+	GetByUser(ctx context.Context, userID uint) ([]*models.Address, error)
+	CountByUser(ctx context.Context, userID uint) (int64, error)
+	Update(ctx context.Context, address *models.Address) error
+	Delete(ctx context.Context, id uint) error
+}
+
+func NewAddressRepository(db *gorm.DB) AddressRepository {
+	return &addressRepository{db: db}
+}
+
+// Create saves the address, clearing the user's other defaults it replaces
+func (r *addressRepository) Create(ctx context.Context, address *models.Address) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearAddressDefaults(tx, address); err != nil {
+			return err
+		}
+		return tx.Create(address).Error
+	})
+}
+
+func (r *addressRepository) GetByID(ctx context.Context, id uint) (*models.Address, error) {
+	var address models.Address
+	err := r.db.WithContext(ctx).First(&address, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// GetByUser returns the user's addresses, defaults first
+func (r *addressRepository) GetByUser(ctx context.Context, userID uint) ([]*models.Address, error) {
+	var addresses []*models.Address
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default_shipping DESC, is_default_billing DESC, created_at ASC").
+		Find(&addresses).Error
+	return addresses, err
+}
+
+func (r *addressRepository) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Address{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// Update saves the address, clearing the user's other defaults it replaces
+func (r *addressRepository) Update(ctx context.Context, address *models.Address) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearAddressDefaults(tx, address); err != nil {
+			return err
+		}
+		return tx.Save(address).Error
+	})
+}
+
+func (r *addressRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Address{}, id).Error
+}
+
+// clearAddressDefaults unsets the default flags on the user's other addresses
+// for each default the address claims
+func clearAddressDefaults(tx *gorm.DB, address *models.Address) error {
+	if address.IsDefaultShipping {
+		if err := tx.Model(&models.Address{}).
+			Where("user_id = ? AND id <> ? AND is_default_shipping = ?", address.UserID, address.ID, true).
+			Update("is_default_shipping", false).Error; err != nil {
+			return err
+		}
+	}
+	if address.IsDefaultBilling {
+		if err := tx.Model(&models.Address{}).
+			Where("user_id = ? AND id <> ? AND is_default_billing = ?", address.UserID, address.ID, true).
+			Update("is_default_billing", false).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ type CartRepository interface {
 	GetOrCreateCart(ctx context.Context, userID uint) (*models.Cart, error)
 	GetCart(ctx context.Context, userID uint) (*models.Cart, error)
 	AddItem(ctx context.Context, cartItem *models.CartItem) error
+	UpsertItemQuantity(ctx context.Context, cartID, productID uint, quantity, maxQuantity int) (*models.CartItem, error)
 	UpdateItem(ctx context.Context, cartItem *models.CartItem) error
 	RemoveItem(ctx context.Context, cartID, itemID uint) error
 	GetItem(ctx context.Context, cartID, itemID uint) (*models.CartItem, error)
@@ -61,6 +62,32 @@ func (r *cartRepository) AddItem(ctx context.Context, cartItem *models.CartItem)
 	return r.db.WithContext(ctx).Create(cartItem).Error
 }
 
+// UpsertItemQuantity atomically adds quantity to the cart line for a product,
+// creating it if needed. The resulting quantity is clamped to maxQuantity so
+// concurrent adds can't push a line past available stock. Soft-deleted lines
+// are revived with the new quantity instead of conflicting.
+func (r *cartRepository) UpsertItemQuantity(ctx context.Context, cartID, productID uint, quantity, maxQuantity int) (*models.CartItem, error) {
+	var item models.CartItem
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO cart_items (cart_id, product_id, quantity, created_at, updated_at)
+		VALUES (?, ?, LEAST(?, ?), NOW(), NOW())
+		ON CONFLICT (cart_id, product_id) DO UPDATE SET
+			quantity = LEAST(
+				CASE WHEN cart_items.deleted_at IS NULL
+					THEN cart_items.quantity + EXCLUDED.quantity
+					ELSE EXCLUDED.quantity
+				END, ?),
+			deleted_at = NULL,
+			updated_at = NOW()
+		RETURNING *`,
+		cartID, productID, quantity, maxQuantity, maxQuantity).
+		Scan(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
 func (r *cartRepository) UpdateItem(ctx context.Context, cartItem *models.CartItem) error {
 	return r.db.WithContext(ctx).Save(cartItem).Error
 }
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestUpsertItemQuantityMergesIntoExistingLine(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewCartRepository(db)
+
+	user := createTestUser(t, db, "cart-merge@example.com")
+	product := createTestProduct(t, db, user.ID, "CART-MERGE", 10)
+	cart, err := repo.GetOrCreateCart(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetOrCreateCart: %v", err)
+	}
+
+	first, err := repo.UpsertItemQuantity(ctx, cart.ID, product.ID, 2, 10)
+	if err != nil {
+		t.Fatalf("first add: %v", err)
+	}
+	second, err := repo.UpsertItemQuantity(ctx, cart.ID, product.ID, 3, 10)
+	if err != nil {
+		t.Fatalf("second add: %v", err)
+	}
+
+	if second.ID != first.ID {
+		t.Errorf("second add created line %d, want it merged into %d", second.ID, first.ID)
+	}
+	if second.Quantity != 5 {
+		t.Errorf("quantity = %d, want 5", second.Quantity)
+	}
+}
+
+func TestUpsertItemQuantityClampsToMax(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewCartRepository(db)
+
+	user := createTestUser(t, db, "cart-clamp@example.com")
+	product := createTestProduct(t, db, user.ID, "CART-CLAMP", 4)
+	cart, err := repo.GetOrCreateCart(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetOrCreateCart: %v", err)
+	}
+
+	if _, err := repo.UpsertItemQuantity(ctx, cart.ID, product.ID, 3, 4); err != nil {
+		t.Fatalf("first add: %v", err)
+	}
+	item, err := repo.UpsertItemQuantity(ctx, cart.ID, product.ID, 3, 4)
+	if err != nil {
+		t.Fatalf("second add: %v", err)
+	}
+
+	if item.Quantity != 4 {
+		t.Errorf("quantity = %d, want it clamped to 4", item.Quantity)
+	}
+}
+
+func TestUpsertItemQuantityRevivesRemovedLine(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewCartRepository(db)
+
+	user := createTestUser(t, db, "cart-revive@example.com")
+	product := createTestProduct(t, db, user.ID, "CART-REVIVE", 10)
+	cart, err := repo.GetOrCreateCart(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetOrCreateCart: %v", err)
+	}
+
+	item, err := repo.UpsertItemQuantity(ctx, cart.ID, product.ID, 5, 10)
+	if err != nil {
+		t.Fatalf("first add: %v", err)
+	}
+	if err := repo.RemoveItem(ctx, cart.ID, item.ID); err != nil {
+		t.Fatalf("RemoveItem: %v", err)
+	}
+
+	item, err = repo.UpsertItemQuantity(ctx, cart.ID, product.ID, 2, 10)
+	if err != nil {
+		t.Fatalf("add after remove: %v", err)
+	}
+	if item.Quantity != 2 {
+		t.Errorf("quantity = %d, want 2 rather than adding to the removed line", item.Quantity)
+	}
+	if item.DeletedAt.Valid {
+		t.Error("line is still soft-deleted")
+	}
+}
+
+func TestUpsertItemQuantityConcurrentAddsAllCount(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewCartRepository(db)
+
+	user := createTestUser(t, db, "cart-race@example.com")
+	product := createTestProduct(t, db, user.ID, "CART-RACE", 100)
+	cart, err := repo.GetOrCreateCart(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetOrCreateCart: %v", err)
+	}
+
+	const adds = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, adds)
+	for i := 0; i < adds; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.UpsertItemQuantity(ctx, cart.ID, product.ID, 1, 100); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent add: %v", err)
+	}
+
+	item, err := repo.GetItemByProduct(ctx, cart.ID, product.ID)
+	if err != nil {
+		t.Fatalf("GetItemByProduct: %v", err)
+	}
+	if item.Quantity != adds {
+		t.Errorf("quantity = %d, want %d", item.Quantity, adds)
+	}
+}
@@ -18,6 +18,7 @@ type CategoryRepository interface {
 	GetAll(ctx context.Context) ([]models.Category, error)
 	GetByID(ctx context.Context, id uint) (*models.Category, error)
 	GetBySlug(ctx context.Context, slug string) (*models.Category, error)
+	NameExists(ctx context.Context, name string, excludeID uint) (bool, error)
 	Update(ctx context.Context, category *models.Category) error
 	Delete(ctx context.Context, id uint) error
 	GetChildren(ctx context.Context, parentID uint) ([]models.Category, error)
@@ -69,6 +70,17 @@ func (r *categoryRepository) GetBySlug(ctx context.Context, slug string) (*model
 	return &category, nil
 }
 
+// NameExists reports whether a live category other than excludeID has the
+// name; deleted categories' names can be reused
+func (r *categoryRepository) NameExists(ctx context.Context, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Category{}).
+		Where("name = ? AND id <> ?", name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (r *categoryRepository) Update(ctx context.Context, category *models.Category) error {
 	return r.db.WithContext(ctx).Save(category).Error
 }
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+func TestDeletedCategoryNameAndSlugCanBeReused(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewCategoryRepository(db)
+
+	first := &models.Category{Name: "Garden Tools", IsActive: true}
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Delete(ctx, first.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	second := &models.Category{Name: "Garden Tools", IsActive: true}
+	if err := repo.Create(ctx, second); err != nil {
+		t.Fatalf("recreating a deleted category: %v", err)
+	}
+	if second.Slug != first.Slug {
+		t.Errorf("slug = %q, want the deleted category's %q", second.Slug, first.Slug)
+	}
+
+	// Live categories still can't share a name
+	if err := db.Create(&models.Category{Name: "Garden Tools", Slug: "garden-tools-2"}).Error; err == nil {
+		t.Error("expected a duplicate live category name to be rejected")
+	}
+}
+
+func TestDeletedTagNameCanBeReused(t *testing.T) {
+	db := openTestDB(t)
+
+	tag := &models.Tag{Name: "outdoor"}
+	if err := db.Create(tag).Error; err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+	if err := db.Create(&models.Tag{Name: "outdoor"}).Error; err == nil {
+		t.Fatal("expected a duplicate live tag name to be rejected")
+	}
+
+	if err := db.Delete(tag).Error; err != nil {
+		t.Fatalf("failed to delete tag: %v", err)
+	}
+	if err := db.Create(&models.Tag{Name: "outdoor"}).Error; err != nil {
+		t.Errorf("recreating a deleted tag: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type conversationRepository struct {
+	db *gorm.DB
+}
+
+type ConversationRepository interface {
+	Create(ctx context.Context, conversation *models.Conversation, message *models.ConversationMessage) error
+	FindExisting(ctx context.Context, customerID, sellerID uint, productID, orderID *uint) (*models.Conversation, error)
+	GetByID(ctx context.Context, id uint) (*models.Conversation, error)
+	GetByParticipant(ctx context.Context, userID uint, limit, offset int) ([]*models.Conversation, int64, error)
+	AddMessage(ctx context.Context, message *models.ConversationMessage) error
+}
+
+func NewConversationRepository(db *gorm.DB) ConversationRepository {
+	return &conversationRepository{db: db}
+}
+
+// Create saves the conversation together with its first message
+func (r *conversationRepository) Create(ctx context.Context, conversation *models.Conversation, message *models.ConversationMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(conversation).Error; err != nil {
+			return err
+		}
+		message.ConversationID = conversation.ID
+		return tx.Create(message).Error
+	})
+}
+
+// FindExisting returns the customer's conversation with the seller about the
+// same product and order, if there is one
+func (r *conversationRepository) FindExisting(ctx context.Context, customerID, sellerID uint, productID, orderID *uint) (*models.Conversation, error) {
+	query := r.db.WithContext(ctx).Where("customer_id = ? AND seller_id = ?", customerID, sellerID)
+	if productID != nil {
+		query = query.Where("product_id = ?", *productID)
+	} else {
+		query = query.Where("product_id IS NULL")
+	}
+	if orderID != nil {
+		query = query.Where("order_id = ?", *orderID)
+	} else {
+		query = query.Where("order_id IS NULL")
+	}
+
+	var conversation models.Conversation
+	if err := query.First(&conversation).Error; err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// GetByID returns the conversation with its messages, oldest first
+func (r *conversationRepository) GetByID(ctx context.Context, id uint) (*models.Conversation, error) {
+	var conversation models.Conversation
+	err := r.db.WithContext(ctx).
+		Preload("Messages", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&conversation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// GetByParticipant returns the user's conversations as customer or seller,
+// most recent activity first
+func (r *conversationRepository) GetByParticipant(ctx context.Context, userID uint, limit, offset int) ([]*models.Conversation, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Conversation{}).
+		Where("customer_id = ? OR seller_id = ?", userID, userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var conversations []*models.Conversation
+	err := query.
+		Order("last_message_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&conversations).Error
+	return conversations, total, err
+}
+
+// AddMessage adds a message to the conversation and moves it to the top of
+// both participants' lists
+func (r *conversationRepository) AddMessage(ctx context.Context, message *models.ConversationMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Conversation{}).
+			Where("id = ?", message.ConversationID).
+			Update("last_message_at", message.CreatedAt).Error
+	})
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+type CouponRepository interface {
+	Create(ctx context.Context, coupon *models.Coupon) error
+	GetByID(ctx context.Context, id uint) (*models.Coupon, error)
+	GetByCode(ctx context.Context, code string) (*models.Coupon, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*models.Coupon, int64, error)
+	IncrementUsage(ctx context.Context, id uint) (bool, error)
+	CreateWelcome(ctx context.Context, coupon *models.Coupon) (bool, error)
+}
+
+func NewCouponRepository(db *gorm.DB) CouponRepository {
+	return &couponRepository{db: db}
+}
+
+func (r *couponRepository) Create(ctx context.Context, coupon *models.Coupon) error {
+	return r.db.WithContext(ctx).Create(coupon).Error
+}
+
+func (r *couponRepository) GetByID(ctx context.Context, id uint) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := r.db.WithContext(ctx).First(&coupon, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// GetByCode looks a coupon up by code; codes are stored upper case
+func (r *couponRepository) GetByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := r.db.WithContext(ctx).
+		Where("code = ?", strings.ToUpper(code)).
+		First(&coupon).Error
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+func (r *couponRepository) GetAll(ctx context.Context, limit, offset int) ([]*models.Coupon, int64, error) {
+	var coupons []*models.Coupon
+	var total int64
+
+	if err := r.db.WithContext(ctx).Model(&models.Coupon{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&coupons).Error
+	return coupons, total, err
+}
+
+// IncrementUsage counts one use of the coupon unless that would exceed its
+// usage limit. It reports whether the use was counted.
+func (r *couponRepository) IncrementUsage(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Coupon{}).
+		Where("id = ? AND (usage_limit IS NULL OR used_count < usage_limit)", id).
+		UpdateColumn("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CreateWelcome creates the welcome coupon for the coupon's user unless they
+// were already issued one, and reports whether it was created. The user row is
+// locked so concurrent issues for the same user are decided in turn.
+func (r *couponRepository) CreateWelcome(ctx context.Context, coupon *models.Coupon) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "welcome_coupon_id").
+			First(&user, *coupon.UserID).Error; err != nil {
+			return err
+		}
+		if user.WelcomeCouponID != nil {
+			return nil
+		}
+
+		if err := tx.Create(coupon).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&user).UpdateColumn("welcome_coupon_id", coupon.ID).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
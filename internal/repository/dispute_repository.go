@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type disputeRepository struct {
+	db *gorm.DB
+}
+
+type DisputeRepository interface {
+	Create(ctx context.Context, dispute *models.Dispute) error
+	Update(ctx context.Context, dispute *models.Dispute) error
+	GetByID(ctx context.Context, id uint) (*models.Dispute, error)
+	GetByStripeID(ctx context.Context, stripeDisputeID string) (*models.Dispute, error)
+	List(ctx context.Context, status *models.DisputeStatus, limit, offset int) ([]*models.Dispute, int64, error)
+}
+
+func NewDisputeRepository(db *gorm.DB) DisputeRepository {
+	return &disputeRepository{db: db}
+}
+
+func (r *disputeRepository) Create(ctx context.Context, dispute *models.Dispute) error {
+	return r.db.WithContext(ctx).Create(dispute).Error
+}
+
+func (r *disputeRepository) Update(ctx context.Context, dispute *models.Dispute) error {
+	return r.db.WithContext(ctx).Omit("Order").Save(dispute).Error
+}
+
+func (r *disputeRepository) GetByID(ctx context.Context, id uint) (*models.Dispute, error) {
+	var dispute models.Dispute
+	err := r.db.WithContext(ctx).
+		Preload("Order").
+		First(&dispute, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *disputeRepository) GetByStripeID(ctx context.Context, stripeDisputeID string) (*models.Dispute, error) {
+	var dispute models.Dispute
+	err := r.db.WithContext(ctx).
+		Where("stripe_dispute_id = ?", stripeDisputeID).
+		First(&dispute).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// List returns disputes, soonest evidence deadline first, optionally filtered by status
+func (r *disputeRepository) List(ctx context.Context, status *models.DisputeStatus, limit, offset int) ([]*models.Dispute, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Dispute{})
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var disputes []*models.Dispute
+	err := query.
+		Preload("Order").
+		Order("evidence_due_by ASC NULLS LAST, created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&disputes).Error
+	return disputes, total, err
+}
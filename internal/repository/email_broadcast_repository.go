@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type emailBroadcastRepository struct {
+	db *gorm.DB
+}
+
+type EmailBroadcastRepository interface {
+	CreateWithDeliveries(ctx context.Context, broadcast *models.EmailBroadcast) error
+	GetByID(ctx context.Context, id uint) (*models.EmailBroadcast, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*models.EmailBroadcast, int64, error)
+	CountDeliveries(ctx context.Context, broadcastIDs []uint) (map[uint]models.EmailDeliveryCounts, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.EmailDelivery, error)
+	MarkDeliverySent(ctx context.Context, id uint, sentAt time.Time) error
+	MarkDeliveryFailed(ctx context.Context, id uint, sendErr string, nextAttemptAt *time.Time) error
+}
+
+func NewEmailBroadcastRepository(db *gorm.DB) EmailBroadcastRepository {
+	return &emailBroadcastRepository{db: db}
+}
+
+// CreateWithDeliveries saves the broadcast and queues a delivery for every
+// active user in its audience, setting TotalRecipients
+func (r *emailBroadcastRepository) CreateWithDeliveries(ctx context.Context, broadcast *models.EmailBroadcast) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(broadcast).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			INSERT INTO email_deliveries (broadcast_id, user_id, email, name, status, attempts, next_attempt_at, created_at, updated_at)
+			SELECT ?, id, email, first_name, ?, 0, NOW(), NOW(), NOW()
+			FROM users
+			WHERE is_active = ? AND role IN ? AND deleted_at IS NULL`,
+			broadcast.ID, models.EmailDeliveryPending, true, broadcast.Audience.Roles())
+		if result.Error != nil {
+			return result.Error
+		}
+
+		broadcast.TotalRecipients = int(result.RowsAffected)
+		return tx.Model(broadcast).Update("total_recipients", broadcast.TotalRecipients).Error
+	})
+}
+
+func (r *emailBroadcastRepository) GetByID(ctx context.Context, id uint) (*models.EmailBroadcast, error) {
+	var broadcast models.EmailBroadcast
+	err := r.db.WithContext(ctx).First(&broadcast, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &broadcast, nil
+}
+
+func (r *emailBroadcastRepository) GetAll(ctx context.Context, limit, offset int) ([]*models.EmailBroadcast, int64, error) {
+	var broadcasts []*models.EmailBroadcast
+	var total int64
+
+	if err := r.db.WithContext(ctx).Model(&models.EmailBroadcast{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&broadcasts).Error
+	return broadcasts, total, err
+}
+
+// CountDeliveries returns each broadcast's delivery counts by status
+func (r *emailBroadcastRepository) CountDeliveries(ctx context.Context, broadcastIDs []uint) (map[uint]models.EmailDeliveryCounts, error) {
+	counts := make(map[uint]models.EmailDeliveryCounts)
+	if len(broadcastIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		BroadcastID uint
+		Status      models.EmailDeliveryStatus
+		Count       int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.EmailDelivery{}).
+		Select("broadcast_id, status, COUNT(*) AS count").
+		Where("broadcast_id IN ?", broadcastIDs).
+		Group("broadcast_id, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		entry := counts[row.BroadcastID]
+		switch row.Status {
+		case models.EmailDeliveryPending:
+			entry.Pending = row.Count
+		case models.EmailDeliverySent:
+			entry.Sent = row.Count
+		case models.EmailDeliveryFailed:
+			entry.Failed = row.Count
+		}
+		counts[row.BroadcastID] = entry
+	}
+	return counts, nil
+}
+
+// ClaimDueDeliveries takes up to limit pending deliveries that are due, oldest
+// first, and pushes their next attempt out by lease so no other instance picks
+// them up meanwhile. A delivery whose sender dies mid-send comes due again once
+// the lease runs out.
+func (r *emailBroadcastRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.EmailDelivery, error) {
+	var deliveries []*models.EmailDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.EmailDeliveryPending, now).
+			Order("next_attempt_at ASC, id ASC").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		if err := tx.Model(&models.EmailDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error; err != nil {
+			return err
+		}
+
+		// A recalled product may since have been deleted; its name is still needed
+		return tx.Preload("Broadcast").
+			Preload("Recall").
+			Preload("Recall.Product", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+			Where("id IN ?", ids).
+			Order("id ASC").
+			Find(&deliveries).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *emailBroadcastRepository) MarkDeliverySent(ctx context.Context, id uint, sentAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.EmailDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.EmailDeliverySent,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": nil,
+			"sent_at":    sentAt,
+		}).Error
+}
+
+// MarkDeliveryFailed records a failed attempt. With nextAttemptAt the delivery
+// stays pending for a retry then; without it the delivery has failed for good,
+// and a recall notice counts against its recall's failed emails.
+func (r *emailBroadcastRepository) MarkDeliveryFailed(ctx context.Context, id uint, sendErr string, nextAttemptAt *time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": sendErr,
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = *nextAttemptAt
+	} else {
+		updates["status"] = models.EmailDeliveryFailed
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EmailDelivery{}).
+			Where("id = ?", id).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+		if nextAttemptAt != nil {
+			return nil
+		}
+
+		return tx.Model(&models.ProductRecall{}).
+			Where("id = (?)", tx.Model(&models.EmailDelivery{}).Select("recall_id").Where("id = ?", id)).
+			Update("emails_failed", gorm.Expr("emails_failed + 1")).Error
+	})
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "errors"
+
+var (
+	ErrProductImageNotFound  = errors.New("product image not found")
+	ErrPrimaryImageNotFound  = errors.New("primary image not found")
+	ErrImagesMixedProducts   = errors.New("images must belong to the same product")
+	ErrMultiplePrimaryImages = errors.New("only one image can be primary")
+)
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type featuredSellerRepository struct {
+	db *gorm.DB
+}
+
+type FeaturedSellerRepository interface {
+	Create(ctx context.Context, featured *models.FeaturedSeller) error
+	GetBySellerID(ctx context.Context, sellerID uint) (*models.FeaturedSeller, error)
+	GetAll(ctx context.Context) ([]*models.FeaturedSeller, error)
+	GetActive(ctx context.Context, limit int) ([]*models.FeaturedSeller, error)
+	CountActive(ctx context.Context) (int64, error)
+	MaxPosition(ctx context.Context) (int, error)
+	DeleteBySellerID(ctx context.Context, sellerID uint) error
+	Reorder(ctx context.Context, sellerIDs []uint) error
+}
+
+func NewFeaturedSellerRepository(db *gorm.DB) FeaturedSellerRepository {
+	return &featuredSellerRepository{db: db}
+}
+
+func (r *featuredSellerRepository) Create(ctx context.Context, featured *models.FeaturedSeller) error {
+	return r.db.WithContext(ctx).Create(featured).Error
+}
+
+func (r *featuredSellerRepository) GetBySellerID(ctx context.Context, sellerID uint) (*models.FeaturedSeller, error) {
+	var featured models.FeaturedSeller
+	err := r.db.WithContext(ctx).
+		Where("seller_id = ?", sellerID).
+		First(&featured).Error
+	if err != nil {
+		return nil, err
+	}
+	return &featured, nil
+}
+
+// GetAll returns every featured entry, expired ones included, for admins
+func (r *featuredSellerRepository) GetAll(ctx context.Context) ([]*models.FeaturedSeller, error) {
+	var featured []*models.FeaturedSeller
+	err := r.db.WithContext(ctx).
+		Preload("Seller").
+		Order("position ASC, created_at ASC").
+		Find(&featured).Error
+	return featured, err
+}
+
+// GetActive returns unexpired entries whose seller is still an active seller
+func (r *featuredSellerRepository) GetActive(ctx context.Context, limit int) ([]*models.FeaturedSeller, error) {
+	var featured []*models.FeaturedSeller
+	err := r.activeQuery(ctx).
+		Preload("Seller").
+		Order("featured_sellers.position ASC, featured_sellers.created_at ASC").
+		Limit(limit).
+		Find(&featured).Error
+	return featured, err
+}
+
+func (r *featuredSellerRepository) CountActive(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.activeQuery(ctx).Count(&count).Error
+	return count, err
+}
+
+func (r *featuredSellerRepository) MaxPosition(ctx context.Context) (int, error) {
+	var position int
+	err := r.db.WithContext(ctx).
+		Model(&models.FeaturedSeller{}).
+		Select("COALESCE(MAX(position), -1)").
+		Scan(&position).Error
+	return position, err
+}
+
+// DeleteBySellerID removes the seller from the list for good so they can be featured again later
+func (r *featuredSellerRepository) DeleteBySellerID(ctx context.Context, sellerID uint) error {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Where("seller_id = ?", sellerID).
+		Delete(&models.FeaturedSeller{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Reorder sets each seller's position to its index in sellerIDs
+func (r *featuredSellerRepository) Reorder(ctx context.Context, sellerIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, sellerID := range sellerIDs {
+			result := tx.Model(&models.FeaturedSeller{}).
+				Where("seller_id = ?", sellerID).
+				Update("position", position)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
+func (r *featuredSellerRepository) activeQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&models.FeaturedSeller{}).
+		Joins("JOIN users ON users.id = featured_sellers.seller_id").
+		Where("featured_sellers.expires_at IS NULL OR featured_sellers.expires_at > ?", time.Now()).
+		Where("users.role = ? AND users.is_active = ? AND users.deleted_at IS NULL", models.RoleSeller, true)
+}
@@ -20,60 +20,134 @@ type UserRepository interface {
 	CreatePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error
 	GetPasswordResetToken(ctx context.Context, tokenStr string) (*models.PasswordResetToken, error)
 	MarkPasswordResetTokenUsed(ctx context.Context, tokenStr string) error
+	InvalidatePasswordResetTokens(ctx context.Context, userID uint) error
 	CreateEmailVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error
 	GetEmailVerificationToken(ctx context.Context, tokenStr string) (*models.EmailVerificationToken, error)
 	MarkEmailVerificationTokenUsed(ctx context.Context, tokenStr string) error
+	InvalidateEmailVerificationTokens(ctx context.Context, userID uint) error
 	MarkEmailVerified(ctx context.Context, userID uint) error
+	GetStaff(ctx context.Context, employerID uint) ([]models.User, error)
 }
 
 // ProductRepository defines the interface for product data operations
 type ProductRepository interface {
 	Create(ctx context.Context, product *models.Product) error
 	GetByID(ctx context.Context, id uint) (*models.Product, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]*models.Product, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Product, error)
+	SlugExists(ctx context.Context, slug string) (bool, error)
+	SKUExists(ctx context.Context, sku string) (bool, error)
+	IncrementViewCount(ctx context.Context, id uint) error
 	GetAll(ctx context.Context, limit, offset int) ([]*models.Product, error)
-	GetByCategory(ctx context.Context, category string, limit, offset int) ([]*models.Product, error)
+	GetByCategory(ctx context.Context, category string, sortBy models.ProductSort, limit, offset int) ([]*models.Product, error)
 	GetBySellerID(ctx context.Context, sellerID uint, limit, offset int) ([]*models.Product, error)
 	Search(ctx context.Context, query string, limit, offset int) ([]*models.Product, error)
 	Update(ctx context.Context, product *models.Product) error
+	UpdateWithPriceChange(ctx context.Context, product *models.Product, change *models.PriceHistory) error
 	Delete(ctx context.Context, id uint) error
 	UpdateStock(ctx context.Context, id uint, stock int) error
+	AdjustStock(ctx context.Context, id uint, delta int) error
+	DecrementStock(ctx context.Context, id uint, quantity int) (int, bool, error)
 	GetLowStock(ctx context.Context, threshold int) ([]*models.Product, error)
 	Count(ctx context.Context) (int64, error)
 	CountByCategory(ctx context.Context, category string) (int64, error)
-	GetTopRated(ctx context.Context, limit int) ([]*models.Product, error)
+	CountBySellerID(ctx context.Context, sellerID uint) (int64, error)
+	GetTopRated(ctx context.Context, limit int, gate models.RatingGate) ([]*models.Product, error)
+	GetFeatured(ctx context.Context, limit int, gate models.RatingGate) ([]*models.Product, error)
+	GetRelated(ctx context.Context, product *models.Product, limit int, gate models.RatingGate) ([]*models.Product, error)
+	GetNewArrivals(ctx context.Context, since time.Time, category string, includeOutOfStock bool, limit int) ([]*models.Product, error)
+	AddPurchaseLimitExemption(ctx context.Context, exemption *models.PurchaseLimitExemption) error
+	RemovePurchaseLimitExemption(ctx context.Context, productID, userID uint) error
+	HasPurchaseLimitExemption(ctx context.Context, productID, userID uint) (bool, error)
 	UpdateRating(ctx context.Context, productID uint, averageRating float64, reviewCount int) error
+	SuggestNames(ctx context.Context, prefix string, limit int) ([]string, error)
+	GetFiltered(ctx context.Context, req *models.GetProductsRequest) ([]*models.Product, int64, error)
+	GetPriceTierCounts(ctx context.Context, req *models.GetProductsRequest) ([]models.PriceTierCount, error)
+	GetTagCounts(ctx context.Context, req *models.GetProductsRequest, limit int) ([]models.TagCount, error)
+	GetPopularTags(ctx context.Context, limit int) ([]models.TagCount, error)
+	GetInventoryValuation(ctx context.Context, sellerID uint) (*models.InventoryValuation, error)
+	GetLowStockBySeller(ctx context.Context, sellerID uint) ([]*models.Product, error)
+	GetUnitsSold(ctx context.Context, productIDs []uint, since time.Time) (map[uint]int, error)
+	GetNeedingAttention(ctx context.Context, sellerID uint, criteria models.ProductAttentionCriteria, limit, offset int) ([]models.ProductAttention, int64, error)
+	UpsertTranslation(ctx context.Context, translation *models.ProductTranslation) error
+	GetTranslations(ctx context.Context, productID uint) ([]*models.ProductTranslation, error)
+	GetTranslationsFor(ctx context.Context, productIDs []uint, locales []string) ([]*models.ProductTranslation, error)
+	DeleteTranslation(ctx context.Context, productID uint, locale string) error
+	GetPriceHistory(ctx context.Context, productID uint, limit, offset int) ([]*models.PriceHistory, int64, error)
+	GetLowestPriceSince(ctx context.Context, productID uint, since time.Time) (*float64, error)
+	GetChangedSince(ctx context.Context, cursor models.ProductChangeCursor, limit int) ([]*models.Product, error)
+	BulkSetVisibility(ctx context.Context, productIDs []uint, visible bool, status *models.ProductStatus, sellerID *uint, actorID uint) ([]models.VisibilityItemResult, error)
+	HideSellerProducts(ctx context.Context, sellerID, actorID uint) ([]uint, error)
+	ResolveApproval(ctx context.Context, id uint, approved bool, reason *string, actorID uint) (bool, error)
+	GetPendingApproval(ctx context.Context, limit, offset int) ([]*models.Product, int64, error)
+	RestoreSellerProducts(ctx context.Context, sellerID, actorID uint) ([]uint, error)
 }
 
 // OrderRepository defines the interface for order data operations
 type OrderRepository interface {
 	Create(ctx context.Context, order *models.Order) error
 	GetByID(ctx context.Context, id uint) (*models.Order, error)
-	GetByUserID(ctx context.Context, userID uint, limit, offset int) ([]*models.Order, error)
+	GetByOrderNumber(ctx context.Context, orderNumber string) (*models.Order, error)
+	GetByUserID(ctx context.Context, userID uint, includeArchived bool, limit, offset int) ([]*models.Order, error)
 	GetAll(ctx context.Context, limit, offset int) ([]*models.Order, error)
+	GetFiltered(ctx context.Context, filter *models.OrderFilter, limit, offset int) ([]*models.Order, int64, error)
 	GetByStatus(ctx context.Context, status models.OrderStatus, limit, offset int) ([]*models.Order, error)
 	GetByDateRange(ctx context.Context, startDate, endDate time.Time, limit, offset int) ([]*models.Order, error)
 	Update(ctx context.Context, order *models.Order) error
 	UpdateStatus(ctx context.Context, id uint, status models.OrderStatus) error
 	UpdateTrackingNumber(ctx context.Context, id uint, trackingNumber string) error
+	UpdateShippingAddress(ctx context.Context, order *models.Order) error
+	UpdateFulfillment(ctx context.Context, fulfillment *models.OrderFulfillment) error
+	SaveRecalculation(ctx context.Context, order *models.Order, entry *models.AuditLog) error
 	Delete(ctx context.Context, id uint) error
 	Count(ctx context.Context) (int64, error)
 	CountByUserID(ctx context.Context, userID uint) (int64, error)
 	CountByStatus(ctx context.Context, status models.OrderStatus) (int64, error)
 	GetTotalRevenue(ctx context.Context, startDate, endDate *time.Time) (float64, error)
-	GetOrdersBySellerID(ctx context.Context, sellerID uint, limit, offset int) ([]*models.Order, error)
+	GetOrdersBySellerID(ctx context.Context, sellerID uint, filter *models.SellerOrderFilter, limit, offset int) ([]*models.Order, error)
+	GetAssignedOrders(ctx context.Context, staffID uint, limit, offset int) ([]*models.Order, error)
+	UpdateAssignment(ctx context.Context, order *models.Order) error
+	UnassignStaff(ctx context.Context, staffID uint) error
+	GetAwaitingConfirmation(ctx context.Context, sellerID *uint, limit, offset int) ([]*models.Order, error)
+	GetOrdersByProductID(ctx context.Context, productID, sellerID uint, limit, offset int) ([]*models.Order, error)
 	GetRevenueBySellerID(ctx context.Context, sellerID uint, startDate, endDate *time.Time) (float64, error)
+	GetPeriodSales(ctx context.Context, sellerID *uint, startDate, endDate time.Time) (*models.PeriodSales, error)
+	Cancel(ctx context.Context, id uint, reason models.CancellationReason, note *string) error
+	CancelPending(ctx context.Context, id uint, reason models.CancellationReason, note *string) (bool, error)
+	AddStatusHistory(ctx context.Context, history *models.OrderStatusHistory) error
+	CountStatusHistoryNotes(ctx context.Context, orderID uint, note string, since time.Time) (int64, error)
+	GetCancellationBreakdown(ctx context.Context, startDate, endDate time.Time) ([]models.CancellationReasonCount, error)
+	UpdatePaymentStatus(ctx context.Context, id uint, status models.PaymentStatus) error
+	UpdatePaymentID(ctx context.Context, id uint, paymentID string) error
+	GetByPaymentID(ctx context.Context, paymentID string) (*models.Order, error)
+	CountFailedPaymentsSince(ctx context.Context, customerID uint, since time.Time) (int64, error)
+	GetCustomerStats(ctx context.Context, customerID uint) (*models.CustomerStats, error)
+	SumCustomerProductQuantity(ctx context.Context, customerID, productID uint, since *time.Time) (int, error)
+	GetBestSellers(ctx context.Context, startDate, endDate time.Time, limit int) ([]models.BestSeller, error)
+	EachBestSeller(ctx context.Context, startDate, endDate time.Time, fn func(*models.BestSeller) error) error
+	EachOrderExportLine(ctx context.Context, startDate, endDate time.Time, fn func(*models.OrderExportLine) error) error
+	GetStuckOrders(ctx context.Context, thresholds map[models.OrderStatus]time.Duration, now time.Time, unalertedOnly bool, limit, offset int) ([]models.StuckOrder, error)
+	MarkSLAAlerted(ctx context.Context, ids []uint, alertedAt time.Time) error
+	GetDueReviewReminders(ctx context.Context, deliveredBefore time.Time, limit int) ([]*models.Order, error)
+	MarkReviewRemindersSent(ctx context.Context, ids []uint, sentAt time.Time) error
+	Archive(ctx context.Context, createdBefore time.Time, limit int) (int64, error)
 }
 
 // ReviewRepository defines the interface for review data operations
 type ReviewRepository interface {
 	Create(ctx context.Context, review *models.Review) error
 	GetByID(ctx context.Context, id uint) (*models.Review, error)
-	GetByProductID(ctx context.Context, productID uint, limit, offset int) ([]*models.Review, error)
+	GetByProductID(ctx context.Context, productID uint, sortBy models.ReviewSort, limit, offset int) ([]*models.Review, error)
+	GetApprovedTextByProductID(ctx context.Context, productID uint) ([]*models.Review, error)
 	GetByUserID(ctx context.Context, userID uint, limit, offset int) ([]*models.Review, error)
+	GetBySellerID(ctx context.Context, sellerID uint, filter *models.SellerReviewFilter, limit, offset int) ([]*models.Review, int64, error)
 	GetByRating(ctx context.Context, rating int, limit, offset int) ([]*models.Review, error)
 	Update(ctx context.Context, review *models.Review) error
+	UpdateWithEdit(ctx context.Context, review *models.Review, edit *models.ReviewEdit) error
+	GetEdits(ctx context.Context, reviewID uint) ([]*models.ReviewEdit, error)
 	Delete(ctx context.Context, id uint) error
 	GetByUserAndProduct(ctx context.Context, userID, productID uint) (*models.Review, error)
+	GetByUserProductAndOrder(ctx context.Context, userID, productID uint, orderID *uint) (*models.Review, error)
 	Count(ctx context.Context) (int64, error)
 	CountByProductID(ctx context.Context, productID uint) (int64, error)
 	CountByUserID(ctx context.Context, userID uint) (int64, error)
@@ -82,6 +156,9 @@ type ReviewRepository interface {
 	GetTopReviews(ctx context.Context, limit int) ([]*models.Review, error)
 	GetRecentReviews(ctx context.Context, limit int) ([]*models.Review, error)
 	CheckUserCanReview(ctx context.Context, userID, productID uint) (bool, error)
+	CountDeliveredPurchases(ctx context.Context, userID, productID uint) (int64, error)
+	CreateWithinPurchases(ctx context.Context, review *models.Review, purchases int64) (bool, error)
+	BulkModerate(ctx context.Context, reviewIDs []uint, action models.ModerationAction, actorID uint) ([]models.ModerationItemResult, []uint, error)
 }
 
 // ProductImageRepository defines the interface for product image data operations
@@ -89,6 +166,7 @@ type ProductImageRepository interface {
 	Create(ctx context.Context, productImage *models.ProductImage) error
 	GetByProductID(ctx context.Context, productID uint) ([]models.ProductImage, error)
 	GetByID(ctx context.Context, id uint) (*models.ProductImage, error)
+	CountByProductID(ctx context.Context, productID uint) (int64, error)
 	Update(ctx context.Context, productImage *models.ProductImage) error
 	Delete(ctx context.Context, id uint) error
 	DeleteByProductID(ctx context.Context, productID uint) error
@@ -96,6 +174,7 @@ type ProductImageRepository interface {
 	GetPrimaryImage(ctx context.Context, productID uint) (*models.ProductImage, error)
 	UpdateSortOrder(ctx context.Context, productID uint, imageID uint, sortOrder int) error
 	BulkCreate(ctx context.Context, productImages []models.ProductImage) error
+	GetAccessibilityGaps(ctx context.Context, sellerID uint, limit, offset int) ([]models.ProductAccessibility, int64, error)
 }
 
 // UserStatsResponse represents user statistics (defined here to avoid circular imports)
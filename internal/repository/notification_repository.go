@@ -13,6 +13,7 @@ type notificationRepository struct {
 
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *models.Notification) error
+	CreateBatch(ctx context.Context, notifications []*models.Notification) error
 	GetByUser(ctx context.Context, userID uint, page, limit int) ([]models.Notification, int64, error)
 	GetUnreadCount(ctx context.Context, userID uint) (int64, error)
 	MarkAsRead(ctx context.Context, userID, notificationID uint) error
@@ -29,6 +30,13 @@ func (r *notificationRepository) Create(ctx context.Context, notification *model
 	return r.db.WithContext(ctx).Create(notification).Error
 }
 
+func (r *notificationRepository) CreateBatch(ctx context.Context, notifications []*models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(notifications, 100).Error
+}
+
 func (r *notificationRepository) GetByUser(ctx context.Context, userID uint, page, limit int) ([]models.Notification, int64, error) {
 	var notifications []models.Notification
 	var total int64
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -23,9 +25,10 @@ func (r *orderRepository) Create(ctx context.Context, order *models.Order) error
 func (r *orderRepository) GetByID(ctx context.Context, id uint) (*models.Order, error) {
 	var order models.Order
 	err := r.db.WithContext(ctx).
-		Preload("User").
-		Preload("Items").
-		Preload("Items.Product").
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
 		First(&order, id).Error
 	if err != nil {
 		return nil, err
@@ -33,12 +36,31 @@ func (r *orderRepository) GetByID(ctx context.Context, id uint) (*models.Order,
 	return &order, nil
 }
 
-func (r *orderRepository) GetByUserID(ctx context.Context, userID uint, limit, offset int) ([]*models.Order, error) {
-	var orders []*models.Order
+// GetByOrderNumber returns the order with its customer, fulfillments and
+// status history, oldest change first
+func (r *orderRepository) GetByOrderNumber(ctx context.Context, orderNumber string) (*models.Order, error) {
+	var order models.Order
 	err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
-		Preload("Items").
-		Preload("Items.Product").
+		Preload("Customer").
+		Preload("Fulfillments").
+		Preload("StatusHistory", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("order_number = ?", orderNumber).
+		First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *orderRepository) GetByUserID(ctx context.Context, userID uint, includeArchived bool, limit, offset int) ([]*models.Order, error) {
+	var orders []*models.Order
+	err := applyArchivedFilter(r.db.WithContext(ctx), includeArchived).
+		Where("customer_id = ?", userID).
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -49,9 +71,10 @@ func (r *orderRepository) GetByUserID(ctx context.Context, userID uint, limit, o
 func (r *orderRepository) GetAll(ctx context.Context, limit, offset int) ([]*models.Order, error) {
 	var orders []*models.Order
 	err := r.db.WithContext(ctx).
-		Preload("User").
-		Preload("Items").
-		Preload("Items.Product").
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -59,13 +82,55 @@ func (r *orderRepository) GetAll(ctx context.Context, limit, offset int) ([]*mod
 	return orders, err
 }
 
+// GetFiltered returns orders matching the filter, newest first, with the total
+// number of matches. The category filter uses a subquery rather than a join so
+// orders with several matching items are counted once.
+func (r *orderRepository) GetFiltered(ctx context.Context, filter *models.OrderFilter, limit, offset int) ([]*models.Order, int64, error) {
+	var orders []*models.Order
+	var total int64
+
+	query := applyArchivedFilter(r.db.WithContext(ctx).Model(&models.Order{}), filter.IncludeArchived)
+	if filter.Status != nil {
+		query = query.Where("orders.status = ?", *filter.Status)
+	}
+	if filter.DateFrom != nil {
+		query = query.Where("orders.created_at >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		query = query.Where("orders.created_at < ?", *filter.DateTo)
+	}
+	if filter.Category != "" {
+		query = query.Where("EXISTS (?)", r.db.
+			Table("order_items").
+			Select("1").
+			Joins("JOIN products ON products.id = order_items.product_id").
+			Where("order_items.order_id = orders.id AND order_items.deleted_at IS NULL AND products.category = ?", filter.Category))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
+		Order("orders.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).Error
+	return orders, total, err
+}
+
 func (r *orderRepository) GetByStatus(ctx context.Context, status models.OrderStatus, limit, offset int) ([]*models.Order, error) {
 	var orders []*models.Order
 	err := r.db.WithContext(ctx).
 		Where("status = ?", status).
-		Preload("User").
-		Preload("Items").
-		Preload("Items.Product").
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -77,9 +142,10 @@ func (r *orderRepository) GetByDateRange(ctx context.Context, startDate, endDate
 	var orders []*models.Order
 	err := r.db.WithContext(ctx).
 		Where("created_at BETWEEN ? AND ?", startDate, endDate).
-		Preload("User").
-		Preload("Items").
-		Preload("Items.Product").
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -91,11 +157,96 @@ func (r *orderRepository) Update(ctx context.Context, order *models.Order) error
 	return r.db.WithContext(ctx).Save(order).Error
 }
 
+// SaveRecalculation saves the order's recomputed amounts, its line totals and
+// its fulfillment allocations, with the audit entry recording the change
+func (r *orderRepository) SaveRecalculation(ctx context.Context, order *models.Order, entry *models.AuditLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(order).
+			Select("item_count", "subtotal_amount", "shipping_amount", "tax_amount", "discount_amount", "total_amount").
+			Updates(order).Error; err != nil {
+			return err
+		}
+		for i := range order.OrderItems {
+			item := &order.OrderItems[i]
+			if err := tx.Model(item).UpdateColumn("total_price", item.TotalPrice).Error; err != nil {
+				return err
+			}
+		}
+		for i := range order.Fulfillments {
+			fulfillment := &order.Fulfillments[i]
+			if err := tx.Model(fulfillment).
+				Select("item_count", "subtotal_amount", "tax_amount", "shipping_amount", "discount_amount", "allocated_amount").
+				Updates(fulfillment).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// UpdateShippingAddress saves only the order's shipping address fields and
+// its shipping charge and total, with its seller groups' shares of them
+func (r *orderRepository) UpdateShippingAddress(ctx context.Context, order *models.Order) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(order).
+			Select("shipping_first_name", "shipping_last_name", "shipping_phone", "shipping_street",
+				"shipping_city", "shipping_state", "shipping_country", "shipping_postal_code",
+				"shipping_amount", "total_amount").
+			Updates(order).Error; err != nil {
+			return err
+		}
+		for i := range order.Fulfillments {
+			fulfillment := &order.Fulfillments[i]
+			if err := tx.Model(fulfillment).
+				Select("shipping_amount", "allocated_amount").
+				Updates(fulfillment).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateStatus sets the order and fulfillment status, stamping shipped_at and
+// delivered_at the first time the order reaches those states
 func (r *orderRepository) UpdateStatus(ctx context.Context, id uint, status models.OrderStatus) error {
-	return r.db.WithContext(ctx).
-		Model(&models.Order{}).
-		Where("id = ?", id).
-		Update("status", status).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Order{}).
+			Where("id = ?", id).
+			Update("status", status).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.OrderFulfillment{}).
+			Where("order_id = ?", id).
+			Update("status", status).Error; err != nil {
+			return err
+		}
+
+		var column string
+		switch status {
+		case models.OrderStatusShipped:
+			column = "shipped_at"
+		case models.OrderStatusDelivered:
+			column = "delivered_at"
+		default:
+			return nil
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.Order{}).
+			Where("id = ? AND "+column+" IS NULL", id).
+			Update(column, now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.OrderFulfillment{}).
+			Where("order_id = ? AND "+column+" IS NULL", id).
+			Update(column, now).Error
+	})
+}
+
+func (r *orderRepository) UpdateFulfillment(ctx context.Context, fulfillment *models.OrderFulfillment) error {
+	return r.db.WithContext(ctx).Save(fulfillment).Error
 }
 
 func (r *orderRepository) UpdateTrackingNumber(ctx context.Context, id uint, trackingNumber string) error {
@@ -119,7 +270,7 @@ func (r *orderRepository) CountByUserID(ctx context.Context, userID uint) (int64
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&models.Order{}).
-		Where("user_id = ?", userID).
+		Where("customer_id = ?", userID).
 		Count(&count).Error
 	return count, err
 }
@@ -148,15 +299,17 @@ func (r *orderRepository) GetTotalRevenue(ctx context.Context, startDate, endDat
 	return total, err
 }
 
-func (r *orderRepository) GetOrdersBySellerID(ctx context.Context, sellerID uint, limit, offset int) ([]*models.Order, error) {
+func (r *orderRepository) GetOrdersBySellerID(ctx context.Context, sellerID uint, filter *models.SellerOrderFilter, limit, offset int) ([]*models.Order, error) {
 	var orders []*models.Order
-	err := r.db.WithContext(ctx).
+	query := applyArchivedFilter(r.db.WithContext(ctx), filter.IncludeArchived)
+	err := applyAssigneeFilter(query, sellerID, filter).
 		Joins("JOIN order_items ON orders.id = order_items.order_id").
 		Joins("JOIN products ON order_items.product_id = products.id").
 		Where("products.seller_id = ?", sellerID).
-		Preload("User").
-		Preload("Items").
-		Preload("Items.Product").
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
 		Group("orders.id").
 		Order("orders.created_at DESC").
 		Limit(limit).
@@ -165,6 +318,95 @@ func (r *orderRepository) GetOrdersBySellerID(ctx context.Context, sellerID uint
 	return orders, err
 }
 
+// GetAssignedOrders returns the live orders with a portion assigned to the
+// staff member, newest first
+func (r *orderRepository) GetAssignedOrders(ctx context.Context, staffID uint, limit, offset int) ([]*models.Order, error) {
+	var orders []*models.Order
+	err := applyArchivedFilter(r.db.WithContext(ctx), false).
+		Where(`(orders.assigned_to = ? OR EXISTS (
+			SELECT 1 FROM order_fulfillments
+			WHERE order_fulfillments.order_id = orders.id
+			AND order_fulfillments.assigned_to = ?
+			AND order_fulfillments.deleted_at IS NULL))`, staffID, staffID).
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
+		Order("orders.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).Error
+	return orders, err
+}
+
+// UpdateAssignment saves only the order's assignment fields
+func (r *orderRepository) UpdateAssignment(ctx context.Context, order *models.Order) error {
+	return r.db.WithContext(ctx).Model(order).
+		Select("assigned_to", "assigned_at").
+		Updates(order).Error
+}
+
+// UnassignStaff clears every assignment to the staff member
+func (r *orderRepository) UnassignStaff(ctx context.Context, staffID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		unassigned := map[string]interface{}{"assigned_to": nil, "assigned_at": nil}
+		if err := tx.Model(&models.Order{}).
+			Where("assigned_to = ?", staffID).
+			Updates(unassigned).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.OrderFulfillment{}).
+			Where("assigned_to = ?", staffID).
+			Updates(unassigned).Error
+	})
+}
+
+// GetAwaitingConfirmation returns paid orders awaiting confirmation, oldest
+// first, optionally only those containing the seller's products
+func (r *orderRepository) GetAwaitingConfirmation(ctx context.Context, sellerID *uint, limit, offset int) ([]*models.Order, error) {
+	var orders []*models.Order
+	query := r.db.WithContext(ctx).
+		Where("orders.status = ?", models.OrderStatusAwaitingConfirmation)
+
+	if sellerID != nil {
+		query = query.
+			Joins("JOIN order_items ON orders.id = order_items.order_id").
+			Joins("JOIN products ON order_items.product_id = products.id").
+			Where("products.seller_id = ?", *sellerID).
+			Group("orders.id")
+	}
+
+	err := query.
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
+		Order("orders.updated_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) GetOrdersByProductID(ctx context.Context, productID, sellerID uint, limit, offset int) ([]*models.Order, error) {
+	var orders []*models.Order
+	err := r.db.WithContext(ctx).
+		Where("orders.id IN (?)", r.db.
+			Table("order_items").
+			Select("order_items.order_id").
+			Joins("JOIN products ON order_items.product_id = products.id").
+			Where("order_items.product_id = ? AND products.seller_id = ?", productID, sellerID)).
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("OrderItems.Product").
+		Preload("Fulfillments").
+		Order("orders.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).Error
+	return orders, err
+}
+
 func (r *orderRepository) GetRevenueBySellerID(ctx context.Context, sellerID uint, startDate, endDate *time.Time) (float64, error) {
 	var total float64
 	query := r.db.WithContext(ctx).
@@ -172,7 +414,7 @@ func (r *orderRepository) GetRevenueBySellerID(ctx context.Context, sellerID uin
 		Joins("JOIN products ON order_items.product_id = products.id").
 		Joins("JOIN orders ON order_items.order_id = orders.id").
 		Where("products.seller_id = ? AND orders.status = ?", sellerID, models.OrderStatusDelivered).
-		Select("COALESCE(SUM(order_items.price * order_items.quantity), 0)")
+		Select("COALESCE(SUM(order_items.total_price - order_items.discount_amount), 0)")
 
 	if startDate != nil && endDate != nil {
 		query = query.Where("orders.created_at BETWEEN ? AND ?", startDate, endDate)
@@ -181,3 +423,469 @@ func (r *orderRepository) GetRevenueBySellerID(ctx context.Context, sellerID uin
 	err := query.Scan(&total).Error
 	return total, err
 }
+
+// GetPeriodSales returns delivered revenue and order count for orders placed
+// in the range, counted the same way as GetTotalRevenue and
+// GetRevenueBySellerID. For a seller only their items' revenue counts.
+func (r *orderRepository) GetPeriodSales(ctx context.Context, sellerID *uint, startDate, endDate time.Time) (*models.PeriodSales, error) {
+	var row struct {
+		Revenue float64
+		Orders  int64
+	}
+
+	var query *gorm.DB
+	if sellerID != nil {
+		query = r.db.WithContext(ctx).
+			Model(&models.OrderItem{}).
+			Joins("JOIN products ON order_items.product_id = products.id").
+			Joins("JOIN orders ON order_items.order_id = orders.id").
+			Where("products.seller_id = ?", *sellerID).
+			Select("COALESCE(SUM(order_items.total_price - order_items.discount_amount), 0) AS revenue, COUNT(DISTINCT orders.id) AS orders")
+	} else {
+		query = r.db.WithContext(ctx).
+			Model(&models.Order{}).
+			Select("COALESCE(SUM(orders.total_amount), 0) AS revenue, COUNT(*) AS orders")
+	}
+
+	err := query.
+		Where("orders.status = ? AND orders.created_at BETWEEN ? AND ?", models.OrderStatusDelivered, startDate, endDate).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sales := &models.PeriodSales{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Revenue:   row.Revenue,
+		Orders:    row.Orders,
+	}
+	if row.Orders > 0 {
+		sales.AverageOrderValue = money.Round(row.Revenue / float64(row.Orders))
+	}
+	return sales, nil
+}
+
+func (r *orderRepository) Cancel(ctx context.Context, id uint, reason models.CancellationReason, note *string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := cancelOrder(tx, id, reason, note)
+		return err
+	})
+}
+
+// CancelPending cancels the order only while it's still pending and reports
+// whether it did, so a sweep can't cancel an order that was paid meanwhile
+func (r *orderRepository) CancelPending(ctx context.Context, id uint, reason models.CancellationReason, note *string) (bool, error) {
+	var cancelled bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		cancelled, err = cancelOrder(tx, id, reason, note, models.OrderStatusPending)
+		return err
+	})
+	return cancelled, err
+}
+
+// cancelOrder cancels the order and its fulfillments, limited to the given
+// statuses if any, and reports whether the order was cancelled
+func cancelOrder(tx *gorm.DB, id uint, reason models.CancellationReason, note *string, statuses ...models.OrderStatus) (bool, error) {
+	query := tx.Model(&models.Order{}).Where("id = ?", id)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	result := query.Updates(map[string]interface{}{
+			"status":              models.OrderStatusCancelled,
+			"cancellation_reason": reason,
+			"cancellation_note":   note,
+			"cancelled_at":        time.Now(),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	return true, tx.Model(&models.OrderFulfillment{}).
+		Where("order_id = ?", id).
+		Update("status", models.OrderStatusCancelled).Error
+}
+
+func (r *orderRepository) AddStatusHistory(ctx context.Context, history *models.OrderStatusHistory) error {
+	return r.db.WithContext(ctx).Create(history).Error
+}
+
+// CountStatusHistoryNotes counts the order's history entries with the note recorded since the given time
+func (r *orderRepository) CountStatusHistoryNotes(ctx context.Context, orderID uint, note string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.OrderStatusHistory{}).
+		Where("order_id = ? AND note = ? AND created_at >= ?", orderID, note, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *orderRepository) GetCancellationBreakdown(ctx context.Context, startDate, endDate time.Time) ([]models.CancellationReasonCount, error) {
+	var breakdown []models.CancellationReasonCount
+	err := r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Select("COALESCE(cancellation_reason, ?) AS reason, COUNT(*) AS count", models.CancellationReasonOther).
+		Where("status = ? AND cancelled_at BETWEEN ? AND ?", models.OrderStatusCancelled, startDate, endDate).
+		Group("reason").
+		Order("count DESC").
+		Scan(&breakdown).Error
+	return breakdown, err
+}
+
+// bestSellersQuery ranks products by units sold on orders placed in the range,
+// excluding cancelled and refunded orders
+func (r *orderRepository) bestSellersQuery(ctx context.Context, startDate, endDate time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&models.OrderItem{}).
+		Select(`order_items.product_id,
+			(ARRAY_AGG(order_items.product_name ORDER BY order_items.id DESC))[1] AS product_name,
+			(ARRAY_AGG(order_items.product_sku ORDER BY order_items.id DESC))[1] AS product_sku,
+			SUM(order_items.quantity) AS units_sold,
+			COUNT(DISTINCT order_items.order_id) AS order_count,
+			SUM(order_items.total_price) AS revenue`).
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.created_at BETWEEN ? AND ? AND orders.status NOT IN ?",
+			startDate, endDate,
+			[]models.OrderStatus{models.OrderStatusCancelled, models.OrderStatusRefunded}).
+		Group("order_items.product_id").
+		Order("units_sold DESC, revenue DESC, order_items.product_id ASC")
+}
+
+func (r *orderRepository) GetBestSellers(ctx context.Context, startDate, endDate time.Time, limit int) ([]models.BestSeller, error) {
+	var bestSellers []models.BestSeller
+	err := r.bestSellersQuery(ctx, startDate, endDate).Limit(limit).Scan(&bestSellers).Error
+	return bestSellers, err
+}
+
+// EachBestSeller calls fn with every product sold in the range, best first,
+// reading rows from a cursor so the whole ranking is never held in memory
+func (r *orderRepository) EachBestSeller(ctx context.Context, startDate, endDate time.Time, fn func(*models.BestSeller) error) error {
+	query := r.bestSellersQuery(ctx, startDate, endDate)
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bestSeller models.BestSeller
+		if err := query.ScanRows(rows, &bestSeller); err != nil {
+			return err
+		}
+		if err := fn(&bestSeller); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// EachOrderExportLine calls fn with every item of the orders placed in the
+// range, grouped by order in the order they were placed, with what returns
+// refunded of each. Rows are read from a cursor so a long range is never held
+// in memory; the order-level shares are left for AllocateOrderExportLines.
+func (r *orderRepository) EachOrderExportLine(ctx context.Context, startDate, endDate time.Time, fn func(*models.OrderExportLine) error) error {
+	query := r.db.WithContext(ctx).
+		Model(&models.OrderItem{}).
+		Select(`orders.id AS order_id, orders.order_number, orders.created_at AS order_date, orders.status AS order_status,
+			orders.customer_id, TRIM(users.first_name || ' ' || users.last_name) AS customer_name, users.email AS customer_email,
+			order_items.product_id, order_items.product_name, order_items.product_sku AS sku,
+			order_items.quantity, order_items.unit_price,
+			order_items.total_price AS line_subtotal, order_items.discount_amount AS line_discount,
+			orders.subtotal_amount AS order_subtotal, orders.discount_amount AS order_discount,
+			orders.tax_amount AS order_tax, orders.shipping_amount AS order_shipping, orders.total_amount AS order_total,
+			orders.payment_status, orders.payment_method, orders.paid_at,
+			COALESCE(refunds.quantity, 0) AS refunded_quantity, COALESCE(refunds.amount, 0) AS refunded_amount`).
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = orders.customer_id").
+		Joins(`LEFT JOIN (
+			SELECT order_item_id, SUM(quantity) AS quantity, SUM(refund_amount) AS amount
+			FROM return_requests
+			WHERE status = ? AND deleted_at IS NULL
+			GROUP BY order_item_id
+		) refunds ON refunds.order_item_id = order_items.id`, models.ReturnStatusRefunded).
+		Where("orders.created_at BETWEEN ? AND ?", startDate, endDate).
+		Order("orders.created_at ASC, orders.id ASC, order_items.id ASC")
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.OrderExportLine
+		if err := query.ScanRows(rows, &line); err != nil {
+			return err
+		}
+		if err := fn(&line); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *orderRepository) UpdatePaymentStatus(ctx context.Context, id uint, status models.PaymentStatus) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Where("id = ?", id).
+		Update("payment_status", status).Error
+}
+
+func (r *orderRepository) UpdatePaymentID(ctx context.Context, id uint, paymentID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Where("id = ?", id).
+		Update("payment_id", paymentID).Error
+}
+
+func (r *orderRepository) GetByPaymentID(ctx context.Context, paymentID string) (*models.Order, error) {
+	var order models.Order
+	err := r.db.WithContext(ctx).
+		Where("payment_id = ?", paymentID).
+		First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *orderRepository) CountFailedPaymentsSince(ctx context.Context, customerID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Where("customer_id = ? AND payment_status = ? AND updated_at >= ?", customerID, models.PaymentStatusFailed, since).
+		Count(&count).Error
+	return count, err
+}
+
+// GetCustomerStats aggregates the customer's paid orders, leaving out cancelled
+// and refunded ones. MemberSince is left for the caller to fill in.
+func (r *orderRepository) GetCustomerStats(ctx context.Context, customerID uint) (*models.CustomerStats, error) {
+	spent := func() *gorm.DB {
+		return r.db.WithContext(ctx).
+			Model(&models.Order{}).
+			Where("orders.customer_id = ? AND orders.payment_status IN ? AND orders.status NOT IN ?",
+				customerID,
+				[]models.PaymentStatus{models.PaymentStatusPaid, models.PaymentStatusDisputed},
+				[]models.OrderStatus{models.OrderStatusCancelled, models.OrderStatusRefunded})
+	}
+
+	var totals struct {
+		TotalSpent float64
+		OrderCount int64
+	}
+	if err := spent().
+		Select("COALESCE(SUM(orders.total_amount), 0) AS total_spent, COUNT(*) AS order_count").
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	stats := &models.CustomerStats{
+		TotalSpent: totals.TotalSpent,
+		OrderCount: totals.OrderCount,
+	}
+	if totals.OrderCount == 0 {
+		return stats, nil
+	}
+
+	var categories []string
+	if err := spent().
+		Joins("JOIN order_items ON order_items.order_id = orders.id AND order_items.deleted_at IS NULL").
+		Joins("JOIN products ON products.id = order_items.product_id").
+		Where("products.category <> ''").
+		Group("products.category").
+		Order("SUM(order_items.quantity) DESC, products.category ASC").
+		Limit(1).
+		Pluck("products.category", &categories).Error; err != nil {
+		return nil, err
+	}
+	if len(categories) > 0 {
+		stats.FavoriteCategory = &categories[0]
+	}
+
+	return stats, nil
+}
+
+// SumCustomerProductQuantity totals the units of the product the customer has
+// ordered since the given time (or ever, when nil). Cancelled and refunded
+// orders don't count; pending ones do, so unpaid orders can't be stacked.
+func (r *orderRepository) SumCustomerProductQuantity(ctx context.Context, customerID, productID uint, since *time.Time) (int, error) {
+	var total int
+	query := r.db.WithContext(ctx).
+		Model(&models.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.customer_id = ? AND order_items.product_id = ? AND orders.status NOT IN ?",
+			customerID, productID,
+			[]models.OrderStatus{models.OrderStatusCancelled, models.OrderStatusRefunded}).
+		Select("COALESCE(SUM(order_items.quantity), 0)")
+
+	if since != nil {
+		query = query.Where("orders.created_at >= ?", *since)
+	}
+
+	err := query.Scan(&total).Error
+	return total, err
+}
+
+// orderStatusSinceSQL is when an order entered its current status: the latest
+// matching history entry, falling back to the status timestamp or creation time
+const orderStatusSinceSQL = `COALESCE(
+	(SELECT MAX(h.created_at) FROM order_status_histories h
+	 WHERE h.order_id = orders.id AND h.to_status = orders.status AND h.deleted_at IS NULL),
+	CASE orders.status WHEN 'confirmed' THEN orders.paid_at WHEN 'shipped' THEN orders.shipped_at END,
+	orders.created_at)`
+
+// GetStuckOrders returns orders that have been in their status longer than the
+// status's threshold, longest waiting first. With unalertedOnly set, orders
+// already alerted since entering their current status are skipped.
+func (r *orderRepository) GetStuckOrders(ctx context.Context, thresholds map[models.OrderStatus]time.Duration, now time.Time, unalertedOnly bool, limit, offset int) ([]models.StuckOrder, error) {
+	var conditions []string
+	var args []interface{}
+	for status, threshold := range thresholds {
+		if threshold <= 0 {
+			continue
+		}
+		conditions = append(conditions, "(orders.status = ? AND "+orderStatusSinceSQL+" < ?)")
+		args = append(args, status, now.Add(-threshold))
+	}
+	if len(conditions) == 0 {
+		return []models.StuckOrder{}, nil
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Select("orders.id, " + orderStatusSinceSQL + " AS status_since").
+		Where("("+strings.Join(conditions, " OR ")+")", args...)
+	if unalertedOnly {
+		query = query.Where("(orders.sla_alerted_at IS NULL OR orders.sla_alerted_at < " + orderStatusSinceSQL + ")")
+	}
+
+	var rows []struct {
+		ID          uint
+		StatusSince time.Time
+	}
+	if err := query.Order("status_since ASC").Limit(limit).Offset(offset).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []models.StuckOrder{}, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	var orders []*models.Order
+	err := r.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Preload("Customer").
+		Preload("OrderItems").
+		Preload("Fulfillments").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*models.Order, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+	}
+
+	stuck := make([]models.StuckOrder, 0, len(rows))
+	for _, row := range rows {
+		order, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		stuck = append(stuck, models.StuckOrder{
+			Order:       order,
+			Status:      order.Status,
+			StatusSince: row.StatusSince,
+		})
+	}
+	return stuck, nil
+}
+
+// applyArchivedFilter leaves archived orders out of a query unless they're included
+func applyArchivedFilter(query *gorm.DB, includeArchived bool) *gorm.DB {
+	if includeArchived {
+		return query
+	}
+	return query.Where("orders.archived_at IS NULL")
+}
+
+// sellerAssigneeColumn is the staff member the seller's portion of an order is
+// assigned to: the seller's fulfillment group's on split orders, else the order's
+const sellerAssigneeColumn = `COALESCE((
+	SELECT order_fulfillments.assigned_to FROM order_fulfillments
+	WHERE order_fulfillments.order_id = orders.id
+	AND order_fulfillments.seller_id = ?
+	AND order_fulfillments.deleted_at IS NULL), orders.assigned_to)`
+
+func applyAssigneeFilter(query *gorm.DB, sellerID uint, filter *models.SellerOrderFilter) *gorm.DB {
+	if filter.Unassigned {
+		return query.Where(sellerAssigneeColumn+" IS NULL", sellerID)
+	}
+	if filter.AssignedTo != nil {
+		return query.Where(sellerAssigneeColumn+" = ?", sellerID, *filter.AssignedTo)
+	}
+	return query
+}
+
+// Archive marks up to limit finished orders created before the cutoff as
+// archived, oldest first, and returns how many it marked. Only delivered,
+// cancelled and refunded orders are archived; anything still in progress stays
+// live however old it is.
+func (r *orderRepository) Archive(ctx context.Context, createdBefore time.Time, limit int) (int64, error) {
+	due := r.db.
+		Model(&models.Order{}).
+		Select("id").
+		Where("archived_at IS NULL AND created_at < ? AND status IN ?", createdBefore, []models.OrderStatus{
+			models.OrderStatusDelivered,
+			models.OrderStatusCancelled,
+			models.OrderStatusRefunded,
+		}).
+		Order("created_at ASC").
+		Limit(limit)
+
+	result := r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Where("id IN (?)", due).
+		Update("archived_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// GetDueReviewReminders returns delivered orders, oldest first, that were
+// delivered before the cutoff and haven't had a review reminder yet
+func (r *orderRepository) GetDueReviewReminders(ctx context.Context, deliveredBefore time.Time, limit int) ([]*models.Order, error) {
+	var orders []*models.Order
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND delivered_at <= ? AND review_reminder_sent_at IS NULL", models.OrderStatusDelivered, deliveredBefore).
+		Preload("Customer").
+		Preload("OrderItems.Product").
+		Order("delivered_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) MarkReviewRemindersSent(ctx context.Context, ids []uint, sentAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Where("id IN ?", ids).
+		Update("review_reminder_sent_at", sentAt).Error
+}
+
+func (r *orderRepository) MarkSLAAlerted(ctx context.Context, ids []uint, alertedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Where("id IN ?", ids).
+		Update("sla_alerted_at", alertedAt).Error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+func TestCancelPendingCancelsPendingOrder(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewOrderRepository(db)
+
+	user := createTestUser(t, db, "cancel-pending@example.com")
+	order := createTestOrder(t, db, user.ID, "ORD-CANCEL-1", models.OrderStatusPending)
+
+	cancelled, err := repo.CancelPending(ctx, order.ID, models.CancellationReasonExpired, nil)
+	if err != nil {
+		t.Fatalf("CancelPending: %v", err)
+	}
+	if !cancelled {
+		t.Fatal("expected the pending order to be cancelled")
+	}
+
+	got, err := repo.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != models.OrderStatusCancelled {
+		t.Errorf("status = %s, want %s", got.Status, models.OrderStatusCancelled)
+	}
+}
+
+// The sweeper lists expired orders before cancelling them; an order paid in
+// between must not be cancelled
+func TestCancelPendingLeavesPaidOrder(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewOrderRepository(db)
+
+	user := createTestUser(t, db, "cancel-paid@example.com")
+	order := createTestOrder(t, db, user.ID, "ORD-CANCEL-2", models.OrderStatusPending)
+	if err := repo.UpdateStatus(ctx, order.ID, models.OrderStatusConfirmed); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	cancelled, err := repo.CancelPending(ctx, order.ID, models.CancellationReasonExpired, nil)
+	if err != nil {
+		t.Fatalf("CancelPending: %v", err)
+	}
+	if cancelled {
+		t.Error("expected the confirmed order not to be cancelled")
+	}
+
+	got, err := repo.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != models.OrderStatusConfirmed {
+		t.Errorf("status = %s, want %s", got.Status, models.OrderStatusConfirmed)
+	}
+}
+
+func TestOrderStatusColumnsFitAwaitingConfirmation(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewOrderRepository(db)
+
+	user := createTestUser(t, db, "awaiting@example.com")
+	order := createTestOrder(t, db, user.ID, "ORD-AWAIT-1", models.OrderStatusAwaitingConfirmation)
+
+	history := &models.OrderStatusHistory{
+		OrderID:    order.ID,
+		FromStatus: models.OrderStatusAwaitingConfirmation,
+		ToStatus:   models.OrderStatusAwaitingConfirmation,
+	}
+	if err := repo.AddStatusHistory(ctx, history); err != nil {
+		t.Fatalf("AddStatusHistory: %v", err)
+	}
+}
+
+func TestEachOrderExportLineIncludesRefundedReturns(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewOrderRepository(db)
+
+	seller := createTestUser(t, db, "export-seller@example.com")
+	customer := createTestUser(t, db, "export-customer@example.com")
+	product := createTestProduct(t, db, seller.ID, "EXPORT-1", 5)
+	order := createTestOrder(t, db, customer.ID, "ORD-EXPORT-1", models.OrderStatusDelivered)
+	item := &models.OrderItem{OrderID: order.ID, ProductID: product.ID, SellerID: seller.ID, Quantity: 2, UnitPrice: 5, TotalPrice: 10, ProductName: product.Name, ProductSKU: product.SKU}
+	if err := db.Create(item).Error; err != nil {
+		t.Fatalf("failed to create order item: %v", err)
+	}
+	returned := &models.ReturnRequest{OrderID: order.ID, OrderItemID: item.ID, CustomerID: customer.ID, Quantity: 1, Reason: "Too small", Status: models.ReturnStatusRefunded, RefundAmount: 5}
+	if err := db.Create(returned).Error; err != nil {
+		t.Fatalf("failed to create return request: %v", err)
+	}
+
+	var lines []models.OrderExportLine
+	err := repo.EachOrderExportLine(ctx, order.CreatedAt.Add(-time.Hour), order.CreatedAt.Add(time.Hour), func(line *models.OrderExportLine) error {
+		lines = append(lines, *line)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachOrderExportLine: %v", err)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1", len(lines))
+	}
+	line := lines[0]
+	if line.OrderNumber != "ORD-EXPORT-1" || line.SKU != "EXPORT-1" || line.CustomerEmail != customer.Email || line.LineSubtotal != 10 {
+		t.Errorf("line = %+v, want the order's item", line)
+	}
+	if line.RefundedQuantity != 1 || line.RefundedAmount != 5 {
+		t.Errorf("refunded %d for %.2f, want 1 for 5.00", line.RefundedQuantity, line.RefundedAmount)
+	}
+}
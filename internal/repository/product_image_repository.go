@@ -6,6 +6,7 @@ import (
 
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productImageRepository struct {
@@ -16,11 +17,33 @@ func NewProductImageRepository(db *gorm.DB) ProductImageRepository {
 	return &productImageRepository{db: db}
 }
 
+// Create adds an image. A primary image replaces the product's current one in the
+// same transaction, and a product's first image becomes primary.
 func (r *productImageRepository) Create(ctx context.Context, productImage *models.ProductImage) error {
-	if err := r.db.WithContext(ctx).Create(productImage).Error; err != nil {
-		return err
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProductImages(tx, productImage.ProductID); err != nil {
+			return err
+		}
+
+		if productImage.IsPrimary {
+			if err := clearPrimary(tx, productImage.ProductID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(productImage).Error; err != nil {
+			return err
+		}
+
+		promotedID, err := ensurePrimary(tx, productImage.ProductID)
+		if err != nil {
+			return err
+		}
+		if promotedID == productImage.ID {
+			productImage.IsPrimary = true
+		}
+		return nil
+	})
 }
 
 func (r *productImageRepository) GetByProductID(ctx context.Context, productID uint) ([]models.ProductImage, error) {
@@ -37,7 +60,7 @@ func (r *productImageRepository) GetByID(ctx context.Context, id uint) (*models.
 	err := r.db.WithContext(ctx).First(&image, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product image not found")
+			return nil, ErrProductImageNotFound
 		}
 		return nil, err
 	}
@@ -45,11 +68,67 @@ func (r *productImageRepository) GetByID(ctx context.Context, id uint) (*models.
 }
 
 func (r *productImageRepository) Update(ctx context.Context, productImage *models.ProductImage) error {
-	return r.db.WithContext(ctx).Save(productImage).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProductImages(tx, productImage.ProductID); err != nil {
+			return err
+		}
+
+		if productImage.IsPrimary {
+			if err := tx.Model(&models.ProductImage{}).
+				Where("product_id = ? AND id <> ? AND is_primary = ?", productImage.ProductID, productImage.ID, true).
+				Update("is_primary", false).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(productImage).Error; err != nil {
+			return err
+		}
+
+		promotedID, err := ensurePrimary(tx, productImage.ProductID)
+		if err != nil {
+			return err
+		}
+		if promotedID == productImage.ID {
+			productImage.IsPrimary = true
+		}
+		return nil
+	})
 }
 
+// Delete removes an image, promoting another one if it was the primary
 func (r *productImageRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.ProductImage{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var image models.ProductImage
+		if err := tx.First(&image, id).Error; err != nil {
+			return err
+		}
+
+		if err := lockProductImages(tx, image.ProductID); err != nil {
+			return err
+		}
+
+		// Clear the flag first so the soft-deleted row doesn't keep holding it
+		if err := tx.Model(&image).Update("is_primary", false).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&image).Error; err != nil {
+			return err
+		}
+
+		_, err := ensurePrimary(tx, image.ProductID)
+		return err
+	})
+}
+
+func (r *productImageRepository) CountByProductID(ctx context.Context, productID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.ProductImage{}).
+		Where("product_id = ?", productID).
+		Count(&count).Error
+	return count, err
 }
 
 func (r *productImageRepository) DeleteByProductID(ctx context.Context, productID uint) error {
@@ -58,33 +137,42 @@ func (r *productImageRepository) DeleteByProductID(ctx context.Context, productI
 
 func (r *productImageRepository) SetPrimary(ctx context.Context, productID uint, imageID uint) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProductImages(tx, productID); err != nil {
+			return err
+		}
+
 		// First, unset all primary images for this product
-		if err := tx.Model(&models.ProductImage{}).
-			Where("product_id = ?", productID).
-			Update("is_primary", false).Error; err != nil {
+		if err := clearPrimary(tx, productID); err != nil {
 			return err
 		}
 
 		// Then set the specified image as primary
-		if err := tx.Model(&models.ProductImage{}).
+		result := tx.Model(&models.ProductImage{}).
 			Where("id = ? AND product_id = ?", imageID, productID).
-			Update("is_primary", true).Error; err != nil {
-			return err
+			Update("is_primary", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrProductImageNotFound
 		}
 
 		return nil
 	})
 }
 
+// GetPrimaryImage returns the flagged primary image. Rows written before the
+// one-primary invariant may have none flagged, so fall back to the first image.
 func (r *productImageRepository) GetPrimaryImage(ctx context.Context, productID uint) (*models.ProductImage, error) {
 	var image models.ProductImage
 	err := r.db.WithContext(ctx).
-		Where("product_id = ? AND is_primary = ?", productID, true).
+		Where("product_id = ?", productID).
+		Order("is_primary DESC, sort_order ASC, created_at ASC").
 		First(&image).Error
 	
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("primary image not found")
+			return nil, ErrPrimaryImageNotFound
 		}
 		return nil, err
 	}
@@ -97,9 +185,142 @@ func (r *productImageRepository) UpdateSortOrder(ctx context.Context, productID
 		Update("sort_order", sortOrder).Error
 }
 
+// BulkCreate adds images for one product. At most one of them may be primary;
+// it replaces the product's current primary image.
 func (r *productImageRepository) BulkCreate(ctx context.Context, productImages []models.ProductImage) error {
 	if len(productImages) == 0 {
 		return nil
 	}
-	return r.db.WithContext(ctx).CreateInBatches(productImages, 100).Error
+
+	productID := productImages[0].ProductID
+	hasPrimary := false
+	for _, image := range productImages {
+		if image.ProductID != productID {
+			return ErrImagesMixedProducts
+		}
+		if image.IsPrimary {
+			if hasPrimary {
+				return ErrMultiplePrimaryImages
+			}
+			hasPrimary = true
+		}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProductImages(tx, productID); err != nil {
+			return err
+		}
+
+		if hasPrimary {
+			if err := clearPrimary(tx, productID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.CreateInBatches(productImages, 100).Error; err != nil {
+			return err
+		}
+
+		promotedID, err := ensurePrimary(tx, productID)
+		if err != nil {
+			return err
+		}
+		for i := range productImages {
+			if productImages[i].ID == promotedID {
+				productImages[i].IsPrimary = true
+			}
+		}
+		return nil
+	})
+}
+
+// lockProductImages serializes primary image changes for a product by locking
+// its row, so concurrent writers can't both clear and then both set a primary.
+// The partial unique index on product_images is the backstop.
+func lockProductImages(tx *gorm.DB, productID uint) error {
+	var product models.Product
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&product, productID).Error
+}
+
+func clearPrimary(tx *gorm.DB, productID uint) error {
+	return tx.Model(&models.ProductImage{}).
+		Where("product_id = ? AND is_primary = ?", productID, true).
+		Update("is_primary", false).Error
+}
+
+// ensurePrimary promotes the first image by sort order when the product has
+// images but none of them is primary. It returns the promoted image's ID, or 0.
+func ensurePrimary(tx *gorm.DB, productID uint) (uint, error) {
+	var count int64
+	if err := tx.Model(&models.ProductImage{}).
+		Where("product_id = ? AND is_primary = ?", productID, true).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		return 0, nil
+	}
+
+	var first models.ProductImage
+	err := tx.Where("product_id = ?", productID).
+		Order("sort_order ASC, created_at ASC").
+		First(&first).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if err := tx.Model(&first).Update("is_primary", true).Error; err != nil {
+		return 0, err
+	}
+	return first.ID, nil
+}
+
+// GetAccessibilityGaps returns a seller's products that have no images, or
+// images whose alt text is blank, generated or a placeholder (see
+// models.ProductImage.AltTextIssue), ordered by product ID
+func (r *productImageRepository) GetAccessibilityGaps(ctx context.Context, sellerID uint, limit, offset int) ([]models.ProductAccessibility, int64, error) {
+	images := r.db.
+		Table("product_images").
+		Select(`product_id,
+			COUNT(*) AS image_count,
+			COUNT(*) FILTER (WHERE TRIM(COALESCE(alt_text, '')) = '') AS missing_alt_text_count,
+			COUNT(*) FILTER (WHERE TRIM(COALESCE(alt_text, '')) <> '' AND alt_text_generated) AS generated_alt_text_count,
+			COUNT(*) FILTER (WHERE TRIM(COALESCE(alt_text, '')) <> '' AND NOT alt_text_generated
+				AND (LOWER(TRIM(alt_text)) IN ? OR TRIM(alt_text) ~* ?)) AS placeholder_alt_text_count`,
+			models.GenericAltTexts, models.AltTextFileNamePattern).
+		Where("deleted_at IS NULL").
+		Group("product_id")
+
+	query := r.db.WithContext(ctx).
+		Table("products").
+		Joins("LEFT JOIN (?) AS images ON images.product_id = products.id", images).
+		Where("products.seller_id = ? AND products.deleted_at IS NULL", sellerID).
+		Where(`images.product_id IS NULL
+			OR images.missing_alt_text_count > 0
+			OR images.generated_alt_text_count > 0
+			OR images.placeholder_alt_text_count > 0`)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []models.ProductAccessibility
+	err := query.
+		Select(`products.id AS product_id, products.name, products.sku,
+			COALESCE(images.image_count, 0) AS image_count,
+			COALESCE(images.missing_alt_text_count, 0) AS missing_alt_text_count,
+			COALESCE(images.generated_alt_text_count, 0) AS generated_alt_text_count,
+			COALESCE(images.placeholder_alt_text_count, 0) AS placeholder_alt_text_count,
+			images.product_id IS NULL AS no_images`).
+		Order("products.id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&products).Error
+	return products, total, err
 }
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// primaryImageIDs returns the IDs of the product's live images flagged primary
+func primaryImageIDs(t *testing.T, db *gorm.DB, productID uint) []uint {
+	t.Helper()
+	var ids []uint
+	if err := db.Model(&models.ProductImage{}).
+		Where("product_id = ? AND is_primary = ?", productID, true).
+		Pluck("id", &ids).Error; err != nil {
+		t.Fatalf("failed to load primary images: %v", err)
+	}
+	return ids
+}
+
+func TestConcurrentPrimaryImagesLeaveExactlyOnePrimary(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewProductImageRepository(db)
+
+	seller := createTestUser(t, db, "images-seller@example.com")
+	product := createTestProduct(t, db, seller.ID, "IMG-RACE", 1)
+
+	const uploads = 5
+	var wg sync.WaitGroup
+	errs := make([]error, uploads)
+	start := make(chan struct{})
+	for i := 0; i < uploads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = repo.Create(ctx, &models.ProductImage{
+				ProductID: product.ID,
+				URL:       fmt.Sprintf("https://example.com/%d.jpg", i),
+				IsPrimary: true,
+			})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("upload %d: %v", i, err)
+		}
+	}
+	if ids := primaryImageIDs(t, db, product.ID); len(ids) != 1 {
+		t.Errorf("product has %d primary images, want 1", len(ids))
+	}
+}
+
+func TestConcurrentSetPrimaryLeavesExactlyOnePrimary(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewProductImageRepository(db)
+
+	seller := createTestUser(t, db, "set-primary-seller@example.com")
+	product := createTestProduct(t, db, seller.ID, "IMG-SET", 1)
+
+	images := make([]*models.ProductImage, 4)
+	for i := range images {
+		images[i] = &models.ProductImage{ProductID: product.ID, URL: fmt.Sprintf("https://example.com/set-%d.jpg", i)}
+		if err := repo.Create(ctx, images[i]); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for _, image := range images {
+		wg.Add(1)
+		go func(imageID uint) {
+			defer wg.Done()
+			<-start
+			if err := repo.SetPrimary(ctx, product.ID, imageID); err != nil {
+				t.Errorf("SetPrimary(%d): %v", imageID, err)
+			}
+		}(image.ID)
+	}
+	close(start)
+	wg.Wait()
+
+	if ids := primaryImageIDs(t, db, product.ID); len(ids) != 1 {
+		t.Errorf("product has %d primary images, want 1", len(ids))
+	}
+}
+
+func TestDeletingPrimaryImagePromotesAnother(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewProductImageRepository(db)
+
+	seller := createTestUser(t, db, "delete-primary-seller@example.com")
+	product := createTestProduct(t, db, seller.ID, "IMG-DEL", 1)
+
+	first := &models.ProductImage{ProductID: product.ID, URL: "https://example.com/first.jpg"}
+	second := &models.ProductImage{ProductID: product.ID, URL: "https://example.com/second.jpg"}
+	for _, image := range []*models.ProductImage{first, second} {
+		if err := repo.Create(ctx, image); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if !first.IsPrimary || second.IsPrimary {
+		t.Fatalf("expected only the first image to become primary")
+	}
+
+	if err := repo.Delete(ctx, first.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	ids := primaryImageIDs(t, db, product.ID)
+	if len(ids) != 1 || ids[0] != second.ID {
+		t.Errorf("primary images = %v, want [%d]", ids, second.ID)
+	}
+}
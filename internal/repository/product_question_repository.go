@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type productQuestionRepository struct {
+	db *gorm.DB
+}
+
+type ProductQuestionRepository interface {
+	CreateQuestion(ctx context.Context, question *models.ProductQuestion) error
+	GetQuestionByID(ctx context.Context, id uint) (*models.ProductQuestion, error)
+	GetQuestionsByProductID(ctx context.Context, productID uint, limit, offset int) ([]*models.ProductQuestion, int64, error)
+	CreateAnswer(ctx context.Context, answer *models.ProductAnswer) error
+}
+
+func NewProductQuestionRepository(db *gorm.DB) ProductQuestionRepository {
+	return &productQuestionRepository{db: db}
+}
+
+func (r *productQuestionRepository) CreateQuestion(ctx context.Context, question *models.ProductQuestion) error {
+	return r.db.WithContext(ctx).Create(question).Error
+}
+
+func (r *productQuestionRepository) GetQuestionByID(ctx context.Context, id uint) (*models.ProductQuestion, error) {
+	var question models.ProductQuestion
+	err := r.db.WithContext(ctx).
+		Preload("Product").
+		Preload("User").
+		Preload("Answers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Answers.User").
+		First(&question, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// GetQuestionsByProductID returns a product's questions, newest first, with their answers
+func (r *productQuestionRepository) GetQuestionsByProductID(ctx context.Context, productID uint, limit, offset int) ([]*models.ProductQuestion, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.ProductQuestion{}).Where("product_id = ?", productID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var questions []*models.ProductQuestion
+	err := query.
+		Preload("User").
+		Preload("Answers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Answers.User").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&questions).Error
+	return questions, total, err
+}
+
+func (r *productQuestionRepository) CreateAnswer(ctx context.Context, answer *models.ProductAnswer) error {
+	return r.db.WithContext(ctx).Create(answer).Error
+}
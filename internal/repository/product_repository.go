@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -16,7 +21,12 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 }
 
 func (r *productRepository) Create(ctx context.Context, product *models.Product) error {
-	return r.db.WithContext(ctx).Create(product).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		return syncProductTags(tx, product)
+	})
 }
 
 func (r *productRepository) GetByID(ctx context.Context, id uint) (*models.Product, error) {
@@ -31,6 +41,61 @@ func (r *productRepository) GetByID(ctx context.Context, id uint) (*models.Produ
 	return &product, nil
 }
 
+// GetByIDs returns the products with the given IDs in no particular order;
+// unknown and deleted IDs are skipped
+func (r *productRepository) GetByIDs(ctx context.Context, ids []uint) ([]*models.Product, error) {
+	var products []*models.Product
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) GetBySlug(ctx context.Context, slug string) (*models.Product, error) {
+	var product models.Product
+	err := r.db.WithContext(ctx).
+		Preload("Reviews").
+		Preload("Reviews.User").
+		Where("slug = ? AND status <> ?", slug, models.ProductStatusDeleted).
+		First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// SlugExists reports whether a live product has the slug; deleted products'
+// slugs can be reused
+func (r *productRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("slug = ?", slug).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SKUExists reports whether a live product has the SKU; deleted products'
+// SKUs can be reused
+func (r *productRepository) SKUExists(ctx context.Context, sku string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("sku = ?", sku).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *productRepository) IncrementViewCount(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("id = ?", id).
+		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+}
+
 func (r *productRepository) GetAll(ctx context.Context, limit, offset int) ([]*models.Product, error) {
 	var products []*models.Product
 	err := r.db.WithContext(ctx).
@@ -41,11 +106,12 @@ func (r *productRepository) GetAll(ctx context.Context, limit, offset int) ([]*m
 	return products, err
 }
 
-func (r *productRepository) GetByCategory(ctx context.Context, category string, limit, offset int) ([]*models.Product, error) {
+func (r *productRepository) GetByCategory(ctx context.Context, category string, sortBy models.ProductSort, limit, offset int) ([]*models.Product, error) {
 	var products []*models.Product
 	err := r.db.WithContext(ctx).
-		Where("category = ?", category).
+		Where("category = ? AND is_active = ?", category, true).
 		Preload("Reviews").
+		Order(productSortOrder(sortBy)).
 		Limit(limit).
 		Offset(offset).
 		Find(&products).Error
@@ -69,7 +135,7 @@ func (r *productRepository) Search(ctx context.Context, query string, limit, off
 	// Use parameterized queries to prevent SQL injection
 	// GORM automatically handles the parameterization when using ? placeholders
 	err := r.db.WithContext(ctx).
-		Where("name ILIKE ? OR description ILIKE ?", "%"+query+"%", "%"+query+"%").
+		Where("(name ILIKE ? OR description ILIKE ?) AND is_active = ?", "%"+query+"%", "%"+query+"%", true).
 		Preload("Reviews").
 		Limit(limit).
 		Offset(offset).
@@ -78,11 +144,82 @@ func (r *productRepository) Search(ctx context.Context, query string, limit, off
 }
 
 func (r *productRepository) Update(ctx context.Context, product *models.Product) error {
-	return r.db.WithContext(ctx).Save(product).Error
+	return r.UpdateWithPriceChange(ctx, product, nil)
 }
 
+// UpdateWithPriceChange saves the product and, when change is set, records it
+// in the price history in the same transaction
+func (r *productRepository) UpdateWithPriceChange(ctx context.Context, product *models.Product, change *models.PriceHistory) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(product).Error; err != nil {
+			return err
+		}
+		if change != nil {
+			if err := tx.Create(change).Error; err != nil {
+				return err
+			}
+		}
+		return syncProductTags(tx, product)
+	})
+}
+
+// syncProductTags upserts the product's tags and rewrites its product_tags
+// links from the Tags column
+func syncProductTags(tx *gorm.DB, product *models.Product) error {
+	if err := tx.Where("product_id = ?", product.ID).Delete(&models.ProductTag{}).Error; err != nil {
+		return err
+	}
+
+	names := models.NormalizeTags(product.GetTagsList())
+	if len(names) == 0 {
+		return nil
+	}
+
+	tags := make([]models.Tag, len(names))
+	for i, name := range names {
+		tags[i] = models.Tag{Name: name}
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return err
+	}
+
+	// Rows that already existed come back without an ID, so look them all up
+	var existing []models.Tag
+	if err := tx.Where("name IN ?", names).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	links := make([]models.ProductTag, len(existing))
+	for i, tag := range existing {
+		links[i] = models.ProductTag{ProductID: product.ID, TagID: tag.ID}
+	}
+	return tx.Create(&links).Error
+}
+
+// Delete soft-deletes the product. updated_at moves with deleted_at so the
+// deleted row shows up in the change feed as a tombstone.
 func (r *productRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Product{}, id).Error
+	now := time.Now()
+	return r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"deleted_at": now,
+			"updated_at": now,
+		}).Error
+}
+
+// GetChangedSince returns products, deleted ones included, changed after the
+// cursor position, ordered by (updated_at, id) to walk idx_products_updated_at_id
+func (r *productRepository) GetChangedSince(ctx context.Context, cursor models.ProductChangeCursor, limit int) ([]*models.Product, error) {
+	var products []*models.Product
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("(updated_at, id) > (?, ?)", cursor.UpdatedAt, cursor.ID).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
 }
 
 func (r *productRepository) UpdateStock(ctx context.Context, id uint, stock int) error {
@@ -92,6 +229,36 @@ func (r *productRepository) UpdateStock(ctx context.Context, id uint, stock int)
 		Update("stock", stock).Error
 }
 
+// AdjustStock atomically adds delta (negative to decrement) to a product's stock
+func (r *productRepository) AdjustStock(ctx context.Context, id uint, delta int) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("id = ?", id).
+		Update("stock", gorm.Expr("stock + ?", delta)).Error
+}
+
+// DecrementStock atomically takes quantity from a product's stock unless that
+// would take it further below zero than the product's backorder limit or
+// oversell tolerance, mirroring Product.CanFulfill. It returns the stock left
+// and whether the decrement happened.
+func (r *productRepository) DecrementStock(ctx context.Context, id uint, quantity int) (int, bool, error) {
+	var remaining []int
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE products SET stock = stock - ?, updated_at = NOW()
+		WHERE id = ? AND deleted_at IS NULL
+		  AND stock - ? >= -GREATEST(oversell_tolerance, CASE WHEN allow_backorders THEN max_backorder_quantity ELSE 0 END)
+		RETURNING stock`,
+		quantity, id, quantity).
+		Scan(&remaining).Error
+	if err != nil {
+		return 0, false, err
+	}
+	if len(remaining) == 0 {
+		return 0, false, nil
+	}
+	return remaining[0], true, nil
+}
+
 func (r *productRepository) GetLowStock(ctx context.Context, threshold int) ([]*models.Product, error) {
 	var products []*models.Product
 	err := r.db.WithContext(ctx).
@@ -115,9 +282,18 @@ func (r *productRepository) CountByCategory(ctx context.Context, category string
 	return count, err
 }
 
-func (r *productRepository) GetTopRated(ctx context.Context, limit int) ([]*models.Product, error) {
-	var products []*models.Product
+func (r *productRepository) CountBySellerID(ctx context.Context, sellerID uint) (int64, error) {
+	var count int64
 	err := r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("seller_id = ?", sellerID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *productRepository) GetTopRated(ctx context.Context, limit int, gate models.RatingGate) ([]*models.Product, error) {
+	var products []*models.Product
+	err := applyRatingGate(r.db.WithContext(ctx), gate).
 		Preload("Reviews").
 		Order("average_rating DESC").
 		Limit(limit).
@@ -125,6 +301,85 @@ func (r *productRepository) GetTopRated(ctx context.Context, limit int) ([]*mode
 	return products, err
 }
 
+// GetFeatured returns active featured products that pass the rating gate, best rated first
+func (r *productRepository) GetFeatured(ctx context.Context, limit int, gate models.RatingGate) ([]*models.Product, error) {
+	var products []*models.Product
+	err := applyAvailabilityWindow(applyRatingGate(r.db.WithContext(ctx), gate), time.Now()).
+		Where("featured = ? AND is_active = ?", true, true).
+		Order("average_rating DESC, created_at DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// GetRelated returns other active products in the same category that pass the
+// rating gate, best rated first
+func (r *productRepository) GetRelated(ctx context.Context, product *models.Product, limit int, gate models.RatingGate) ([]*models.Product, error) {
+	var products []*models.Product
+	query := applyAvailabilityWindow(applyRatingGate(r.db.WithContext(ctx), gate), time.Now()).
+		Where("id <> ? AND is_active = ?", product.ID, true)
+
+	if product.CategoryID != nil {
+		query = query.Where("category_id = ?", *product.CategoryID)
+	} else {
+		query = query.Where("category = ?", product.Category)
+	}
+
+	err := query.
+		Order("average_rating DESC, review_count DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// GetNewArrivals returns active, visible, published products created since the
+// given time, newest first, optionally in one category
+func (r *productRepository) GetNewArrivals(ctx context.Context, since time.Time, category string, includeOutOfStock bool, limit int) ([]*models.Product, error) {
+	var products []*models.Product
+	query := applyAvailabilityWindow(r.db.WithContext(ctx), time.Now()).
+		Where("is_active = ? AND visible = ? AND status = ? AND created_at >= ?", true, true, models.ProductStatusActive, since)
+
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if !includeOutOfStock {
+		query = query.Where("stock > 0")
+	}
+
+	err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// AddPurchaseLimitExemption saves the exemption, replacing the note of an existing one
+func (r *productRepository) AddPurchaseLimitExemption(ctx context.Context, exemption *models.PurchaseLimitExemption) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"note", "created_by", "updated_at"}),
+		}).
+		Create(exemption).Error
+}
+
+// RemovePurchaseLimitExemption deletes the exemption outright so it can be granted again later
+func (r *productRepository) RemovePurchaseLimitExemption(ctx context.Context, productID, userID uint) error {
+	return r.db.WithContext(ctx).
+		Unscoped().
+		Where("product_id = ? AND user_id = ?", productID, userID).
+		Delete(&models.PurchaseLimitExemption{}).Error
+}
+
+func (r *productRepository) HasPurchaseLimitExemption(ctx context.Context, productID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.PurchaseLimitExemption{}).
+		Where("product_id = ? AND user_id = ?", productID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (r *productRepository) UpdateRating(ctx context.Context, productID uint, averageRating float64, reviewCount int) error {
 	return r.db.WithContext(ctx).
 		Model(&models.Product{}).
@@ -134,3 +389,707 @@ func (r *productRepository) UpdateRating(ctx context.Context, productID uint, av
 			"review_count":   reviewCount,
 		}).Error
 }
+
+func (r *productRepository) SuggestNames(ctx context.Context, prefix string, limit int) ([]string, error) {
+	var names []string
+	// Matches idx_products_name_prefix (LOWER(name) text_pattern_ops)
+	err := r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("is_active = ? AND LOWER(name) LIKE ?", true, escapeLike(strings.ToLower(prefix))+"%").
+		Group("name").
+		Order("MAX(view_count) DESC, name ASC").
+		Limit(limit).
+		Pluck("name", &names).Error
+	return names, err
+}
+
+// BulkSetVisibility shows or hides each product in one transaction, optionally
+// changing its status too. Each product runs under its own savepoint so a
+// failure rolls back only that item; every change is recorded in the audit
+// log. A non-nil sellerID limits changes to that seller's products.
+func (r *productRepository) BulkSetVisibility(ctx context.Context, productIDs []uint, visible bool, status *models.ProductStatus, sellerID *uint, actorID uint) ([]models.VisibilityItemResult, error) {
+	results := make([]models.VisibilityItemResult, 0, len(productIDs))
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range productIDs {
+			savepoint := fmt.Sprintf("visibility_%d", i)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return err
+			}
+
+			if err := setProductVisibility(tx, id, visible, status, sellerID, actorID); err != nil {
+				if rbErr := tx.RollbackTo(savepoint).Error; rbErr != nil {
+					return rbErr
+				}
+				results = append(results, models.VisibilityItemResult{ProductID: id, Error: err.Error()})
+				continue
+			}
+
+			results = append(results, models.VisibilityItemResult{ProductID: id, Success: true})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// setProductVisibility applies a single visibility change and writes its audit entry
+func setProductVisibility(tx *gorm.DB, id uint, visible bool, status *models.ProductStatus, sellerID *uint, actorID uint) error {
+	var product models.Product
+	if err := tx.Select("id", "seller_id", "status", "rejection_reason").First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("product not found")
+		}
+		return err
+	}
+	if sellerID != nil && product.SellerID != *sellerID {
+		return errors.New("product does not belong to seller")
+	}
+	if product.Status == models.ProductStatusDeleted {
+		return errors.New("product is deleted")
+	}
+	// Only an admin's approval lists these; sellers can't show them or move
+	// them out of review
+	if sellerID != nil && (product.Status == models.ProductStatusPendingApproval || (visible && product.NeedsApproval())) {
+		return errors.New("product is awaiting approval")
+	}
+
+	// Public listings filter on is_active, so it follows visibility
+	updates := map[string]interface{}{
+		"visible":   visible,
+		"is_active": visible,
+	}
+	details := fmt.Sprintf("visible=%t", visible)
+	if status != nil {
+		updates["status"] = *status
+		details += fmt.Sprintf(" status=%s", *status)
+	}
+	if err := tx.Model(&models.Product{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	entry := &models.AuditLog{
+		ActorID:    actorID,
+		Action:     "product.visibility",
+		EntityType: "product",
+		EntityID:   id,
+		Details:    &details,
+	}
+	return tx.Create(entry).Error
+}
+
+// ResolveApproval lists a product awaiting approval, or rejects it back to a
+// draft with the reason, and writes the audit entry. It returns false if the
+// product wasn't awaiting approval, so only one decision is applied.
+func (r *productRepository) ResolveApproval(ctx context.Context, id uint, approved bool, reason *string, actorID uint) (bool, error) {
+	updates := map[string]interface{}{
+		"status":           models.ProductStatusDraft,
+		"is_active":        false,
+		"visible":          false,
+		"rejection_reason": reason,
+	}
+	details := "rejected"
+	if reason != nil {
+		details += ": " + *reason
+	}
+	if approved {
+		updates = map[string]interface{}{
+			"status":           models.ProductStatusActive,
+			"is_active":        true,
+			"visible":          true,
+			"rejection_reason": nil,
+		}
+		details = "approved"
+	}
+
+	resolved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Product{}).
+			Where("id = ? AND status = ?", id, models.ProductStatusPendingApproval).
+			Updates(updates)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		resolved = true
+
+		return tx.Create(&models.AuditLog{
+			ActorID:    actorID,
+			Action:     "product.approval",
+			EntityType: "product",
+			EntityID:   id,
+			Details:    &details,
+		}).Error
+	})
+	return resolved, err
+}
+
+// GetPendingApproval returns products awaiting approval, longest waiting first
+func (r *productRepository) GetPendingApproval(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Product{}).Where("status = ?", models.ProductStatusPendingApproval)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []*models.Product
+	err := query.Order("updated_at ASC").Limit(limit).Offset(offset).Find(&products).Error
+	return products, total, err
+}
+
+// HideSellerProducts makes a deactivated seller's active products inactive and
+// hidden, marking them so RestoreSellerProducts can bring them back. Drafts and
+// products the seller had already taken down are left as they are. Returns the
+// IDs of the products hidden.
+func (r *productRepository) HideSellerProducts(ctx context.Context, sellerID, actorID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Product{}).
+			Where("seller_id = ? AND status = ?", sellerID, models.ProductStatusActive).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.Product{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":             models.ProductStatusInactive,
+			"is_active":          false,
+			"visible":            false,
+			"hidden_with_seller": true,
+		}).Error; err != nil {
+			return err
+		}
+		return createSellerProductAuditLogs(tx, ids, actorID, "hidden: seller deactivated")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// RestoreSellerProducts makes the products hidden by HideSellerProducts active
+// and visible again. Returns the IDs of the products restored.
+func (r *productRepository) RestoreSellerProducts(ctx context.Context, sellerID, actorID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Product{}).
+			Where("seller_id = ? AND hidden_with_seller = ?", sellerID, true).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.Product{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":             models.ProductStatusActive,
+			"is_active":          true,
+			"visible":            true,
+			"hidden_with_seller": false,
+		}).Error; err != nil {
+			return err
+		}
+		return createSellerProductAuditLogs(tx, ids, actorID, "restored: seller reactivated")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// createSellerProductAuditLogs records a visibility change made on behalf of
+// the products' seller
+func createSellerProductAuditLogs(tx *gorm.DB, productIDs []uint, actorID uint, details string) error {
+	entries := make([]models.AuditLog, len(productIDs))
+	for i, id := range productIDs {
+		entries[i] = models.AuditLog{
+			ActorID:    actorID,
+			Action:     "product.visibility",
+			EntityType: "product",
+			EntityID:   id,
+			Details:    &details,
+		}
+	}
+	return tx.Create(&entries).Error
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (r *productRepository) GetFiltered(ctx context.Context, req *models.GetProductsRequest) ([]*models.Product, int64, error) {
+	var products []*models.Product
+	var total int64
+
+	query := applyProductFilters(r.db.WithContext(ctx).Model(&models.Product{}), req, true)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Reviews").
+		Order(productSortOrder(req.SortBy)).
+		Limit(req.Limit).
+		Offset(req.Offset).
+		Find(&products).Error
+	return products, total, err
+}
+
+// productSortOrder returns the ORDER BY for a listing sort, newest first for
+// anything else. Ties fall back to newest so pages don't shuffle.
+func productSortOrder(sortBy models.ProductSort) string {
+	switch sortBy {
+	case models.ProductSortBestSelling:
+		return `(SELECT COALESCE(SUM(order_items.quantity), 0) FROM order_items
+			JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL
+			WHERE order_items.product_id = products.id AND orders.status NOT IN ('cancelled', 'refunded')) DESC,
+			products.created_at DESC, products.id DESC`
+	case models.ProductSortPriceAsc:
+		return "products.price ASC, products.created_at DESC, products.id DESC"
+	case models.ProductSortPriceDesc:
+		return "products.price DESC, products.created_at DESC, products.id DESC"
+	case models.ProductSortRating:
+		return "products.average_rating DESC, products.review_count DESC, products.created_at DESC, products.id DESC"
+	case models.ProductSortPopular:
+		return "products.view_count DESC, products.created_at DESC, products.id DESC"
+	case models.ProductSortName:
+		return "products.name ASC, products.id ASC"
+	default:
+		return "products.created_at DESC, products.id DESC"
+	}
+}
+
+func (r *productRepository) GetPriceTierCounts(ctx context.Context, req *models.GetProductsRequest) ([]models.PriceTierCount, error) {
+	// Bucket every matching product into its tier in one pass; price filters are
+	// ignored so all budget chips stay visible while one is selected
+	cases := make([]string, 0, len(models.PriceTierRanges))
+	for _, tier := range models.PriceTierRanges {
+		if tier.Max > 0 {
+			cases = append(cases, fmt.Sprintf("WHEN price < %.2f THEN '%s'", tier.Max, tier.Tier))
+		} else {
+			cases = append(cases, fmt.Sprintf("ELSE '%s'", tier.Tier))
+		}
+	}
+
+	var rows []struct {
+		Tier  models.PriceTier
+		Count int64
+	}
+	err := applyProductFilters(r.db.WithContext(ctx).Model(&models.Product{}), req, false).
+		Select(fmt.Sprintf("CASE %s END AS tier, COUNT(*) AS count", strings.Join(cases, " "))).
+		Group("tier").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	countByTier := make(map[models.PriceTier]int64, len(rows))
+	for _, row := range rows {
+		countByTier[row.Tier] = row.Count
+	}
+
+	counts := make([]models.PriceTierCount, len(models.PriceTierRanges))
+	for i, tier := range models.PriceTierRanges {
+		counts[i] = models.PriceTierCount{
+			Tier:  tier.Tier,
+			Label: tier.Label,
+			Min:   tier.Min,
+			Count: countByTier[tier.Tier],
+		}
+		if tier.Max > 0 {
+			max := tier.Max
+			counts[i].Max = &max
+		}
+	}
+	return counts, nil
+}
+
+// GetTagCounts returns the most common tags among the products matching req
+func (r *productRepository) GetTagCounts(ctx context.Context, req *models.GetProductsRequest, limit int) ([]models.TagCount, error) {
+	matching := applyProductFilters(r.db.WithContext(ctx).Model(&models.Product{}).Select("products.id"), req, true)
+
+	var counts []models.TagCount
+	err := r.db.WithContext(ctx).
+		Table("product_tags").
+		Select("tags.name AS tag, COUNT(*) AS count").
+		Joins("JOIN tags ON tags.id = product_tags.tag_id").
+		Where("product_tags.product_id IN (?)", matching).
+		Group("tags.name").
+		Order("count DESC, tags.name ASC").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// GetPopularTags returns the tags used by the most active products
+func (r *productRepository) GetPopularTags(ctx context.Context, limit int) ([]models.TagCount, error) {
+	var counts []models.TagCount
+	err := r.db.WithContext(ctx).
+		Table("product_tags").
+		Select("tags.name AS tag, COUNT(*) AS count").
+		Joins("JOIN tags ON tags.id = product_tags.tag_id").
+		Joins("JOIN products ON products.id = product_tags.product_id").
+		Where("products.is_active = ? AND products.deleted_at IS NULL", true).
+		Group("tags.name").
+		Order("count DESC, tags.name ASC").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// applyProductFilters adds the listing filters from req to query
+// applyRatingGate excludes products below the gate's minimum rating, and
+// products with too few reviews unless the gate includes them
+func applyRatingGate(query *gorm.DB, gate models.RatingGate) *gorm.DB {
+	if !gate.Enabled() {
+		return query
+	}
+	if gate.IncludeFewReviews {
+		return query.Where("(review_count < ? OR average_rating >= ?)", gate.MinReviews, gate.MinRating)
+	}
+	return query.Where("review_count >= ? AND average_rating >= ?", gate.MinReviews, gate.MinRating)
+}
+
+// applyAvailabilityWindow excludes products outside their available from/until window
+func applyAvailabilityWindow(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where("(products.available_from IS NULL OR products.available_from <= ?) AND (products.available_until IS NULL OR products.available_until >= ?)", now, now)
+}
+
+// applyStockStatus keeps products whose stock matches status. The conditions
+// mirror Product.UpdateComputedFields and Product.BackorderAvailable.
+func applyStockStatus(query *gorm.DB, status models.StockStatus) *gorm.DB {
+	const backorderable = "products.allow_backorders = true AND products.stock > -products.max_backorder_quantity"
+
+	switch status {
+	case models.StockStatusInStock:
+		return query.Where("(products.track_inventory = false OR products.stock > 0)")
+	case models.StockStatusLowStock:
+		return query.Where("products.track_inventory = true AND products.stock > 0 AND products.stock <= products.low_stock_level")
+	case models.StockStatusOutOfStock:
+		return query.Where("products.track_inventory = true AND products.stock <= 0 AND NOT (" + backorderable + ")")
+	case models.StockStatusBackorderable:
+		return query.Where("products.track_inventory = true AND products.stock <= 0 AND " + backorderable)
+	}
+	return query
+}
+
+func applyProductFilters(query *gorm.DB, req *models.GetProductsRequest, includePrice bool) *gorm.DB {
+	// Hidden products never show in public listings
+	query = query.Where("products.is_active = ?", true)
+	if !req.IncludeUnavailable {
+		query = applyAvailabilityWindow(query, time.Now())
+	}
+
+	if req.Category != "" {
+		query = query.Where("category = ?", req.Category)
+	}
+	if req.SellerID != nil {
+		query = query.Where("seller_id = ?", *req.SellerID)
+	}
+	if req.Search != "" {
+		query = query.Where("(name ILIKE ? OR description ILIKE ?)", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+
+	if tags := models.NormalizeTags(req.Tags); len(tags) > 0 {
+		tagged := query.Session(&gorm.Session{NewDB: true}).
+			Table("product_tags").
+			Select("product_tags.product_id").
+			Joins("JOIN tags ON tags.id = product_tags.tag_id").
+			Where("tags.name IN ?", tags)
+		if req.TagMatch == models.TagMatchAll {
+			tagged = tagged.Group("product_tags.product_id").Having("COUNT(DISTINCT tags.id) = ?", len(tags))
+		}
+		query = query.Where("products.id IN (?)", tagged)
+	}
+
+	if req.StockStatus != "" {
+		query = applyStockStatus(query, req.StockStatus)
+	}
+
+	if !includePrice {
+		return query
+	}
+
+	if req.MinPrice != nil {
+		query = query.Where("price >= ?", *req.MinPrice)
+	}
+	if req.MaxPrice != nil {
+		query = query.Where("price <= ?", *req.MaxPrice)
+	}
+	if tier, ok := models.GetPriceTierRange(req.PriceTier); ok {
+		query = query.Where("price >= ?", tier.Min)
+		if tier.Max > 0 {
+			query = query.Where("price < ?", tier.Max)
+		}
+	}
+	return query
+}
+
+func (r *productRepository) GetInventoryValuation(ctx context.Context, sellerID uint) (*models.InventoryValuation, error) {
+	// Only positive stock carries value; backordered (negative) stock is ignored.
+	// Products with a NULL cost_price contribute nothing to cost value. Stock
+	// that checkout took below zero within the oversell tolerance is reported
+	// separately as oversold.
+	var rows []models.CategoryInventoryValuation
+	err := r.db.WithContext(ctx).Model(&models.Product{}).
+		Select(`category,
+			COUNT(*) AS product_count,
+			COALESCE(SUM(GREATEST(stock_quantity, 0)), 0) AS total_units,
+			COALESCE(SUM(cost_price * GREATEST(stock_quantity, 0)), 0) AS cost_value,
+			COALESCE(SUM(price * GREATEST(stock_quantity, 0)), 0) AS retail_value,
+			COUNT(*) FILTER (WHERE cost_price IS NULL) AS products_without_cost,
+			COUNT(*) FILTER (WHERE stock < 0) AS oversold_products,
+			COALESCE(SUM(-LEAST(stock, 0)), 0) AS oversold_units`).
+		Where("seller_id = ?", sellerID).
+		Group("category").
+		Order("category").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	valuation := &models.InventoryValuation{
+		SellerID:   sellerID,
+		ByCategory: rows,
+	}
+	for _, row := range rows {
+		valuation.TotalUnits += row.TotalUnits
+		valuation.TotalCostValue += row.CostValue
+		valuation.TotalRetailValue += row.RetailValue
+		valuation.ProductsWithoutCost += row.ProductsWithoutCost
+		valuation.OversoldProducts += row.OversoldProducts
+		valuation.OversoldUnits += row.OversoldUnits
+	}
+	return valuation, nil
+}
+
+// GetLowStockBySeller returns the seller's tracked products at or below their own low stock level
+func (r *productRepository) GetLowStockBySeller(ctx context.Context, sellerID uint) ([]*models.Product, error) {
+	var products []*models.Product
+	err := r.db.WithContext(ctx).
+		Where("seller_id = ? AND track_inventory = ? AND stock <= low_stock_level", sellerID, true).
+		Order("stock ASC").
+		Find(&products).Error
+	return products, err
+}
+
+// GetUnitsSold returns units ordered per product since the given time, excluding
+// cancelled and refunded orders. Products without sales are absent from the map.
+func (r *productRepository) GetUnitsSold(ctx context.Context, productIDs []uint, since time.Time) (map[uint]int, error) {
+	unitsSold := make(map[uint]int)
+	if len(productIDs) == 0 {
+		return unitsSold, nil
+	}
+
+	var rows []struct {
+		ProductID uint
+		Units     int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.OrderItem{}).
+		Select("order_items.product_id, COALESCE(SUM(order_items.quantity), 0) AS units").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("order_items.product_id IN ? AND orders.created_at >= ? AND orders.status NOT IN ?",
+			productIDs, since,
+			[]models.OrderStatus{models.OrderStatusCancelled, models.OrderStatusRefunded}).
+		Group("order_items.product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		unitsSold[row.ProductID] = row.Units
+	}
+	return unitsSold, nil
+}
+
+// productAttentionCheck is the SQL condition flagging a product for one
+// attention reason
+type productAttentionCheck struct {
+	condition string
+	args      []interface{}
+}
+
+func productAttentionChecks(criteria models.ProductAttentionCriteria) map[models.ProductAttentionReason]productAttentionCheck {
+	// Unreviewed products have no rating to be low
+	minReviews := criteria.Rating.MinReviews
+	if minReviews < 1 {
+		minReviews = 1
+	}
+
+	return map[models.ProductAttentionReason]productAttentionCheck{
+		models.AttentionOutOfStock: {condition: "products.track_inventory AND products.stock <= 0"},
+		models.AttentionLowRating: {
+			condition: "products.review_count >= ? AND products.average_rating < ?",
+			args:      []interface{}{minReviews, criteria.Rating.MinRating},
+		},
+		models.AttentionMissingImages: {
+			condition: "NOT EXISTS (SELECT 1 FROM product_images WHERE product_images.product_id = products.id AND product_images.deleted_at IS NULL)",
+		},
+		models.AttentionMissingCategory:    {condition: "products.category_id IS NULL"},
+		models.AttentionMissingDescription: {condition: "TRIM(COALESCE(products.description, '')) = ''"},
+		models.AttentionStaleDraft: {
+			condition: "products.status = ? AND products.created_at < ?",
+			args:      []interface{}{models.ProductStatusDraft, criteria.DraftBefore},
+		},
+		models.AttentionMissingShortDescription: {condition: "TRIM(COALESCE(products.short_description, '')) = ''"},
+	}
+}
+
+// GetNeedingAttention returns the seller's products flagged by any of the
+// attention checks, or only by criteria.Reason, with every reason each was
+// flagged for. Products flagged for a more pressing reason come first, ties
+// going to those flagged for more pressing reasons after it, then by product ID.
+func (r *productRepository) GetNeedingAttention(ctx context.Context, sellerID uint, criteria models.ProductAttentionCriteria, limit, offset int) ([]models.ProductAttention, int64, error) {
+	if criteria.Reason != nil && !criteria.Reason.IsValid() {
+		return nil, 0, fmt.Errorf("unknown attention reason %q", *criteria.Reason)
+	}
+
+	// Each check becomes a boolean column named after its reason
+	checks := productAttentionChecks(criteria)
+	columns := []string{`products.id AS product_id, products.name, products.sku, products.status, products.stock,
+		products.average_rating, products.review_count, products.created_at`}
+	var args []interface{}
+	flags := make([]string, len(models.ProductAttentionReasons))
+	reasons := make([]string, len(models.ProductAttentionReasons))
+	priority := make([]string, len(models.ProductAttentionReasons))
+	for i, reason := range models.ProductAttentionReasons {
+		check := checks[reason]
+		columns = append(columns, fmt.Sprintf("(%s) AS %s", check.condition, reason))
+		args = append(args, check.args...)
+		flags[i] = string(reason)
+		reasons[i] = fmt.Sprintf("CASE WHEN %s THEN '%s' END", reason, reason)
+		priority[i] = fmt.Sprintf("CASE WHEN %s THEN %d ELSE 0 END", reason, 1<<(len(models.ProductAttentionReasons)-1-i))
+	}
+	products := r.db.
+		Table("products").
+		Select(strings.Join(columns, ", "), args...).
+		Where("products.seller_id = ? AND products.deleted_at IS NULL AND products.status <> ?", sellerID, models.ProductStatusDeleted)
+
+	query := r.db.WithContext(ctx).Table("(?) AS checked", products)
+	if criteria.Reason != nil {
+		query = query.Where(string(*criteria.Reason))
+	} else {
+		query = query.Where(strings.Join(flags, " OR "))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		ProductID     uint
+		Name          string
+		SKU           string
+		Status        models.ProductStatus
+		Stock         int
+		AverageRating float64
+		ReviewCount   int
+		CreatedAt     time.Time
+		Reasons       string
+	}
+	err := query.
+		Select(fmt.Sprintf("checked.*, CONCAT_WS(',', %s) AS reasons, %s AS priority",
+			strings.Join(reasons, ", "), strings.Join(priority, " + "))).
+		Order("priority DESC, product_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	attention := make([]models.ProductAttention, len(rows))
+	for i, row := range rows {
+		attention[i] = models.ProductAttention{
+			ProductID:     row.ProductID,
+			Name:          row.Name,
+			SKU:           row.SKU,
+			Status:        row.Status,
+			Stock:         row.Stock,
+			AverageRating: row.AverageRating,
+			ReviewCount:   row.ReviewCount,
+			CreatedAt:     row.CreatedAt,
+		}
+		for _, reason := range strings.Split(row.Reasons, ",") {
+			attention[i].Reasons = append(attention[i].Reasons, models.ProductAttentionReason(reason))
+		}
+	}
+	return attention, total, nil
+}
+
+// UpsertTranslation creates or replaces the product's content in the translation's locale
+func (r *productRepository) UpsertTranslation(ctx context.Context, translation *models.ProductTranslation) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "short_description", "meta_title", "meta_description", "updated_at"}),
+		}).
+		Create(translation).Error
+}
+
+func (r *productRepository) GetTranslations(ctx context.Context, productID uint) ([]*models.ProductTranslation, error) {
+	var translations []*models.ProductTranslation
+	err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("locale ASC").
+		Find(&translations).Error
+	return translations, err
+}
+
+// GetTranslationsFor returns the products' translations in any of the locales
+func (r *productRepository) GetTranslationsFor(ctx context.Context, productIDs []uint, locales []string) ([]*models.ProductTranslation, error) {
+	var translations []*models.ProductTranslation
+	if len(productIDs) == 0 || len(locales) == 0 {
+		return translations, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("product_id IN ? AND locale IN ?", productIDs, locales).
+		Find(&translations).Error
+	return translations, err
+}
+
+func (r *productRepository) DeleteTranslation(ctx context.Context, productID uint, locale string) error {
+	return r.db.WithContext(ctx).
+		Unscoped().
+		Where("product_id = ? AND locale = ?", productID, locale).
+		Delete(&models.ProductTranslation{}).Error
+}
+
+// GetPriceHistory returns a product's price changes, newest first
+func (r *productRepository) GetPriceHistory(ctx context.Context, productID uint, limit, offset int) ([]*models.PriceHistory, int64, error) {
+	var total int64
+	query := r.db.WithContext(ctx).Model(&models.PriceHistory{}).Where("product_id = ?", productID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var history []*models.PriceHistory
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&history).Error
+	return history, total, err
+}
+
+// GetLowestPriceSince returns the lowest price the product was sold at through
+// changes made since the given time, or nil when its price hasn't changed. The
+// caller should also compare against the current price.
+func (r *productRepository) GetLowestPriceSince(ctx context.Context, productID uint, since time.Time) (*float64, error) {
+	var lowest *float64
+	err := r.db.WithContext(ctx).
+		Model(&models.PriceHistory{}).
+		Select("LEAST(MIN(old_price), MIN(new_price))").
+		Where("product_id = ? AND created_at >= ?", productID, since).
+		Scan(&lowest).Error
+	return lowest, err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// updateTestProduct sets columns of a test product
+func updateTestProduct(t *testing.T, db *gorm.DB, productID uint, columns map[string]interface{}) {
+	t.Helper()
+	if err := db.Model(&models.Product{}).Where("id = ?", productID).Updates(columns).Error; err != nil {
+		t.Fatalf("failed to update product: %v", err)
+	}
+}
+
+// createCompleteTestProduct inserts an active product with nothing to fix
+func createCompleteTestProduct(t *testing.T, db *gorm.DB, sellerID, categoryID uint, sku string, stock int) *models.Product {
+	t.Helper()
+	product := createTestProduct(t, db, sellerID, sku, stock)
+	updateTestProduct(t, db, product.ID, map[string]interface{}{
+		"status":            models.ProductStatusActive,
+		"category_id":       categoryID,
+		"short_description": "Short description",
+	})
+	if err := db.Create(&models.ProductImage{ProductID: product.ID, URL: "https://example.com/" + sku + ".jpg", AltText: sku, IsPrimary: true}).Error; err != nil {
+		t.Fatalf("failed to create image: %v", err)
+	}
+	return product
+}
+
+func TestGetNeedingAttentionFlagsAndPrioritizesProducts(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewProductRepository(db)
+
+	seller := createTestUser(t, db, "attention-seller@example.com")
+	other := createTestUser(t, db, "attention-other@example.com")
+	category := &models.Category{Name: "Attention", Slug: "attention", IsActive: true}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+
+	createCompleteTestProduct(t, db, seller.ID, category.ID, "ATT-OK", 5)
+	outOfStock := createCompleteTestProduct(t, db, seller.ID, category.ID, "ATT-STOCK", 0)
+	lowRated := createCompleteTestProduct(t, db, seller.ID, category.ID, "ATT-RATED", 5)
+	updateTestProduct(t, db, lowRated.ID, map[string]interface{}{"average_rating": 2, "review_count": 5})
+	bare := createTestProduct(t, db, seller.ID, "ATT-BARE", 5)
+	updateTestProduct(t, db, bare.ID, map[string]interface{}{"created_at": time.Now().AddDate(0, 0, -30)})
+	createTestProduct(t, db, other.ID, "ATT-OTHER", 0)
+
+	criteria := models.ProductAttentionCriteria{
+		Rating:      models.RatingGate{MinRating: 3.5, MinReviews: 3},
+		DraftBefore: time.Now().AddDate(0, 0, -14),
+	}
+	products, total, err := repo.GetNeedingAttention(ctx, seller.ID, criteria, 10, 0)
+	if err != nil {
+		t.Fatalf("GetNeedingAttention: %v", err)
+	}
+	if total != 3 || len(products) != 3 {
+		t.Fatalf("expected 3 products, got %d of %d", len(products), total)
+	}
+
+	want := []struct {
+		productID uint
+		reasons   []models.ProductAttentionReason
+	}{
+		{outOfStock.ID, []models.ProductAttentionReason{models.AttentionOutOfStock}},
+		{lowRated.ID, []models.ProductAttentionReason{models.AttentionLowRating}},
+		{bare.ID, []models.ProductAttentionReason{
+			models.AttentionMissingImages,
+			models.AttentionMissingCategory,
+			models.AttentionStaleDraft,
+			models.AttentionMissingShortDescription,
+		}},
+	}
+	for i, w := range want {
+		if products[i].ProductID != w.productID || !reflect.DeepEqual(products[i].Reasons, w.reasons) {
+			t.Errorf("position %d: expected product %d with %v, got product %d with %v",
+				i, w.productID, w.reasons, products[i].ProductID, products[i].Reasons)
+		}
+	}
+
+	reason := models.AttentionMissingImages
+	criteria.Reason = &reason
+	products, total, err = repo.GetNeedingAttention(ctx, seller.ID, criteria, 10, 0)
+	if err != nil {
+		t.Fatalf("GetNeedingAttention by reason: %v", err)
+	}
+	if total != 1 || len(products) != 1 || products[0].ProductID != bare.ID {
+		t.Errorf("expected only the product without images, got %d products", total)
+	}
+}
+
+func TestDeletedProductSKUCanBeReused(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewProductRepository(db)
+
+	seller := createTestUser(t, db, "sku-reuse-seller@example.com")
+	first := createTestProduct(t, db, seller.ID, "SKU-REUSE", 5)
+	if exists, err := repo.SKUExists(ctx, "SKU-REUSE"); err != nil || !exists {
+		t.Fatalf("SKUExists = %v, %v; want the live product's SKU taken", exists, err)
+	}
+
+	if err := repo.Delete(ctx, first.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if exists, err := repo.SKUExists(ctx, "SKU-REUSE"); err != nil || exists {
+		t.Fatalf("SKUExists = %v, %v; want the deleted product's SKU free", exists, err)
+	}
+
+	second := &models.Product{Name: "Test product again", Description: "A product for tests", SKU: "SKU-REUSE", Slug: "sku-reuse-2", Price: 10, Category: string(models.CategoryOther), SellerID: seller.ID, Returnable: true}
+	if err := repo.Create(ctx, second); err != nil {
+		t.Fatalf("recreating a deleted product's SKU: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Product{Name: "Duplicate", Description: "A product for tests", SKU: "SKU-REUSE", Slug: "sku-reuse-3", Price: 10, Category: string(models.CategoryOther), SellerID: seller.ID, Returnable: true}); err == nil {
+		t.Error("expected a second live product with the SKU to be rejected")
+	}
+}
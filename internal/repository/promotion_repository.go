@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *models.Promotion) error
+	GetByID(ctx context.Context, id uint) (*models.Promotion, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*models.Promotion, int64, error)
+	GetRunning(ctx context.Context, at time.Time) ([]*models.Promotion, error)
+	Update(ctx context.Context, promotion *models.Promotion) error
+	Delete(ctx context.Context, id uint) error
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+func (r *promotionRepository) Create(ctx context.Context, promotion *models.Promotion) error {
+	return r.db.WithContext(ctx).Create(promotion).Error
+}
+
+func (r *promotionRepository) GetByID(ctx context.Context, id uint) (*models.Promotion, error) {
+	var promotion models.Promotion
+	err := r.db.WithContext(ctx).First(&promotion, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// GetAll returns every promotion, inactive and expired ones included, for admins
+func (r *promotionRepository) GetAll(ctx context.Context, limit, offset int) ([]*models.Promotion, int64, error) {
+	var promotions []*models.Promotion
+	var total int64
+
+	if err := r.db.WithContext(ctx).Model(&models.Promotion{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&promotions).Error
+	return promotions, total, err
+}
+
+// GetRunning returns active promotions whose window contains at
+func (r *promotionRepository) GetRunning(ctx context.Context, at time.Time) ([]*models.Promotion, error) {
+	var promotions []*models.Promotion
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND starts_at <= ?", true, at).
+		Where("ends_at IS NULL OR ends_at > ?", at).
+		Order("id ASC").
+		Find(&promotions).Error
+	return promotions, err
+}
+
+func (r *promotionRepository) Update(ctx context.Context, promotion *models.Promotion) error {
+	return r.db.WithContext(ctx).Save(promotion).Error
+}
+
+func (r *promotionRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Promotion{}, id).Error
+}
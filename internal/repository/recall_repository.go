@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type recallRepository struct {
+	db *gorm.DB
+}
+
+type RecallRepository interface {
+	Create(ctx context.Context, recall *models.ProductRecall) error
+	Update(ctx context.Context, recall *models.ProductRecall) error
+	GetAffectedCustomers(ctx context.Context, productID, afterID uint, limit int) ([]*models.User, error)
+	QueueEmails(ctx context.Context, recallID uint, customers []*models.User) error
+}
+
+func NewRecallRepository(db *gorm.DB) RecallRepository {
+	return &recallRepository{db: db}
+}
+
+func (r *recallRepository) Create(ctx context.Context, recall *models.ProductRecall) error {
+	return r.db.WithContext(ctx).Create(recall).Error
+}
+
+func (r *recallRepository) Update(ctx context.Context, recall *models.ProductRecall) error {
+	return r.db.WithContext(ctx).Save(recall).Error
+}
+
+// GetAffectedCustomers returns customers with a paid order containing the product,
+// ordered by ID so callers can page through them with afterID
+func (r *recallRepository) GetAffectedCustomers(ctx context.Context, productID, afterID uint, limit int) ([]*models.User, error) {
+	var users []*models.User
+	err := r.db.WithContext(ctx).
+		Where("users.id > ?", afterID).
+		Where("users.id IN (?)", r.db.
+			Table("orders").
+			Select("orders.customer_id").
+			Joins("JOIN order_items ON order_items.order_id = orders.id").
+			Where("order_items.product_id = ? AND orders.payment_status = ? AND orders.deleted_at IS NULL", productID, models.PaymentStatusPaid)).
+		Order("users.id ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// QueueEmails queues the recall notice for each customer. The email dispatcher
+// sends them at the configured rate.
+func (r *recallRepository) QueueEmails(ctx context.Context, recallID uint, customers []*models.User) error {
+	if len(customers) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	deliveries := make([]*models.EmailDelivery, len(customers))
+	for i, customer := range customers {
+		deliveries[i] = &models.EmailDelivery{
+			RecallID:      &recallID,
+			UserID:        customer.ID,
+			Email:         customer.Email,
+			Name:          customer.FirstName,
+			Status:        models.EmailDeliveryPending,
+			NextAttemptAt: now,
+		}
+	}
+	return r.db.WithContext(ctx).Create(&deliveries).Error
+}
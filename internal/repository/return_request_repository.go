@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type returnRequestRepository struct {
+	db *gorm.DB
+}
+
+type ReturnRequestRepository interface {
+	CreateWithinQuantity(ctx context.Context, returnRequest *models.ReturnRequest, purchased int) (bool, error)
+	Update(ctx context.Context, returnRequest *models.ReturnRequest) error
+	Transition(ctx context.Context, id uint, from, to models.ReturnStatus) (bool, error)
+	GetByID(ctx context.Context, id uint) (*models.ReturnRequest, error)
+	ListByCustomer(ctx context.Context, customerID uint, limit, offset int) ([]*models.ReturnRequest, int64, error)
+	List(ctx context.Context, status *models.ReturnStatus, limit, offset int) ([]*models.ReturnRequest, int64, error)
+}
+
+func NewReturnRequestRepository(db *gorm.DB) ReturnRequestRepository {
+	return &returnRequestRepository{db: db}
+}
+
+// CreateWithinQuantity creates the return unless it would take the units under
+// return for the order item, across every request that wasn't rejected, past
+// purchased. The item row is locked so concurrent requests are counted in turn.
+func (r *returnRequestRepository) CreateWithinQuantity(ctx context.Context, returnRequest *models.ReturnRequest, purchased int) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item models.OrderItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&item, returnRequest.OrderItemID).Error; err != nil {
+			return err
+		}
+
+		var returned int64
+		if err := tx.Model(&models.ReturnRequest{}).
+			Where("order_item_id = ? AND status <> ?", returnRequest.OrderItemID, models.ReturnStatusRejected).
+			Select("COALESCE(SUM(quantity), 0)").
+			Scan(&returned).Error; err != nil {
+			return err
+		}
+		if int(returned)+returnRequest.Quantity > purchased {
+			return nil
+		}
+
+		if err := tx.Create(returnRequest).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *returnRequestRepository) Update(ctx context.Context, returnRequest *models.ReturnRequest) error {
+	return r.db.WithContext(ctx).Omit("OrderItem").Save(returnRequest).Error
+}
+
+// Transition moves the return from one status to another. It returns false if
+// the return was no longer in from, so only one admin can act on it.
+func (r *returnRequestRepository) Transition(ctx context.Context, id uint, from, to models.ReturnStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ReturnRequest{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *returnRequestRepository) GetByID(ctx context.Context, id uint) (*models.ReturnRequest, error) {
+	var returnRequest models.ReturnRequest
+	err := r.db.WithContext(ctx).
+		Preload("OrderItem").
+		First(&returnRequest, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &returnRequest, nil
+}
+
+// ListByCustomer returns the customer's returns, newest first
+func (r *returnRequestRepository) ListByCustomer(ctx context.Context, customerID uint, limit, offset int) ([]*models.ReturnRequest, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.ReturnRequest{}).Where("customer_id = ?", customerID)
+	return r.list(query, "created_at DESC", limit, offset)
+}
+
+// List returns returns, oldest first so the longest-waiting are reviewed
+// first, optionally filtered by status
+func (r *returnRequestRepository) List(ctx context.Context, status *models.ReturnStatus, limit, offset int) ([]*models.ReturnRequest, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.ReturnRequest{})
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+	return r.list(query, "created_at ASC", limit, offset)
+}
+
+func (r *returnRequestRepository) list(query *gorm.DB, order string, limit, offset int) ([]*models.ReturnRequest, int64, error) {
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var returnRequests []*models.ReturnRequest
+	err := query.
+		Preload("OrderItem").
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&returnRequests).Error
+	return returnRequests, total, err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+func TestConcurrentReturnsStayWithinPurchasedQuantity(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewReturnRequestRepository(db)
+
+	customer := createTestUser(t, db, "returns-customer@example.com")
+	seller := createTestUser(t, db, "returns-seller@example.com")
+	product := createTestProduct(t, db, seller.ID, "RET-RACE", 5)
+	order := createTestOrder(t, db, customer.ID, "ORD-RETURN-1", models.OrderStatusDelivered)
+
+	item := &models.OrderItem{
+		OrderID:     order.ID,
+		ProductID:   product.ID,
+		SellerID:    seller.ID,
+		Quantity:    2,
+		UnitPrice:   10,
+		TotalPrice:  20,
+		ProductName: product.Name,
+		ProductSKU:  product.SKU,
+	}
+	if err := db.Create(item).Error; err != nil {
+		t.Fatalf("failed to create order item: %v", err)
+	}
+
+	const requests = 5
+	var wg sync.WaitGroup
+	results := make([]bool, requests)
+	start := make(chan struct{})
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			created, err := repo.CreateWithinQuantity(ctx, &models.ReturnRequest{
+				OrderID:     order.ID,
+				OrderItemID: item.ID,
+				CustomerID:  customer.ID,
+				Quantity:    1,
+				Reason:      "Changed my mind",
+				Status:      models.ReturnStatusRequested,
+			}, item.Quantity)
+			if err != nil {
+				t.Errorf("CreateWithinQuantity: %v", err)
+			}
+			results[i] = created
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for _, ok := range results {
+		if ok {
+			created++
+		}
+	}
+	if created != item.Quantity {
+		t.Errorf("%d single-unit returns created for %d units, want %d", created, item.Quantity, item.Quantity)
+	}
+}
+
+func TestTransitionOnlyMovesFromExpectedStatus(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewReturnRequestRepository(db)
+
+	customer := createTestUser(t, db, "returns-transition@example.com")
+	seller := createTestUser(t, db, "returns-transition-seller@example.com")
+	product := createTestProduct(t, db, seller.ID, "RET-MOVE", 5)
+	order := createTestOrder(t, db, customer.ID, "ORD-RETURN-2", models.OrderStatusDelivered)
+	item := &models.OrderItem{OrderID: order.ID, ProductID: product.ID, SellerID: seller.ID, Quantity: 1, UnitPrice: 10, TotalPrice: 10, ProductName: product.Name, ProductSKU: product.SKU}
+	if err := db.Create(item).Error; err != nil {
+		t.Fatalf("failed to create order item: %v", err)
+	}
+
+	returnRequest := &models.ReturnRequest{OrderID: order.ID, OrderItemID: item.ID, CustomerID: customer.ID, Quantity: 1, Reason: "Too small", Status: models.ReturnStatusRequested}
+	if created, err := repo.CreateWithinQuantity(ctx, returnRequest, item.Quantity); err != nil || !created {
+		t.Fatalf("CreateWithinQuantity = %v, %v", created, err)
+	}
+
+	moved, err := repo.Transition(ctx, returnRequest.ID, models.ReturnStatusRequested, models.ReturnStatusApproved)
+	if err != nil || !moved {
+		t.Fatalf("first transition = %v, %v", moved, err)
+	}
+	moved, err = repo.Transition(ctx, returnRequest.ID, models.ReturnStatusRequested, models.ReturnStatusRejected)
+	if err != nil {
+		t.Fatalf("second transition: %v", err)
+	}
+	if moved {
+		t.Error("expected an approved return not to move from requested again")
+	}
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type reviewRepository struct {
@@ -31,18 +34,28 @@ func (r *reviewRepository) GetByID(ctx context.Context, id uint) (*models.Review
 	return &review, nil
 }
 
-func (r *reviewRepository) GetByProductID(ctx context.Context, productID uint, limit, offset int) ([]*models.Review, error) {
+func (r *reviewRepository) GetByProductID(ctx context.Context, productID uint, sortBy models.ReviewSort, limit, offset int) ([]*models.Review, error) {
 	var reviews []*models.Review
 	err := r.db.WithContext(ctx).
 		Where("product_id = ?", productID).
 		Preload("User").
-		Order("created_at DESC").
+		Order(reviewSortOrder(sortBy)).
 		Limit(limit).
 		Offset(offset).
 		Find(&reviews).Error
 	return reviews, err
 }
 
+// GetApprovedTextByProductID returns the rating and text of every approved review for a product
+func (r *reviewRepository) GetApprovedTextByProductID(ctx context.Context, productID uint) ([]*models.Review, error) {
+	var reviews []*models.Review
+	err := r.db.WithContext(ctx).
+		Select("id", "rating", "title", "comment").
+		Where("product_id = ? AND is_approved = ?", productID, true).
+		Find(&reviews).Error
+	return reviews, err
+}
+
 func (r *reviewRepository) GetByUserID(ctx context.Context, userID uint, limit, offset int) ([]*models.Review, error) {
 	var reviews []*models.Review
 	err := r.db.WithContext(ctx).
@@ -55,6 +68,47 @@ func (r *reviewRepository) GetByUserID(ctx context.Context, userID uint, limit,
 	return reviews, err
 }
 
+// GetBySellerID returns the approved reviews of the seller's products, newest
+// first, with the total matching the filter
+func (r *reviewRepository) GetBySellerID(ctx context.Context, sellerID uint, filter *models.SellerReviewFilter, limit, offset int) ([]*models.Review, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Review{}).
+		Joins("JOIN products ON products.id = reviews.product_id").
+		Where("products.seller_id = ? AND reviews.is_approved = ?", sellerID, true)
+
+	if filter.Rating != nil {
+		query = query.Where("reviews.rating = ?", *filter.Rating)
+	}
+	if filter.HasResponse != nil {
+		if *filter.HasResponse {
+			query = query.Where("COALESCE(reviews.seller_response, '') <> ''")
+		} else {
+			query = query.Where("COALESCE(reviews.seller_response, '') = ''")
+		}
+	}
+	if filter.DateFrom != nil {
+		query = query.Where("reviews.created_at >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		query = query.Where("reviews.created_at < ?", *filter.DateTo)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []*models.Review
+	err := query.
+		Preload("User").
+		Preload("Product").
+		Order("reviews.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reviews).Error
+	return reviews, total, err
+}
+
 func (r *reviewRepository) GetByRating(ctx context.Context, rating int, limit, offset int) ([]*models.Review, error) {
 	var reviews []*models.Review
 	err := r.db.WithContext(ctx).
@@ -72,6 +126,26 @@ func (r *reviewRepository) Update(ctx context.Context, review *models.Review) er
 	return r.db.WithContext(ctx).Save(review).Error
 }
 
+// UpdateWithEdit saves the review and records its previous content in one transaction
+func (r *reviewRepository) UpdateWithEdit(ctx context.Context, review *models.Review, edit *models.ReviewEdit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(edit).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Save(review).Error
+	})
+}
+
+// GetEdits returns a review's edit history, oldest first
+func (r *reviewRepository) GetEdits(ctx context.Context, reviewID uint) ([]*models.ReviewEdit, error) {
+	var edits []*models.ReviewEdit
+	err := r.db.WithContext(ctx).
+		Where("review_id = ?", reviewID).
+		Order("created_at ASC").
+		Find(&edits).Error
+	return edits, err
+}
+
 func (r *reviewRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&models.Review{}, id).Error
 }
@@ -89,6 +163,23 @@ func (r *reviewRepository) GetByUserAndProduct(ctx context.Context, userID, prod
 	return &review, nil
 }
 
+// GetByUserProductAndOrder returns the user's review of the product from the
+// order, or their review not tied to an order when orderID is nil
+func (r *reviewRepository) GetByUserProductAndOrder(ctx context.Context, userID, productID uint, orderID *uint) (*models.Review, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ? AND product_id = ?", userID, productID)
+	if orderID != nil {
+		query = query.Where("order_id = ?", *orderID)
+	} else {
+		query = query.Where("order_id IS NULL")
+	}
+
+	var review models.Review
+	if err := query.First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
 func (r *reviewRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.Review{}).Count(&count).Error
@@ -99,7 +190,7 @@ func (r *reviewRepository) CountByProductID(ctx context.Context, productID uint)
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&models.Review{}).
-		Where("product_id = ?", productID).
+		Where("product_id = ? AND is_approved = ?", productID, true).
 		Count(&count).Error
 	return count, err
 }
@@ -117,7 +208,7 @@ func (r *reviewRepository) GetAverageRatingByProductID(ctx context.Context, prod
 	var avgRating float64
 	err := r.db.WithContext(ctx).
 		Model(&models.Review{}).
-		Where("product_id = ?", productID).
+		Where("product_id = ? AND is_approved = ?", productID, true).
 		Select("COALESCE(AVG(rating), 0)").
 		Scan(&avgRating).Error
 	return avgRating, err
@@ -132,7 +223,7 @@ func (r *reviewRepository) GetRatingDistribution(ctx context.Context, productID
 	var results []RatingCount
 	err := r.db.WithContext(ctx).
 		Model(&models.Review{}).
-		Where("product_id = ?", productID).
+		Where("product_id = ? AND is_approved = ?", productID, true).
 		Select("rating, COUNT(*) as count").
 		Group("rating").
 		Order("rating").
@@ -179,9 +270,156 @@ func (r *reviewRepository) CheckUserCanReview(ctx context.Context, userID, produ
 	err := r.db.WithContext(ctx).
 		Model(&models.Order{}).
 		Joins("JOIN order_items ON orders.id = order_items.order_id").
-		Where("orders.user_id = ? AND order_items.product_id = ? AND orders.status = ?",
+		Where("orders.customer_id = ? AND order_items.product_id = ? AND orders.status = ?",
 			userID, productID, models.OrderStatusDelivered).
 		Count(&count).Error
 
 	return count > 0, err
 }
+
+// CountDeliveredPurchases counts the user's orders that delivered the product,
+// counting a split order once its seller's part has been delivered
+func (r *reviewRepository) CountDeliveredPurchases(ctx context.Context, userID, productID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Joins("JOIN order_items ON orders.id = order_items.order_id AND order_items.deleted_at IS NULL").
+		Joins("LEFT JOIN order_fulfillments ON order_fulfillments.order_id = orders.id AND order_fulfillments.seller_id = order_items.seller_id AND order_fulfillments.deleted_at IS NULL").
+		Where("orders.customer_id = ? AND order_items.product_id = ?", userID, productID).
+		Where("orders.status = ? OR order_fulfillments.delivered_at IS NOT NULL", models.OrderStatusDelivered).
+		Distinct("orders.id").
+		Count(&count).Error
+	return count, err
+}
+
+// CreateWithinPurchases creates the review unless the user already has a
+// review of the product for each of their purchases of it, reviews not tied to
+// an order included. The user's row is locked so concurrent reviews are counted
+// in turn.
+func (r *reviewRepository) CreateWithinPurchases(ctx context.Context, review *models.Review, purchases int64) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&user, review.UserID).Error; err != nil {
+			return err
+		}
+
+		var reviewed int64
+		if err := tx.Model(&models.Review{}).
+			Where("user_id = ? AND product_id = ?", review.UserID, review.ProductID).
+			Count(&reviewed).Error; err != nil {
+			return err
+		}
+		if reviewed >= purchases {
+			return nil
+		}
+
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+// BulkModerate applies action to each review in one transaction. Each review
+// runs under its own savepoint so a failure rolls back only that item; every
+// successful change is recorded in the audit log. It returns the per-item
+// results and the IDs of products whose reviews changed.
+func (r *reviewRepository) BulkModerate(ctx context.Context, reviewIDs []uint, action models.ModerationAction, actorID uint) ([]models.ModerationItemResult, []uint, error) {
+	results := make([]models.ModerationItemResult, 0, len(reviewIDs))
+	affected := make(map[uint]bool)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range reviewIDs {
+			savepoint := fmt.Sprintf("moderate_%d", i)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return err
+			}
+
+			productID, err := moderateReview(tx, id, action, actorID)
+			if err != nil {
+				if rbErr := tx.RollbackTo(savepoint).Error; rbErr != nil {
+					return rbErr
+				}
+				results = append(results, models.ModerationItemResult{ReviewID: id, Error: err.Error()})
+				continue
+			}
+
+			affected[productID] = true
+			results = append(results, models.ModerationItemResult{ReviewID: id, Success: true})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	productIDs := make([]uint, 0, len(affected))
+	for productID := range affected {
+		productIDs = append(productIDs, productID)
+	}
+	return results, productIDs, nil
+}
+
+// moderateReview applies a single moderation action and writes its audit entry
+func moderateReview(tx *gorm.DB, id uint, action models.ModerationAction, actorID uint) (uint, error) {
+	var review models.Review
+	if err := tx.Select("id", "product_id", "is_approved").First(&review, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.New("review not found")
+		}
+		return 0, err
+	}
+
+	var err error
+	switch action {
+	case models.ModerationActionApprove:
+		err = tx.Model(&models.Review{}).Where("id = ?", id).Update("is_approved", true).Error
+	case models.ModerationActionReject:
+		err = tx.Model(&models.Review{}).Where("id = ?", id).Update("is_approved", false).Error
+	case models.ModerationActionDelete:
+		err = tx.Delete(&models.Review{}, id).Error
+	default:
+		return 0, fmt.Errorf("unsupported moderation action: %s", action)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	entry := &models.AuditLog{
+		ActorID:    actorID,
+		Action:     "review." + string(action),
+		EntityType: "review",
+		EntityID:   id,
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return 0, err
+	}
+
+	return review.ProductID, nil
+}
+
+// reviewSortOrder returns the ORDER BY for a review sort, newest first for
+// anything else. Ties fall back to newest, then ID, so pages don't shuffle.
+func reviewSortOrder(sortBy models.ReviewSort) string {
+	switch sortBy {
+	case models.ReviewSortOldest:
+		return "reviews.created_at ASC, reviews.id ASC"
+	case models.ReviewSortRatingHigh:
+		return "reviews.rating DESC, reviews.created_at DESC, reviews.id DESC"
+	case models.ReviewSortRatingLow:
+		return "reviews.rating ASC, reviews.created_at DESC, reviews.id DESC"
+	case models.ReviewSortMostHelpful:
+		// A review one shopper found helpful shouldn't outrank one many have
+		// voted on, so reviews short of the minimum votes come after the rest
+		return fmt.Sprintf(`CASE WHEN reviews.helpful_count + reviews.not_helpful_count >= %d THEN 0 ELSE 1 END,
+			reviews.helpful_count DESC, reviews.not_helpful_count ASC, reviews.created_at DESC, reviews.id DESC`,
+			models.ReviewHelpfulMinVotes)
+	default:
+		return "reviews.created_at DESC, reviews.id DESC"
+	}
+}
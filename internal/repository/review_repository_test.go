@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+func TestGetBySellerIDListsApprovedReviewsOfSellersProducts(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewReviewRepository(db)
+
+	seller := createTestUser(t, db, "review-seller@example.com")
+	other := createTestUser(t, db, "review-other-seller@example.com")
+	product := createTestProduct(t, db, seller.ID, "REVIEW-SELLER-1", 5)
+	otherProduct := createTestProduct(t, db, other.ID, "REVIEW-SELLER-2", 5)
+
+	response := "Thanks for the feedback"
+	reviews := []*models.Review{
+		{ProductID: product.ID, Rating: 2, Comment: "Broke after a week of use"},
+		{ProductID: product.ID, Rating: 5, Comment: "Exactly what I needed", SellerResponse: &response},
+		{ProductID: product.ID, Rating: 1, Comment: "Hidden by the moderators"},
+		{ProductID: otherProduct.ID, Rating: 4, Comment: "Another seller's product"},
+	}
+	for i, review := range reviews {
+		review.UserID = createTestUser(t, db, fmt.Sprintf("reviewer-%d@example.com", i)).ID
+		if err := db.Create(review).Error; err != nil {
+			t.Fatalf("failed to create review: %v", err)
+		}
+	}
+	if err := db.Model(reviews[2]).Update("is_approved", false).Error; err != nil {
+		t.Fatalf("failed to unapprove review: %v", err)
+	}
+
+	got, total, err := repo.GetBySellerID(ctx, seller.ID, &models.SellerReviewFilter{}, 10, 0)
+	if err != nil {
+		t.Fatalf("GetBySellerID: %v", err)
+	}
+	if total != 2 || len(got) != 2 {
+		t.Fatalf("got %d reviews (total %d), want the seller's 2 approved reviews", len(got), total)
+	}
+
+	awaiting := false
+	got, total, err = repo.GetBySellerID(ctx, seller.ID, &models.SellerReviewFilter{HasResponse: &awaiting}, 10, 0)
+	if err != nil {
+		t.Fatalf("GetBySellerID: %v", err)
+	}
+	if total != 1 || len(got) != 1 || got[0].ID != reviews[0].ID {
+		t.Errorf("got %d reviews (total %d), want only the unanswered review", len(got), total)
+	}
+
+	rating := 5
+	got, total, err = repo.GetBySellerID(ctx, seller.ID, &models.SellerReviewFilter{Rating: &rating}, 10, 0)
+	if err != nil {
+		t.Fatalf("GetBySellerID: %v", err)
+	}
+	if total != 1 || len(got) != 1 || got[0].ID != reviews[1].ID {
+		t.Errorf("got %d reviews (total %d), want only the 5-star review", len(got), total)
+	}
+}
+
+// Reviews tied on every sort key must still page without repeats or gaps
+func TestGetByProductIDPagesTiedReviewsStably(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewReviewRepository(db)
+
+	seller := createTestUser(t, db, "tied-seller@example.com")
+	product := createTestProduct(t, db, seller.ID, "REVIEW-TIED-1", 5)
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < 7; i++ {
+		review := &models.Review{
+			ProductID:    product.ID,
+			UserID:       createTestUser(t, db, fmt.Sprintf("tied-reviewer-%d@example.com", i)).ID,
+			Rating:       4,
+			Comment:      "Does what it says on the box",
+			HelpfulCount: 2,
+		}
+		review.CreatedAt = createdAt
+		if err := db.Create(review).Error; err != nil {
+			t.Fatalf("failed to create review: %v", err)
+		}
+	}
+
+	sorts := []models.ReviewSort{models.ReviewSortNewest, models.ReviewSortOldest, models.ReviewSortRatingHigh, models.ReviewSortRatingLow, models.ReviewSortMostHelpful}
+	for _, sortBy := range sorts {
+		seen := make(map[uint]bool)
+		for offset := 0; offset < 7; offset += 3 {
+			page, err := repo.GetByProductID(ctx, product.ID, sortBy, 3, offset)
+			if err != nil {
+				t.Fatalf("GetByProductID(%s): %v", sortBy, err)
+			}
+			for _, review := range page {
+				if seen[review.ID] {
+					t.Errorf("%s: review %d appeared on two pages", sortBy, review.ID)
+				}
+				seen[review.ID] = true
+			}
+		}
+		if len(seen) != 7 {
+			t.Errorf("%s: paged through %d reviews, want 7", sortBy, len(seen))
+		}
+	}
+}
+
+func TestGetByProductIDMostHelpfulNeedsMinimumVotes(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewReviewRepository(db)
+
+	seller := createTestUser(t, db, "helpful-seller@example.com")
+	product := createTestProduct(t, db, seller.ID, "REVIEW-HELPFUL-1", 5)
+	voted := &models.Review{ProductID: product.ID, UserID: createTestUser(t, db, "voted@example.com").ID, Rating: 3, Comment: "Solid, if a little loud", HelpfulCount: 2, NotHelpfulCount: 2}
+	voted.CreatedAt = time.Now().Add(-48 * time.Hour)
+	newer := &models.Review{ProductID: product.ID, UserID: createTestUser(t, db, "newer@example.com").ID, Rating: 5, Comment: "Love it, works great", HelpfulCount: 2}
+	for _, review := range []*models.Review{voted, newer} {
+		if err := db.Create(review).Error; err != nil {
+			t.Fatalf("failed to create review: %v", err)
+		}
+	}
+
+	reviews, err := repo.GetByProductID(ctx, product.ID, models.ReviewSortMostHelpful, 10, 0)
+	if err != nil {
+		t.Fatalf("GetByProductID: %v", err)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("got %d reviews, want 2", len(reviews))
+	}
+	// Equally helpful, and newer with fewer not helpful votes, but short of
+	// the minimum votes
+	if reviews[0].ID != voted.ID {
+		t.Errorf("most helpful put review %d first, want the well-voted review %d", reviews[0].ID, voted.ID)
+	}
+}
+
+// One purchase allows one review, whether or not it names the order
+func TestCreateWithinPurchasesCountsReviewsWithoutAnOrder(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewReviewRepository(db)
+
+	seller := createTestUser(t, db, "purchase-seller@example.com")
+	customer := createTestUser(t, db, "purchase-customer@example.com")
+	product := createTestProduct(t, db, seller.ID, "REVIEW-PURCHASE-1", 5)
+	order := createTestOrder(t, db, customer.ID, "ORD-REVIEW-PURCHASE-1", models.OrderStatusDelivered)
+	item := &models.OrderItem{OrderID: order.ID, ProductID: product.ID, SellerID: seller.ID, Quantity: 1, UnitPrice: 10, TotalPrice: 10, ProductName: product.Name, ProductSKU: product.SKU}
+	if err := db.Create(item).Error; err != nil {
+		t.Fatalf("failed to create order item: %v", err)
+	}
+
+	purchases, err := repo.CountDeliveredPurchases(ctx, customer.ID, product.ID)
+	if err != nil || purchases != 1 {
+		t.Fatalf("CountDeliveredPurchases = %d, %v, want 1", purchases, err)
+	}
+
+	withoutOrder := &models.Review{ProductID: product.ID, UserID: customer.ID, Rating: 4, Comment: "Reviewed without the order"}
+	if created, err := repo.CreateWithinPurchases(ctx, withoutOrder, purchases); err != nil || !created {
+		t.Fatalf("CreateWithinPurchases = %v, %v", created, err)
+	}
+
+	withOrder := &models.Review{ProductID: product.ID, UserID: customer.ID, OrderID: &order.ID, Rating: 5, Comment: "Reviewed again from the order"}
+	created, err := repo.CreateWithinPurchases(ctx, withOrder, purchases)
+	if err != nil {
+		t.Fatalf("CreateWithinPurchases: %v", err)
+	}
+	if created {
+		t.Error("created a second review for a single purchase")
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type searchLogRepository struct {
+	db *gorm.DB
+}
+
+type SearchLogRepository interface {
+	CreateBatch(ctx context.Context, logs []models.SearchLog) error
+	CountSearches(ctx context.Context, startDate, endDate time.Time, zeroResultsOnly bool) (int64, error)
+	GetTopQueries(ctx context.Context, startDate, endDate time.Time, zeroResultsOnly bool, limit int) ([]models.SearchQueryStat, error)
+}
+
+func NewSearchLogRepository(db *gorm.DB) SearchLogRepository {
+	return &searchLogRepository{db: db}
+}
+
+func (r *searchLogRepository) CreateBatch(ctx context.Context, logs []models.SearchLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(logs, 100).Error
+}
+
+func (r *searchLogRepository) CountSearches(ctx context.Context, startDate, endDate time.Time, zeroResultsOnly bool) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).
+		Model(&models.SearchLog{}).
+		Where("created_at BETWEEN ? AND ?", startDate, endDate)
+
+	if zeroResultsOnly {
+		query = query.Where("result_count = 0")
+	}
+
+	err := query.Count(&count).Error
+	return count, err
+}
+
+func (r *searchLogRepository) GetTopQueries(ctx context.Context, startDate, endDate time.Time, zeroResultsOnly bool, limit int) ([]models.SearchQueryStat, error) {
+	var stats []models.SearchQueryStat
+	query := r.db.WithContext(ctx).
+		Model(&models.SearchLog{}).
+		Select("query, COUNT(*) AS count").
+		Where("created_at BETWEEN ? AND ?", startDate, endDate)
+
+	if zeroResultsOnly {
+		query = query.Where("result_count = 0")
+	}
+
+	err := query.
+		Group("query").
+		Order("count DESC, query ASC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type shippingZoneRepository struct {
+	db *gorm.DB
+}
+
+type ShippingZoneRepository interface {
+	Create(ctx context.Context, zone *models.ShippingZone) error
+	GetByID(ctx context.Context, id uint) (*models.ShippingZone, error)
+	GetAll(ctx context.Context) ([]*models.ShippingZone, error)
+	Update(ctx context.Context, zone *models.ShippingZone) error
+	Delete(ctx context.Context, id uint) error
+}
+
+func NewShippingZoneRepository(db *gorm.DB) ShippingZoneRepository {
+	return &shippingZoneRepository{db: db}
+}
+
+// Create saves the zone with its rates. A new default zone replaces the old one.
+func (r *shippingZoneRepository) Create(ctx context.Context, zone *models.ShippingZone) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultShippingZone(tx, zone); err != nil {
+			return err
+		}
+		return tx.Create(zone).Error
+	})
+}
+
+func (r *shippingZoneRepository) GetByID(ctx context.Context, id uint) (*models.ShippingZone, error) {
+	var zone models.ShippingZone
+	err := r.db.WithContext(ctx).
+		Preload("Rates", func(db *gorm.DB) *gorm.DB { return db.Order("min_weight ASC") }).
+		First(&zone, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// GetAll returns every zone with its rates in bracket order
+func (r *shippingZoneRepository) GetAll(ctx context.Context) ([]*models.ShippingZone, error) {
+	var zones []*models.ShippingZone
+	err := r.db.WithContext(ctx).
+		Preload("Rates", func(db *gorm.DB) *gorm.DB { return db.Order("min_weight ASC") }).
+		Order("name ASC").
+		Find(&zones).Error
+	return zones, err
+}
+
+// Update saves the zone and replaces its rate table with zone.Rates
+func (r *shippingZoneRepository) Update(ctx context.Context, zone *models.ShippingZone) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultShippingZone(tx, zone); err != nil {
+			return err
+		}
+		if err := tx.Where("zone_id = ?", zone.ID).Delete(&models.ShippingRate{}).Error; err != nil {
+			return err
+		}
+		for i := range zone.Rates {
+			zone.Rates[i].ID = 0
+			zone.Rates[i].ZoneID = zone.ID
+		}
+		if len(zone.Rates) > 0 {
+			if err := tx.Create(&zone.Rates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Rates").Save(zone).Error
+	})
+}
+
+func (r *shippingZoneRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("zone_id = ?", id).Delete(&models.ShippingRate{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ShippingZone{}, id).Error
+	})
+}
+
+// clearDefaultShippingZone unsets the current default when zone becomes it,
+// so there is only ever one
+func clearDefaultShippingZone(tx *gorm.DB, zone *models.ShippingZone) error {
+	if !zone.IsDefault {
+		return nil
+	}
+	query := tx.Model(&models.ShippingZone{}).Where("is_default = ?", true)
+	if zone.ID != 0 {
+		query = query.Where("id <> ?", zone.ID)
+	}
+	return query.Update("is_default", false).Error
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockReservationRepository struct {
+	db *gorm.DB
+}
+
+type StockReservationRepository interface {
+	CreateBatch(ctx context.Context, reservations []models.StockReservation) error
+	CountByOrderID(ctx context.Context, orderID uint, status models.ReservationStatus) (int64, error)
+	HasAny(ctx context.Context, orderID uint) (bool, error)
+	Commit(ctx context.Context, orderID uint) (int64, error)
+	Release(ctx context.Context, orderID uint, statuses ...models.ReservationStatus) ([]models.StockReservation, error)
+	ExtendExpiry(ctx context.Context, orderID uint, expiresAt time.Time) error
+	ExtendActive(ctx context.Context, orderID uint, now, expiresAt time.Time) (int64, error)
+	GetActiveByCustomer(ctx context.Context, customerID uint, now time.Time) ([]models.StockReservation, error)
+	GetExpiredOrderIDs(ctx context.Context, now time.Time, limit int) ([]uint, error)
+}
+
+func NewStockReservationRepository(db *gorm.DB) StockReservationRepository {
+	return &stockReservationRepository{db: db}
+}
+
+func (r *stockReservationRepository) CreateBatch(ctx context.Context, reservations []models.StockReservation) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&reservations).Error
+}
+
+func (r *stockReservationRepository) CountByOrderID(ctx context.Context, orderID uint, status models.ReservationStatus) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.StockReservation{}).
+		Where("order_id = ? AND status = ?", orderID, status).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *stockReservationRepository) HasAny(ctx context.Context, orderID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.StockReservation{}).
+		Where("order_id = ?", orderID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Commit marks the order's reserved stock as sold and returns how many reservations it committed
+func (r *stockReservationRepository) Commit(ctx context.Context, orderID uint) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.StockReservation{}).
+		Where("order_id = ? AND status = ?", orderID, models.ReservationStatusReserved).
+		Update("status", models.ReservationStatusCommitted)
+	return result.RowsAffected, result.Error
+}
+
+// Release marks the order's reservations in the given statuses as released and
+// returns the rows it flipped. Rows are locked so a concurrent release (e.g. the
+// sweeper racing a cancel) can't return the same stock twice.
+func (r *stockReservationRepository) Release(ctx context.Context, orderID uint, statuses ...models.ReservationStatus) ([]models.StockReservation, error) {
+	var released []models.StockReservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ? AND status IN ?", orderID, statuses).
+			Find(&released).Error; err != nil {
+			return err
+		}
+		if len(released) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(released))
+		for i, reservation := range released {
+			ids[i] = reservation.ID
+		}
+		return tx.Model(&models.StockReservation{}).
+			Where("id IN ?", ids).
+			Update("status", models.ReservationStatusReleased).Error
+	})
+	return released, err
+}
+
+func (r *stockReservationRepository) ExtendExpiry(ctx context.Context, orderID uint, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.StockReservation{}).
+		Where("order_id = ? AND status = ?", orderID, models.ReservationStatusReserved).
+		Update("expires_at", expiresAt).Error
+}
+
+// ExtendActive pushes the expiry of the order's reservations still live at now
+// out to expiresAt, never bringing one forward. It returns how many it
+// extended; none means the sweeper may already be releasing them.
+func (r *stockReservationRepository) ExtendActive(ctx context.Context, orderID uint, now, expiresAt time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.StockReservation{}).
+		Where("order_id = ? AND status = ? AND expires_at > ?", orderID, models.ReservationStatusReserved, now).
+		Update("expires_at", gorm.Expr("GREATEST(expires_at, ?)", expiresAt))
+	return result.RowsAffected, result.Error
+}
+
+// GetActiveByCustomer returns the live reservations of the customer's unpaid
+// orders, soonest to expire first
+func (r *stockReservationRepository) GetActiveByCustomer(ctx context.Context, customerID uint, now time.Time) ([]models.StockReservation, error) {
+	var reservations []models.StockReservation
+	err := r.db.WithContext(ctx).
+		Joins("JOIN orders ON orders.id = stock_reservations.order_id").
+		Where("orders.customer_id = ? AND orders.status = ?", customerID, models.OrderStatusPending).
+		Where("stock_reservations.status = ? AND stock_reservations.expires_at > ?", models.ReservationStatusReserved, now).
+		Order("stock_reservations.expires_at ASC").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+// GetExpiredOrderIDs returns pending orders whose reservations have expired.
+// Orders held for fraud review keep their stock until an admin decides.
+func (r *stockReservationRepository) GetExpiredOrderIDs(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	var orderIDs []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.StockReservation{}).
+		Joins("JOIN orders ON orders.id = stock_reservations.order_id").
+		Where("stock_reservations.status = ? AND stock_reservations.expires_at < ?", models.ReservationStatusReserved, now).
+		Where("orders.status = ?", models.OrderStatusPending).
+		Distinct("stock_reservations.order_id").
+		Limit(limit).
+		Pluck("stock_reservations.order_id", &orderIDs).Error
+	return orderIDs, err
+}
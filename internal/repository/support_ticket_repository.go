@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type supportTicketRepository struct {
+	db *gorm.DB
+}
+
+type SupportTicketRepository interface {
+	Create(ctx context.Context, ticket *models.SupportTicket) error
+	GetByID(ctx context.Context, id uint) (*models.SupportTicket, error)
+	GetByUser(ctx context.Context, userID uint, limit, offset int) ([]*models.SupportTicket, int64, error)
+	List(ctx context.Context, status *models.SupportTicketStatus, limit, offset int) ([]*models.SupportTicket, int64, error)
+	AddMessage(ctx context.Context, message *models.SupportTicketMessage, status models.SupportTicketStatus) error
+}
+
+func NewSupportTicketRepository(db *gorm.DB) SupportTicketRepository {
+	return &supportTicketRepository{db: db}
+}
+
+func (r *supportTicketRepository) Create(ctx context.Context, ticket *models.SupportTicket) error {
+	return r.db.WithContext(ctx).Create(ticket).Error
+}
+
+// GetByID returns the ticket with its linked order and its messages, oldest first
+func (r *supportTicketRepository) GetByID(ctx context.Context, id uint) (*models.SupportTicket, error) {
+	var ticket models.SupportTicket
+	err := r.db.WithContext(ctx).
+		Preload("Order").
+		Preload("Messages", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&ticket, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// GetByUser returns a customer's tickets, most recently updated first
+func (r *supportTicketRepository) GetByUser(ctx context.Context, userID uint, limit, offset int) ([]*models.SupportTicket, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.SupportTicket{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tickets []*models.SupportTicket
+	err := query.
+		Order("updated_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&tickets).Error
+	return tickets, total, err
+}
+
+// List returns tickets, oldest first so staff work the longest-waiting ones,
+// optionally filtered by status
+func (r *supportTicketRepository) List(ctx context.Context, status *models.SupportTicketStatus, limit, offset int) ([]*models.SupportTicket, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.SupportTicket{})
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tickets []*models.SupportTicket
+	err := query.
+		Order("updated_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&tickets).Error
+	return tickets, total, err
+}
+
+// AddMessage adds a reply to the ticket's thread and moves the ticket to status
+func (r *supportTicketRepository) AddMessage(ctx context.Context, message *models.SupportTicketMessage, status models.SupportTicketStatus) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.SupportTicket{}).
+			Where("id = ?", message.TicketID).
+			Update("status", status).Error
+	})
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB returns a database with the schema built from the SQL migrations
+// in a throwaway Postgres schema, dropped when the test ends. Tests using it
+// are skipped unless TEST_DATABASE_URL points at a Postgres database, e.g.
+// "host=localhost port=5433 user=postgres password=... dbname=ecommerce_test sslmode=disable".
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to test schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.sql"))
+	if err != nil {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		if err := db.Exec(string(sql)).Error; err != nil {
+			t.Fatalf("failed to run %s: %v", filepath.Base(file), err)
+		}
+	}
+
+	return db
+}
+
+// createTestUser inserts an active customer
+func createTestUser(t *testing.T, db *gorm.DB, email string) *models.User {
+	t.Helper()
+	user := &models.User{
+		FirstName: "Test",
+		LastName:  "User",
+		Email:     email,
+		Password:  "not-a-real-hash",
+		Role:      models.RoleCustomer,
+		IsActive:  true,
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
+
+// createTestProduct inserts an active product with the given stock
+func createTestProduct(t *testing.T, db *gorm.DB, sellerID uint, sku string, stock int) *models.Product {
+	t.Helper()
+	product := &models.Product{
+		Name:          "Test product " + sku,
+		Description:   "A product for tests",
+		SKU:           sku,
+		Slug:          sku,
+		Price:         10,
+		Stock:         stock,
+		StockQuantity: stock,
+		Category:      string(models.CategoryOther),
+		SellerID:      sellerID,
+		IsActive:      true,
+		Returnable:    true,
+	}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+	return product
+}
+
+// createTestOrder inserts an order for the customer in the given status
+func createTestOrder(t *testing.T, db *gorm.DB, customerID uint, number string, status models.OrderStatus) *models.Order {
+	t.Helper()
+	order := &models.Order{
+		OrderNumber:        number,
+		CustomerID:         customerID,
+		Status:             status,
+		TotalAmount:        10,
+		SubtotalAmount:     10,
+		ShippingFirstName:  "Test",
+		ShippingLastName:   "User",
+		ShippingStreet:     "1 Main St",
+		ShippingCity:       "Springfield",
+		ShippingState:      "IL",
+		ShippingCountry:    "US",
+		ShippingPostalCode: "62701",
+		PaymentMethod:      models.PaymentMethodCard,
+	}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+	return order
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
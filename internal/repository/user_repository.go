@@ -71,6 +71,16 @@ func (r *userRepository) List(ctx context.Context, page, limit int, role *models
 	return users, total, nil
 }
 
+// GetStaff returns the seller's staff accounts, including deactivated ones
+func (r *userRepository) GetStaff(ctx context.Context, employerID uint) ([]models.User, error) {
+	var staff []models.User
+	err := r.db.WithContext(ctx).
+		Where("role = ? AND employer_id = ?", models.RoleSellerStaff, employerID).
+		Order("first_name ASC, last_name ASC").
+		Find(&staff).Error
+	return staff, err
+}
+
 func (r *userRepository) UpdateLastLogin(ctx context.Context, id uint) error {
 	now := time.Now()
 	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("last_login_at", now).Error
@@ -144,10 +154,26 @@ func (r *userRepository) GetPasswordResetToken(ctx context.Context, tokenStr str
 	return &token, nil
 }
 
+// MarkPasswordResetTokenUsed claims the token. It returns gorm.ErrRecordNotFound
+// if the token was already used, so two concurrent resets can't both succeed.
 func (r *userRepository) MarkPasswordResetTokenUsed(ctx context.Context, tokenStr string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.PasswordResetToken{}).
+		Where("token = ? AND used_at IS NULL", tokenStr).
+		Update("used_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) InvalidatePasswordResetTokens(ctx context.Context, userID uint) error {
 	return r.db.WithContext(ctx).
 		Model(&models.PasswordResetToken{}).
-		Where("token = ?", tokenStr).
+		Where("user_id = ? AND used_at IS NULL", userID).
 		Update("used_at", gorm.Expr("NOW()")).Error
 }
 
@@ -167,10 +193,26 @@ func (r *userRepository) GetEmailVerificationToken(ctx context.Context, tokenStr
 	return &token, nil
 }
 
+// MarkEmailVerificationTokenUsed claims the token. It returns gorm.ErrRecordNotFound
+// if the token was already used.
 func (r *userRepository) MarkEmailVerificationTokenUsed(ctx context.Context, tokenStr string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.EmailVerificationToken{}).
+		Where("token = ? AND used_at IS NULL", tokenStr).
+		Update("used_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) InvalidateEmailVerificationTokens(ctx context.Context, userID uint) error {
 	return r.db.WithContext(ctx).
 		Model(&models.EmailVerificationToken{}).
-		Where("token = ?", tokenStr).
+		Where("user_id = ? AND used_at IS NULL", userID).
 		Update("used_at", gorm.Expr("NOW()")).Error
 }
 
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestGetPasswordResetTokenSkipsExpiredAndUsed(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewUserRepository(db)
+	user := createTestUser(t, db, "reset-lookup@example.com")
+
+	for _, token := range []*models.PasswordResetToken{
+		{UserID: user.ID, Token: "expired", ExpiresAt: time.Now().Add(-time.Minute)},
+		{UserID: user.ID, Token: "valid", ExpiresAt: time.Now().Add(time.Hour)},
+	} {
+		if err := repo.CreatePasswordResetToken(ctx, token); err != nil {
+			t.Fatalf("CreatePasswordResetToken: %v", err)
+		}
+	}
+
+	if _, err := repo.GetPasswordResetToken(ctx, "expired"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expired token: err = %v, want ErrRecordNotFound", err)
+	}
+	if _, err := repo.GetPasswordResetToken(ctx, "valid"); err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+
+	if err := repo.MarkPasswordResetTokenUsed(ctx, "valid"); err != nil {
+		t.Fatalf("MarkPasswordResetTokenUsed: %v", err)
+	}
+	if _, err := repo.GetPasswordResetToken(ctx, "valid"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("used token: err = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestMarkPasswordResetTokenUsedClaimsOnce(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewUserRepository(db)
+	user := createTestUser(t, db, "reset-claim@example.com")
+
+	token := &models.PasswordResetToken{UserID: user.ID, Token: "claimed", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := repo.CreatePasswordResetToken(ctx, token); err != nil {
+		t.Fatalf("CreatePasswordResetToken: %v", err)
+	}
+
+	const attempts = 5
+	var wg sync.WaitGroup
+	results := make([]error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = repo.MarkPasswordResetTokenUsed(ctx, "claimed")
+		}(i)
+	}
+	wg.Wait()
+
+	claimed := 0
+	for _, err := range results {
+		switch {
+		case err == nil:
+			claimed++
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			t.Fatalf("MarkPasswordResetTokenUsed: %v", err)
+		}
+	}
+	if claimed != 1 {
+		t.Errorf("token claimed %d times, want 1", claimed)
+	}
+}
+
+func TestMarkEmailVerificationTokenUsedRejectsReuse(t *testing.T) {
+	db := openTestDB(t)
+	ctx := testContext(t)
+	repo := NewUserRepository(db)
+	user := createTestUser(t, db, "verify-claim@example.com")
+
+	token := &models.EmailVerificationToken{UserID: user.ID, Token: "verify", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := repo.CreateEmailVerificationToken(ctx, token); err != nil {
+		t.Fatalf("CreateEmailVerificationToken: %v", err)
+	}
+
+	if err := repo.MarkEmailVerificationTokenUsed(ctx, "verify"); err != nil {
+		t.Fatalf("first claim: %v", err)
+	}
+	if err := repo.MarkEmailVerificationTokenUsed(ctx, "verify"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("second claim: err = %v, want ErrRecordNotFound", err)
+	}
+}
@@ -16,6 +16,8 @@ type WishlistRepository interface {
 	GetByUser(ctx context.Context, userID uint) ([]models.Wishlist, error)
 	Remove(ctx context.Context, userID, productID uint) error
 	IsInWishlist(ctx context.Context, userID, productID uint) (bool, error)
+	GetWishlistedProductIDs(ctx context.Context, userID uint, productIDs []uint) ([]uint, error)
+	CountByUser(ctx context.Context, userID uint) (int64, error)
 	GetByUserAndProduct(ctx context.Context, userID, productID uint) (*models.Wishlist, error)
 }
 
@@ -53,6 +55,26 @@ func (r *wishlistRepository) IsInWishlist(ctx context.Context, userID, productID
 	return count > 0, err
 }
 
+// GetWishlistedProductIDs returns which of the given products are in the user's wishlist
+func (r *wishlistRepository) GetWishlistedProductIDs(ctx context.Context, userID uint, productIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.Wishlist{}).
+		Where("user_id = ? AND product_id IN ?", userID, productIDs).
+		Distinct().
+		Pluck("product_id", &ids).Error
+	return ids, err
+}
+
+func (r *wishlistRepository) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Wishlist{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *wishlistRepository) GetByUserAndProduct(ctx context.Context, userID, productID uint) (*models.Wishlist, error) {
 	var wishlist models.Wishlist
 	err := r.db.WithContext(ctx).
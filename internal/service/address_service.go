@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// maxAddressesPerUser caps the size of a user's address book
+const maxAddressesPerUser = 20
+
+type addressService struct {
+	addressRepo repository.AddressRepository
+}
+
+func NewAddressService(addressRepo repository.AddressRepository) AddressService {
+	return &addressService{addressRepo: addressRepo}
+}
+
+func (s *addressService) CreateAddress(ctx context.Context, userID uint, req *models.AddressRequest) (*models.Address, error) {
+	count, err := s.addressRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count addresses: %w", err)
+	}
+	if count >= maxAddressesPerUser {
+		return nil, ErrAddressBookFull
+	}
+
+	address := &models.Address{UserID: userID}
+	req.Apply(address)
+	// The first address becomes the default of both kinds
+	if count == 0 {
+		address.IsDefaultShipping = true
+		address.IsDefaultBilling = true
+	}
+
+	if err := s.addressRepo.Create(ctx, address); err != nil {
+		return nil, fmt.Errorf("failed to create address: %w", err)
+	}
+
+	return address, nil
+}
+
+func (s *addressService) GetAddresses(ctx context.Context, userID uint) ([]*models.Address, error) {
+	addresses, err := s.addressRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get addresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// GetAddress returns the user's address. Addresses of other users are
+// reported as not found.
+func (s *addressService) GetAddress(ctx context.Context, userID, addressID uint) (*models.Address, error) {
+	address, err := s.addressRepo.GetByID(ctx, addressID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAddressNotFound
+		}
+		return nil, fmt.Errorf("failed to get address: %w", err)
+	}
+
+	if address.UserID != userID {
+		return nil, ErrAddressNotFound
+	}
+
+	return address, nil
+}
+
+func (s *addressService) UpdateAddress(ctx context.Context, userID, addressID uint, req *models.AddressRequest) (*models.Address, error) {
+	address, err := s.GetAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(address)
+	if err := s.addressRepo.Update(ctx, address); err != nil {
+		return nil, fmt.Errorf("failed to update address: %w", err)
+	}
+
+	return address, nil
+}
+
+// DeleteAddress removes the user's address. Orders keep their own copy of the
+// address, so they're unaffected.
+func (s *addressService) DeleteAddress(ctx context.Context, userID, addressID uint) error {
+	if _, err := s.GetAddress(ctx, userID, addressID); err != nil {
+		return err
+	}
+
+	if err := s.addressRepo.Delete(ctx, addressID); err != nil {
+		return fmt.Errorf("failed to delete address: %w", err)
+	}
+
+	return nil
+}
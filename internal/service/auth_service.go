@@ -16,17 +16,21 @@ import (
 
 type authService struct {
 	userRepo   repository.UserRepository
+	emailSvc   EmailService
+	couponSvc  CouponService
 	jwtService *utils.JWTService
 	redis      *redis.Client
 	config     *config.Config
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo repository.UserRepository, cfg *config.Config, redisClient *redis.Client) AuthService {
+func NewAuthService(userRepo repository.UserRepository, emailSvc EmailService, couponSvc CouponService, cfg *config.Config, redisClient *redis.Client) AuthService {
 	jwtService := utils.NewJWTService(cfg.JWT.Secret, cfg.JWT.Expiry)
 	
 	return &authService{
 		userRepo:   userRepo,
+		emailSvc:   emailSvc,
+		couponSvc:  couponSvc,
 		jwtService: jwtService,
 		redis:      redisClient,
 		config:     cfg,
@@ -34,23 +38,33 @@ func NewAuthService(userRepo repository.UserRepository, cfg *config.Config, redi
 }
 
 func (s *authService) Register(ctx context.Context, req *models.RegisterRequest) (*models.AuthResponse, error) {
-	// Check if user already exists
+	// Check if user already exists. A guest record for the email is claimed
+	// instead, so the guest's orders move into the new account, but only by
+	// whoever can read that inbox.
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
-	if err == nil && existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+	if err == nil && existingUser != nil && !existingUser.IsGuest {
+		return nil, ErrEmailTaken
 	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
+	if existingUser != nil {
+		if err := s.verifyGuestClaim(ctx, existingUser, req.VerificationToken); err != nil {
+			return nil, err
+		}
+	}
 
-	// Create new user
-	user := &models.User{
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Email:     req.Email,
-		Role:      req.Role,
-		Phone:     req.Phone,
-		IsActive:  true,
+	// Create new user, or fill in the claimed guest record
+	user := existingUser
+	if user == nil {
+		user = &models.User{}
 	}
+	user.FirstName = req.FirstName
+	user.LastName = req.LastName
+	user.Email = req.Email
+	user.Role = req.Role
+	user.Phone = req.Phone
+	user.IsActive = true
+	user.IsGuest = false
 
 	// Validate password strength
 	if err := utils.ValidatePassword(req.Password); err != nil {
@@ -63,7 +77,12 @@ func (s *authService) Register(ctx context.Context, req *models.RegisterRequest)
 	}
 
 	// Save user
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	if user.ID != 0 {
+		err = s.userRepo.Update(ctx, user)
+	} else {
+		err = s.userRepo.Create(ctx, user)
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -76,30 +95,51 @@ func (s *authService) Register(ctx context.Context, req *models.RegisterRequest)
 	// Update last login
 	s.userRepo.UpdateLastLogin(ctx, user.ID)
 
+	s.welcome(ctx, user)
+
 	return &models.AuthResponse{
 		User:  user.ToResponse(),
 		Token: token,
 	}, nil
 }
 
+// welcome sends a newly registered user the welcome email, issuing customers
+// their welcome coupon first when those are enabled. Neither failing fails
+// the registration.
+func (s *authService) welcome(ctx context.Context, user *models.User) {
+	coupon, err := s.couponSvc.IssueWelcomeCoupon(ctx, user)
+	if err != nil {
+		fmt.Printf("Warning: failed to issue welcome coupon to user %d: %v\n", user.ID, err)
+	}
+
+	if err := s.emailSvc.SendWelcomeEmail(ctx, user, coupon); err != nil {
+		fmt.Printf("Warning: failed to send welcome email to user %d: %v\n", user.ID, err)
+	}
+}
+
 func (s *authService) Login(ctx context.Context, req *models.LoginRequest) (*models.AuthResponse, error) {
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid email or password")
+			return nil, ErrInvalidCredentials
 		}
 		return nil, err
 	}
 
+	// Guests have no password to sign in with until they register
+	if user.IsGuest {
+		return nil, ErrInvalidCredentials
+	}
+
 	// Check if user is active
 	if !user.IsActive {
-		return nil, errors.New("account is deactivated")
+		return nil, ErrAccountDeactivated
 	}
 
 	// Verify password
 	if err := user.CheckPassword(req.Password); err != nil {
-		return nil, errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
 	}
 
 	// Generate JWT token
@@ -146,7 +186,7 @@ func (s *authService) ChangePassword(ctx context.Context, userID uint, req *mode
 
 	// Verify current password
 	if err := user.CheckPassword(req.CurrentPassword); err != nil {
-		return errors.New("current password is incorrect")
+		return ErrIncorrectPassword
 	}
 
 	// Validate new password strength
@@ -177,6 +217,67 @@ func (s *authService) GetJWTService() *utils.JWTService {
 	return s.jwtService
 }
 
+// verifyGuestClaim redeems the verification token sent to a guest's email.
+// Without one, a token is sent and ErrGuestClaimUnverified tells the client to
+// register again with it.
+func (s *authService) verifyGuestClaim(ctx context.Context, guest *models.User, token string) error {
+	if token == "" {
+		verificationToken, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			return err
+		}
+		if err := s.userRepo.CreateEmailVerificationToken(ctx, &models.EmailVerificationToken{
+			UserID:    guest.ID,
+			Token:     verificationToken,
+			ExpiresAt: time.Now().Add(s.config.Auth.EmailVerificationTTL),
+		}); err != nil {
+			return err
+		}
+		if err := s.emailSvc.SendEmailVerificationEmail(ctx, guest, verificationToken); err != nil {
+			return fmt.Errorf("failed to send verification email: %w", err)
+		}
+		return ErrGuestClaimUnverified
+	}
+
+	verifyToken, err := s.userRepo.GetEmailVerificationToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+	if verifyToken.UserID != guest.ID || verifyToken.IsExpired() || verifyToken.IsUsed() {
+		return ErrInvalidToken
+	}
+
+	// Claim the token so it can only be redeemed once
+	if err := s.userRepo.MarkEmailVerificationTokenUsed(ctx, token); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+	guest.IsVerified = true
+
+	if err := s.userRepo.InvalidateEmailVerificationTokens(ctx, guest.ID); err != nil {
+		fmt.Printf("Warning: failed to invalidate verification tokens for user %d: %v\n", guest.ID, err)
+	}
+	return nil
+}
+
+// IsEmailAvailable reports whether no account is registered with the email.
+// Guest checkouts don't count, as registering claims them.
+func (s *authService) IsEmailAvailable(ctx context.Context, email string) (bool, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	return user.IsGuest, nil
+}
+
 // ForgotPassword initiates password reset process
 func (s *authService) ForgotPassword(ctx context.Context, email string) error {
 	// Check if user exists
@@ -199,7 +300,7 @@ func (s *authService) ForgotPassword(ctx context.Context, email string) error {
 	passwordResetToken := &models.PasswordResetToken{
 		UserID:    user.ID,
 		Token:     resetToken,
-		ExpiresAt: time.Now().Add(24 * time.Hour), // 24 hours
+		ExpiresAt: time.Now().Add(s.config.Auth.PasswordResetTTL),
 	}
 
 	if err := s.userRepo.CreatePasswordResetToken(ctx, passwordResetToken); err != nil {
@@ -219,16 +320,29 @@ func (s *authService) ResetPassword(ctx context.Context, token string, newPasswo
 	resetToken, err := s.userRepo.GetPasswordResetToken(ctx, token)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("invalid or expired token")
+			return ErrInvalidToken
 		}
 		return err
 	}
 
+	// The query filters these too, but don't rely on the lookup alone
+	if resetToken.IsExpired() || resetToken.IsUsed() {
+		return ErrInvalidToken
+	}
+
 	// Validate new password strength
 	if err := utils.ValidatePassword(newPassword); err != nil {
 		return fmt.Errorf("password validation failed: %w", err)
 	}
 
+	// Claim the token before changing the password so it can only be redeemed once
+	if err := s.userRepo.MarkPasswordResetTokenUsed(ctx, token); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
 	// Hash new password
 	if err := resetToken.User.HashPassword(newPassword); err != nil {
 		return err
@@ -239,9 +353,9 @@ func (s *authService) ResetPassword(ctx context.Context, token string, newPasswo
 		return err
 	}
 
-	// Mark token as used
-	if err := s.userRepo.MarkPasswordResetTokenUsed(ctx, token); err != nil {
-		return err
+	// Any other reset links sent to this user are now stale
+	if err := s.userRepo.InvalidatePasswordResetTokens(ctx, resetToken.UserID); err != nil {
+		fmt.Printf("Warning: failed to invalidate reset tokens for user %d: %v\n", resetToken.UserID, err)
 	}
 
 	return nil
@@ -253,7 +367,19 @@ func (s *authService) VerifyEmail(ctx context.Context, token string) error {
 	verifyToken, err := s.userRepo.GetEmailVerificationToken(ctx, token)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("invalid or expired token")
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	if verifyToken.IsExpired() || verifyToken.IsUsed() {
+		return ErrInvalidToken
+	}
+
+	// Claim the token so it can only be redeemed once
+	if err := s.userRepo.MarkEmailVerificationTokenUsed(ctx, token); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
 		}
 		return err
 	}
@@ -263,9 +389,8 @@ func (s *authService) VerifyEmail(ctx context.Context, token string) error {
 		return err
 	}
 
-	// Mark token as used
-	if err := s.userRepo.MarkEmailVerificationTokenUsed(ctx, token); err != nil {
-		return err
+	if err := s.userRepo.InvalidateEmailVerificationTokens(ctx, verifyToken.UserID); err != nil {
+		fmt.Printf("Warning: failed to invalidate verification tokens for user %d: %v\n", verifyToken.UserID, err)
 	}
 
 	return nil
@@ -277,14 +402,14 @@ func (s *authService) ResendVerification(ctx context.Context, email string) erro
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return err
 	}
 
 	// Check if already verified
 	if user.IsVerified {
-		return errors.New("email already verified")
+		return ErrEmailAlreadyVerified
 	}
 
 	// Generate verification token
@@ -297,7 +422,7 @@ func (s *authService) ResendVerification(ctx context.Context, email string) erro
 	emailVerificationToken := &models.EmailVerificationToken{
 		UserID:    user.ID,
 		Token:     verificationToken,
-		ExpiresAt: time.Now().Add(24 * time.Hour), // 24 hours
+		ExpiresAt: time.Now().Add(s.config.Auth.EmailVerificationTTL),
 	}
 
 	if err := s.userRepo.CreateEmailVerificationToken(ctx, emailVerificationToken); err != nil {
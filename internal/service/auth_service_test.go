@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+const newTestPassword = "N3w-Passw0rd!"
+
+func newTokenTestService(t *testing.T) (*authService, *fakeUserRepo) {
+	t.Helper()
+	user := &models.User{BaseModel: models.BaseModel{ID: 1}, Email: "ana@example.com", IsActive: true}
+	if err := user.HashPassword("Old-Passw0rd!"); err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	repo := newFakeUserRepo(user)
+	cfg := &config.Config{Auth: config.AuthConfig{PasswordResetTTL: time.Hour, EmailVerificationTTL: 24 * time.Hour}}
+	return NewAuthService(repo, nil, nil, cfg, nil).(*authService), repo
+}
+
+func TestForgotPasswordUsesConfiguredTTL(t *testing.T) {
+	svc, repo := newTokenTestService(t)
+	svc.config.Auth.PasswordResetTTL = 15 * time.Minute
+
+	if err := svc.ForgotPassword(context.Background(), "ana@example.com"); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	if len(repo.resetTokens) != 1 {
+		t.Fatalf("created %d reset tokens, want 1", len(repo.resetTokens))
+	}
+	for _, token := range repo.resetTokens {
+		if ttl := time.Until(token.ExpiresAt); ttl > 15*time.Minute || ttl < 14*time.Minute {
+			t.Errorf("token expires in %v, want 15m", ttl)
+		}
+	}
+}
+
+func TestResetPasswordRejectsExpiredToken(t *testing.T) {
+	svc, repo := newTokenTestService(t)
+	repo.CreatePasswordResetToken(context.Background(), &models.PasswordResetToken{
+		UserID: 1, Token: "expired", ExpiresAt: time.Now().Add(-time.Second),
+	})
+
+	err := svc.ResetPassword(context.Background(), "expired", newTestPassword)
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("err = %v, want ErrInvalidToken", err)
+	}
+	if repo.resetTokens["expired"].IsUsed() {
+		t.Error("expected the expired token not to be claimed")
+	}
+	if repo.users[1].CheckPassword(newTestPassword) == nil {
+		t.Error("expected the password to be unchanged")
+	}
+}
+
+func TestResetPasswordTokenCanOnlyBeUsedOnce(t *testing.T) {
+	svc, repo := newTokenTestService(t)
+	ctx := context.Background()
+	repo.CreatePasswordResetToken(ctx, &models.PasswordResetToken{
+		UserID: 1, Token: "once", ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	if err := svc.ResetPassword(ctx, "once", newTestPassword); err != nil {
+		t.Fatalf("first reset: %v", err)
+	}
+	if repo.users[1].CheckPassword(newTestPassword) != nil {
+		t.Error("expected the password to be changed")
+	}
+	if err := svc.ResetPassword(ctx, "once", "An0ther-Passw0rd!"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("reuse: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestConcurrentResetsClaimTokenOnce(t *testing.T) {
+	svc, repo := newTokenTestService(t)
+	ctx := context.Background()
+	repo.CreatePasswordResetToken(ctx, &models.PasswordResetToken{
+		UserID: 1, Token: "raced", ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	const attempts = 5
+	var wg sync.WaitGroup
+	results := make([]error, attempts)
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			results[i] = svc.ResetPassword(ctx, "raced", newTestPassword)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range results {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrInvalidToken):
+			t.Fatalf("ResetPassword: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d resets succeeded with one token, want 1", succeeded)
+	}
+}
+
+func TestVerifyEmailRejectsExpiredAndReusedTokens(t *testing.T) {
+	svc, repo := newTokenTestService(t)
+	ctx := context.Background()
+	repo.CreateEmailVerificationToken(ctx, &models.EmailVerificationToken{
+		UserID: 1, Token: "expired", ExpiresAt: time.Now().Add(-time.Second),
+	})
+	repo.CreateEmailVerificationToken(ctx, &models.EmailVerificationToken{
+		UserID: 1, Token: "valid", ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	if err := svc.VerifyEmail(ctx, "expired"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expired: err = %v, want ErrInvalidToken", err)
+	}
+	if repo.users[1].IsVerified {
+		t.Fatal("expected an expired token not to verify the email")
+	}
+
+	if err := svc.VerifyEmail(ctx, "valid"); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if !repo.users[1].IsVerified {
+		t.Error("expected the email to be verified")
+	}
+	if err := svc.VerifyEmail(ctx, "valid"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("reuse: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func newGuestClaimTestService(t *testing.T) (*authService, *fakeUserRepo, *recordingEmails) {
+	t.Helper()
+	guest := &models.User{BaseModel: models.BaseModel{ID: 2}, Email: "guest@example.com", IsActive: true, IsGuest: true}
+	repo := newFakeUserRepo(guest)
+	emails := &recordingEmails{}
+	cfg := &config.Config{
+		JWT:  config.JWTConfig{Secret: "test-secret", Expiry: time.Hour},
+		Auth: config.AuthConfig{EmailVerificationTTL: time.Hour},
+	}
+	coupons := NewCouponService(&fakeCouponRepo{}, nil, cfg)
+	return NewAuthService(repo, emails, coupons, cfg, nil).(*authService), repo, emails
+}
+
+func guestRegistration(token string) *models.RegisterRequest {
+	return &models.RegisterRequest{
+		FirstName: "Ana", LastName: "Lima", Email: "guest@example.com",
+		Password: newTestPassword, Role: models.RoleCustomer, VerificationToken: token,
+	}
+}
+
+func TestRegisterWithGuestEmailRequiresVerification(t *testing.T) {
+	svc, repo, emails := newGuestClaimTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.Register(ctx, guestRegistration(""))
+	if !errors.Is(err, ErrGuestClaimUnverified) {
+		t.Fatalf("err = %v, want ErrGuestClaimUnverified", err)
+	}
+	if !repo.users[2].IsGuest {
+		t.Fatal("expected the guest record not to be claimed without verification")
+	}
+	if len(emails.verificationTokens) != 1 {
+		t.Fatalf("sent %d verification emails, want 1", len(emails.verificationTokens))
+	}
+
+	resp, err := svc.Register(ctx, guestRegistration(emails.verificationTokens[0]))
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if resp.User.ID != 2 {
+		t.Errorf("registered user %d, want the guest record 2", resp.User.ID)
+	}
+	claimed := repo.users[2]
+	if claimed.IsGuest || !claimed.IsVerified {
+		t.Errorf("is_guest = %v, is_verified = %v; want a verified, claimed account", claimed.IsGuest, claimed.IsVerified)
+	}
+	if claimed.CheckPassword(newTestPassword) != nil {
+		t.Error("expected the registration password to be set")
+	}
+}
+
+func TestRegisterWithGuestEmailRejectsOtherUsersToken(t *testing.T) {
+	svc, repo, _ := newGuestClaimTestService(t)
+	ctx := context.Background()
+	repo.users[3] = &models.User{BaseModel: models.BaseModel{ID: 3}, Email: "other@example.com"}
+	repo.CreateEmailVerificationToken(ctx, &models.EmailVerificationToken{
+		UserID: 3, Token: "someone-elses", ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	if _, err := svc.Register(ctx, guestRegistration("someone-elses")); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("err = %v, want ErrInvalidToken", err)
+	}
+	if !repo.users[2].IsGuest {
+		t.Error("expected the guest record not to be claimed")
+	}
+	if repo.verifyTokens["someone-elses"].IsUsed() {
+		t.Error("expected the other user's token not to be redeemed")
+	}
+}
+
+func TestRegisterEmailsCustomersTheirWelcomeCoupon(t *testing.T) {
+	svc, _, emails := newGuestClaimTestService(t)
+	ctx := context.Background()
+	svc.config.Order.WelcomeCoupon = true
+	svc.config.Order.WelcomeCouponType = models.CouponTypeFixed
+	svc.config.Order.WelcomeCouponValue = 5
+
+	if _, err := svc.Register(ctx, guestRegistration("")); !errors.Is(err, ErrGuestClaimUnverified) {
+		t.Fatalf("err = %v, want ErrGuestClaimUnverified", err)
+	}
+	if len(emails.welcomed) != 0 {
+		t.Fatalf("sent %d welcome emails before registering, want none", len(emails.welcomed))
+	}
+
+	if _, err := svc.Register(ctx, guestRegistration(emails.verificationTokens[0])); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if len(emails.welcomeCoupons) != 1 || emails.welcomeCoupons[0] == nil {
+		t.Fatalf("welcome coupons emailed = %v, want one", emails.welcomeCoupons)
+	}
+	if coupon := emails.welcomeCoupons[0]; coupon.UserID == nil || *coupon.UserID != 2 || coupon.Value != 5 {
+		t.Errorf("coupon = %+v, want 5.00 off for user 2", coupon)
+	}
+}
@@ -3,29 +3,46 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
 	"gorm.io/gorm"
 )
 
 type cartService struct {
-	cartRepo    repository.CartRepository
-	productRepo repository.ProductRepository
+	cartRepo        repository.CartRepository
+	productRepo     repository.ProductRepository
+	reservationRepo repository.StockReservationRepository
+	shippingSvc     ShippingService
+	promotions      *PromotionEngine
+	minimums        *MinimumOrderPolicy
+	limits          config.CartConfig
 }
 
 
 
-func NewCartService(cartRepo repository.CartRepository, productRepo repository.ProductRepository) CartService {
+func NewCartService(cartRepo repository.CartRepository, productRepo repository.ProductRepository, reservationRepo repository.StockReservationRepository, shippingSvc ShippingService, promotions *PromotionEngine, minimums *MinimumOrderPolicy, limits config.CartConfig) CartService {
 	return &cartService{
-		cartRepo:    cartRepo,
-		productRepo: productRepo,
+		cartRepo:        cartRepo,
+		productRepo:     productRepo,
+		reservationRepo: reservationRepo,
+		shippingSvc:     shippingSvc,
+		promotions:      promotions,
+		minimums:        minimums,
+		limits:          limits,
 	}
 }
 
-func (s *cartService) AddToCart(ctx context.Context, userID uint, req *models.CartAddRequest) (*models.CartResponse, error) {
+func (s *cartService) AddToCart(ctx context.Context, userID uint, userRole models.UserRole, req *models.CartAddRequest) (*models.CartResponse, error) {
 	// Get or create cart
-	cart, err := s.cartRepo.GetOrCreateCart(ctx, userID)
+	if _, err := s.cartRepo.GetOrCreateCart(ctx, userID); err != nil {
+		return nil, err
+	}
+	cart, err := s.cartRepo.GetCartWithItems(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -34,44 +51,168 @@ func (s *cartService) AddToCart(ctx context.Context, userID uint, req *models.Ca
 	product, err := s.productRepo.GetByID(ctx, req.ProductID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, ErrProductNotFound
 		}
 		return nil, err
 	}
 
+	// Seasonal products can only be added within their available window
+	if !product.IsAvailableAt(time.Now()) {
+		return nil, ErrProductUnavailable
+	}
+
 	// Check if product is in stock
 	if product.Stock < req.Quantity {
-		return nil, errors.New("insufficient stock")
+		return nil, ErrInsufficientStock
 	}
 
-	// Check if item already exists in cart
-	existingItem, err := s.cartRepo.GetItemByProduct(ctx, cart.ID, req.ProductID)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	// The upsert clamps the line to stock, so check the cart as it will be
+	inCart := cartQuantity(cart, req.ProductID)
+	quantity := inCart + req.Quantity
+	if quantity > product.Stock {
+		quantity = product.Stock
+	}
+	lines, value := cartContents(cart)
+	if inCart == 0 {
+		lines++
+	}
+	value += money.FromFloat(product.Price).Times(quantity - inCart)
+	if err := s.checkLimits(userRole, lines, value); err != nil {
 		return nil, err
 	}
 
-	if existingItem != nil {
-		// Update quantity
-		existingItem.Quantity += req.Quantity
-		if err := s.cartRepo.UpdateItem(ctx, existingItem); err != nil {
-			return nil, err
-		}
-	} else {
-		// Add new item
-		cartItem := &models.CartItem{
-			CartID:    cart.ID,
-			ProductID: req.ProductID,
-			Quantity:  req.Quantity,
-		}
-		if err := s.cartRepo.AddItem(ctx, cartItem); err != nil {
-			return nil, err
-		}
+	// Add to the existing line in a single upsert so concurrent adds sum
+	// correctly, clamped to the stock currently available
+	if _, err := s.cartRepo.UpsertItemQuantity(ctx, cart.ID, req.ProductID, req.Quantity, product.Stock); err != nil {
+		return nil, err
 	}
 
 	// Return updated cart
 	return s.GetCart(ctx, userID)
 }
 
+// AddMany adds a list of items in one go. Each goes through the same atomic
+// upsert as AddToCart, clamped to available stock; items that can't be bought,
+// or would take the cart over its limits, are skipped rather than failing the
+// whole request.
+func (s *cartService) AddMany(ctx context.Context, userID uint, userRole models.UserRole, req *models.CartBulkAddRequest) (*models.CartBulkAddResponse, error) {
+	if _, err := s.cartRepo.GetOrCreateCart(ctx, userID); err != nil {
+		return nil, err
+	}
+	cart, err := s.cartRepo.GetCartWithItems(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inCart := make(map[uint]int, len(cart.CartItems))
+	for _, item := range cart.CartItems {
+		inCart[item.ProductID] = item.Quantity
+	}
+	limits := s.limits.For(userRole)
+	lines, value := cartContents(cart)
+
+	ids := make([]uint, len(req.Items))
+	for i, item := range req.Items {
+		ids[i] = item.ProductID
+	}
+	found, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	products := make(map[uint]*models.Product, len(found))
+	for _, product := range found {
+		products[product.ID] = product
+	}
+
+	now := time.Now()
+	results := make([]models.CartAddResult, len(req.Items))
+	for i, item := range req.Items {
+		result := models.CartAddResult{
+			ProductID:    item.ProductID,
+			Requested:    item.Quantity,
+			CartQuantity: inCart[item.ProductID],
+			Status:       models.CartAddSkippedUnavailable,
+		}
+
+		product, ok := products[item.ProductID]
+		if ok && product.IsActive && product.IsAvailableAt(now) && product.Stock > 0 {
+			quantity := inCart[item.ProductID] + item.Quantity
+			if quantity > product.Stock {
+				quantity = product.Stock
+			}
+			added := money.FromFloat(product.Price).Times(quantity - inCart[item.ProductID])
+			switch {
+			case inCart[item.ProductID] == 0 && limits.ExceedsItems(lines+1):
+				result.Status = models.CartAddSkippedItemLimit
+				results[i] = result
+				continue
+			case added > 0 && limits.ExceedsValue((value + added).Float()):
+				result.Status = models.CartAddSkippedValueLimit
+				results[i] = result
+				continue
+			}
+
+			line, err := s.cartRepo.UpsertItemQuantity(ctx, cart.ID, item.ProductID, item.Quantity, product.Stock)
+			if err != nil {
+				return nil, err
+			}
+
+			result.Added = line.Quantity - inCart[item.ProductID]
+			if result.Added < 0 {
+				result.Added = 0
+			}
+			result.CartQuantity = line.Quantity
+			result.Status = models.CartAddAdded
+			if result.Added < item.Quantity {
+				result.Status = models.CartAddClamped
+			}
+			if inCart[item.ProductID] == 0 {
+				lines++
+			}
+			value += money.FromFloat(product.Price).Times(line.Quantity - inCart[item.ProductID])
+			inCart[item.ProductID] = line.Quantity
+		}
+		results[i] = result
+	}
+
+	updated, err := s.GetCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CartBulkAddResponse{Cart: updated, Results: results}, nil
+}
+
+// checkLimits returns an error if a cart of lines different products worth
+// value would be over the limits of the user's role
+func (s *cartService) checkLimits(userRole models.UserRole, lines int, value money.Amount) error {
+	limits := s.limits.For(userRole)
+	if limits.ExceedsItems(lines) {
+		return errorf(ErrCartItemLimit, "a cart can hold at most %d different products", limits.MaxItems)
+	}
+	if limits.ExceedsValue(value.Float()) {
+		return errorf(ErrCartValueLimit, "a cart can hold at most %s worth of products", money.Format(limits.MaxValue))
+	}
+	return nil
+}
+
+// cartContents returns how many different products the cart holds and what
+// they're worth at current prices
+func cartContents(cart *models.Cart) (int, money.Amount) {
+	cart.CalculateTotals()
+	return len(cart.CartItems), money.FromFloat(cart.TotalAmount)
+}
+
+// cartQuantity returns the product's quantity in the cart
+func cartQuantity(cart *models.Cart, productID uint) int {
+	for _, item := range cart.CartItems {
+		if item.ProductID == productID {
+			return item.Quantity
+		}
+	}
+	return 0
+}
+
 func (s *cartService) GetCart(ctx context.Context, userID uint) (*models.CartResponse, error) {
 	cart, err := s.cartRepo.GetCartWithItems(ctx, userID)
 	if err != nil {
@@ -120,7 +261,7 @@ func (s *cartService) GetUserCart(ctx context.Context, userID uint) ([]*models.C
 			ProductID: item.ProductID,
 			Product:   product.ToResponse(),
 			Quantity:  item.Quantity,
-			Subtotal:  product.Price * float64(item.Quantity),
+			Subtotal:  money.FromFloat(product.Price).Times(item.Quantity).Float(),
 			CreatedAt: item.CreatedAt,
 			UpdatedAt: item.UpdatedAt,
 		}
@@ -141,22 +282,134 @@ func (s *cartService) GetUserCart(ctx context.Context, userID uint) ([]*models.C
 	return responses, nil
 }
 
-func (s *cartService) GetCartTotal(ctx context.Context, userID uint) (float64, error) {
+func (s *cartService) GetCartTotal(ctx context.Context, userID uint) (*models.CartTotalResponse, error) {
 	cartWithItems, err := s.cartRepo.GetCartWithItems(ctx, userID)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	var subtotal money.Amount
+	var weight float64
+	for _, item := range cartWithItems.CartItems {
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			continue
+		}
+		subtotal += money.FromFloat(product.Price).Times(item.Quantity)
+		weight += product.ShippingWeight(item.Quantity)
+	}
+
+	// Include what's left to qualify for free shipping so the UI can nudge.
+	// There's no destination yet, so this is the default zone's rate.
+	quote := s.shippingSvc.Quote(ctx, subtotal.Float(), models.ShippingDestination{}, weight)
+
+	return &models.CartTotalResponse{
+		Subtotal: subtotal.Float(),
+		Total:    (subtotal + money.FromFloat(quote.Cost)).Float(),
+		Shipping: *quote,
+	}, nil
+}
+
+// GetCartSummary prices the cart as an order would be priced at checkout.
+// Destination ("US" or "US-CA") picks the shipping zone. Orders don't charge
+// tax yet, so that comes back as zero.
+func (s *cartService) GetCartSummary(ctx context.Context, userID uint, userRole models.UserRole, destination string) (*models.CartSummary, error) {
+	cartWithItems, err := s.cartRepo.GetCartWithItems(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	var total float64
+	// Build the same order items CreateOrder would so line discounts and
+	// totals go through Order.CalculateTotals
+	order := &models.Order{}
+	categories := make(map[uint]*uint)
+	var weight float64
 	for _, item := range cartWithItems.CartItems {
 		product, err := s.productRepo.GetByID(ctx, item.ProductID)
 		if err != nil {
 			continue
 		}
-		total += product.Price * float64(item.Quantity)
+		categories[product.ID] = product.CategoryID
+		weight += product.ShippingWeight(item.Quantity)
+		order.OrderItems = append(order.OrderItems, models.OrderItem{
+			ProductID:  item.ProductID,
+			SellerID:   product.SellerID,
+			Quantity:   item.Quantity,
+			UnitPrice:  product.Price,
+			TotalPrice: money.FromFloat(product.Price).Times(item.Quantity).Float(),
+		})
+	}
+
+	order.CalculateTotals()
+	quote := s.shippingSvc.Quote(ctx, order.SubtotalAmount, models.ParseShippingDestination(destination), weight)
+	order.ShippingAmount = quote.Cost
+	order.CalculateTotals()
+	promotion := s.promotions.Apply(ctx, order)
+
+	// SubtotalAmount is net of line discounts; report the gross subtotal and
+	// show every discount in one place
+	var lineDiscount money.Amount
+	lines := make([]models.PromotionLine, 0, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		lineDiscount += money.FromFloat(item.DiscountAmount)
+		lines = append(lines, models.PromotionLine{
+			ProductID:  item.ProductID,
+			CategoryID: categories[item.ProductID],
+			Quantity:   item.Quantity,
+			UnitPrice:  item.UnitPrice,
+			LineTotal:  item.LineTotal(),
+		})
+	}
+
+	return &models.CartSummary{
+		Destination:       destination,
+		ItemCount:         order.ItemCount,
+		Subtotal:          (money.FromFloat(order.SubtotalAmount) + lineDiscount).Float(),
+		EstimatedTax:      order.TaxAmount,
+		EstimatedShipping: order.ShippingAmount,
+		Discount:          (lineDiscount + money.FromFloat(order.DiscountAmount)).Float(),
+		GrandTotal:        order.TotalAmount,
+		Shipping:          *quote,
+		Promotion:         promotion,
+		MinimumOrder:      s.minimums.Unmet(ctx, order),
+		Reservations:      s.cartReservations(ctx, userID, order.OrderItems),
+		LineCount:         len(order.OrderItems),
+		Limits:            s.limits.For(userRole),
+		Lines:             lines,
+	}, nil
+}
+
+// cartReservations returns the stock the user's unpaid orders hold for items
+// in the cart, so checkout can show how long each is held. A failed lookup
+// leaves them out rather than failing the summary.
+func (s *cartService) cartReservations(ctx context.Context, userID uint, items []models.OrderItem) []models.CartReservation {
+	if len(items) == 0 {
+		return nil
+	}
+
+	reservations, err := s.reservationRepo.GetActiveByCustomer(ctx, userID, time.Now())
+	if err != nil {
+		fmt.Printf("Warning: failed to get stock reservations for user %d: %v\n", userID, err)
+		return nil
 	}
 
-	return total, nil
+	inCart := make(map[uint]bool, len(items))
+	for _, item := range items {
+		inCart[item.ProductID] = true
+	}
+
+	var held []models.CartReservation
+	for _, reservation := range reservations {
+		if inCart[reservation.ProductID] {
+			held = append(held, models.CartReservation{
+				OrderID:   reservation.OrderID,
+				ProductID: reservation.ProductID,
+				Quantity:  reservation.Quantity,
+				ExpiresAt: reservation.ExpiresAt,
+			})
+		}
+	}
+	return held
 }
 
 func (s *cartService) GetCartItemCount(ctx context.Context, userID uint) (int, error) {
@@ -173,7 +426,7 @@ func (s *cartService) GetCartItemCount(ctx context.Context, userID uint) (int, e
 	return count, nil
 }
 
-func (s *cartService) UpdateCartItem(ctx context.Context, userID uint, productID uint, quantity int) (*models.CartResponse, error) {
+func (s *cartService) UpdateCartItem(ctx context.Context, userID uint, userRole models.UserRole, productID uint, quantity int) (*models.CartResponse, error) {
 	cart, err := s.cartRepo.GetOrCreateCart(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -182,7 +435,7 @@ func (s *cartService) UpdateCartItem(ctx context.Context, userID uint, productID
 	existingItem, err := s.cartRepo.GetItemByProduct(ctx, cart.ID, productID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("item not found in cart")
+			return nil, ErrCartItemNotFound
 		}
 		return nil, err
 	}
@@ -194,7 +447,21 @@ func (s *cartService) UpdateCartItem(ctx context.Context, userID uint, productID
 	}
 
 	if product.Stock < quantity {
-		return nil, errors.New("insufficient stock")
+		return nil, ErrInsufficientStock
+	}
+
+	// Raising a quantity mustn't take the cart over its value limit; lowering
+	// one is always allowed
+	if quantity > existingItem.Quantity {
+		cartWithItems, err := s.cartRepo.GetCartWithItems(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		lines, value := cartContents(cartWithItems)
+		value += money.FromFloat(product.Price).Times(quantity - existingItem.Quantity)
+		if err := s.checkLimits(userRole, lines, value); err != nil {
+			return nil, err
+		}
 	}
 
 	// Update quantity
@@ -207,7 +474,7 @@ func (s *cartService) UpdateCartItem(ctx context.Context, userID uint, productID
 		ID:          cart.ID,
 		CustomerID:  cart.CustomerID,
 		Items:       []models.CartItemResponse{},
-		TotalAmount: product.Price * float64(existingItem.Quantity),
+		TotalAmount: money.FromFloat(product.Price).Times(existingItem.Quantity).Float(),
 		ItemCount:   existingItem.Quantity,
 		CreatedAt:   existingItem.CreatedAt,
 		UpdatedAt:   existingItem.UpdatedAt,
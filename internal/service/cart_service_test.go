@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+// newLimitedCartService limits customers to two products worth 100.00 and
+// sellers to five worth 1000.00
+func newLimitedCartService(products ...*models.Product) (CartService, *fakeCartRepo) {
+	productRepo := newFakeProductRepo(products...)
+	carts := newFakeCartRepo(productRepo)
+	limits := config.CartConfig{
+		Customer: models.CartLimits{MaxItems: 2, MaxValue: 100},
+		Seller:   models.CartLimits{MaxItems: 5, MaxValue: 1000},
+		Admin:    models.CartLimits{MaxItems: 2, MaxValue: 100},
+	}
+	return NewCartService(carts, productRepo, nil, nil, nil, nil, limits), carts
+}
+
+func cartTestProduct(id uint, price float64) *models.Product {
+	return &models.Product{BaseModel: models.BaseModel{ID: id}, Name: "Product", Price: price, Stock: 50, IsActive: true}
+}
+
+func TestAddToCartEnforcesRoleLimits(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newLimitedCartService(cartTestProduct(1, 10), cartTestProduct(2, 10), cartTestProduct(3, 10), cartTestProduct(4, 60))
+
+	for _, id := range []uint{1, 2} {
+		if _, err := svc.AddToCart(ctx, 7, models.RoleCustomer, &models.CartAddRequest{ProductID: id, Quantity: 1}); err != nil {
+			t.Fatalf("adding product %d: %v", id, err)
+		}
+	}
+
+	// A third product is over the item limit, more of one already in the cart isn't
+	_, err := svc.AddToCart(ctx, 7, models.RoleCustomer, &models.CartAddRequest{ProductID: 3, Quantity: 1})
+	if !errors.Is(err, ErrCartItemLimit) {
+		t.Fatalf("expected ErrCartItemLimit, got %v", err)
+	}
+	if _, err := svc.AddToCart(ctx, 7, models.RoleCustomer, &models.CartAddRequest{ProductID: 1, Quantity: 2}); err != nil {
+		t.Fatalf("adding more of a product in the cart: %v", err)
+	}
+
+	// 40.00 in the cart; nine more at 10.00 would make 130.00
+	_, err = svc.AddToCart(ctx, 7, models.RoleCustomer, &models.CartAddRequest{ProductID: 2, Quantity: 9})
+	if !errors.Is(err, ErrCartValueLimit) {
+		t.Fatalf("expected ErrCartValueLimit, got %v", err)
+	}
+
+	// Sellers have higher limits
+	for _, id := range []uint{1, 2, 3, 4} {
+		if _, err := svc.AddToCart(ctx, 8, models.RoleSeller, &models.CartAddRequest{ProductID: id, Quantity: 2}); err != nil {
+			t.Fatalf("seller adding product %d: %v", id, err)
+		}
+	}
+}
+
+func TestAddManySkipsItemsOverLimits(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newLimitedCartService(cartTestProduct(1, 10), cartTestProduct(2, 95), cartTestProduct(3, 10), cartTestProduct(4, 10))
+
+	resp, err := svc.AddMany(ctx, 7, models.RoleCustomer, &models.CartBulkAddRequest{Items: []models.CartAddRequest{
+		{ProductID: 1, Quantity: 1},
+		{ProductID: 2, Quantity: 1},
+		{ProductID: 3, Quantity: 1},
+		{ProductID: 4, Quantity: 1},
+	}})
+	if err != nil {
+		t.Fatalf("AddMany: %v", err)
+	}
+
+	want := []models.CartAddStatus{models.CartAddAdded, models.CartAddSkippedValueLimit, models.CartAddAdded, models.CartAddSkippedItemLimit}
+	for i, result := range resp.Results {
+		if result.Status != want[i] {
+			t.Errorf("item %d: expected %s, got %s", i, want[i], result.Status)
+		}
+	}
+	if resp.Cart.TotalAmount != 20 {
+		t.Errorf("expected a 20.00 cart, got %.2f", resp.Cart.TotalAmount)
+	}
+}
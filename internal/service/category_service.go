@@ -29,6 +29,11 @@ func (s *categoryService) Create(ctx context.Context, req *models.CategoryCreate
 		ParentID:    req.ParentID,
 		IsActive:    req.IsActive,
 		SortOrder:   req.SortOrder,
+		DefaultSort: req.DefaultSort,
+	}
+
+	if err := s.checkNameAvailable(ctx, category.Name, 0); err != nil {
+		return nil, err
 	}
 
 	if err := s.categoryRepo.Create(ctx, category); err != nil {
@@ -62,7 +67,7 @@ func (s *categoryService) GetByID(ctx context.Context, id uint) (*models.Categor
 	category, err := s.categoryRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+			return nil, ErrCategoryNotFound
 		}
 		return nil, err
 	}
@@ -75,13 +80,16 @@ func (s *categoryService) Update(ctx context.Context, id uint, req *models.Categ
 	category, err := s.categoryRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+			return nil, ErrCategoryNotFound
 		}
 		return nil, err
 	}
 
 	// Update fields
-	if req.Name != nil {
+	if req.Name != nil && *req.Name != category.Name {
+		if err := s.checkNameAvailable(ctx, *req.Name, category.ID); err != nil {
+			return nil, err
+		}
 		category.Name = *req.Name
 	}
 	if req.Description != nil {
@@ -99,6 +107,16 @@ func (s *categoryService) Update(ctx context.Context, id uint, req *models.Categ
 	if req.SortOrder != nil {
 		category.SortOrder = *req.SortOrder
 	}
+	if req.DefaultSort != nil {
+		switch {
+		case *req.DefaultSort == "":
+			category.DefaultSort = nil
+		case req.DefaultSort.IsValid():
+			category.DefaultSort = req.DefaultSort
+		default:
+			return nil, ErrInvalidProductSort
+		}
+	}
 
 	if err := s.categoryRepo.Update(ctx, category); err != nil {
 		return nil, err
@@ -119,7 +137,7 @@ func (s *categoryService) Delete(ctx context.Context, id uint) error {
 	_, err := s.categoryRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("category not found")
+			return ErrCategoryNotFound
 		}
 		return err
 	}
@@ -134,7 +152,7 @@ func (s *categoryService) Delete(ctx context.Context, id uint) error {
 		return err
 	}
 	if len(children) > 0 {
-		return errors.New("cannot delete category with subcategories")
+		return ErrCategoryHasChildren
 	}
 
 	return s.categoryRepo.Delete(ctx, id)
@@ -178,6 +196,10 @@ func (s *categoryService) CreateCategory(ctx context.Context, req *models.Catego
 		SortOrder:   req.SortOrder,
 	}
 
+	if err := s.checkNameAvailable(ctx, category.Name, 0); err != nil {
+		return nil, err
+	}
+
 	if err := s.categoryRepo.Create(ctx, category); err != nil {
 		return nil, err
 	}
@@ -213,7 +235,10 @@ func (s *categoryService) UpdateCategory(ctx context.Context, id uint, req *mode
 		return nil, err
 	}
 
-	if req.Name != nil {
+	if req.Name != nil && *req.Name != category.Name {
+		if err := s.checkNameAvailable(ctx, *req.Name, category.ID); err != nil {
+			return nil, err
+		}
 		category.Name = *req.Name
 	}
 	if req.Description != nil {
@@ -270,3 +295,15 @@ func (s *categoryService) GetCategoryChildren(ctx context.Context, parentID uint
 
 	return result, nil
 }
+
+// checkNameAvailable rejects a name already used by another live category
+func (s *categoryService) checkNameAvailable(ctx context.Context, name string, excludeID uint) error {
+	taken, err := s.categoryRepo.NameExists(ctx, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrCategoryNameTaken
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type conversationService struct {
+	conversationRepo repository.ConversationRepository
+	userRepo         repository.UserRepository
+	productRepo      repository.ProductRepository
+	orderRepo        repository.OrderRepository
+	notificationRepo repository.NotificationRepository
+}
+
+func NewConversationService(
+	conversationRepo repository.ConversationRepository,
+	userRepo repository.UserRepository,
+	productRepo repository.ProductRepository,
+	orderRepo repository.OrderRepository,
+	notificationRepo repository.NotificationRepository,
+) ConversationService {
+	return &conversationService{
+		conversationRepo: conversationRepo,
+		userRepo:         userRepo,
+		productRepo:      productRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// ContactSeller sends the customer's message to a seller. Messages about the
+// same product and order go into the same conversation.
+func (s *conversationService) ContactSeller(ctx context.Context, customerID, sellerID uint, req *models.ContactSellerRequest) (*models.Conversation, error) {
+	if customerID == sellerID {
+		return nil, ErrMessageSelf
+	}
+
+	seller, err := s.userRepo.GetByID(ctx, sellerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSellerNotFound
+		}
+		return nil, fmt.Errorf("failed to get seller: %w", err)
+	}
+	if seller.Role != models.RoleSeller || !seller.IsActive {
+		return nil, ErrSellerNotFound
+	}
+
+	subject, err := s.conversationSubject(ctx, customerID, sellerID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.conversationRepo.FindExisting(ctx, customerID, sellerID, req.ProductID, req.OrderID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	if conversation != nil {
+		message := &models.ConversationMessage{
+			ConversationID: conversation.ID,
+			SenderID:       customerID,
+			Body:           req.Body,
+		}
+		if err := s.conversationRepo.AddMessage(ctx, message); err != nil {
+			return nil, fmt.Errorf("failed to send message: %w", err)
+		}
+	} else {
+		conversation = &models.Conversation{
+			CustomerID:    customerID,
+			SellerID:      sellerID,
+			ProductID:     req.ProductID,
+			OrderID:       req.OrderID,
+			Subject:       subject,
+			LastMessageAt: time.Now(),
+		}
+		message := &models.ConversationMessage{
+			SenderID: customerID,
+			Body:     req.Body,
+		}
+		if err := s.conversationRepo.Create(ctx, conversation, message); err != nil {
+			return nil, fmt.Errorf("failed to create conversation: %w", err)
+		}
+	}
+
+	s.notifyRecipient(ctx, conversation, customerID)
+
+	return s.getConversation(ctx, conversation.ID)
+}
+
+// conversationSubject checks the product is the seller's and the order is the
+// customer's with items from the seller, and names the conversation after them
+func (s *conversationService) conversationSubject(ctx context.Context, customerID, sellerID uint, req *models.ContactSellerRequest) (string, error) {
+	subject := ""
+	if req.ProductID != nil {
+		product, err := s.productRepo.GetByID(ctx, *req.ProductID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", ErrProductNotFound
+			}
+			return "", fmt.Errorf("failed to get product: %w", err)
+		}
+		if product.SellerID != sellerID {
+			return "", ErrProductNotFound
+		}
+		subject = product.Name
+	}
+
+	if req.OrderID != nil {
+		order, err := s.orderRepo.GetByID(ctx, *req.OrderID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", ErrOrderNotFound
+			}
+			return "", fmt.Errorf("failed to get order: %w", err)
+		}
+		if order.CustomerID != customerID || !order.HasSellerItems(sellerID) {
+			return "", ErrOrderNotFound
+		}
+		if subject == "" {
+			subject = fmt.Sprintf("Order %s", order.OrderNumber)
+		}
+	}
+
+	return subject, nil
+}
+
+func (s *conversationService) GetConversations(ctx context.Context, userID uint, limit, offset int) ([]*models.Conversation, int64, error) {
+	conversations, total, err := s.conversationRepo.GetByParticipant(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get conversations: %w", err)
+	}
+
+	return conversations, total, nil
+}
+
+// GetConversation returns the conversation with its messages to a participant or an admin
+func (s *conversationService) GetConversation(ctx context.Context, userID uint, userRole models.UserRole, id uint) (*models.Conversation, error) {
+	conversation, err := s.getConversation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Outsiders get the same answer as for a missing conversation
+	if userRole != models.RoleAdmin && !conversation.HasParticipant(userID) {
+		return nil, ErrConversationNotFound
+	}
+
+	return conversation, nil
+}
+
+// SendMessage adds a participant's message and notifies the other participant
+func (s *conversationService) SendMessage(ctx context.Context, userID, id uint, req *models.ConversationMessageRequest) (*models.Conversation, error) {
+	conversation, err := s.getConversation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !conversation.HasParticipant(userID) {
+		return nil, ErrConversationNotFound
+	}
+
+	message := &models.ConversationMessage{
+		ConversationID: conversation.ID,
+		SenderID:       userID,
+		Body:           req.Body,
+	}
+	if err := s.conversationRepo.AddMessage(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	s.notifyRecipient(ctx, conversation, userID)
+
+	return s.getConversation(ctx, conversation.ID)
+}
+
+func (s *conversationService) getConversation(ctx context.Context, id uint) (*models.Conversation, error) {
+	conversation, err := s.conversationRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	return conversation, nil
+}
+
+// notifyRecipient tells the other participant about a new message; a failure
+// doesn't block the message
+func (s *conversationService) notifyRecipient(ctx context.Context, conversation *models.Conversation, senderID uint) {
+	title := "New message from a customer"
+	if senderID == conversation.SellerID {
+		title = "New message from a seller"
+	}
+	message := "You have a new message."
+	if conversation.Subject != "" {
+		message = fmt.Sprintf("You have a new message about %s.", conversation.Subject)
+	}
+
+	notification := &models.Notification{
+		UserID:  conversation.OtherParticipant(senderID),
+		Type:    models.NotificationTypeNewMessage,
+		Title:   title,
+		Message: message,
+		Data:    conversationNotificationData(conversation),
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		fmt.Printf("Warning: failed to notify about conversation %d: %v\n", conversation.ID, err)
+	}
+}
+
+func conversationNotificationData(conversation *models.Conversation) *string {
+	fields := map[string]interface{}{"conversation_id": conversation.ID}
+	if conversation.ProductID != nil {
+		fields["product_id"] = *conversation.ProductID
+	}
+	if conversation.OrderID != nil {
+		fields["order_id"] = *conversation.OrderID
+	}
+
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	data := string(payload)
+	return &data
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const couponHoldsPrefix = "coupons:holds:"
+
+// holdCouponScript places or refreshes a hold on a coupon use. Holds are kept
+// in a sorted set scored by expiry, so lapsed holds are dropped before
+// counting. Checking and adding happen in one script so concurrent checkouts
+// can't both take the last use.
+//
+// KEYS[1] holds set, ARGV: now (ms), hold expiry (ms), holder, uses left, key TTL (ms)
+var holdCouponScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+if not redis.call('ZSCORE', KEYS[1], ARGV[3]) and redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[4]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ARGV[5])
+return 1
+`)
+
+type couponService struct {
+	couponRepo repository.CouponRepository
+	redis      *redis.Client
+	config     *config.Config
+}
+
+func NewCouponService(couponRepo repository.CouponRepository, redisClient *redis.Client, cfg *config.Config) CouponService {
+	return &couponService{
+		couponRepo: couponRepo,
+		redis:      redisClient,
+		config:     cfg,
+	}
+}
+
+func (s *couponService) CreateCoupon(ctx context.Context, req *models.CreateCouponRequest, adminID uint) (*models.Coupon, error) {
+	if req.Type == models.CouponTypePercent && req.Value > 100 {
+		return nil, ErrCouponPercentTooHigh
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrExpiryInPast
+	}
+
+	if _, err := s.couponRepo.GetByCode(ctx, req.Code); err == nil {
+		return nil, ErrCouponCodeTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check coupon code: %w", err)
+	}
+
+	coupon := &models.Coupon{
+		Code:       strings.ToUpper(req.Code),
+		Type:       req.Type,
+		Value:      req.Value,
+		MinSpend:   req.MinSpend,
+		UsageLimit: req.UsageLimit,
+		IsActive:   true,
+		ExpiresAt:  req.ExpiresAt,
+		CreatedBy:  adminID,
+		ProductID:  req.ProductID,
+		CategoryID: req.CategoryID,
+	}
+
+	if err := s.couponRepo.Create(ctx, coupon); err != nil {
+		return nil, fmt.Errorf("failed to create coupon: %w", err)
+	}
+
+	return coupon, nil
+}
+
+// IssueWelcomeCoupon creates the single-use coupon a customer gets for
+// registering, when welcome coupons are enabled. It returns nil if the user
+// isn't a customer or was already issued one.
+func (s *couponService) IssueWelcomeCoupon(ctx context.Context, user *models.User) (*models.Coupon, error) {
+	cfg := s.config.Order
+	if !cfg.WelcomeCoupon || !user.IsCustomer() {
+		return nil, nil
+	}
+
+	suffix, err := utils.GenerateRandomToken(5)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate coupon code: %w", err)
+	}
+
+	usageLimit := 1
+	coupon := &models.Coupon{
+		Code:       "WELCOME" + strings.ToUpper(suffix),
+		Type:       cfg.WelcomeCouponType,
+		Value:      cfg.WelcomeCouponValue,
+		MinSpend:   cfg.WelcomeCouponMinSpend,
+		UsageLimit: &usageLimit,
+		IsActive:   true,
+		CreatedBy:  user.ID,
+		UserID:     &user.ID,
+	}
+	if cfg.WelcomeCouponValidity > 0 {
+		expiresAt := time.Now().Add(cfg.WelcomeCouponValidity)
+		coupon.ExpiresAt = &expiresAt
+	}
+
+	created, err := s.couponRepo.CreateWelcome(ctx, coupon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create welcome coupon: %w", err)
+	}
+	if !created {
+		return nil, nil
+	}
+	return coupon, nil
+}
+
+func (s *couponService) GetCoupons(ctx context.Context, limit, offset int) ([]*models.Coupon, int64, error) {
+	coupons, total, err := s.couponRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get coupons: %w", err)
+	}
+	return coupons, total, nil
+}
+
+// Apply validates a coupon for a checkout and returns the discount. The
+// minimum spend is checked against subtotal; the discount is taken off the
+// lines the coupon targets, which is all of them for an untargeted coupon.
+// Nothing is held yet: the order holds a use with Hold once it exists, which
+// is what settles a race for the last use.
+func (s *couponService) Apply(ctx context.Context, code string, userID uint, subtotal float64, lines []models.PromotionLine) (*models.Coupon, float64, error) {
+	coupon, err := s.usableCoupon(ctx, code, userID, subtotal, lines)
+	if err == nil {
+		err = s.checkUsesLeft(ctx, coupon)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return coupon, coupon.Discount(coupon.EligibleSubtotal(lines)), nil
+}
+
+// Preview works out what the coupon would take off a subtotal that already
+// has promotionDiscount applied, combining the two per the configured
+// stacking policy as checkout does. Nothing is held: a coupon the user can't
+// use comes back as not valid with the reason rather than as an error.
+func (s *couponService) Preview(ctx context.Context, code string, userID uint, subtotal, promotionDiscount float64, lines []models.PromotionLine) (*models.CouponPreview, error) {
+	preview := &models.CouponPreview{
+		Code:              strings.ToUpper(code),
+		Subtotal:          subtotal,
+		PromotionDiscount: promotionDiscount,
+		Total:             (money.FromFloat(subtotal) - money.FromFloat(promotionDiscount)).Float(),
+	}
+
+	coupon, err := s.usableCoupon(ctx, code, userID, subtotal, lines)
+	if err == nil {
+		err = s.checkUsesLeft(ctx, coupon)
+	}
+	if err != nil {
+		if isCouponRejection(err) {
+			preview.Reason = err.Error()
+			return preview, nil
+		}
+		return nil, err
+	}
+
+	discount := coupon.Discount(coupon.EligibleSubtotal(lines))
+	if s.config.Order.CouponStacking == models.CouponStackingBest && promotionDiscount > 0 {
+		if discount <= promotionDiscount {
+			preview.Reason = "the cart's promotion is worth more than this coupon"
+			return preview, nil
+		}
+		preview.PromotionDiscount = 0
+	}
+
+	// Never discount past the subtotal
+	remaining := money.FromFloat(subtotal) - money.FromFloat(preview.PromotionDiscount)
+	couponDiscount := money.FromFloat(discount).Clamp(remaining)
+
+	preview.Code = coupon.Code
+	preview.Valid = true
+	preview.Discount = couponDiscount.Float()
+	preview.Total = (remaining - couponDiscount).Float()
+	return preview, nil
+}
+
+// usableCoupon looks up a coupon and checks the user can use it on subtotal
+// and lines, leaving its remaining uses to the caller
+func (s *couponService) usableCoupon(ctx context.Context, code string, userID uint, subtotal float64, lines []models.PromotionLine) (*models.Coupon, error) {
+	coupon, err := s.couponRepo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCoupon
+		}
+		return nil, fmt.Errorf("failed to get coupon: %w", err)
+	}
+
+	// Someone else's coupon is treated as not existing
+	if !coupon.IsActive || !coupon.UsableBy(userID) {
+		return nil, ErrInvalidCoupon
+	}
+	if coupon.IsExpired(time.Now()) {
+		return nil, ErrCouponExpired
+	}
+	if subtotal < coupon.MinSpend {
+		return nil, ErrCouponMinSpend
+	}
+	if coupon.Targeted() && coupon.EligibleSubtotal(lines) == 0 {
+		return nil, ErrCouponNotApplicable
+	}
+
+	return coupon, nil
+}
+
+// checkUsesLeft reports whether a use of the coupon is free, counting other
+// checkouts' live holds, without taking one
+func (s *couponService) checkUsesLeft(ctx context.Context, coupon *models.Coupon) error {
+	if coupon.UsageLimit == nil {
+		return nil
+	}
+
+	remaining := *coupon.UsageLimit - coupon.UsedCount
+	if remaining <= 0 {
+		return ErrCouponUsageLimit
+	}
+
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	held, err := s.redis.ZCount(ctx, couponHoldsKey(coupon.ID), "("+now, "+inf").Result()
+	if err != nil {
+		return fmt.Errorf("failed to check coupon holds: %w", err)
+	}
+
+	if held >= int64(remaining) {
+		return ErrCouponUsageLimit
+	}
+	return nil
+}
+
+// isCouponRejection reports whether err means the user can't use the coupon,
+// as opposed to a failure checking it
+func isCouponRejection(err error) bool {
+	return errors.Is(err, ErrInvalidCoupon) ||
+		errors.Is(err, ErrCouponExpired) ||
+		errors.Is(err, ErrCouponMinSpend) ||
+		errors.Is(err, ErrCouponNotApplicable) ||
+		errors.Is(err, ErrCouponUsageLimit)
+}
+
+// Hold holds a use of the coupon for an order until the hold TTL passes.
+// Holding again for the same order refreshes its hold, e.g. when retrying
+// payment, rather than taking another use.
+func (s *couponService) Hold(ctx context.Context, couponID, orderID uint) error {
+	coupon, err := s.couponRepo.GetByID(ctx, couponID)
+	if err != nil {
+		return fmt.Errorf("failed to get coupon: %w", err)
+	}
+	return s.hold(ctx, coupon, orderID)
+}
+
+// Commit counts the order's held use once it is paid and drops the hold
+func (s *couponService) Commit(ctx context.Context, couponID, orderID uint) error {
+	counted, err := s.couponRepo.IncrementUsage(ctx, couponID)
+	if err != nil {
+		return fmt.Errorf("failed to record coupon use: %w", err)
+	}
+
+	s.Release(ctx, couponID, orderID)
+
+	if !counted {
+		// Only possible if the hold lapsed while payment was in flight
+		return ErrCouponUsageLimit
+	}
+	return nil
+}
+
+// Release drops the order's hold so the use goes back to other checkouts
+func (s *couponService) Release(ctx context.Context, couponID, orderID uint) {
+	if err := s.redis.ZRem(ctx, couponHoldsKey(couponID), couponHolder(orderID)).Err(); err != nil {
+		fmt.Printf("Warning: failed to release coupon %d hold for order %d: %v\n", couponID, orderID, err)
+	}
+}
+
+// hold reserves one of the coupon's remaining uses for the order until the hold TTL passes
+func (s *couponService) hold(ctx context.Context, coupon *models.Coupon, orderID uint) error {
+	// Unlimited coupons can't be over-redeemed, so there's nothing to hold
+	if coupon.UsageLimit == nil {
+		return nil
+	}
+
+	remaining := *coupon.UsageLimit - coupon.UsedCount
+	if remaining <= 0 {
+		return ErrCouponUsageLimit
+	}
+
+	now := time.Now()
+	ttl := s.config.Order.CouponHoldTTL
+	held, err := holdCouponScript.Run(ctx, s.redis, []string{couponHoldsKey(coupon.ID)},
+		now.UnixMilli(), now.Add(ttl).UnixMilli(), couponHolder(orderID), remaining, ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return fmt.Errorf("failed to hold coupon: %w", err)
+	}
+	if held == 0 {
+		return ErrCouponUsageLimit
+	}
+
+	return nil
+}
+
+func couponHoldsKey(couponID uint) string {
+	return fmt.Sprintf("%s%d", couponHoldsPrefix, couponID)
+}
+
+// couponHolder is an order's member in a coupon's holds set. Holds are per
+// order, not per customer, so one customer's concurrent checkouts each need
+// their own use.
+func couponHolder(orderID uint) string {
+	return fmt.Sprintf("order:%d", orderID)
+}
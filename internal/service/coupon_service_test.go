@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type fakeCouponRepo struct {
+	repository.CouponRepository
+	coupon *models.Coupon
+
+	mu      sync.Mutex
+	welcome map[uint]*models.Coupon // Welcome coupons by user
+}
+
+func (r *fakeCouponRepo) GetByID(ctx context.Context, id uint) (*models.Coupon, error) {
+	copied := *r.coupon
+	return &copied, nil
+}
+
+func (r *fakeCouponRepo) GetByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	if r.coupon == nil || !strings.EqualFold(r.coupon.Code, code) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *r.coupon
+	return &copied, nil
+}
+
+func (r *fakeCouponRepo) CreateWelcome(ctx context.Context, coupon *models.Coupon) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.welcome == nil {
+		r.welcome = make(map[uint]*models.Coupon)
+	}
+	if _, issued := r.welcome[*coupon.UserID]; issued {
+		return false, nil
+	}
+	coupon.ID = uint(len(r.welcome) + 1)
+	r.welcome[*coupon.UserID] = coupon
+	return true, nil
+}
+
+func newWelcomeTestService() (*couponService, *fakeCouponRepo) {
+	repo := &fakeCouponRepo{}
+	cfg := &config.Config{Order: config.OrderConfig{
+		WelcomeCoupon:         true,
+		WelcomeCouponType:     models.CouponTypePercent,
+		WelcomeCouponValue:    15,
+		WelcomeCouponValidity: 30 * 24 * time.Hour,
+	}}
+	return NewCouponService(repo, nil, cfg).(*couponService), repo
+}
+
+func newHoldTestService(t *testing.T, usageLimit int) *couponService {
+	coupon := &models.Coupon{BaseModel: models.BaseModel{ID: 1}, Code: "LAST", UsageLimit: &usageLimit, IsActive: true}
+	cfg := &config.Config{Order: config.OrderConfig{CouponHoldTTL: time.Minute}}
+	return NewCouponService(&fakeCouponRepo{coupon: coupon}, openTestRedis(t), cfg).(*couponService)
+}
+
+// holdConcurrently has each order try to hold the coupon at the same moment
+// and returns how many got it
+func holdConcurrently(t *testing.T, svc *couponService, orderIDs ...uint) int {
+	t.Helper()
+	var wg sync.WaitGroup
+	results := make([]error, len(orderIDs))
+	start := make(chan struct{})
+	for i, orderID := range orderIDs {
+		wg.Add(1)
+		go func(i int, orderID uint) {
+			defer wg.Done()
+			<-start
+			results[i] = svc.Hold(context.Background(), 1, orderID)
+		}(i, orderID)
+	}
+	close(start)
+	wg.Wait()
+
+	held := 0
+	for _, err := range results {
+		switch {
+		case err == nil:
+			held++
+		case !errors.Is(err, ErrCouponUsageLimit):
+			t.Fatalf("Hold: %v", err)
+		}
+	}
+	return held
+}
+
+func TestConcurrentCheckoutsRaceForLastCouponUse(t *testing.T) {
+	svc := newHoldTestService(t, 1)
+
+	if held := holdConcurrently(t, svc, 101, 102); held != 1 {
+		t.Errorf("%d checkouts held the last use, want 1", held)
+	}
+}
+
+// Holds used to be keyed by customer, letting one customer's two checkouts
+// share a single hold and both redeem the last use
+func TestSameCustomersCheckoutsEachNeedAUse(t *testing.T) {
+	svc := newHoldTestService(t, 1)
+	ctx := context.Background()
+
+	if err := svc.Hold(ctx, 1, 101); err != nil {
+		t.Fatalf("first order: %v", err)
+	}
+	if err := svc.Hold(ctx, 1, 102); !errors.Is(err, ErrCouponUsageLimit) {
+		t.Errorf("second order: err = %v, want ErrCouponUsageLimit", err)
+	}
+	// Retrying payment refreshes the order's own hold
+	if err := svc.Hold(ctx, 1, 101); err != nil {
+		t.Errorf("refreshing the first order's hold: %v", err)
+	}
+
+	svc.Release(ctx, 1, 101)
+	if err := svc.Hold(ctx, 1, 102); err != nil {
+		t.Errorf("second order after release: %v", err)
+	}
+}
+
+func TestIssueWelcomeCouponOncePerCustomer(t *testing.T) {
+	svc, _ := newWelcomeTestService()
+	ctx := context.Background()
+	customer := &models.User{BaseModel: models.BaseModel{ID: 7}, Role: models.RoleCustomer}
+
+	coupon, err := svc.IssueWelcomeCoupon(ctx, customer)
+	if err != nil {
+		t.Fatalf("IssueWelcomeCoupon: %v", err)
+	}
+	if coupon == nil {
+		t.Fatal("expected a welcome coupon for the customer")
+	}
+	if coupon.UserID == nil || *coupon.UserID != 7 || coupon.UsageLimit == nil || *coupon.UsageLimit != 1 {
+		t.Errorf("coupon = %+v, want single use by user 7", coupon)
+	}
+	if coupon.Value != 15 || coupon.ExpiresAt == nil {
+		t.Errorf("value = %v, expires_at = %v; want 15 and an expiry", coupon.Value, coupon.ExpiresAt)
+	}
+
+	if again, err := svc.IssueWelcomeCoupon(ctx, customer); err != nil || again != nil {
+		t.Errorf("second issue = %v, %v; want none", again, err)
+	}
+
+	for _, role := range []models.UserRole{models.RoleSeller, models.RoleAdmin} {
+		user := &models.User{BaseModel: models.BaseModel{ID: 8}, Role: role}
+		if coupon, err := svc.IssueWelcomeCoupon(ctx, user); err != nil || coupon != nil {
+			t.Errorf("%s: issued %v, %v; want none", role, coupon, err)
+		}
+	}
+}
+
+func TestCustomerCouponOnlyUsableByItsCustomer(t *testing.T) {
+	owner := uint(7)
+	coupon := &models.Coupon{BaseModel: models.BaseModel{ID: 1}, Code: "WELCOME1", Type: models.CouponTypeFixed, Value: 5, IsActive: true, UserID: &owner}
+	svc := NewCouponService(&fakeCouponRepo{coupon: coupon}, nil, &config.Config{}).(*couponService)
+	ctx := context.Background()
+
+	if _, err := svc.usableCoupon(ctx, "welcome1", 8, 50, nil); !errors.Is(err, ErrInvalidCoupon) {
+		t.Errorf("another customer: err = %v, want ErrInvalidCoupon", err)
+	}
+	if _, err := svc.usableCoupon(ctx, "welcome1", owner, 50, nil); err != nil {
+		t.Errorf("its customer: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/pkg/payment"
+	"gorm.io/gorm"
+)
+
+type disputeService struct {
+	disputeRepo      repository.DisputeRepository
+	orderRepo        repository.OrderRepository
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+	paymentSvc       payment.Service
+	orderSvc         OrderService
+}
+
+func NewDisputeService(
+	disputeRepo repository.DisputeRepository,
+	orderRepo repository.OrderRepository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+	paymentSvc payment.Service,
+	orderSvc OrderService,
+) DisputeService {
+	return &disputeService{
+		disputeRepo:      disputeRepo,
+		orderRepo:        orderRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		paymentSvc:       paymentSvc,
+		orderSvc:         orderSvc,
+	}
+}
+
+// HandlePaymentWebhook verifies a payment provider webhook, finalizes orders on
+// succeeded payments and applies dispute events to the matching order. Other
+// event types are acknowledged and ignored.
+func (s *disputeService) HandlePaymentWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.paymentSvc.ParseWebhookEvent(payload, signature)
+	if err != nil {
+		return errorf(ErrInvalidWebhook, "invalid webhook: %v", err)
+	}
+
+	if event.Type == payment.EventPaymentSucceeded && event.PaymentIntentID != "" {
+		return s.orderSvc.HandlePaymentSucceeded(ctx, event.PaymentIntentID)
+	}
+
+	if event.Dispute == nil {
+		return nil
+	}
+
+	return s.syncDispute(ctx, event.Dispute)
+}
+
+// syncDispute creates or updates the local dispute and keeps the order's payment status in step
+func (s *disputeService) syncDispute(ctx context.Context, info *payment.DisputeInfo) error {
+	dispute, err := s.disputeRepo.GetByStripeID(ctx, info.ID)
+	isNew := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !isNew {
+		return fmt.Errorf("failed to get dispute: %w", err)
+	}
+
+	if isNew {
+		order, err := s.orderRepo.GetByPaymentID(ctx, info.PaymentIntentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// Nothing to track; acknowledge so the provider doesn't keep retrying
+				fmt.Printf("Warning: no order found for disputed payment %s (dispute %s)\n", info.PaymentIntentID, info.ID)
+				return nil
+			}
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+		dispute = &models.Dispute{
+			OrderID:         order.ID,
+			StripeDisputeID: info.ID,
+		}
+	}
+
+	dispute.Amount = info.Amount
+	dispute.Currency = info.Currency
+	dispute.Reason = info.Reason
+	dispute.Status = models.DisputeStatus(info.Status)
+	dispute.EvidenceDueBy = info.EvidenceDueBy
+
+	if isNew {
+		if err := s.disputeRepo.Create(ctx, dispute); err != nil {
+			return fmt.Errorf("failed to create dispute: %w", err)
+		}
+	} else if err := s.disputeRepo.Update(ctx, dispute); err != nil {
+		return fmt.Errorf("failed to update dispute: %w", err)
+	}
+
+	if err := s.orderRepo.UpdatePaymentStatus(ctx, dispute.OrderID, disputePaymentStatus(dispute)); err != nil {
+		return fmt.Errorf("failed to update order payment status: %w", err)
+	}
+
+	if isNew {
+		s.notifyAdmins(ctx, dispute)
+	}
+
+	return nil
+}
+
+// disputePaymentStatus maps a dispute to the order's payment status. A lost
+// dispute means the funds were returned to the customer.
+func disputePaymentStatus(dispute *models.Dispute) models.PaymentStatus {
+	switch {
+	case dispute.IsOpen():
+		return models.PaymentStatusDisputed
+	case dispute.Status == models.DisputeStatusLost:
+		return models.PaymentStatusRefunded
+	default:
+		return models.PaymentStatusPaid
+	}
+}
+
+// notifyAdmins alerts every admin about a new dispute; a failure doesn't block the webhook
+func (s *disputeService) notifyAdmins(ctx context.Context, dispute *models.Dispute) {
+	adminRole := models.RoleAdmin
+	admins, _, err := s.userRepo.List(ctx, 1, 100, &adminRole)
+	if err != nil {
+		fmt.Printf("Warning: failed to get admins for dispute %d: %v\n", dispute.ID, err)
+		return
+	}
+	if len(admins) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("A %.2f %s dispute (%s) was opened on order %d.", dispute.Amount, dispute.Currency, dispute.Reason, dispute.OrderID)
+	if dispute.EvidenceDueBy != nil {
+		message += fmt.Sprintf(" Evidence is due by %s.", dispute.EvidenceDueBy.Format(time.RFC1123))
+	}
+	data := disputeNotificationData(dispute)
+
+	notifications := make([]*models.Notification, len(admins))
+	for i, admin := range admins {
+		notifications[i] = &models.Notification{
+			UserID:  admin.ID,
+			Type:    models.NotificationTypePaymentDisputed,
+			Title:   "New payment dispute",
+			Message: message,
+			Data:    data,
+		}
+	}
+	if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+		fmt.Printf("Warning: failed to notify admins about dispute %d: %v\n", dispute.ID, err)
+	}
+}
+
+func (s *disputeService) GetDisputes(ctx context.Context, status *models.DisputeStatus, limit, offset int) ([]models.DisputeResponse, int64, error) {
+	disputes, total, err := s.disputeRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get disputes: %w", err)
+	}
+
+	responses := make([]models.DisputeResponse, len(disputes))
+	for i, dispute := range disputes {
+		responses[i] = dispute.ToResponse()
+	}
+
+	return responses, total, nil
+}
+
+func (s *disputeService) GetDispute(ctx context.Context, id uint) (*models.DisputeResponse, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDisputeNotFound
+		}
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+
+	response := dispute.ToResponse()
+	return &response, nil
+}
+
+// SubmitEvidence records the evidence prepared for an open dispute
+func (s *disputeService) SubmitEvidence(ctx context.Context, id uint, req *models.DisputeEvidenceRequest, adminID uint) (*models.DisputeResponse, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDisputeNotFound
+		}
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+
+	if !dispute.IsOpen() {
+		return nil, ErrDisputeClosed
+	}
+
+	now := time.Now()
+	dispute.EvidenceNotes = &req.Notes
+	dispute.SetEvidenceDocuments(req.DocumentURLs)
+	dispute.EvidenceSubmittedAt = &now
+	dispute.EvidenceSubmittedBy = &adminID
+
+	if err := s.disputeRepo.Update(ctx, dispute); err != nil {
+		return nil, fmt.Errorf("failed to save dispute evidence: %w", err)
+	}
+
+	response := dispute.ToResponse()
+	return &response, nil
+}
+
+func disputeNotificationData(dispute *models.Dispute) *string {
+	payload, err := json.Marshal(map[string]interface{}{
+		"dispute_id": dispute.ID,
+		"order_id":   dispute.OrderID,
+	})
+	if err != nil {
+		return nil
+	}
+	data := string(payload)
+	return &data
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type emailBroadcastService struct {
+	broadcastRepo repository.EmailBroadcastRepository
+}
+
+func NewEmailBroadcastService(broadcastRepo repository.EmailBroadcastRepository) EmailBroadcastService {
+	return &emailBroadcastService{broadcastRepo: broadcastRepo}
+}
+
+// CreateBroadcast queues the email for every active user in the audience. The
+// email dispatcher sends it in the background at the configured rate.
+func (s *emailBroadcastService) CreateBroadcast(ctx context.Context, req *models.EmailBroadcastRequest, adminID uint) (*models.EmailBroadcastProgress, error) {
+	broadcast := &models.EmailBroadcast{
+		Subject:   req.Subject,
+		Body:      req.Body,
+		Audience:  req.Audience,
+		CreatedBy: adminID,
+	}
+	if err := s.broadcastRepo.CreateWithDeliveries(ctx, broadcast); err != nil {
+		return nil, fmt.Errorf("failed to create email broadcast: %w", err)
+	}
+
+	counts := models.EmailDeliveryCounts{Pending: int64(broadcast.TotalRecipients)}
+	return models.NewEmailBroadcastProgress(broadcast, counts), nil
+}
+
+// GetBroadcast returns the broadcast with how far its sending has got
+func (s *emailBroadcastService) GetBroadcast(ctx context.Context, id uint) (*models.EmailBroadcastProgress, error) {
+	broadcast, err := s.broadcastRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBroadcastNotFound
+		}
+		return nil, fmt.Errorf("failed to get email broadcast: %w", err)
+	}
+
+	counts, err := s.broadcastRepo.CountDeliveries(ctx, []uint{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count email deliveries: %w", err)
+	}
+
+	return models.NewEmailBroadcastProgress(broadcast, counts[id]), nil
+}
+
+func (s *emailBroadcastService) GetBroadcasts(ctx context.Context, limit, offset int) ([]*models.EmailBroadcastProgress, int64, error) {
+	broadcasts, total, err := s.broadcastRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get email broadcasts: %w", err)
+	}
+
+	ids := make([]uint, len(broadcasts))
+	for i, broadcast := range broadcasts {
+		ids[i] = broadcast.ID
+	}
+	counts, err := s.broadcastRepo.CountDeliveries(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count email deliveries: %w", err)
+	}
+
+	progress := make([]*models.EmailBroadcastProgress, len(broadcasts))
+	for i, broadcast := range broadcasts {
+		progress[i] = models.NewEmailBroadcastProgress(broadcast, counts[broadcast.ID])
+	}
+	return progress, total, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/pkg/email"
+	"golang.org/x/time/rate"
+)
+
+// EmailDispatcher sends queued broadcast and recall deliveries in batches
+// without going over the configured per-second and per-minute send rates. Every attempt's
+// result is recorded on the delivery; failures are retried with backoff until
+// the maximum attempts, so a partly failed broadcast finishes on later passes.
+type EmailDispatcher struct {
+	broadcastRepo repository.EmailBroadcastRepository
+	emailSender   email.Service
+
+	limiters    []*rate.Limiter
+	batchSize   int
+	maxAttempts int
+	retryDelay  time.Duration
+	lease       time.Duration
+}
+
+func NewEmailDispatcher(broadcastRepo repository.EmailBroadcastRepository, emailSender email.Service, cfg *config.Config) *EmailDispatcher {
+	d := &EmailDispatcher{
+		broadcastRepo: broadcastRepo,
+		emailSender:   emailSender,
+		batchSize:     cfg.Email.BulkBatchSize,
+		maxAttempts:   cfg.Email.BulkMaxAttempts,
+		retryDelay:    cfg.Email.BulkRetryDelay,
+	}
+	if d.batchSize <= 0 {
+		d.batchSize = 100
+	}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = 1
+	}
+
+	// The slowest window sets how long a batch can take to send
+	perSecond := 0.0
+	if n := cfg.Email.BulkRatePerSecond; n > 0 {
+		d.limiters = append(d.limiters, rate.NewLimiter(rate.Limit(n), n))
+		perSecond = float64(n)
+	}
+	if n := cfg.Email.BulkRatePerMinute; n > 0 {
+		// A small burst keeps any sliding minute under the limit, not just the average
+		burst := n / 60
+		if burst < 1 {
+			burst = 1
+		}
+		d.limiters = append(d.limiters, rate.NewLimiter(rate.Limit(float64(n)/60), burst))
+		if perMinute := float64(n) / 60; perSecond == 0 || perMinute < perSecond {
+			perSecond = perMinute
+		}
+	}
+
+	// Claimed deliveries are held for as long as a batch should take, plus a
+	// second a send for the provider, so other instances don't send them too
+	d.lease = time.Duration(d.batchSize)*time.Second + time.Minute
+	if perSecond > 0 {
+		d.lease += time.Duration(float64(d.batchSize) / perSecond * float64(time.Second))
+	}
+
+	return d
+}
+
+// Start dispatches due deliveries every interval until ctx is done. A
+// non-positive interval disables the dispatcher.
+func (d *EmailDispatcher) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Keep going while full batches come back so a large broadcast
+				// isn't held to one batch per tick
+				for {
+					sent, err := d.DispatchDue(ctx)
+					if err != nil {
+						fmt.Printf("Warning: email dispatch failed: %v\n", err)
+						break
+					}
+					if sent < d.batchSize {
+						break
+					}
+				}
+			}
+		}
+	}()
+}
+
+// DispatchDue sends one batch of due deliveries and returns how many it
+// attempted
+func (d *EmailDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := d.broadcastRepo.ClaimDueDeliveries(ctx, time.Now(), d.lease, d.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim email deliveries: %w", err)
+	}
+
+	for i, delivery := range deliveries {
+		if err := d.wait(ctx); err != nil {
+			// The rest stay claimed and come due again once their lease runs out
+			return i, err
+		}
+		d.send(ctx, delivery)
+	}
+
+	return len(deliveries), nil
+}
+
+// wait blocks until every rate window allows another send
+func (d *EmailDispatcher) wait(ctx context.Context) error {
+	for _, limiter := range d.limiters {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send delivers one email and records the result
+func (d *EmailDispatcher) send(ctx context.Context, delivery *models.EmailDelivery) {
+	sendErr := d.deliver(delivery)
+	if sendErr == nil {
+		if err := d.broadcastRepo.MarkDeliverySent(ctx, delivery.ID, time.Now()); err != nil {
+			fmt.Printf("Warning: failed to record email delivery %d as sent: %v\n", delivery.ID, err)
+		}
+		return
+	}
+
+	// Back off exponentially between attempts; give up after the last one
+	var nextAttemptAt *time.Time
+	if attempts := delivery.Attempts + 1; attempts < d.maxAttempts {
+		retryAt := time.Now().Add(d.retryDelay << (attempts - 1))
+		nextAttemptAt = &retryAt
+	}
+	if err := d.broadcastRepo.MarkDeliveryFailed(ctx, delivery.ID, sendErr.Error(), nextAttemptAt); err != nil {
+		fmt.Printf("Warning: failed to record email delivery %d failure: %v\n", delivery.ID, err)
+	}
+}
+
+// deliver sends the delivery's email: its recall notice, or its broadcast
+func (d *EmailDispatcher) deliver(delivery *models.EmailDelivery) error {
+	switch {
+	case delivery.Recall != nil:
+		return d.emailSender.SendProductRecallEmail(delivery.Email, delivery.Name, delivery.Recall.Product.Name, delivery.Recall.Message)
+	case delivery.Broadcast != nil:
+		return d.emailSender.SendBroadcastEmail(delivery.Email, delivery.Name, delivery.Broadcast.Subject, delivery.Broadcast.Body)
+	}
+	return fmt.Errorf("email delivery %d has no broadcast or recall", delivery.ID)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+func TestDispatchDueSendsRecallNoticesAndBroadcasts(t *testing.T) {
+	broadcastID, recallID := uint(1), uint(2)
+	queue := &fakeDeliveryQueue{due: []*models.EmailDelivery{
+		{ID: 10, BroadcastID: &broadcastID, Email: "a@example.com", Broadcast: &models.EmailBroadcast{Subject: "Summer sale"}},
+		{ID: 11, RecallID: &recallID, Email: "b@example.com", Recall: &models.ProductRecall{Message: "Stop using it", Product: models.Product{Name: "Kettle"}}},
+	}}
+	sender := &recordingSender{}
+
+	sent, err := NewEmailDispatcher(queue, sender, &config.Config{}).DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("DispatchDue: %v", err)
+	}
+
+	if sent != 2 || len(queue.sent) != 2 {
+		t.Errorf("sent %d, recorded %v, want both deliveries", sent, queue.sent)
+	}
+	if len(sender.broadcasts) != 1 || sender.broadcasts[0] != "Summer sale" {
+		t.Errorf("broadcasts = %v, want the summer sale", sender.broadcasts)
+	}
+	if len(sender.recalls) != 1 || sender.recalls[0] != "Kettle" {
+		t.Errorf("recall notices = %v, want the kettle's", sender.recalls)
+	}
+}
@@ -18,7 +18,11 @@ func NewEmailService(emailSender email.Service) EmailService {
 	}
 }
 
-func (s *emailService) SendWelcomeEmail(ctx context.Context, user *models.User) error {
+// SendWelcomeEmail welcomes a new user, with their welcome coupon if they were issued one
+func (s *emailService) SendWelcomeEmail(ctx context.Context, user *models.User, coupon *models.Coupon) error {
+	if coupon != nil {
+		return s.emailSender.SendWelcomeCouponEmail(user.Email, user.FirstName, coupon.Code, coupon.Describe(), coupon.ExpiresAt)
+	}
 	return s.emailSender.SendWelcomeEmail(user.Email, user.FirstName)
 }
 
@@ -44,9 +48,8 @@ func (s *emailService) SendPasswordResetEmail(ctx context.Context, user *models.
 }
 
 func (s *emailService) SendEmailVerificationEmail(ctx context.Context, user *models.User, verificationToken string) error {
-	// For now, use password reset email as template since verification email is not in the interface
 	verificationLink := fmt.Sprintf("https://yourdomain.com/verify-email?token=%s", verificationToken)
-	return s.emailSender.SendPasswordResetEmail(user.Email, verificationLink)
+	return s.emailSender.SendEmailVerificationEmail(user.Email, verificationLink)
 }
 
 func (s *emailService) SendLowStockAlert(ctx context.Context, seller *models.User, product *models.Product) error {
@@ -54,7 +57,23 @@ func (s *emailService) SendLowStockAlert(ctx context.Context, seller *models.Use
 	return s.emailSender.SendWelcomeEmail(seller.Email, seller.FirstName)
 }
 
+func (s *emailService) SendOversellAlert(ctx context.Context, seller *models.User, product *models.Product, order *models.Order, stock int) error {
+	return s.emailSender.SendOversellAlertEmail(seller.Email, seller.FirstName, product.Name, order.OrderNumber, stock)
+}
+
 func (s *emailService) SendNewReviewNotification(ctx context.Context, seller *models.User, product *models.Product, review *models.Review) error {
 	// Since this is not in the email.Service interface, we'll use a basic welcome email format
 	return s.emailSender.SendWelcomeEmail(seller.Email, seller.FirstName)
 }
+
+func (s *emailService) SendReviewReminder(ctx context.Context, user *models.User, order *models.Order, products []*models.Product) error {
+	names := make([]string, len(products))
+	for i, product := range products {
+		names[i] = product.Name
+	}
+	return s.emailSender.SendReviewReminderEmail(user.Email, user.FirstName, order.OrderNumber, names)
+}
+
+func (s *emailService) SendGuestOrderEmail(ctx context.Context, user *models.User, order *models.Order) error {
+	return s.emailSender.SendGuestOrderEmail(user.Email, user.FirstName, order.OrderNumber)
+}
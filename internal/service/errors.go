@@ -0,0 +1,276 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+)
+
+// Error kinds. Every service error wraps one of these, so a handler can map a
+// whole family to a status with errors.Is(err, ErrNotFound) and only needs the
+// specific error when it responds differently.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrInvalid      = errors.New("invalid")
+	ErrConflict     = errors.New("conflict")
+	ErrLimitReached = errors.New("limit reached")
+)
+
+// Error is an error a service returns to its caller on purpose. Its message is
+// safe to show to clients and wraps the error it is an instance of, so callers
+// match it with errors.Is rather than by comparing messages. Errors clients
+// can branch on also carry an API error code.
+type Error struct {
+	message string
+	code    apierror.Code
+	err     error
+}
+
+func (e *Error) Error() string { return e.message }
+
+func (e *Error) Unwrap() error { return e.err }
+
+// Code returns the error's API error code, or "" if it has none
+func (e *Error) Code() apierror.Code { return e.code }
+
+func newError(kind error, message string) *Error {
+	return &Error{message: message, err: kind}
+}
+
+// withCode sets the API error code sent with the error
+func (e *Error) withCode(code apierror.Code) *Error {
+	e.code = code
+	return e
+}
+
+// errorf returns an instance of err with a more detailed message and err's code
+func errorf(err error, format string, args ...interface{}) error {
+	var code apierror.Code
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) {
+		code = serviceErr.code
+	}
+	return &Error{message: fmt.Sprintf(format, args...), code: code, err: err}
+}
+
+// Accounts
+var (
+	ErrAccountDeactivated       = newError(ErrForbidden, "account is deactivated").withCode(apierror.AccountDeactivated)
+	ErrInvalidCredentials       = newError(ErrUnauthorized, "invalid email or password").withCode(apierror.InvalidCredentials)
+	ErrInvalidToken             = newError(ErrUnauthorized, "invalid or expired token").withCode(apierror.TokenInvalid)
+	ErrEmailTaken               = newError(ErrConflict, "user with this email already exists").withCode(apierror.EmailTaken)
+	ErrEmailAlreadyVerified     = newError(ErrConflict, "email already verified").withCode(apierror.EmailAlreadyVerified)
+	ErrIncorrectPassword        = newError(ErrInvalid, "current password is incorrect").withCode(apierror.PasswordIncorrect)
+	ErrUserNotFound             = newError(ErrNotFound, "user not found").withCode(apierror.UserNotFound)
+	ErrNotASeller               = newError(ErrInvalid, "user is not a seller").withCode(apierror.NotASeller)
+	ErrSellerAlreadyActive      = newError(ErrConflict, "seller is already active")
+	ErrSellerAlreadyDeactivated = newError(ErrConflict, "seller is already deactivated")
+	ErrSellerNotFound           = newError(ErrNotFound, "seller not found").withCode(apierror.SellerNotFound)
+	ErrStaffNotFound            = newError(ErrNotFound, "staff member not found").withCode(apierror.StaffNotFound)
+	ErrWeakPassword             = newError(ErrInvalid, "password does not meet the password policy")
+	ErrGuestClaimUnverified     = newError(ErrForbidden, "this email has guest orders; register again with the verification token sent to it").withCode(apierror.EmailVerificationRequired)
+)
+
+// Addresses
+var (
+	ErrAddressNotFound = newError(ErrNotFound, "address not found")
+	ErrAddressBookFull = newError(ErrLimitReached, "address book is full")
+)
+
+// Products
+var (
+	ErrProductNotFound           = newError(ErrNotFound, "product not found").withCode(apierror.ProductNotFound)
+	ErrSKUTaken                  = newError(ErrConflict, "a product with this SKU already exists").withCode(apierror.SKUTaken)
+	ErrProductUnavailable        = newError(ErrInvalid, "product is not available").withCode(apierror.ProductUnavailable)
+	ErrInsufficientStock         = newError(ErrInvalid, "insufficient stock").withCode(apierror.InsufficientStock)
+	ErrProductUpdateForbidden    = newError(ErrForbidden, "unauthorized to update this product").withCode(apierror.ProductForbidden)
+	ErrProductDeleteForbidden    = newError(ErrForbidden, "unauthorized to delete this product").withCode(apierror.ProductForbidden)
+	ErrStockUpdateForbidden      = newError(ErrForbidden, "unauthorized to update this product's stock").withCode(apierror.ProductForbidden)
+	ErrTranslationsForbidden     = newError(ErrForbidden, "unauthorized to manage this product's translations").withCode(apierror.ProductForbidden)
+	ErrPriceHistoryForbidden     = newError(ErrForbidden, "unauthorized to view this product's price history").withCode(apierror.ProductForbidden)
+	ErrRecallForbidden           = newError(ErrForbidden, "unauthorized to recall this product").withCode(apierror.ProductForbidden)
+	ErrInvalidPrice              = newError(ErrInvalid, "product price must be greater than 0")
+	ErrComparePriceTooLow        = newError(ErrInvalid, "compare price must be greater than price")
+	ErrNegativeProductStock      = newError(ErrInvalid, "product stock cannot be negative")
+	ErrNegativeStock             = newError(ErrInvalid, "stock cannot be negative")
+	ErrNegativeBackorderLimit    = newError(ErrInvalid, "max backorder quantity cannot be negative")
+	ErrBackordersDisabled        = newError(ErrInvalid, "max backorder quantity requires backorders to be enabled")
+	ErrNegativeReturnWindow      = newError(ErrInvalid, "return window cannot be negative")
+	ErrReturnWindowNotReturnable = newError(ErrInvalid, "return window requires the product to be returnable")
+	ErrNegativeProcessingTime    = newError(ErrInvalid, "processing time cannot be negative")
+	ErrInvalidAvailabilityWindow = newError(ErrInvalid, "available until must not be before available from")
+	ErrEmptyCategory             = newError(ErrInvalid, "category cannot be empty")
+	ErrEmptySearchQuery          = newError(ErrInvalid, "search query cannot be empty")
+	ErrInvalidPriceRange         = newError(ErrInvalid, "min_price cannot be greater than max_price")
+	ErrInvalidLocale             = newError(ErrInvalid, "invalid locale")
+	ErrTooManyProductIDs         = newError(ErrInvalid, "too many product IDs")
+	ErrProductPendingApproval    = newError(ErrConflict, "product is awaiting approval").withCode(apierror.ProductPendingApproval)
+	ErrProductNotPendingApproval = newError(ErrConflict, "product is not awaiting approval")
+	ErrInvalidAttentionReason    = newError(ErrInvalid, "invalid reason (use out_of_stock, low_rating, missing_images, missing_category, missing_description, stale_draft or missing_short_description)")
+)
+
+// Product images
+var (
+	ErrImageNotFound     = newError(ErrNotFound, "image not found").withCode(apierror.ImageNotFound)
+	ErrImageNotOnProduct = newError(ErrInvalid, "image does not belong to the specified product")
+	ErrImageLimitReached = newError(ErrLimitReached, "product image limit reached").withCode(apierror.ImageLimitReached)
+	ErrInvalidImageURL   = newError(ErrInvalid, "invalid image URL")
+)
+
+// Categories
+var (
+	ErrCategoryNotFound    = newError(ErrNotFound, "category not found").withCode(apierror.CategoryNotFound)
+	ErrCategoryHasChildren = newError(ErrConflict, "cannot delete category with subcategories")
+	ErrCategoryNameTaken   = newError(ErrConflict, "a category with this name already exists").withCode(apierror.CategoryNameTaken)
+	ErrInvalidProductSort  = newError(ErrInvalid, "invalid sort (use newest, best_selling, price_asc, price_desc, rating, popular or name)")
+)
+
+// Cart
+var (
+	ErrCartItemNotFound = newError(ErrNotFound, "item not found in cart").withCode(apierror.CartItemNotFound)
+	ErrCartItemLimit    = newError(ErrLimitReached, "cart can't hold any more different products").withCode(apierror.CartItemLimitReached)
+	ErrCartValueLimit   = newError(ErrLimitReached, "cart value limit reached").withCode(apierror.CartValueLimitReached)
+)
+
+// Wishlist
+var (
+	ErrAlreadyInWishlist = newError(ErrConflict, "product already in wishlist")
+)
+
+// Orders
+var (
+	ErrOrderNotFound                = newError(ErrNotFound, "order not found").withCode(apierror.OrderNotFound)
+	ErrEmptyOrder                   = newError(ErrInvalid, "order must contain at least one item")
+	ErrOrderViewForbidden           = newError(ErrForbidden, "unauthorized to view this order").withCode(apierror.OrderForbidden)
+	ErrOrderUpdateForbidden         = newError(ErrForbidden, "unauthorized to update this order").withCode(apierror.OrderForbidden)
+	ErrOrderModifyForbidden         = newError(ErrForbidden, "unauthorized to modify this order").withCode(apierror.OrderForbidden)
+	ErrOrderCancelForbidden         = newError(ErrForbidden, "unauthorized to cancel this order").withCode(apierror.OrderForbidden)
+	ErrOrderStatusForbidden         = newError(ErrForbidden, "unauthorized to update order status").withCode(apierror.OrderForbidden)
+	ErrProductOrdersForbidden       = newError(ErrForbidden, "unauthorized to view orders for this product").withCode(apierror.ProductForbidden)
+	ErrInvalidStatusTransition      = newError(ErrInvalid, "invalid status transition").withCode(apierror.InvalidStatusTransition)
+	ErrOrderNotModifiable           = newError(ErrConflict, "order can no longer be modified").withCode(apierror.OrderNotModifiable)
+	ErrOrderShipped                 = newError(ErrConflict, "order has already shipped").withCode(apierror.OrderNotModifiable)
+	ErrOrderCancelled               = newError(ErrConflict, "order is cancelled").withCode(apierror.OrderNotModifiable)
+	ErrShippingCostIncrease         = newError(ErrConflict, "shipping to this address costs more than was paid; cancel the order and place it again").withCode(apierror.ShippingCostIncrease)
+	ErrOrderNotCancellable          = newError(ErrConflict, "order cannot be cancelled in its current status").withCode(apierror.OrderNotCancellable)
+	ErrPartialCancel                = newError(ErrInvalid, "cannot cancel one seller's portion of a split order").withCode(apierror.OrderNotCancellable)
+	ErrOrderNotPending              = newError(ErrConflict, "order is not in pending status")
+	ErrOrderNotPendingReview        = newError(ErrConflict, "order is not pending review")
+	ErrOrderNotAwaitingConfirmation = newError(ErrConflict, "order is not awaiting confirmation")
+	ErrPurchaseLimitReached         = newError(ErrLimitReached, "purchase limit reached").withCode(apierror.PurchaseLimitReached)
+	ErrMinimumOrderNotMet           = newError(ErrInvalid, "minimum order amount not met").withCode(apierror.MinimumOrderNotMet)
+	ErrStockNotCommitted            = newError(ErrConflict, "stock could not be committed for this order; the payment has been refunded").withCode(apierror.StockNotCommitted)
+	ErrReservationReleased          = newError(ErrConflict, "stock reservation was released")
+	ErrReservationHoldLimit         = newError(ErrLimitReached, "stock reservation can't be extended any further")
+	ErrPaymentAmountMismatch        = newError(ErrInvalid, "payment amount does not match the order total")
+	ErrPaymentNotCaptured           = newError(ErrConflict, "payment amount collected does not match the order total; the payment has been refunded")
+	ErrPaymentNotCollected          = newError(ErrConflict, "payment has not been collected yet")
+	ErrOrderPaymentDisputed         = newError(ErrConflict, "the order's payment is disputed and can't be refunded until the dispute closes").withCode(apierror.PaymentDisputed)
+	ErrPaymentNotRefundable         = newError(ErrConflict, "the order's payment is disputed or already refunded")
+	ErrResendLimitReached           = newError(ErrLimitReached, "confirmation email resend limit reached").withCode(apierror.ResendLimitReached)
+	ErrOrderAssignForbidden         = newError(ErrForbidden, "unauthorized to assign this order").withCode(apierror.OrderForbidden)
+	ErrOrderNotAssignable           = newError(ErrConflict, "finished orders can't be assigned")
+	ErrAssignSellerRequired         = newError(ErrInvalid, "seller_id is required to assign a split order")
+	ErrRecalculateNeedsForce        = newError(ErrConflict, "order was charged or has shipped; recalculating it needs force")
+	ErrGuestCheckoutDisabled        = newError(ErrForbidden, "guest checkout is disabled")
+	ErrGuestEmailRegistered         = newError(ErrConflict, "an account exists for this email; sign in to check out")
+)
+
+// Returns
+var (
+	ErrReturnNotFound         = newError(ErrNotFound, "return request not found").withCode(apierror.ReturnNotFound)
+	ErrOrderItemNotFound      = newError(ErrNotFound, "order item not found")
+	ErrReturnNotDelivered     = newError(ErrInvalid, "item has not been delivered yet").withCode(apierror.ReturnNotEligible)
+	ErrReturnWindowClosed     = newError(ErrInvalid, "item is not returnable or its return window has closed").withCode(apierror.ReturnNotEligible)
+	ErrReturnQuantityExceeded = newError(ErrInvalid, "return quantity exceeds the units left to return").withCode(apierror.ReturnQuantityExceeded)
+	ErrReturnClosed           = newError(ErrConflict, "return request has already been resolved").withCode(apierror.ReturnClosed)
+	ErrReturnNotRefundable    = newError(ErrConflict, "order payment can no longer be refunded").withCode(apierror.ReturnNotEligible)
+)
+
+// Coupons
+var (
+	ErrInvalidCoupon        = newError(ErrInvalid, "invalid coupon code").withCode(apierror.CouponInvalid)
+	ErrCouponExpired        = newError(ErrInvalid, "coupon has expired").withCode(apierror.CouponExpired)
+	ErrCouponMinSpend       = newError(ErrInvalid, "order does not meet the coupon's minimum spend").withCode(apierror.CouponMinSpendNotMet)
+	ErrCouponNotApplicable  = newError(ErrInvalid, "coupon does not apply to any item in the order").withCode(apierror.CouponNotApplicable)
+	ErrCouponUsageLimit     = newError(ErrLimitReached, "coupon usage limit reached").withCode(apierror.CouponUsageLimitReached)
+	ErrCouponCodeTaken      = newError(ErrConflict, "coupon code already exists").withCode(apierror.CouponCodeTaken)
+	ErrCouponPercentTooHigh = newError(ErrInvalid, "percent coupon value cannot exceed 100")
+	ErrExpiryInPast         = newError(ErrInvalid, "expiry must be in the future")
+)
+
+// Promotions
+var (
+	ErrPromotionNotFound         = newError(ErrNotFound, "promotion not found")
+	ErrPromotionCategoryRequired = newError(ErrInvalid, "category is required for a category promotion")
+	ErrPromotionEndsBeforeStart  = newError(ErrInvalid, "promotion must end after it starts")
+	ErrDiscountPercentRequired   = newError(ErrInvalid, "discount percent must be greater than zero")
+	ErrMinSpendDiscountRequired  = newError(ErrInvalid, "minimum spend and discount amount must be greater than zero")
+	ErrDiscountExceedsMinSpend   = newError(ErrInvalid, "discount amount cannot exceed the minimum spend")
+	ErrBuyGetQuantitiesRequired  = newError(ErrInvalid, "buy and get quantities must be greater than zero")
+)
+
+// Reviews
+var (
+	ErrReviewNotFound        = newError(ErrNotFound, "review not found").withCode(apierror.ReviewNotFound)
+	ErrInvalidRating         = newError(ErrInvalid, "rating must be between 1 and 5")
+	ErrReviewNotPurchased    = newError(ErrForbidden, "you can only review products you have purchased and received").withCode(apierror.ReviewNotAllowed)
+	ErrAlreadyReviewed       = newError(ErrConflict, "you have already reviewed this product").withCode(apierror.AlreadyReviewed)
+	ErrReviewOrderMismatch   = newError(ErrInvalid, "the product isn't part of one of your orders").withCode(apierror.ReviewNotAllowed)
+	ErrReviewUpdateForbidden = newError(ErrForbidden, "unauthorized to update this review").withCode(apierror.ReviewForbidden)
+	ErrReviewDeleteForbidden = newError(ErrForbidden, "unauthorized to delete this review").withCode(apierror.ReviewForbidden)
+	ErrInvalidReviewSort     = newError(ErrInvalid, "invalid sort (use newest, oldest, rating_high, rating_low or most_helpful)")
+)
+
+// Questions
+var (
+	ErrQuestionNotFound = newError(ErrNotFound, "question not found")
+)
+
+// Shipping
+var (
+	ErrShippingZoneNotFound    = newError(ErrNotFound, "shipping zone not found")
+	ErrZoneWithoutDestinations = newError(ErrInvalid, "shipping zone must list countries or regions unless it is the default zone")
+	ErrInvalidRateBracket      = newError(ErrInvalid, "rate max weight must be greater than its min weight")
+	ErrOverlappingRates        = newError(ErrInvalid, "rate weight brackets must not overlap")
+)
+
+// Featured sellers
+var (
+	ErrFeaturedSellerNotFound  = newError(ErrNotFound, "featured seller not found")
+	ErrSellerAlreadyFeatured   = newError(ErrConflict, "seller is already featured")
+	ErrFeaturedSellerLimit     = newError(ErrLimitReached, "featured seller limit reached")
+	ErrDuplicateFeaturedSeller = newError(ErrInvalid, "duplicate seller in order")
+)
+
+// Support and messaging
+var (
+	ErrTicketNotFound       = newError(ErrNotFound, "support ticket not found")
+	ErrTicketClosed         = newError(ErrConflict, "support ticket is closed")
+	ErrConversationNotFound = newError(ErrNotFound, "conversation not found")
+	ErrMessageSelf          = newError(ErrInvalid, "cannot message yourself")
+)
+
+// Disputes
+var (
+	ErrDisputeNotFound = newError(ErrNotFound, "dispute not found")
+	ErrDisputeClosed   = newError(ErrConflict, "dispute is already closed")
+	ErrInvalidWebhook  = newError(ErrInvalid, "invalid webhook")
+)
+
+// Notifications and email
+var (
+	ErrNotificationNotFound  = newError(ErrNotFound, "notification not found")
+	ErrNotificationForbidden = newError(ErrForbidden, "unauthorized")
+	ErrBroadcastNotFound     = newError(ErrNotFound, "email broadcast not found")
+)
+
+// Errors passed through unchanged from the repositories
+var (
+	ErrProductImageNotFound = repository.ErrProductImageNotFound
+	ErrPrimaryImageNotFound = repository.ErrPrimaryImageNotFound
+)
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
+)
+
+func TestErrorCodeSurvivesDetailAndWrapping(t *testing.T) {
+	err := fmt.Errorf("failed to reserve stock: %w",
+		errorf(ErrInsufficientStock, "insufficient stock for product %s (requested: %d)", "Mug", 3))
+
+	if code := apierror.Resolve(http.StatusBadRequest, err); code != apierror.InsufficientStock {
+		t.Errorf("code = %s, want %s", code, apierror.InsufficientStock)
+	}
+}
+
+func TestErrorCodeIsNotSentWithServerErrors(t *testing.T) {
+	if code := apierror.Resolve(http.StatusInternalServerError, ErrOrderNotFound); code != apierror.Internal {
+		t.Errorf("code = %s, want %s", code, apierror.Internal)
+	}
+}
+
+func TestUncodedErrorFallsBackToStatusCode(t *testing.T) {
+	if code := apierror.Resolve(http.StatusConflict, ErrTicketClosed); code != apierror.Conflict {
+		t.Errorf("code = %s, want %s", code, apierror.Conflict)
+	}
+}
@@ -0,0 +1,704 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/pkg/email"
+	"github.com/JonathanVera18/ecommerce-api/pkg/payment"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Fakes embed the repository interfaces so each only implements what the
+// tests call; anything else panics on the nil embedded value.
+
+type fakeOrderRepo struct {
+	repository.OrderRepository
+
+	mu      sync.Mutex
+	orders  map[uint]*models.Order
+	history []*models.OrderStatusHistory
+	audit   []*models.AuditLog
+}
+
+func newFakeOrderRepo(orders ...*models.Order) *fakeOrderRepo {
+	repo := &fakeOrderRepo{orders: make(map[uint]*models.Order)}
+	for _, order := range orders {
+		repo.orders[order.ID] = order
+	}
+	return repo
+}
+
+func (r *fakeOrderRepo) GetByID(ctx context.Context, id uint) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (r *fakeOrderRepo) GetByOrderNumber(ctx context.Context, orderNumber string) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		if order.OrderNumber == orderNumber {
+			copied := *order
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeOrderRepo) GetByPaymentID(ctx context.Context, paymentID string) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		if order.PaymentID != nil && *order.PaymentID == paymentID {
+			copied := *order
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeOrderRepo) SaveRecalculation(ctx context.Context, order *models.Order, entry *models.AuditLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[order.ID] = order
+	r.audit = append(r.audit, entry)
+	return nil
+}
+
+func (r *fakeOrderRepo) UpdateShippingAddress(ctx context.Context, order *models.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[order.ID] = order
+	return nil
+}
+
+func (r *fakeOrderRepo) status(id uint) models.OrderStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.orders[id].Status
+}
+
+func (r *fakeOrderRepo) UpdateStatus(ctx context.Context, id uint, status models.OrderStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[id].Status = status
+	return nil
+}
+
+func (r *fakeOrderRepo) Cancel(ctx context.Context, id uint, reason models.CancellationReason, note *string) error {
+	return r.UpdateStatus(ctx, id, models.OrderStatusCancelled)
+}
+
+func (r *fakeOrderRepo) CancelPending(ctx context.Context, id uint, reason models.CancellationReason, note *string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.orders[id].Status != models.OrderStatusPending {
+		return false, nil
+	}
+	r.orders[id].Status = models.OrderStatusCancelled
+	return true, nil
+}
+
+func (r *fakeOrderRepo) UpdatePaymentStatus(ctx context.Context, id uint, status models.PaymentStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[id].PaymentStatus = status
+	return nil
+}
+
+func (r *fakeOrderRepo) UpdatePaymentID(ctx context.Context, id uint, paymentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[id].PaymentID = &paymentID
+	return nil
+}
+
+func (r *fakeOrderRepo) AddStatusHistory(ctx context.Context, history *models.OrderStatusHistory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = append(r.history, history)
+	return nil
+}
+
+type fakeReservationRepo struct {
+	repository.StockReservationRepository
+
+	mu           sync.Mutex
+	expired      []uint
+	reservations map[uint][]models.StockReservation
+	commitErr    error
+}
+
+func (r *fakeReservationRepo) GetExpiredOrderIDs(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	return r.expired, nil
+}
+
+func (r *fakeReservationRepo) CountByOrderID(ctx context.Context, orderID uint, status models.ReservationStatus) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, reservation := range r.reservations[orderID] {
+		if reservation.Status == status {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeReservationRepo) HasAny(ctx context.Context, orderID uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.reservations[orderID]) > 0, nil
+}
+
+func (r *fakeReservationRepo) Release(ctx context.Context, orderID uint, statuses ...models.ReservationStatus) ([]models.StockReservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var released []models.StockReservation
+	for i, reservation := range r.reservations[orderID] {
+		for _, status := range statuses {
+			if reservation.Status == status {
+				released = append(released, reservation)
+				r.reservations[orderID][i].Status = models.ReservationStatusReleased
+			}
+		}
+	}
+	return released, nil
+}
+
+func (r *fakeReservationRepo) ExtendActive(ctx context.Context, orderID uint, now, expiresAt time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var extended int64
+	for i, reservation := range r.reservations[orderID] {
+		if reservation.Status == models.ReservationStatusReserved && reservation.ExpiresAt.After(now) {
+			if expiresAt.After(reservation.ExpiresAt) {
+				r.reservations[orderID][i].ExpiresAt = expiresAt
+			}
+			extended++
+		}
+	}
+	return extended, nil
+}
+
+func (r *fakeReservationRepo) Commit(ctx context.Context, orderID uint) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.commitErr != nil {
+		return 0, r.commitErr
+	}
+	var committed int64
+	for i, reservation := range r.reservations[orderID] {
+		if reservation.Status == models.ReservationStatusReserved {
+			r.reservations[orderID][i].Status = models.ReservationStatusCommitted
+			committed++
+		}
+	}
+	return committed, nil
+}
+
+type fakeProductRepo struct {
+	repository.ProductRepository
+
+	mu       sync.Mutex
+	products map[uint]*models.Product
+	adjusted map[uint]int
+	ratings  map[uint]int // Rating updates per product
+}
+
+func newFakeProductRepo(products ...*models.Product) *fakeProductRepo {
+	repo := &fakeProductRepo{products: make(map[uint]*models.Product), adjusted: make(map[uint]int), ratings: make(map[uint]int)}
+	for _, product := range products {
+		repo.products[product.ID] = product
+	}
+	return repo
+}
+
+func (r *fakeProductRepo) GetByID(ctx context.Context, id uint) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, ok := r.products[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *product
+	return &copied, nil
+}
+
+func (r *fakeProductRepo) SKUExists(ctx context.Context, sku string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, product := range r.products {
+		if product.SKU == sku && !product.DeletedAt.Valid {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeProductRepo) UpdateRating(ctx context.Context, productID uint, averageRating float64, reviewCount int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ratings[productID]++
+	return nil
+}
+
+func (r *fakeProductRepo) AdjustStock(ctx context.Context, id uint, delta int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.adjusted[id] += delta
+	return nil
+}
+
+func (r *fakeProductRepo) GetByIDs(ctx context.Context, ids []uint) ([]*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var products []*models.Product
+	for _, id := range ids {
+		if product, ok := r.products[id]; ok {
+			copied := *product
+			products = append(products, &copied)
+		}
+	}
+	return products, nil
+}
+
+// fakeCartRepo holds one cart per user, its lines priced from products
+type fakeCartRepo struct {
+	repository.CartRepository
+
+	mu       sync.Mutex
+	products *fakeProductRepo
+	carts    map[uint]*models.Cart
+}
+
+func newFakeCartRepo(products *fakeProductRepo) *fakeCartRepo {
+	return &fakeCartRepo{products: products, carts: make(map[uint]*models.Cart)}
+}
+
+func (r *fakeCartRepo) GetOrCreateCart(ctx context.Context, userID uint) (*models.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cart, ok := r.carts[userID]
+	if !ok {
+		cart = &models.Cart{BaseModel: models.BaseModel{ID: userID}, CustomerID: userID}
+		r.carts[userID] = cart
+	}
+	return cart, nil
+}
+
+func (r *fakeCartRepo) GetCartWithItems(ctx context.Context, userID uint) (*models.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cart, ok := r.carts[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *cart
+	copied.CartItems = append([]models.CartItem(nil), cart.CartItems...)
+	for i := range copied.CartItems {
+		product, _ := r.products.GetByID(ctx, copied.CartItems[i].ProductID)
+		copied.CartItems[i].Product = *product
+	}
+	return &copied, nil
+}
+
+func (r *fakeCartRepo) UpsertItemQuantity(ctx context.Context, cartID, productID uint, quantity, maxQuantity int) (*models.CartItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cart := r.carts[cartID]
+	for i := range cart.CartItems {
+		if item := &cart.CartItems[i]; item.ProductID == productID {
+			item.Quantity += quantity
+			if item.Quantity > maxQuantity {
+				item.Quantity = maxQuantity
+			}
+			copied := *item
+			return &copied, nil
+		}
+	}
+	if quantity > maxQuantity {
+		quantity = maxQuantity
+	}
+	item := models.CartItem{CartID: cartID, ProductID: productID, Quantity: quantity}
+	cart.CartItems = append(cart.CartItems, item)
+	return &item, nil
+}
+
+// recordingPayments is the sandbox payment service, recording refunds
+type recordingPayments struct {
+	*payment.MockService
+
+	refunds []float64
+}
+
+func (p *recordingPayments) RefundPayment(paymentIntentID string, amount float64) error {
+	if err := p.MockService.RefundPayment(paymentIntentID, amount); err != nil {
+		return err
+	}
+	p.refunds = append(p.refunds, amount)
+	return nil
+}
+
+// newOfflineProductCache returns a cache whose Redis can't be reached, so
+// every read misses and invalidations are logged and ignored
+func newOfflineProductCache() *ProductCache {
+	return NewProductCache(redis.NewClient(&redis.Options{Addr: "127.0.0.1:0", MaxRetries: -1}))
+}
+
+// fakeUserRepo stores tokens in memory. Its lookups don't filter expired or
+// used tokens, so the service's own checks are what reject them.
+type fakeUserRepo struct {
+	repository.UserRepository
+
+	mu           sync.Mutex
+	users        map[uint]*models.User
+	resetTokens  map[string]*models.PasswordResetToken
+	verifyTokens map[string]*models.EmailVerificationToken
+}
+
+func newFakeUserRepo(users ...*models.User) *fakeUserRepo {
+	repo := &fakeUserRepo{
+		users:        make(map[uint]*models.User),
+		resetTokens:  make(map[string]*models.PasswordResetToken),
+		verifyTokens: make(map[string]*models.EmailVerificationToken),
+	}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) CreatePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resetTokens[token.Token] = token
+	return nil
+}
+
+func (r *fakeUserRepo) GetPasswordResetToken(ctx context.Context, tokenStr string) (*models.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.resetTokens[tokenStr]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *token
+	copied.User = *r.users[token.UserID]
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) MarkPasswordResetTokenUsed(ctx context.Context, tokenStr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.resetTokens[tokenStr]
+	if !ok || token.UsedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return nil
+}
+
+func (r *fakeUserRepo) InvalidatePasswordResetTokens(ctx context.Context, userID uint) error {
+	return nil
+}
+
+func (r *fakeUserRepo) CreateEmailVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verifyTokens[token.Token] = token
+	return nil
+}
+
+func (r *fakeUserRepo) GetEmailVerificationToken(ctx context.Context, tokenStr string) (*models.EmailVerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.verifyTokens[tokenStr]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) MarkEmailVerificationTokenUsed(ctx context.Context, tokenStr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.verifyTokens[tokenStr]
+	if !ok || token.UsedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return nil
+}
+
+func (r *fakeUserRepo) InvalidateEmailVerificationTokens(ctx context.Context, userID uint) error {
+	return nil
+}
+
+func (r *fakeUserRepo) MarkEmailVerified(ctx context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[userID].IsVerified = true
+	return nil
+}
+
+func (r *fakeUserRepo) UpdateLastLogin(ctx context.Context, userID uint) error {
+	return nil
+}
+
+// recordingEmails records the verification tokens and welcome emails it's asked to send
+type recordingEmails struct {
+	EmailService
+
+	verificationTokens []string
+	welcomed           []*models.User
+	welcomeCoupons     []*models.Coupon
+}
+
+func (e *recordingEmails) SendWelcomeEmail(ctx context.Context, user *models.User, coupon *models.Coupon) error {
+	e.welcomed = append(e.welcomed, user)
+	e.welcomeCoupons = append(e.welcomeCoupons, coupon)
+	return nil
+}
+
+func (e *recordingEmails) SendEmailVerificationEmail(ctx context.Context, user *models.User, verificationToken string) error {
+	e.verificationTokens = append(e.verificationTokens, verificationToken)
+	return nil
+}
+
+type fakeReviewRepo struct {
+	repository.ReviewRepository
+}
+
+func (r *fakeReviewRepo) GetAverageRatingByProductID(ctx context.Context, productID uint) (float64, error) {
+	return 4, nil
+}
+
+func (r *fakeReviewRepo) CountByProductID(ctx context.Context, productID uint) (int64, error) {
+	return 2, nil
+}
+
+type fakeCategoryRepo struct {
+	repository.CategoryRepository
+
+	categories map[string]*models.Category // By slug
+}
+
+func (r *fakeCategoryRepo) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	if category, ok := r.categories[slug]; ok {
+		return category, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// noPromotions is a promotion store with nothing running
+type noPromotions struct {
+	repository.PromotionRepository
+}
+
+func (noPromotions) GetRunning(ctx context.Context, at time.Time) ([]*models.Promotion, error) {
+	return nil, nil
+}
+
+// flatShipping quotes the same cost everywhere
+type flatShipping struct {
+	ShippingService
+	cost float64
+}
+
+func (s flatShipping) Quote(ctx context.Context, subtotal float64, dest models.ShippingDestination, weight float64) *models.ShippingQuote {
+	return &models.ShippingQuote{Cost: s.cost}
+}
+
+type fakeReturnRepo struct {
+	repository.ReturnRequestRepository
+
+	mu      sync.Mutex
+	returns map[uint]*models.ReturnRequest
+	nextID  uint
+}
+
+func newFakeReturnRepo() *fakeReturnRepo {
+	return &fakeReturnRepo{returns: make(map[uint]*models.ReturnRequest)}
+}
+
+func (r *fakeReturnRepo) CreateWithinQuantity(ctx context.Context, returnRequest *models.ReturnRequest, purchased int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	returned := 0
+	for _, existing := range r.returns {
+		if existing.OrderItemID == returnRequest.OrderItemID && existing.Status != models.ReturnStatusRejected {
+			returned += existing.Quantity
+		}
+	}
+	if returned+returnRequest.Quantity > purchased {
+		return false, nil
+	}
+	r.nextID++
+	returnRequest.ID = r.nextID
+	copied := *returnRequest
+	r.returns[returnRequest.ID] = &copied
+	return true, nil
+}
+
+func (r *fakeReturnRepo) Update(ctx context.Context, returnRequest *models.ReturnRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *returnRequest
+	r.returns[returnRequest.ID] = &copied
+	return nil
+}
+
+func (r *fakeReturnRepo) Transition(ctx context.Context, id uint, from, to models.ReturnStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	returnRequest, ok := r.returns[id]
+	if !ok || returnRequest.Status != from {
+		return false, nil
+	}
+	returnRequest.Status = to
+	return true, nil
+}
+
+func (r *fakeReturnRepo) GetByID(ctx context.Context, id uint) (*models.ReturnRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	returnRequest, ok := r.returns[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *returnRequest
+	return &copied, nil
+}
+
+func (r *fakeReturnRepo) status(id uint) models.ReturnStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.returns[id].Status
+}
+
+// countryShipping quotes a cost per destination country
+type countryShipping struct {
+	ShippingService
+	costs map[string]float64
+}
+
+func (s countryShipping) Quote(ctx context.Context, subtotal float64, dest models.ShippingDestination, weight float64) *models.ShippingQuote {
+	return &models.ShippingQuote{Cost: s.costs[dest.Country]}
+}
+
+// exportOrderRepo feeds fixed rows to EachOrderExportLine
+type exportOrderRepo struct {
+	repository.OrderRepository
+	lines []models.OrderExportLine
+}
+
+func (r *exportOrderRepo) EachOrderExportLine(ctx context.Context, startDate, endDate time.Time, fn func(*models.OrderExportLine) error) error {
+	for i := range r.lines {
+		line := r.lines[i]
+		if err := fn(&line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fakeDeliveryQueue hands out its due deliveries once and records which were sent
+type fakeDeliveryQueue struct {
+	repository.EmailBroadcastRepository
+
+	due  []*models.EmailDelivery
+	sent []uint
+}
+
+func (q *fakeDeliveryQueue) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.EmailDelivery, error) {
+	due := q.due
+	q.due = nil
+	return due, nil
+}
+
+func (q *fakeDeliveryQueue) MarkDeliverySent(ctx context.Context, id uint, sentAt time.Time) error {
+	q.sent = append(q.sent, id)
+	return nil
+}
+
+// recordingSender records the subjects of the broadcasts and the products of
+// the recall notices it's asked to send
+type recordingSender struct {
+	email.Service
+
+	broadcasts []string
+	recalls    []string
+}
+
+func (s *recordingSender) SendBroadcastEmail(to, name, subject, message string) error {
+	s.broadcasts = append(s.broadcasts, subject)
+	return nil
+}
+
+func (s *recordingSender) SendProductRecallEmail(to, name, productName, message string) error {
+	s.recalls = append(s.recalls, productName)
+	return nil
+}
+
+// fakeNotificationRepo counts unread notifications per user
+type fakeNotificationRepo struct {
+	repository.NotificationRepository
+
+	unread map[uint]int64
+}
+
+func (r *fakeNotificationRepo) CreateBatch(ctx context.Context, notifications []*models.Notification) error {
+	for _, notification := range notifications {
+		r.unread[notification.UserID]++
+	}
+	return nil
+}
+
+func (r *fakeNotificationRepo) GetUnreadCount(ctx context.Context, userID uint) (int64, error) {
+	return r.unread[userID], nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type featuredSellerService struct {
+	featuredRepo repository.FeaturedSellerRepository
+	userRepo     repository.UserRepository
+	config       *config.Config
+}
+
+func NewFeaturedSellerService(
+	featuredRepo repository.FeaturedSellerRepository,
+	userRepo repository.UserRepository,
+	cfg *config.Config,
+) FeaturedSellerService {
+	return &featuredSellerService{
+		featuredRepo: featuredRepo,
+		userRepo:     userRepo,
+		config:       cfg,
+	}
+}
+
+// GetFeaturedSellers returns the public profiles of the currently featured sellers in display order
+func (s *featuredSellerService) GetFeaturedSellers(ctx context.Context) ([]models.SellerPublicProfile, error) {
+	featured, err := s.featuredRepo.GetActive(ctx, s.config.Storefront.MaxFeaturedSellers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get featured sellers: %w", err)
+	}
+
+	profiles := make([]models.SellerPublicProfile, 0, len(featured))
+	for _, f := range featured {
+		profiles = append(profiles, f.Seller.ToSellerPublicProfile())
+	}
+
+	return profiles, nil
+}
+
+func (s *featuredSellerService) ListFeaturedSellers(ctx context.Context) ([]*models.FeaturedSeller, error) {
+	featured, err := s.featuredRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get featured sellers: %w", err)
+	}
+	return featured, nil
+}
+
+func (s *featuredSellerService) FeatureSeller(ctx context.Context, req *models.FeatureSellerRequest, adminID uint) (*models.FeaturedSeller, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrExpiryInPast
+	}
+
+	seller, err := s.userRepo.GetByID(ctx, req.SellerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSellerNotFound
+		}
+		return nil, fmt.Errorf("failed to get seller: %w", err)
+	}
+	if seller.Role != models.RoleSeller || !seller.IsActive {
+		return nil, ErrSellerNotFound
+	}
+
+	if _, err := s.featuredRepo.GetBySellerID(ctx, req.SellerID); err == nil {
+		return nil, ErrSellerAlreadyFeatured
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check featured seller: %w", err)
+	}
+
+	count, err := s.featuredRepo.CountActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count featured sellers: %w", err)
+	}
+	if count >= int64(s.config.Storefront.MaxFeaturedSellers) {
+		return nil, ErrFeaturedSellerLimit
+	}
+
+	position := 0
+	if req.Position != nil {
+		position = *req.Position
+	} else {
+		maxPosition, err := s.featuredRepo.MaxPosition(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get featured seller position: %w", err)
+		}
+		position = maxPosition + 1
+	}
+
+	featured := &models.FeaturedSeller{
+		SellerID:  req.SellerID,
+		Position:  position,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: adminID,
+	}
+	if err := s.featuredRepo.Create(ctx, featured); err != nil {
+		return nil, fmt.Errorf("failed to feature seller: %w", err)
+	}
+	featured.Seller = *seller
+
+	return featured, nil
+}
+
+func (s *featuredSellerService) UnfeatureSeller(ctx context.Context, sellerID uint) error {
+	if err := s.featuredRepo.DeleteBySellerID(ctx, sellerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrFeaturedSellerNotFound
+		}
+		return fmt.Errorf("failed to unfeature seller: %w", err)
+	}
+	return nil
+}
+
+// ReorderFeaturedSellers places the given sellers first, in order; sellers left out keep their relative order after them
+func (s *featuredSellerService) ReorderFeaturedSellers(ctx context.Context, req *models.ReorderFeaturedSellersRequest) ([]*models.FeaturedSeller, error) {
+	current, err := s.featuredRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get featured sellers: %w", err)
+	}
+
+	featuredIDs := make(map[uint]bool, len(current))
+	for _, f := range current {
+		featuredIDs[f.SellerID] = true
+	}
+
+	seen := make(map[uint]bool, len(req.SellerIDs))
+	order := make([]uint, 0, len(current))
+	for _, sellerID := range req.SellerIDs {
+		if !featuredIDs[sellerID] {
+			return nil, ErrFeaturedSellerNotFound
+		}
+		if seen[sellerID] {
+			return nil, ErrDuplicateFeaturedSeller
+		}
+		seen[sellerID] = true
+		order = append(order, sellerID)
+	}
+	for _, f := range current {
+		if !seen[f.SellerID] {
+			order = append(order, f.SellerID)
+		}
+	}
+
+	if err := s.featuredRepo.Reorder(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to reorder featured sellers: %w", err)
+	}
+
+	return s.ListFeaturedSellers(ctx)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+)
+
+const (
+	fraudScoreCountryMismatch  = 30
+	fraudScoreNewAccountHigh   = 40
+	fraudScoreFailedPayments   = 40
+	fraudHighValueAmount       = 1000.0
+	fraudNewAccountAge         = 7 * 24 * time.Hour
+	fraudFailedPaymentWindow   = 24 * time.Hour
+	fraudFailedPaymentAttempts = 3
+)
+
+type ruleBasedFraudService struct {
+	orderRepo repository.OrderRepository
+}
+
+// NewRuleBasedFraudService creates the default rules-based fraud scorer
+func NewRuleBasedFraudService(orderRepo repository.OrderRepository) FraudService {
+	return &ruleBasedFraudService{
+		orderRepo: orderRepo,
+	}
+}
+
+func (s *ruleBasedFraudService) Score(ctx context.Context, order *models.Order, user *models.User) (int, []string) {
+	score := 0
+	reasons := []string{}
+
+	// Billing country differs from shipping country
+	if order.BillingCountry != nil && *order.BillingCountry != "" &&
+		!strings.EqualFold(*order.BillingCountry, order.ShippingCountry) {
+		score += fraudScoreCountryMismatch
+		reasons = append(reasons, "billing country does not match shipping country")
+	}
+
+	// High value order from a brand-new account
+	if user != nil && order.TotalAmount >= fraudHighValueAmount && time.Since(user.CreatedAt) < fraudNewAccountAge {
+		score += fraudScoreNewAccountHigh
+		reasons = append(reasons, fmt.Sprintf("order of %.2f from an account younger than %d days",
+			order.TotalAmount, int(fraudNewAccountAge.Hours()/24)))
+	}
+
+	// Many recent failed payments
+	failed, err := s.orderRepo.CountFailedPaymentsSince(ctx, order.CustomerID, time.Now().Add(-fraudFailedPaymentWindow))
+	if err != nil {
+		fmt.Printf("Warning: failed to count failed payments for user %d: %v\n", order.CustomerID, err)
+	} else if failed >= fraudFailedPaymentAttempts {
+		score += fraudScoreFailedPayments
+		reasons = append(reasons, fmt.Sprintf("%d failed payments in the last 24 hours", failed))
+	}
+
+	return score, reasons
+}
@@ -23,6 +23,7 @@ type AuthService interface {
 	ResetPassword(ctx context.Context, token string, newPassword string) error
 	VerifyEmail(ctx context.Context, token string) error
 	ResendVerification(ctx context.Context, email string) error
+	IsEmailAvailable(ctx context.Context, email string) (bool, error)
 }
 
 // UserService defines the interface for user operations
@@ -33,63 +34,138 @@ type UserService interface {
 	GetUserByID(ctx context.Context, id uint) (*models.UserResponse, error)
 	CreateUser(ctx context.Context, req *models.UserCreateRequest) (*models.UserResponse, error)
 	UpdateUser(ctx context.Context, id uint, req *models.UserUpdateRequest) (*models.UserResponse, error)
-	DeleteUser(ctx context.Context, id uint) error
+	DeleteUser(ctx context.Context, id uint, adminID uint) error
+	DeactivateSeller(ctx context.Context, id uint, adminID uint) (*models.SellerStatusChange, error)
+	ReactivateSeller(ctx context.Context, id uint, adminID uint) (*models.SellerStatusChange, error)
 	GetUserStats(ctx context.Context) (*models.UserStatsResponse, error)
+	GetSellerOnboarding(ctx context.Context, userID uint) (*models.SellerOnboardingStatus, error)
+	GetCustomerStats(ctx context.Context, userID uint) (*models.CustomerStats, error)
+	CreateStaff(ctx context.Context, sellerID uint, req *models.StaffCreateRequest) (*models.UserResponse, error)
+	GetStaff(ctx context.Context, sellerID uint) ([]models.UserResponse, error)
+	RemoveStaff(ctx context.Context, sellerID, staffID uint) error
 }
 
 // ProductService defines the interface for product operations
 type ProductService interface {
-	CreateProduct(ctx context.Context, req *models.CreateProductRequest, sellerID uint) (*models.Product, error)
+	CreateProduct(ctx context.Context, req *models.CreateProductRequest, sellerID uint, userRole models.UserRole) (*models.Product, error)
 	GetProduct(ctx context.Context, id uint) (*models.Product, error)
+	GetProductBySlug(ctx context.Context, slug string) (*models.Product, error)
+	GetProductsByIDs(ctx context.Context, ids []uint) ([]*models.Product, error)
+	GetProductChanges(ctx context.Context, cursor models.ProductChangeCursor, limit int) (*models.ProductChangesResponse, error)
 	GetProducts(ctx context.Context, req *models.GetProductsRequest) (*models.ProductListResponse, error)
-	UpdateProduct(ctx context.Context, id uint, req *models.UpdateProductRequest, sellerID uint) (*models.Product, error)
+	GetPopularTags(ctx context.Context, limit int) ([]models.TagCount, error)
+	UpdateProduct(ctx context.Context, id uint, req *models.UpdateProductRequest, sellerID uint, userRole models.UserRole) (*models.Product, error)
+	ApproveProduct(ctx context.Context, id, adminID uint) (*models.Product, error)
+	RejectProduct(ctx context.Context, id uint, req *models.ProductRejectRequest, adminID uint) (*models.Product, error)
+	GetPendingProducts(ctx context.Context, limit, offset int) ([]*models.Product, int64, error)
+	GetPriceHistory(ctx context.Context, productID, userID uint, userRole models.UserRole, limit, offset int) ([]*models.PriceHistory, int64, error)
 	DeleteProduct(ctx context.Context, id uint, sellerID uint) error
 	UpdateStock(ctx context.Context, id uint, stock int, sellerID uint) error
 	GetLowStockProducts(ctx context.Context, threshold int, sellerID *uint) ([]*models.Product, error)
+	GetInventoryValuation(ctx context.Context, sellerID uint) (*models.InventoryValuation, error)
+	GetInventoryAlerts(ctx context.Context, sellerID uint) (*models.InventoryAlerts, error)
+	GetProductsNeedingAttention(ctx context.Context, sellerID uint, reason *models.ProductAttentionReason, limit, offset int) ([]models.ProductAttention, int64, error)
+	BulkSetVisibility(ctx context.Context, req *models.BulkVisibilityRequest, userID uint, userRole models.UserRole) (*models.BulkVisibilityResponse, error)
+	GrantPurchaseLimitExemption(ctx context.Context, productID uint, req *models.PurchaseLimitExemptionRequest, adminID uint) (*models.PurchaseLimitExemption, error)
+	RevokePurchaseLimitExemption(ctx context.Context, productID, userID uint) error
+	LocalizeProducts(ctx context.Context, locales []string, products ...*models.Product)
+	GetTranslations(ctx context.Context, productID, userID uint, userRole models.UserRole) ([]*models.ProductTranslation, error)
+	SetTranslation(ctx context.Context, productID uint, locale string, req *models.ProductTranslationRequest, userID uint, userRole models.UserRole) (*models.ProductTranslation, error)
+	DeleteTranslation(ctx context.Context, productID uint, locale string, userID uint, userRole models.UserRole) error
 	GetTopRatedProducts(ctx context.Context, limit int) ([]*models.Product, error)
+	GetFeaturedProducts(ctx context.Context, limit int) ([]*models.Product, error)
+	GetNewArrivals(ctx context.Context, days int, category string, limit int) ([]*models.Product, error)
+	GetRelatedProducts(ctx context.Context, productID uint, limit int) ([]*models.Product, error)
 	SearchProducts(ctx context.Context, query string, limit, offset int) ([]*models.Product, error)
-	GetProductsByCategory(ctx context.Context, category string, limit, offset int) ([]*models.Product, error)
+	GetProductsByCategory(ctx context.Context, category string, sortBy models.ProductSort, limit, offset int) ([]*models.Product, error)
 	UpdateProductRating(ctx context.Context, productID uint) error
+	GetCacheStats() []models.CacheStats
+}
+
+// SearchService defines the interface for search suggestions and tracking
+type SearchService interface {
+	GetSuggestions(ctx context.Context, prefix string, limit int) (*models.SearchSuggestionsResponse, error)
+	RecordSearch(ctx context.Context, term string, resultCount int, userID *uint)
+	GetSearchAnalytics(ctx context.Context, startDate, endDate time.Time) (*models.SearchAnalytics, error)
 }
 
 // OrderService defines the interface for order operations
 type OrderService interface {
 	CreateOrder(ctx context.Context, req *models.CreateOrderRequest, userID uint) (*models.Order, error)
+	PreviewOrder(ctx context.Context, req *models.CreateOrderRequest, userID uint) (*models.OrderPreview, error)
+	CreateGuestOrder(ctx context.Context, req *models.GuestOrderRequest) (*models.Order, error)
 	GetOrder(ctx context.Context, id uint, userID uint, userRole models.UserRole) (*models.Order, error)
-	GetUserOrders(ctx context.Context, userID uint, limit, offset int) ([]*models.Order, error)
-	GetAllOrders(ctx context.Context, limit, offset int) ([]*models.Order, error)
+	TrackOrder(ctx context.Context, req *models.OrderTrackRequest) (*models.OrderTrackingStatus, error)
+	GetUserOrders(ctx context.Context, userID uint, includeArchived bool, limit, offset int) ([]*models.Order, error)
+	GetAllOrders(ctx context.Context, filter *models.OrderFilter, limit, offset int) ([]*models.Order, int64, error)
 	GetOrdersByStatus(ctx context.Context, status models.OrderStatus, limit, offset int) ([]*models.Order, error)
-	GetSellerOrders(ctx context.Context, sellerID uint, limit, offset int) ([]*models.Order, error)
+	GetSellerOrders(ctx context.Context, sellerID uint, filter *models.SellerOrderFilter, limit, offset int) ([]*models.Order, error)
+	GetAssignedOrders(ctx context.Context, staffID uint, limit, offset int) ([]*models.Order, error)
+	AssignOrder(ctx context.Context, id uint, req *models.OrderAssignRequest, userID uint, userRole models.UserRole) (*models.Order, error)
+	GetProductOrders(ctx context.Context, productID, userID uint, userRole models.UserRole, limit, offset int) ([]models.ProductOrderItem, error)
 	UpdateOrderStatus(ctx context.Context, id uint, status models.OrderStatus, userID uint, userRole models.UserRole) error
 	ProcessPayment(ctx context.Context, orderID uint, paymentReq *models.PaymentRequest) (*models.PaymentResponse, error)
-	CancelOrder(ctx context.Context, id uint, userID uint, userRole models.UserRole) error
+	ProcessGuestPayment(ctx context.Context, orderID uint, req *models.GuestPaymentRequest) (*models.PaymentResponse, error)
+	HandlePaymentSucceeded(ctx context.Context, paymentIntentID string) error
+	CancelOrder(ctx context.Context, id uint, req *models.CancelOrderRequest, userID uint, userRole models.UserRole) error
+	UpdateShippingAddress(ctx context.Context, id uint, req *models.UpdateShippingAddressRequest, userID uint) (*models.Order, error)
 	GetOrderAnalytics(ctx context.Context, sellerID *uint, startDate, endDate *time.Time) (*models.OrderAnalytics, error)
+	ComparePeriods(ctx context.Context, sellerID *uint, startDate, endDate time.Time) (*models.PeriodComparison, error)
+	GetCancellationAnalytics(ctx context.Context, startDate, endDate time.Time) (*models.CancellationAnalytics, error)
+	GetBestSellers(ctx context.Context, startDate, endDate time.Time, limit int) (*models.BestSellersReport, error)
+	EachBestSeller(ctx context.Context, startDate, endDate time.Time, fn func(*models.BestSeller) error) error
+	EachOrderExportLine(ctx context.Context, startDate, endDate time.Time, fn func(*models.OrderExportLine) error) error
+	GetFlaggedOrders(ctx context.Context, limit, offset int) ([]models.FraudReviewItem, error)
+	ReviewFlaggedOrder(ctx context.Context, id uint, req *models.FraudReviewRequest, adminID uint) error
+	RecalculateOrder(ctx context.Context, id, adminID uint, req *models.OrderRecalculateRequest) (*models.OrderRecalculation, error)
+	ResendConfirmationEmail(ctx context.Context, id uint, userID uint, userRole models.UserRole) error
+	GetConfirmationQueue(ctx context.Context, userID uint, userRole models.UserRole, limit, offset int) ([]*models.Order, error)
+	ReviewOrderConfirmation(ctx context.Context, id uint, req *models.OrderConfirmationRequest, userID uint, userRole models.UserRole) error
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+	ExtendReservation(ctx context.Context, id, userID uint) (*models.ReservationHold, error)
+	StartReservationSweeper(ctx context.Context, interval time.Duration)
+	GetStuckOrders(ctx context.Context, limit, offset int) ([]models.StuckOrder, error)
+	NotifySLABreaches(ctx context.Context) (int, error)
+	StartSLAMonitor(ctx context.Context, interval time.Duration)
+}
+
+// FraudService scores orders for fraud risk. Implementations can wrap an
+// external provider; NewRuleBasedFraudService is the built-in default.
+type FraudService interface {
+	Score(ctx context.Context, order *models.Order, user *models.User) (int, []string)
 }
 
 // ReviewService defines the interface for review operations
 type ReviewService interface {
 	CreateReview(ctx context.Context, req *models.CreateReviewRequest, userID uint) (*models.Review, error)
 	GetReview(ctx context.Context, id uint) (*models.Review, error)
-	GetProductReviews(ctx context.Context, productID uint, limit, offset int) ([]*models.Review, error)
+	GetProductReviews(ctx context.Context, productID uint, sortBy models.ReviewSort, limit, offset int) ([]*models.Review, error)
 	GetUserReviews(ctx context.Context, userID uint, limit, offset int) ([]*models.Review, error)
+	GetSellerReviews(ctx context.Context, sellerID uint, filter *models.SellerReviewFilter, limit, offset int) ([]models.SellerReview, int64, error)
 	UpdateReview(ctx context.Context, id uint, req *models.UpdateReviewRequest, userID uint) (*models.Review, error)
 	DeleteReview(ctx context.Context, id uint, userID uint, userRole models.UserRole) error
 	GetReviewsByRating(ctx context.Context, rating int, limit, offset int) ([]*models.Review, error)
 	GetTopReviews(ctx context.Context, limit int) ([]*models.Review, error)
 	GetRecentReviews(ctx context.Context, limit int) ([]*models.Review, error)
 	GetProductReviewStats(ctx context.Context, productID uint) (*models.ReviewStats, error)
+	GetReviewSummary(ctx context.Context, productID uint) (*models.ReviewSummary, error)
+	BulkModerate(ctx context.Context, req *models.BulkModerateRequest, adminID uint) (*models.BulkModerateResponse, error)
+	GetReviewHistory(ctx context.Context, id uint) ([]*models.ReviewEdit, error)
 	CanUserReview(ctx context.Context, userID, productID uint) (bool, error)
 }
 
 // EmailService defines the interface for email operations
 type EmailService interface {
-	SendWelcomeEmail(ctx context.Context, user *models.User) error
+	SendWelcomeEmail(ctx context.Context, user *models.User, coupon *models.Coupon) error
 	SendOrderConfirmationEmail(ctx context.Context, user *models.User, order *models.Order) error
 	SendOrderStatusUpdateEmail(ctx context.Context, user *models.User, order *models.Order) error
 	SendPasswordResetEmail(ctx context.Context, user *models.User, resetToken string) error
 	SendEmailVerificationEmail(ctx context.Context, user *models.User, verificationToken string) error
 	SendLowStockAlert(ctx context.Context, seller *models.User, product *models.Product) error
+	SendOversellAlert(ctx context.Context, seller *models.User, product *models.Product, order *models.Order, stock int) error
 	SendNewReviewNotification(ctx context.Context, seller *models.User, product *models.Product, review *models.Review) error
+	SendReviewReminder(ctx context.Context, user *models.User, order *models.Order, products []*models.Product) error
+	SendGuestOrderEmail(ctx context.Context, user *models.User, order *models.Order) error
 }
 
 // CategoryService defines the interface for category operations
@@ -110,16 +186,20 @@ type WishlistService interface {
 	RemoveFromWishlist(ctx context.Context, userID uint, productID uint) error
 	GetUserWishlist(ctx context.Context, userID uint) ([]*models.WishlistResponse, error)
 	IsProductInWishlist(ctx context.Context, userID uint, productID uint) (bool, error)
+	CheckMany(ctx context.Context, userID uint, productIDs []uint) (map[uint]bool, error)
+	GetWishlistCount(ctx context.Context, userID uint) (int64, error)
 	ClearWishlist(ctx context.Context, userID uint) error
 }
 
 // CartService defines the interface for cart operations
 type CartService interface {
-	AddToCart(ctx context.Context, userID uint, req *models.CartAddRequest) (*models.CartResponse, error)
-	UpdateCartItem(ctx context.Context, userID uint, productID uint, quantity int) (*models.CartResponse, error)
+	AddToCart(ctx context.Context, userID uint, userRole models.UserRole, req *models.CartAddRequest) (*models.CartResponse, error)
+	AddMany(ctx context.Context, userID uint, userRole models.UserRole, req *models.CartBulkAddRequest) (*models.CartBulkAddResponse, error)
+	UpdateCartItem(ctx context.Context, userID uint, userRole models.UserRole, productID uint, quantity int) (*models.CartResponse, error)
 	RemoveFromCart(ctx context.Context, userID uint, productID uint) error
 	GetUserCart(ctx context.Context, userID uint) ([]*models.CartResponse, error)
-	GetCartTotal(ctx context.Context, userID uint) (float64, error)
+	GetCartTotal(ctx context.Context, userID uint) (*models.CartTotalResponse, error)
+	GetCartSummary(ctx context.Context, userID uint, userRole models.UserRole, destination string) (*models.CartSummary, error)
 	ClearCart(ctx context.Context, userID uint) error
 	GetCartItemCount(ctx context.Context, userID uint) (int, error)
 }
@@ -142,10 +222,118 @@ type ProductImageService interface {
 	GetProductImages(ctx context.Context, productID uint) ([]models.ProductImage, error)
 	GetProductImage(ctx context.Context, imageID uint) (*models.ProductImage, error)
 	UpdateProductImage(ctx context.Context, imageID uint, imageReq *models.ProductImageRequest) (*models.ProductImage, error)
-	DeleteProductImage(ctx context.Context, imageID uint) error
+	DeleteProductImage(ctx context.Context, imageID uint) (*models.ProductImage, error)
 	SetPrimaryImage(ctx context.Context, productID uint, imageID uint) error
 	GetPrimaryImage(ctx context.Context, productID uint) (*models.ProductImage, error)
 	UpdateImageOrder(ctx context.Context, productID uint, imageID uint, sortOrder int) error
 	BulkAddImages(ctx context.Context, productID uint, imageReqs []models.ProductImageRequest) ([]models.ProductImage, error)
 	ReplaceProductImages(ctx context.Context, productID uint, imageReqs []models.ProductImageRequest) ([]models.ProductImage, error)
+	GetAccessibilityReport(ctx context.Context, sellerID uint, limit, offset int) ([]models.ProductAccessibility, int64, error)
+}
+
+// RecallService defines the interface for product recall operations
+type RecallService interface {
+	RecallProduct(ctx context.Context, productID uint, req *models.ProductRecallRequest, userID uint, userRole models.UserRole) (*models.ProductRecall, error)
+}
+
+// ShippingService defines the interface for shipping cost quotes and the
+// shipping zones they are priced from
+type ShippingService interface {
+	Quote(ctx context.Context, subtotal float64, dest models.ShippingDestination, weight float64) *models.ShippingQuote
+	CreateZone(ctx context.Context, req *models.ShippingZoneRequest) (*models.ShippingZone, error)
+	GetZone(ctx context.Context, id uint) (*models.ShippingZone, error)
+	GetZones(ctx context.Context) ([]*models.ShippingZone, error)
+	UpdateZone(ctx context.Context, id uint, req *models.ShippingZoneRequest) (*models.ShippingZone, error)
+	DeleteZone(ctx context.Context, id uint) error
+}
+
+// FeaturedSellerService defines the interface for storefront curation operations
+type FeaturedSellerService interface {
+	GetFeaturedSellers(ctx context.Context) ([]models.SellerPublicProfile, error)
+	ListFeaturedSellers(ctx context.Context) ([]*models.FeaturedSeller, error)
+	FeatureSeller(ctx context.Context, req *models.FeatureSellerRequest, adminID uint) (*models.FeaturedSeller, error)
+	UnfeatureSeller(ctx context.Context, sellerID uint) error
+	ReorderFeaturedSellers(ctx context.Context, req *models.ReorderFeaturedSellersRequest) ([]*models.FeaturedSeller, error)
+}
+
+// DisputeService defines the interface for payment dispute operations
+type DisputeService interface {
+	HandlePaymentWebhook(ctx context.Context, payload []byte, signature string) error
+	GetDisputes(ctx context.Context, status *models.DisputeStatus, limit, offset int) ([]models.DisputeResponse, int64, error)
+	GetDispute(ctx context.Context, id uint) (*models.DisputeResponse, error)
+	SubmitEvidence(ctx context.Context, id uint, req *models.DisputeEvidenceRequest, adminID uint) (*models.DisputeResponse, error)
+}
+
+// ReturnService defines the interface for return request operations
+type ReturnService interface {
+	RequestReturn(ctx context.Context, orderID, customerID uint, req *models.ReturnCreateRequest) (*models.ReturnRequest, error)
+	GetMyReturns(ctx context.Context, customerID uint, limit, offset int) ([]*models.ReturnRequest, int64, error)
+	GetReturns(ctx context.Context, status *models.ReturnStatus, limit, offset int) ([]*models.ReturnRequest, int64, error)
+	ApproveReturn(ctx context.Context, id, adminID uint, req *models.ReturnResolveRequest) (*models.ReturnRequest, error)
+	RejectReturn(ctx context.Context, id, adminID uint, req *models.ReturnResolveRequest) (*models.ReturnRequest, error)
+}
+
+// ProductQuestionService defines the interface for product Q&A operations
+type ProductQuestionService interface {
+	AskQuestion(ctx context.Context, productID, userID uint, req *models.AskQuestionRequest) (*models.ProductQuestionResponse, error)
+	GetProductQuestions(ctx context.Context, productID uint, limit, offset int) ([]models.ProductQuestionResponse, int64, error)
+	AnswerQuestion(ctx context.Context, questionID, userID uint, userRole models.UserRole, req *models.AnswerQuestionRequest) (*models.ProductAnswerResponse, error)
+	NotifyAsker(ctx context.Context, question *models.ProductQuestion, answer *models.ProductAnswer) error
+}
+
+// PromotionService defines the interface for managing order-level promotions
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, req *models.PromotionRequest, adminID uint) (*models.Promotion, error)
+	GetPromotion(ctx context.Context, id uint) (*models.Promotion, error)
+	GetPromotions(ctx context.Context, limit, offset int) ([]*models.Promotion, int64, error)
+	UpdatePromotion(ctx context.Context, id uint, req *models.PromotionRequest) (*models.Promotion, error)
+	DeletePromotion(ctx context.Context, id uint) error
+}
+
+// EmailBroadcastService defines the interface for admin email broadcasts
+type EmailBroadcastService interface {
+	CreateBroadcast(ctx context.Context, req *models.EmailBroadcastRequest, adminID uint) (*models.EmailBroadcastProgress, error)
+	GetBroadcast(ctx context.Context, id uint) (*models.EmailBroadcastProgress, error)
+	GetBroadcasts(ctx context.Context, limit, offset int) ([]*models.EmailBroadcastProgress, int64, error)
+}
+
+// CouponService defines the interface for coupon operations. Limited coupons
+// are held per order during checkout so concurrent checkouts can't over-redeem them.
+type CouponService interface {
+	CreateCoupon(ctx context.Context, req *models.CreateCouponRequest, adminID uint) (*models.Coupon, error)
+	IssueWelcomeCoupon(ctx context.Context, user *models.User) (*models.Coupon, error)
+	GetCoupons(ctx context.Context, limit, offset int) ([]*models.Coupon, int64, error)
+	Apply(ctx context.Context, code string, userID uint, subtotal float64, lines []models.PromotionLine) (*models.Coupon, float64, error)
+	Preview(ctx context.Context, code string, userID uint, subtotal, promotionDiscount float64, lines []models.PromotionLine) (*models.CouponPreview, error)
+	Hold(ctx context.Context, couponID, orderID uint) error
+	Commit(ctx context.Context, couponID, orderID uint) error
+	Release(ctx context.Context, couponID, orderID uint)
+}
+
+// AddressService defines the interface for address book operations
+type AddressService interface {
+	CreateAddress(ctx context.Context, userID uint, req *models.AddressRequest) (*models.Address, error)
+	GetAddresses(ctx context.Context, userID uint) ([]*models.Address, error)
+	GetAddress(ctx context.Context, userID, addressID uint) (*models.Address, error)
+	UpdateAddress(ctx context.Context, userID, addressID uint, req *models.AddressRequest) (*models.Address, error)
+	DeleteAddress(ctx context.Context, userID, addressID uint) error
+}
+
+// ConversationService defines the interface for customer-seller messaging
+type ConversationService interface {
+	ContactSeller(ctx context.Context, customerID, sellerID uint, req *models.ContactSellerRequest) (*models.Conversation, error)
+	GetConversations(ctx context.Context, userID uint, limit, offset int) ([]*models.Conversation, int64, error)
+	GetConversation(ctx context.Context, userID uint, userRole models.UserRole, id uint) (*models.Conversation, error)
+	SendMessage(ctx context.Context, userID, id uint, req *models.ConversationMessageRequest) (*models.Conversation, error)
+}
+
+// SupportService defines the interface for customer support tickets
+type SupportService interface {
+	CreateTicket(ctx context.Context, userID uint, req *models.CreateSupportTicketRequest) (*models.SupportTicket, error)
+	GetMyTickets(ctx context.Context, userID uint, limit, offset int) ([]*models.SupportTicket, int64, error)
+	GetMyTicket(ctx context.Context, userID, ticketID uint) (*models.SupportTicket, error)
+	ReplyToTicket(ctx context.Context, userID, ticketID uint, req *models.SupportTicketReplyRequest) (*models.SupportTicket, error)
+	GetTickets(ctx context.Context, status *models.SupportTicketStatus, limit, offset int) ([]*models.SupportTicket, int64, error)
+	GetTicket(ctx context.Context, ticketID uint) (*models.SupportTicket, error)
+	RespondToTicket(ctx context.Context, adminID, ticketID uint, req *models.SupportTicketReplyRequest) (*models.SupportTicket, error)
 }
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
+)
+
+// MinimumOrderPolicy checks orders against the store's minimum order amount
+// and each seller's own minimum for their portion. It's shared by the cart
+// summary and CreateOrder so both agree on what falls short.
+type MinimumOrderPolicy struct {
+	userRepo       repository.UserRepository
+	minimum        float64
+	exemptPayments map[models.PaymentMethod]bool
+}
+
+func NewMinimumOrderPolicy(userRepo repository.UserRepository, cfg *config.Config) *MinimumOrderPolicy {
+	exempt := make(map[models.PaymentMethod]bool)
+	for _, method := range cfg.Order.MinimumAmountExemptPayments {
+		exempt[models.PaymentMethod(method)] = true
+	}
+
+	return &MinimumOrderPolicy{
+		userRepo:       userRepo,
+		minimum:        cfg.Order.MinimumAmount,
+		exemptPayments: exempt,
+	}
+}
+
+// Unmet returns the minimums the order doesn't reach, the store's first.
+// Order totals and discounts must already be applied. Orders paid with an
+// exempt method have none. A seller who can't be loaded is skipped rather
+// than blocking checkout.
+func (p *MinimumOrderPolicy) Unmet(ctx context.Context, order *models.Order) []models.OrderMinimum {
+	if p.exemptPayments[order.PaymentMethod] {
+		return nil
+	}
+
+	var unmet []models.OrderMinimum
+	if p.minimum > 0 {
+		if minimum := newOrderMinimum(p.minimum, order.DiscountedSubtotal()); !minimum.Met() {
+			unmet = append(unmet, minimum)
+		}
+	}
+
+	seen := make(map[uint]bool)
+	for _, item := range order.OrderItems {
+		if seen[item.SellerID] {
+			continue
+		}
+		seen[item.SellerID] = true
+
+		seller, err := p.userRepo.GetByID(ctx, item.SellerID)
+		if err != nil {
+			fmt.Printf("Warning: failed to get seller %d for minimum order check: %v\n", item.SellerID, err)
+			continue
+		}
+		if seller.MinimumOrderAmount == nil || *seller.MinimumOrderAmount <= 0 {
+			continue
+		}
+
+		minimum := newOrderMinimum(*seller.MinimumOrderAmount, order.DiscountedSellerSubtotal(seller.ID))
+		if minimum.Met() {
+			continue
+		}
+		sellerID := seller.ID
+		minimum.SellerID = &sellerID
+		if seller.StoreName != nil {
+			minimum.StoreName = *seller.StoreName
+		}
+		unmet = append(unmet, minimum)
+	}
+
+	return unmet
+}
+
+// Check rejects the order if it falls short of any minimum, naming the first
+func (p *MinimumOrderPolicy) Check(ctx context.Context, order *models.Order) error {
+	unmet := p.Unmet(ctx, order)
+	if len(unmet) == 0 {
+		return nil
+	}
+
+	minimum := unmet[0]
+	if minimum.SellerID == nil {
+		return errorf(ErrMinimumOrderNotMet, "minimum order amount is %s after discounts (add %s more)", money.Format(minimum.Minimum), money.Format(minimum.Shortfall))
+	}
+	seller := minimum.StoreName
+	if seller == "" {
+		seller = fmt.Sprintf("seller %d", *minimum.SellerID)
+	}
+	return errorf(ErrMinimumOrderNotMet, "minimum order amount for %s is %s after discounts (add %s more from this seller)", seller, money.Format(minimum.Minimum), money.Format(minimum.Shortfall))
+}
+
+func newOrderMinimum(minimum, amount float64) models.OrderMinimum {
+	return models.OrderMinimum{
+		Minimum:   minimum,
+		Amount:    amount,
+		Shortfall: (money.FromFloat(minimum) - money.FromFloat(amount)).Clamp(money.FromFloat(minimum)).Float(),
+	}
+}
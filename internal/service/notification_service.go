@@ -3,19 +3,112 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
+const (
+	unreadCountCachePrefix = "notifications:unread:"
+	// Every write through TrackUnreadCounts invalidates the count; the TTL
+	// only bounds how long one changed outside the app can stay stale
+	unreadCountCacheTTL = 5 * time.Minute
+)
+
+// unreadCountTracker wraps the notification repository so every write drops
+// the affected users' cached unread counts, whichever service makes it
+type unreadCountTracker struct {
+	repository.NotificationRepository
+	redis *redis.Client
+}
+
+// TrackUnreadCounts wraps repo so that creating, reading or deleting
+// notifications invalidates the unread counts NotificationService caches.
+// Every service that writes notifications should be given the wrapped
+// repository.
+func TrackUnreadCounts(repo repository.NotificationRepository, redisClient *redis.Client) repository.NotificationRepository {
+	return &unreadCountTracker{NotificationRepository: repo, redis: redisClient}
+}
+
+func (r *unreadCountTracker) Create(ctx context.Context, notification *models.Notification) error {
+	if err := r.NotificationRepository.Create(ctx, notification); err != nil {
+		return err
+	}
+	r.invalidate(ctx, notification.UserID)
+	return nil
+}
+
+func (r *unreadCountTracker) CreateBatch(ctx context.Context, notifications []*models.Notification) error {
+	if err := r.NotificationRepository.CreateBatch(ctx, notifications); err != nil {
+		return err
+	}
+	userIDs := make([]uint, len(notifications))
+	for i, notification := range notifications {
+		userIDs[i] = notification.UserID
+	}
+	r.invalidate(ctx, userIDs...)
+	return nil
+}
+
+func (r *unreadCountTracker) MarkAsRead(ctx context.Context, userID, notificationID uint) error {
+	if err := r.NotificationRepository.MarkAsRead(ctx, userID, notificationID); err != nil {
+		return err
+	}
+	r.invalidate(ctx, userID)
+	return nil
+}
+
+func (r *unreadCountTracker) MarkAllAsRead(ctx context.Context, userID uint) error {
+	if err := r.NotificationRepository.MarkAllAsRead(ctx, userID); err != nil {
+		return err
+	}
+	r.invalidate(ctx, userID)
+	return nil
+}
+
+func (r *unreadCountTracker) DeleteOld(ctx context.Context, userID uint, days int) error {
+	if err := r.NotificationRepository.DeleteOld(ctx, userID, days); err != nil {
+		return err
+	}
+	r.invalidate(ctx, userID)
+	return nil
+}
+
+// invalidate drops the users' cached unread counts so the next read
+// recomputes them
+func (r *unreadCountTracker) invalidate(ctx context.Context, userIDs ...uint) {
+	if len(userIDs) == 0 {
+		return
+	}
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = unreadCountKey(userID)
+	}
+	if err := r.redis.Del(ctx, keys...).Err(); err != nil {
+		fmt.Printf("Warning: failed to invalidate unread notification count: %v\n", err)
+	}
+}
+
+func unreadCountKey(userID uint) string {
+	return fmt.Sprintf("%s%d", unreadCountCachePrefix, userID)
+}
+
 type notificationService struct {
 	notificationRepo repository.NotificationRepository
+	redis            *redis.Client
 }
 
-func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+// NewNotificationService serves notifications from notificationRepo, which
+// should be wrapped with TrackUnreadCounts so the cached unread counts are
+// invalidated when they change
+func NewNotificationService(notificationRepo repository.NotificationRepository, redisClient *redis.Client) NotificationService {
 	return &notificationService{
 		notificationRepo: notificationRepo,
+		redis:            redisClient,
 	}
 }
 
@@ -66,7 +159,7 @@ func (s *notificationService) GetByUser(ctx context.Context, userID uint, page,
 		return nil, err
 	}
 
-	unreadCount, err := s.notificationRepo.GetUnreadCount(ctx, userID)
+	unreadCount, err := s.unreadCount(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -83,18 +176,20 @@ func (s *notificationService) GetByUser(ctx context.Context, userID uint, page,
 	}, nil
 }
 
+// MarkAsRead marks the user's notification as read. It is idempotent: a
+// notification that is already read, missing or owned by someone else is left
+// alone without an error.
 func (s *notificationService) MarkAsRead(ctx context.Context, userID, notificationID uint) error {
-	// Verify notification belongs to user
 	notification, err := s.notificationRepo.GetByID(ctx, notificationID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("notification not found")
+			return nil
 		}
 		return err
 	}
 
-	if notification.UserID != userID {
-		return errors.New("unauthorized")
+	if notification.UserID != userID || notification.IsRead {
+		return nil
 	}
 
 	return s.notificationRepo.MarkAsRead(ctx, userID, notificationID)
@@ -105,10 +200,30 @@ func (s *notificationService) MarkAllAsRead(ctx context.Context, userID uint) er
 }
 
 func (s *notificationService) GetUnreadCount(ctx context.Context, userID uint) (int, error) {
-	count, err := s.notificationRepo.GetUnreadCount(ctx, userID)
+	count, err := s.unreadCount(ctx, userID)
 	return int(count), err
 }
 
+// unreadCount returns the user's unread notification count from the cache,
+// recomputing it from the database when the cache is cold
+func (s *notificationService) unreadCount(ctx context.Context, userID uint) (int64, error) {
+	cacheKey := unreadCountKey(userID)
+	if cached, err := s.redis.Get(ctx, cacheKey).Int64(); err == nil {
+		return cached, nil
+	}
+
+	count, err := s.notificationRepo.GetUnreadCount(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.redis.Set(ctx, cacheKey, count, unreadCountCacheTTL).Err(); err != nil {
+		fmt.Printf("Warning: failed to cache unread notification count: %v\n", err)
+	}
+
+	return count, nil
+}
+
 func (s *notificationService) GetUserNotifications(ctx context.Context, userID uint, limit, offset int) ([]*models.Notification, error) {
 	page := (offset / limit) + 1
 	notifications, _, err := s.notificationRepo.GetByUser(ctx, userID, page, limit)
@@ -146,13 +261,13 @@ func (s *notificationService) DeleteNotification(ctx context.Context, userID, no
 	notification, err := s.notificationRepo.GetByID(ctx, notificationID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("notification not found")
+			return ErrNotificationNotFound
 		}
 		return err
 	}
 
 	if notification.UserID != userID {
-		return errors.New("unauthorized")
+		return ErrNotificationForbidden
 	}
 
 	// Since we don't have a specific Delete method, we'll use DeleteOld with 0 days
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+// Other services write notifications straight to the repository; the count
+// must still move once they do
+func TestUnreadCountSeesNotificationsCreatedByOtherServices(t *testing.T) {
+	ctx := context.Background()
+	client := openTestRedis(t)
+	repo := TrackUnreadCounts(&fakeNotificationRepo{unread: make(map[uint]int64)}, client)
+	svc := NewNotificationService(repo, client)
+
+	if count, err := svc.GetUnreadCount(ctx, 5); err != nil || count != 0 {
+		t.Fatalf("GetUnreadCount = %d, %v, want 0", count, err)
+	}
+
+	if err := repo.CreateBatch(ctx, []*models.Notification{{UserID: 5, Title: "Recall notice"}}); err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+
+	if count, err := svc.GetUnreadCount(ctx, 5); err != nil || count != 1 {
+		t.Errorf("GetUnreadCount = %d, %v, want 1", count, err)
+	}
+}
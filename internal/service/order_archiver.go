@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+)
+
+const orderArchiveBatchSize = 500
+
+// OrderArchiver archives finished orders once they are older than the
+// retention period. Archived orders stay in the orders table, so they can
+// still be fetched by ID and still count towards analytics, but order lists
+// skip them unless include_archived is set.
+type OrderArchiver struct {
+	orderRepo repository.OrderRepository
+
+	retention time.Duration
+}
+
+func NewOrderArchiver(orderRepo repository.OrderRepository, cfg *config.Config) *OrderArchiver {
+	return &OrderArchiver{
+		orderRepo: orderRepo,
+		retention: time.Duration(cfg.Order.RetentionDays) * 24 * time.Hour,
+	}
+}
+
+// Start archives due orders every interval until ctx is done. A non-positive
+// interval or retention period disables archiving.
+func (a *OrderArchiver) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 || a.retention <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				count, err := a.ArchiveDue(ctx)
+				if err != nil {
+					fmt.Printf("Warning: order archiving failed: %v\n", err)
+				}
+				if count > 0 {
+					fmt.Printf("Archived %d orders\n", count)
+				}
+			}
+		}
+	}()
+}
+
+// ArchiveDue archives every order past the retention period, a batch at a
+// time so no single update holds locks on a large part of the table, and
+// returns how many were archived
+func (a *OrderArchiver) ArchiveDue(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-a.retention)
+
+	var total int64
+	for {
+		archived, err := a.orderRepo.Archive(ctx, cutoff, orderArchiveBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("failed to archive orders: %w", err)
+		}
+		total += archived
+		if archived < orderArchiveBatchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
@@ -2,43 +2,234 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
 	"github.com/JonathanVera18/ecommerce-api/pkg/payment"
+	"gorm.io/gorm"
+)
+
+const (
+	expiredReservationBatchSize = 100
+	slaAlertBatchSize           = 100
+	confirmationResendLimit     = 3 // Per order per hour
 )
 
 type orderService struct {
-	orderRepo   repository.OrderRepository
-	productRepo repository.ProductRepository
-	userRepo    repository.UserRepository
-	paymentSvc  payment.Service
+	orderRepo        repository.OrderRepository
+	productRepo      repository.ProductRepository
+	userRepo         repository.UserRepository
+	reservationRepo  repository.StockReservationRepository
+	notificationRepo repository.NotificationRepository
+	paymentSvc       payment.Service
+	fraudSvc         FraudService
+	shippingSvc      ShippingService
+	promotions       *PromotionEngine
+	productCache     *ProductCache
+	minimums         *MinimumOrderPolicy
+	couponSvc        CouponService
+	addressSvc       AddressService
+	emailSvc         EmailService
+	config           *config.Config
 }
 
 func NewOrderService(
 	orderRepo repository.OrderRepository,
 	productRepo repository.ProductRepository,
 	userRepo repository.UserRepository,
+	reservationRepo repository.StockReservationRepository,
+	notificationRepo repository.NotificationRepository,
 	paymentSvc payment.Service,
+	fraudSvc FraudService,
+	shippingSvc ShippingService,
+	promotions *PromotionEngine,
+	productCache *ProductCache,
+	minimums *MinimumOrderPolicy,
+	couponSvc CouponService,
+	addressSvc AddressService,
+	emailSvc EmailService,
+	cfg *config.Config,
 ) OrderService {
 	return &orderService{
-		orderRepo:   orderRepo,
-		productRepo: productRepo,
-		userRepo:    userRepo,
-		paymentSvc:  paymentSvc,
+		orderRepo:        orderRepo,
+		productRepo:      productRepo,
+		userRepo:         userRepo,
+		reservationRepo:  reservationRepo,
+		notificationRepo: notificationRepo,
+		paymentSvc:       paymentSvc,
+		fraudSvc:         fraudSvc,
+		shippingSvc:      shippingSvc,
+		promotions:       promotions,
+		productCache:     productCache,
+		minimums:         minimums,
+		couponSvc:        couponSvc,
+		addressSvc:       addressSvc,
+		emailSvc:         emailSvc,
+		config:           cfg,
+	}
+}
+
+// applySavedAddresses copies the saved shipping and billing addresses the
+// request refers to into the order, so later edits to the address book don't
+// change the order
+func (s *orderService) applySavedAddresses(ctx context.Context, order *models.Order, req *models.CreateOrderRequest, userID uint) error {
+	if req.ShippingAddressID != nil {
+		address, err := s.addressSvc.GetAddress(ctx, userID, *req.ShippingAddressID)
+		if err != nil {
+			return fmt.Errorf("shipping %w", err)
+		}
+		address.CopyToShipping(order)
+
+		if user, err := s.userRepo.GetByID(ctx, userID); err == nil {
+			order.ShippingEmail = user.Email
+		}
+	}
+
+	if req.BillingAddressID != nil {
+		address, err := s.addressSvc.GetAddress(ctx, userID, *req.BillingAddressID)
+		if err != nil {
+			return fmt.Errorf("billing %w", err)
+		}
+		address.CopyToBilling(order)
+	}
+
+	return nil
+}
+
+// checkPurchaseLimits rejects the order when it would take the customer past a
+// product's per-customer purchase limit within the limit's window. Customers
+// an admin has exempted are not limited.
+func (s *orderService) checkPurchaseLimits(ctx context.Context, userID uint, products map[uint]*models.Product, quantities map[uint]int) error {
+	for productID, quantity := range quantities {
+		product := products[productID]
+		if product.PurchaseLimitPerCustomer <= 0 {
+			continue
+		}
+
+		exempt, err := s.productRepo.HasPurchaseLimitExemption(ctx, productID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to check purchase limit exemption: %w", err)
+		}
+		if exempt {
+			continue
+		}
+
+		var since *time.Time
+		if product.PurchaseLimitWindowDays > 0 {
+			windowStart := time.Now().AddDate(0, 0, -product.PurchaseLimitWindowDays)
+			since = &windowStart
+		}
+
+		purchased, err := s.orderRepo.SumCustomerProductQuantity(ctx, userID, productID, since)
+		if err != nil {
+			return fmt.Errorf("failed to check purchase limit: %w", err)
+		}
+
+		if purchased+quantity > product.PurchaseLimitPerCustomer {
+			remaining := product.PurchaseLimitPerCustomer - purchased
+			if remaining < 0 {
+				remaining = 0
+			}
+			window := "in total"
+			if product.PurchaseLimitWindowDays > 0 {
+				window = fmt.Sprintf("every %d days", product.PurchaseLimitWindowDays)
+			}
+			return errorf(ErrPurchaseLimitReached, "purchase limit reached for %s: each customer can buy %d %s and you can buy %d more",
+				product.Name, product.PurchaseLimitPerCustomer, window, remaining)
+		}
 	}
+
+	return nil
 }
 
 func (s *orderService) CreateOrder(ctx context.Context, req *models.CreateOrderRequest, userID uint) (*models.Order, error) {
+	return s.createOrder(ctx, req, userID, nil)
+}
+
+// PreviewOrder prices the order as CreateOrder would, with the same checks,
+// without saving it or holding stock or the coupon
+func (s *orderService) PreviewOrder(ctx context.Context, req *models.CreateOrderRequest, userID uint) (*models.OrderPreview, error) {
+	order, err := s.priceOrder(ctx, req, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewOrderPreview(order), nil
+}
+
+// createOrder creates an order for the user. A guest checkout's contact and
+// shipping details are copied into the order in place of saved addresses.
+func (s *orderService) createOrder(ctx context.Context, req *models.CreateOrderRequest, userID uint, guest *models.GuestOrderRequest) (*models.Order, error) {
+	order, err := s.priceOrder(ctx, req, userID, guest)
+	if err != nil {
+		return nil, err
+	}
+	if guest != nil {
+		// The guest has no session, so paying for the order takes this token
+		token, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		tokenHash := utils.HashToken(token)
+		order.GuestTokenHash = &tokenHash
+		order.GuestToken = token
+	}
+	s.applyFraudScore(ctx, order)
+
+	// Orders spanning several sellers get one fulfillment group per seller
+	order.BuildFulfillments()
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	// Hold the coupon's use for this order until payment; of checkouts racing
+	// for its last use, only one gets it
+	if order.CouponID != nil {
+		if err := s.couponSvc.Hold(ctx, *order.CouponID, order.ID); err != nil {
+			if cancelErr := s.orderRepo.Cancel(ctx, order.ID, models.CancellationReasonOther, nil); cancelErr != nil {
+				fmt.Printf("Warning: failed to cancel order %d after coupon hold failure: %v\n", order.ID, cancelErr)
+			}
+			return nil, err
+		}
+	}
+
+	// Hold stock until payment; it's released if payment fails or the order expires
+	if err := s.reserveStock(ctx, order); err != nil {
+		// Don't leave an order behind that holds no stock
+		if cancelErr := s.orderRepo.Cancel(ctx, order.ID, models.CancellationReasonOutOfStock, nil); cancelErr != nil {
+			fmt.Printf("Warning: failed to cancel order %d after reservation failure: %v\n", order.ID, cancelErr)
+		}
+		s.releaseCoupon(ctx, order)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// priceOrder builds the order the request describes, priced with shipping,
+// promotions and the coupon, after checking availability, stock, purchase
+// limits and order minimums. Nothing is saved or held.
+func (s *orderService) priceOrder(ctx context.Context, req *models.CreateOrderRequest, userID uint, guest *models.GuestOrderRequest) (*models.Order, error) {
 	if len(req.Items) == 0 {
-		return nil, errors.New("order must contain at least one item")
+		return nil, ErrEmptyOrder
 	}
 
-	var totalAmount float64
+	var totalAmount money.Amount
 	var orderItems []models.OrderItem
+	products := make(map[uint]*models.Product)
+	quantities := make(map[uint]int)
+
+	activeSellers := make(map[uint]bool)
 
 	// Validate and calculate order items
 	for _, item := range req.Items {
@@ -46,24 +237,39 @@ func (s *orderService) CreateOrder(ctx context.Context, req *models.CreateOrderR
 		if err != nil {
 			return nil, fmt.Errorf("failed to get product %d: %w", item.ProductID, err)
 		}
+		products[product.ID] = product
+		quantities[product.ID] += item.Quantity
+
+		if !product.IsActive || !product.IsAvailableAt(time.Now()) {
+			return nil, errorf(ErrProductUnavailable, "product %s is not available", product.Name)
+		}
 
-		if !product.IsActive {
-			return nil, fmt.Errorf("product %s is not available", product.Name)
+		// A deactivated seller's products can't be bought even if one was
+		// made active again behind the seller's back
+		active, checked := activeSellers[product.SellerID]
+		if !checked {
+			seller, err := s.userRepo.GetByID(ctx, product.SellerID)
+			active = err == nil && seller.IsActive
+			activeSellers[product.SellerID] = active
+		}
+		if !active {
+			return nil, errorf(ErrProductUnavailable, "product %s is not available", product.Name)
 		}
 
-		if product.Stock < item.Quantity {
-			return nil, fmt.Errorf("insufficient stock for product %s (available: %d, requested: %d)",
+		if !product.CanFulfill(item.Quantity) {
+			return nil, errorf(ErrInsufficientStock, "insufficient stock for product %s (available: %d, requested: %d)",
 				product.Name, product.Stock, item.Quantity)
 		}
 
-		itemTotal := product.Price * float64(item.Quantity)
+		itemTotal := money.FromFloat(product.Price).Times(item.Quantity)
 		totalAmount += itemTotal
 
 		orderItems = append(orderItems, models.OrderItem{
 			ProductID:          item.ProductID,
+			SellerID:           product.SellerID,
 			Quantity:           item.Quantity,
 			UnitPrice:          product.Price,
-			TotalPrice:         itemTotal,
+			TotalPrice:         itemTotal.Float(),
 			ProductName:        product.Name,
 			ProductSKU:         product.SKU,
 			ProductDescription: &product.Description,
@@ -71,12 +277,16 @@ func (s *orderService) CreateOrder(ctx context.Context, req *models.CreateOrderR
 		})
 	}
 
+	if err := s.checkPurchaseLimits(ctx, userID, products, quantities); err != nil {
+		return nil, err
+	}
+
 	// Create order
 	order := &models.Order{
 		CustomerID:         userID,
 		Status:             models.OrderStatusPending,
-		TotalAmount:        totalAmount,
-		SubtotalAmount:     totalAmount,
+		TotalAmount:        totalAmount.Float(),
+		SubtotalAmount:     totalAmount.Float(),
 		PaymentMethod:      req.PaymentMethod,
 		ShippingFirstName:  "Customer", // These should come from user profile or request
 		ShippingLastName:   "User",
@@ -88,25 +298,158 @@ func (s *orderService) CreateOrder(ctx context.Context, req *models.CreateOrderR
 		ShippingPostalCode: "12345",
 		OrderItems:         orderItems,
 	}
+	if err := s.applySavedAddresses(ctx, order, req, userID); err != nil {
+		return nil, err
+	}
+	if guest != nil {
+		guest.Address().CopyToShipping(order)
+		order.ShippingEmail = guest.Email
+	}
 
-	if err := s.orderRepo.Create(ctx, order); err != nil {
-		return nil, fmt.Errorf("failed to create order: %w", err)
+	// The order ships once its slowest item is ready
+	processingDays := 0
+	for _, product := range products {
+		if days := product.EffectiveProcessingTimeDays(); days > processingDays {
+			processingDays = days
+		}
 	}
+	order.EstimateDelivery(processingDays, s.config.Shipping.TransitDays, time.Now())
 
-	// Update product stock
-	for _, item := range req.Items {
-		product, _ := s.productRepo.GetByID(ctx, item.ProductID)
-		newStock := product.Stock - item.Quantity
-		if err := s.productRepo.UpdateStock(ctx, item.ProductID, newStock); err != nil {
-			// Log error but don't fail the order creation
-			// In production, you might want to implement a rollback mechanism
-			fmt.Printf("Warning: failed to update stock for product %d: %v\n", item.ProductID, err)
+	var weight float64
+	for id, product := range products {
+		weight += product.ShippingWeight(quantities[id])
+	}
+
+	// Quote shipping on the discounted subtotal, then fold it into the total
+	// along with the best order-level promotion
+	order.CalculateTotals()
+	destination := models.ShippingDestination{Country: order.ShippingCountry, Region: order.ShippingState}
+	order.ShippingAmount = s.shippingSvc.Quote(ctx, order.SubtotalAmount, destination, weight).Cost
+	order.CalculateTotals()
+	s.promotions.Apply(ctx, order)
+	if req.CouponCode != nil && *req.CouponCode != "" {
+		if err := s.applyCoupon(ctx, order, *req.CouponCode, products); err != nil {
+			return nil, err
 		}
 	}
+	if err := s.minimums.Check(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CreateGuestOrder creates an order for a shopper without an account. The
+// order belongs to a guest record for the email, made on first checkout, and
+// comes back with the token the guest pays for it with. Emails of registered
+// accounts must sign in instead.
+func (s *orderService) CreateGuestOrder(ctx context.Context, req *models.GuestOrderRequest) (*models.Order, error) {
+	if !s.config.Order.GuestCheckout {
+		return nil, ErrGuestCheckoutDisabled
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+	guest, err := s.guestFor(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.createOrder(ctx, req.CreateOrderRequest(), guest.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.emailSvc.SendGuestOrderEmail(ctx, guest, order); err != nil {
+		fmt.Printf("Warning: failed to send guest order email for order %d: %v\n", order.ID, err)
+	}
 
 	return order, nil
 }
 
+// guestFor returns the guest record for the request's email, creating it on
+// the guest's first checkout
+func (s *orderService) guestFor(ctx context.Context, req *models.GuestOrderRequest) (*models.User, error) {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err == nil {
+		if !user.IsGuest {
+			return nil, ErrGuestEmailRegistered
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get guest: %w", err)
+	}
+
+	user = &models.User{
+		FirstName: strings.TrimSpace(req.FirstName),
+		LastName:  strings.TrimSpace(req.LastName),
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Role:      models.RoleCustomer,
+		IsActive:  true,
+		IsGuest:   true,
+	}
+
+	// Guests can't sign in; the password is random and replaced when the
+	// guest registers
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.HashPassword(password); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create guest: %w", err)
+	}
+	return user, nil
+}
+
+// ProcessGuestPayment pays for a guest's order with the token it was placed
+// with. A wrong token is reported as not found so order IDs can't be probed.
+// The order total is charged, as with any payment.
+func (s *orderService) ProcessGuestPayment(ctx context.Context, orderID uint, req *models.GuestPaymentRequest) (*models.PaymentResponse, error) {
+	if !s.config.Order.GuestCheckout {
+		return nil, ErrGuestCheckoutDisabled
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	tokenHash := utils.HashToken(req.GuestToken)
+	if order.GuestTokenHash == nil || subtle.ConstantTimeCompare([]byte(*order.GuestTokenHash), []byte(tokenHash)) != 1 {
+		return nil, ErrOrderNotFound
+	}
+
+	return s.ProcessPayment(ctx, orderID, &req.PaymentRequest)
+}
+
+// TrackOrder looks an order up by number for whoever knows the email it was
+// placed with, either the customer's or the shipping email. A wrong email
+// reads as an unknown order so the endpoint doesn't confirm which numbers exist.
+func (s *orderService) TrackOrder(ctx context.Context, req *models.OrderTrackRequest) (*models.OrderTrackingStatus, error) {
+	order, err := s.orderRepo.GetByOrderNumber(ctx, strings.TrimSpace(req.OrderNumber))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if !strings.EqualFold(email, order.Customer.Email) && !strings.EqualFold(email, order.ShippingEmail) {
+		return nil, ErrOrderNotFound
+	}
+
+	return models.NewOrderTrackingStatus(order), nil
+}
+
 func (s *orderService) GetOrder(ctx context.Context, id uint, userID uint, userRole models.UserRole) (*models.Order, error) {
 	order, err := s.orderRepo.GetByID(ctx, id)
 	if err != nil {
@@ -126,18 +469,29 @@ func (s *orderService) GetOrder(ctx context.Context, id uint, userID uint, userR
 				}
 			}
 			if !hasSellerItem {
-				return nil, errors.New("unauthorized to view this order")
+				return nil, ErrOrderViewForbidden
+			}
+		} else if userRole == models.RoleSellerStaff {
+			// Staff see the portion assigned to them, as their seller would
+			sellerID, ok := order.SellerAssignedTo(userID)
+			if !ok {
+				return nil, ErrOrderViewForbidden
 			}
+			order.ScopeToSeller(sellerID)
+			return order, nil
 		} else {
-			return nil, errors.New("unauthorized to view this order")
+			return nil, ErrOrderViewForbidden
 		}
+
+		// Sellers only see their own portion of a split order
+		order.ScopeToSeller(userID)
 	}
 
 	return order, nil
 }
 
-func (s *orderService) GetUserOrders(ctx context.Context, userID uint, limit, offset int) ([]*models.Order, error) {
-	orders, err := s.orderRepo.GetByUserID(ctx, userID, limit, offset)
+func (s *orderService) GetUserOrders(ctx context.Context, userID uint, includeArchived bool, limit, offset int) ([]*models.Order, error) {
+	orders, err := s.orderRepo.GetByUserID(ctx, userID, includeArchived, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user orders: %w", err)
 	}
@@ -145,13 +499,13 @@ func (s *orderService) GetUserOrders(ctx context.Context, userID uint, limit, of
 	return orders, nil
 }
 
-func (s *orderService) GetAllOrders(ctx context.Context, limit, offset int) ([]*models.Order, error) {
-	orders, err := s.orderRepo.GetAll(ctx, limit, offset)
+func (s *orderService) GetAllOrders(ctx context.Context, filter *models.OrderFilter, limit, offset int) ([]*models.Order, int64, error) {
+	orders, total, err := s.orderRepo.GetFiltered(ctx, filter, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all orders: %w", err)
+		return nil, 0, fmt.Errorf("failed to get all orders: %w", err)
 	}
 
-	return orders, nil
+	return orders, total, nil
 }
 
 func (s *orderService) GetOrdersByStatus(ctx context.Context, status models.OrderStatus, limit, offset int) ([]*models.Order, error) {
@@ -163,18 +517,146 @@ func (s *orderService) GetOrdersByStatus(ctx context.Context, status models.Orde
 	return orders, nil
 }
 
-func (s *orderService) GetSellerOrders(ctx context.Context, sellerID uint, limit, offset int) ([]*models.Order, error) {
-	orders, err := s.orderRepo.GetOrdersBySellerID(ctx, sellerID, limit, offset)
+func (s *orderService) GetSellerOrders(ctx context.Context, sellerID uint, filter *models.SellerOrderFilter, limit, offset int) ([]*models.Order, error) {
+	orders, err := s.orderRepo.GetOrdersBySellerID(ctx, sellerID, filter, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get seller orders: %w", err)
 	}
 
+	for _, order := range orders {
+		order.ScopeToSeller(sellerID)
+	}
+
 	return orders, nil
 }
 
+// GetAssignedOrders returns the live orders assigned to the staff member, each
+// scoped to the portion they're fulfilling
+func (s *orderService) GetAssignedOrders(ctx context.Context, staffID uint, limit, offset int) ([]*models.Order, error) {
+	orders, err := s.orderRepo.GetAssignedOrders(ctx, staffID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned orders: %w", err)
+	}
+
+	for _, order := range orders {
+		if sellerID, ok := order.SellerAssignedTo(staffID); ok {
+			order.ScopeToSeller(sellerID)
+		}
+	}
+
+	return orders, nil
+}
+
+// AssignOrder assigns the seller's portion of an order to one of the seller's
+// staff, or unassigns it, and notifies a newly assigned staff member. Sellers
+// assign their own portion; admins name the seller for split orders.
+func (s *orderService) AssignOrder(ctx context.Context, id uint, req *models.OrderAssignRequest, userID uint, userRole models.UserRole) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	sellerID := userID
+	if userRole == models.RoleAdmin {
+		switch {
+		case req.SellerID != nil:
+			sellerID = *req.SellerID
+		case order.IsSplit():
+			return nil, ErrAssignSellerRequired
+		case len(order.OrderItems) > 0:
+			sellerID = order.OrderItems[0].SellerID
+		}
+	}
+	if !order.HasSellerItems(sellerID) {
+		return nil, ErrOrderAssignForbidden
+	}
+	if order.IsFinished() {
+		return nil, ErrOrderNotAssignable
+	}
+
+	var staff *models.User
+	if req.StaffID != nil {
+		staff, err = s.userRepo.GetByID(ctx, *req.StaffID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get staff member: %w", err)
+		}
+		if staff == nil || !staff.IsStaffOf(sellerID) {
+			return nil, ErrStaffNotFound
+		}
+	}
+
+	previous := order.AssigneeFor(sellerID)
+	if group := order.AssignTo(sellerID, req.StaffID, time.Now()); group != nil {
+		err = s.orderRepo.UpdateFulfillment(ctx, group)
+	} else {
+		err = s.orderRepo.UpdateAssignment(ctx, order)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign order: %w", err)
+	}
+
+	if staff != nil && (previous == nil || *previous != staff.ID) {
+		s.notifyAssignee(ctx, order, staff.ID)
+	}
+
+	if userRole != models.RoleAdmin {
+		order.ScopeToSeller(sellerID)
+	}
+	return order, nil
+}
+
+// notifyAssignee tells a staff member an order was assigned to them. A failed
+// notification doesn't fail the assignment.
+func (s *orderService) notifyAssignee(ctx context.Context, order *models.Order, staffID uint) {
+	notification := &models.Notification{
+		UserID:  staffID,
+		Type:    models.NotificationTypeOrderAssigned,
+		Title:   fmt.Sprintf("Order %s was assigned to you", order.OrderNumber),
+		Message: fmt.Sprintf("You've been assigned to fulfill order %s.", order.OrderNumber),
+		Data:    orderNotificationData(order),
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		fmt.Printf("Warning: failed to notify staff member %d about order %d: %v\n", staffID, order.ID, err)
+	}
+}
+
+func (s *orderService) GetProductOrders(ctx context.Context, productID, userID uint, userRole models.UserRole, limit, offset int) ([]models.ProductOrderItem, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	// Sellers can only see orders for their own products
+	if userRole != models.RoleAdmin && product.SellerID != userID {
+		return nil, ErrProductOrdersForbidden
+	}
+
+	orders, err := s.orderRepo.GetOrdersByProductID(ctx, productID, product.SellerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product orders: %w", err)
+	}
+
+	items := make([]models.ProductOrderItem, len(orders))
+	for i, order := range orders {
+		order.ScopeToSeller(product.SellerID)
+		items[i] = order.ToProductOrderItem(productID)
+	}
+
+	return items, nil
+}
+
 func (s *orderService) UpdateOrderStatus(ctx context.Context, id uint, status models.OrderStatus, userID uint, userRole models.UserRole) error {
 	order, err := s.orderRepo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrderNotFound
+		}
 		return fmt.Errorf("failed to get order: %w", err)
 	}
 
@@ -191,50 +673,141 @@ func (s *orderService) UpdateOrderStatus(ctx context.Context, id uint, status mo
 				}
 			}
 			if !hasSellerItem {
-				return errors.New("unauthorized to update this order")
+				return ErrOrderUpdateForbidden
 			}
 		} else {
-			return errors.New("unauthorized to update order status")
+			return ErrOrderStatusForbidden
+		}
+
+		// On split orders a seller only moves their own fulfillment group
+		if order.IsSplit() {
+			return s.updateFulfillmentStatus(ctx, order, userID, status)
 		}
 	}
 
 	// Validate status transition
 	if !isValidStatusTransition(order.Status, status) {
-		return fmt.Errorf("invalid status transition from %s to %s", order.Status, status)
+		return errorf(ErrInvalidStatusTransition, "invalid status transition from %s to %s", order.Status, status)
 	}
 
 	if err := s.orderRepo.UpdateStatus(ctx, id, status); err != nil {
 		return fmt.Errorf("failed to update order status: %w", err)
 	}
 
+	s.recordStatusChange(ctx, order.ID, order.Status, status, userID, nil, nil)
+
+	return nil
+}
+
+// updateFulfillmentStatus moves one seller's fulfillment group and, once every
+// group has reached the same status, moves the parent order with them
+func (s *orderService) updateFulfillmentStatus(ctx context.Context, order *models.Order, sellerID uint, status models.OrderStatus) error {
+	fulfillment := order.FulfillmentForSeller(sellerID)
+	if fulfillment == nil {
+		return ErrOrderUpdateForbidden
+	}
+
+	// Cancelling part of an order would leave its payment and stock half released
+	if status == models.OrderStatusCancelled {
+		return ErrPartialCancel
+	}
+
+	if !isValidStatusTransition(fulfillment.Status, status) {
+		return errorf(ErrInvalidStatusTransition, "invalid status transition from %s to %s", fulfillment.Status, status)
+	}
+
+	now := time.Now()
+	fulfillment.Status = status
+	switch status {
+	case models.OrderStatusShipped:
+		fulfillment.ShippedAt = &now
+	case models.OrderStatusDelivered:
+		fulfillment.DeliveredAt = &now
+	}
+
+	if err := s.orderRepo.UpdateFulfillment(ctx, fulfillment); err != nil {
+		return fmt.Errorf("failed to update fulfillment status: %w", err)
+	}
+
+	for _, f := range order.Fulfillments {
+		if f.Status != status {
+			return nil
+		}
+	}
+
+	if !isValidStatusTransition(order.Status, status) {
+		return nil
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, order.ID, status); err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	s.recordStatusChange(ctx, order.ID, order.Status, status, sellerID, nil, nil)
+
 	return nil
 }
 
 func (s *orderService) ProcessPayment(ctx context.Context, orderID uint, paymentReq *models.PaymentRequest) (*models.PaymentResponse, error) {
 	order, err := s.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
 
 	if order.Status != models.OrderStatusPending {
-		return nil, errors.New("order is not in pending status")
+		return nil, ErrOrderNotPending
+	}
+
+	// Charge the order total, whatever amount the client sent
+	if paymentReq.Amount != 0 && money.FromFloat(paymentReq.Amount) != money.FromFloat(order.TotalAmount) {
+		return nil, ErrPaymentAmountMismatch
+	}
+	paymentReq.OrderID = order.ID
+	paymentReq.Amount = order.TotalAmount
+
+	// A previous failed attempt released the stock, so reserve it again before charging
+	active, err := s.reservationRepo.CountByOrderID(ctx, orderID, models.ReservationStatusReserved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check stock reservation: %w", err)
+	}
+	if active == 0 {
+		if err := s.reserveStock(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+
+	// Likewise the coupon hold; if its uses ran out meanwhile, don't charge
+	if order.CouponID != nil {
+		if err := s.couponSvc.Hold(ctx, *order.CouponID, order.ID); err != nil {
+			s.markPaymentFailed(ctx, order)
+			return nil, err
+		}
 	}
 
 	// Process payment using payment service
 	paymentIntentID, err := s.paymentSvc.CreatePaymentIntent(paymentReq)
 	if err != nil {
+		s.markPaymentFailed(ctx, order)
 		return nil, fmt.Errorf("payment processing failed: %w", err)
 	}
 
+	// Keep the payment ID so provider webhooks (e.g. disputes) can find the order
+	if err := s.orderRepo.UpdatePaymentID(ctx, orderID, paymentIntentID); err != nil {
+		fmt.Printf("Warning: failed to save payment ID for order %d: %v\n", orderID, err)
+	}
+
 	// Confirm payment
 	err = s.paymentSvc.ConfirmPayment(paymentIntentID)
 	if err != nil {
+		s.markPaymentFailed(ctx, order)
 		return nil, fmt.Errorf("payment confirmation failed: %w", err)
 	}
 
-	// Update order status to confirmed
-	if err := s.orderRepo.UpdateStatus(ctx, orderID, models.OrderStatusConfirmed); err != nil {
-		return nil, fmt.Errorf("failed to update order status after payment: %w", err)
+	if err := s.finalizeOrder(ctx, order, paymentIntentID); err != nil {
+		return nil, err
 	}
 
 	return &models.PaymentResponse{
@@ -244,38 +817,1081 @@ func (s *orderService) ProcessPayment(ctx context.Context, orderID uint, payment
 	}, nil
 }
 
-func (s *orderService) CancelOrder(ctx context.Context, id uint, userID uint, userRole models.UserRole) error {
-	order, err := s.orderRepo.GetByID(ctx, id)
+// HandlePaymentSucceeded finalizes the order behind a payment the provider
+// reports as collected. Orders already finalized, e.g. by ProcessPayment, are
+// left alone, so repeated or late webhooks are harmless.
+func (s *orderService) HandlePaymentSucceeded(ctx context.Context, paymentIntentID string) error {
+	order, err := s.orderRepo.GetByPaymentID(ctx, paymentIntentID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Nothing to finalize; acknowledge so the provider doesn't keep retrying
+			fmt.Printf("Warning: no order found for succeeded payment %s\n", paymentIntentID)
+			return nil
+		}
 		return fmt.Errorf("failed to get order: %w", err)
 	}
 
-	// Check authorization
-	if userRole != models.RoleAdmin && order.CustomerID != userID {
-		return errors.New("unauthorized to cancel this order")
+	if order.PaymentStatus == models.PaymentStatusPaid || order.PaymentStatus == models.PaymentStatusRefunded {
+		return nil
 	}
 
-	// Can only cancel pending or confirmed orders
-	if order.Status != models.OrderStatusPending && order.Status != models.OrderStatusConfirmed {
-		return errors.New("order cannot be cancelled in its current status")
+	return s.finalizeOrder(ctx, order, paymentIntentID)
+}
+
+// finalizeOrder completes a charged order. It is confirmed only once the
+// provider reports the order total as collected and its reserved stock is
+// committed. If a different amount was collected, or the stock can't be
+// committed, e.g. because the reservation expired and the order was cancelled
+// while the payment was in flight, what was collected is refunded and the
+// order cancelled instead. Both ProcessPayment and the payment webhook
+// finalize through here, whichever comes first.
+func (s *orderService) finalizeOrder(ctx context.Context, order *models.Order, paymentIntentID string) error {
+	info, err := s.paymentSvc.GetPayment(paymentIntentID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+	if info.AmountReceived == 0 {
+		// Nothing collected yet; the webhook finalizes once it is
+		return ErrPaymentNotCollected
+	}
+	if money.FromFloat(info.AmountReceived) != money.FromFloat(order.TotalAmount) {
+		note := fmt.Sprintf("Payment collected %.2f instead of the order total %.2f; the payment was refunded", info.AmountReceived, order.TotalAmount)
+		s.refundUncommittedOrder(ctx, order, paymentIntentID, info.AmountReceived, models.CancellationReasonPaymentFailed, note, ErrPaymentAmountMismatch)
+		return ErrPaymentNotCaptured
 	}
 
-	// Restore product stock
-	for _, item := range order.OrderItems {
-		product, err := s.productRepo.GetByID(ctx, item.ProductID)
-		if err == nil {
-			newStock := product.Stock + item.Quantity
-			s.productRepo.UpdateStock(ctx, item.ProductID, newStock)
+	if err := s.commitStock(ctx, order); err != nil {
+		note := "Stock could not be committed after payment; the payment was refunded"
+		s.refundUncommittedOrder(ctx, order, paymentIntentID, info.AmountReceived, models.CancellationReasonOutOfStock, note, err)
+		return ErrStockNotCommitted
+	}
+
+	if order.CouponID != nil {
+		if err := s.couponSvc.Commit(ctx, *order.CouponID, order.ID); err != nil {
+			fmt.Printf("Warning: failed to commit coupon use for order %d: %v\n", order.ID, err)
 		}
 	}
 
-	if err := s.orderRepo.UpdateStatus(ctx, id, models.OrderStatusCancelled); err != nil {
-		return fmt.Errorf("failed to cancel order: %w", err)
+	if err := s.orderRepo.UpdatePaymentStatus(ctx, order.ID, models.PaymentStatusPaid); err != nil {
+		return fmt.Errorf("failed to update payment status: %w", err)
+	}
+
+	// Confirm the order, or hold it for a merchant to confirm when auto-confirmation is off
+	nextStatus := models.OrderStatusConfirmed
+	if !s.config.Order.AutoConfirm {
+		nextStatus = models.OrderStatusAwaitingConfirmation
+	}
+	if err := s.orderRepo.UpdateStatus(ctx, order.ID, nextStatus); err != nil {
+		return fmt.Errorf("failed to update order status after payment: %w", err)
+	}
+	// Changed by the system, not a user
+	s.recordStatusChange(ctx, order.ID, order.Status, nextStatus, 0, nil, nil)
+
+	return nil
+}
+
+// commitStock marks the order's reserved stock as sold. It fails when the
+// reservation is gone; an order already committed by a concurrent finalize,
+// or created before reservations existed, counts as committed.
+func (s *orderService) commitStock(ctx context.Context, order *models.Order) error {
+	committed, err := s.reservationRepo.Commit(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to commit stock reservation: %w", err)
+	}
+	if committed > 0 {
+		return nil
+	}
+
+	alreadyCommitted, err := s.reservationRepo.CountByOrderID(ctx, order.ID, models.ReservationStatusCommitted)
+	if err != nil {
+		return fmt.Errorf("failed to check stock reservation: %w", err)
+	}
+	if alreadyCommitted > 0 {
+		return nil
 	}
 
+	hasReservations, err := s.reservationRepo.HasAny(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check stock reservation: %w", err)
+	}
+	if hasReservations {
+		return ErrReservationReleased
+	}
 	return nil
 }
 
+// refundPayment refunds amount of the order's payment. Every refund goes
+// through here, so a payment under dispute or already refunded is never paid
+// back a second time.
+func refundPayment(paymentSvc payment.Service, order *models.Order, paymentIntentID string, amount float64) error {
+	if !order.CanRefund() {
+		return ErrPaymentNotRefundable
+	}
+	return paymentSvc.RefundPayment(paymentIntentID, amount)
+}
+
+// refundUncommittedOrder rolls back a charge that can't confirm the order: the
+// amount collected is refunded, anything still held is released and the order
+// is cancelled for the given reason. A failed refund leaves the order marked
+// paid so the charge stays visible to admins.
+func (s *orderService) refundUncommittedOrder(ctx context.Context, order *models.Order, paymentIntentID string, collected float64, reason models.CancellationReason, note string, cause error) {
+	fmt.Printf("Warning: refunding order %d after finalizing failed: %v\n", order.ID, cause)
+
+	paymentStatus := models.PaymentStatusRefunded
+	if err := refundPayment(s.paymentSvc, order, paymentIntentID, collected); err != nil {
+		fmt.Printf("Warning: failed to refund order %d: %v\n", order.ID, err)
+		paymentStatus = models.PaymentStatusPaid
+	}
+	// A dispute opened before the order was finalized keeps its status until it closes
+	if order.PaymentStatus != models.PaymentStatusDisputed {
+		if err := s.orderRepo.UpdatePaymentStatus(ctx, order.ID, paymentStatus); err != nil {
+			fmt.Printf("Warning: failed to update payment status for order %d: %v\n", order.ID, err)
+		}
+	}
+
+	s.releaseReservations(ctx, order.ID, models.ReservationStatusReserved)
+	s.releaseCoupon(ctx, order)
+
+	if order.Status == models.OrderStatusCancelled {
+		return
+	}
+	if err := s.orderRepo.Cancel(ctx, order.ID, reason, &note); err != nil {
+		fmt.Printf("Warning: failed to cancel order %d after refund: %v\n", order.ID, err)
+		return
+	}
+	// Changed by the system, not a user
+	s.recordStatusChange(ctx, order.ID, order.Status, models.OrderStatusCancelled, 0, &reason, &note)
+}
+
+func (s *orderService) CancelOrder(ctx context.Context, id uint, req *models.CancelOrderRequest, userID uint, userRole models.UserRole) error {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrderNotFound
+		}
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	// Check authorization
+	if userRole != models.RoleAdmin && order.CustomerID != userID {
+		return ErrOrderCancelForbidden
+	}
+
+	// Can only cancel pending, held or confirmed orders
+	if !order.CanCancel() {
+		return ErrOrderNotCancellable
+	}
+
+	// Restore product stock, whether it was still reserved or already paid for
+	s.releaseStock(ctx, order, models.ReservationStatusReserved, models.ReservationStatusCommitted)
+	s.releaseCoupon(ctx, order)
+
+	reason := req.Reason
+	if reason == "" {
+		reason = models.CancellationReasonOther
+	}
+
+	if err := s.orderRepo.Cancel(ctx, id, reason, req.Note); err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	s.recordStatusChange(ctx, order.ID, order.Status, models.OrderStatusCancelled, userID, &reason, req.Note)
+
+	return nil
+}
+
+func (s *orderService) GetCancellationAnalytics(ctx context.Context, startDate, endDate time.Time) (*models.CancellationAnalytics, error) {
+	breakdown, err := s.orderRepo.GetCancellationBreakdown(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cancellation breakdown: %w", err)
+	}
+
+	var total int64
+	for _, entry := range breakdown {
+		total += entry.Count
+	}
+
+	return &models.CancellationAnalytics{
+		StartDate:      startDate,
+		EndDate:        endDate,
+		TotalCancelled: total,
+		ByReason:       breakdown,
+	}, nil
+}
+
+// GetBestSellers ranks the top products by units sold on orders placed in the range
+func (s *orderService) GetBestSellers(ctx context.Context, startDate, endDate time.Time, limit int) (*models.BestSellersReport, error) {
+	bestSellers, err := s.orderRepo.GetBestSellers(ctx, startDate, endDate, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best sellers: %w", err)
+	}
+
+	return &models.BestSellersReport{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Products:  bestSellers,
+	}, nil
+}
+
+// EachBestSeller walks the full best-seller ranking for exports
+func (s *orderService) EachBestSeller(ctx context.Context, startDate, endDate time.Time, fn func(*models.BestSeller) error) error {
+	return s.orderRepo.EachBestSeller(ctx, startDate, endDate, fn)
+}
+
+// EachOrderExportLine walks the item lines of the orders placed in the range
+// for the accounting export. An order's lines are held until its last one is
+// read, so its discount, tax and shipping can be split across them exactly.
+func (s *orderService) EachOrderExportLine(ctx context.Context, startDate, endDate time.Time, fn func(*models.OrderExportLine) error) error {
+	var pending []*models.OrderExportLine
+	flush := func() error {
+		models.AllocateOrderExportLines(pending)
+		for _, line := range pending {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
+		pending = pending[:0]
+		return nil
+	}
+
+	err := s.orderRepo.EachOrderExportLine(ctx, startDate, endDate, func(line *models.OrderExportLine) error {
+		if len(pending) > 0 && pending[0].OrderID != line.OrderID {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		pending = append(pending, line)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export orders: %w", err)
+	}
+	return flush()
+}
+
+func (s *orderService) GetFlaggedOrders(ctx context.Context, limit, offset int) ([]models.FraudReviewItem, error) {
+	orders, err := s.orderRepo.GetByStatus(ctx, models.OrderStatusPendingReview, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flagged orders: %w", err)
+	}
+
+	items := make([]models.FraudReviewItem, len(orders))
+	for i, order := range orders {
+		items[i] = order.ToFraudReviewItem()
+	}
+
+	return items, nil
+}
+
+func (s *orderService) ReviewFlaggedOrder(ctx context.Context, id uint, req *models.FraudReviewRequest, adminID uint) error {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if !order.IsPendingReview() {
+		return ErrOrderNotPendingReview
+	}
+
+	if req.Approve {
+		// Release the order back into the normal payment flow
+		if err := s.orderRepo.UpdateStatus(ctx, id, models.OrderStatusPending); err != nil {
+			return fmt.Errorf("failed to approve order: %w", err)
+		}
+		s.recordStatusChange(ctx, order.ID, order.Status, models.OrderStatusPending, adminID, nil, req.Note)
+
+		// Give the customer a full window to pay from the time of approval
+		if err := s.reservationRepo.ExtendExpiry(ctx, id, time.Now().Add(s.config.Order.StockReservationTTL)); err != nil {
+			fmt.Printf("Warning: failed to extend stock reservation for order %d: %v\n", id, err)
+		}
+		return nil
+	}
+
+	return s.CancelOrder(ctx, id, &models.CancelOrderRequest{
+		Reason: models.CancellationReasonFraud,
+		Note:   req.Note,
+	}, adminID, models.RoleAdmin)
+}
+
+// UpdateShippingAddress changes where the customer's order ships while it is
+// still before fulfillment, and records the old and new address in the
+// order's status history. Shipping is quoted again for the new destination.
+// An unpaid order takes the new quote. A charged order keeps what it paid, so
+// an address that costs more to ship to is refused rather than left unpaid.
+func (s *orderService) UpdateShippingAddress(ctx context.Context, id uint, req *models.UpdateShippingAddressRequest, userID uint) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if order.CustomerID != userID {
+		return nil, ErrOrderModifyForbidden
+	}
+
+	if order.HasShipped() {
+		return nil, ErrOrderShipped
+	}
+	if !order.CanChangeShippingAddress() {
+		return nil, ErrOrderNotModifiable
+	}
+
+	address := req.Address()
+	if req.AddressID != nil {
+		address, err = s.addressSvc.GetAddress(ctx, userID, *req.AddressID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	previous := order.GetShippingAddress()
+	address.CopyToShipping(order)
+
+	var weight float64
+	for i := range order.OrderItems {
+		weight += order.OrderItems[i].Product.ShippingWeight(order.OrderItems[i].Quantity)
+	}
+	destination := models.ShippingDestination{Country: order.ShippingCountry, Region: order.ShippingState}
+	shipping := s.shippingSvc.Quote(ctx, order.SubtotalAmount, destination, weight).Cost
+	if order.WasCharged() {
+		if money.FromFloat(shipping) > money.FromFloat(order.ShippingAmount) {
+			return nil, ErrShippingCostIncrease
+		}
+	} else {
+		order.ShippingAmount = shipping
+		order.CalculateTotals()
+		order.ReallocateFulfillments()
+	}
+
+	if err := s.orderRepo.UpdateShippingAddress(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to update shipping address: %w", err)
+	}
+
+	note := fmt.Sprintf("Shipping address changed from:\n%s\nto:\n%s", previous, order.GetShippingAddress())
+	s.recordStatusChange(ctx, order.ID, order.Status, order.Status, userID, nil, &note)
+
+	return order, nil
+}
+
+// ResendConfirmationEmail sends the order confirmation email again, at most
+// confirmationResendLimit times an hour per order, and records each resend in
+// the order's status history
+func (s *orderService) ResendConfirmationEmail(ctx context.Context, id uint, userID uint, userRole models.UserRole) error {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if userRole != models.RoleAdmin && order.CustomerID != userID {
+		return ErrOrderViewForbidden
+	}
+
+	if order.Status == models.OrderStatusCancelled {
+		return ErrOrderCancelled
+	}
+
+	sent, err := s.orderRepo.CountStatusHistoryNotes(ctx, id, models.OrderNoteConfirmationResent, time.Now().Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to check confirmation resends: %w", err)
+	}
+	if sent >= confirmationResendLimit {
+		return ErrResendLimitReached
+	}
+
+	customer, err := s.userRepo.GetByID(ctx, order.CustomerID)
+	if err != nil {
+		return fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	if err := s.emailSvc.SendOrderConfirmationEmail(ctx, customer, order); err != nil {
+		return fmt.Errorf("failed to send confirmation email: %w", err)
+	}
+
+	note := models.OrderNoteConfirmationResent
+	s.recordStatusChange(ctx, order.ID, order.Status, order.Status, userID, nil, &note)
+
+	return nil
+}
+
+// GetConfirmationQueue returns paid orders awaiting confirmation, oldest
+// first. Sellers only see orders containing their products, scoped to their portion.
+func (s *orderService) GetConfirmationQueue(ctx context.Context, userID uint, userRole models.UserRole, limit, offset int) ([]*models.Order, error) {
+	if userRole == models.RoleAdmin {
+		orders, err := s.orderRepo.GetAwaitingConfirmation(ctx, nil, limit, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get confirmation queue: %w", err)
+		}
+		return orders, nil
+	}
+
+	orders, err := s.orderRepo.GetAwaitingConfirmation(ctx, &userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get confirmation queue: %w", err)
+	}
+	for _, order := range orders {
+		order.ScopeToSeller(userID)
+	}
+	return orders, nil
+}
+
+// ReviewOrderConfirmation approves or rejects a paid order awaiting
+// confirmation. Approval confirms the order, or on a split order the seller's
+// own portion. Rejection refunds the payment, then cancels the whole order; a
+// disputed payment can't be refunded, and a failed refund leaves the order
+// awaiting confirmation so the rejection can be tried again.
+func (s *orderService) ReviewOrderConfirmation(ctx context.Context, id uint, req *models.OrderConfirmationRequest, userID uint, userRole models.UserRole) error {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if order.Status != models.OrderStatusAwaitingConfirmation {
+		return ErrOrderNotAwaitingConfirmation
+	}
+
+	if req.Approve {
+		return s.UpdateOrderStatus(ctx, id, models.OrderStatusConfirmed, userID, userRole)
+	}
+
+	if userRole != models.RoleAdmin && !s.hasSellerItem(ctx, order, userID) {
+		return ErrOrderUpdateForbidden
+	}
+
+	// The chargeback is already returning the money; refunding it as well
+	// would pay the customer twice
+	if order.PaymentStatus == models.PaymentStatusDisputed {
+		return ErrOrderPaymentDisputed
+	}
+
+	// Refund before cancelling, so a failed refund can be retried. An order
+	// already refunded by an earlier attempt skips straight to cancelling.
+	if order.PaymentID != nil && order.PaymentStatus == models.PaymentStatusPaid {
+		if err := refundPayment(s.paymentSvc, order, *order.PaymentID, order.TotalAmount); err != nil {
+			return fmt.Errorf("failed to refund rejected order: %w", err)
+		}
+		if err := s.orderRepo.UpdatePaymentStatus(ctx, id, models.PaymentStatusRefunded); err != nil {
+			return fmt.Errorf("refunded rejected order %d but failed to record it: %w", id, err)
+		}
+	}
+
+	// Sellers can't cancel through CancelOrder, so cancel with admin rights once authorized
+	return s.CancelOrder(ctx, id, &models.CancelOrderRequest{
+		Reason: models.CancellationReasonRejected,
+		Note:   req.Note,
+	}, userID, models.RoleAdmin)
+}
+
+// hasSellerItem reports whether the order contains any of the seller's products
+func (s *orderService) hasSellerItem(ctx context.Context, order *models.Order, sellerID uint) bool {
+	for _, item := range order.OrderItems {
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err == nil && product.SellerID == sellerID {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFraudScore scores a new order and holds it for review above the threshold
+func (s *orderService) applyFraudScore(ctx context.Context, order *models.Order) {
+	if s.fraudSvc == nil {
+		return
+	}
+
+	user, err := s.userRepo.GetByID(ctx, order.CustomerID)
+	if err != nil {
+		fmt.Printf("Warning: failed to load user %d for fraud scoring: %v\n", order.CustomerID, err)
+		user = nil
+	}
+
+	score, reasons := s.fraudSvc.Score(ctx, order, user)
+	order.FraudScore = score
+	if len(reasons) > 0 {
+		order.SetFraudReasons(reasons)
+	}
+
+	if score >= s.config.Order.FraudReviewThreshold {
+		order.Status = models.OrderStatusPendingReview
+	}
+}
+
+// markPaymentFailed records a failed payment attempt on the order and
+// releases its reserved stock and coupon hold; a retry takes them again
+func (s *orderService) markPaymentFailed(ctx context.Context, order *models.Order) {
+	if err := s.orderRepo.UpdatePaymentStatus(ctx, order.ID, models.PaymentStatusFailed); err != nil {
+		fmt.Printf("Warning: failed to mark payment failed for order %d: %v\n", order.ID, err)
+	}
+	s.releaseReservations(ctx, order.ID, models.ReservationStatusReserved)
+	s.releaseCoupon(ctx, order)
+}
+
+// applyCoupon checks the coupon can be used and adds its discount to the
+// order, combined with any promotion per the configured stacking policy. The
+// use is held once the order is created.
+// A targeted coupon discounts only the matching items, as line discounts.
+// Order totals must already include the promotion.
+func (s *orderService) applyCoupon(ctx context.Context, order *models.Order, code string, products map[uint]*models.Product) error {
+	lines := make([]models.PromotionLine, 0, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		lines = append(lines, models.PromotionLine{
+			ProductID:  item.ProductID,
+			CategoryID: products[item.ProductID].CategoryID,
+			Quantity:   item.Quantity,
+			UnitPrice:  item.UnitPrice,
+			LineTotal:  item.LineTotal(),
+		})
+	}
+
+	coupon, discount, err := s.couponSvc.Apply(ctx, code, order.CustomerID, order.SubtotalAmount, lines)
+	if err != nil {
+		return err
+	}
+
+	if s.config.Order.CouponStacking == models.CouponStackingBest && order.PromotionID != nil {
+		if discount <= order.DiscountAmount {
+			// The promotion is worth more, so the coupon isn't used
+			return nil
+		}
+		order.DiscountAmount = 0
+		order.PromotionID = nil
+		order.PromotionName = nil
+	}
+
+	order.CouponID = &coupon.ID
+	order.CouponCode = &coupon.Code
+
+	if coupon.Targeted() {
+		order.CouponDiscount = order.ApplyItemDiscount(func(item *models.OrderItem) bool {
+			return coupon.Matches(item.ProductID, products[item.ProductID].CategoryID)
+		}, discount)
+		return nil
+	}
+
+	// Never discount past the subtotal
+	couponDiscount := money.FromFloat(discount).Clamp(money.FromFloat(order.SubtotalAmount) - money.FromFloat(order.DiscountAmount))
+
+	order.CouponDiscount = couponDiscount.Float()
+	order.DiscountAmount = (money.FromFloat(order.DiscountAmount) + couponDiscount).Float()
+	order.CalculateTotals()
+	return nil
+}
+
+// releaseCoupon drops the order's hold on its coupon, if any. Holds of
+// orders that expire unpaid lapse on their own after the hold TTL.
+func (s *orderService) releaseCoupon(ctx context.Context, order *models.Order) {
+	if order.CouponID != nil {
+		s.couponSvc.Release(ctx, *order.CouponID, order.ID)
+	}
+}
+
+// reserveStock decrements stock for each order line and records a reservation
+// that expires after the configured TTL. Stock may go below zero only as far as
+// each product's oversell tolerance; the decrement itself enforces it, so
+// concurrent orders can't oversell past it either.
+func (s *orderService) reserveStock(ctx context.Context, order *models.Order) error {
+	products := make(map[uint]*models.Product, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return fmt.Errorf("failed to get product %d: %w", item.ProductID, err)
+		}
+		if !product.CanFulfill(item.Quantity) {
+			return errorf(ErrInsufficientStock, "insufficient stock for product %s (available: %d, requested: %d)",
+				product.Name, product.Stock, item.Quantity)
+		}
+		products[product.ID] = product
+	}
+
+	expiresAt := time.Now().Add(s.config.Order.StockReservationTTL)
+	reservations := make([]models.StockReservation, 0, len(order.OrderItems))
+	oversold := make(map[uint]int)
+	for _, item := range order.OrderItems {
+		remaining, ok, err := s.productRepo.DecrementStock(ctx, item.ProductID, item.Quantity)
+		if err == nil && !ok {
+			product := products[item.ProductID]
+			err = errorf(ErrInsufficientStock, "insufficient stock for product %s (requested: %d)", product.Name, item.Quantity)
+		}
+		if err != nil {
+			// Put back what was already taken before giving up
+			for _, reserved := range reservations {
+				s.restoreStock(ctx, reserved.ProductID, reserved.Quantity)
+			}
+			return fmt.Errorf("failed to reserve stock for product %d: %w", item.ProductID, err)
+		}
+		// Backorders within the product's limit are expected, not oversells
+		if remaining < -products[item.ProductID].BackorderLimit() {
+			oversold[item.ProductID] = remaining
+		}
+		reservations = append(reservations, models.StockReservation{
+			OrderID:   order.ID,
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Status:    models.ReservationStatusReserved,
+			ExpiresAt: expiresAt,
+		})
+	}
+
+	if err := s.reservationRepo.CreateBatch(ctx, reservations); err != nil {
+		for _, reserved := range reservations {
+			s.restoreStock(ctx, reserved.ProductID, reserved.Quantity)
+		}
+		return fmt.Errorf("failed to record stock reservation: %w", err)
+	}
+
+	productIDs := make([]uint, len(reservations))
+	for i, reserved := range reservations {
+		productIDs[i] = reserved.ProductID
+	}
+	s.productCache.Invalidate(ctx, productIDs...)
+
+	for productID, stock := range oversold {
+		s.alertOversell(ctx, order, products[productID], stock)
+	}
+
+	return nil
+}
+
+// alertOversell warns the seller, in the app and by email, that the order took
+// the product's stock below zero. A failed alert doesn't fail the order.
+func (s *orderService) alertOversell(ctx context.Context, order *models.Order, product *models.Product, stock int) {
+	data := oversellNotificationData(order, product, stock)
+	notification := &models.Notification{
+		UserID: product.SellerID,
+		Type:   models.NotificationTypeProductOversold,
+		Title:  fmt.Sprintf("Urgent: %s is oversold", product.Name),
+		Message: fmt.Sprintf("Order %s took %s to %d in stock, %d of its oversell tolerance of %d. Restock or contact the customer before it ships.",
+			order.OrderNumber, product.Name, stock, -stock, product.OversellTolerance),
+		Data: data,
+	}
+	if err := s.notificationRepo.CreateBatch(ctx, []*models.Notification{notification}); err != nil {
+		fmt.Printf("Warning: failed to notify seller %d about oversold product %d: %v\n", product.SellerID, product.ID, err)
+	}
+
+	seller, err := s.userRepo.GetByID(ctx, product.SellerID)
+	if err != nil {
+		fmt.Printf("Warning: failed to get seller %d for oversell alert: %v\n", product.SellerID, err)
+		return
+	}
+	if err := s.emailSvc.SendOversellAlert(ctx, seller, product, order, stock); err != nil {
+		fmt.Printf("Warning: failed to email oversell alert for product %d: %v\n", product.ID, err)
+	}
+}
+
+// releaseStock returns an order's stock. Orders created before reservations
+// existed have none, so their stock is restored from the order items.
+func (s *orderService) releaseStock(ctx context.Context, order *models.Order, statuses ...models.ReservationStatus) {
+	hasReservations, err := s.reservationRepo.HasAny(ctx, order.ID)
+	if err != nil {
+		fmt.Printf("Warning: failed to check stock reservations for order %d: %v\n", order.ID, err)
+		return
+	}
+
+	if !hasReservations {
+		for _, item := range order.OrderItems {
+			s.restoreStock(ctx, item.ProductID, item.Quantity)
+		}
+		return
+	}
+
+	s.releaseReservations(ctx, order.ID, statuses...)
+}
+
+// releaseReservations releases the order's reservations in the given statuses,
+// restores their stock and returns how many were released
+func (s *orderService) releaseReservations(ctx context.Context, orderID uint, statuses ...models.ReservationStatus) int {
+	released, err := s.reservationRepo.Release(ctx, orderID, statuses...)
+	if err != nil {
+		fmt.Printf("Warning: failed to release stock reservation for order %d: %v\n", orderID, err)
+		return 0
+	}
+	for _, reservation := range released {
+		s.restoreStock(ctx, reservation.ProductID, reservation.Quantity)
+	}
+	return len(released)
+}
+
+func (s *orderService) restoreStock(ctx context.Context, productID uint, quantity int) {
+	if err := s.productRepo.AdjustStock(ctx, productID, quantity); err != nil {
+		fmt.Printf("Warning: failed to restore stock for product %d: %v\n", productID, err)
+		return
+	}
+	s.productCache.Invalidate(ctx, productID)
+}
+
+// RecalculateOrder repairs an order's stored totals from its items: each
+// line's total from its unit price and quantity, shipping re-quoted for the
+// items' weight and the shipping destination, and the order total. Discounts
+// and tax are kept as charged; split orders' seller allocations follow. Once
+// the order was charged or has shipped its totals stand for what the customer
+// paid, so they're only recomputed with force. The change is audited.
+func (s *orderService) RecalculateOrder(ctx context.Context, id, adminID uint, req *models.OrderRecalculateRequest) (*models.OrderRecalculation, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if (order.WasCharged() || order.HasShipped()) && !req.Force {
+		return nil, ErrRecalculateNeedsForce
+	}
+
+	before := order.Totals()
+	before.ItemCount = order.ItemCount
+
+	order.RepriceItems()
+	order.CalculateTotals()
+	var weight float64
+	for i := range order.OrderItems {
+		weight += order.OrderItems[i].Product.ShippingWeight(order.OrderItems[i].Quantity)
+	}
+	destination := models.ShippingDestination{Country: order.ShippingCountry, Region: order.ShippingState}
+	order.ShippingAmount = s.shippingSvc.Quote(ctx, order.SubtotalAmount, destination, weight).Cost
+	order.CalculateTotals()
+	order.ReallocateFulfillments()
+
+	after := order.Totals()
+	recalculation := &models.OrderRecalculation{Order: order, Before: before, After: after, Changed: before != after}
+	if !recalculation.Changed {
+		return recalculation, nil
+	}
+
+	details := fmt.Sprintf("before: %s; after: %s", formatTotals(before), formatTotals(after))
+	if req.Force {
+		details += "; forced"
+	}
+	if req.Note != nil && *req.Note != "" {
+		details += "; note: " + *req.Note
+	}
+	entry := &models.AuditLog{
+		ActorID:    adminID,
+		Action:     "order.recalculate",
+		EntityType: "order",
+		EntityID:   order.ID,
+		Details:    &details,
+	}
+	if err := s.orderRepo.SaveRecalculation(ctx, order, entry); err != nil {
+		return nil, fmt.Errorf("failed to save recalculated order: %w", err)
+	}
+
+	return recalculation, nil
+}
+
+// formatTotals formats order totals for the audit log
+func formatTotals(totals models.OrderTotals) string {
+	return fmt.Sprintf("items=%d subtotal=%s discount=%s shipping=%s tax=%s total=%s",
+		totals.ItemCount, money.Format(totals.Subtotal), money.Format(totals.DiscountAmount),
+		money.Format(totals.ShippingAmount), money.Format(totals.TaxAmount), money.Format(totals.Total))
+}
+
+// ExtendReservation gives the customer's unpaid order a fresh reservation TTL
+// to finish checking out, up to the longest an order may hold its stock
+// counting from when it was placed. Reservations that already lapsed aren't
+// revived; the sweeper releases those.
+func (s *orderService) ExtendReservation(ctx context.Context, id, userID uint) (*models.ReservationHold, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.CustomerID != userID {
+		return nil, ErrOrderViewForbidden
+	}
+	if order.Status != models.OrderStatusPending {
+		return nil, ErrOrderNotPending
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.config.Order.StockReservationTTL)
+	if limit := order.CreatedAt.Add(s.config.Order.StockReservationMax); limit.Before(expiresAt) {
+		expiresAt = limit
+	}
+	if !expiresAt.After(now) {
+		return nil, ErrReservationHoldLimit
+	}
+
+	extended, err := s.reservationRepo.ExtendActive(ctx, id, now, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extend stock reservation: %w", err)
+	}
+	if extended == 0 {
+		return nil, ErrReservationReleased
+	}
+
+	return &models.ReservationHold{OrderID: id, ExpiresAt: expiresAt}, nil
+}
+
+// ReleaseExpiredReservations cancels a batch of unpaid orders whose reservations
+// have expired and returns their stock. It returns the number of orders expired;
+// anything beyond the batch is picked up on the next sweep.
+func (s *orderService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	orderIDs, err := s.reservationRepo.GetExpiredOrderIDs(ctx, time.Now(), expiredReservationBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired reservations: %w", err)
+	}
+
+	expired := 0
+	for _, orderID := range orderIDs {
+		// Payment may have committed the reservations since they were listed;
+		// then there's nothing to release and the order stays as it is
+		if s.releaseReservations(ctx, orderID, models.ReservationStatusReserved) == 0 {
+			continue
+		}
+
+		reason := models.CancellationReasonExpired
+		note := "Payment was not completed before the stock reservation expired"
+		cancelled, err := s.orderRepo.CancelPending(ctx, orderID, reason, &note)
+		if err != nil {
+			fmt.Printf("Warning: failed to cancel expired order %d: %v\n", orderID, err)
+			continue
+		}
+		if !cancelled {
+			continue
+		}
+		// Changed by the system, not a user
+		s.recordStatusChange(ctx, orderID, models.OrderStatusPending, models.OrderStatusCancelled, 0, &reason, &note)
+		expired++
+	}
+
+	return expired, nil
+}
+
+// StartReservationSweeper periodically releases expired reservations until ctx is done
+func (s *orderService) StartReservationSweeper(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				count, err := s.ReleaseExpiredReservations(ctx)
+				if err != nil {
+					fmt.Printf("Warning: stock reservation sweep failed: %v\n", err)
+					continue
+				}
+				if count > 0 {
+					fmt.Printf("Released stock for %d expired orders\n", count)
+				}
+			}
+		}
+	}()
+}
+
+// slaThresholds returns the configured time allowed in each status
+func (s *orderService) slaThresholds() map[models.OrderStatus]time.Duration {
+	return map[models.OrderStatus]time.Duration{
+		models.OrderStatusPendingReview: s.config.Order.SLAPendingReview,
+		models.OrderStatusConfirmed:     s.config.Order.SLAConfirmed,
+		models.OrderStatusProcessing:    s.config.Order.SLAProcessing,
+		models.OrderStatusShipped:       s.config.Order.SLAShipped,
+	}
+}
+
+// GetStuckOrders returns orders that have been in their status longer than the SLA allows
+func (s *orderService) GetStuckOrders(ctx context.Context, limit, offset int) ([]models.StuckOrder, error) {
+	now := time.Now()
+	stuck, err := s.orderRepo.GetStuckOrders(ctx, s.slaThresholds(), now, false, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stuck orders: %w", err)
+	}
+
+	s.fillSLATimeline(stuck, now)
+	return stuck, nil
+}
+
+// fillSLATimeline sets when each stuck order was due to move on and how late it is
+func (s *orderService) fillSLATimeline(stuck []models.StuckOrder, now time.Time) {
+	thresholds := s.slaThresholds()
+	for i := range stuck {
+		stuck[i].DueBy = stuck[i].StatusSince.Add(thresholds[stuck[i].Status])
+		stuck[i].OverdueHours = math.Round(now.Sub(stuck[i].DueBy).Hours()*10) / 10
+	}
+}
+
+// NotifySLABreaches alerts the sellers and admins of newly stuck orders. Each
+// order is alerted once per status it gets stuck in. It returns the number of
+// orders alerted; anything beyond the batch is picked up on the next check.
+func (s *orderService) NotifySLABreaches(ctx context.Context) (int, error) {
+	now := time.Now()
+	stuck, err := s.orderRepo.GetStuckOrders(ctx, s.slaThresholds(), now, true, slaAlertBatchSize, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get stuck orders: %w", err)
+	}
+	if len(stuck) == 0 {
+		return 0, nil
+	}
+	s.fillSLATimeline(stuck, now)
+
+	adminRole := models.RoleAdmin
+	admins, _, err := s.userRepo.List(ctx, 1, 100, &adminRole)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get admins: %w", err)
+	}
+
+	var notifications []*models.Notification
+	ids := make([]uint, len(stuck))
+	for i := range stuck {
+		ids[i] = stuck[i].Order.ID
+		title := fmt.Sprintf("Order %s is stuck in %s", stuck[i].Order.OrderNumber, stuck[i].Status)
+		message := fmt.Sprintf("Order %s has been %s since %s and is %.1f hours past its expected progress.",
+			stuck[i].Order.OrderNumber, stuck[i].Status, stuck[i].StatusSince.Format(time.RFC1123), stuck[i].OverdueHours)
+		data := slaNotificationData(&stuck[i])
+
+		recipients := stuckOrderSellerIDs(stuck[i].Order)
+		for _, admin := range admins {
+			recipients = append(recipients, admin.ID)
+		}
+		for _, userID := range recipients {
+			notifications = append(notifications, &models.Notification{
+				UserID:  userID,
+				Type:    models.NotificationTypeOrderSLABreach,
+				Title:   title,
+				Message: message,
+				Data:    data,
+			})
+		}
+	}
+
+	if len(notifications) > 0 {
+		if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+			return 0, fmt.Errorf("failed to create SLA notifications: %w", err)
+		}
+	}
+	if err := s.orderRepo.MarkSLAAlerted(ctx, ids, now); err != nil {
+		return 0, fmt.Errorf("failed to mark orders alerted: %w", err)
+	}
+
+	return len(stuck), nil
+}
+
+// StartSLAMonitor periodically alerts on stuck orders until ctx is done. A
+// non-positive interval disables the monitor.
+func (s *orderService) StartSLAMonitor(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				count, err := s.NotifySLABreaches(ctx)
+				if err != nil {
+					fmt.Printf("Warning: order SLA check failed: %v\n", err)
+					continue
+				}
+				if count > 0 {
+					fmt.Printf("Alerted on %d stuck orders\n", count)
+				}
+			}
+		}
+	}()
+}
+
+// stuckOrderSellerIDs returns the sellers responsible for moving a stuck order
+// on. For split orders that's only the sellers whose group is still behind.
+func stuckOrderSellerIDs(order *models.Order) []uint {
+	seen := make(map[uint]bool)
+	var sellerIDs []uint
+	for _, item := range order.OrderItems {
+		if item.SellerID == 0 || seen[item.SellerID] {
+			continue
+		}
+		if fulfillment := order.FulfillmentForSeller(item.SellerID); fulfillment != nil && fulfillment.Status != order.Status {
+			continue
+		}
+		seen[item.SellerID] = true
+		sellerIDs = append(sellerIDs, item.SellerID)
+	}
+	return sellerIDs
+}
+
+func oversellNotificationData(order *models.Order, product *models.Product, stock int) *string {
+	payload, err := json.Marshal(map[string]interface{}{
+		"priority":           "high",
+		"order_id":           order.ID,
+		"product_id":         product.ID,
+		"stock":              stock,
+		"oversell_tolerance": product.OversellTolerance,
+	})
+	if err != nil {
+		return nil
+	}
+	data := string(payload)
+	return &data
+}
+
+func orderNotificationData(order *models.Order) *string {
+	payload, err := json.Marshal(map[string]interface{}{
+		"order_id":     order.ID,
+		"order_number": order.OrderNumber,
+	})
+	if err != nil {
+		return nil
+	}
+	data := string(payload)
+	return &data
+}
+
+func slaNotificationData(stuck *models.StuckOrder) *string {
+	payload, err := json.Marshal(map[string]interface{}{
+		"order_id":      stuck.Order.ID,
+		"status":        stuck.Status,
+		"status_since":  stuck.StatusSince,
+		"due_by":        stuck.DueBy,
+		"overdue_hours": stuck.OverdueHours,
+	})
+	if err != nil {
+		return nil
+	}
+	data := string(payload)
+	return &data
+}
+
+// recordStatusChange appends an entry to the order status history
+func (s *orderService) recordStatusChange(ctx context.Context, orderID uint, from, to models.OrderStatus, changedBy uint, reason *models.CancellationReason, note *string) {
+	history := &models.OrderStatusHistory{
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		Note:       note,
+		ChangedBy:  changedBy,
+	}
+	if err := s.orderRepo.AddStatusHistory(ctx, history); err != nil {
+		// History is informational, don't fail the status change
+		fmt.Printf("Warning: failed to record status history for order %d: %v\n", orderID, err)
+	}
+}
+
+// ComparePeriods compares delivered sales in the range with the immediately
+// preceding range of the same length
+func (s *orderService) ComparePeriods(ctx context.Context, sellerID *uint, startDate, endDate time.Time) (*models.PeriodComparison, error) {
+	current, err := s.orderRepo.GetPeriodSales(ctx, sellerID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get period sales: %w", err)
+	}
+
+	// The previous period ends just before this one starts so no order counts twice
+	length := endDate.Sub(startDate)
+	previousEnd := startDate.Add(-time.Microsecond)
+	previous, err := s.orderRepo.GetPeriodSales(ctx, sellerID, previousEnd.Add(-length), previousEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous period sales: %w", err)
+	}
+
+	return models.NewPeriodComparison(*current, *previous), nil
+}
+
 func (s *orderService) GetOrderAnalytics(ctx context.Context, sellerID *uint, startDate, endDate *time.Time) (*models.OrderAnalytics, error) {
 	var totalRevenue float64
 	var err error
@@ -317,6 +1933,8 @@ func (s *orderService) GetOrderAnalytics(ctx context.Context, sellerID *uint, st
 func isValidStatusTransition(from, to models.OrderStatus) bool {
 	validTransitions := map[models.OrderStatus][]models.OrderStatus{
 		models.OrderStatusPending:   {models.OrderStatusConfirmed, models.OrderStatusCancelled},
+		models.OrderStatusPendingReview: {models.OrderStatusPending, models.OrderStatusCancelled},
+		models.OrderStatusAwaitingConfirmation: {models.OrderStatusConfirmed, models.OrderStatusCancelled},
 		models.OrderStatusConfirmed: {models.OrderStatusProcessing, models.OrderStatusCancelled},
 		models.OrderStatusProcessing: {models.OrderStatusShipped, models.OrderStatusCancelled},
 		models.OrderStatusShipped:   {models.OrderStatusDelivered},
@@ -0,0 +1,699 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
+	"github.com/JonathanVera18/ecommerce-api/pkg/payment"
+)
+
+func reservedStock(orderID, productID uint, quantity int) []models.StockReservation {
+	return []models.StockReservation{{
+		OrderID:   orderID,
+		ProductID: productID,
+		Quantity:  quantity,
+		Status:    models.ReservationStatusReserved,
+	}}
+}
+
+func newSweepTestService(orders *fakeOrderRepo, reservations *fakeReservationRepo, products *fakeProductRepo) *orderService {
+	return &orderService{
+		orderRepo:       orders,
+		productRepo:     products,
+		reservationRepo: reservations,
+		productCache:    newOfflineProductCache(),
+	}
+}
+
+func TestReleaseExpiredReservationsCancelsUnpaidOrder(t *testing.T) {
+	order := &models.Order{BaseModel: models.BaseModel{ID: 1}, Status: models.OrderStatusPending}
+	orders := newFakeOrderRepo(order)
+	reservations := &fakeReservationRepo{
+		expired:      []uint{1},
+		reservations: map[uint][]models.StockReservation{1: reservedStock(1, 7, 2)},
+	}
+	products := newFakeProductRepo()
+
+	expired, err := newSweepTestService(orders, reservations, products).ReleaseExpiredReservations(context.Background())
+	if err != nil {
+		t.Fatalf("ReleaseExpiredReservations: %v", err)
+	}
+
+	if expired != 1 {
+		t.Errorf("expired = %d, want 1", expired)
+	}
+	if got := orders.status(1); got != models.OrderStatusCancelled {
+		t.Errorf("status = %s, want %s", got, models.OrderStatusCancelled)
+	}
+	if products.adjusted[7] != 2 {
+		t.Errorf("restored stock = %d, want 2", products.adjusted[7])
+	}
+}
+
+// Payment committed the reservations after the sweep listed the order
+func TestReleaseExpiredReservationsSkipsOrderPaidBeforeRelease(t *testing.T) {
+	order := &models.Order{BaseModel: models.BaseModel{ID: 1}, Status: models.OrderStatusConfirmed}
+	orders := newFakeOrderRepo(order)
+	committed := reservedStock(1, 7, 2)
+	committed[0].Status = models.ReservationStatusCommitted
+	reservations := &fakeReservationRepo{
+		expired:      []uint{1},
+		reservations: map[uint][]models.StockReservation{1: committed},
+	}
+	products := newFakeProductRepo()
+
+	expired, err := newSweepTestService(orders, reservations, products).ReleaseExpiredReservations(context.Background())
+	if err != nil {
+		t.Fatalf("ReleaseExpiredReservations: %v", err)
+	}
+
+	if expired != 0 {
+		t.Errorf("expired = %d, want 0", expired)
+	}
+	if got := orders.status(1); got != models.OrderStatusConfirmed {
+		t.Errorf("status = %s, want %s", got, models.OrderStatusConfirmed)
+	}
+	if len(products.adjusted) != 0 {
+		t.Errorf("restored stock %v, want none", products.adjusted)
+	}
+	if len(orders.history) != 0 {
+		t.Errorf("recorded %d status changes, want none", len(orders.history))
+	}
+}
+
+// The order was confirmed between the release and the cancel; payment then
+// finds nothing to commit and refunds, so the sweep must leave the order be
+func TestReleaseExpiredReservationsDoesNotCancelConfirmedOrder(t *testing.T) {
+	order := &models.Order{BaseModel: models.BaseModel{ID: 1}, Status: models.OrderStatusConfirmed}
+	orders := newFakeOrderRepo(order)
+	reservations := &fakeReservationRepo{
+		expired:      []uint{1},
+		reservations: map[uint][]models.StockReservation{1: reservedStock(1, 7, 2)},
+	}
+
+	expired, err := newSweepTestService(orders, reservations, newFakeProductRepo()).ReleaseExpiredReservations(context.Background())
+	if err != nil {
+		t.Fatalf("ReleaseExpiredReservations: %v", err)
+	}
+
+	if expired != 0 {
+		t.Errorf("expired = %d, want 0", expired)
+	}
+	if got := orders.status(1); got != models.OrderStatusConfirmed {
+		t.Errorf("status = %s, want %s", got, models.OrderStatusConfirmed)
+	}
+	if len(orders.history) != 0 {
+		t.Errorf("recorded %d status changes, want none", len(orders.history))
+	}
+}
+
+func newPaymentTestService(orders *fakeOrderRepo, reservations *fakeReservationRepo, payments *recordingPayments) *orderService {
+	return &orderService{
+		orderRepo:       orders,
+		productRepo:     newFakeProductRepo(),
+		reservationRepo: reservations,
+		paymentSvc:      payments,
+		productCache:    newOfflineProductCache(),
+		config:          &config.Config{Order: config.OrderConfig{AutoConfirm: true}},
+	}
+}
+
+func pendingOrder(id uint, total float64) *models.Order {
+	return &models.Order{
+		BaseModel:     models.BaseModel{ID: id},
+		Status:        models.OrderStatusPending,
+		PaymentStatus: models.PaymentStatusPending,
+		TotalAmount:   total,
+	}
+}
+
+func paymentRequest(amount float64) *models.PaymentRequest {
+	token := payment.MockTokenSuccess
+	return &models.PaymentRequest{PaymentMethod: models.PaymentMethodCard, Amount: amount, Currency: "usd", PaymentMethodID: &token}
+}
+
+func TestProcessPaymentChargesOrderTotal(t *testing.T) {
+	orders := newFakeOrderRepo(pendingOrder(1, 42.50))
+	reservations := &fakeReservationRepo{reservations: map[uint][]models.StockReservation{1: reservedStock(1, 7, 1)}}
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+
+	resp, err := newPaymentTestService(orders, reservations, payments).ProcessPayment(context.Background(), 1, paymentRequest(0))
+	if err != nil {
+		t.Fatalf("ProcessPayment: %v", err)
+	}
+
+	info, err := payments.GetPayment(resp.TransactionID)
+	if err != nil {
+		t.Fatalf("GetPayment: %v", err)
+	}
+	if info.AmountReceived != 42.50 {
+		t.Errorf("charged %.2f, want the order total 42.50", info.AmountReceived)
+	}
+	if got := orders.status(1); got != models.OrderStatusConfirmed {
+		t.Errorf("status = %s, want %s", got, models.OrderStatusConfirmed)
+	}
+	if len(orders.history) != 1 || orders.history[0].FromStatus != models.OrderStatusPending || orders.history[0].ToStatus != models.OrderStatusConfirmed {
+		t.Errorf("history = %+v, want one pending -> confirmed entry", orders.history)
+	}
+}
+
+func TestProcessPaymentRejectsDifferentAmount(t *testing.T) {
+	orders := newFakeOrderRepo(pendingOrder(1, 42.50))
+	reservations := &fakeReservationRepo{reservations: map[uint][]models.StockReservation{1: reservedStock(1, 7, 1)}}
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+
+	_, err := newPaymentTestService(orders, reservations, payments).ProcessPayment(context.Background(), 1, paymentRequest(0.50))
+	if !errors.Is(err, ErrPaymentAmountMismatch) {
+		t.Fatalf("err = %v, want ErrPaymentAmountMismatch", err)
+	}
+	if got := orders.status(1); got != models.OrderStatusPending {
+		t.Errorf("status = %s, want %s", got, models.OrderStatusPending)
+	}
+}
+
+func TestProcessPaymentRefundsWhenStockCommitFails(t *testing.T) {
+	orders := newFakeOrderRepo(pendingOrder(1, 42.50))
+	reservations := &fakeReservationRepo{
+		reservations: map[uint][]models.StockReservation{1: reservedStock(1, 7, 1)},
+		commitErr:    errors.New("connection reset"),
+	}
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+
+	_, err := newPaymentTestService(orders, reservations, payments).ProcessPayment(context.Background(), 1, paymentRequest(0))
+	if !errors.Is(err, ErrStockNotCommitted) {
+		t.Fatalf("err = %v, want ErrStockNotCommitted", err)
+	}
+
+	if len(payments.refunds) != 1 || payments.refunds[0] != 42.50 {
+		t.Errorf("refunds = %v, want one of 42.50", payments.refunds)
+	}
+	order, _ := orders.GetByID(context.Background(), 1)
+	if order.Status != models.OrderStatusCancelled {
+		t.Errorf("status = %s, want %s", order.Status, models.OrderStatusCancelled)
+	}
+	if order.PaymentStatus != models.PaymentStatusRefunded {
+		t.Errorf("payment status = %s, want %s", order.PaymentStatus, models.PaymentStatusRefunded)
+	}
+}
+
+func guestOrder(id uint, total float64, token string) *models.Order {
+	order := pendingOrder(id, total)
+	tokenHash := utils.HashToken(token)
+	order.GuestTokenHash = &tokenHash
+	return order
+}
+
+func TestProcessGuestPaymentChargesOrderTotalWithToken(t *testing.T) {
+	orders := newFakeOrderRepo(guestOrder(1, 42.50, "guest-token"))
+	reservations := &fakeReservationRepo{reservations: map[uint][]models.StockReservation{1: reservedStock(1, 7, 1)}}
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+	svc := newPaymentTestService(orders, reservations, payments)
+	svc.config.Order.GuestCheckout = true
+
+	resp, err := svc.ProcessGuestPayment(context.Background(), 1, &models.GuestPaymentRequest{
+		GuestToken: "guest-token", PaymentRequest: *paymentRequest(0),
+	})
+	if err != nil {
+		t.Fatalf("ProcessGuestPayment: %v", err)
+	}
+	info, err := payments.GetPayment(resp.TransactionID)
+	if err != nil {
+		t.Fatalf("GetPayment: %v", err)
+	}
+	if info.AmountReceived != 42.50 {
+		t.Errorf("charged %.2f, want the order total 42.50", info.AmountReceived)
+	}
+}
+
+func TestProcessGuestPaymentRejectsWrongToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		order *models.Order
+	}{
+		{"another guest order's token", guestOrder(1, 42.50, "guest-token")},
+		{"account order", pendingOrder(1, 42.50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := newFakeOrderRepo(tt.order)
+			reservations := &fakeReservationRepo{reservations: map[uint][]models.StockReservation{1: reservedStock(1, 7, 1)}}
+			payments := &recordingPayments{MockService: payment.NewMockService()}
+			svc := newPaymentTestService(orders, reservations, payments)
+			svc.config.Order.GuestCheckout = true
+
+			_, err := svc.ProcessGuestPayment(context.Background(), 1, &models.GuestPaymentRequest{
+				GuestToken: "other-token", PaymentRequest: *paymentRequest(0),
+			})
+			if !errors.Is(err, ErrOrderNotFound) {
+				t.Fatalf("err = %v, want ErrOrderNotFound", err)
+			}
+			if got := orders.status(1); got != models.OrderStatusPending {
+				t.Errorf("status = %s, want %s", got, models.OrderStatusPending)
+			}
+		})
+	}
+}
+
+// A payment created outside checkout for less than the order total must not
+// confirm the order, and only what was collected is refunded
+func TestHandlePaymentSucceededRefundsMismatchedAmount(t *testing.T) {
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+	intentID, err := payments.CreatePaymentIntent(paymentRequest(1.00))
+	if err != nil {
+		t.Fatalf("CreatePaymentIntent: %v", err)
+	}
+	if err := payments.ConfirmPayment(intentID); err != nil {
+		t.Fatalf("ConfirmPayment: %v", err)
+	}
+
+	order := pendingOrder(1, 42.50)
+	order.PaymentID = &intentID
+	orders := newFakeOrderRepo(order)
+	reservations := &fakeReservationRepo{reservations: map[uint][]models.StockReservation{1: reservedStock(1, 7, 1)}}
+
+	err = newPaymentTestService(orders, reservations, payments).HandlePaymentSucceeded(context.Background(), intentID)
+	if !errors.Is(err, ErrPaymentNotCaptured) {
+		t.Fatalf("err = %v, want ErrPaymentNotCaptured", err)
+	}
+	if len(payments.refunds) != 1 || payments.refunds[0] != 1.00 {
+		t.Errorf("refunds = %v, want one of 1.00", payments.refunds)
+	}
+	if got := orders.status(1); got != models.OrderStatusCancelled {
+		t.Errorf("status = %s, want %s", got, models.OrderStatusCancelled)
+	}
+}
+
+func TestRefundPaymentRefusesDisputedOrder(t *testing.T) {
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+	order := awaitingConfirmation(t, payments, 42.50)
+
+	for _, status := range []models.PaymentStatus{models.PaymentStatusDisputed, models.PaymentStatusRefunded} {
+		order.PaymentStatus = status
+		if err := refundPayment(payments, order, *order.PaymentID, 10); !errors.Is(err, ErrPaymentNotRefundable) {
+			t.Errorf("%s: err = %v, want ErrPaymentNotRefundable", status, err)
+		}
+	}
+	if len(payments.refunds) != 0 {
+		t.Errorf("refunds = %v, want none", payments.refunds)
+	}
+}
+
+// A chargeback opened before the webhook finalized the order is left to settle
+// the payment, even though the amount collected doesn't match
+func TestHandlePaymentSucceededDoesNotRefundDisputedPayment(t *testing.T) {
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+	intentID, err := payments.CreatePaymentIntent(paymentRequest(1.00))
+	if err != nil {
+		t.Fatalf("CreatePaymentIntent: %v", err)
+	}
+	if err := payments.ConfirmPayment(intentID); err != nil {
+		t.Fatalf("ConfirmPayment: %v", err)
+	}
+
+	order := pendingOrder(1, 42.50)
+	order.PaymentID = &intentID
+	order.PaymentStatus = models.PaymentStatusDisputed
+	orders := newFakeOrderRepo(order)
+	reservations := &fakeReservationRepo{reservations: map[uint][]models.StockReservation{1: reservedStock(1, 7, 1)}}
+
+	err = newPaymentTestService(orders, reservations, payments).HandlePaymentSucceeded(context.Background(), intentID)
+	if !errors.Is(err, ErrPaymentNotCaptured) {
+		t.Fatalf("err = %v, want ErrPaymentNotCaptured", err)
+	}
+	if len(payments.refunds) != 0 {
+		t.Errorf("refunds = %v, want none", payments.refunds)
+	}
+	got, _ := orders.GetByID(context.Background(), 1)
+	if got.PaymentStatus != models.PaymentStatusDisputed {
+		t.Errorf("payment status = %s, want %s", got.PaymentStatus, models.PaymentStatusDisputed)
+	}
+}
+
+// awaitingConfirmation returns an order paid through payments and waiting for
+// a seller to confirm it
+func awaitingConfirmation(t *testing.T, payments *recordingPayments, total float64) *models.Order {
+	t.Helper()
+	intentID, err := payments.CreatePaymentIntent(paymentRequest(total))
+	if err != nil {
+		t.Fatalf("CreatePaymentIntent: %v", err)
+	}
+	if err := payments.ConfirmPayment(intentID); err != nil {
+		t.Fatalf("ConfirmPayment: %v", err)
+	}
+
+	order := pendingOrder(1, total)
+	order.Status = models.OrderStatusAwaitingConfirmation
+	order.PaymentStatus = models.PaymentStatusPaid
+	order.PaymentID = &intentID
+	return order
+}
+
+func rejection() *models.OrderConfirmationRequest {
+	return &models.OrderConfirmationRequest{Approve: false}
+}
+
+func TestRejectOrderConfirmationRefundsAndCancels(t *testing.T) {
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+	orders := newFakeOrderRepo(awaitingConfirmation(t, payments, 42.50))
+
+	svc := newPaymentTestService(orders, &fakeReservationRepo{}, payments)
+	if err := svc.ReviewOrderConfirmation(context.Background(), 1, rejection(), 99, models.RoleAdmin); err != nil {
+		t.Fatalf("ReviewOrderConfirmation: %v", err)
+	}
+
+	if len(payments.refunds) != 1 || payments.refunds[0] != 42.50 {
+		t.Errorf("refunds = %v, want one of 42.50", payments.refunds)
+	}
+	order, _ := orders.GetByID(context.Background(), 1)
+	if order.Status != models.OrderStatusCancelled || order.PaymentStatus != models.PaymentStatusRefunded {
+		t.Errorf("order is %s/%s, want %s/%s", order.Status, order.PaymentStatus, models.OrderStatusCancelled, models.PaymentStatusRefunded)
+	}
+}
+
+// A chargeback is already returning the money
+func TestRejectOrderConfirmationLeavesDisputedPaymentAlone(t *testing.T) {
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+	order := awaitingConfirmation(t, payments, 42.50)
+	order.PaymentStatus = models.PaymentStatusDisputed
+	orders := newFakeOrderRepo(order)
+
+	svc := newPaymentTestService(orders, &fakeReservationRepo{}, payments)
+	err := svc.ReviewOrderConfirmation(context.Background(), 1, rejection(), 99, models.RoleAdmin)
+	if !errors.Is(err, ErrOrderPaymentDisputed) {
+		t.Fatalf("err = %v, want ErrOrderPaymentDisputed", err)
+	}
+
+	if len(payments.refunds) != 0 {
+		t.Errorf("refunds = %v, want none", payments.refunds)
+	}
+	got, _ := orders.GetByID(context.Background(), 1)
+	if got.Status != models.OrderStatusAwaitingConfirmation || got.PaymentStatus != models.PaymentStatusDisputed {
+		t.Errorf("order is %s/%s, want it left %s/%s", got.Status, got.PaymentStatus, models.OrderStatusAwaitingConfirmation, models.PaymentStatusDisputed)
+	}
+}
+
+func TestRejectOrderConfirmationReportsFailedRefund(t *testing.T) {
+	payments := &recordingPayments{MockService: payment.NewMockService()}
+	order := awaitingConfirmation(t, payments, 42.50)
+	unknownIntent := "pi_unknown"
+	order.PaymentID = &unknownIntent
+	orders := newFakeOrderRepo(order)
+
+	svc := newPaymentTestService(orders, &fakeReservationRepo{}, payments)
+	if err := svc.ReviewOrderConfirmation(context.Background(), 1, rejection(), 99, models.RoleAdmin); err == nil {
+		t.Fatal("rejection succeeded although the refund failed")
+	}
+
+	// Still waiting, so the rejection can be tried again
+	got, _ := orders.GetByID(context.Background(), 1)
+	if got.Status != models.OrderStatusAwaitingConfirmation || got.PaymentStatus != models.PaymentStatusPaid {
+		t.Errorf("order is %s/%s, want it left %s/%s", got.Status, got.PaymentStatus, models.OrderStatusAwaitingConfirmation, models.PaymentStatusPaid)
+	}
+}
+
+func TestPreviewOrderPricesWithoutSavingOrHolding(t *testing.T) {
+	product := &models.Product{BaseModel: models.BaseModel{ID: 7}, Name: "Lamp", Price: 12.50, Stock: 5, SellerID: 3, IsActive: true}
+	cfg := &config.Config{}
+	users := newFakeUserRepo(&models.User{BaseModel: models.BaseModel{ID: 3}, IsActive: true})
+	svc := &orderService{
+		// The order and reservation stores would panic if anything were saved or held
+		orderRepo:   newFakeOrderRepo(),
+		productRepo: newFakeProductRepo(product),
+		userRepo:    users,
+		shippingSvc: flatShipping{cost: 4.99},
+		promotions:  NewPromotionEngine(noPromotions{}, nil),
+		minimums:    NewMinimumOrderPolicy(users, cfg),
+		config:      cfg,
+	}
+
+	preview, err := svc.PreviewOrder(context.Background(), &models.CreateOrderRequest{
+		Items:           []models.OrderItemRequest{{ProductID: 7, Quantity: 3}},
+		ShippingAddress: "1 Main St",
+		PaymentMethod:   models.PaymentMethodCard,
+	}, 5)
+	if err != nil {
+		t.Fatalf("PreviewOrder: %v", err)
+	}
+
+	want := models.OrderTotals{ItemCount: 3, Subtotal: 37.50, ShippingAmount: 4.99, Total: 42.49}
+	if preview.Totals != want {
+		t.Errorf("totals = %+v, want %+v", preview.Totals, want)
+	}
+	if len(preview.Lines) != 1 || preview.Lines[0].Total != 37.50 {
+		t.Errorf("lines = %+v, want one line totalling 37.50", preview.Lines)
+	}
+}
+
+func TestPreviewOrderRejectsWhatCheckoutWould(t *testing.T) {
+	product := &models.Product{BaseModel: models.BaseModel{ID: 7}, Name: "Lamp", Price: 12.50, Stock: 2, SellerID: 3, IsActive: true}
+	svc := &orderService{
+		productRepo: newFakeProductRepo(product),
+		userRepo:    newFakeUserRepo(&models.User{BaseModel: models.BaseModel{ID: 3}, IsActive: true}),
+		config:      &config.Config{},
+	}
+
+	_, err := svc.PreviewOrder(context.Background(), &models.CreateOrderRequest{
+		Items:           []models.OrderItemRequest{{ProductID: 7, Quantity: 3}},
+		ShippingAddress: "1 Main St",
+		PaymentMethod:   models.PaymentMethodCard,
+	}, 5)
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("expected ErrInsufficientStock, got %v", err)
+	}
+}
+
+func TestExtendReservationHoldsStockUpToTheLimit(t *testing.T) {
+	ctx := context.Background()
+	order := pendingOrder(1, 20)
+	order.CustomerID = 5
+	order.CreatedAt = time.Now().Add(-20 * time.Minute)
+	held := reservedStock(1, 7, 1)
+	held[0].ExpiresAt = time.Now().Add(10 * time.Minute)
+	reservations := &fakeReservationRepo{reservations: map[uint][]models.StockReservation{1: held}}
+	svc := newPaymentTestService(newFakeOrderRepo(order), reservations, &recordingPayments{})
+	svc.config.Order.StockReservationTTL = 30 * time.Minute
+	svc.config.Order.StockReservationMax = 45 * time.Minute
+
+	if _, err := svc.ExtendReservation(ctx, 1, 6); !errors.Is(err, ErrOrderViewForbidden) {
+		t.Errorf("another customer: err = %v, want ErrOrderViewForbidden", err)
+	}
+
+	// A fresh 30 minutes would pass the 45 minute limit, so the hold stops there
+	hold, err := svc.ExtendReservation(ctx, 1, 5)
+	if err != nil {
+		t.Fatalf("ExtendReservation: %v", err)
+	}
+	if limit := order.CreatedAt.Add(45 * time.Minute); !hold.ExpiresAt.Equal(limit) {
+		t.Errorf("expires at %v, want the limit %v", hold.ExpiresAt, limit)
+	}
+	if got := reservations.reservations[1][0].ExpiresAt; !got.Equal(hold.ExpiresAt) {
+		t.Errorf("reservation expires at %v, want %v", got, hold.ExpiresAt)
+	}
+
+	order.CreatedAt = time.Now().Add(-time.Hour)
+	if _, err := svc.ExtendReservation(ctx, 1, 5); !errors.Is(err, ErrReservationHoldLimit) {
+		t.Errorf("past the limit: err = %v, want ErrReservationHoldLimit", err)
+	}
+}
+
+func TestExtendReservationDoesNotReviveLapsedHold(t *testing.T) {
+	order := pendingOrder(1, 20)
+	order.CustomerID = 5
+	order.CreatedAt = time.Now().Add(-31 * time.Minute)
+	held := reservedStock(1, 7, 1)
+	held[0].ExpiresAt = time.Now().Add(-time.Minute)
+	reservations := &fakeReservationRepo{reservations: map[uint][]models.StockReservation{1: held}}
+	svc := newPaymentTestService(newFakeOrderRepo(order), reservations, &recordingPayments{})
+	svc.config.Order.StockReservationTTL = 30 * time.Minute
+	svc.config.Order.StockReservationMax = time.Hour
+
+	if _, err := svc.ExtendReservation(context.Background(), 1, 5); !errors.Is(err, ErrReservationReleased) {
+		t.Errorf("err = %v, want ErrReservationReleased", err)
+	}
+	if got := reservations.reservations[1][0].ExpiresAt; got.After(time.Now()) {
+		t.Errorf("lapsed reservation extended to %v", got)
+	}
+}
+
+// inconsistentOrder is a pending order for two 10.00 units whose stored line
+// and order totals were left at one unit and with no shipping
+func inconsistentOrder() *models.Order {
+	return &models.Order{
+		BaseModel:      models.BaseModel{ID: 1},
+		Status:         models.OrderStatusPending,
+		PaymentStatus:  models.PaymentStatusPending,
+		ItemCount:      1,
+		SubtotalAmount: 10,
+		DiscountAmount: 2,
+		TotalAmount:    8,
+		OrderItems:     []models.OrderItem{{BaseModel: models.BaseModel{ID: 3}, Quantity: 2, UnitPrice: 10, TotalPrice: 10}},
+	}
+}
+
+func TestRecalculateOrderRepairsAndAuditsTotals(t *testing.T) {
+	orders := newFakeOrderRepo(inconsistentOrder())
+	svc := &orderService{orderRepo: orders, shippingSvc: flatShipping{cost: 5}}
+
+	result, err := svc.RecalculateOrder(context.Background(), 1, 9, &models.OrderRecalculateRequest{})
+	if err != nil {
+		t.Fatalf("RecalculateOrder: %v", err)
+	}
+
+	// 20.00 of items, less the 2.00 discount, plus 5.00 shipping
+	want := models.OrderTotals{ItemCount: 2, Subtotal: 20, DiscountAmount: 2, ShippingAmount: 5, Total: 23}
+	if !result.Changed || result.After != want {
+		t.Errorf("after = %+v, want %+v", result.After, want)
+	}
+	if result.Before.Total != 8 || result.Before.ItemCount != 1 {
+		t.Errorf("before = %+v, want the stored totals", result.Before)
+	}
+	if saved := orders.orders[1]; saved.TotalAmount != 23 || saved.OrderItems[0].TotalPrice != 20 {
+		t.Errorf("saved total = %v, line total = %v; want 23 and 20", saved.TotalAmount, saved.OrderItems[0].TotalPrice)
+	}
+	if len(orders.audit) != 1 || orders.audit[0].ActorID != 9 || orders.audit[0].EntityID != 1 {
+		t.Fatalf("audit = %+v, want one entry by admin 9 for order 1", orders.audit)
+	}
+}
+
+func TestRecalculateChargedOrderNeedsForce(t *testing.T) {
+	order := inconsistentOrder()
+	order.PaymentStatus = models.PaymentStatusPaid
+	orders := newFakeOrderRepo(order)
+	svc := &orderService{orderRepo: orders, shippingSvc: flatShipping{cost: 5}}
+	ctx := context.Background()
+
+	if _, err := svc.RecalculateOrder(ctx, 1, 9, &models.OrderRecalculateRequest{}); !errors.Is(err, ErrRecalculateNeedsForce) {
+		t.Fatalf("err = %v, want ErrRecalculateNeedsForce", err)
+	}
+	if len(orders.audit) != 0 || orders.orders[1].TotalAmount != 8 {
+		t.Fatal("expected the charged order to be left as it was")
+	}
+
+	if _, err := svc.RecalculateOrder(ctx, 1, 9, &models.OrderRecalculateRequest{Force: true}); err != nil {
+		t.Fatalf("forced: %v", err)
+	}
+	if len(orders.audit) != 1 || !strings.Contains(*orders.audit[0].Details, "forced") {
+		t.Errorf("audit = %+v, want the forced recalculation recorded", orders.audit)
+	}
+}
+
+// shippedToUS is an order of 20.00 shipping to the US for 5.00
+func shippedToUS(paymentStatus models.PaymentStatus) *models.Order {
+	return &models.Order{
+		BaseModel:       models.BaseModel{ID: 1},
+		CustomerID:      7,
+		Status:          models.OrderStatusConfirmed,
+		PaymentStatus:   paymentStatus,
+		SubtotalAmount:  20,
+		ShippingAmount:  5,
+		TotalAmount:     25,
+		ShippingCountry: "US",
+		OrderItems:      []models.OrderItem{{Quantity: 2, UnitPrice: 10, TotalPrice: 20}},
+	}
+}
+
+func canadianAddress() *models.UpdateShippingAddressRequest {
+	return &models.UpdateShippingAddressRequest{FirstName: "Ada", LastName: "Lovelace", Street: "1 Rue Principale", City: "Montreal", State: "QC", Country: "CA", PostalCode: "H2X 1Y4"}
+}
+
+func TestUpdateShippingAddressRequotesUnpaidOrder(t *testing.T) {
+	orders := newFakeOrderRepo(shippedToUS(models.PaymentStatusPending))
+	svc := &orderService{orderRepo: orders, shippingSvc: countryShipping{costs: map[string]float64{"US": 5, "CA": 15}}}
+
+	order, err := svc.UpdateShippingAddress(context.Background(), 1, canadianAddress(), 7)
+	if err != nil {
+		t.Fatalf("UpdateShippingAddress: %v", err)
+	}
+	if order.ShippingCountry != "CA" || order.ShippingAmount != 15 || order.TotalAmount != 35 {
+		t.Errorf("order ships to %s for %v, total %v; want CA for 15, total 35", order.ShippingCountry, order.ShippingAmount, order.TotalAmount)
+	}
+	if saved := orders.orders[1]; saved.TotalAmount != 35 {
+		t.Errorf("saved total = %v, want 35", saved.TotalAmount)
+	}
+}
+
+func TestUpdateShippingAddressRefusesCostlierAddressForChargedOrder(t *testing.T) {
+	orders := newFakeOrderRepo(shippedToUS(models.PaymentStatusPaid))
+	svc := &orderService{orderRepo: orders, shippingSvc: countryShipping{costs: map[string]float64{"US": 5, "CA": 15}}}
+
+	if _, err := svc.UpdateShippingAddress(context.Background(), 1, canadianAddress(), 7); !errors.Is(err, ErrShippingCostIncrease) {
+		t.Fatalf("err = %v, want ErrShippingCostIncrease", err)
+	}
+	if saved := orders.orders[1]; saved.ShippingCountry != "US" || saved.TotalAmount != 25 {
+		t.Errorf("saved order ships to %s, total %v; want it left as paid", saved.ShippingCountry, saved.TotalAmount)
+	}
+
+	// Shipping there for no more than was paid keeps the paid amounts
+	svc.shippingSvc = countryShipping{costs: map[string]float64{"US": 5, "CA": 3}}
+	order, err := svc.UpdateShippingAddress(context.Background(), 1, canadianAddress(), 7)
+	if err != nil {
+		t.Fatalf("cheaper address: %v", err)
+	}
+	if order.ShippingCountry != "CA" || order.ShippingAmount != 5 || order.TotalAmount != 25 {
+		t.Errorf("order ships to %s for %v, total %v; want CA with the paid 5 and 25", order.ShippingCountry, order.ShippingAmount, order.TotalAmount)
+	}
+}
+
+func TestTrackOrderMatchesEmailAndHidesNotes(t *testing.T) {
+	note := "Left at the back door"
+	tracking := "1Z999"
+	order := pendingOrder(1, 42.50)
+	order.OrderNumber = "ORD-20261016-001"
+	order.Status = models.OrderStatusShipped
+	order.Customer = models.User{Email: "shopper@example.com"}
+	order.ShippingEmail = "gift@example.com"
+	order.TrackingNumber = &tracking
+	order.StatusHistory = []models.OrderStatusHistory{
+		{FromStatus: models.OrderStatusPending, ToStatus: models.OrderStatusConfirmed},
+		{FromStatus: models.OrderStatusConfirmed, ToStatus: models.OrderStatusConfirmed, Note: &note},
+		{FromStatus: models.OrderStatusConfirmed, ToStatus: models.OrderStatusShipped, Note: &note},
+	}
+	svc := &orderService{orderRepo: newFakeOrderRepo(order)}
+
+	for _, email := range []string{"Shopper@Example.com", " gift@example.com"} {
+		status, err := svc.TrackOrder(context.Background(), &models.OrderTrackRequest{OrderNumber: order.OrderNumber, Email: email})
+		if err != nil {
+			t.Fatalf("TrackOrder(%q): %v", email, err)
+		}
+		if len(status.Timeline) != 2 || status.Timeline[1].Status != models.OrderStatusShipped {
+			t.Errorf("timeline = %+v, want confirmed then shipped", status.Timeline)
+		}
+		if len(status.Shipments) != 1 || status.Shipments[0].TrackingNumber != tracking {
+			t.Errorf("shipments = %+v, want %s", status.Shipments, tracking)
+		}
+	}
+
+	for _, req := range []models.OrderTrackRequest{
+		{OrderNumber: order.OrderNumber, Email: "someone@example.com"},
+		{OrderNumber: "ORD-20261016-999", Email: "shopper@example.com"},
+	} {
+		if _, err := svc.TrackOrder(context.Background(), &req); !errors.Is(err, ErrOrderNotFound) {
+			t.Errorf("TrackOrder(%+v) err = %v, want ErrOrderNotFound", req, err)
+		}
+	}
+}
+
+func TestEachOrderExportLineAllocatesPerOrder(t *testing.T) {
+	first := models.OrderExportLine{OrderID: 1, OrderSubtotal: 20, OrderShipping: 5, LineSubtotal: 10, Quantity: 1}
+	second := models.OrderExportLine{OrderID: 2, OrderSubtotal: 8, OrderShipping: 3, LineSubtotal: 8, Quantity: 1}
+	svc := &orderService{orderRepo: &exportOrderRepo{lines: []models.OrderExportLine{first, first, second}}}
+
+	var shipping []float64
+	err := svc.EachOrderExportLine(context.Background(), time.Now(), time.Now(), func(line *models.OrderExportLine) error {
+		shipping = append(shipping, line.ShippingShare)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachOrderExportLine: %v", err)
+	}
+	if len(shipping) != 3 || shipping[0] != 2.5 || shipping[1] != 2.5 || shipping[2] != 3 {
+		t.Errorf("shipping shares = %v, want [2.5 2.5 3]", shipping)
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	productCachePrefix     = "products:detail:"
+	productSlugCachePrefix = "products:slug:"
+	productListCachePrefix = "products:list:"
+	// productListVersionKey is bumped on every product change; listing keys
+	// embed it so a bump orphans every cached listing at once
+	productListVersionKey = "products:list-version"
+
+	productCacheTTL     = 5 * time.Minute
+	productListCacheTTL = 2 * time.Minute
+
+	// Cache names reported in the hit/miss stats
+	productCacheDetail      = "product"
+	productCacheSlug        = "product_slug"
+	productCacheNewArrivals = "new_arrivals"
+	productCacheFeatured    = "featured"
+	productCacheTopRated    = "top_rated"
+	productCacheRelated     = "related"
+)
+
+// ProductCache is a cache-aside layer in Redis for product reads. It is shared
+// by every service that changes products so each change can invalidate it.
+// A Redis failure falls through to the database rather than failing the read.
+type ProductCache struct {
+	redis *redis.Client
+
+	mu    sync.Mutex
+	stats map[string]*models.CacheStats
+}
+
+func NewProductCache(redisClient *redis.Client) *ProductCache {
+	return &ProductCache{
+		redis: redisClient,
+		stats: make(map[string]*models.CacheStats),
+	}
+}
+
+// getOrLoad unmarshals the cached value at key into dest. On a miss it calls
+// load, which must fill dest, and caches the result for ttl.
+func (c *ProductCache) getOrLoad(ctx context.Context, name, key string, ttl time.Duration, dest interface{}, load func() error) error {
+	if cached, err := c.redis.Get(ctx, key).Bytes(); err == nil && json.Unmarshal(cached, dest) == nil {
+		c.record(name, true)
+		return nil
+	}
+	c.record(name, false)
+
+	if err := load(); err != nil {
+		return err
+	}
+
+	if data, err := json.Marshal(dest); err == nil {
+		if err := c.redis.Set(ctx, key, data, ttl).Err(); err != nil {
+			fmt.Printf("Warning: failed to cache %s: %v\n", name, err)
+		}
+	}
+	return nil
+}
+
+// slugProductID returns the product ID cached for a slug, if any
+func (c *ProductCache) slugProductID(ctx context.Context, slug string) (uint, bool) {
+	id, err := c.redis.Get(ctx, productSlugCachePrefix+slug).Uint64()
+	if err != nil {
+		c.record(productCacheSlug, false)
+		return 0, false
+	}
+	c.record(productCacheSlug, true)
+	return uint(id), true
+}
+
+// setSlug remembers which product a slug belongs to
+func (c *ProductCache) setSlug(ctx context.Context, slug string, productID uint) {
+	if err := c.redis.Set(ctx, productSlugCachePrefix+slug, productID, productCacheTTL).Err(); err != nil {
+		fmt.Printf("Warning: failed to cache product slug %s: %v\n", slug, err)
+	}
+}
+
+// listKey builds a listing key under the current listing version
+func (c *ProductCache) listKey(ctx context.Context, name string, params ...interface{}) string {
+	version, err := c.redis.Get(ctx, productListVersionKey).Int64()
+	if err != nil && err != redis.Nil {
+		fmt.Printf("Warning: failed to get product listing cache version: %v\n", err)
+	}
+
+	key := fmt.Sprintf("%s%s:v%d", productListCachePrefix, name, version)
+	for _, param := range params {
+		key += fmt.Sprintf(":%v", param)
+	}
+	return key
+}
+
+// Invalidate drops the cached details of the given products and every cached
+// listing. Slug entries are left alone: reads check the slug on the product
+// they resolve to, so a renamed product's old slug simply misses.
+func (c *ProductCache) Invalidate(ctx context.Context, productIDs ...uint) {
+	if len(productIDs) > 0 {
+		keys := make([]string, len(productIDs))
+		for i, id := range productIDs {
+			keys[i] = fmt.Sprintf("%s%d", productCachePrefix, id)
+		}
+		if err := c.redis.Del(ctx, keys...).Err(); err != nil {
+			fmt.Printf("Warning: failed to invalidate product cache for %v: %v\n", productIDs, err)
+		}
+	}
+
+	if err := c.redis.Incr(ctx, productListVersionKey).Err(); err != nil {
+		fmt.Printf("Warning: failed to invalidate product listing cache: %v\n", err)
+	}
+}
+
+func (c *ProductCache) record(name string, hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.stats[name]
+	if !ok {
+		stats = &models.CacheStats{Name: name}
+		c.stats[name] = stats
+	}
+	if hit {
+		stats.Hits++
+	} else {
+		stats.Misses++
+	}
+}
+
+// Stats returns the hit and miss counts of each cache since startup, by name
+func (c *ProductCache) Stats() []models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make([]models.CacheStats, 0, len(c.stats))
+	for _, s := range c.stats {
+		entry := *s
+		if total := entry.Hits + entry.Misses; total > 0 {
+			entry.HitRate = float64(entry.Hits) / float64(total)
+		}
+		stats = append(stats, entry)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
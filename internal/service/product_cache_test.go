@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+// loadProduct reads product 1 through the cache, counting database loads
+func loadProduct(t *testing.T, cache *ProductCache, name string, loads *int) string {
+	t.Helper()
+	var product models.Product
+	err := cache.getOrLoad(context.Background(), productCacheDetail, productCachePrefix+"1", productCacheTTL, &product, func() error {
+		*loads++
+		product = models.Product{BaseModel: models.BaseModel{ID: 1}, Name: name}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("getOrLoad: %v", err)
+	}
+	return product.Name
+}
+
+func cacheStats(cache *ProductCache, name string) models.CacheStats {
+	for _, stats := range cache.Stats() {
+		if stats.Name == name {
+			return stats
+		}
+	}
+	return models.CacheStats{Name: name}
+}
+
+func TestProductCacheHitAndMiss(t *testing.T) {
+	cache := NewProductCache(openTestRedis(t))
+	loads := 0
+
+	loadProduct(t, cache, "Lamp", &loads)
+	if got := loadProduct(t, cache, "Changed in the database", &loads); got != "Lamp" {
+		t.Errorf("second read = %q, want the cached %q", got, "Lamp")
+	}
+	if loads != 1 {
+		t.Errorf("loaded %d times, want 1", loads)
+	}
+
+	stats := cacheStats(cache, productCacheDetail)
+	if stats.Hits != 1 || stats.Misses != 1 || stats.HitRate != 0.5 {
+		t.Errorf("stats = %+v, want 1 hit, 1 miss", stats)
+	}
+}
+
+func TestProductCacheInvalidateDropsDetailsAndListings(t *testing.T) {
+	cache := NewProductCache(openTestRedis(t))
+	ctx := context.Background()
+	loads := 0
+
+	loadProduct(t, cache, "Lamp", &loads)
+	listKey := cache.listKey(ctx, productCacheFeatured, 10)
+	var listed []models.Product
+	cache.getOrLoad(ctx, productCacheFeatured, listKey, productListCacheTTL, &listed, func() error {
+		listed = []models.Product{{Name: "Lamp"}}
+		return nil
+	})
+
+	cache.Invalidate(ctx, 1)
+
+	if got := loadProduct(t, cache, "Desk lamp", &loads); got != "Desk lamp" {
+		t.Errorf("read after invalidation = %q, want %q", got, "Desk lamp")
+	}
+	if cache.listKey(ctx, productCacheFeatured, 10) == listKey {
+		t.Error("expected invalidation to move listings to a new key")
+	}
+}
+
+func TestProductCacheFallsThroughWhenRedisIsDown(t *testing.T) {
+	cache := newOfflineProductCache()
+	loads := 0
+
+	loadProduct(t, cache, "Lamp", &loads)
+	loadProduct(t, cache, "Lamp", &loads)
+	if loads != 2 {
+		t.Errorf("loaded %d times, want every read to reach the database", loads)
+	}
+	cache.Invalidate(context.Background(), 1)
+
+	var product models.Product
+	loadErr := errors.New("database down")
+	err := cache.getOrLoad(context.Background(), productCacheDetail, productCachePrefix+"2", productCacheTTL, &product, func() error {
+		return loadErr
+	})
+	if !errors.Is(err, loadErr) {
+		t.Errorf("err = %v, want the load error", err)
+	}
+	if stats := cacheStats(cache, productCacheDetail); stats.Misses != 3 || stats.Hits != 0 {
+		t.Errorf("stats = %+v, want 3 misses", stats)
+	}
+}
@@ -4,7 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/repository"
 )
@@ -12,26 +20,113 @@ import (
 type productImageService struct {
 	productImageRepo repository.ProductImageRepository
 	productRepo      repository.ProductRepository
+	config           *config.Config
+	httpClient       *http.Client
 }
 
 func NewProductImageService(
 	productImageRepo repository.ProductImageRepository,
 	productRepo repository.ProductRepository,
+	cfg *config.Config,
 ) ProductImageService {
 	return &productImageService{
 		productImageRepo: productImageRepo,
 		productRepo:      productRepo,
+		config:           cfg,
+		httpClient:       newImageCheckClient(cfg.Upload.ImageURLCheckTimeout),
+	}
+}
+
+// maxImageRedirects is how many redirects an image URL check follows
+const maxImageRedirects = 5
+
+// newImageCheckClient returns the client that checks seller-supplied image
+// URLs. It only connects to public addresses, checked on the resolved IP of
+// every connection so redirects and DNS answers can't point it inside our
+// network, and it ignores proxy settings so that check sees the real target.
+func newImageCheckClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !isPublicAddr(addrPort.Addr()) {
+				return errImageHostNotAllowed
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxImageRedirects || !isWebURL(req.URL) {
+				return errImageHostNotAllowed
+			}
+			return nil
+		},
 	}
 }
 
+// errImageHostNotAllowed stops an image URL check from reaching a host it
+// mustn't; the caller only ever sees ErrInvalidImageURL
+var errImageHostNotAllowed = errors.New("image host not allowed")
+
+// reservedPrefixes are non-public ranges netip doesn't already classify
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // Documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // Documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // Documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved, and broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, which can reach IPv4 inside
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation
+}
+
+// isPublicAddr reports whether an address is a public unicast one, not
+// loopback, private, link-local (cloud metadata), multicast or reserved
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWebURL reports whether the URL is an absolute http or https one
+func isWebURL(u *url.URL) bool {
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Hostname() != ""
+}
+
 func (s *productImageService) AddProductImage(ctx context.Context, productID uint, imageReq *models.ProductImageRequest) (*models.ProductImage, error) {
 	// Verify product exists
-	_, err := s.productRepo.GetByID(ctx, productID)
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	count, err := s.checkImageLimit(ctx, productID, 1)
 	if err != nil {
-		return nil, errors.New("product not found")
+		return nil, err
 	}
 
-	// Create product image
+	if err := s.verifyImageURL(ctx, imageReq.URL); err != nil {
+		return nil, err
+	}
+
+	// Create product image; the repository swaps out any existing primary atomically
 	productImage := &models.ProductImage{
 		ProductID: productID,
 		URL:       imageReq.URL,
@@ -39,13 +134,7 @@ func (s *productImageService) AddProductImage(ctx context.Context, productID uin
 		SortOrder: imageReq.SortOrder,
 		IsPrimary: imageReq.IsPrimary,
 	}
-
-	// If this is set as primary, ensure no other image is primary
-	if imageReq.IsPrimary {
-		if err := s.productImageRepo.SetPrimary(ctx, productID, 0); err != nil {
-			return nil, err
-		}
-	}
+	fillAltText(productImage, product.Name, count+1)
 
 	if err := s.productImageRepo.Create(ctx, productImage); err != nil {
 		return nil, err
@@ -58,7 +147,7 @@ func (s *productImageService) GetProductImages(ctx context.Context, productID ui
 	// Verify product exists
 	_, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
-		return nil, errors.New("product not found")
+		return nil, ErrProductNotFound
 	}
 
 	return s.productImageRepo.GetByProductID(ctx, productID)
@@ -78,18 +167,18 @@ func (s *productImageService) UpdateProductImage(ctx context.Context, imageID ui
 	// Update fields
 	existingImage.URL = imageReq.URL
 	existingImage.AltText = imageReq.AltText
+	existingImage.AltTextGenerated = false
 	existingImage.SortOrder = imageReq.SortOrder
 
-	// Handle primary image logic
-	if imageReq.IsPrimary && !existingImage.IsPrimary {
-		if err := s.productImageRepo.SetPrimary(ctx, existingImage.ProductID, imageID); err != nil {
+	if strings.TrimSpace(existingImage.AltText) == "" {
+		if err := s.fillExistingAltText(ctx, existingImage); err != nil {
 			return nil, err
 		}
-		existingImage.IsPrimary = true
-	} else if !imageReq.IsPrimary && existingImage.IsPrimary {
-		existingImage.IsPrimary = false
 	}
 
+	// Handle primary image logic; the repository clears the previous primary in the same transaction
+	existingImage.IsPrimary = imageReq.IsPrimary
+
 	if err := s.productImageRepo.Update(ctx, existingImage); err != nil {
 		return nil, err
 	}
@@ -97,31 +186,46 @@ func (s *productImageService) UpdateProductImage(ctx context.Context, imageID ui
 	return existingImage, nil
 }
 
-func (s *productImageService) DeleteProductImage(ctx context.Context, imageID uint) error {
+// DeleteProductImage deletes an image and returns the product's primary image
+// afterwards (another image is promoted if the primary was deleted), or nil
+// if the product has no images left
+func (s *productImageService) DeleteProductImage(ctx context.Context, imageID uint) (*models.ProductImage, error) {
 	// Get existing image to verify it exists
-	_, err := s.productImageRepo.GetByID(ctx, imageID)
+	image, err := s.productImageRepo.GetByID(ctx, imageID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := s.productImageRepo.Delete(ctx, imageID); err != nil {
+		return nil, err
 	}
 
-	return s.productImageRepo.Delete(ctx, imageID)
+	primary, err := s.productImageRepo.GetPrimaryImage(ctx, image.ProductID)
+	if err != nil {
+		if errors.Is(err, ErrPrimaryImageNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return primary, nil
 }
 
 func (s *productImageService) SetPrimaryImage(ctx context.Context, productID uint, imageID uint) error {
 	// Verify product exists
 	_, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
-		return errors.New("product not found")
+		return ErrProductNotFound
 	}
 
 	// Verify image exists and belongs to product
 	image, err := s.productImageRepo.GetByID(ctx, imageID)
 	if err != nil {
-		return errors.New("image not found")
+		return ErrImageNotFound
 	}
 
 	if image.ProductID != productID {
-		return errors.New("image does not belong to the specified product")
+		return ErrImageNotOnProduct
 	}
 
 	return s.productImageRepo.SetPrimary(ctx, productID, imageID)
@@ -131,7 +235,7 @@ func (s *productImageService) GetPrimaryImage(ctx context.Context, productID uin
 	// Verify product exists
 	_, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
-		return nil, errors.New("product not found")
+		return nil, ErrProductNotFound
 	}
 
 	return s.productImageRepo.GetPrimaryImage(ctx, productID)
@@ -141,17 +245,17 @@ func (s *productImageService) UpdateImageOrder(ctx context.Context, productID ui
 	// Verify product exists
 	_, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
-		return errors.New("product not found")
+		return ErrProductNotFound
 	}
 
 	// Verify image exists and belongs to product
 	image, err := s.productImageRepo.GetByID(ctx, imageID)
 	if err != nil {
-		return errors.New("image not found")
+		return ErrImageNotFound
 	}
 
 	if image.ProductID != productID {
-		return errors.New("image does not belong to the specified product")
+		return ErrImageNotOnProduct
 	}
 
 	return s.productImageRepo.UpdateSortOrder(ctx, productID, imageID, sortOrder)
@@ -159,11 +263,31 @@ func (s *productImageService) UpdateImageOrder(ctx context.Context, productID ui
 
 func (s *productImageService) BulkAddImages(ctx context.Context, productID uint, imageReqs []models.ProductImageRequest) ([]models.ProductImage, error) {
 	// Verify product exists
-	_, err := s.productRepo.GetByID(ctx, productID)
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	if len(imageReqs) == 0 {
+		return []models.ProductImage{}, nil
+	}
+
+	count, err := s.checkImageLimit(ctx, productID, len(imageReqs))
 	if err != nil {
-		return nil, errors.New("product not found")
+		return nil, err
+	}
+
+	if err := s.verifyImageURLs(ctx, imageReqs); err != nil {
+		return nil, err
 	}
 
+	return s.createImages(ctx, product, imageReqs, count)
+}
+
+// createImages adds the images after the product's existing ones, of which
+// there are existing
+func (s *productImageService) createImages(ctx context.Context, product *models.Product, imageReqs []models.ProductImageRequest, existing int) ([]models.ProductImage, error) {
+	productID := product.ID
 	if len(imageReqs) == 0 {
 		return []models.ProductImage{}, nil
 	}
@@ -172,7 +296,7 @@ func (s *productImageService) BulkAddImages(ctx context.Context, productID uint,
 	var images []models.ProductImage
 	hasPrimary := false
 	
-	for _, req := range imageReqs {
+	for i, req := range imageReqs {
 		image := models.ProductImage{
 			ProductID: productID,
 			URL:       req.URL,
@@ -180,6 +304,7 @@ func (s *productImageService) BulkAddImages(ctx context.Context, productID uint,
 			SortOrder: req.SortOrder,
 			IsPrimary: req.IsPrimary,
 		}
+		fillAltText(&image, product.Name, existing+i+1)
 
 		// Ensure only one primary image
 		if req.IsPrimary {
@@ -193,13 +318,6 @@ func (s *productImageService) BulkAddImages(ctx context.Context, productID uint,
 		images = append(images, image)
 	}
 
-	// If a primary image is being added, clear existing primary
-	if hasPrimary {
-		if err := s.productImageRepo.SetPrimary(ctx, productID, 0); err != nil {
-			return nil, err
-		}
-	}
-
 	if err := s.productImageRepo.BulkCreate(ctx, images); err != nil {
 		return nil, err
 	}
@@ -209,9 +327,19 @@ func (s *productImageService) BulkAddImages(ctx context.Context, productID uint,
 
 func (s *productImageService) ReplaceProductImages(ctx context.Context, productID uint, imageReqs []models.ProductImageRequest) ([]models.ProductImage, error) {
 	// Verify product exists
-	_, err := s.productRepo.GetByID(ctx, productID)
+	product, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
-		return nil, errors.New("product not found")
+		return nil, ErrProductNotFound
+	}
+
+	// The new set replaces the old one, so only its own size counts
+	if len(imageReqs) > s.config.Upload.MaxImagesPerProduct {
+		return nil, ErrImageLimitReached
+	}
+
+	// Check the new URLs before the existing images are gone
+	if err := s.verifyImageURLs(ctx, imageReqs); err != nil {
+		return nil, err
 	}
 
 	// Delete existing images
@@ -220,5 +348,138 @@ func (s *productImageService) ReplaceProductImages(ctx context.Context, productI
 	}
 
 	// Add new images
-	return s.BulkAddImages(ctx, productID, imageReqs)
+	return s.createImages(ctx, product, imageReqs, 0)
+}
+
+// checkImageLimit rejects adding more images than a product may have and
+// returns how many it has now
+func (s *productImageService) checkImageLimit(ctx context.Context, productID uint, adding int) (int, error) {
+	count, err := s.productImageRepo.CountByProductID(ctx, productID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count product images: %w", err)
+	}
+
+	if int(count)+adding > s.config.Upload.MaxImagesPerProduct {
+		return 0, ErrImageLimitReached
+	}
+	return int(count), nil
+}
+
+// fillAltText gives an image added without alt text a default built from the
+// product name and the image's position, and marks it as generated so the
+// seller can be told to replace it
+func fillAltText(image *models.ProductImage, productName string, position int) {
+	if strings.TrimSpace(image.AltText) != "" {
+		return
+	}
+	image.AltText = models.DefaultAltText(productName, position)
+	image.AltTextGenerated = true
+}
+
+// fillExistingAltText fills in alt text for an image already on its product,
+// using its place among the product's images
+func (s *productImageService) fillExistingAltText(ctx context.Context, image *models.ProductImage) error {
+	product, err := s.productRepo.GetByID(ctx, image.ProductID)
+	if err != nil {
+		return ErrProductNotFound
+	}
+
+	images, err := s.productImageRepo.GetByProductID(ctx, image.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to get product images: %w", err)
+	}
+
+	position := len(images)
+	for i := range images {
+		if images[i].ID == image.ID {
+			position = i + 1
+			break
+		}
+	}
+	fillAltText(image, product.Name, position)
+	return nil
+}
+
+// GetAccessibilityReport lists a seller's products that have no images or
+// images without meaningful alt text
+func (s *productImageService) GetAccessibilityReport(ctx context.Context, sellerID uint, limit, offset int) ([]models.ProductAccessibility, int64, error) {
+	products, total, err := s.productImageRepo.GetAccessibilityGaps(ctx, sellerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get accessibility report: %w", err)
+	}
+	return products, total, nil
+}
+
+func (s *productImageService) verifyImageURLs(ctx context.Context, imageReqs []models.ProductImageRequest) error {
+	for _, req := range imageReqs {
+		if err := s.verifyImageURL(ctx, req.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyImageURL checks that an external image URL is an http or https one
+// and, when VerifyImageURLs is set, that it answers with an image, so a
+// typo'd or dead link is rejected up front. The round trip is off by default
+// since it adds one per image. Our own uploads are trusted. Every failure is
+// the same ErrInvalidImageURL, so the check can't be used to probe which
+// hosts are reachable.
+func (s *productImageService) verifyImageURL(ctx context.Context, rawURL string) error {
+	if s.isInternalUploadURL(rawURL) {
+		return nil
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || !isWebURL(parsed) {
+		return ErrInvalidImageURL
+	}
+	if !s.config.Upload.VerifyImageURLs {
+		return nil
+	}
+
+	resp, err := s.requestImage(ctx, http.MethodHead, rawURL)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		// Some hosts don't support HEAD
+		resp, err = s.requestImage(ctx, http.MethodGet, rawURL)
+	}
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 || !strings.HasPrefix(resp.Header.Get("Content-Type"), "image/") {
+		return ErrInvalidImageURL
+	}
+
+	return nil
+}
+
+func (s *productImageService) requestImage(ctx context.Context, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// Only the status and headers matter
+	resp.Body.Close()
+
+	return resp, nil
+}
+
+// isInternalUploadURL reports whether the URL points at a file uploaded through this API
+func (s *productImageService) isInternalUploadURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	if !strings.HasPrefix(parsed.Path, "/uploads/") {
+		return false
+	}
+
+	if parsed.Host == "" {
+		return true
+	}
+
+	appURL, err := url.Parse(s.config.App.URL)
+	return err == nil && strings.EqualFold(parsed.Host, appURL.Host)
 }
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+)
+
+func TestVerifyImageURLOnlyReachesPublicWebURLs(t *testing.T) {
+	// Answers like a real image host, but on loopback
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Upload: config.UploadConfig{VerifyImageURLs: true, ImageURLCheckTimeout: 2 * time.Second}}
+	svc := NewProductImageService(nil, nil, cfg).(*productImageService)
+
+	for _, rawURL := range []string{
+		server.URL + "/image.png",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/image.png",
+		"file:///etc/passwd",
+		"gopher://example.com/image.png",
+		"https:///image.png",
+	} {
+		if err := svc.verifyImageURL(context.Background(), rawURL); !errors.Is(err, ErrInvalidImageURL) {
+			t.Errorf("verifyImageURL(%q) = %v, want ErrInvalidImageURL", rawURL, err)
+		}
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":        true,
+		"2606:2800:220:1::248": true,
+		"127.0.0.1":            false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"255.255.255.255":      false,
+		"::1":                  false,
+		"fc00::1":              false,
+		"fe80::1":              false,
+		"::ffff:127.0.0.1":     false,
+		"64:ff9b::a00:1":       false,
+	}
+	for addr, want := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("isPublicAddr(%s) = %t, want %t", addr, got, want)
+		}
+	}
+}
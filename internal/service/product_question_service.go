@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type productQuestionService struct {
+	questionRepo     repository.ProductQuestionRepository
+	productRepo      repository.ProductRepository
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+}
+
+func NewProductQuestionService(
+	questionRepo repository.ProductQuestionRepository,
+	productRepo repository.ProductRepository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+) ProductQuestionService {
+	return &productQuestionService{
+		questionRepo:     questionRepo,
+		productRepo:      productRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *productQuestionService) AskQuestion(ctx context.Context, productID, userID uint, req *models.AskQuestionRequest) (*models.ProductQuestionResponse, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	question := &models.ProductQuestion{
+		ProductID: productID,
+		UserID:    userID,
+		Question:  req.Question,
+	}
+	if err := s.questionRepo.CreateQuestion(ctx, question); err != nil {
+		return nil, fmt.Errorf("failed to create question: %w", err)
+	}
+
+	if user, err := s.userRepo.GetByID(ctx, userID); err == nil {
+		question.User = *user
+	}
+
+	resp := question.ToResponse()
+	return &resp, nil
+}
+
+func (s *productQuestionService) GetProductQuestions(ctx context.Context, productID uint, limit, offset int) ([]models.ProductQuestionResponse, int64, error) {
+	questions, total, err := s.questionRepo.GetQuestionsByProductID(ctx, productID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get questions: %w", err)
+	}
+
+	responses := make([]models.ProductQuestionResponse, len(questions))
+	for i, question := range questions {
+		responses[i] = question.ToResponse()
+	}
+
+	return responses, total, nil
+}
+
+// AnswerQuestion records an answer, badged by who gave it, and notifies the asker
+func (s *productQuestionService) AnswerQuestion(ctx context.Context, questionID, userID uint, userRole models.UserRole, req *models.AnswerQuestionRequest) (*models.ProductAnswerResponse, error) {
+	question, err := s.questionRepo.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, fmt.Errorf("failed to get question: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	answer := &models.ProductAnswer{
+		QuestionID: question.ID,
+		UserID:     userID,
+		User:       *user,
+		Answer:     req.Answer,
+		AuthorType: answerAuthorType(question, userID, userRole),
+	}
+	if err := s.questionRepo.CreateAnswer(ctx, answer); err != nil {
+		return nil, fmt.Errorf("failed to create answer: %w", err)
+	}
+
+	// The answer is saved; a failed notification shouldn't fail the request
+	if err := s.NotifyAsker(ctx, question, answer); err != nil {
+		fmt.Printf("Warning: failed to notify asker of question %d: %v\n", question.ID, err)
+	}
+
+	resp := answer.ToResponse()
+	return &resp, nil
+}
+
+// NotifyAsker tells the question's asker that it was answered. Askers answering
+// their own question aren't notified.
+func (s *productQuestionService) NotifyAsker(ctx context.Context, question *models.ProductQuestion, answer *models.ProductAnswer) error {
+	if answer.UserID == question.UserID {
+		return nil
+	}
+
+	title := "Your question was answered"
+	switch answer.AuthorType {
+	case models.AnswerAuthorSeller:
+		title = "The seller answered your question"
+	case models.AnswerAuthorStaff:
+		title = "Our team answered your question"
+	}
+
+	notification := &models.Notification{
+		UserID:  question.UserID,
+		Type:    models.NotificationTypeQuestionAnswered,
+		Title:   title,
+		Message: fmt.Sprintf("Your question about %s has a new answer: %s", question.Product.Name, answer.Answer),
+		Data:    questionNotificationData(question, answer),
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return nil
+}
+
+// answerAuthorType badges the product's seller and admins; everyone else is a customer
+func answerAuthorType(question *models.ProductQuestion, userID uint, userRole models.UserRole) models.AnswerAuthorType {
+	switch {
+	case userRole == models.RoleAdmin:
+		return models.AnswerAuthorStaff
+	case userRole == models.RoleSeller && question.Product.SellerID == userID:
+		return models.AnswerAuthorSeller
+	default:
+		return models.AnswerAuthorCustomer
+	}
+}
+
+func questionNotificationData(question *models.ProductQuestion, answer *models.ProductAnswer) *string {
+	payload, err := json.Marshal(map[string]interface{}{
+		"product_id":  question.ProductID,
+		"question_id": question.ID,
+		"answer_id":   answer.ID,
+	})
+	if err != nil {
+		return nil
+	}
+	data := string(payload)
+	return &data
+}
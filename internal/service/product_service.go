@@ -4,115 +4,364 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// tagFacetLimit caps how many tag facets a product listing returns
+	tagFacetLimit = 20
+
+	maxNewArrivalsDays = 365
+
+	// inventoryVelocityWindowDays is how far back sales are counted for inventory alerts
+	inventoryVelocityWindowDays = 30
+	// inventoryReorderCoverageDays is how many days of sales a suggested reorder covers
+	inventoryReorderCoverageDays = 30
 )
 
 type productService struct {
-	productRepo repository.ProductRepository
-	reviewRepo  repository.ReviewRepository
+	productRepo      repository.ProductRepository
+	reviewRepo       repository.ReviewRepository
+	categoryRepo     repository.CategoryRepository
+	notificationRepo repository.NotificationRepository
+	cache            *ProductCache
+	ratingGate       models.RatingGate
+	storefront       config.StorefrontConfig
 }
 
-func NewProductService(productRepo repository.ProductRepository, reviewRepo repository.ReviewRepository) ProductService {
+func NewProductService(productRepo repository.ProductRepository, reviewRepo repository.ReviewRepository, categoryRepo repository.CategoryRepository, notificationRepo repository.NotificationRepository, productCache *ProductCache, cfg *config.Config) ProductService {
 	return &productService{
-		productRepo: productRepo,
-		reviewRepo:  reviewRepo,
+		productRepo:      productRepo,
+		reviewRepo:       reviewRepo,
+		categoryRepo:     categoryRepo,
+		notificationRepo: notificationRepo,
+		cache:            productCache,
+		ratingGate: models.RatingGate{
+			MinRating:         cfg.Storefront.MinListingRating,
+			MinReviews:        cfg.Storefront.MinListingReviews,
+			IncludeFewReviews: cfg.Storefront.ListingIncludeFewReviews,
+		},
+		storefront: cfg.Storefront,
 	}
 }
 
-func (s *productService) CreateProduct(ctx context.Context, req *models.CreateProductRequest, sellerID uint) (*models.Product, error) {
+func (s *productService) CreateProduct(ctx context.Context, req *models.CreateProductRequest, sellerID uint, userRole models.UserRole) (*models.Product, error) {
 	if req.Price <= 0 {
-		return nil, errors.New("product price must be greater than 0")
+		return nil, ErrInvalidPrice
 	}
 
 	if req.Stock < 0 {
-		return nil, errors.New("product stock cannot be negative")
+		return nil, ErrNegativeProductStock
+	}
+
+	if err := validateBackorderLimit(req.AllowBackorders, req.MaxBackorderQuantity); err != nil {
+		return nil, err
+	}
+
+	returnable := req.Returnable == nil || *req.Returnable
+	if err := validateReturnWindow(returnable, req.ReturnWindowDays); err != nil {
+		return nil, err
+	}
+
+	if req.ProcessingTimeDays != nil && *req.ProcessingTimeDays < 0 {
+		return nil, ErrNegativeProcessingTime
+	}
+
+	if err := validateAvailabilityWindow(req.AvailableFrom, req.AvailableUntil); err != nil {
+		return nil, err
 	}
 
 	product := &models.Product{
 		Name:        req.Name,
 		Description: req.Description,
+		SKU:         req.SKU,
 		Price:       req.Price,
 		Stock:       req.Stock,
 		Category:    req.Category,
 		Images:      req.Images,
 		SellerID:    sellerID,
 		IsActive:    true,
+
+		AllowBackorders:      req.AllowBackorders,
+		MaxBackorderQuantity: req.MaxBackorderQuantity,
+		OversellTolerance:    req.OversellTolerance,
+
+		PurchaseLimitPerCustomer: req.PurchaseLimitPerCustomer,
+		PurchaseLimitWindowDays:  req.PurchaseLimitWindowDays,
+
+		Returnable:       returnable,
+		ReturnWindowDays: returnWindowOverride(req.ReturnWindowDays),
+
+		RestockingFeePercent: req.RestockingFeePercent,
+
+		ProcessingTimeDays: req.ProcessingTimeDays,
+
+		AvailableFrom:  req.AvailableFrom,
+		AvailableUntil: req.AvailableUntil,
+	}
+	product.SetTagsList(req.Tags)
+	if s.storefront.ProductApproval && userRole != models.RoleAdmin {
+		product.HoldForApproval()
+	}
+
+	exists, err := s.productRepo.SKUExists(ctx, product.SKU)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check product SKU: %w", err)
+	}
+	if exists {
+		return nil, ErrSKUTaken
+	}
+
+	if err := s.assignUniqueSlug(ctx, product); err != nil {
+		return nil, err
 	}
 
 	if err := s.productRepo.Create(ctx, product); err != nil {
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
+	s.cache.Invalidate(ctx)
 
 	return product, nil
 }
 
 func (s *productService) GetProduct(ctx context.Context, id uint) (*models.Product, error) {
-	product, err := s.productRepo.GetByID(ctx, id)
+	product, err := s.getCachedProduct(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
+	s.recordView(ctx, product)
+
 	return product, nil
 }
 
-func (s *productService) GetProducts(ctx context.Context, req *models.GetProductsRequest) (*models.ProductListResponse, error) {
-	var products []*models.Product
-	var err error
-	var total int64
-
-	switch {
-	case req.Category != "":
-		products, err = s.productRepo.GetByCategory(ctx, req.Category, req.Limit, req.Offset)
+// getCachedProduct returns the product with its lowest recent price through
+// the product cache
+func (s *productService) getCachedProduct(ctx context.Context, id uint) (*models.Product, error) {
+	var product *models.Product
+	err := s.cache.getOrLoad(ctx, productCacheDetail, fmt.Sprintf("%s%d", productCachePrefix, id), productCacheTTL, &product, func() (err error) {
+		product, err = s.productRepo.GetByID(ctx, id)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get products by category: %w", err)
+			return err
 		}
-		total, err = s.productRepo.CountByCategory(ctx, req.Category)
-	case req.SellerID != nil:
-		products, err = s.productRepo.GetBySellerID(ctx, *req.SellerID, req.Limit, req.Offset)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get products by seller: %w", err)
+		s.fillLowestRecentPrice(ctx, product)
+		return nil
+	})
+	return product, err
+}
+
+// GetProductsByIDs returns the requested products in the order they were
+// requested, each once. Unknown and deleted IDs are left out.
+func (s *productService) GetProductsByIDs(ctx context.Context, ids []uint) ([]*models.Product, error) {
+	if len(ids) > models.MaxBatchProductIDs {
+		return nil, errorf(ErrTooManyProductIDs, "too many product IDs (maximum %d)", models.MaxBatchProductIDs)
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	byID := make(map[uint]*models.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	ordered := make([]*models.Product, 0, len(products))
+	for _, id := range ids {
+		if product, ok := byID[id]; ok {
+			ordered = append(ordered, product)
+			delete(byID, id)
 		}
-		// For seller products, we could implement a specific count method
-		total, err = s.productRepo.Count(ctx)
-	case req.Search != "":
-		products, err = s.productRepo.Search(ctx, req.Search, req.Limit, req.Offset)
-		if err != nil {
-			return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	return ordered, nil
+}
+
+// GetProductChanges returns the page of products changed after the cursor,
+// deleted products included as tombstones
+func (s *productService) GetProductChanges(ctx context.Context, cursor models.ProductChangeCursor, limit int) (*models.ProductChangesResponse, error) {
+	if limit <= 0 {
+		limit = models.DefaultProductChangesLimit
+	}
+	if limit > models.MaxProductChangesLimit {
+		limit = models.MaxProductChangesLimit
+	}
+
+	// Fetch one extra row to know whether another page follows
+	products, err := s.productRepo.GetChangedSince(ctx, cursor, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product changes: %w", err)
+	}
+
+	response := &models.ProductChangesResponse{Changes: make([]models.ProductChange, 0, len(products))}
+	if len(products) > limit {
+		products = products[:limit]
+		response.HasMore = true
+	}
+	for _, product := range products {
+		response.Changes = append(response.Changes, models.NewProductChange(product, cursor.Since))
+	}
+
+	// The cursor stays put on an empty page so the caller can poll with it again
+	next := cursor
+	if len(products) > 0 {
+		last := products[len(products)-1]
+		next.UpdatedAt = last.UpdatedAt
+		next.ID = last.ID
+	}
+	response.NextCursor = next.Encode()
+
+	return response, nil
+}
+
+// GetProductBySlug resolves the slug to a product ID through the cache when it
+// can. The cached product must still carry the slug and not be deleted, so a
+// renamed product's old slug falls back to the database.
+func (s *productService) GetProductBySlug(ctx context.Context, slug string) (*models.Product, error) {
+	if id, ok := s.cache.slugProductID(ctx, slug); ok {
+		product, err := s.getCachedProduct(ctx, id)
+		if err == nil && product.Slug == slug && product.Status != models.ProductStatusDeleted {
+			s.recordView(ctx, product)
+			return product, nil
 		}
-		total, err = s.productRepo.Count(ctx) // Simplified for now
-	default:
-		products, err = s.productRepo.GetAll(ctx, req.Limit, req.Offset)
+	}
+
+	product, err := s.productRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	s.cache.setSlug(ctx, slug, product.ID)
+
+	s.recordView(ctx, product)
+	s.fillLowestRecentPrice(ctx, product)
+
+	return product, nil
+}
+
+// fillLowestRecentPrice sets the lowest price over the last LowestPriceWindowDays;
+// a failure leaves it unset rather than blocking the read
+func (s *productService) fillLowestRecentPrice(ctx context.Context, product *models.Product) {
+	since := time.Now().AddDate(0, 0, -models.LowestPriceWindowDays)
+	lowest, err := s.productRepo.GetLowestPriceSince(ctx, product.ID, since)
+	if err != nil {
+		fmt.Printf("Warning: failed to get price history for product %d: %v\n", product.ID, err)
+		return
+	}
+
+	price := product.Price
+	if lowest != nil && *lowest < price {
+		price = *lowest
+	}
+	product.LowestRecentPrice = &price
+}
+
+// recordView bumps the product's view count; a failure doesn't block the read
+func (s *productService) recordView(ctx context.Context, product *models.Product) {
+	if err := s.productRepo.IncrementViewCount(ctx, product.ID); err != nil {
+		fmt.Printf("Warning: failed to increment view count for product %d: %v\n", product.ID, err)
+		return
+	}
+	product.ViewCount++
+}
+
+// assignUniqueSlug generates a slug from the product name, suffixing it on collision
+func (s *productService) assignUniqueSlug(ctx context.Context, product *models.Product) error {
+	product.GenerateSlug()
+	base := product.Slug
+	for i := 2; ; i++ {
+		exists, err := s.productRepo.SlugExists(ctx, product.Slug)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get all products: %w", err)
+			return fmt.Errorf("failed to check product slug: %w", err)
+		}
+		if !exists {
+			return nil
 		}
-		total, err = s.productRepo.Count(ctx)
+		product.Slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+func (s *productService) GetProducts(ctx context.Context, req *models.GetProductsRequest) (*models.ProductListResponse, error) {
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		return nil, ErrInvalidPriceRange
 	}
 
+	req.SortBy = s.listingSort(ctx, req.Category, req.SortBy)
+	products, total, err := s.productRepo.GetFiltered(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get product count: %w", err)
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	priceTiers, err := s.productRepo.GetPriceTierCounts(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price tier counts: %w", err)
+	}
+
+	tags, err := s.productRepo.GetTagCounts(ctx, req, tagFacetLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag counts: %w", err)
 	}
 
 	return &models.ProductListResponse{
-		Products: products,
-		Total:    total,
-		Page:     req.Page,
-		Limit:    req.Limit,
+		Products:   products,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		PriceTiers: priceTiers,
+		Tags:       tags,
 	}, nil
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, id uint, req *models.UpdateProductRequest, sellerID uint) (*models.Product, error) {
+// listingSort returns the sort a listing asked for, else its category's
+// default, else the store's. A category that can't be loaded uses the store's.
+func (s *productService) listingSort(ctx context.Context, category string, requested models.ProductSort) models.ProductSort {
+	if requested != "" {
+		return requested
+	}
+	if category != "" {
+		c, err := s.categoryRepo.GetBySlug(ctx, category)
+		if err == nil && c.DefaultSort != nil {
+			return *c.DefaultSort
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			fmt.Printf("Warning: failed to get category %q for its default sort: %v\n", category, err)
+		}
+	}
+	return s.storefront.DefaultProductSort
+}
+
+func (s *productService) GetPopularTags(ctx context.Context, limit int) ([]models.TagCount, error) {
+	tags, err := s.productRepo.GetPopularTags(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (s *productService) UpdateProduct(ctx context.Context, id uint, req *models.UpdateProductRequest, sellerID uint, userRole models.UserRole) (*models.Product, error) {
 	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
 	if product.SellerID != sellerID {
-		return nil, errors.New("unauthorized to update this product")
+		return nil, ErrProductUpdateForbidden
 	}
 
+	oldPrice := product.Price
+	// A seller's content edit to a listed or rejected product goes back for approval
+	reapprove := s.storefront.ProductApproval && userRole != models.RoleAdmin &&
+		req.ChangesContent(product) && (product.Status == models.ProductStatusActive || product.NeedsApproval())
+
 	// Update fields if provided
 	if req.Name != nil {
 		product.Name = *req.Name
@@ -122,13 +371,25 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, req *models
 	}
 	if req.Price != nil {
 		if *req.Price <= 0 {
-			return nil, errors.New("product price must be greater than 0")
+			return nil, ErrInvalidPrice
 		}
 		product.Price = *req.Price
 	}
+	if req.ComparePrice != nil {
+		if *req.ComparePrice == 0 {
+			product.ComparePrice = nil
+		} else {
+			comparePrice := *req.ComparePrice
+			product.ComparePrice = &comparePrice
+		}
+	}
+	// Validate against the resulting price so a price-only update can't invert the discount
+	if product.ComparePrice != nil && *product.ComparePrice <= product.Price {
+		return nil, ErrComparePriceTooLow
+	}
 	if req.Stock != nil {
 		if *req.Stock < 0 {
-			return nil, errors.New("product stock cannot be negative")
+			return nil, ErrNegativeProductStock
 		}
 		product.Stock = *req.Stock
 	}
@@ -138,17 +399,179 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, req *models
 	if req.Images != nil {
 		product.Images = req.Images
 	}
+	if req.Tags != nil {
+		product.SetTagsList(req.Tags)
+	}
 	if req.IsActive != nil {
+		if *req.IsActive && product.NeedsApproval() {
+			return nil, ErrProductPendingApproval
+		}
 		product.IsActive = *req.IsActive
 	}
+	if req.AllowBackorders != nil {
+		product.AllowBackorders = *req.AllowBackorders
+	}
+	if req.MaxBackorderQuantity != nil {
+		product.MaxBackorderQuantity = *req.MaxBackorderQuantity
+	}
+	if err := validateBackorderLimit(product.AllowBackorders, product.MaxBackorderQuantity); err != nil {
+		return nil, err
+	}
+	if req.OversellTolerance != nil {
+		product.OversellTolerance = *req.OversellTolerance
+	}
+	if req.PurchaseLimitPerCustomer != nil {
+		product.PurchaseLimitPerCustomer = *req.PurchaseLimitPerCustomer
+	}
+	if req.PurchaseLimitWindowDays != nil {
+		product.PurchaseLimitWindowDays = *req.PurchaseLimitWindowDays
+	}
+	if req.Returnable != nil {
+		product.Returnable = *req.Returnable
+	}
+	if req.ReturnWindowDays != nil {
+		product.ReturnWindowDays = returnWindowOverride(*req.ReturnWindowDays)
+	}
+	if req.RestockingFeePercent != nil {
+		product.RestockingFeePercent = req.RestockingFeePercent
+	}
+	windowDays := 0
+	if product.ReturnWindowDays != nil {
+		windowDays = *product.ReturnWindowDays
+	}
+	if err := validateReturnWindow(product.Returnable, windowDays); err != nil {
+		return nil, err
+	}
+	if req.ProcessingTimeDays != nil {
+		if *req.ProcessingTimeDays < 0 {
+			return nil, ErrNegativeProcessingTime
+		}
+		product.ProcessingTimeDays = req.ProcessingTimeDays
+	}
+	if req.AvailableFrom != nil {
+		product.AvailableFrom = availabilityBound(*req.AvailableFrom)
+	}
+	if req.AvailableUntil != nil {
+		product.AvailableUntil = availabilityBound(*req.AvailableUntil)
+	}
+	// Validate the resulting window so updating one side can't invert it
+	if err := validateAvailabilityWindow(product.AvailableFrom, product.AvailableUntil); err != nil {
+		return nil, err
+	}
+
+	if reapprove {
+		product.HoldForApproval()
+	}
+
+	var priceChange *models.PriceHistory
+	if product.Price != oldPrice {
+		priceChange = &models.PriceHistory{
+			ProductID: product.ID,
+			OldPrice:  oldPrice,
+			NewPrice:  product.Price,
+			ChangedBy: sellerID,
+		}
+	}
 
-	if err := s.productRepo.Update(ctx, product); err != nil {
+	if err := s.productRepo.UpdateWithPriceChange(ctx, product, priceChange); err != nil {
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
+	s.cache.Invalidate(ctx, product.ID)
 
 	return product, nil
 }
 
+// ApproveProduct lists a product that was awaiting approval
+func (s *productService) ApproveProduct(ctx context.Context, id, adminID uint) (*models.Product, error) {
+	product, err := s.resolveApproval(ctx, id, true, nil, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifySeller(ctx, product, fmt.Sprintf("%s was approved", product.Name),
+		fmt.Sprintf("Your product %s was approved and is now listed.", product.Name))
+	return product, nil
+}
+
+// RejectProduct returns a product awaiting approval to its seller as a draft,
+// telling them why
+func (s *productService) RejectProduct(ctx context.Context, id uint, req *models.ProductRejectRequest, adminID uint) (*models.Product, error) {
+	reason := strings.TrimSpace(req.Reason)
+	product, err := s.resolveApproval(ctx, id, false, &reason, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifySeller(ctx, product, fmt.Sprintf("%s was not approved", product.Name),
+		fmt.Sprintf("Your product %s was not approved: %s. Edit it to submit it for approval again.", product.Name, reason))
+	return product, nil
+}
+
+func (s *productService) GetPendingProducts(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	products, total, err := s.productRepo.GetPendingApproval(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get products awaiting approval: %w", err)
+	}
+	return products, total, nil
+}
+
+func (s *productService) resolveApproval(ctx context.Context, id uint, approved bool, reason *string, adminID uint) (*models.Product, error) {
+	resolved, err := s.productRepo.ResolveApproval(ctx, id, approved, reason, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve product approval: %w", err)
+	}
+
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if !resolved {
+		return nil, ErrProductNotPendingApproval
+	}
+	s.cache.Invalidate(ctx, id)
+
+	return product, nil
+}
+
+// notifySeller tells the product's seller about an approval decision. A failed
+// notification doesn't undo the decision.
+func (s *productService) notifySeller(ctx context.Context, product *models.Product, title, message string) {
+	notification := &models.Notification{
+		UserID:  product.SellerID,
+		Type:    models.NotificationTypeProductApproval,
+		Title:   title,
+		Message: message,
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		fmt.Printf("Warning: failed to notify seller %d about product %d: %v\n", product.SellerID, product.ID, err)
+	}
+}
+
+func (s *productService) GetPriceHistory(ctx context.Context, productID, userID uint, userRole models.UserRole, limit, offset int) ([]*models.PriceHistory, int64, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrProductNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	// Sellers can only see the history of their own products
+	if userRole != models.RoleAdmin && product.SellerID != userID {
+		return nil, 0, ErrPriceHistoryForbidden
+	}
+
+	history, total, err := s.productRepo.GetPriceHistory(ctx, productID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	return history, total, nil
+}
+
 func (s *productService) DeleteProduct(ctx context.Context, id uint, sellerID uint) error {
 	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
@@ -156,12 +579,13 @@ func (s *productService) DeleteProduct(ctx context.Context, id uint, sellerID ui
 	}
 
 	if product.SellerID != sellerID {
-		return errors.New("unauthorized to delete this product")
+		return ErrProductDeleteForbidden
 	}
 
 	if err := s.productRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
+	s.cache.Invalidate(ctx, id)
 
 	return nil
 }
@@ -173,16 +597,17 @@ func (s *productService) UpdateStock(ctx context.Context, id uint, stock int, se
 	}
 
 	if product.SellerID != sellerID {
-		return errors.New("unauthorized to update this product's stock")
+		return ErrStockUpdateForbidden
 	}
 
 	if stock < 0 {
-		return errors.New("stock cannot be negative")
+		return ErrNegativeStock
 	}
 
 	if err := s.productRepo.UpdateStock(ctx, id, stock); err != nil {
 		return fmt.Errorf("failed to update stock: %w", err)
 	}
+	s.cache.Invalidate(ctx, id)
 
 	return nil
 }
@@ -207,8 +632,336 @@ func (s *productService) GetLowStockProducts(ctx context.Context, threshold int,
 	return products, nil
 }
 
+func (s *productService) GetInventoryValuation(ctx context.Context, sellerID uint) (*models.InventoryValuation, error) {
+	valuation, err := s.productRepo.GetInventoryValuation(ctx, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory valuation: %w", err)
+	}
+
+	return valuation, nil
+}
+
+// GetInventoryAlerts returns the seller's low stock products with their recent
+// sales velocity, most urgent first: out of stock, then fewest days of stock
+// left, then products without recent sales. The suggested reorder brings stock
+// to inventoryReorderCoverageDays of sales above the low stock level.
+func (s *productService) GetInventoryAlerts(ctx context.Context, sellerID uint) (*models.InventoryAlerts, error) {
+	products, err := s.productRepo.GetLowStockBySeller(ctx, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low stock products: %w", err)
+	}
+
+	productIDs := make([]uint, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+	}
+	since := time.Now().AddDate(0, 0, -inventoryVelocityWindowDays)
+	unitsSold, err := s.productRepo.GetUnitsSold(ctx, productIDs, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales velocity: %w", err)
+	}
+
+	alerts := make([]models.InventoryAlert, len(products))
+	for i, product := range products {
+		alert := models.InventoryAlert{
+			ProductID:     product.ID,
+			Name:          product.Name,
+			SKU:           product.SKU,
+			Stock:         product.Stock,
+			LowStockLevel: product.LowStockLevel,
+			UnitsSold:     unitsSold[product.ID],
+		}
+		rate := float64(alert.UnitsSold) / inventoryVelocityWindowDays
+		alert.DailySalesRate = math.Round(rate*100) / 100
+
+		if rate > 0 {
+			days := math.Round(math.Max(float64(product.Stock), 0)/rate*10) / 10
+			alert.DaysOfStockRemaining = &days
+			target := int(math.Ceil(rate*inventoryReorderCoverageDays)) + product.LowStockLevel
+			alert.SuggestedReorderQuantity = target - product.Stock
+		} else {
+			alert.SuggestedReorderQuantity = product.LowStockLevel - product.Stock
+		}
+		if alert.SuggestedReorderQuantity < 0 {
+			alert.SuggestedReorderQuantity = 0
+		}
+
+		alerts[i] = alert
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return inventoryAlertUrgency(alerts[i]) < inventoryAlertUrgency(alerts[j])
+	})
+
+	return &models.InventoryAlerts{
+		SellerID:           sellerID,
+		VelocityWindowDays: inventoryVelocityWindowDays,
+		Alerts:             alerts,
+	}, nil
+}
+
+// GetProductsNeedingAttention lists the seller's products with listing issues
+// to fix, most pressing first. Low ratings are judged by the same gate as the
+// featured and top-rated lists.
+func (s *productService) GetProductsNeedingAttention(ctx context.Context, sellerID uint, reason *models.ProductAttentionReason, limit, offset int) ([]models.ProductAttention, int64, error) {
+	if reason != nil && !reason.IsValid() {
+		return nil, 0, ErrInvalidAttentionReason
+	}
+
+	criteria := models.ProductAttentionCriteria{
+		Rating:      s.ratingGate,
+		DraftBefore: time.Now().AddDate(0, 0, -s.storefront.StaleDraftDays),
+		Reason:      reason,
+	}
+	products, total, err := s.productRepo.GetNeedingAttention(ctx, sellerID, criteria, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get products needing attention: %w", err)
+	}
+	return products, total, nil
+}
+
+// inventoryAlertUrgency is the days of stock left, 0 when out of stock and
+// infinite without recent sales; lower is more urgent
+func inventoryAlertUrgency(alert models.InventoryAlert) float64 {
+	if alert.Stock <= 0 {
+		return 0
+	}
+	if alert.DaysOfStockRemaining == nil {
+		return math.Inf(1)
+	}
+	return *alert.DaysOfStockRemaining
+}
+
+// BulkSetVisibility shows or hides many products at once. Sellers can only
+// change their own products; admins can change any.
+func (s *productService) BulkSetVisibility(ctx context.Context, req *models.BulkVisibilityRequest, userID uint, userRole models.UserRole) (*models.BulkVisibilityResponse, error) {
+	visible := req.Status != nil && *req.Status == models.ProductStatusActive
+	if req.Visible != nil {
+		visible = *req.Visible
+	}
+
+	var sellerID *uint
+	if userRole != models.RoleAdmin {
+		sellerID = &userID
+	}
+
+	results, err := s.productRepo.BulkSetVisibility(ctx, req.ProductIDs, visible, req.Status, sellerID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product visibility: %w", err)
+	}
+
+	response := &models.BulkVisibilityResponse{
+		Visible: visible,
+		Status:  req.Status,
+		Results: results,
+	}
+	var changed []uint
+	for _, result := range results {
+		if result.Success {
+			response.Succeeded++
+			changed = append(changed, result.ProductID)
+		} else {
+			response.Failed++
+		}
+	}
+	if len(changed) > 0 {
+		s.cache.Invalidate(ctx, changed...)
+	}
+
+	return response, nil
+}
+
+// LocalizeProducts replaces the products' content with their translation in
+// the first preferred locale that has one, trying each locale's base language
+// after it (e.g. "fr-CA" then "fr"). Products without a matching translation
+// keep their base content. A failure leaves every product untranslated rather
+// than blocking the read.
+func (s *productService) LocalizeProducts(ctx context.Context, locales []string, products ...*models.Product) {
+	if len(locales) == 0 || len(products) == 0 {
+		return
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+	for _, locale := range locales {
+		for _, candidate := range models.LocaleFallbacks(locale) {
+			if !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	productIDs := make([]uint, 0, len(products))
+	for _, product := range products {
+		if product != nil {
+			productIDs = append(productIDs, product.ID)
+		}
+	}
+
+	translations, err := s.productRepo.GetTranslationsFor(ctx, productIDs, candidates)
+	if err != nil {
+		fmt.Printf("Warning: failed to get product translations: %v\n", err)
+		return
+	}
+
+	byProduct := make(map[uint]map[string]*models.ProductTranslation)
+	for _, translation := range translations {
+		if byProduct[translation.ProductID] == nil {
+			byProduct[translation.ProductID] = make(map[string]*models.ProductTranslation)
+		}
+		byProduct[translation.ProductID][translation.Locale] = translation
+	}
+
+	for _, product := range products {
+		if product == nil {
+			continue
+		}
+		for _, candidate := range candidates {
+			if translation, ok := byProduct[product.ID][candidate]; ok {
+				product.ApplyTranslation(translation)
+				break
+			}
+		}
+	}
+}
+
+// getOwnProduct loads a product the seller may manage; admins may manage any
+func (s *productService) getOwnProduct(ctx context.Context, productID, userID uint, userRole models.UserRole) (*models.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if userRole != models.RoleAdmin && product.SellerID != userID {
+		return nil, ErrTranslationsForbidden
+	}
+
+	return product, nil
+}
+
+func (s *productService) GetTranslations(ctx context.Context, productID, userID uint, userRole models.UserRole) ([]*models.ProductTranslation, error) {
+	if _, err := s.getOwnProduct(ctx, productID, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	translations, err := s.productRepo.GetTranslations(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+// SetTranslation creates or replaces the product's content in the locale
+func (s *productService) SetTranslation(ctx context.Context, productID uint, locale string, req *models.ProductTranslationRequest, userID uint, userRole models.UserRole) (*models.ProductTranslation, error) {
+	normalized, ok := models.NormalizeLocale(locale)
+	if !ok {
+		return nil, ErrInvalidLocale
+	}
+
+	if _, err := s.getOwnProduct(ctx, productID, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	translation := &models.ProductTranslation{
+		ProductID:       productID,
+		Locale:          normalized,
+		Name:            req.Name,
+		Description:     req.Description,
+		ShortDesc:       req.ShortDesc,
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+	}
+	if err := s.productRepo.UpsertTranslation(ctx, translation); err != nil {
+		return nil, fmt.Errorf("failed to save product translation: %w", err)
+	}
+
+	return translation, nil
+}
+
+func (s *productService) DeleteTranslation(ctx context.Context, productID uint, locale string, userID uint, userRole models.UserRole) error {
+	normalized, ok := models.NormalizeLocale(locale)
+	if !ok {
+		return ErrInvalidLocale
+	}
+
+	if _, err := s.getOwnProduct(ctx, productID, userID, userRole); err != nil {
+		return err
+	}
+
+	if err := s.productRepo.DeleteTranslation(ctx, productID, normalized); err != nil {
+		return fmt.Errorf("failed to delete product translation: %w", err)
+	}
+
+	return nil
+}
+
+// GrantPurchaseLimitExemption lets the customer buy the product past its per-customer purchase limit
+func (s *productService) GrantPurchaseLimitExemption(ctx context.Context, productID uint, req *models.PurchaseLimitExemptionRequest, adminID uint) (*models.PurchaseLimitExemption, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	exemption := &models.PurchaseLimitExemption{
+		ProductID: productID,
+		UserID:    req.UserID,
+		Note:      req.Note,
+		CreatedBy: adminID,
+	}
+	if err := s.productRepo.AddPurchaseLimitExemption(ctx, exemption); err != nil {
+		return nil, fmt.Errorf("failed to grant purchase limit exemption: %w", err)
+	}
+
+	return exemption, nil
+}
+
+func (s *productService) RevokePurchaseLimitExemption(ctx context.Context, productID, userID uint) error {
+	if err := s.productRepo.RemovePurchaseLimitExemption(ctx, productID, userID); err != nil {
+		return fmt.Errorf("failed to revoke purchase limit exemption: %w", err)
+	}
+
+	return nil
+}
+
+// GetNewArrivals returns products added in the last days days, newest first.
+// Zero days uses the configured window. Results are cached briefly since the
+// list backs the storefront homepage.
+func (s *productService) GetNewArrivals(ctx context.Context, days int, category string, limit int) ([]*models.Product, error) {
+	if days <= 0 {
+		days = s.storefront.NewArrivalsDays
+	}
+	if days > maxNewArrivalsDays {
+		days = maxNewArrivalsDays
+	}
+
+	var products []*models.Product
+	cacheKey := s.cache.listKey(ctx, productCacheNewArrivals, days, limit, strings.ToLower(category))
+	err := s.cache.getOrLoad(ctx, productCacheNewArrivals, cacheKey, productListCacheTTL, &products, func() (err error) {
+		since := time.Now().AddDate(0, 0, -days)
+		products, err = s.productRepo.GetNewArrivals(ctx, since, category, s.storefront.NewArrivalsIncludeOutOfStock, limit)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get new arrivals: %w", err)
+	}
+
+	return products, nil
+}
+
 func (s *productService) GetTopRatedProducts(ctx context.Context, limit int) ([]*models.Product, error) {
-	products, err := s.productRepo.GetTopRated(ctx, limit)
+	var products []*models.Product
+	cacheKey := s.cache.listKey(ctx, productCacheTopRated, limit)
+	err := s.cache.getOrLoad(ctx, productCacheTopRated, cacheKey, productListCacheTTL, &products, func() (err error) {
+		products, err = s.productRepo.GetTopRated(ctx, limit, s.ratingGate)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get top rated products: %w", err)
 	}
@@ -216,9 +969,46 @@ func (s *productService) GetTopRatedProducts(ctx context.Context, limit int) ([]
 	return products, nil
 }
 
+func (s *productService) GetFeaturedProducts(ctx context.Context, limit int) ([]*models.Product, error) {
+	var products []*models.Product
+	cacheKey := s.cache.listKey(ctx, productCacheFeatured, limit)
+	err := s.cache.getOrLoad(ctx, productCacheFeatured, cacheKey, productListCacheTTL, &products, func() (err error) {
+		products, err = s.productRepo.GetFeatured(ctx, limit, s.ratingGate)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get featured products: %w", err)
+	}
+
+	return products, nil
+}
+
+// GetRelatedProducts recommends other products from the product's category
+func (s *productService) GetRelatedProducts(ctx context.Context, productID uint, limit int) ([]*models.Product, error) {
+	product, err := s.getCachedProduct(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	var products []*models.Product
+	cacheKey := s.cache.listKey(ctx, productCacheRelated, productID, limit)
+	err = s.cache.getOrLoad(ctx, productCacheRelated, cacheKey, productListCacheTTL, &products, func() (err error) {
+		products, err = s.productRepo.GetRelated(ctx, product, limit, s.ratingGate)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get related products: %w", err)
+	}
+
+	return products, nil
+}
+
 func (s *productService) SearchProducts(ctx context.Context, query string, limit, offset int) ([]*models.Product, error) {
 	if strings.TrimSpace(query) == "" {
-		return nil, errors.New("search query cannot be empty")
+		return nil, ErrEmptySearchQuery
 	}
 
 	products, err := s.productRepo.Search(ctx, query, limit, offset)
@@ -229,12 +1019,12 @@ func (s *productService) SearchProducts(ctx context.Context, query string, limit
 	return products, nil
 }
 
-func (s *productService) GetProductsByCategory(ctx context.Context, category string, limit, offset int) ([]*models.Product, error) {
+func (s *productService) GetProductsByCategory(ctx context.Context, category string, sortBy models.ProductSort, limit, offset int) ([]*models.Product, error) {
 	if strings.TrimSpace(category) == "" {
-		return nil, errors.New("category cannot be empty")
+		return nil, ErrEmptyCategory
 	}
 
-	products, err := s.productRepo.GetByCategory(ctx, category, limit, offset)
+	products, err := s.productRepo.GetByCategory(ctx, category, s.listingSort(ctx, category, sortBy), limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get products by category: %w", err)
 	}
@@ -259,6 +1049,58 @@ func (s *productService) UpdateProductRating(ctx context.Context, productID uint
 	if err := s.productRepo.UpdateRating(ctx, productID, avgRating, int(reviewCount)); err != nil {
 		return fmt.Errorf("failed to update product rating: %w", err)
 	}
+	s.cache.Invalidate(ctx, productID)
+
+	return nil
+}
+
+// GetCacheStats returns the product cache's hit and miss counts since startup
+func (s *productService) GetCacheStats() []models.CacheStats {
+	return s.cache.Stats()
+}
+
+// validateBackorderLimit checks the backorder cap against the backorder setting
+func validateBackorderLimit(allowBackorders bool, maxBackorderQuantity int) error {
+	if maxBackorderQuantity < 0 {
+		return ErrNegativeBackorderLimit
+	}
+	if maxBackorderQuantity > 0 && !allowBackorders {
+		return ErrBackordersDisabled
+	}
+	return nil
+}
 
+// validateReturnWindow rejects a custom return window on a non-returnable product
+func validateReturnWindow(returnable bool, returnWindowDays int) error {
+	if returnWindowDays < 0 {
+		return ErrNegativeReturnWindow
+	}
+	if returnWindowDays > 0 && !returnable {
+		return ErrReturnWindowNotReturnable
+	}
 	return nil
 }
+
+// validateAvailabilityWindow rejects a window that ends before it starts
+func validateAvailabilityWindow(from, until *time.Time) error {
+	if from != nil && until != nil && until.Before(*from) {
+		return ErrInvalidAvailabilityWindow
+	}
+	return nil
+}
+
+// availabilityBound maps a requested window bound to the stored one; the zero time clears it
+func availabilityBound(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// returnWindowOverride maps a requested window to the stored override; 0 means use the store default
+func returnWindowOverride(days int) *int {
+	if days <= 0 {
+		return nil
+	}
+	return &days
+}
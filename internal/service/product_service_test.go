@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+func TestListingSortFallsBackFromRequestToCategoryToStore(t *testing.T) {
+	bestSelling := models.ProductSortBestSelling
+	svc := &productService{
+		categoryRepo: &fakeCategoryRepo{categories: map[string]*models.Category{
+			"electronics": {Slug: "electronics", DefaultSort: &bestSelling},
+			"books":       {Slug: "books"},
+		}},
+		storefront: config.StorefrontConfig{DefaultProductSort: models.ProductSortNewest},
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		category  string
+		requested models.ProductSort
+		want      models.ProductSort
+	}{
+		{"requested sort wins", "electronics", models.ProductSortPriceAsc, models.ProductSortPriceAsc},
+		{"category default", "electronics", "", models.ProductSortBestSelling},
+		{"category without a default", "books", "", models.ProductSortNewest},
+		{"unknown category", "garden", "", models.ProductSortNewest},
+		{"no category", "", "", models.ProductSortNewest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := svc.listingSort(ctx, tt.category, tt.requested); got != tt.want {
+				t.Errorf("listingSort() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateProductRejectsTakenSKU(t *testing.T) {
+	svc := &productService{productRepo: newFakeProductRepo(&models.Product{BaseModel: models.BaseModel{ID: 1}, SKU: "MUG-01"})}
+	req := &models.CreateProductRequest{Name: "Mug", Description: "A sturdy mug", SKU: "MUG-01", Price: 12, Category: "home"}
+
+	if _, err := svc.CreateProduct(context.Background(), req, 3, models.RoleSeller); !errors.Is(err, ErrSKUTaken) {
+		t.Errorf("err = %v, want ErrSKUTaken", err)
+	}
+}
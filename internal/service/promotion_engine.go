@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+)
+
+// PromotionEngine picks the order-level promotion for a cart or order. It's
+// shared by the cart summary and CreateOrder so both price the same way.
+type PromotionEngine struct {
+	promotionRepo repository.PromotionRepository
+	productRepo   repository.ProductRepository
+}
+
+func NewPromotionEngine(promotionRepo repository.PromotionRepository, productRepo repository.ProductRepository) *PromotionEngine {
+	return &PromotionEngine{
+		promotionRepo: promotionRepo,
+		productRepo:   productRepo,
+	}
+}
+
+// Apply evaluates every running promotion against the order, applies the one
+// giving the largest discount as the order discount and records it on the
+// order. Order totals must already be calculated; they're recalculated after.
+// A failure to load promotions only skips them so checkout isn't blocked.
+func (e *PromotionEngine) Apply(ctx context.Context, order *models.Order) *models.AppliedPromotion {
+	order.DiscountAmount = 0
+	order.PromotionID = nil
+	order.PromotionName = nil
+
+	promotions, err := e.promotionRepo.GetRunning(ctx, time.Now())
+	if err != nil {
+		fmt.Printf("Warning: failed to load promotions: %v\n", err)
+		return nil
+	}
+	if len(promotions) == 0 {
+		return nil
+	}
+
+	lines := e.promotionLines(ctx, order)
+
+	var best *models.Promotion
+	var bestDiscount float64
+	for _, promotion := range promotions {
+		if discount := promotion.Discount(lines, order.SubtotalAmount); discount > bestDiscount {
+			best, bestDiscount = promotion, discount
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	order.DiscountAmount = bestDiscount
+	order.PromotionID = &best.ID
+	order.PromotionName = &best.Name
+	order.CalculateTotals()
+
+	return &models.AppliedPromotion{
+		ID:       best.ID,
+		Name:     best.Name,
+		Discount: bestDiscount,
+	}
+}
+
+// promotionLines looks up each item's category so category rules can match
+func (e *PromotionEngine) promotionLines(ctx context.Context, order *models.Order) []models.PromotionLine {
+	lines := make([]models.PromotionLine, 0, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		line := models.PromotionLine{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			LineTotal: item.LineTotal(),
+		}
+		if product, err := e.productRepo.GetByID(ctx, item.ProductID); err == nil {
+			line.CategoryID = product.CategoryID
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
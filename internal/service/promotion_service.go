@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type promotionService struct {
+	promotionRepo repository.PromotionRepository
+	categoryRepo  repository.CategoryRepository
+	productRepo   repository.ProductRepository
+}
+
+func NewPromotionService(promotionRepo repository.PromotionRepository, categoryRepo repository.CategoryRepository, productRepo repository.ProductRepository) PromotionService {
+	return &promotionService{
+		promotionRepo: promotionRepo,
+		categoryRepo:  categoryRepo,
+		productRepo:   productRepo,
+	}
+}
+
+func (s *promotionService) CreatePromotion(ctx context.Context, req *models.PromotionRequest, adminID uint) (*models.Promotion, error) {
+	promotion := &models.Promotion{CreatedBy: adminID}
+	if err := s.applyRequest(ctx, promotion, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.promotionRepo.Create(ctx, promotion); err != nil {
+		return nil, fmt.Errorf("failed to create promotion: %w", err)
+	}
+
+	return promotion, nil
+}
+
+func (s *promotionService) GetPromotion(ctx context.Context, id uint) (*models.Promotion, error) {
+	promotion, err := s.promotionRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	return promotion, nil
+}
+
+func (s *promotionService) GetPromotions(ctx context.Context, limit, offset int) ([]*models.Promotion, int64, error) {
+	promotions, total, err := s.promotionRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get promotions: %w", err)
+	}
+	return promotions, total, nil
+}
+
+func (s *promotionService) UpdatePromotion(ctx context.Context, id uint, req *models.PromotionRequest) (*models.Promotion, error) {
+	promotion, err := s.GetPromotion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRequest(ctx, promotion, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.promotionRepo.Update(ctx, promotion); err != nil {
+		return nil, fmt.Errorf("failed to update promotion: %w", err)
+	}
+
+	return promotion, nil
+}
+
+func (s *promotionService) DeletePromotion(ctx context.Context, id uint) error {
+	if _, err := s.GetPromotion(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.promotionRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete promotion: %w", err)
+	}
+	return nil
+}
+
+// applyRequest validates the rule for the promotion type and copies the
+// request onto the promotion
+func (s *promotionService) applyRequest(ctx context.Context, promotion *models.Promotion, req *models.PromotionRequest) error {
+	switch req.Type {
+	case models.PromotionTypeCategoryPercent:
+		if req.CategoryID == nil {
+			return ErrPromotionCategoryRequired
+		}
+		if req.DiscountPercent <= 0 {
+			return ErrDiscountPercentRequired
+		}
+	case models.PromotionTypeBOGO:
+		if req.BuyQuantity <= 0 || req.GetQuantity <= 0 {
+			return ErrBuyGetQuantitiesRequired
+		}
+		if req.DiscountPercent <= 0 {
+			return ErrDiscountPercentRequired
+		}
+	case models.PromotionTypeSpendAndSave:
+		if req.MinSpend <= 0 || req.DiscountAmount <= 0 {
+			return ErrMinSpendDiscountRequired
+		}
+		if req.DiscountAmount > req.MinSpend {
+			return ErrDiscountExceedsMinSpend
+		}
+	}
+
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil && !req.EndsAt.After(startsAt) {
+		return ErrPromotionEndsBeforeStart
+	}
+
+	if req.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *req.CategoryID); err != nil {
+			return ErrCategoryNotFound
+		}
+	}
+	if req.ProductID != nil {
+		if _, err := s.productRepo.GetByID(ctx, *req.ProductID); err != nil {
+			return ErrProductNotFound
+		}
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	promotion.Name = req.Name
+	promotion.Description = req.Description
+	promotion.Type = req.Type
+	promotion.IsActive = isActive
+	promotion.StartsAt = startsAt
+	promotion.EndsAt = req.EndsAt
+	promotion.CategoryID = req.CategoryID
+	promotion.ProductID = req.ProductID
+	promotion.DiscountPercent = req.DiscountPercent
+	promotion.BuyQuantity = req.BuyQuantity
+	promotion.GetQuantity = req.GetQuantity
+	promotion.MinSpend = req.MinSpend
+	promotion.DiscountAmount = req.DiscountAmount
+	return nil
+}
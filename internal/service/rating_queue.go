@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+)
+
+// RatingQueue collects the products whose reviews changed and recomputes each
+// one's rating once per flush, so a burst of review writes such as an import or
+// bulk moderation costs one recompute per product rather than one per review.
+// Until Start runs, or with a non-positive interval, products are recomputed as
+// soon as they're queued.
+type RatingQueue struct {
+	reviewRepo   repository.ReviewRepository
+	productRepo  repository.ProductRepository
+	productCache *ProductCache
+
+	interval time.Duration
+
+	mu      sync.Mutex
+	running bool
+	pending map[uint]struct{}
+}
+
+func NewRatingQueue(reviewRepo repository.ReviewRepository, productRepo repository.ProductRepository, productCache *ProductCache, cfg *config.Config) *RatingQueue {
+	return &RatingQueue{
+		reviewRepo:   reviewRepo,
+		productRepo:  productRepo,
+		productCache: productCache,
+		interval:     cfg.Storefront.RatingRecomputeInterval,
+		pending:      make(map[uint]struct{}),
+	}
+}
+
+// Start flushes the queue every interval until ctx is done
+func (q *RatingQueue) Start(ctx context.Context) {
+	if q.interval <= 0 {
+		return
+	}
+
+	q.mu.Lock()
+	q.running = true
+	q.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(q.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				q.Flush(ctx)
+			}
+		}
+	}()
+}
+
+// Enqueue marks the products' ratings as stale
+func (q *RatingQueue) Enqueue(ctx context.Context, productIDs ...uint) {
+	q.mu.Lock()
+	running := q.running
+	if running {
+		for _, productID := range productIDs {
+			q.pending[productID] = struct{}{}
+		}
+	}
+	q.mu.Unlock()
+
+	if !running {
+		for _, productID := range productIDs {
+			q.recompute(ctx, productID)
+		}
+	}
+}
+
+// Flush recomputes every queued product now and returns how many it handled.
+// Bulk importers call it once their batch is written.
+func (q *RatingQueue) Flush(ctx context.Context) int {
+	q.mu.Lock()
+	pending := q.pending
+	q.pending = make(map[uint]struct{})
+	q.mu.Unlock()
+
+	for productID := range pending {
+		q.recompute(ctx, productID)
+	}
+	return len(pending)
+}
+
+// recompute stores the product's rating from its reviews. A failure is only
+// logged; the next review change queues the product again.
+func (q *RatingQueue) recompute(ctx context.Context, productID uint) {
+	if err := q.updateProductRating(ctx, productID); err != nil {
+		fmt.Printf("Warning: failed to update product rating: %v\n", err)
+	}
+}
+
+func (q *RatingQueue) updateProductRating(ctx context.Context, productID uint) error {
+	avgRating, err := q.reviewRepo.GetAverageRatingByProductID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to get average rating: %w", err)
+	}
+
+	reviewCount, err := q.reviewRepo.CountByProductID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to get review count: %w", err)
+	}
+
+	if err := q.productRepo.UpdateRating(ctx, productID, avgRating, int(reviewCount)); err != nil {
+		return fmt.Errorf("failed to update product rating: %w", err)
+	}
+	q.productCache.Invalidate(ctx, productID)
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+)
+
+func newTestRatingQueue(interval time.Duration) (*RatingQueue, *fakeProductRepo) {
+	productRepo := newFakeProductRepo()
+	cfg := &config.Config{Storefront: config.StorefrontConfig{RatingRecomputeInterval: interval}}
+	return NewRatingQueue(&fakeReviewRepo{}, productRepo, newOfflineProductCache(), cfg), productRepo
+}
+
+func TestRatingQueueRecomputesEachProductOncePerFlush(t *testing.T) {
+	queue, productRepo := newTestRatingQueue(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue.Start(ctx)
+
+	queue.Enqueue(ctx, 7)
+	queue.Enqueue(ctx, 7, 8)
+	queue.Enqueue(ctx, 7)
+	if len(productRepo.ratings) != 0 {
+		t.Fatalf("expected no recompute before the flush, got %v", productRepo.ratings)
+	}
+
+	if got := queue.Flush(ctx); got != 2 {
+		t.Errorf("Flush() = %d, want 2", got)
+	}
+	if productRepo.ratings[7] != 1 || productRepo.ratings[8] != 1 {
+		t.Errorf("expected one recompute per product, got %v", productRepo.ratings)
+	}
+
+	if got := queue.Flush(ctx); got != 0 {
+		t.Errorf("expected an empty queue after flushing, got %d", got)
+	}
+}
+
+func TestRatingQueueRecomputesImmediatelyWhenNotRunning(t *testing.T) {
+	queue, productRepo := newTestRatingQueue(0)
+	queue.Start(context.Background())
+
+	queue.Enqueue(context.Background(), 7)
+	if productRepo.ratings[7] != 1 {
+		t.Errorf("expected the rating to be recomputed on enqueue, got %v", productRepo.ratings)
+	}
+}
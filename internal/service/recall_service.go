@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+const recallBatchSize = 100
+
+type recallService struct {
+	recallRepo       repository.RecallRepository
+	productRepo      repository.ProductRepository
+	notificationRepo repository.NotificationRepository
+}
+
+func NewRecallService(
+	recallRepo repository.RecallRepository,
+	productRepo repository.ProductRepository,
+	notificationRepo repository.NotificationRepository,
+) RecallService {
+	return &recallService{
+		recallRepo:       recallRepo,
+		productRepo:      productRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// RecallProduct records the recall and notifies everyone who paid for the
+// product. Their emails are queued for the email dispatcher rather than sent
+// here, so a large recall returns as soon as it is recorded.
+func (s *recallService) RecallProduct(ctx context.Context, productID uint, req *models.ProductRecallRequest, userID uint, userRole models.UserRole) (*models.ProductRecall, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	// Sellers can only recall their own products
+	if userRole != models.RoleAdmin && product.SellerID != userID {
+		return nil, ErrRecallForbidden
+	}
+
+	recall := &models.ProductRecall{
+		ProductID:   productID,
+		InitiatedBy: userID,
+		Message:     req.Message,
+	}
+	if err := s.recallRepo.Create(ctx, recall); err != nil {
+		return nil, fmt.Errorf("failed to create recall: %w", err)
+	}
+
+	data := recallNotificationData(product, recall)
+
+	// Page through affected customers so large recalls don't load everyone at once
+	var afterID uint
+	for {
+		customers, err := s.recallRepo.GetAffectedCustomers(ctx, productID, afterID, recallBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get affected customers: %w", err)
+		}
+		if len(customers) == 0 {
+			break
+		}
+
+		notifications := make([]*models.Notification, len(customers))
+		for i, customer := range customers {
+			notifications[i] = &models.Notification{
+				UserID:  customer.ID,
+				Type:    models.NotificationTypeProductRecall,
+				Title:   fmt.Sprintf("Recall notice: %s", product.Name),
+				Message: req.Message,
+				Data:    data,
+			}
+		}
+		if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+			return nil, fmt.Errorf("failed to create recall notifications: %w", err)
+		}
+
+		if err := s.recallRepo.QueueEmails(ctx, recall.ID, customers); err != nil {
+			return nil, fmt.Errorf("failed to queue recall emails: %w", err)
+		}
+
+		recall.CustomersNotified += len(customers)
+		afterID = customers[len(customers)-1].ID
+
+		if len(customers) < recallBatchSize {
+			break
+		}
+	}
+
+	if err := s.recallRepo.Update(ctx, recall); err != nil {
+		return nil, fmt.Errorf("failed to update recall: %w", err)
+	}
+
+	return recall, nil
+}
+
+// recallNotificationData builds the notification payload linking back to the product and recall
+func recallNotificationData(product *models.Product, recall *models.ProductRecall) *string {
+	payload, err := json.Marshal(map[string]interface{}{
+		"product_id": product.ID,
+		"recall_id":  recall.ID,
+	})
+	if err != nil {
+		return nil
+	}
+	data := string(payload)
+	return &data
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/pkg/payment"
+	"gorm.io/gorm"
+)
+
+type returnService struct {
+	returnRepo repository.ReturnRequestRepository
+	orderRepo  repository.OrderRepository
+	userRepo   repository.UserRepository
+	paymentSvc payment.Service
+}
+
+func NewReturnService(returnRepo repository.ReturnRequestRepository, orderRepo repository.OrderRepository, userRepo repository.UserRepository, paymentSvc payment.Service) ReturnService {
+	return &returnService{
+		returnRepo: returnRepo,
+		orderRepo:  orderRepo,
+		userRepo:   userRepo,
+		paymentSvc: paymentSvc,
+	}
+}
+
+// RequestReturn opens a return for units of a delivered item still inside its
+// product's return window
+func (s *returnService) RequestReturn(ctx context.Context, orderID, customerID uint, req *models.ReturnCreateRequest) (*models.ReturnRequest, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.CustomerID != customerID {
+		return nil, ErrOrderViewForbidden
+	}
+
+	item := order.FindItem(req.OrderItemID)
+	if item == nil {
+		return nil, ErrOrderItemNotFound
+	}
+	if order.PaymentStatus != models.PaymentStatusPaid {
+		return nil, ErrReturnNotRefundable
+	}
+	if order.ItemDeliveredAt(item) == nil {
+		return nil, ErrReturnNotDelivered
+	}
+	if !order.IsItemReturnEligible(item, time.Now()) {
+		return nil, ErrReturnWindowClosed
+	}
+
+	feePercent := 0.0
+	if !req.Defective {
+		if feePercent, err = s.restockingFeePercent(ctx, &item.Product); err != nil {
+			return nil, err
+		}
+	}
+
+	returnRequest := &models.ReturnRequest{
+		OrderID:              order.ID,
+		OrderItemID:          item.ID,
+		CustomerID:           customerID,
+		Quantity:             req.Quantity,
+		Reason:               strings.TrimSpace(req.Reason),
+		Defective:            req.Defective,
+		Status:               models.ReturnStatusRequested,
+		RestockingFeePercent: feePercent,
+	}
+
+	created, err := s.returnRepo.CreateWithinQuantity(ctx, returnRequest, item.Quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create return request: %w", err)
+	}
+	if !created {
+		return nil, ErrReturnQuantityExceeded
+	}
+
+	returnRequest.OrderItem = *item
+	return returnRequest, nil
+}
+
+// restockingFeePercent returns the product's restocking fee, falling back to
+// its seller's
+func (s *returnService) restockingFeePercent(ctx context.Context, product *models.Product) (float64, error) {
+	if product.RestockingFeePercent != nil {
+		return *product.RestockingFeePercent, nil
+	}
+
+	seller, err := s.userRepo.GetByID(ctx, product.SellerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get seller: %w", err)
+	}
+	if seller.RestockingFeePercent == nil {
+		return 0, nil
+	}
+	return *seller.RestockingFeePercent, nil
+}
+
+func (s *returnService) GetMyReturns(ctx context.Context, customerID uint, limit, offset int) ([]*models.ReturnRequest, int64, error) {
+	returnRequests, total, err := s.returnRepo.ListByCustomer(ctx, customerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get return requests: %w", err)
+	}
+	return returnRequests, total, nil
+}
+
+func (s *returnService) GetReturns(ctx context.Context, status *models.ReturnStatus, limit, offset int) ([]*models.ReturnRequest, int64, error) {
+	returnRequests, total, err := s.returnRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get return requests: %w", err)
+	}
+	return returnRequests, total, nil
+}
+
+// ApproveReturn refunds the returned units' share of what was paid, less the
+// restocking fee fixed when the return was requested. The
+// return is claimed first so two admins can't both refund it; if the refund
+// fails it goes back to requested to be tried again.
+func (s *returnService) ApproveReturn(ctx context.Context, id, adminID uint, req *models.ReturnResolveRequest) (*models.ReturnRequest, error) {
+	returnRequest, order, err := s.getOpenReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.PaymentStatus != models.PaymentStatusPaid || order.PaymentID == nil {
+		return nil, ErrReturnNotRefundable
+	}
+
+	claimed, err := s.returnRepo.Transition(ctx, id, models.ReturnStatusRequested, models.ReturnStatusApproved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve return request: %w", err)
+	}
+	if !claimed {
+		return nil, ErrReturnClosed
+	}
+
+	item := order.FindItem(returnRequest.OrderItemID)
+	if item == nil {
+		return nil, ErrOrderItemNotFound
+	}
+	amount, fee := returnRequest.SplitRefund(order.ItemRefundAmount(item, returnRequest.Quantity))
+
+	if err := refundPayment(s.paymentSvc, order, *order.PaymentID, amount); err != nil {
+		if _, revertErr := s.returnRepo.Transition(ctx, id, models.ReturnStatusApproved, models.ReturnStatusRequested); revertErr != nil {
+			fmt.Printf("Warning: failed to reopen return request %d after refund failure: %v\n", id, revertErr)
+		}
+		return nil, fmt.Errorf("failed to refund return: %w", err)
+	}
+
+	returnRequest.Status = models.ReturnStatusRefunded
+	returnRequest.RefundAmount = amount
+	returnRequest.RestockingFee = fee
+	s.resolve(returnRequest, adminID, req)
+	if err := s.returnRepo.Update(ctx, returnRequest); err != nil {
+		// The money has moved; leave it claimed so it can't be refunded twice
+		return nil, fmt.Errorf("refunded %.2f but failed to record return request %d: %w", amount, id, err)
+	}
+
+	return returnRequest, nil
+}
+
+// RejectReturn closes the return without a refund
+func (s *returnService) RejectReturn(ctx context.Context, id, adminID uint, req *models.ReturnResolveRequest) (*models.ReturnRequest, error) {
+	returnRequest, _, err := s.getOpenReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.returnRepo.Transition(ctx, id, models.ReturnStatusRequested, models.ReturnStatusRejected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject return request: %w", err)
+	}
+	if !claimed {
+		return nil, ErrReturnClosed
+	}
+
+	returnRequest.Status = models.ReturnStatusRejected
+	s.resolve(returnRequest, adminID, req)
+	if err := s.returnRepo.Update(ctx, returnRequest); err != nil {
+		return nil, fmt.Errorf("failed to reject return request: %w", err)
+	}
+
+	return returnRequest, nil
+}
+
+// getOpenReturn loads a return still waiting for a decision, with its order
+func (s *returnService) getOpenReturn(ctx context.Context, id uint) (*models.ReturnRequest, *models.Order, error) {
+	returnRequest, err := s.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrReturnNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get return request: %w", err)
+	}
+	if !returnRequest.IsOpen() {
+		return nil, nil, ErrReturnClosed
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, returnRequest.OrderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return returnRequest, order, nil
+}
+
+func (s *returnService) resolve(returnRequest *models.ReturnRequest, adminID uint, req *models.ReturnResolveRequest) {
+	now := time.Now()
+	returnRequest.ResolvedBy = &adminID
+	returnRequest.ResolvedAt = &now
+	if note := strings.TrimSpace(req.Note); note != "" {
+		returnRequest.ResolutionNote = &note
+	}
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/pkg/payment"
+)
+
+// refundRecorder records refunds, failing them with err when set
+type refundRecorder struct {
+	payment.Service
+
+	mu      sync.Mutex
+	err     error
+	refunds []float64
+}
+
+func (p *refundRecorder) RefundPayment(paymentIntentID string, amount float64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.refunds = append(p.refunds, amount)
+	return nil
+}
+
+// deliveredOrder is a paid order for customer 5, delivered deliveredDaysAgo
+// days ago, with two units of a returnable 10.00 item (ID 10) and a 4.00
+// order discount on its 40.00 subtotal
+func deliveredOrder(deliveredDaysAgo int) *models.Order {
+	paymentID := "pi_returns"
+	deliveredAt := time.Now().AddDate(0, 0, -deliveredDaysAgo)
+	return &models.Order{
+		BaseModel:      models.BaseModel{ID: 1},
+		CustomerID:     5,
+		Status:         models.OrderStatusDelivered,
+		PaymentStatus:  models.PaymentStatusPaid,
+		PaymentID:      &paymentID,
+		DeliveredAt:    &deliveredAt,
+		SubtotalAmount: 40,
+		DiscountAmount: 4,
+		TotalAmount:    36,
+		OrderItems: []models.OrderItem{
+			{BaseModel: models.BaseModel{ID: 10}, Quantity: 2, UnitPrice: 10, TotalPrice: 20, Product: models.Product{Returnable: true}},
+			{BaseModel: models.BaseModel{ID: 11}, Quantity: 1, UnitPrice: 20, TotalPrice: 20, Product: models.Product{Returnable: false}},
+		},
+	}
+}
+
+func newReturnTestService(order *models.Order, payments *refundRecorder, sellers ...*models.User) (*returnService, *fakeReturnRepo) {
+	returns := newFakeReturnRepo()
+	return NewReturnService(returns, newFakeOrderRepo(order), newFakeUserRepo(sellers...), payments).(*returnService), returns
+}
+
+func returnOf(itemID uint, quantity int) *models.ReturnCreateRequest {
+	return &models.ReturnCreateRequest{OrderItemID: itemID, Quantity: quantity, Reason: "Arrived damaged"}
+}
+
+func TestRequestReturnChecksEligibility(t *testing.T) {
+	ctx := context.Background()
+	windowDays := 7
+
+	tests := []struct {
+		name    string
+		order   func() *models.Order
+		itemID  uint
+		userID  uint
+		wantErr error
+	}{
+		{"inside the default window", func() *models.Order { return deliveredOrder(3) }, 10, 5, nil},
+		{"another customer's order", func() *models.Order { return deliveredOrder(3) }, 10, 6, ErrOrderViewForbidden},
+		{"unknown item", func() *models.Order { return deliveredOrder(3) }, 99, 5, ErrOrderItemNotFound},
+		{"non-returnable product", func() *models.Order { return deliveredOrder(3) }, 11, 5, ErrReturnWindowClosed},
+		{"past the default window", func() *models.Order { return deliveredOrder(models.DefaultReturnWindowDays + 1) }, 10, 5, ErrReturnWindowClosed},
+		{"past the product's own window", func() *models.Order {
+			order := deliveredOrder(8)
+			order.OrderItems[0].Product.ReturnWindowDays = &windowDays
+			return order
+		}, 10, 5, ErrReturnWindowClosed},
+		{"not delivered yet", func() *models.Order {
+			order := deliveredOrder(0)
+			order.DeliveredAt = nil
+			order.Status = models.OrderStatusShipped
+			return order
+		}, 10, 5, ErrReturnNotDelivered},
+		{"refunded payment", func() *models.Order {
+			order := deliveredOrder(3)
+			order.PaymentStatus = models.PaymentStatusRefunded
+			return order
+		}, 10, 5, ErrReturnNotRefundable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newReturnTestService(tt.order(), &refundRecorder{})
+
+			returnRequest, err := svc.RequestReturn(ctx, 1, tt.userID, returnOf(tt.itemID, 1))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && returnRequest.Status != models.ReturnStatusRequested {
+				t.Errorf("status = %s, want %s", returnRequest.Status, models.ReturnStatusRequested)
+			}
+		})
+	}
+}
+
+func TestRequestReturnCapsUnitsAcrossRequests(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newReturnTestService(deliveredOrder(3), &refundRecorder{})
+
+	first, err := svc.RequestReturn(ctx, 1, 5, returnOf(10, 1))
+	if err != nil {
+		t.Fatalf("first return: %v", err)
+	}
+	if _, err := svc.RequestReturn(ctx, 1, 5, returnOf(10, 2)); !errors.Is(err, ErrReturnQuantityExceeded) {
+		t.Fatalf("returning 3 of 2 units: err = %v, want ErrReturnQuantityExceeded", err)
+	}
+
+	// Rejected returns give their units back
+	if _, err := svc.RejectReturn(ctx, first.ID, 1, &models.ReturnResolveRequest{Note: "No damage visible in the photos"}); err != nil {
+		t.Fatalf("RejectReturn: %v", err)
+	}
+	if _, err := svc.RequestReturn(ctx, 1, 5, returnOf(10, 2)); err != nil {
+		t.Errorf("returning both units after the rejection: %v", err)
+	}
+}
+
+func TestApproveReturnRefundsUnitsShareOfWhatWasPaid(t *testing.T) {
+	ctx := context.Background()
+	payments := &refundRecorder{}
+	svc, returns := newReturnTestService(deliveredOrder(3), payments)
+
+	returnRequest, err := svc.RequestReturn(ctx, 1, 5, returnOf(10, 1))
+	if err != nil {
+		t.Fatalf("RequestReturn: %v", err)
+	}
+
+	approved, err := svc.ApproveReturn(ctx, returnRequest.ID, 1, &models.ReturnResolveRequest{})
+	if err != nil {
+		t.Fatalf("ApproveReturn: %v", err)
+	}
+
+	// The 20.00 line carries half the 4.00 order discount, so one of its two units refunds 9.00
+	if len(payments.refunds) != 1 || payments.refunds[0] != 9 {
+		t.Errorf("refunds = %v, want [9]", payments.refunds)
+	}
+	if approved.Status != models.ReturnStatusRefunded || approved.RefundAmount != 9 || approved.ResolvedAt == nil {
+		t.Errorf("return = %+v, want refunded 9.00 and resolved", approved)
+	}
+	if got := returns.status(returnRequest.ID); got != models.ReturnStatusRefunded {
+		t.Errorf("stored status = %s, want %s", got, models.ReturnStatusRefunded)
+	}
+
+	if _, err := svc.ApproveReturn(ctx, returnRequest.ID, 1, &models.ReturnResolveRequest{}); !errors.Is(err, ErrReturnClosed) {
+		t.Errorf("approving again: err = %v, want ErrReturnClosed", err)
+	}
+	if len(payments.refunds) != 1 {
+		t.Errorf("refunded %d times, want once", len(payments.refunds))
+	}
+}
+
+func TestConcurrentApprovalsRefundOnce(t *testing.T) {
+	ctx := context.Background()
+	payments := &refundRecorder{}
+	svc, _ := newReturnTestService(deliveredOrder(3), payments)
+
+	returnRequest, err := svc.RequestReturn(ctx, 1, 5, returnOf(10, 2))
+	if err != nil {
+		t.Fatalf("RequestReturn: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			svc.ApproveReturn(ctx, returnRequest.ID, 1, &models.ReturnResolveRequest{})
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if len(payments.refunds) != 1 || payments.refunds[0] != 18 {
+		t.Errorf("refunds = %v, want a single 18.00 refund", payments.refunds)
+	}
+}
+
+func TestApproveReturnReopensWhenRefundFails(t *testing.T) {
+	ctx := context.Background()
+	payments := &refundRecorder{err: errors.New("processor unavailable")}
+	svc, returns := newReturnTestService(deliveredOrder(3), payments)
+
+	returnRequest, err := svc.RequestReturn(ctx, 1, 5, returnOf(10, 1))
+	if err != nil {
+		t.Fatalf("RequestReturn: %v", err)
+	}
+
+	if _, err := svc.ApproveReturn(ctx, returnRequest.ID, 1, &models.ReturnResolveRequest{}); err == nil {
+		t.Fatal("expected the refund failure to be returned")
+	}
+	if got := returns.status(returnRequest.ID); got != models.ReturnStatusRequested {
+		t.Errorf("status = %s, want the return reopened as %s", got, models.ReturnStatusRequested)
+	}
+
+	payments.err = nil
+	if _, err := svc.ApproveReturn(ctx, returnRequest.ID, 1, &models.ReturnResolveRequest{}); err != nil {
+		t.Errorf("retrying the approval: %v", err)
+	}
+}
+
+func TestApproveReturnKeepsRestockingFeeUnlessDefective(t *testing.T) {
+	ctx := context.Background()
+	sellerFee, productFee := 10.0, 20.0
+
+	tests := []struct {
+		name       string
+		productFee *float64
+		defective  bool
+		wantRefund float64
+		wantFee    float64
+	}{
+		{name: "seller's fee", wantRefund: 8.1, wantFee: 0.9},
+		{name: "product overrides seller", productFee: &productFee, wantRefund: 7.2, wantFee: 1.8},
+		{name: "defective is exempt", productFee: &productFee, defective: true, wantRefund: 9, wantFee: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := deliveredOrder(3)
+			order.OrderItems[0].Product.SellerID = 2
+			order.OrderItems[0].Product.RestockingFeePercent = tt.productFee
+			seller := &models.User{BaseModel: models.BaseModel{ID: 2}, RestockingFeePercent: &sellerFee}
+			payments := &refundRecorder{}
+			svc, _ := newReturnTestService(order, payments, seller)
+
+			req := returnOf(10, 1)
+			req.Defective = tt.defective
+			returnRequest, err := svc.RequestReturn(ctx, 1, 5, req)
+			if err != nil {
+				t.Fatalf("RequestReturn: %v", err)
+			}
+
+			approved, err := svc.ApproveReturn(ctx, returnRequest.ID, 1, &models.ReturnResolveRequest{})
+			if err != nil {
+				t.Fatalf("ApproveReturn: %v", err)
+			}
+			// One unit is worth 9.00 after its share of the order discount
+			if len(payments.refunds) != 1 || payments.refunds[0] != tt.wantRefund {
+				t.Errorf("refunds = %v, want [%.2f]", payments.refunds, tt.wantRefund)
+			}
+			if approved.RefundAmount != tt.wantRefund || approved.RestockingFee != tt.wantFee {
+				t.Errorf("refund = %.2f, fee = %.2f, want %.2f and %.2f", approved.RefundAmount, approved.RestockingFee, tt.wantRefund, tt.wantFee)
+			}
+		})
+	}
+}
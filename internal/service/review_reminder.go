@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+)
+
+const reviewReminderBatchSize = 100
+
+// ReviewReminder emails customers a set number of days after an order is
+// delivered, inviting them to review the products from it they haven't
+// reviewed yet. Each order is reminded at most once: it is marked as soon as
+// it has been handled, whether the email went out, the customer opted out or
+// there was nothing left to review.
+type ReviewReminder struct {
+	orderRepo     repository.OrderRepository
+	reviewService ReviewService
+	emailService  EmailService
+
+	delay time.Duration
+}
+
+func NewReviewReminder(orderRepo repository.OrderRepository, reviewService ReviewService, emailService EmailService, cfg *config.Config) *ReviewReminder {
+	return &ReviewReminder{
+		orderRepo:     orderRepo,
+		reviewService: reviewService,
+		emailService:  emailService,
+		delay:         time.Duration(cfg.Order.ReviewReminderDays) * 24 * time.Hour,
+	}
+}
+
+// Start sends due reminders every interval until ctx is done. A non-positive
+// interval or reminder delay disables the reminders.
+func (r *ReviewReminder) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 || r.delay <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				count, err := r.SendDue(ctx)
+				if err != nil {
+					fmt.Printf("Warning: review reminder run failed: %v\n", err)
+					continue
+				}
+				if count > 0 {
+					fmt.Printf("Sent %d review reminders\n", count)
+				}
+			}
+		}
+	}()
+}
+
+// SendDue handles one batch of orders due a reminder and returns how many
+// emails were sent; anything beyond the batch is picked up on the next run
+func (r *ReviewReminder) SendDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	orders, err := r.orderRepo.GetDueReviewReminders(ctx, now.Add(-r.delay), reviewReminderBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get orders due a review reminder: %w", err)
+	}
+	if len(orders) == 0 {
+		return 0, nil
+	}
+
+	sent := 0
+	ids := make([]uint, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+
+		customer := &order.Customer
+		if customer.ID == 0 || !customer.IsActive || customer.ReviewRemindersOptOut {
+			continue
+		}
+
+		products := r.reviewableProducts(ctx, order)
+		if len(products) == 0 {
+			continue
+		}
+
+		// A failed send isn't retried, so a bad address can't hold up the queue
+		if err := r.emailService.SendReviewReminder(ctx, customer, order, products); err != nil {
+			fmt.Printf("Warning: failed to send review reminder for order %s: %v\n", order.OrderNumber, err)
+			continue
+		}
+		sent++
+	}
+
+	if err := r.orderRepo.MarkReviewRemindersSent(ctx, ids, now); err != nil {
+		return sent, fmt.Errorf("failed to mark review reminders sent: %w", err)
+	}
+
+	return sent, nil
+}
+
+// reviewableProducts returns the order's products the customer can still review
+func (r *ReviewReminder) reviewableProducts(ctx context.Context, order *models.Order) []*models.Product {
+	seen := make(map[uint]bool)
+	var products []*models.Product
+	for i := range order.OrderItems {
+		item := &order.OrderItems[i]
+		if seen[item.ProductID] || item.Product.ID == 0 {
+			continue
+		}
+		seen[item.ProductID] = true
+
+		canReview, err := r.reviewService.CanUserReview(ctx, order.CustomerID, item.ProductID)
+		if err != nil {
+			fmt.Printf("Warning: failed to check review eligibility for product %d: %v\n", item.ProductID, err)
+			continue
+		}
+		if canReview {
+			products = append(products, &item.Product)
+		}
+	}
+	return products
+}
@@ -2,28 +2,71 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
+const (
+	reviewSummaryCachePrefix = "reviews:summary:"
+	reviewSummaryCacheTTL    = 6 * time.Hour
+	reviewSummaryKeywords    = 10
+	minKeywordLength         = 3
+)
+
+// reviewStopWords are common words that carry no product signal
+var reviewStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "any": true, "can": true, "had": true, "her": true,
+	"was": true, "one": true, "our": true, "out": true, "has": true, "have": true,
+	"him": true, "his": true, "how": true, "its": true, "may": true, "new": true,
+	"now": true, "old": true, "see": true, "two": true, "way": true, "who": true,
+	"did": true, "get": true, "got": true, "let": true, "put": true, "say": true,
+	"she": true, "too": true, "use": true, "this": true, "that": true, "with": true,
+	"from": true, "they": true, "them": true, "then": true, "than": true, "were": true,
+	"been": true, "being": true, "would": true, "could": true, "should": true, "will": true,
+	"just": true, "very": true, "really": true, "also": true, "only": true, "what": true,
+	"when": true, "which": true, "there": true, "their": true, "these": true, "those": true,
+	"into": true, "about": true, "after": true, "before": true, "because": true, "while": true,
+	"some": true, "more": true, "most": true, "much": true, "many": true, "such": true,
+	"even": true, "still": true, "here": true, "does": true, "doesn't": true, "didn't": true,
+	"don't": true, "isn't": true, "wasn't": true, "product": true, "item": true, "bought": true,
+	"buy": true, "purchase": true, "purchased": true, "i've": true, "i'm": true, "it's": true,
+}
+
 type reviewService struct {
-	reviewRepo  repository.ReviewRepository
-	productRepo repository.ProductRepository
-	userRepo    repository.UserRepository
+	reviewRepo   repository.ReviewRepository
+	productRepo  repository.ProductRepository
+	userRepo     repository.UserRepository
+	orderRepo    repository.OrderRepository
+	redis        *redis.Client
+	ratingQueue  *RatingQueue
 }
 
 func NewReviewService(
 	reviewRepo repository.ReviewRepository,
 	productRepo repository.ProductRepository,
 	userRepo repository.UserRepository,
+	orderRepo repository.OrderRepository,
+	redisClient *redis.Client,
+	ratingQueue *RatingQueue,
 ) ReviewService {
 	return &reviewService{
-		reviewRepo:  reviewRepo,
-		productRepo: productRepo,
-		userRepo:    userRepo,
+		reviewRepo:   reviewRepo,
+		productRepo:  productRepo,
+		userRepo:     userRepo,
+		orderRepo:    orderRepo,
+		redis:        redisClient,
+		ratingQueue:  ratingQueue,
 	}
 }
 
@@ -40,49 +83,86 @@ func (s *reviewService) CreateReview(ctx context.Context, req *models.CreateRevi
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	// Check if user can review this product (has purchased it)
-	canReview, err := s.reviewRepo.CheckUserCanReview(ctx, userID, req.ProductID)
+	// A review tied to an order is a verified purchase of it; otherwise the
+	// user just needs to have received the product at some point
+	if req.OrderID != nil {
+		if err := s.checkReviewOrder(ctx, *req.OrderID, userID, req.ProductID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Each delivered purchase allows one review, and a review without an
+	// order uses up one of them
+	purchases, err := s.reviewRepo.CountDeliveredPurchases(ctx, userID, req.ProductID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check review eligibility: %w", err)
 	}
-
-	if !canReview {
-		return nil, errors.New("you can only review products you have purchased and received")
+	if purchases == 0 {
+		return nil, ErrReviewNotPurchased
 	}
 
-	// Check if user has already reviewed this product
-	existingReview, err := s.reviewRepo.GetByUserAndProduct(ctx, userID, req.ProductID)
+	// Check if user has already reviewed this product, from this order
+	existingReview, err := s.reviewRepo.GetByUserProductAndOrder(ctx, userID, req.ProductID, req.OrderID)
 	if err == nil && existingReview != nil {
-		return nil, errors.New("you have already reviewed this product")
+		return nil, ErrAlreadyReviewed
 	}
 
 	// Validate rating
 	if req.Rating < 1 || req.Rating > 5 {
-		return nil, errors.New("rating must be between 1 and 5")
+		return nil, ErrInvalidRating
 	}
 
 	review := &models.Review{
-		UserID:    userID,
-		ProductID: req.ProductID,
-		Rating:    req.Rating,
-		Comment:   req.Comment,
-		User:      *user,
-		Product:   *product,
+		UserID:     userID,
+		ProductID:  req.ProductID,
+		OrderID:    req.OrderID,
+		Rating:     req.Rating,
+		Comment:    req.Comment,
+		IsVerified: req.OrderID != nil,
+		User:       *user,
+		Product:    *product,
 	}
 
-	if err := s.reviewRepo.Create(ctx, review); err != nil {
+	created, err := s.reviewRepo.CreateWithinPurchases(ctx, review, purchases)
+	if err != nil {
 		return nil, fmt.Errorf("failed to create review: %w", err)
 	}
-
-	// Update product rating after creating review
-	if err := s.updateProductRating(ctx, req.ProductID); err != nil {
-		// Log error but don't fail the review creation
-		fmt.Printf("Warning: failed to update product rating: %v\n", err)
+	if !created {
+		return nil, ErrAlreadyReviewed
 	}
 
+	// Queue the product rating to be recomputed after creating the review
+	s.ratingQueue.Enqueue(ctx, req.ProductID)
+	s.invalidateReviewSummary(ctx, req.ProductID)
+
 	return review, nil
 }
 
+// checkReviewOrder checks that the order is the user's and has delivered the
+// product. Someone else's order is reported as a mismatch, like one without the
+// product, so orders can't be probed.
+func (s *reviewService) checkReviewOrder(ctx context.Context, orderID, userID, productID uint) error {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReviewOrderMismatch
+		}
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.CustomerID != userID {
+		return ErrReviewOrderMismatch
+	}
+
+	item := order.ItemForProduct(productID)
+	if item == nil {
+		return ErrReviewOrderMismatch
+	}
+	if order.Status != models.OrderStatusDelivered && order.ItemDeliveredAt(item) == nil {
+		return ErrReviewNotPurchased
+	}
+	return nil
+}
+
 func (s *reviewService) GetReview(ctx context.Context, id uint) (*models.Review, error) {
 	review, err := s.reviewRepo.GetByID(ctx, id)
 	if err != nil {
@@ -92,14 +172,14 @@ func (s *reviewService) GetReview(ctx context.Context, id uint) (*models.Review,
 	return review, nil
 }
 
-func (s *reviewService) GetProductReviews(ctx context.Context, productID uint, limit, offset int) ([]*models.Review, error) {
+func (s *reviewService) GetProductReviews(ctx context.Context, productID uint, sortBy models.ReviewSort, limit, offset int) ([]*models.Review, error) {
 	// Validate product exists
 	_, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	reviews, err := s.reviewRepo.GetByProductID(ctx, productID, limit, offset)
+	reviews, err := s.reviewRepo.GetByProductID(ctx, productID, sortBy, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get product reviews: %w", err)
 	}
@@ -122,6 +202,21 @@ func (s *reviewService) GetUserReviews(ctx context.Context, userID uint, limit,
 	return reviews, nil
 }
 
+// GetSellerReviews lists the reviews of the seller's products, marking those
+// still waiting for the seller's response
+func (s *reviewService) GetSellerReviews(ctx context.Context, sellerID uint, filter *models.SellerReviewFilter, limit, offset int) ([]models.SellerReview, int64, error) {
+	reviews, total, err := s.reviewRepo.GetBySellerID(ctx, sellerID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get seller reviews: %w", err)
+	}
+
+	items := make([]models.SellerReview, len(reviews))
+	for i, review := range reviews {
+		items[i] = models.SellerReview{Review: review, AwaitingResponse: review.AwaitingSellerResponse()}
+	}
+	return items, total, nil
+}
+
 func (s *reviewService) UpdateReview(ctx context.Context, id uint, req *models.UpdateReviewRequest, userID uint) (*models.Review, error) {
 	review, err := s.reviewRepo.GetByID(ctx, id)
 	if err != nil {
@@ -130,13 +225,22 @@ func (s *reviewService) UpdateReview(ctx context.Context, id uint, req *models.U
 
 	// Check if user owns this review
 	if review.UserID != userID {
-		return nil, errors.New("unauthorized to update this review")
+		return nil, ErrReviewUpdateForbidden
+	}
+
+	// Keep what the review said before so moderators can compare
+	edit := &models.ReviewEdit{
+		ReviewID:        review.ID,
+		PreviousRating:  review.Rating,
+		PreviousTitle:   review.Title,
+		PreviousComment: review.Comment,
+		EditedBy:        userID,
 	}
 
 	// Update fields if provided
 	if req.Rating != nil {
 		if *req.Rating < 1 || *req.Rating > 5 {
-			return nil, errors.New("rating must be between 1 and 5")
+			return nil, ErrInvalidRating
 		}
 		review.Rating = *req.Rating
 	}
@@ -145,19 +249,41 @@ func (s *reviewService) UpdateReview(ctx context.Context, id uint, req *models.U
 		review.Comment = *req.Comment
 	}
 
-	if err := s.reviewRepo.Update(ctx, review); err != nil {
-		return nil, fmt.Errorf("failed to update review: %w", err)
+	// Nothing changed, so there's no edit to record
+	if review.Rating == edit.PreviousRating && review.Comment == edit.PreviousComment {
+		return review, nil
 	}
 
-	// Update product rating after updating review
-	if err := s.updateProductRating(ctx, review.ProductID); err != nil {
-		// Log error but don't fail the review update
-		fmt.Printf("Warning: failed to update product rating: %v\n", err)
+	now := time.Now()
+	review.EditedAt = &now
+	if err := s.reviewRepo.UpdateWithEdit(ctx, review, edit); err != nil {
+		return nil, fmt.Errorf("failed to update review: %w", err)
 	}
 
+	// Queue the product rating to be recomputed after updating the review
+	s.ratingQueue.Enqueue(ctx, review.ProductID)
+	s.invalidateReviewSummary(ctx, review.ProductID)
+
 	return review, nil
 }
 
+// GetReviewHistory returns what the review said before each edit, oldest first
+func (s *reviewService) GetReviewHistory(ctx context.Context, id uint) ([]*models.ReviewEdit, error) {
+	if _, err := s.reviewRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+
+	edits, err := s.reviewRepo.GetEdits(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review history: %w", err)
+	}
+
+	return edits, nil
+}
+
 func (s *reviewService) DeleteReview(ctx context.Context, id uint, userID uint, userRole models.UserRole) error {
 	review, err := s.reviewRepo.GetByID(ctx, id)
 	if err != nil {
@@ -166,7 +292,7 @@ func (s *reviewService) DeleteReview(ctx context.Context, id uint, userID uint,
 
 	// Check authorization
 	if userRole != models.RoleAdmin && review.UserID != userID {
-		return errors.New("unauthorized to delete this review")
+		return ErrReviewDeleteForbidden
 	}
 
 	productID := review.ProductID
@@ -175,18 +301,16 @@ func (s *reviewService) DeleteReview(ctx context.Context, id uint, userID uint,
 		return fmt.Errorf("failed to delete review: %w", err)
 	}
 
-	// Update product rating after deleting review
-	if err := s.updateProductRating(ctx, productID); err != nil {
-		// Log error but don't fail the review deletion
-		fmt.Printf("Warning: failed to update product rating: %v\n", err)
-	}
+	// Queue the product rating to be recomputed after deleting the review
+	s.ratingQueue.Enqueue(ctx, productID)
+	s.invalidateReviewSummary(ctx, productID)
 
 	return nil
 }
 
 func (s *reviewService) GetReviewsByRating(ctx context.Context, rating int, limit, offset int) ([]*models.Review, error) {
 	if rating < 1 || rating > 5 {
-		return nil, errors.New("rating must be between 1 and 5")
+		return nil, ErrInvalidRating
 	}
 
 	reviews, err := s.reviewRepo.GetByRating(ctx, rating, limit, offset)
@@ -247,6 +371,64 @@ func (s *reviewService) GetProductReviewStats(ctx context.Context, productID uin
 	}, nil
 }
 
+func (s *reviewService) GetReviewSummary(ctx context.Context, productID uint) (*models.ReviewSummary, error) {
+	cacheKey := fmt.Sprintf("%s%d", reviewSummaryCachePrefix, productID)
+	if cached, err := s.redis.Get(ctx, cacheKey).Bytes(); err == nil {
+		var summary models.ReviewSummary
+		if json.Unmarshal(cached, &summary) == nil {
+			return &summary, nil
+		}
+	}
+
+	// Validate product exists
+	_, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	reviews, err := s.reviewRepo.GetApprovedTextByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product reviews: %w", err)
+	}
+
+	summary := buildReviewSummary(productID, reviews)
+
+	if data, err := json.Marshal(summary); err == nil {
+		if err := s.redis.Set(ctx, cacheKey, data, reviewSummaryCacheTTL).Err(); err != nil {
+			fmt.Printf("Warning: failed to cache review summary: %v\n", err)
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *reviewService) BulkModerate(ctx context.Context, req *models.BulkModerateRequest, adminID uint) (*models.BulkModerateResponse, error) {
+	results, productIDs, err := s.reviewRepo.BulkModerate(ctx, req.ReviewIDs, req.Action, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate reviews: %w", err)
+	}
+
+	// The queue recomputes each affected product once rather than per review
+	s.ratingQueue.Enqueue(ctx, productIDs...)
+	for _, productID := range productIDs {
+		s.invalidateReviewSummary(ctx, productID)
+	}
+
+	response := &models.BulkModerateResponse{
+		Action:  req.Action,
+		Results: results,
+	}
+	for _, result := range results {
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	return response, nil
+}
+
 func (s *reviewService) CanUserReview(ctx context.Context, userID, productID uint) (bool, error) {
 	canReview, err := s.reviewRepo.CheckUserCanReview(ctx, userID, productID)
 	if err != nil {
@@ -264,23 +446,96 @@ func (s *reviewService) CanUserReview(ctx context.Context, userID, productID uin
 	return canReview, nil
 }
 
-func (s *reviewService) updateProductRating(ctx context.Context, productID uint) error {
-	// Get average rating
-	avgRating, err := s.reviewRepo.GetAverageRatingByProductID(ctx, productID)
-	if err != nil {
-		return fmt.Errorf("failed to get average rating: %w", err)
+// invalidateReviewSummary drops the cached summary so the next read rebuilds it
+func (s *reviewService) invalidateReviewSummary(ctx context.Context, productID uint) {
+	cacheKey := fmt.Sprintf("%s%d", reviewSummaryCachePrefix, productID)
+	if err := s.redis.Del(ctx, cacheKey).Err(); err != nil {
+		fmt.Printf("Warning: failed to invalidate review summary: %v\n", err)
 	}
+}
 
-	// Get review count
-	reviewCount, err := s.reviewRepo.CountByProductID(ctx, productID)
-	if err != nil {
-		return fmt.Errorf("failed to get review count: %w", err)
+// buildReviewSummary buckets reviews by rating and counts keywords. Each
+// keyword is counted at most once per review so one long review can't dominate.
+func buildReviewSummary(productID uint, reviews []*models.Review) *models.ReviewSummary {
+	summary := &models.ReviewSummary{
+		ProductID:    productID,
+		TotalReviews: len(reviews),
+		GeneratedAt:  time.Now(),
+	}
+
+	all := make(map[string]int)
+	positive := make(map[string]int)
+	negative := make(map[string]int)
+
+	for _, review := range reviews {
+		keywords := extractKeywords(review.Title + " " + review.Comment)
+		for _, keyword := range keywords {
+			all[keyword]++
+		}
+
+		switch {
+		case review.Rating >= 4:
+			summary.PositiveCount++
+			for _, keyword := range keywords {
+				positive[keyword]++
+			}
+		case review.Rating <= 2:
+			summary.NegativeCount++
+			for _, keyword := range keywords {
+				negative[keyword]++
+			}
+		default:
+			summary.NeutralCount++
+		}
 	}
 
-	// Update product rating
-	if err := s.productRepo.UpdateRating(ctx, productID, avgRating, int(reviewCount)); err != nil {
-		return fmt.Errorf("failed to update product rating: %w", err)
+	if summary.TotalReviews > 0 {
+		summary.PositiveRatio = float64(summary.PositiveCount) / float64(summary.TotalReviews)
+		summary.NegativeRatio = float64(summary.NegativeCount) / float64(summary.TotalReviews)
 	}
 
-	return nil
+	summary.TopKeywords = topKeywords(all, reviewSummaryKeywords)
+	summary.Pros = topKeywords(positive, reviewSummaryKeywords)
+	summary.Cons = topKeywords(negative, reviewSummaryKeywords)
+
+	return summary
+}
+
+// extractKeywords returns the distinct non-stop-words in text
+func extractKeywords(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	seen := make(map[string]bool, len(words))
+	keywords := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+		if len([]rune(word)) < minKeywordLength || reviewStopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
+
+// topKeywords returns the most frequent keywords, ties broken alphabetically
+func topKeywords(counts map[string]int, limit int) []models.KeywordCount {
+	keywords := make([]models.KeywordCount, 0, len(counts))
+	for keyword, count := range counts {
+		keywords = append(keywords, models.KeywordCount{Keyword: keyword, Count: count})
+	}
+
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Count != keywords[j].Count {
+			return keywords[i].Count > keywords[j].Count
+		}
+		return keywords[i].Keyword < keywords[j].Keyword
+	})
+
+	if len(keywords) > limit {
+		keywords = keywords[:limit]
+	}
+	return keywords
 }
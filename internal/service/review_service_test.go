@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+func reviewableOrder(status models.OrderStatus) *models.Order {
+	return &models.Order{
+		BaseModel:  models.BaseModel{ID: 1},
+		CustomerID: 5,
+		Status:     status,
+		OrderItems: []models.OrderItem{{BaseModel: models.BaseModel{ID: 10}, ProductID: 7, SellerID: 3, Quantity: 1}},
+	}
+}
+
+func TestCheckReviewOrder(t *testing.T) {
+	deliveredAt := time.Now()
+	splitDelivered := reviewableOrder(models.OrderStatusShipped)
+	splitDelivered.Fulfillments = []models.OrderFulfillment{{SellerID: 3, DeliveredAt: &deliveredAt}}
+
+	tests := []struct {
+		name      string
+		order     *models.Order
+		userID    uint
+		productID uint
+		want      error
+	}{
+		{"delivered order with the product", reviewableOrder(models.OrderStatusDelivered), 5, 7, nil},
+		{"seller's part of a split order delivered", splitDelivered, 5, 7, nil},
+		{"someone else's order", reviewableOrder(models.OrderStatusDelivered), 6, 7, ErrReviewOrderMismatch},
+		{"product not in the order", reviewableOrder(models.OrderStatusDelivered), 5, 8, ErrReviewOrderMismatch},
+		{"not delivered yet", reviewableOrder(models.OrderStatusShipped), 5, 7, ErrReviewNotPurchased},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &reviewService{orderRepo: newFakeOrderRepo(tt.order)}
+			err := svc.checkReviewOrder(context.Background(), 1, tt.userID, tt.productID)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	svc := &reviewService{orderRepo: newFakeOrderRepo()}
+	if err := svc.checkReviewOrder(context.Background(), 1, 5, 7); !errors.Is(err, ErrReviewOrderMismatch) {
+		t.Errorf("missing order: err = %v, want ErrReviewOrderMismatch", err)
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	popularSearchesKey      = "search:popular"
+	suggestionsCachePrefix  = "search:suggest:"
+	suggestionsCacheTTL     = 5 * time.Minute
+	maxSuggestions          = 10
+	maxTrackedSearchTerms   = 1000
+	popularSearchesScanSize = 200
+	minSearchTermLength     = 2
+	maxSearchTermLength     = 100
+	searchLogBufferSize     = 1000
+	searchLogBatchSize      = 100
+	searchLogFlushInterval  = 5 * time.Second
+	searchReportLimit       = 20
+)
+
+type searchService struct {
+	productRepo   repository.ProductRepository
+	searchLogRepo repository.SearchLogRepository
+	redis         *redis.Client
+	logs          chan models.SearchLog
+}
+
+func NewSearchService(productRepo repository.ProductRepository, searchLogRepo repository.SearchLogRepository, redisClient *redis.Client) SearchService {
+	s := &searchService{
+		productRepo:   productRepo,
+		searchLogRepo: searchLogRepo,
+		redis:         redisClient,
+		logs:          make(chan models.SearchLog, searchLogBufferSize),
+	}
+	go s.runLogWriter()
+	return s
+}
+
+func (s *searchService) GetSuggestions(ctx context.Context, prefix string, limit int) (*models.SearchSuggestionsResponse, error) {
+	prefix = normalizeSearchTerm(prefix)
+	if limit <= 0 || limit > maxSuggestions {
+		limit = maxSuggestions
+	}
+
+	response := &models.SearchSuggestionsResponse{
+		Query:    prefix,
+		Products: []string{},
+		Popular:  []string{},
+	}
+	if prefix == "" {
+		return response, nil
+	}
+
+	cacheKey := fmt.Sprintf("%s%d:%s", suggestionsCachePrefix, limit, prefix)
+	if cached, err := s.redis.Get(ctx, cacheKey).Bytes(); err == nil {
+		if json.Unmarshal(cached, response) == nil {
+			return response, nil
+		}
+	}
+
+	names, err := s.productRepo.SuggestNames(ctx, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product suggestions: %w", err)
+	}
+	if names != nil {
+		response.Products = names
+	}
+
+	popular, err := s.redis.ZRevRange(ctx, popularSearchesKey, 0, popularSearchesScanSize-1).Result()
+	if err != nil && err != redis.Nil {
+		fmt.Printf("Warning: failed to get popular searches: %v\n", err)
+	}
+	for _, term := range popular {
+		if len(response.Popular) >= limit {
+			break
+		}
+		if strings.HasPrefix(term, prefix) {
+			response.Popular = append(response.Popular, term)
+		}
+	}
+
+	if data, err := json.Marshal(response); err == nil {
+		if err := s.redis.Set(ctx, cacheKey, data, suggestionsCacheTTL).Err(); err != nil {
+			fmt.Printf("Warning: failed to cache search suggestions: %v\n", err)
+		}
+	}
+
+	return response, nil
+}
+
+func (s *searchService) RecordSearch(ctx context.Context, term string, resultCount int, userID *uint) {
+	term = normalizeSearchTerm(term)
+	if term == "" || len(term) > maxSearchTermLength {
+		return
+	}
+
+	// Queue the log entry; the writer persists it in batches
+	select {
+	case s.logs <- models.SearchLog{Query: term, ResultCount: resultCount, UserID: userID, CreatedAt: time.Now()}:
+	default:
+		fmt.Printf("Warning: search log buffer full, dropping query %q\n", term)
+	}
+
+	// Only suggest terms that actually find something
+	if len(term) < minSearchTermLength || resultCount == 0 {
+		return
+	}
+
+	pipe := s.redis.Pipeline()
+	pipe.ZIncrBy(ctx, popularSearchesKey, 1, term)
+	// Keep only the most popular terms
+	pipe.ZRemRangeByRank(ctx, popularSearchesKey, 0, -maxTrackedSearchTerms-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		fmt.Printf("Warning: failed to record search term: %v\n", err)
+	}
+}
+
+func (s *searchService) GetSearchAnalytics(ctx context.Context, startDate, endDate time.Time) (*models.SearchAnalytics, error) {
+	total, err := s.searchLogRepo.CountSearches(ctx, startDate, endDate, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count searches: %w", err)
+	}
+
+	zeroResults, err := s.searchLogRepo.CountSearches(ctx, startDate, endDate, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count zero-result searches: %w", err)
+	}
+
+	topQueries, err := s.searchLogRepo.GetTopQueries(ctx, startDate, endDate, false, searchReportLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top queries: %w", err)
+	}
+
+	topZeroResultQueries, err := s.searchLogRepo.GetTopQueries(ctx, startDate, endDate, true, searchReportLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top zero-result queries: %w", err)
+	}
+
+	return &models.SearchAnalytics{
+		StartDate:            startDate,
+		EndDate:              endDate,
+		TotalSearches:        total,
+		ZeroResultSearches:   zeroResults,
+		TopQueries:           topQueries,
+		TopZeroResultQueries: topZeroResultQueries,
+	}, nil
+}
+
+// runLogWriter flushes queued search logs when a batch fills up or on a timer
+func (s *searchService) runLogWriter() {
+	ticker := time.NewTicker(searchLogFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.SearchLog, 0, searchLogBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.searchLogRepo.CreateBatch(context.Background(), batch); err != nil {
+			fmt.Printf("Warning: failed to write %d search logs: %v\n", len(batch), err)
+		}
+		batch = make([]models.SearchLog, 0, searchLogBatchSize)
+	}
+
+	for {
+		select {
+		case entry := <-s.logs:
+			batch = append(batch, entry)
+			if len(batch) >= searchLogBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// normalizeSearchTerm lowercases and collapses whitespace in a search term
+func normalizeSearchTerm(term string) string {
+	return strings.Join(strings.Fields(strings.ToLower(term)), " ")
+}
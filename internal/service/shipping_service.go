@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
+	"gorm.io/gorm"
+)
+
+type shippingService struct {
+	zoneRepo repository.ShippingZoneRepository
+	config   *config.Config
+}
+
+func NewShippingService(zoneRepo repository.ShippingZoneRepository, cfg *config.Config) ShippingService {
+	return &shippingService{zoneRepo: zoneRepo, config: cfg}
+}
+
+// Quote returns the shipping cost of a parcel of the given weight to dest, or
+// 0 once subtotal meets the free-shipping threshold. A threshold of 0
+// disables free shipping. The cost comes from the matching zone's weight
+// bracket, falling back to the default zone and then to the flat rate.
+func (s *shippingService) Quote(ctx context.Context, subtotal float64, dest models.ShippingDestination, weight float64) *models.ShippingQuote {
+	quote := &models.ShippingQuote{
+		Cost:                  s.config.Shipping.FlatRate,
+		FreeShippingThreshold: s.config.Shipping.FreeShippingThreshold,
+	}
+
+	if zone, rate := s.resolveRate(ctx, dest, weight); rate != nil {
+		quote.Zone = zone.Name
+		quote.Cost = rate.Cost
+	}
+
+	if subtotal <= 0 {
+		quote.Cost = 0
+	}
+
+	if quote.FreeShippingThreshold <= 0 {
+		return quote
+	}
+
+	if subtotal >= quote.FreeShippingThreshold {
+		quote.Cost = 0
+		quote.QualifiesForFree = true
+		return quote
+	}
+
+	quote.AmountToFreeShipping = (money.FromFloat(quote.FreeShippingThreshold) - money.FromFloat(subtotal)).Float()
+	return quote
+}
+
+// resolveRate finds the zone covering dest, most specific first, and its
+// bracket for the weight. Unmatched destinations use the default zone. A zone
+// without a bracket for the weight, or a failure loading zones, leaves the
+// flat rate in place.
+func (s *shippingService) resolveRate(ctx context.Context, dest models.ShippingDestination, weight float64) (*models.ShippingZone, *models.ShippingRate) {
+	zones, err := s.zoneRepo.GetAll(ctx)
+	if err != nil {
+		fmt.Printf("Warning: failed to load shipping zones, using flat rate: %v\n", err)
+		return nil, nil
+	}
+
+	var zone, defaultZone *models.ShippingZone
+	bestMatch := 0
+	for _, z := range zones {
+		if match := z.Match(dest); match > bestMatch {
+			zone, bestMatch = z, match
+		}
+		if z.IsDefault {
+			defaultZone = z
+		}
+	}
+	if zone == nil {
+		zone = defaultZone
+	}
+	if zone == nil {
+		return nil, nil
+	}
+
+	return zone, zone.RateFor(weight)
+}
+
+func (s *shippingService) CreateZone(ctx context.Context, req *models.ShippingZoneRequest) (*models.ShippingZone, error) {
+	zone := &models.ShippingZone{}
+	if err := applyShippingZoneRequest(zone, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.zoneRepo.Create(ctx, zone); err != nil {
+		return nil, fmt.Errorf("failed to create shipping zone: %w", err)
+	}
+
+	return zone, nil
+}
+
+func (s *shippingService) GetZone(ctx context.Context, id uint) (*models.ShippingZone, error) {
+	zone, err := s.zoneRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShippingZoneNotFound
+		}
+		return nil, fmt.Errorf("failed to get shipping zone: %w", err)
+	}
+	return zone, nil
+}
+
+func (s *shippingService) GetZones(ctx context.Context) ([]*models.ShippingZone, error) {
+	zones, err := s.zoneRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipping zones: %w", err)
+	}
+	return zones, nil
+}
+
+func (s *shippingService) UpdateZone(ctx context.Context, id uint, req *models.ShippingZoneRequest) (*models.ShippingZone, error) {
+	zone, err := s.GetZone(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyShippingZoneRequest(zone, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.zoneRepo.Update(ctx, zone); err != nil {
+		return nil, fmt.Errorf("failed to update shipping zone: %w", err)
+	}
+
+	return zone, nil
+}
+
+func (s *shippingService) DeleteZone(ctx context.Context, id uint) error {
+	if _, err := s.GetZone(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.zoneRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete shipping zone: %w", err)
+	}
+	return nil
+}
+
+// applyShippingZoneRequest validates the rate table and copies the request
+// onto the zone. Brackets are stored in weight order and must not overlap;
+// gaps are allowed and fall back to the flat rate.
+func applyShippingZoneRequest(zone *models.ShippingZone, req *models.ShippingZoneRequest) error {
+	countries := models.NormalizeShippingCodes(req.Countries)
+	regions := models.NormalizeShippingCodes(req.Regions)
+	if countries == "" && regions == "" && !req.IsDefault {
+		return ErrZoneWithoutDestinations
+	}
+
+	rates := make([]models.ShippingRate, len(req.Rates))
+	for i, r := range req.Rates {
+		if r.MaxWeight != nil && *r.MaxWeight <= r.MinWeight {
+			return ErrInvalidRateBracket
+		}
+		rates[i] = models.ShippingRate{MinWeight: r.MinWeight, MaxWeight: r.MaxWeight, Cost: r.Cost}
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].MinWeight < rates[j].MinWeight })
+	for i := 1; i < len(rates); i++ {
+		if prev := rates[i-1]; prev.MaxWeight == nil || *prev.MaxWeight > rates[i].MinWeight {
+			return ErrOverlappingRates
+		}
+	}
+
+	zone.Name = req.Name
+	zone.Countries = countries
+	zone.Regions = regions
+	zone.IsDefault = req.IsDefault
+	zone.Rates = rates
+	return nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+type supportService struct {
+	ticketRepo       repository.SupportTicketRepository
+	orderRepo        repository.OrderRepository
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+}
+
+func NewSupportService(
+	ticketRepo repository.SupportTicketRepository,
+	orderRepo repository.OrderRepository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+) SupportService {
+	return &supportService{
+		ticketRepo:       ticketRepo,
+		orderRepo:        orderRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *supportService) CreateTicket(ctx context.Context, userID uint, req *models.CreateSupportTicketRequest) (*models.SupportTicket, error) {
+	// Tickets can only point at the customer's own orders
+	if req.OrderID != nil {
+		order, err := s.orderRepo.GetByID(ctx, *req.OrderID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrOrderNotFound
+			}
+			return nil, fmt.Errorf("failed to get order: %w", err)
+		}
+		if order.CustomerID != userID {
+			return nil, ErrOrderNotFound
+		}
+	}
+
+	ticket := &models.SupportTicket{
+		UserID:  userID,
+		OrderID: req.OrderID,
+		Subject: req.Subject,
+		Body:    req.Body,
+		Status:  models.SupportTicketStatusOpen,
+	}
+	if err := s.ticketRepo.Create(ctx, ticket); err != nil {
+		return nil, fmt.Errorf("failed to create support ticket: %w", err)
+	}
+
+	s.notifyAdmins(ctx, ticket)
+
+	return ticket, nil
+}
+
+func (s *supportService) GetMyTickets(ctx context.Context, userID uint, limit, offset int) ([]*models.SupportTicket, int64, error) {
+	tickets, total, err := s.ticketRepo.GetByUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get support tickets: %w", err)
+	}
+
+	return tickets, total, nil
+}
+
+func (s *supportService) GetMyTicket(ctx context.Context, userID, ticketID uint) (*models.SupportTicket, error) {
+	ticket, err := s.GetTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.UserID != userID {
+		return nil, ErrTicketNotFound
+	}
+
+	return ticket, nil
+}
+
+// ReplyToTicket adds the customer's reply and puts the ticket back in the staff queue
+func (s *supportService) ReplyToTicket(ctx context.Context, userID, ticketID uint, req *models.SupportTicketReplyRequest) (*models.SupportTicket, error) {
+	ticket, err := s.GetMyTicket(ctx, userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.IsClosed() {
+		return nil, ErrTicketClosed
+	}
+
+	message := &models.SupportTicketMessage{
+		TicketID: ticket.ID,
+		UserID:   userID,
+		Body:     req.Body,
+	}
+	if err := s.ticketRepo.AddMessage(ctx, message, models.SupportTicketStatusOpen); err != nil {
+		return nil, fmt.Errorf("failed to reply to support ticket: %w", err)
+	}
+
+	return s.GetTicket(ctx, ticket.ID)
+}
+
+func (s *supportService) GetTickets(ctx context.Context, status *models.SupportTicketStatus, limit, offset int) ([]*models.SupportTicket, int64, error) {
+	tickets, total, err := s.ticketRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get support tickets: %w", err)
+	}
+
+	return tickets, total, nil
+}
+
+func (s *supportService) GetTicket(ctx context.Context, ticketID uint) (*models.SupportTicket, error) {
+	ticket, err := s.ticketRepo.GetByID(ctx, ticketID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketNotFound
+		}
+		return nil, fmt.Errorf("failed to get support ticket: %w", err)
+	}
+
+	return ticket, nil
+}
+
+// RespondToTicket adds a staff reply and notifies the customer. Without a
+// status the ticket is marked answered.
+func (s *supportService) RespondToTicket(ctx context.Context, adminID, ticketID uint, req *models.SupportTicketReplyRequest) (*models.SupportTicket, error) {
+	ticket, err := s.GetTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.SupportTicketStatusAnswered
+	if req.Status != nil {
+		status = *req.Status
+	}
+
+	message := &models.SupportTicketMessage{
+		TicketID: ticket.ID,
+		UserID:   adminID,
+		Body:     req.Body,
+		IsStaff:  true,
+	}
+	if err := s.ticketRepo.AddMessage(ctx, message, status); err != nil {
+		return nil, fmt.Errorf("failed to respond to support ticket: %w", err)
+	}
+
+	s.notifyCustomer(ctx, ticket)
+
+	return s.GetTicket(ctx, ticket.ID)
+}
+
+// notifyAdmins alerts every admin about a new ticket; a failure doesn't block the ticket
+func (s *supportService) notifyAdmins(ctx context.Context, ticket *models.SupportTicket) {
+	adminRole := models.RoleAdmin
+	admins, _, err := s.userRepo.List(ctx, 1, 100, &adminRole)
+	if err != nil {
+		fmt.Printf("Warning: failed to get admins for support ticket %d: %v\n", ticket.ID, err)
+		return
+	}
+	if len(admins) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("A customer opened a support ticket: %s", ticket.Subject)
+	if ticket.OrderID != nil {
+		message = fmt.Sprintf("A customer opened a support ticket about order %d: %s", *ticket.OrderID, ticket.Subject)
+	}
+	data := supportTicketNotificationData(ticket)
+
+	notifications := make([]*models.Notification, len(admins))
+	for i, admin := range admins {
+		notifications[i] = &models.Notification{
+			UserID:  admin.ID,
+			Type:    models.NotificationTypeSupportTicket,
+			Title:   "New support ticket",
+			Message: message,
+			Data:    data,
+		}
+	}
+	if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+		fmt.Printf("Warning: failed to notify admins about support ticket %d: %v\n", ticket.ID, err)
+	}
+}
+
+// notifyCustomer tells the customer staff responded to their ticket
+func (s *supportService) notifyCustomer(ctx context.Context, ticket *models.SupportTicket) {
+	notification := &models.Notification{
+		UserID:  ticket.UserID,
+		Type:    models.NotificationTypeSupportReply,
+		Title:   "Support replied to your ticket",
+		Message: fmt.Sprintf("Our support team responded to \"%s\".", ticket.Subject),
+		Data:    supportTicketNotificationData(ticket),
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		fmt.Printf("Warning: failed to notify customer about support ticket %d: %v\n", ticket.ID, err)
+	}
+}
+
+func supportTicketNotificationData(ticket *models.SupportTicket) *string {
+	fields := map[string]interface{}{"ticket_id": ticket.ID}
+	if ticket.OrderID != nil {
+		fields["order_id"] = *ticket.OrderID
+	}
+
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	data := string(payload)
+	return &data
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// openTestRedis returns a client for the database in TEST_REDIS_DB (default
+// 15) of the Redis at TEST_REDIS_ADDR, flushed before and after the test.
+// Tests using it are skipped unless TEST_REDIS_ADDR is set.
+func openTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR not set")
+	}
+	db := 15
+	if value := os.Getenv("TEST_REDIS_DB"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			t.Fatalf("invalid TEST_REDIS_DB: %v", err)
+		}
+		db = parsed
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr, DB: db})
+	ctx := context.Background()
+	if err := client.FlushDB(ctx).Err(); err != nil {
+		t.Fatalf("failed to flush test redis: %v", err)
+	}
+	t.Cleanup(func() {
+		client.FlushDB(ctx)
+		client.Close()
+	})
+	return client
+}
@@ -3,20 +3,31 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/repository"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
 	"gorm.io/gorm"
 )
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	productRepo      repository.ProductRepository
+	orderRepo        repository.OrderRepository
+	notificationRepo repository.NotificationRepository
+	productCache     *ProductCache
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, productRepo repository.ProductRepository, orderRepo repository.OrderRepository, notificationRepo repository.NotificationRepository, productCache *ProductCache) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		productRepo:      productRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		productCache:     productCache,
 	}
 }
 
@@ -52,6 +63,9 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uint, req *model
 	if req.Gender != nil {
 		user.Gender = req.Gender
 	}
+	if req.ReviewRemindersOptOut != nil {
+		user.ReviewRemindersOptOut = *req.ReviewRemindersOptOut
+	}
 	if req.Street != nil {
 		user.Street = req.Street
 	}
@@ -76,6 +90,12 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uint, req *model
 	if req.TaxID != nil && user.IsSeller() {
 		user.TaxID = req.TaxID
 	}
+	if req.MinimumOrderAmount != nil && user.IsSeller() {
+		user.MinimumOrderAmount = req.MinimumOrderAmount
+	}
+	if req.RestockingFeePercent != nil && user.IsSeller() {
+		user.RestockingFeePercent = req.RestockingFeePercent
+	}
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
@@ -113,7 +133,7 @@ func (s *userService) CreateUser(ctx context.Context, req *models.UserCreateRequ
 	// Check if user already exists
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err == nil && existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, ErrEmailTaken
 	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
@@ -146,10 +166,193 @@ func (s *userService) UpdateUser(ctx context.Context, id uint, req *models.UserU
 	return s.UpdateProfile(ctx, id, req)
 }
 
-func (s *userService) DeleteUser(ctx context.Context, id uint) error {
+// DeleteUser soft-deletes the user. A seller's active products are taken off
+// sale first; their order items and reviews are kept.
+func (s *userService) DeleteUser(ctx context.Context, id uint, adminID uint) error {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.IsSeller() {
+		if _, err := s.hideSellerProducts(ctx, id, adminID); err != nil {
+			return err
+		}
+	}
+
 	return s.userRepo.Delete(ctx, id)
 }
 
+// DeactivateSeller stops a seller from selling: their account is made inactive
+// and their active products are hidden. Orders and reviews are left untouched.
+func (s *userService) DeactivateSeller(ctx context.Context, id uint, adminID uint) (*models.SellerStatusChange, error) {
+	seller, err := s.getSeller(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !seller.IsActive {
+		return nil, ErrSellerAlreadyDeactivated
+	}
+
+	// Products go first so a failed account update can simply be retried
+	hidden, err := s.hideSellerProducts(ctx, id, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	seller.IsActive = false
+	if err := s.userRepo.Update(ctx, seller); err != nil {
+		return nil, fmt.Errorf("failed to deactivate seller: %w", err)
+	}
+
+	s.notifySellerStatus(ctx, seller.ID, "Your seller account has been deactivated",
+		fmt.Sprintf("Your seller account was deactivated and %d of your products are no longer for sale. Contact support if you believe this is a mistake.", len(hidden)))
+
+	return &models.SellerStatusChange{SellerID: seller.ID, IsActive: false, ProductsAffected: len(hidden)}, nil
+}
+
+// ReactivateSeller reverses DeactivateSeller, putting back on sale only the
+// products that deactivation hid
+func (s *userService) ReactivateSeller(ctx context.Context, id uint, adminID uint) (*models.SellerStatusChange, error) {
+	seller, err := s.getSeller(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if seller.IsActive {
+		return nil, ErrSellerAlreadyActive
+	}
+
+	seller.IsActive = true
+	if err := s.userRepo.Update(ctx, seller); err != nil {
+		return nil, fmt.Errorf("failed to reactivate seller: %w", err)
+	}
+
+	restored, err := s.productRepo.RestoreSellerProducts(ctx, id, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore seller products: %w", err)
+	}
+	if len(restored) > 0 {
+		s.productCache.Invalidate(ctx, restored...)
+	}
+
+	s.notifySellerStatus(ctx, seller.ID, "Your seller account has been reactivated",
+		fmt.Sprintf("Your seller account is active again and %d of your products are back on sale.", len(restored)))
+
+	return &models.SellerStatusChange{SellerID: seller.ID, IsActive: true, ProductsAffected: len(restored)}, nil
+}
+
+// CreateStaff creates a staff account for the seller. Staff sign in with their
+// own credentials and fulfill the orders the seller assigns them.
+func (s *userService) CreateStaff(ctx context.Context, sellerID uint, req *models.StaffCreateRequest) (*models.UserResponse, error) {
+	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err == nil && existingUser != nil {
+		return nil, ErrEmailTaken
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := utils.ValidatePassword(req.Password); err != nil {
+		return nil, errorf(ErrWeakPassword, "%s", err.Error())
+	}
+
+	staff := &models.User{
+		FirstName:  req.FirstName,
+		LastName:   req.LastName,
+		Email:      req.Email,
+		Role:       models.RoleSellerStaff,
+		Phone:      req.Phone,
+		IsActive:   true,
+		EmployerID: &sellerID,
+	}
+	if err := staff.HashPassword(req.Password); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Create(ctx, staff); err != nil {
+		return nil, err
+	}
+
+	response := staff.ToResponse()
+	return &response, nil
+}
+
+// GetStaff lists the seller's staff accounts
+func (s *userService) GetStaff(ctx context.Context, sellerID uint) ([]models.UserResponse, error) {
+	staff, err := s.userRepo.GetStaff(ctx, sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staff: %w", err)
+	}
+
+	responses := make([]models.UserResponse, len(staff))
+	for i := range staff {
+		responses[i] = staff[i].ToResponse()
+	}
+	return responses, nil
+}
+
+// RemoveStaff deactivates the staff account so it can no longer sign in and
+// unassigns the orders assigned to it so the seller can hand them to someone else
+func (s *userService) RemoveStaff(ctx context.Context, sellerID, staffID uint) error {
+	staff, err := s.userRepo.GetByID(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrStaffNotFound
+		}
+		return err
+	}
+	if !staff.IsStaffOf(sellerID) {
+		return ErrStaffNotFound
+	}
+
+	if err := s.orderRepo.UnassignStaff(ctx, staffID); err != nil {
+		return fmt.Errorf("failed to unassign staff orders: %w", err)
+	}
+
+	staff.IsActive = false
+	if err := s.userRepo.Update(ctx, staff); err != nil {
+		return fmt.Errorf("failed to deactivate staff member: %w", err)
+	}
+	return nil
+}
+
+func (s *userService) getSeller(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !user.IsSeller() {
+		return nil, ErrNotASeller
+	}
+	return user, nil
+}
+
+func (s *userService) hideSellerProducts(ctx context.Context, sellerID, adminID uint) ([]uint, error) {
+	hidden, err := s.productRepo.HideSellerProducts(ctx, sellerID, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hide seller products: %w", err)
+	}
+	if len(hidden) > 0 {
+		s.productCache.Invalidate(ctx, hidden...)
+	}
+	return hidden, nil
+}
+
+// notifySellerStatus tells the seller their account status changed. A failure
+// is logged rather than undoing the change.
+func (s *userService) notifySellerStatus(ctx context.Context, sellerID uint, title, message string) {
+	notification := &models.Notification{
+		UserID:  sellerID,
+		Type:    models.NotificationTypeAccountStatus,
+		Title:   title,
+		Message: message,
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		fmt.Printf("Warning: failed to notify seller %d of account status change: %v\n", sellerID, err)
+	}
+}
+
 func (s *userService) GetUserStats(ctx context.Context) (*models.UserStatsResponse, error) {
 	stats, err := s.userRepo.GetStats(ctx)
 	if err != nil {
@@ -169,3 +372,67 @@ func (s *userService) GetUserStats(ctx context.Context) (*models.UserStatsRespon
 		NewUsersMonth: stats.NewUsersMonth,
 	}, nil
 }
+
+// GetSellerOnboarding builds the seller's getting-started checklist from their
+// profile and product count. Onboarding is complete once every required step is.
+func (s *userService) GetSellerOnboarding(ctx context.Context, userID uint) (*models.SellerOnboardingStatus, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	productCount, err := s.productRepo.CountBySellerID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []models.SellerOnboardingStep{
+		{Key: "verify_email", Title: "Verify your email address", Required: true, Completed: user.IsVerified},
+		{Key: "store_name", Title: "Add your store name", Required: true, Completed: isSet(user.StoreName)},
+		{Key: "tax_id", Title: "Add your tax ID", Required: true, Completed: isSet(user.TaxID)},
+		{Key: "first_product", Title: "Add your first product", Required: true, Completed: productCount > 0},
+		// Payouts aren't configurable yet, so this can't block onboarding
+		{Key: "payouts", Title: "Set up payouts", Required: false, Completed: false},
+	}
+
+	status := &models.SellerOnboardingStatus{
+		Steps:      steps,
+		TotalSteps: len(steps),
+		IsComplete: true,
+	}
+	for _, step := range steps {
+		if step.Completed {
+			status.CompletedSteps++
+		} else if step.Required {
+			status.IsComplete = false
+		}
+	}
+
+	return status, nil
+}
+
+// GetCustomerStats summarizes the user's own order history
+func (s *userService) GetCustomerStats(ctx context.Context, userID uint) (*models.CustomerStats, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	stats, err := s.orderRepo.GetCustomerStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats.MemberSince = user.CreatedAt
+
+	return stats, nil
+}
+
+func isSet(value *string) bool {
+	return value != nil && strings.TrimSpace(*value) != ""
+}
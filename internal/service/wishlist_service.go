@@ -26,7 +26,7 @@ func (s *wishlistService) AddToWishlist(ctx context.Context, userID uint, req *m
 	product, err := s.productRepo.GetByID(ctx, req.ProductID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, ErrProductNotFound
 		}
 		return nil, err
 	}
@@ -37,7 +37,7 @@ func (s *wishlistService) AddToWishlist(ctx context.Context, userID uint, req *m
 		return nil, err
 	}
 	if exists {
-		return nil, errors.New("product already in wishlist")
+		return nil, ErrAlreadyInWishlist
 	}
 
 	// Add to wishlist
@@ -113,6 +113,28 @@ func (s *wishlistService) IsProductInWishlist(ctx context.Context, userID, produ
 	return s.wishlistRepo.IsInWishlist(ctx, userID, productID)
 }
 
+// CheckMany reports for each product whether it's in the user's wishlist, in one query
+func (s *wishlistService) CheckMany(ctx context.Context, userID uint, productIDs []uint) (map[uint]bool, error) {
+	wishlisted, err := s.wishlistRepo.GetWishlistedProductIDs(ctx, userID, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]bool, len(productIDs))
+	for _, id := range productIDs {
+		result[id] = false
+	}
+	for _, id := range wishlisted {
+		result[id] = true
+	}
+
+	return result, nil
+}
+
+func (s *wishlistService) GetWishlistCount(ctx context.Context, userID uint) (int64, error) {
+	return s.wishlistRepo.CountByUser(ctx, userID)
+}
+
 func (s *wishlistService) ClearWishlist(ctx context.Context, userID uint) error {
 	// Since we don't have a direct clear method, we'll get all items and remove them
 	wishlistItems, err := s.wishlistRepo.GetByUser(ctx, userID)
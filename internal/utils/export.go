@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Report export formats, chosen with the format query parameter
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+)
+
+// csvFlushRows is how many rows are written between flushes to the client
+const csvFlushRows = 500
+
+// ExportFormat returns the format requested with ?format=, JSON by default
+func ExportFormat(c echo.Context) (string, error) {
+	switch format := strings.ToLower(c.QueryParam("format")); format {
+	case "", ExportFormatJSON:
+		return ExportFormatJSON, nil
+	case ExportFormatCSV:
+		return ExportFormatCSV, nil
+	default:
+		return "", errors.New("Invalid format (use json or csv)")
+	}
+}
+
+// ExportFilename names a report export covering a date range
+func ExportFilename(report string, startDate, endDate time.Time, format string) string {
+	return fmt.Sprintf("%s_%s_%s.%s", report, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"), format)
+}
+
+// StreamCSV sends a CSV attachment: the header, then every record passed to
+// write by writeRows. Records are flushed to the client as they go rather than
+// buffered, so writeRows can feed rows straight from a database cursor. Once
+// the header is sent the status can't change, so an error part way through
+// ends the download early.
+func StreamCSV(c echo.Context, filename string, header []string, writeRows func(write func(record []string) error) error) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	res.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(res)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	rows := 0
+	err := writeRows(func(record []string) error {
+		if err := w.Write(record); err != nil {
+			return err
+		}
+		rows++
+		if rows%csvFlushRows == 0 {
+			w.Flush()
+			if err := w.Error(); err != nil {
+				return err
+			}
+			res.Flush()
+		}
+		return nil
+	})
+
+	w.Flush()
+	if err == nil {
+		err = w.Error()
+	}
+	if err != nil {
+		fmt.Printf("Warning: CSV export %s ended early: %v\n", filename, err)
+	}
+	return nil
+}
+
+// ExportCSV streams rows, a slice of structs, as CSV. Columns are the struct's
+// JSON field names in declaration order; nested lists and objects (other than
+// times) are left out, so a report's summary type exports as-is.
+func ExportCSV(c echo.Context, filename string, rows interface{}) error {
+	value := reflect.ValueOf(rows)
+	if value.Kind() != reflect.Slice {
+		return fmt.Errorf("export rows must be a slice, got %T", rows)
+	}
+
+	elemType := value.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	columns := csvColumns(elemType)
+
+	return StreamCSV(c, filename, columns.header(), func(write func(record []string) error) error {
+		for i := 0; i < value.Len(); i++ {
+			if err := write(columns.record(value.Index(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CSVHeader returns the columns ExportCSV would write for a row struct, for
+// exports that stream rows through StreamCSV themselves
+func CSVHeader(row interface{}) []string {
+	return csvColumns(reflect.Indirect(reflect.ValueOf(row)).Type()).header()
+}
+
+// CSVRecord formats a row struct the way ExportCSV does
+func CSVRecord(row interface{}) []string {
+	value := reflect.ValueOf(row)
+	return csvColumns(reflect.Indirect(value).Type()).record(value)
+}
+
+type csvColumn struct {
+	name  string
+	index int
+}
+
+type csvColumnList []csvColumn
+
+func (columns csvColumnList) header() []string {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	return header
+}
+
+func (columns csvColumnList) record(row reflect.Value) []string {
+	row = reflect.Indirect(row)
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = csvValue(row.Field(column.index))
+	}
+	return record
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// csvColumns lists the exportable fields of a struct type
+func csvColumns(t reflect.Type) csvColumnList {
+	var columns csvColumnList
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch fieldType.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+			continue
+		case reflect.Struct:
+			if fieldType != timeType {
+				continue
+			}
+		}
+
+		columns = append(columns, csvColumn{name: name, index: i})
+	}
+	return columns
+}
+
+// csvValue formats a field for a CSV cell; nil pointers are empty
+func csvValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	// Spreadsheets misread exponent notation, which %v uses for large floats
+	if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -8,24 +8,88 @@ import (
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 )
 
+// PageResource identifies a group of list endpoints sharing page size limits
+type PageResource string
+
+const (
+	PageResourceDefault       PageResource = "default"
+	PageResourceProducts      PageResource = "products"
+	PageResourceOrders        PageResource = "orders"
+	PageResourceReviews       PageResource = "reviews"
+	PageResourceNotifications PageResource = "notifications"
+	PageResourceAdmin         PageResource = "admin"
+)
+
+// PageSize holds the default and maximum page size of a resource
+type PageSize struct {
+	Default int
+	Max     int
+}
+
+// pageSizes holds the page sizes per resource, replaced at startup by SetPageSizes
+var pageSizes = map[PageResource]PageSize{
+	PageResourceDefault: {Default: 20, Max: 100},
+}
+
+// SetPageSizes configures the page sizes per resource. Resources without an
+// entry use the PageResourceDefault sizes.
+func SetPageSizes(sizes map[PageResource]PageSize) {
+	configured := make(map[PageResource]PageSize, len(sizes)+1)
+	configured[PageResourceDefault] = pageSizes[PageResourceDefault]
+	for resource, size := range sizes {
+		if size.Max <= 0 {
+			size.Max = 100
+		}
+		if size.Default <= 0 || size.Default > size.Max {
+			size.Default = size.Max
+		}
+		configured[resource] = size
+	}
+	pageSizes = configured
+}
+
+// pageSizeFor returns the page sizes configured for the resource
+func pageSizeFor(resource PageResource) PageSize {
+	if size, ok := pageSizes[resource]; ok {
+		return size
+	}
+	return pageSizes[PageResourceDefault]
+}
+
 // PaginationParams extracts pagination parameters from query string
 func PaginationParams(c echo.Context) (page, limit int) {
-	page = 1
-	limit = 20
+	return PaginationParamsFor(c, PageResourceDefault)
+}
 
+// PaginationParamsFor extracts pagination parameters from query string using
+// the page sizes of the resource. A limit above the maximum is clamped to it.
+func PaginationParamsFor(c echo.Context, resource PageResource) (page, limit int) {
+	page = 1
 	if p := c.QueryParam("page"); p != "" {
 		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
 			page = parsed
 		}
 	}
 
+	return page, LimitParamFor(c, resource)
+}
+
+// LimitParamFor extracts the limit query parameter using the page sizes of
+// the resource, for lists that aren't paged
+func LimitParamFor(c echo.Context, resource PageResource) int {
+	size := pageSizeFor(resource)
+
+	limit := size.Default
 	if l := c.QueryParam("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
 			limit = parsed
 		}
 	}
+	if limit > size.Max {
+		limit = size.Max
+	}
 
-	return page, limit
+	return limit
 }
 
 // BuildPaginationMeta creates pagination metadata
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/JonathanVera18/ecommerce-api/internal/apierror"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 )
 
@@ -35,11 +36,33 @@ func CreatedResponse(c echo.Context, message string, data interface{}) error {
 	})
 }
 
-// ErrorResponse sends an error JSON response
+// CreatedResponseWithMeta sends a created JSON response with metadata
+func CreatedResponseWithMeta(c echo.Context, message string, data interface{}, meta interface{}) error {
+	return c.JSON(http.StatusCreated, models.Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
+// ErrorResponse sends an error JSON response with the generic code for the status
 func ErrorResponse(c echo.Context, statusCode int, message string) error {
+	return ErrorResponseWithCode(c, statusCode, apierror.ForStatus(statusCode), message)
+}
+
+// ErrorResponseFromError sends err's message as an error JSON response. Its
+// code is the one err carries, or the generic code for the status.
+func ErrorResponseFromError(c echo.Context, statusCode int, err error) error {
+	return ErrorResponseWithCode(c, statusCode, apierror.Resolve(statusCode, err), err.Error())
+}
+
+// ErrorResponseWithCode sends an error JSON response with an explicit code
+func ErrorResponseWithCode(c echo.Context, statusCode int, code apierror.Code, message string) error {
 	return c.JSON(statusCode, models.ErrorResponse{
 		Success: false,
 		Error:   message,
+		Code:    string(code),
 	})
 }
 
@@ -48,6 +71,11 @@ func BadRequestError(c echo.Context, message string) error {
 	return ErrorResponse(c, http.StatusBadRequest, message)
 }
 
+// InvalidIDError sends a bad request error response for a malformed ID
+func InvalidIDError(c echo.Context, message string) error {
+	return ErrorResponseWithCode(c, http.StatusBadRequest, apierror.InvalidID, message)
+}
+
 // UnauthorizedError sends an unauthorized error response
 func UnauthorizedError(c echo.Context, message string) error {
 	return ErrorResponse(c, http.StatusUnauthorized, message)
@@ -78,6 +106,7 @@ func ValidationError(c echo.Context, errors map[string]string) error {
 	return c.JSON(http.StatusBadRequest, map[string]interface{}{
 		"success": false,
 		"error":   "Validation failed",
+		"code":    apierror.ValidationFailed,
 		"details": errors,
 	})
 }
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/JonathanVera18/ecommerce-api/internal/config"
 	"github.com/JonathanVera18/ecommerce-api/internal/handler"
 	"github.com/JonathanVera18/ecommerce-api/internal/middleware"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
 	"github.com/JonathanVera18/ecommerce-api/internal/repository"
 	"github.com/JonathanVera18/ecommerce-api/internal/service"
+	"github.com/JonathanVera18/ecommerce-api/internal/utils"
 	
+	"github.com/JonathanVera18/ecommerce-api/pkg/email"
+	"github.com/JonathanVera18/ecommerce-api/pkg/geo"
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
 	"github.com/JonathanVera18/ecommerce-api/pkg/payment"
 
 	"github.com/labstack/echo/v4"
@@ -27,6 +34,10 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
@@ -34,6 +45,27 @@ func main() {
 		log.Fatal("Failed to load configuration:", err)
 	}
 
+	// Money is rounded to the store currency's minor units
+	if err := money.Configure(cfg.Currency.MinorUnits, money.RoundingMode(cfg.Currency.Rounding)); err != nil {
+		log.Fatal("Invalid currency configuration:", err)
+	}
+
+	// Products without their own return window use the configured default
+	models.DefaultReturnWindowDays = cfg.Order.ReturnWindowDays
+
+	// Products without their own processing time use the configured default
+	models.DefaultProcessingTimeDays = cfg.Shipping.DefaultProcessingDays
+
+	// List endpoints share the configured page sizes per resource
+	utils.SetPageSizes(map[utils.PageResource]utils.PageSize{
+		utils.PageResourceDefault:       {Default: cfg.Pagination.Default.Default, Max: cfg.Pagination.Default.Max},
+		utils.PageResourceProducts:      {Default: cfg.Pagination.Products.Default, Max: cfg.Pagination.Products.Max},
+		utils.PageResourceOrders:        {Default: cfg.Pagination.Orders.Default, Max: cfg.Pagination.Orders.Max},
+		utils.PageResourceReviews:       {Default: cfg.Pagination.Reviews.Default, Max: cfg.Pagination.Reviews.Max},
+		utils.PageResourceNotifications: {Default: cfg.Pagination.Notifications.Default, Max: cfg.Pagination.Notifications.Max},
+		utils.PageResourceAdmin:         {Default: cfg.Pagination.Admin.Default, Max: cfg.Pagination.Admin.Max},
+	})
+
 	// Initialize database
 	db, err := config.InitDatabase(cfg)
 	if err != nil {
@@ -48,7 +80,15 @@ func main() {
 
 	// Initialize external services
 	
-	paymentService := payment.NewStripeService(cfg)
+	paymentService, err := payment.NewService(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize payments:", err)
+	}
+	if cfg.Payment.Mode == config.PaymentModeTest {
+		log.Println("Payments are in test mode: no real charges will be made")
+	}
+	emailSender := email.NewSMTPService(cfg)
+	geoService := geo.NewNoopService()
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
@@ -58,34 +98,100 @@ func main() {
 	categoryRepo := repository.NewCategoryRepository(db)
 	wishlistRepo := repository.NewWishlistRepository(db)
 	cartRepo := repository.NewCartRepository(db)
-	notificationRepo := repository.NewNotificationRepository(db)
+	notificationRepo := service.TrackUnreadCounts(repository.NewNotificationRepository(db), redisClient)
 	productImageRepo := repository.NewProductImageRepository(db)
+	searchLogRepo := repository.NewSearchLogRepository(db)
+	recallRepo := repository.NewRecallRepository(db)
+	reservationRepo := repository.NewStockReservationRepository(db)
+	featuredSellerRepo := repository.NewFeaturedSellerRepository(db)
+	disputeRepo := repository.NewDisputeRepository(db)
+	returnRequestRepo := repository.NewReturnRequestRepository(db)
+	questionRepo := repository.NewProductQuestionRepository(db)
+	promotionRepo := repository.NewPromotionRepository(db)
+	couponRepo := repository.NewCouponRepository(db)
+	shippingZoneRepo := repository.NewShippingZoneRepository(db)
+	emailBroadcastRepo := repository.NewEmailBroadcastRepository(db)
+	addressRepo := repository.NewAddressRepository(db)
+	supportTicketRepo := repository.NewSupportTicketRepository(db)
+	conversationRepo := repository.NewConversationRepository(db)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg, redisClient)
-	userService := service.NewUserService(userRepo)
-	productService := service.NewProductService(productRepo, reviewRepo)
-	orderService := service.NewOrderService(orderRepo, productRepo, userRepo, paymentService)
-	reviewService := service.NewReviewService(reviewRepo, productRepo, userRepo)
+	emailService := service.NewEmailService(emailSender)
+	couponService := service.NewCouponService(couponRepo, redisClient, cfg)
+	authService := service.NewAuthService(userRepo, emailService, couponService, cfg, redisClient)
+	productCache := service.NewProductCache(redisClient)
+	userService := service.NewUserService(userRepo, productRepo, orderRepo, notificationRepo, productCache)
+	productService := service.NewProductService(productRepo, reviewRepo, categoryRepo, notificationRepo, productCache, cfg)
+	searchService := service.NewSearchService(productRepo, searchLogRepo, redisClient)
+	fraudService := service.NewRuleBasedFraudService(orderRepo)
+	shippingService := service.NewShippingService(shippingZoneRepo, cfg)
+	promotionEngine := service.NewPromotionEngine(promotionRepo, productRepo)
+	minimumOrderPolicy := service.NewMinimumOrderPolicy(userRepo, cfg)
+	addressService := service.NewAddressService(addressRepo)
+	orderService := service.NewOrderService(orderRepo, productRepo, userRepo, reservationRepo, notificationRepo, paymentService, fraudService, shippingService, promotionEngine, productCache, minimumOrderPolicy, couponService, addressService, emailService, cfg)
+	ratingQueue := service.NewRatingQueue(reviewRepo, productRepo, productCache, cfg)
+	reviewService := service.NewReviewService(reviewRepo, productRepo, userRepo, orderRepo, redisClient, ratingQueue)
 	categoryService := service.NewCategoryService(categoryRepo, productRepo)
 	wishlistService := service.NewWishlistService(wishlistRepo, productRepo)
-	cartService := service.NewCartService(cartRepo, productRepo)
-	notificationService := service.NewNotificationService(notificationRepo)
-	productImageService := service.NewProductImageService(productImageRepo, productRepo)
+	cartService := service.NewCartService(cartRepo, productRepo, reservationRepo, shippingService, promotionEngine, minimumOrderPolicy, cfg.Cart)
+	notificationService := service.NewNotificationService(notificationRepo, redisClient)
+	productImageService := service.NewProductImageService(productImageRepo, productRepo, cfg)
+	recallService := service.NewRecallService(recallRepo, productRepo, notificationRepo)
+	featuredSellerService := service.NewFeaturedSellerService(featuredSellerRepo, userRepo, cfg)
+	disputeService := service.NewDisputeService(disputeRepo, orderRepo, userRepo, notificationRepo, paymentService, orderService)
+	returnService := service.NewReturnService(returnRequestRepo, orderRepo, userRepo, paymentService)
+	supportService := service.NewSupportService(supportTicketRepo, orderRepo, userRepo, notificationRepo)
+	conversationService := service.NewConversationService(conversationRepo, userRepo, productRepo, orderRepo, notificationRepo)
+	questionService := service.NewProductQuestionService(questionRepo, productRepo, userRepo, notificationRepo)
+	promotionService := service.NewPromotionService(promotionRepo, categoryRepo, productRepo)
+	emailBroadcastService := service.NewEmailBroadcastService(emailBroadcastRepo)
+	emailDispatcher := service.NewEmailDispatcher(emailBroadcastRepo, emailSender, cfg)
+	reviewReminder := service.NewReviewReminder(orderRepo, reviewService, emailService, cfg)
+	orderArchiver := service.NewOrderArchiver(orderRepo, cfg)
+
+	// Release stock held by unpaid orders once their reservation expires
+	orderService.StartReservationSweeper(context.Background(), time.Minute)
+
+	// Alert sellers and admins about orders stuck past their SLA
+	orderService.StartSLAMonitor(context.Background(), cfg.Order.SLACheckInterval)
+
+	// Recompute the ratings of products whose reviews changed, once per product
+	ratingQueue.Start(context.Background())
+
+	// Send queued broadcast emails within the provider's rate limits
+	emailDispatcher.Start(context.Background(), cfg.Email.BulkDispatchInterval)
+
+	// Ask customers to review what they bought once it has been delivered a while
+	reviewReminder.Start(context.Background(), cfg.Order.ReviewReminderCheckInterval)
+
+	// Move finished orders past the retention period out of order lists
+	orderArchiver.Start(context.Background(), cfg.Order.ArchiveCheckInterval)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
 	userHandler := handler.NewUserHandler(userService, authService)
-	productHandler := handler.NewProductHandler(productService)
-	orderHandler := handler.NewOrderHandler(orderService)
+	productHandler := handler.NewProductHandler(productService, searchService)
+	orderHandler := handler.NewOrderHandler(orderService, productService)
 	reviewHandler := handler.NewReviewHandler(reviewService)
-	adminHandler := handler.NewAdminHandler(userService, productService, orderService, reviewService)
+	adminHandler := handler.NewAdminHandler(userService, productService, orderService, reviewService, searchService)
 	categoryHandler := handler.NewCategoryHandler(categoryService)
 	wishlistHandler := handler.NewWishlistHandler(wishlistService)
 	cartHandler := handler.NewCartHandler(cartService)
 	notificationHandler := handler.NewNotificationHandler(notificationService)
 	fileUploadHandler := handler.NewFileUploadHandler("uploads")
 	productImageHandler := handler.NewProductImageHandler(productImageService)
+	recallHandler := handler.NewRecallHandler(recallService)
+	featuredSellerHandler := handler.NewFeaturedSellerHandler(featuredSellerService)
+	disputeHandler := handler.NewDisputeHandler(disputeService)
+	returnHandler := handler.NewReturnHandler(returnService)
+	questionHandler := handler.NewProductQuestionHandler(questionService)
+	promotionHandler := handler.NewPromotionHandler(promotionService)
+	couponHandler := handler.NewCouponHandler(couponService, cartService)
+	addressHandler := handler.NewAddressHandler(addressService)
+	supportHandler := handler.NewSupportHandler(supportService)
+	conversationHandler := handler.NewConversationHandler(conversationService)
+	shippingHandler := handler.NewShippingHandler(shippingService)
+	emailBroadcastHandler := handler.NewEmailBroadcastHandler(emailBroadcastService)
 
 	// Initialize Echo
 	e := echo.New()
@@ -95,8 +201,10 @@ func main() {
 	e.Use(echomiddleware.Recover())
 	e.Use(middleware.SecurityHeaders())
 	e.Use(middleware.CORS())
+	e.Use(middleware.RequestID())
 	e.Use(middleware.Logging())
-	e.Use(middleware.APIRateLimit())
+	e.Use(middleware.APIRateLimit(redisClient))
+	e.Use(middleware.GeoLocation(geoService))
 
 	// HTTPS redirect in production
 	if os.Getenv("APP_ENV") == "production" {
@@ -105,19 +213,31 @@ func main() {
 
 	// Routes
 	handler.SetupRoutes(e, &handler.Handlers{
-		Auth:         authHandler,
-		User:         userHandler,
-		Product:      productHandler,
-		Order:        orderHandler,
-		Review:       reviewHandler,
-		Admin:        adminHandler,
-		Category:     categoryHandler,
-		Wishlist:     wishlistHandler,
-		Cart:         cartHandler,
-		Notification: notificationHandler,
-		FileUpload:   fileUploadHandler,
-		ProductImage: productImageHandler,
-	}, authService)
+		Auth:           authHandler,
+		User:           userHandler,
+		Product:        productHandler,
+		Order:          orderHandler,
+		Review:         reviewHandler,
+		Admin:          adminHandler,
+		Category:       categoryHandler,
+		Wishlist:       wishlistHandler,
+		Cart:           cartHandler,
+		Notification:   notificationHandler,
+		FileUpload:     fileUploadHandler,
+		ProductImage:   productImageHandler,
+		Recall:         recallHandler,
+		FeaturedSeller: featuredSellerHandler,
+		Dispute:        disputeHandler,
+		Return:         returnHandler,
+		Question:       questionHandler,
+		Promotion:      promotionHandler,
+		Coupon:         couponHandler,
+		Address:        addressHandler,
+		Support:        supportHandler,
+		Conversation:   conversationHandler,
+		Shipping:       shippingHandler,
+		EmailBroadcast: emailBroadcastHandler,
+	}, authService, redisClient, cfg.Integration.APIKeys)
 
 	// Health check
 	e.GET("/health", func(c echo.Context) error {
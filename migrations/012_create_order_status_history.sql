@@ -0,0 +1,23 @@
+-- Add cancellation details to orders
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancellation_reason VARCHAR(30);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancellation_note TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_orders_cancellation_reason ON orders(cancellation_reason);
+
+-- Create order_status_histories table
+CREATE TABLE IF NOT EXISTS order_status_histories (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason VARCHAR(30),
+    note TEXT,
+    changed_by INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_order_status_histories_order_id ON order_status_histories(order_id);
@@ -0,0 +1,10 @@
+-- Add fraud scoring details to orders
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fraud_score INTEGER DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fraud_reasons TEXT;
+
+-- Speed up the admin fraud review queue
+CREATE INDEX IF NOT EXISTS idx_orders_pending_review ON orders(created_at) WHERE status = 'pending_review';
+
+-- Allow the pending_review status
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status CHECK (status IN ('pending', 'pending_review', 'confirmed', 'processing', 'shipped', 'delivered', 'cancelled', 'refunded'));
@@ -0,0 +1,2 @@
+-- Prefix index for search suggestions (LOWER(name) LIKE 'prefix%')
+CREATE INDEX IF NOT EXISTS idx_products_name_prefix ON products (LOWER(name) text_pattern_ops);
@@ -0,0 +1,14 @@
+-- Create search_logs table
+CREATE TABLE IF NOT EXISTS search_logs (
+    id SERIAL PRIMARY KEY,
+    query VARCHAR(100) NOT NULL,
+    result_count INTEGER NOT NULL DEFAULT 0,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_search_logs_query ON search_logs(query);
+CREATE INDEX IF NOT EXISTS idx_search_logs_user_id ON search_logs(user_id);
+CREATE INDEX IF NOT EXISTS idx_search_logs_created_at ON search_logs(created_at);
+CREATE INDEX IF NOT EXISTS idx_search_logs_zero_results ON search_logs(created_at) WHERE result_count = 0;
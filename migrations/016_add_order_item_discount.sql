@@ -0,0 +1,2 @@
+-- Add line-level discounts to order items
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10,2) DEFAULT 0;
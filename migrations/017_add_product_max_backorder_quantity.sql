@@ -0,0 +1,3 @@
+-- Cap how many units a product may be backordered beyond its stock
+ALTER TABLE products ADD COLUMN IF NOT EXISTS max_backorder_quantity INTEGER DEFAULT 0;
+ALTER TABLE products ADD CONSTRAINT chk_products_max_backorder_quantity CHECK (max_backorder_quantity >= 0);
@@ -0,0 +1,17 @@
+-- Create product_recalls table
+CREATE TABLE IF NOT EXISTS product_recalls (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    initiated_by INTEGER NOT NULL REFERENCES users(id),
+    message TEXT NOT NULL,
+    customers_notified INTEGER DEFAULT 0,
+    emails_failed INTEGER DEFAULT 0,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_product_recalls_product_id ON product_recalls(product_id);
+CREATE INDEX IF NOT EXISTS idx_product_recalls_deleted_at ON product_recalls(deleted_at);
@@ -0,0 +1,22 @@
+-- Create stock_reservations table
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'reserved',
+    expires_at TIMESTAMP NOT NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_order_id ON stock_reservations(order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_expiry ON stock_reservations(expires_at) WHERE status = 'reserved';
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_deleted_at ON stock_reservations(deleted_at);
+
+-- Add constraints
+ALTER TABLE stock_reservations ADD CONSTRAINT chk_stock_reservations_status CHECK (status IN ('reserved', 'committed', 'released'));
+ALTER TABLE stock_reservations ADD CONSTRAINT chk_stock_reservations_quantity CHECK (quantity > 0);
@@ -0,0 +1,38 @@
+-- Snapshot the seller on each order item
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS seller_id INTEGER REFERENCES users(id);
+
+UPDATE order_items
+SET seller_id = products.seller_id
+FROM products
+WHERE order_items.product_id = products.id AND order_items.seller_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_order_items_seller_id ON order_items(seller_id);
+
+-- Create order_fulfillments table (one row per seller on multi-seller orders)
+CREATE TABLE IF NOT EXISTS order_fulfillments (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    seller_id INTEGER NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    item_count INTEGER NOT NULL DEFAULT 0,
+    subtotal_amount DECIMAL(10,2) NOT NULL,
+    tax_amount DECIMAL(10,2) DEFAULT 0,
+    shipping_amount DECIMAL(10,2) DEFAULT 0,
+    discount_amount DECIMAL(10,2) DEFAULT 0,
+    allocated_amount DECIMAL(10,2) NOT NULL,
+    tracking_number VARCHAR(100),
+    shipped_at TIMESTAMP,
+    delivered_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_fulfillments_order_seller ON order_fulfillments(order_id, seller_id);
+CREATE INDEX IF NOT EXISTS idx_order_fulfillments_seller_id ON order_fulfillments(seller_id);
+CREATE INDEX IF NOT EXISTS idx_order_fulfillments_deleted_at ON order_fulfillments(deleted_at);
+
+-- Add constraints
+ALTER TABLE order_fulfillments ADD CONSTRAINT chk_order_fulfillments_status CHECK (status IN ('pending', 'pending_review', 'confirmed', 'processing', 'shipped', 'delivered', 'cancelled', 'refunded'));
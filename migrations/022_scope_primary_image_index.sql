@@ -0,0 +1,12 @@
+-- GORM soft-deletes product images, so make sure the column exists
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_product_images_deleted_at ON product_images(deleted_at);
+
+-- Soft-deleted images shouldn't hold the primary slot
+UPDATE product_images SET is_primary = false WHERE deleted_at IS NOT NULL AND is_primary = true;
+
+-- Ensure only one primary image per live product image set
+DROP INDEX IF EXISTS idx_product_images_primary_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_images_primary_unique
+ON product_images(product_id)
+WHERE is_primary = true AND deleted_at IS NULL;
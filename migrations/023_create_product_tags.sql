@@ -0,0 +1,18 @@
+-- Create product_tags table (normalized copy of products.tags for filtering)
+CREATE TABLE IF NOT EXISTS product_tags (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    tag VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_tags_product_tag ON product_tags(product_id, tag);
+CREATE INDEX IF NOT EXISTS idx_product_tags_tag ON product_tags(tag);
+
+-- Migrate existing comma-separated tags
+INSERT INTO product_tags (product_id, tag)
+SELECT DISTINCT products.id, LOWER(TRIM(tag))
+FROM products, UNNEST(STRING_TO_ARRAY(products.tags, ',')) AS tag
+WHERE products.tags IS NOT NULL AND TRIM(tag) <> ''
+ON CONFLICT (product_id, tag) DO NOTHING;
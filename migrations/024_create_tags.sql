@@ -0,0 +1,30 @@
+-- Create tags table
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags(name);
+
+-- Move product_tags from a tag string to a tags reference
+INSERT INTO tags (name)
+SELECT DISTINCT tag FROM product_tags
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE product_tags ADD COLUMN IF NOT EXISTS tag_id INTEGER REFERENCES tags(id) ON DELETE CASCADE;
+
+UPDATE product_tags
+SET tag_id = tags.id
+FROM tags
+WHERE tags.name = product_tags.tag AND product_tags.tag_id IS NULL;
+
+ALTER TABLE product_tags ALTER COLUMN tag_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_product_tags_product_tag;
+DROP INDEX IF EXISTS idx_product_tags_tag;
+ALTER TABLE product_tags DROP COLUMN IF EXISTS tag;
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_tags_product_tag ON product_tags(product_id, tag_id);
+CREATE INDEX IF NOT EXISTS idx_product_tags_tag_id ON product_tags(tag_id);
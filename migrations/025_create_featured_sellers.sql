@@ -0,0 +1,21 @@
+-- Create featured_sellers table
+CREATE TABLE IF NOT EXISTS featured_sellers (
+    id SERIAL PRIMARY KEY,
+    seller_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_featured_sellers_seller_id ON featured_sellers(seller_id);
+CREATE INDEX IF NOT EXISTS idx_featured_sellers_position ON featured_sellers(position);
+CREATE INDEX IF NOT EXISTS idx_featured_sellers_expires_at ON featured_sellers(expires_at);
+CREATE INDEX IF NOT EXISTS idx_featured_sellers_deleted_at ON featured_sellers(deleted_at);
+
+-- Add constraints
+ALTER TABLE featured_sellers ADD CONSTRAINT chk_featured_sellers_position CHECK (position >= 0);
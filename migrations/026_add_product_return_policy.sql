@@ -0,0 +1,4 @@
+-- Per-product return policy; a NULL window uses the store default
+ALTER TABLE products ADD COLUMN IF NOT EXISTS returnable BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS return_window_days INTEGER;
+ALTER TABLE products ADD CONSTRAINT chk_products_return_window_days CHECK (return_window_days IS NULL OR (return_window_days BETWEEN 1 AND 365));
@@ -0,0 +1,4 @@
+-- Track when sellers and admins were last alerted that an order is stuck
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS sla_alerted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_orders_status_created_at ON orders(status, created_at);
@@ -0,0 +1,13 @@
+-- Create price_histories table
+CREATE TABLE IF NOT EXISTS price_histories (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    old_price DECIMAL(10,2) NOT NULL,
+    new_price DECIMAL(10,2) NOT NULL,
+    changed_by INTEGER NOT NULL REFERENCES users(id),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_price_histories_product_created ON price_histories(product_id, created_at);
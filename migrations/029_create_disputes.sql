@@ -0,0 +1,33 @@
+-- Create disputes table
+CREATE TABLE IF NOT EXISTS disputes (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    stripe_dispute_id VARCHAR(255) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3),
+    reason VARCHAR(50),
+    status VARCHAR(30) NOT NULL,
+    evidence_due_by TIMESTAMP,
+
+    -- Evidence metadata
+    evidence_notes TEXT,
+    evidence_documents TEXT,
+    evidence_submitted_at TIMESTAMP,
+    evidence_submitted_by INTEGER REFERENCES users(id),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disputes_stripe_dispute_id ON disputes(stripe_dispute_id);
+CREATE INDEX IF NOT EXISTS idx_disputes_order_id ON disputes(order_id);
+CREATE INDEX IF NOT EXISTS idx_disputes_status ON disputes(status);
+CREATE INDEX IF NOT EXISTS idx_disputes_deleted_at ON disputes(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_orders_payment_id ON orders(payment_id);
+
+-- Add constraints
+ALTER TABLE disputes ADD CONSTRAINT chk_disputes_status CHECK (status IN ('warning_needs_response', 'warning_under_review', 'warning_closed', 'needs_response', 'under_review', 'won', 'lost'));
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_payment_status;
+ALTER TABLE orders ADD CONSTRAINT chk_orders_payment_status CHECK (payment_status IN ('pending', 'paid', 'failed', 'refunded', 'cancelled', 'disputed'));
@@ -0,0 +1,34 @@
+-- Create product_questions table
+CREATE TABLE IF NOT EXISTS product_questions (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question TEXT NOT NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create product_answers table
+CREATE TABLE IF NOT EXISTS product_answers (
+    id SERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL REFERENCES product_questions(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    answer TEXT NOT NULL,
+    author_type VARCHAR(20) NOT NULL DEFAULT 'customer',
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_product_questions_product_id ON product_questions(product_id);
+CREATE INDEX IF NOT EXISTS idx_product_questions_user_id ON product_questions(user_id);
+CREATE INDEX IF NOT EXISTS idx_product_questions_deleted_at ON product_questions(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_product_answers_question_id ON product_answers(question_id);
+CREATE INDEX IF NOT EXISTS idx_product_answers_deleted_at ON product_answers(deleted_at);
+
+-- Add constraints
+ALTER TABLE product_answers ADD CONSTRAINT chk_product_answers_author_type CHECK (author_type IN ('seller', 'staff', 'customer'));
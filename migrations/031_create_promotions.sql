@@ -0,0 +1,42 @@
+-- Create promotions table
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    type VARCHAR(30) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+
+    -- Rule parameters
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    discount_percent DECIMAL(5,2) DEFAULT 0,
+    buy_quantity INTEGER DEFAULT 0,
+    get_quantity INTEGER DEFAULT 0,
+    min_spend DECIMAL(10,2) DEFAULT 0,
+    discount_amount DECIMAL(10,2) DEFAULT 0,
+
+    created_by INTEGER NOT NULL REFERENCES users(id),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Record the promotion applied to each order
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS promotion_id INTEGER REFERENCES promotions(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS promotion_name VARCHAR(255);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_promotions_is_active ON promotions(is_active);
+CREATE INDEX IF NOT EXISTS idx_promotions_window ON promotions(starts_at, ends_at);
+CREATE INDEX IF NOT EXISTS idx_promotions_category_id ON promotions(category_id);
+CREATE INDEX IF NOT EXISTS idx_promotions_product_id ON promotions(product_id);
+CREATE INDEX IF NOT EXISTS idx_promotions_deleted_at ON promotions(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_orders_promotion_id ON orders(promotion_id);
+
+-- Add constraints
+ALTER TABLE promotions ADD CONSTRAINT chk_promotions_type CHECK (type IN ('category_percent', 'bogo', 'spend_and_save'));
+ALTER TABLE promotions ADD CONSTRAINT chk_promotions_discount_percent CHECK (discount_percent >= 0 AND discount_percent <= 100);
+ALTER TABLE promotions ADD CONSTRAINT chk_promotions_window CHECK (ends_at IS NULL OR ends_at > starts_at);
@@ -0,0 +1,31 @@
+-- Create coupons table
+CREATE TABLE IF NOT EXISTS coupons (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    type VARCHAR(20) NOT NULL,
+    value DECIMAL(10,2) NOT NULL,
+    min_spend DECIMAL(10,2) DEFAULT 0,
+    usage_limit INTEGER,
+    used_count INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    expires_at TIMESTAMP,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Record the coupon applied to each order
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS coupon_id INTEGER REFERENCES coupons(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS coupon_code VARCHAR(50);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS coupon_discount DECIMAL(10,2) DEFAULT 0;
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_coupons_deleted_at ON coupons(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_orders_coupon_id ON orders(coupon_id);
+
+-- Add constraints
+ALTER TABLE coupons ADD CONSTRAINT chk_coupons_type CHECK (type IN ('percent', 'fixed'));
+ALTER TABLE coupons ADD CONSTRAINT chk_coupons_value CHECK (value > 0);
+ALTER TABLE coupons ADD CONSTRAINT chk_coupons_usage CHECK (usage_limit IS NULL OR used_count <= usage_limit);
@@ -0,0 +1,14 @@
+-- awaiting_confirmation is longer than the original VARCHAR(20) status columns
+ALTER TABLE orders ALTER COLUMN status TYPE VARCHAR(30);
+ALTER TABLE order_fulfillments ALTER COLUMN status TYPE VARCHAR(30);
+ALTER TABLE order_status_histories ALTER COLUMN from_status TYPE VARCHAR(30);
+ALTER TABLE order_status_histories ALTER COLUMN to_status TYPE VARCHAR(30);
+
+-- Speed up the order confirmation queue
+CREATE INDEX IF NOT EXISTS idx_orders_awaiting_confirmation ON orders(updated_at) WHERE status = 'awaiting_confirmation';
+
+-- Allow the awaiting_confirmation status
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status CHECK (status IN ('pending', 'pending_review', 'awaiting_confirmation', 'confirmed', 'processing', 'shipped', 'delivered', 'cancelled', 'refunded'));
+ALTER TABLE order_fulfillments DROP CONSTRAINT IF EXISTS chk_order_fulfillments_status;
+ALTER TABLE order_fulfillments ADD CONSTRAINT chk_order_fulfillments_status CHECK (status IN ('pending', 'pending_review', 'awaiting_confirmation', 'confirmed', 'processing', 'shipped', 'delivered', 'cancelled', 'refunded'));
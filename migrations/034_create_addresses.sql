@@ -0,0 +1,29 @@
+-- Create addresses table
+CREATE TABLE IF NOT EXISTS addresses (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label VARCHAR(50) NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    phone VARCHAR(20),
+    street VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(100) NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    postal_code VARCHAR(20) NOT NULL,
+    is_default_shipping BOOLEAN DEFAULT FALSE,
+    is_default_billing BOOLEAN DEFAULT FALSE,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_addresses_user_id ON addresses(user_id);
+CREATE INDEX IF NOT EXISTS idx_addresses_deleted_at ON addresses(deleted_at);
+
+-- Add constraints
+-- At most one default of each kind per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_default_shipping ON addresses(user_id) WHERE is_default_shipping AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_default_billing ON addresses(user_id) WHERE is_default_billing AND deleted_at IS NULL;
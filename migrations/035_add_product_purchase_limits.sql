@@ -0,0 +1,24 @@
+-- Cap how many units of a product one customer may buy (0 = unlimited, window 0 = lifetime)
+ALTER TABLE products ADD COLUMN IF NOT EXISTS purchase_limit_per_customer INTEGER DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS purchase_limit_window_days INTEGER DEFAULT 0;
+
+-- Create purchase_limit_exemptions table
+CREATE TABLE IF NOT EXISTS purchase_limit_exemptions (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_purchase_limit_exemptions_product_user ON purchase_limit_exemptions(product_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_limit_exemptions_deleted_at ON purchase_limit_exemptions(deleted_at);
+
+-- Add constraints
+ALTER TABLE products ADD CONSTRAINT chk_products_purchase_limit_per_customer CHECK (purchase_limit_per_customer >= 0);
+ALTER TABLE products ADD CONSTRAINT chk_products_purchase_limit_window_days CHECK (purchase_limit_window_days >= 0);
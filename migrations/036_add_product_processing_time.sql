@@ -0,0 +1,8 @@
+-- Business days a product needs before it ships (NULL uses the store default)
+ALTER TABLE products ADD COLUMN IF NOT EXISTS processing_time_days INTEGER;
+ALTER TABLE products ADD CONSTRAINT chk_products_processing_time_days CHECK (processing_time_days IS NULL OR processing_time_days >= 0);
+
+-- Delivery estimate made at checkout
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS processing_time_days INTEGER DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS estimated_ship_date TIMESTAMP;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS estimated_delivery_date TIMESTAMP;
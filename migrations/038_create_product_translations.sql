@@ -0,0 +1,23 @@
+-- Create product_translations table
+CREATE TABLE IF NOT EXISTS product_translations (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    short_description VARCHAR(500),
+    meta_title VARCHAR(255),
+    meta_description VARCHAR(500),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_translations_product_locale ON product_translations(product_id, locale);
+CREATE INDEX IF NOT EXISTS idx_product_translations_deleted_at ON product_translations(deleted_at);
+
+-- Add constraints
+-- Language code with an optional region, e.g. fr or pt-BR
+ALTER TABLE product_translations ADD CONSTRAINT chk_product_translations_locale CHECK (locale ~ '^[a-z]{2,3}(-[A-Z]{2})?$');
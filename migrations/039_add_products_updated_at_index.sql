@@ -0,0 +1,3 @@
+-- Keyset index for the product change feed (GET /products/changes), which
+-- walks products, soft-deleted ones included, in (updated_at, id) order
+CREATE INDEX IF NOT EXISTS idx_products_updated_at_id ON products(updated_at, id);
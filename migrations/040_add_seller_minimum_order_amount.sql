@@ -0,0 +1,3 @@
+-- Least a customer must spend with a seller per order, after discounts (NULL or 0 for none)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS minimum_order_amount DECIMAL(10,2);
+ALTER TABLE users ADD CONSTRAINT chk_users_minimum_order_amount CHECK (minimum_order_amount IS NULL OR minimum_order_amount >= 0);
@@ -0,0 +1,23 @@
+-- Create review_edits table
+CREATE TABLE IF NOT EXISTS review_edits (
+    id SERIAL PRIMARY KEY,
+    review_id INTEGER NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    previous_rating INTEGER NOT NULL,
+    previous_title VARCHAR(255),
+    previous_comment TEXT,
+    edited_by INTEGER NOT NULL REFERENCES users(id),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- When the review was last edited by its author
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_review_edits_review_id ON review_edits(review_id);
+CREATE INDEX IF NOT EXISTS idx_review_edits_deleted_at ON review_edits(deleted_at);
+
+-- Add constraints
+ALTER TABLE review_edits ADD CONSTRAINT chk_review_edits_previous_rating CHECK (previous_rating >= 1 AND previous_rating <= 5);
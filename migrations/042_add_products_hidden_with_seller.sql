@@ -0,0 +1,3 @@
+-- Products hidden because their seller was deactivated; reactivation restores only these
+ALTER TABLE products ADD COLUMN IF NOT EXISTS hidden_with_seller BOOLEAN DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_products_seller_hidden_with_seller ON products(seller_id) WHERE hidden_with_seller = TRUE;
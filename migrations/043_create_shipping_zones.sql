@@ -0,0 +1,32 @@
+-- Create shipping_zones table
+CREATE TABLE IF NOT EXISTS shipping_zones (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    countries VARCHAR(1000),
+    regions VARCHAR(1000),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create shipping_rates table
+CREATE TABLE IF NOT EXISTS shipping_rates (
+    id SERIAL PRIMARY KEY,
+    zone_id INTEGER NOT NULL REFERENCES shipping_zones(id) ON DELETE CASCADE,
+    min_weight DECIMAL(8,3) NOT NULL DEFAULT 0,
+    max_weight DECIMAL(8,3),
+    cost DECIMAL(10,2) NOT NULL
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_shipping_zones_is_default ON shipping_zones(is_default);
+CREATE INDEX IF NOT EXISTS idx_shipping_zones_deleted_at ON shipping_zones(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_shipping_rates_zone_id ON shipping_rates(zone_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shipping_zones_single_default ON shipping_zones(is_default) WHERE is_default = TRUE AND deleted_at IS NULL;
+
+-- Add constraints
+ALTER TABLE shipping_rates ADD CONSTRAINT chk_shipping_rates_min_weight CHECK (min_weight >= 0);
+ALTER TABLE shipping_rates ADD CONSTRAINT chk_shipping_rates_max_weight CHECK (max_weight IS NULL OR max_weight > min_weight);
+ALTER TABLE shipping_rates ADD CONSTRAINT chk_shipping_rates_cost CHECK (cost >= 0);
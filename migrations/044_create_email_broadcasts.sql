@@ -0,0 +1,39 @@
+-- Create email_broadcasts table
+CREATE TABLE IF NOT EXISTS email_broadcasts (
+    id SERIAL PRIMARY KEY,
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    audience VARCHAR(20) NOT NULL,
+    total_recipients INTEGER DEFAULT 0,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create email_deliveries table: one row per broadcast recipient, worked through by the email dispatcher
+CREATE TABLE IF NOT EXISTS email_deliveries (
+    id SERIAL PRIMARY KEY,
+    broadcast_id INTEGER NOT NULL REFERENCES email_broadcasts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_email_broadcasts_deleted_at ON email_broadcasts(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_email_deliveries_broadcast_id ON email_deliveries(broadcast_id, status);
+CREATE INDEX IF NOT EXISTS idx_email_deliveries_due ON email_deliveries(next_attempt_at) WHERE status = 'pending';
+
+-- Add constraints
+ALTER TABLE email_broadcasts ADD CONSTRAINT chk_email_broadcasts_audience CHECK (audience IN ('all', 'customers', 'sellers'));
+ALTER TABLE email_deliveries ADD CONSTRAINT chk_email_deliveries_status CHECK (status IN ('pending', 'sent', 'failed'));
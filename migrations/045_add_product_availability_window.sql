@@ -0,0 +1,6 @@
+-- Seasonal availability window; NULL leaves that side of the window open
+ALTER TABLE products ADD COLUMN IF NOT EXISTS available_from TIMESTAMP WITH TIME ZONE;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS available_until TIMESTAMP WITH TIME ZONE;
+ALTER TABLE products ADD CONSTRAINT chk_products_availability_window CHECK (available_until IS NULL OR available_from IS NULL OR available_until >= available_from);
+CREATE INDEX IF NOT EXISTS idx_products_available_from ON products(available_from);
+CREATE INDEX IF NOT EXISTS idx_products_available_until ON products(available_until);
@@ -0,0 +1,3 @@
+-- Units checkout may take stock below zero (0 is strict)
+ALTER TABLE products ADD COLUMN IF NOT EXISTS oversell_tolerance INTEGER DEFAULT 0;
+ALTER TABLE products ADD CONSTRAINT chk_products_oversell_tolerance CHECK (oversell_tolerance >= 0);
@@ -0,0 +1,10 @@
+-- When the customer was asked to review a delivered order's products
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS review_reminder_sent_at TIMESTAMP;
+
+-- Customers can opt out of review reminder emails
+ALTER TABLE users ADD COLUMN IF NOT EXISTS review_reminders_opt_out BOOLEAN DEFAULT FALSE;
+
+-- Orders delivered before reminders existed aren't reminded after the fact
+UPDATE orders SET review_reminder_sent_at = NOW() WHERE status = 'delivered' AND review_reminder_sent_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_orders_review_reminder_due ON orders (delivered_at) WHERE status = 'delivered' AND review_reminder_sent_at IS NULL;
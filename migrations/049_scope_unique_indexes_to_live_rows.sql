@@ -0,0 +1,19 @@
+-- Soft-deleted rows keep their values, so uniqueness only applies to live rows;
+-- otherwise a deleted product's SKU or slug, a deleted coupon's code or a
+-- deleted account's email could never be used again
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_sku_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku_unique ON products(sku) WHERE deleted_at IS NULL;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_slug_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_slug_unique ON products(slug) WHERE deleted_at IS NULL;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_unique ON users(email) WHERE deleted_at IS NULL;
+
+ALTER TABLE coupons DROP CONSTRAINT IF EXISTS coupons_code_key;
+DROP INDEX IF EXISTS idx_coupons_code;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code_unique ON coupons(code) WHERE deleted_at IS NULL;
+
+-- A customer whose review was deleted can review the product again
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_product_id_user_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_product_user_unique ON reviews(product_id, user_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,9 @@
+-- Orders past the retention period are marked archived and left out of order lists
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+-- Order lists only read live orders unless archived ones are asked for
+CREATE INDEX IF NOT EXISTS idx_orders_customer_live ON orders (customer_id, created_at DESC) WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_created_at_live ON orders (created_at DESC) WHERE archived_at IS NULL;
+
+-- Finds finished orders still waiting to be archived
+CREATE INDEX IF NOT EXISTS idx_orders_archive_due ON orders (created_at) WHERE archived_at IS NULL AND status IN ('delivered', 'cancelled', 'refunded');
@@ -0,0 +1,2 @@
+-- Set when an image's alt text was filled in from the product name because none was given
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS alt_text_generated BOOLEAN DEFAULT FALSE;
@@ -0,0 +1,14 @@
+-- Seller staff accounts belong to the seller that created them
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users ADD CONSTRAINT chk_users_role CHECK (role IN ('customer', 'seller', 'admin', 'seller_staff'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS employer_id INTEGER REFERENCES users(id);
+CREATE INDEX IF NOT EXISTS idx_users_employer_id ON users (employer_id);
+
+-- Orders, or a seller's portion of a split order, can be assigned to a staff member
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS assigned_to INTEGER REFERENCES users(id);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_orders_assigned_to ON orders (assigned_to);
+
+ALTER TABLE order_fulfillments ADD COLUMN IF NOT EXISTS assigned_to INTEGER REFERENCES users(id);
+ALTER TABLE order_fulfillments ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_order_fulfillments_assigned_to ON order_fulfillments (assigned_to);
@@ -0,0 +1,7 @@
+-- Coupons that only discount one product or the products in one category
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS product_id INTEGER REFERENCES products(id);
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES categories(id);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_coupons_product_id ON coupons(product_id);
+CREATE INDEX IF NOT EXISTS idx_coupons_category_id ON coupons(category_id);
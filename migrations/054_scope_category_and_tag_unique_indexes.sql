@@ -0,0 +1,18 @@
+-- Categories and tags are soft-deleted through their models, but the tables
+-- had no deleted_at column, so give them one and scope name and slug
+-- uniqueness to live rows like 049 did for products
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories(deleted_at);
+
+-- The model has always written sort_order, which the table never had
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS sort_order INTEGER DEFAULT 0;
+
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_slug_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug_unique ON categories(slug) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name_unique ON categories(name) WHERE deleted_at IS NULL;
+
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tags(deleted_at);
+
+DROP INDEX IF EXISTS idx_tags_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_unique ON tags(name) WHERE deleted_at IS NULL;
@@ -0,0 +1,6 @@
+-- Guest checkouts get a user record that can't sign in until it's claimed by registering
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_guest BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_users_is_guest ON users (is_guest);
+
+-- Hash of the token a guest pays for their order with
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS guest_token_hash VARCHAR(64);
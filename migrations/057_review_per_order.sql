@@ -0,0 +1,4 @@
+-- Reviews tied to an order are verified purchases, and a product bought in
+-- several orders can be reviewed once per order, plus once without one
+DROP INDEX IF EXISTS idx_reviews_product_user_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_product_user_order_unique ON reviews(product_id, user_id, COALESCE(order_id, 0)) WHERE deleted_at IS NULL;
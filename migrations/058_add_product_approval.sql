@@ -0,0 +1,7 @@
+-- Sellers' products can wait for an admin's approval before they're listed
+ALTER TABLE products DROP CONSTRAINT IF EXISTS chk_products_status;
+ALTER TABLE products ADD CONSTRAINT chk_products_status CHECK (status IN ('draft', 'active', 'inactive', 'deleted', 'pending_approval'));
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+
+-- Admin approval queue
+CREATE INDEX IF NOT EXISTS idx_products_pending_approval ON products (updated_at) WHERE status = 'pending_approval';
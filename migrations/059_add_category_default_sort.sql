@@ -0,0 +1,5 @@
+-- Per-category sort for product listings that don't ask for one
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS default_sort VARCHAR(20);
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS chk_categories_default_sort;
+ALTER TABLE categories ADD CONSTRAINT chk_categories_default_sort
+    CHECK (default_sort IS NULL OR default_sort IN ('newest', 'best_selling', 'price_asc', 'price_desc', 'rating', 'popular', 'name'));
@@ -0,0 +1,29 @@
+-- Create return_requests table
+CREATE TABLE IF NOT EXISTS return_requests (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id INTEGER NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    customer_id INTEGER NOT NULL REFERENCES users(id),
+    quantity INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested',
+    refund_amount DECIMAL(10,2) DEFAULT 0,
+    resolution_note TEXT,
+    resolved_by INTEGER REFERENCES users(id),
+    resolved_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_return_requests_order_id ON return_requests(order_id);
+CREATE INDEX IF NOT EXISTS idx_return_requests_order_item_id ON return_requests(order_item_id);
+CREATE INDEX IF NOT EXISTS idx_return_requests_customer_id ON return_requests(customer_id);
+CREATE INDEX IF NOT EXISTS idx_return_requests_status ON return_requests(status);
+CREATE INDEX IF NOT EXISTS idx_return_requests_deleted_at ON return_requests(deleted_at);
+
+-- Add constraints
+ALTER TABLE return_requests ADD CONSTRAINT chk_return_requests_quantity CHECK (quantity > 0);
+ALTER TABLE return_requests ADD CONSTRAINT chk_return_requests_status CHECK (status IN ('requested', 'approved', 'refunded', 'rejected'));
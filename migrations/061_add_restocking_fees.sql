@@ -0,0 +1,12 @@
+-- Percent of a non-defective return's refund kept as a restocking fee; a product's
+-- overrides its seller's (NULL uses the seller's, or none)
+ALTER TABLE products ADD COLUMN IF NOT EXISTS restocking_fee_percent DECIMAL(5,2);
+ALTER TABLE products ADD CONSTRAINT chk_products_restocking_fee_percent CHECK (restocking_fee_percent IS NULL OR restocking_fee_percent BETWEEN 0 AND 100);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS restocking_fee_percent DECIMAL(5,2);
+ALTER TABLE users ADD CONSTRAINT chk_users_restocking_fee_percent CHECK (restocking_fee_percent IS NULL OR restocking_fee_percent BETWEEN 0 AND 100);
+
+-- The fee is fixed when the return is requested and charged when it's refunded
+ALTER TABLE return_requests ADD COLUMN IF NOT EXISTS defective BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE return_requests ADD COLUMN IF NOT EXISTS restocking_fee_percent DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE return_requests ADD COLUMN IF NOT EXISTS restocking_fee DECIMAL(10,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Coupons only one customer can use, such as the coupon issued when they register
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_coupons_user_id ON coupons(user_id);
+
+-- Each customer is issued one welcome coupon
+ALTER TABLE users ADD COLUMN IF NOT EXISTS welcome_coupon_id INTEGER REFERENCES coupons(id);
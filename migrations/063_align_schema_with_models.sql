@@ -0,0 +1,49 @@
+-- Columns the models write that the earlier migrations never created. Each is
+-- added without a default first so only rows that predate it are backfilled,
+-- and running this again changes nothing.
+
+-- Products: stock is the live count checkout, holds and restocks adjust;
+-- stock_quantity was only ever the initial count
+ALTER TABLE products ADD COLUMN IF NOT EXISTS stock INTEGER;
+UPDATE products SET stock = stock_quantity WHERE stock IS NULL;
+ALTER TABLE products ALTER COLUMN stock SET DEFAULT 0;
+ALTER TABLE products ALTER COLUMN stock SET NOT NULL;
+
+-- Public listings filter on is_active, which follows visibility and is off
+-- while the seller is deactivated
+ALTER TABLE products ADD COLUMN IF NOT EXISTS is_active BOOLEAN;
+UPDATE products SET is_active = COALESCE(visible, true) AND NOT COALESCE(hidden_with_seller, false) WHERE is_active IS NULL;
+ALTER TABLE products ALTER COLUMN is_active SET DEFAULT true;
+CREATE INDEX IF NOT EXISTS idx_products_is_active ON products(is_active);
+
+-- Rating aggregates kept on the product, from its approved live reviews
+ALTER TABLE products ADD COLUMN IF NOT EXISTS average_rating DECIMAL(3,2);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS review_count INTEGER;
+UPDATE products
+SET average_rating = COALESCE((SELECT AVG(rating) FROM reviews WHERE reviews.product_id = products.id AND is_approved = true AND deleted_at IS NULL), 0),
+    review_count = (SELECT COUNT(*) FROM reviews WHERE reviews.product_id = products.id AND is_approved = true AND deleted_at IS NULL)
+WHERE average_rating IS NULL;
+ALTER TABLE products ALTER COLUMN average_rating SET DEFAULT 0;
+ALTER TABLE products ALTER COLUMN review_count SET DEFAULT 0;
+
+-- Notifications: the types grew past the original enum, and GORM writes the
+-- data payload and soft-deletes
+ALTER TABLE notifications ALTER COLUMN type DROP DEFAULT;
+ALTER TABLE notifications ALTER COLUMN type TYPE VARCHAR(50) USING type::text;
+ALTER TABLE notifications ALTER COLUMN type SET DEFAULT 'general';
+DROP TYPE IF EXISTS notification_type;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS data JSON;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_notifications_deleted_at ON notifications(deleted_at);
+
+-- Auth tokens record when they were redeemed rather than a used flag
+ALTER TABLE password_reset_tokens ADD COLUMN IF NOT EXISTS used_at TIMESTAMP WITH TIME ZONE;
+UPDATE password_reset_tokens SET used_at = created_at WHERE used = true AND used_at IS NULL;
+ALTER TABLE password_reset_tokens ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE password_reset_tokens ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE email_verification_tokens ADD COLUMN IF NOT EXISTS used_at TIMESTAMP WITH TIME ZONE;
+UPDATE email_verification_tokens SET used_at = created_at WHERE used = true AND used_at IS NULL;
+ALTER TABLE email_verification_tokens ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE email_verification_tokens ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
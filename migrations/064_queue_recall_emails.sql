@@ -0,0 +1,8 @@
+-- Recall notices are emailed through the same rate-limited queue as
+-- broadcasts: each delivery belongs to either a broadcast or a recall
+ALTER TABLE email_deliveries ALTER COLUMN broadcast_id DROP NOT NULL;
+ALTER TABLE email_deliveries ADD COLUMN IF NOT EXISTS recall_id INTEGER REFERENCES product_recalls(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_email_deliveries_recall_id ON email_deliveries(recall_id);
+
+ALTER TABLE email_deliveries DROP CONSTRAINT IF EXISTS chk_email_deliveries_source;
+ALTER TABLE email_deliveries ADD CONSTRAINT chk_email_deliveries_source CHECK ((broadcast_id IS NULL) <> (recall_id IS NULL));
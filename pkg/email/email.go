@@ -1,17 +1,26 @@
 package email
 
 import (
+	"time"
+
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
 )
 
 // Service defines the email service interface
 type Service interface {
 	SendWelcomeEmail(to, name string) error
+	SendWelcomeCouponEmail(to, name, code, discount string, expiresAt *time.Time) error
 	SendOrderConfirmationEmail(to string, order *models.Order) error
 	SendOrderShippedEmail(to string, order *models.Order) error
 	SendOrderDeliveredEmail(to string, order *models.Order) error
 	SendPasswordResetEmail(to, resetLink string) error
+	SendEmailVerificationEmail(to, verificationLink string) error
 	SendInvoiceEmail(to string, order *models.Order) error
+	SendProductRecallEmail(to, name, productName, message string) error
+	SendOversellAlertEmail(to, name, productName, orderNumber string, stock int) error
+	SendBroadcastEmail(to, name, subject, message string) error
+	SendReviewReminderEmail(to, name, orderNumber string, productNames []string) error
+	SendGuestOrderEmail(to, name, orderNumber string) error
 }
 
 // EmailTemplate represents an email template
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"html/template"
 	"net/smtp"
+	"strings"
+	"time"
 
 	"github.com/JonathanVera18/ecommerce-api/internal/config"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
@@ -60,6 +62,32 @@ func (s *smtpService) SendWelcomeEmail(to, name string) error {
 	return s.sendEmail(to, subject, body, true)
 }
 
+// SendWelcomeCouponEmail welcomes a new customer with the coupon they were issued
+func (s *smtpService) SendWelcomeCouponEmail(to, name, code, discount string, expiresAt *time.Time) error {
+	subject := "Welcome! Here's a coupon for your first order"
+
+	expiry := ""
+	if expiresAt != nil {
+		expiry = fmt.Sprintf(" It's valid until %s.", expiresAt.Format("January 2, 2006"))
+	}
+
+	body := fmt.Sprintf(`
+		<html>
+		<body>
+			<h1>Welcome %s!</h1>
+			<p>Thank you for joining our e-commerce platform. We're excited to have you as part of our community.</p>
+			<p>To get you started, here's <strong>%s</strong> your first order. Enter this code at checkout:</p>
+			<p style="font-size: 20px;"><strong>%s</strong></p>
+			<p>The code can be used once, on your account only.%s</p>
+			<br>
+			<p>Best regards,<br>The E-commerce Team</p>
+		</body>
+		</html>
+	`, template.HTMLEscapeString(name), template.HTMLEscapeString(discount), template.HTMLEscapeString(code), expiry)
+	
+	return s.sendEmail(to, subject, body, true)
+}
+
 func (s *smtpService) SendOrderConfirmationEmail(to string, order *models.Order) error {
 	subject := fmt.Sprintf("Order Confirmation - Order #%s", order.OrderNumber)
 	
@@ -74,6 +102,9 @@ func (s *smtpService) SendOrderConfirmationEmail(to string, order *models.Order)
 			<p><strong>Order Number:</strong> {{.OrderNumber}}</p>
 			<p><strong>Order Date:</strong> {{.CreatedAt.Format "January 2, 2006"}}</p>
 			<p><strong>Total Amount:</strong> ${{printf "%.2f" .TotalAmount}}</p>
+			{{if .EstimatedDeliveryDate}}
+			<p><strong>{{.ShippingEstimate}}</strong>, estimated delivery by {{.EstimatedDeliveryDate.Format "January 2, 2006"}}</p>
+			{{end}}
 			
 			<h3>Items Ordered</h3>
 			<table border="1" style="border-collapse: collapse; width: 100%;">
@@ -181,12 +212,152 @@ func (s *smtpService) SendPasswordResetEmail(to, resetLink string) error {
 			<p>Click the link below to reset your password:</p>
 			<p><a href="%s">Reset Password</a></p>
 			<p>If you didn't request this password reset, you can safely ignore this email.</p>
-			<p>This link will expire in 1 hour.</p>
+			<p>This link will expire in %s.</p>
+			
+			<p>Best regards,<br>The E-commerce Team</p>
+		</body>
+		</html>
+	`, resetLink, formatTTL(s.config.Auth.PasswordResetTTL))
+	
+	return s.sendEmail(to, subject, body, true)
+}
+
+func (s *smtpService) SendEmailVerificationEmail(to, verificationLink string) error {
+	subject := "Verify Your Email Address"
+	body := fmt.Sprintf(`
+		<html>
+		<body>
+			<h1>Verify Your Email Address</h1>
+			<p>Thanks for signing up! Please confirm your email address.</p>
+			<p><a href="%s">Verify Email</a></p>
+			<p>If you didn't create an account, you can safely ignore this email.</p>
+			<p>This link will expire in %s.</p>
+			
+			<p>Best regards,<br>The E-commerce Team</p>
+		</body>
+		</html>
+	`, verificationLink, formatTTL(s.config.Auth.EmailVerificationTTL))
+	
+	return s.sendEmail(to, subject, body, true)
+}
+
+// formatTTL renders a token lifetime for email copy, e.g. "1 hour" or "30 minutes"
+func formatTTL(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return pluralize(int(d/time.Hour), "hour")
+	case d >= time.Minute:
+		return pluralize(int(d/time.Minute), "minute")
+	default:
+		return pluralize(int(d/time.Second), "second")
+	}
+}
+
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func (s *smtpService) SendProductRecallEmail(to, name, productName, message string) error {
+	subject := fmt.Sprintf("Important Recall Notice: %s", productName)
+	body := fmt.Sprintf(`
+		<html>
+		<body>
+			<h1>Product Recall Notice</h1>
+			<p>Hi %s,</p>
+			<p>You recently purchased <strong>%s</strong>, which is subject to a recall.</p>
+			<p>%s</p>
+			<p>If you have any questions, feel free to contact our support team.</p>
+			
+			<p>Best regards,<br>The E-commerce Team</p>
+		</body>
+		</html>
+	`, template.HTMLEscapeString(name), template.HTMLEscapeString(productName), template.HTMLEscapeString(message))
+	
+	return s.sendEmail(to, subject, body, true)
+}
+
+func (s *smtpService) SendOversellAlertEmail(to, name, productName, orderNumber string, stock int) error {
+	subject := fmt.Sprintf("Urgent: %s is oversold", productName)
+	body := fmt.Sprintf(`
+		<html>
+		<body>
+			<h1>Product Oversold</h1>
+			<p>Hi %s,</p>
+			<p>Order <strong>%s</strong> took <strong>%s</strong> below zero stock. It now stands at <strong>%d</strong>.</p>
+			<p>Please restock or contact the customer before the order ships.</p>
+			
+			<p>Best regards,<br>The E-commerce Team</p>
+		</body>
+		</html>
+	`, template.HTMLEscapeString(name), template.HTMLEscapeString(orderNumber), template.HTMLEscapeString(productName), stock)
+	
+	return s.sendEmail(to, subject, body, true)
+}
+
+func (s *smtpService) SendReviewReminderEmail(to, name, orderNumber string, productNames []string) error {
+	subject := fmt.Sprintf("How was your order #%s?", orderNumber)
+
+	var items strings.Builder
+	for _, productName := range productNames {
+		items.WriteString("<li>" + template.HTMLEscapeString(productName) + "</li>")
+	}
+
+	body := fmt.Sprintf(`
+		<html>
+		<body>
+			<h1>Tell us what you think</h1>
+			<p>Hi %s,</p>
+			<p>Your order <strong>%s</strong> arrived a little while ago. We'd love to hear how you're getting on with:</p>
+			<ul>%s</ul>
+			<p>A quick review helps other shoppers and the sellers who made it.</p>
 			
 			<p>Best regards,<br>The E-commerce Team</p>
 		</body>
 		</html>
-	`, resetLink)
+	`, template.HTMLEscapeString(name), template.HTMLEscapeString(orderNumber), items.String())
+	
+	return s.sendEmail(to, subject, body, true)
+}
+
+// SendGuestOrderEmail confirms a guest's order. It carries no link to the
+// order: anyone the email is forwarded to could follow it.
+func (s *smtpService) SendGuestOrderEmail(to, name, orderNumber string) error {
+	subject := fmt.Sprintf("Your order #%s", orderNumber)
+
+	body := fmt.Sprintf(`
+		<html>
+		<body>
+			<h1>Thanks for your order</h1>
+			<p>Hi %s,</p>
+			<p>We've received your order <strong>%s</strong>. Keep this order number for any questions about it.</p>
+			<p>Create an account with this email address to keep all your orders in one place.</p>
+			
+			<p>Best regards,<br>The E-commerce Team</p>
+		</body>
+		</html>
+	`, template.HTMLEscapeString(name), template.HTMLEscapeString(orderNumber))
+	
+	return s.sendEmail(to, subject, body, true)
+}
+
+// SendBroadcastEmail sends an admin broadcast. The message is plain text; line
+// breaks are kept.
+func (s *smtpService) SendBroadcastEmail(to, name, subject, message string) error {
+	// The subject goes into a header, so it can't be allowed to start new ones
+	subject = strings.NewReplacer("\r", "", "\n", " ").Replace(subject)
+	body := fmt.Sprintf(`
+		<html>
+		<body>
+			<p>Hi %s,</p>
+			<p>%s</p>
+			
+			<p>Best regards,<br>The E-commerce Team</p>
+		</body>
+		</html>
+	`, template.HTMLEscapeString(name), strings.ReplaceAll(template.HTMLEscapeString(message), "\n", "<br>"))
 	
 	return s.sendEmail(to, subject, body, true)
 }
@@ -213,6 +384,7 @@ func (s *smtpService) SendInvoiceEmail(to string, order *models.Order) error {
 					<th>Description</th>
 					<th>Quantity</th>
 					<th>Unit Price</th>
+					<th>Discount</th>
 					<th>Total</th>
 				</tr>
 				{{range .OrderItems}}
@@ -220,7 +392,8 @@ func (s *smtpService) SendInvoiceEmail(to string, order *models.Order) error {
 					<td>{{.ProductName}}</td>
 					<td>{{.Quantity}}</td>
 					<td>${{printf "%.2f" .UnitPrice}}</td>
-					<td>${{printf "%.2f" .TotalPrice}}</td>
+					<td>{{if gt .DiscountAmount 0.0}}-${{printf "%.2f" .DiscountAmount}}{{else}}-{{end}}</td>
+					<td>${{printf "%.2f" .LineTotal}}</td>
 				</tr>
 				{{end}}
 			</table>
@@ -229,6 +402,7 @@ func (s *smtpService) SendInvoiceEmail(to string, order *models.Order) error {
 			<p><strong>Subtotal:</strong> ${{printf "%.2f" .SubtotalAmount}}</p>
 			<p><strong>Tax:</strong> ${{printf "%.2f" .TaxAmount}}</p>
 			<p><strong>Shipping:</strong> ${{printf "%.2f" .ShippingAmount}}</p>
+			{{if gt .DiscountAmount 0.0}}<p><strong>Discount:</strong> -${{printf "%.2f" .DiscountAmount}}</p>{{end}}
 			<p><strong>Total:</strong> ${{printf "%.2f" .TotalAmount}}</p>
 			
 			<p>Thank you for your business!</p>
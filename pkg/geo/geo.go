@@ -0,0 +1,70 @@
+package geo
+
+import (
+	"context"
+	"strings"
+)
+
+// Service defines the IP geolocation provider interface
+type Service interface {
+	Lookup(ctx context.Context, ip string) (*Location, error)
+}
+
+// Location represents the result of an IP lookup
+type Location struct {
+	CountryCode string `json:"country_code"` // ISO 3166-1 alpha-2
+	Region      string `json:"region,omitempty"`
+	City        string `json:"city,omitempty"`
+}
+
+// Defaults represents the currency and locale suggested for a country
+type Defaults struct {
+	Currency string
+	Locale   string
+}
+
+// Fallback values used when the country is unknown
+const (
+	DefaultCountry  = "US"
+	DefaultCurrency = "USD"
+	DefaultLocale   = "en-US"
+)
+
+var countryDefaults = map[string]Defaults{
+	"US": {Currency: "USD", Locale: "en-US"},
+	"CA": {Currency: "CAD", Locale: "en-CA"},
+	"MX": {Currency: "MXN", Locale: "es-MX"},
+	"GB": {Currency: "GBP", Locale: "en-GB"},
+	"DE": {Currency: "EUR", Locale: "de-DE"},
+	"FR": {Currency: "EUR", Locale: "fr-FR"},
+	"ES": {Currency: "EUR", Locale: "es-ES"},
+	"IT": {Currency: "EUR", Locale: "it-IT"},
+	"NL": {Currency: "EUR", Locale: "nl-NL"},
+	"EC": {Currency: "USD", Locale: "es-EC"},
+	"CO": {Currency: "COP", Locale: "es-CO"},
+	"BR": {Currency: "BRL", Locale: "pt-BR"},
+	"AR": {Currency: "ARS", Locale: "es-AR"},
+	"JP": {Currency: "JPY", Locale: "ja-JP"},
+	"AU": {Currency: "AUD", Locale: "en-AU"},
+	"IN": {Currency: "INR", Locale: "en-IN"},
+}
+
+// DefaultsForCountry returns the suggested currency and locale for a country code
+func DefaultsForCountry(countryCode string) Defaults {
+	if defaults, ok := countryDefaults[strings.ToUpper(countryCode)]; ok {
+		return defaults
+	}
+	return Defaults{Currency: DefaultCurrency, Locale: DefaultLocale}
+}
+
+// noopService never resolves a location
+type noopService struct{}
+
+// NewNoopService creates a geolocation service that always returns no location
+func NewNoopService() Service {
+	return &noopService{}
+}
+
+func (s *noopService) Lookup(ctx context.Context, ip string) (*Location, error) {
+	return nil, nil
+}
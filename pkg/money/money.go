@@ -0,0 +1,130 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Amount is a monetary value in the currency's minor units, e.g. cents for
+// USD. Totals are summed and split as Amounts so they can't drift by a cent;
+// convert with FromFloat and Float only where amounts are stored or shown.
+type Amount int64
+
+// RoundingMode controls how a fraction of a minor unit is rounded
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"   // Halves round away from zero
+	RoundHalfEven RoundingMode = "half_even" // Halves round to the even neighbour (banker's rounding)
+	RoundDown     RoundingMode = "down"      // Fractions are dropped, rounding toward zero
+)
+
+// MaxMinorUnits is the most decimal places supported; amounts are stored as
+// decimal(10,2) columns
+const MaxMinorUnits = 2
+
+// Settings in effect, replaced at startup by Configure
+var (
+	minorUnits = 2
+	scale      = 100.0
+	rounding   = RoundHalfUp
+)
+
+// Configure sets the number of minor units (decimal places) of the store's
+// currency and the rounding mode. Call once at startup.
+func Configure(units int, mode RoundingMode) error {
+	if units < 0 || units > MaxMinorUnits {
+		return fmt.Errorf("minor units must be between 0 and %d, got %d", MaxMinorUnits, units)
+	}
+	switch mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown:
+	default:
+		return fmt.Errorf("unknown rounding mode %q (use %s, %s or %s)", mode, RoundHalfUp, RoundHalfEven, RoundDown)
+	}
+
+	minorUnits = units
+	scale = math.Pow10(units)
+	rounding = mode
+	return nil
+}
+
+// MinorUnits returns the configured number of decimal places
+func MinorUnits() int {
+	return minorUnits
+}
+
+// FromFloat converts a decimal amount to minor units with the configured rounding
+func FromFloat(amount float64) Amount {
+	return Amount(round(amount * scale))
+}
+
+// FromMinor wraps an amount that is already in minor units
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Round rounds a decimal amount to the currency's minor units
+func Round(amount float64) float64 {
+	return FromFloat(amount).Float()
+}
+
+// Format formats a decimal amount with the currency's minor units, for messages
+func Format(amount float64) string {
+	return strconv.FormatFloat(Round(amount), 'f', minorUnits, 64)
+}
+
+// Float returns the amount as a decimal for storage and JSON
+func (a Amount) Float() float64 {
+	return float64(a) / scale
+}
+
+// Minor returns the amount in minor units, as payment providers expect it
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Times returns the amount multiplied by a whole quantity
+func (a Amount) Times(quantity int) Amount {
+	return a * Amount(quantity)
+}
+
+// Percent returns percent of the amount, rounded to minor units
+func (a Amount) Percent(percent float64) Amount {
+	return Amount(round(float64(a) * percent / 100))
+}
+
+// Share returns the part/whole share of the amount, rounded to minor units,
+// or zero when whole is zero
+func (a Amount) Share(part, whole Amount) Amount {
+	if whole == 0 {
+		return 0
+	}
+	return Amount(round(float64(a) * float64(part) / float64(whole)))
+}
+
+// Clamp limits the amount to between zero and max
+func (a Amount) Clamp(max Amount) Amount {
+	if a < 0 {
+		return 0
+	}
+	if a > max {
+		return max
+	}
+	return a
+}
+
+// round rounds a value in minor units to a whole number with the configured
+// mode. The value is first snapped to a millionth of a minor unit so binary
+// float error (1.005*100 = 100.4999...) doesn't decide the halfway case.
+func round(minor float64) float64 {
+	minor = math.Round(minor*1e6) / 1e6
+	switch rounding {
+	case RoundHalfEven:
+		return math.RoundToEven(minor)
+	case RoundDown:
+		return math.Trunc(minor)
+	default:
+		return math.Round(minor)
+	}
+}
@@ -0,0 +1,121 @@
+package money
+
+import "testing"
+
+// configure switches the settings for one test and restores the defaults after
+func configure(t *testing.T, units int, mode RoundingMode) {
+	t.Helper()
+	if err := Configure(units, mode); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	t.Cleanup(func() { Configure(2, RoundHalfUp) })
+}
+
+func TestFromFloatRoundingModes(t *testing.T) {
+	tests := []struct {
+		mode   RoundingMode
+		amount float64
+		want   Amount
+	}{
+		{RoundHalfUp, 1.005, 101},
+		{RoundHalfUp, 1.015, 102},
+		{RoundHalfUp, -1.005, -101},
+		{RoundHalfEven, 1.005, 100},
+		{RoundHalfEven, 1.015, 102},
+		{RoundHalfEven, 1.0051, 101},
+		{RoundDown, 1.009, 100},
+		{RoundDown, -1.009, -100},
+		// Binary float error mustn't push an exact amount down a cent
+		{RoundDown, 19.99, 1999},
+		{RoundDown, 0.29, 29},
+	}
+	for _, tt := range tests {
+		configure(t, 2, tt.mode)
+		if got := FromFloat(tt.amount); got != tt.want {
+			t.Errorf("%s: FromFloat(%v) = %d, want %d", tt.mode, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestZeroMinorUnits(t *testing.T) {
+	configure(t, 0, RoundHalfEven)
+
+	if got := FromFloat(2.5); got != 2 {
+		t.Errorf("FromFloat(2.5) = %d, want 2", got)
+	}
+	if got := FromFloat(3.5); got != 4 {
+		t.Errorf("FromFloat(3.5) = %d, want 4", got)
+	}
+	if got := Format(1234.5); got != "1234" {
+		t.Errorf("Format(1234.5) = %q, want %q", got, "1234")
+	}
+	if got := Amount(1500).Float(); got != 1500 {
+		t.Errorf("Float() = %v, want 1500", got)
+	}
+}
+
+func TestConfigureRejectsInvalidSettings(t *testing.T) {
+	if err := Configure(3, RoundHalfUp); err == nil {
+		t.Error("expected more than two minor units to be rejected")
+	}
+	if err := Configure(-1, RoundHalfUp); err == nil {
+		t.Error("expected negative minor units to be rejected")
+	}
+	if err := Configure(2, "ceiling"); err == nil {
+		t.Error("expected an unknown rounding mode to be rejected")
+	}
+	if MinorUnits() != 2 || rounding != RoundHalfUp {
+		t.Error("expected rejected settings to leave the current ones in place")
+	}
+}
+
+func TestSummedAmountsDoNotDrift(t *testing.T) {
+	// 0.1 + 0.2 != 0.3 in float64
+	var total Amount
+	for _, price := range []float64{0.1, 0.2} {
+		total += FromFloat(price)
+	}
+	if total.Float() != 0.3 {
+		t.Errorf("0.10 + 0.20 = %v, want 0.3", total.Float())
+	}
+
+	var sum Amount
+	for i := 0; i < 100; i++ {
+		sum += FromFloat(0.01)
+	}
+	if sum != 100 {
+		t.Errorf("100 x 0.01 = %d minor units, want 100", sum)
+	}
+}
+
+func TestPercentAndShareRound(t *testing.T) {
+	configure(t, 2, RoundHalfEven)
+
+	// 15% of 0.30 is 4.5 cents
+	if got := Amount(30).Percent(15); got != 4 {
+		t.Errorf("half-even 15%% of 30 = %d, want 4", got)
+	}
+	configure(t, 2, RoundHalfUp)
+	if got := Amount(30).Percent(15); got != 5 {
+		t.Errorf("half-up 15%% of 30 = %d, want 5", got)
+	}
+
+	// A 10.00 discount split 1:2 must still add up to 10.00
+	discount := Amount(1000)
+	first := discount.Share(100, 300)
+	if first != 333 {
+		t.Errorf("Share(100, 300) = %d, want 333", first)
+	}
+	if got := discount.Share(5, 0); got != 0 {
+		t.Errorf("Share with zero whole = %d, want 0", got)
+	}
+}
+
+func TestClamp(t *testing.T) {
+	if got := Amount(-5).Clamp(100); got != 0 {
+		t.Errorf("Clamp(-5) = %d, want 0", got)
+	}
+	if got := Amount(150).Clamp(100); got != 100 {
+		t.Errorf("Clamp(150) = %d, want 100", got)
+	}
+}
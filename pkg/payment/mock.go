@@ -0,0 +1,183 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/config"
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
+
+// Magic payment method tokens understood by MockService. Pass one as
+// payment_method_id; a token takes precedence over a magic amount.
+const (
+	MockTokenSuccess        = "tok_test_success"         // Payment succeeds
+	MockTokenDecline        = "tok_test_decline"         // Declined when the payment is created
+	MockTokenConfirmFailure = "tok_test_confirm_failure" // Created, then fails on confirmation
+)
+
+// Magic amounts understood by MockService, matched on the cents: any amount
+// ending in .02 is declined and any ending in .03 fails on confirmation.
+// Every other amount succeeds.
+const (
+	MockCentsDecline        = 2
+	MockCentsConfirmFailure = 3
+)
+
+// Mock payment errors, shaped like the provider's so callers can't tell the difference
+var (
+	ErrMockDeclined       = errors.New("card_declined: your card was declined (test mode)")
+	ErrMockConfirmFailure = errors.New("processing_error: an error occurred while processing your card (test mode)")
+	ErrMockNotFound       = errors.New("resource_missing: no such payment intent (test mode)")
+)
+
+type mockOutcome int
+
+const (
+	mockOutcomeSuccess mockOutcome = iota
+	mockOutcomeDecline
+	mockOutcomeConfirmFailure
+)
+
+type mockIntent struct {
+	info    PaymentInfo
+	outcome mockOutcome
+}
+
+// MockService is a sandbox payment service for QA. It never contacts the
+// provider and decides each payment's outcome from the magic tokens and
+// amounts above. Intents are kept in memory and are lost on restart.
+type MockService struct {
+	mu      sync.Mutex
+	intents map[string]*mockIntent
+}
+
+// NewMockService creates a sandbox payment service
+func NewMockService() *MockService {
+	return &MockService{intents: make(map[string]*mockIntent)}
+}
+
+// NewService returns the payment service for the configured mode: the mock
+// service when PAYMENT_MODE is "test", Stripe when it's "live". Any other mode
+// is an error rather than a guess.
+func NewService(cfg *config.Config) (Service, error) {
+	switch cfg.Payment.Mode {
+	case config.PaymentModeTest:
+		return NewMockService(), nil
+	case config.PaymentModeLive:
+		return NewStripeService(cfg), nil
+	}
+	return nil, fmt.Errorf("unknown payment mode %q", cfg.Payment.Mode)
+}
+
+func (s *MockService) CreatePaymentIntent(req *models.PaymentRequest) (string, error) {
+	outcome := mockOutcomeFor(req)
+	if outcome == mockOutcomeDecline {
+		return "", ErrMockDeclined
+	}
+
+	id := fmt.Sprintf("pi_test_%d", time.Now().UnixNano())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.intents[id] = &mockIntent{
+		info: PaymentInfo{
+			ID:       id,
+			Amount:   req.Amount,
+			Currency: req.Currency,
+			Status:   "requires_confirmation",
+		},
+		outcome: outcome,
+	}
+
+	return id, nil
+}
+
+func (s *MockService) ConfirmPayment(paymentIntentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[paymentIntentID]
+	if !ok {
+		return ErrMockNotFound
+	}
+
+	if intent.outcome == mockOutcomeConfirmFailure {
+		intent.info.Status = "requires_payment_method"
+		return ErrMockConfirmFailure
+	}
+
+	intent.info.Status = "succeeded"
+	intent.info.AmountReceived = intent.info.Amount
+	return nil
+}
+
+func (s *MockService) RefundPayment(paymentIntentID string, amount float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[paymentIntentID]
+	if !ok {
+		return ErrMockNotFound
+	}
+	if intent.info.Status != "succeeded" {
+		return errors.New("charge_not_refundable: payment has not succeeded (test mode)")
+	}
+	if amount > intent.info.AmountReceived {
+		return errors.New("amount_too_large: refund exceeds the payment amount (test mode)")
+	}
+
+	return nil
+}
+
+func (s *MockService) GetPayment(paymentIntentID string) (*PaymentInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[paymentIntentID]
+	if !ok {
+		return nil, ErrMockNotFound
+	}
+
+	info := intent.info
+	return &info, nil
+}
+
+// ParseWebhookEvent decodes the payload as a WebhookEvent without checking a
+// signature, so QA can post payment and dispute events directly
+func (s *MockService) ParseWebhookEvent(payload []byte, signature string) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid test webhook payload: %w", err)
+	}
+	if event.Type == "" {
+		return nil, errors.New("invalid test webhook payload: missing type")
+	}
+	return &event, nil
+}
+
+// mockOutcomeFor picks the outcome from the payment method token, falling back to the amount
+func mockOutcomeFor(req *models.PaymentRequest) mockOutcome {
+	if req.PaymentMethodID != nil {
+		switch *req.PaymentMethodID {
+		case MockTokenSuccess:
+			return mockOutcomeSuccess
+		case MockTokenDecline:
+			return mockOutcomeDecline
+		case MockTokenConfirmFailure:
+			return mockOutcomeConfirmFailure
+		}
+	}
+
+	switch int(math.Round(req.Amount*100)) % 100 {
+	case MockCentsDecline:
+		return mockOutcomeDecline
+	case MockCentsConfirmFailure:
+		return mockOutcomeConfirmFailure
+	}
+	return mockOutcomeSuccess
+}
@@ -1,6 +1,10 @@
 package payment
 
-import "github.com/JonathanVera18/ecommerce-api/internal/models"
+import (
+	"time"
+
+	"github.com/JonathanVera18/ecommerce-api/internal/models"
+)
 
 // Service defines the payment service interface
 type Service interface {
@@ -8,14 +12,38 @@ type Service interface {
 	ConfirmPayment(paymentIntentID string) error
 	RefundPayment(paymentIntentID string, amount float64) error
 	GetPayment(paymentIntentID string) (*PaymentInfo, error)
+	ParseWebhookEvent(payload []byte, signature string) (*WebhookEvent, error)
 }
 
 // PaymentInfo represents payment information
 type PaymentInfo struct {
-	ID       string  `json:"id"`
-	Amount   float64 `json:"amount"`
-	Currency string  `json:"currency"`
-	Status   string  `json:"status"`
+	ID             string  `json:"id"`
+	Amount         float64 `json:"amount"`
+	AmountReceived float64 `json:"amount_received"` // Amount actually collected; 0 until the payment succeeds
+	Currency       string  `json:"currency"`
+	Status         string  `json:"status"`
+}
+
+// EventPaymentSucceeded is the webhook event type sent once a payment has been collected
+const EventPaymentSucceeded = "payment_intent.succeeded"
+
+// WebhookEvent represents a verified webhook event from the payment provider
+type WebhookEvent struct {
+	ID              string       `json:"id"`
+	Type            string       `json:"type"`
+	PaymentIntentID string       `json:"payment_intent_id,omitempty"` // Set for EventPaymentSucceeded
+	Dispute         *DisputeInfo `json:"dispute,omitempty"`           // Set for dispute events
+}
+
+// DisputeInfo represents a dispute (chargeback) reported by the payment provider
+type DisputeInfo struct {
+	ID              string     `json:"id"`
+	PaymentIntentID string     `json:"payment_intent_id"`
+	Amount          float64    `json:"amount"`
+	Currency        string     `json:"currency"`
+	Reason          string     `json:"reason"`
+	Status          string     `json:"status"`
+	EvidenceDueBy   *time.Time `json:"evidence_due_by,omitempty"`
 }
 
 // PaymentResult represents the result of a payment operation
@@ -1,12 +1,18 @@
 package payment
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/stripe/stripe-go/v76"
 	"github.com/stripe/stripe-go/v76/paymentintent"
+	"github.com/stripe/stripe-go/v76/webhook"
 	"github.com/JonathanVera18/ecommerce-api/internal/config"
 	"github.com/JonathanVera18/ecommerce-api/internal/models"
+	"github.com/JonathanVera18/ecommerce-api/pkg/money"
 )
 
 type stripeService struct {
@@ -24,7 +30,7 @@ func NewStripeService(cfg *config.Config) Service {
 
 func (s *stripeService) CreatePaymentIntent(req *models.PaymentRequest) (string, error) {
 	params := &stripe.PaymentIntentParams{
-		Amount:   stripe.Int64(int64(req.Amount * 100)), // Convert to cents
+		Amount:   stripe.Int64(money.FromFloat(req.Amount).Minor()), // Convert to minor units
 		Currency: stripe.String(req.Currency),
 		Metadata: map[string]string{
 			"order_id": fmt.Sprintf("%d", req.OrderID),
@@ -64,9 +70,63 @@ func (s *stripeService) GetPayment(paymentIntentID string) (*PaymentInfo, error)
 	}
 	
 	return &PaymentInfo{
-		ID:       pi.ID,
-		Amount:   float64(pi.Amount) / 100, // Convert from cents
-		Currency: string(pi.Currency),
-		Status:   string(pi.Status),
+		ID:             pi.ID,
+		Amount:         money.FromMinor(pi.Amount).Float(), // Convert from minor units
+		AmountReceived: money.FromMinor(pi.AmountReceived).Float(),
+		Currency:       string(pi.Currency),
+		Status:         string(pi.Status),
 	}, nil
 }
+
+// ParseWebhookEvent verifies the Stripe-Signature header and decodes the event.
+// Dispute events (charge.dispute.*) carry the dispute details.
+func (s *stripeService) ParseWebhookEvent(payload []byte, signature string) (*WebhookEvent, error) {
+	if s.config.Stripe.WebhookSecret == "" {
+		return nil, errors.New("stripe webhook secret is not configured")
+	}
+
+	event, err := webhook.ConstructEventWithOptions(payload, signature, s.config.Stripe.WebhookSecret, webhook.ConstructEventOptions{
+		IgnoreAPIVersionMismatch: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WebhookEvent{
+		ID:   event.ID,
+		Type: string(event.Type),
+	}
+
+	if result.Type == EventPaymentSucceeded && event.Data != nil {
+		var intent stripe.PaymentIntent
+		if err := json.Unmarshal(event.Data.Raw, &intent); err != nil {
+			return nil, fmt.Errorf("failed to decode payment intent: %w", err)
+		}
+		result.PaymentIntentID = intent.ID
+	}
+
+	if strings.HasPrefix(result.Type, "charge.dispute.") && event.Data != nil {
+		var dispute stripe.Dispute
+		if err := json.Unmarshal(event.Data.Raw, &dispute); err != nil {
+			return nil, fmt.Errorf("failed to decode dispute: %w", err)
+		}
+
+		info := &DisputeInfo{
+			ID:       dispute.ID,
+			Amount:   money.FromMinor(dispute.Amount).Float(), // Convert from minor units
+			Currency: string(dispute.Currency),
+			Reason:   string(dispute.Reason),
+			Status:   string(dispute.Status),
+		}
+		if dispute.PaymentIntent != nil {
+			info.PaymentIntentID = dispute.PaymentIntent.ID
+		}
+		if dispute.EvidenceDetails != nil && dispute.EvidenceDetails.DueBy > 0 {
+			dueBy := time.Unix(dispute.EvidenceDetails.DueBy, 0)
+			info.EvidenceDueBy = &dueBy
+		}
+		result.Dispute = info
+	}
+
+	return result, nil
+}
@@ -150,6 +150,7 @@ func createSampleProducts(db *gorm.DB) {
 
 	for _, product := range products {
 		product.GenerateSlug()
+		product.Returnable = true
 		if err := db.Create(product).Error; err != nil {
 			log.Printf("Failed to create product %s: %v", product.Name, err)
 		} else {